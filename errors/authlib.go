@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+
+	"github.com/tniah/authlib/types"
 )
 
 // AuthLibError extends OAuth2Error with authorization-server-specific context:
@@ -21,6 +23,20 @@ type AuthLibError struct {
 	// response instead of a JSON error body.
 	RedirectURI string
 
+	// Fragment, when true, causes HandleError to return the error parameters
+	// in the fragment component of RedirectURI instead of the query string
+	// (RFC 6749 §4.2.2.1).
+	Fragment bool
+
+	// ResponseMode is the response_mode of the authorization request that
+	// failed. HandleError returns the error the same way, and passes errors
+	// with a JWT response mode to the registered ResponseModeHandler (JARM §2.4).
+	ResponseMode types.ResponseMode
+
+	// ClientID identifies the client of the authorization request that failed,
+	// the audience of a JWT-secured error response.
+	ClientID string
+
 	// Cause holds the original lower-level error (e.g. a store error or a
 	// wrapped ErrInvalidClient). Used for internal logging; never sent to clients.
 	Cause error
@@ -47,16 +63,22 @@ func (e *AuthLibError) Data() map[string]interface{} {
 }
 
 // Response returns the HTTP status code, headers, and JSON body for the error
-// response. For invalid_client (401), the WWW-Authenticate header is built at
-// this point using the current Description so it always reflects the latest value
-// set via WithDescription.
+// response. For invalid_client (401), invalid_token and insufficient_scope, the
+// WWW-Authenticate header is built at this point using the current Description
+// so it always reflects the latest value set via WithDescription.
 func (e *AuthLibError) Response() (statusCode int, header http.Header, data map[string]interface{}) {
+	errDesc := strings.ReplaceAll(e.Description, `"`, `\"`)
 	if errors.Is(e.Code, ErrInvalidClient) && e.HttpCode == http.StatusUnauthorized {
-		errDesc := strings.ReplaceAll(e.Description, `"`, `\"`)
 		challenge := fmt.Sprintf(`Basic error="%s", error_description="%s"`, e.Code, errDesc)
 		e.SetHeader("WWW-Authenticate", challenge)
 	}
 
+	// RFC 6750 §3: protected resources answer with a Bearer challenge.
+	if errors.Is(e.Code, ErrInvalidToken) || errors.Is(e.Code, ErrInsufficientScope) {
+		challenge := fmt.Sprintf(`Bearer error="%s", error_description="%s"`, e.Code, errDesc)
+		e.SetHeader("WWW-Authenticate", challenge)
+	}
+
 	return e.HttpCode, e.HttpHeader, e.Data()
 }
 
@@ -86,6 +108,21 @@ func (e *AuthLibError) WithRedirectURI(redirectURI string) *AuthLibError {
 	return e
 }
 
+// WithFragment marks the error redirect as a fragment redirect. Returns e for
+// chaining.
+func (e *AuthLibError) WithFragment() *AuthLibError {
+	e.Fragment = true
+	return e
+}
+
+// WithResponseMode records the response_mode and client_id of the
+// authorization request the error answers. Returns e for chaining.
+func (e *AuthLibError) WithResponseMode(mode types.ResponseMode, clientID string) *AuthLibError {
+	e.ResponseMode = mode
+	e.ClientID = clientID
+	return e
+}
+
 // WithCause attaches the underlying error for internal diagnostics. The cause
 // is never exposed to clients. Returns e for chaining.
 func (e *AuthLibError) WithCause(err error) *AuthLibError {
@@ -189,3 +226,69 @@ func ConsentRequiredError() *AuthLibError {
 func AccountSelectionRequiredError() *AuthLibError {
 	return NewAuthLibError(ErrAccountSelectionRequired)
 }
+
+// AuthorizationPendingError returns a 400 error while the end-user has not yet
+// approved or denied the device authorization request
+// (RFC 8628 §3.5 "authorization_pending").
+func AuthorizationPendingError() *AuthLibError {
+	return NewAuthLibError(ErrAuthorizationPending)
+}
+
+// SlowDownError returns a 400 error when the device polls faster than the
+// permitted interval (RFC 8628 §3.5 "slow_down").
+func SlowDownError() *AuthLibError {
+	return NewAuthLibError(ErrSlowDown)
+}
+
+// ExpiredTokenError returns a 400 error when the device_code has expired
+// (RFC 8628 §3.5 "expired_token").
+func ExpiredTokenError() *AuthLibError {
+	return NewAuthLibError(ErrExpiredToken)
+}
+
+// InvalidTargetError returns a 400 error when the requested resource or
+// audience is not acceptable (RFC 8693 §2.2.2 "invalid_target").
+func InvalidTargetError() *AuthLibError {
+	return NewAuthLibError(ErrInvalidTarget)
+}
+
+// InvalidDPoPProofError returns a 400 error when the DPoP proof sent to the
+// token endpoint is missing or invalid (RFC 9449 §5 "invalid_dpop_proof").
+func InvalidDPoPProofError() *AuthLibError {
+	return NewAuthLibError(ErrInvalidDPoPProof)
+}
+
+// UseDPoPNonceError returns a 400 error asking the client to retry with the
+// nonce sent in the DPoP-Nonce header (RFC 9449 §8 "use_dpop_nonce").
+func UseDPoPNonceError(nonce string) *AuthLibError {
+	e := NewAuthLibError(ErrUseDPoPNonce)
+	e.SetHeader("DPoP-Nonce", nonce)
+	return e
+}
+
+// InvalidRequestURIError returns a 400 error when the request_uri of an
+// authorization request cannot be resolved (RFC 9101 §6.2
+// "invalid_request_uri").
+func InvalidRequestURIError() *AuthLibError {
+	return NewAuthLibError(ErrInvalidRequestURI)
+}
+
+// InvalidRequestObjectError returns a 400 error when the request object of an
+// authorization request is invalid (RFC 9101 §6.1 "invalid_request_object").
+func InvalidRequestObjectError() *AuthLibError {
+	return NewAuthLibError(ErrInvalidRequestObject)
+}
+
+// InvalidTokenError returns a 401 error when the bearer access token presented
+// to a protected resource is missing or invalid (RFC 6750 §3.1
+// "invalid_token"). Response adds a Bearer WWW-Authenticate challenge.
+func InvalidTokenError() *AuthLibError {
+	return NewAuthLibError(ErrInvalidToken)
+}
+
+// InsufficientScopeError returns a 403 error when the bearer access token
+// lacks the scope required by a protected resource (RFC 6750 §3.1
+// "insufficient_scope"). Response adds a Bearer WWW-Authenticate challenge.
+func InsufficientScopeError() *AuthLibError {
+	return NewAuthLibError(ErrInsufficientScope)
+}
@@ -57,6 +57,37 @@ var (
 	// ErrAccountSelectionRequired is returned when the end-user must select a
 	// session but prompt=none was requested (OpenID Connect Core).
 	ErrAccountSelectionRequired = errors.New("account_selection_required")
+	// ErrAuthorizationPending is returned while the end-user has not yet
+	// completed the device authorization request (RFC 8628 §3.5).
+	ErrAuthorizationPending = errors.New("authorization_pending")
+	// ErrSlowDown is returned when the device polls the token endpoint faster
+	// than the permitted interval (RFC 8628 §3.5).
+	ErrSlowDown = errors.New("slow_down")
+	// ErrExpiredToken is returned when the device_code has expired
+	// (RFC 8628 §3.5).
+	ErrExpiredToken = errors.New("expired_token")
+	// ErrInvalidTarget is returned when the requested resource or audience is
+	// invalid, unknown, or not permitted (RFC 8693 §2.2.2, RFC 8707 §2).
+	ErrInvalidTarget = errors.New("invalid_target")
+	// ErrInvalidDPoPProof is returned when the DPoP proof is missing or
+	// invalid (RFC 9449 §5).
+	ErrInvalidDPoPProof = errors.New("invalid_dpop_proof")
+	// ErrUseDPoPNonce is returned when the DPoP proof must carry a nonce
+	// provided by the server (RFC 9449 §8).
+	ErrUseDPoPNonce = errors.New("use_dpop_nonce")
+	// ErrInvalidRequestURI is returned when the request_uri of an
+	// authorization request is unknown, expired, or already used
+	// (RFC 9101 §6.2).
+	ErrInvalidRequestURI = errors.New("invalid_request_uri")
+	// ErrInvalidRequestObject is returned when the request object of an
+	// authorization request is invalid (RFC 9101 §6.1).
+	ErrInvalidRequestObject = errors.New("invalid_request_object")
+	// ErrInvalidToken is returned by a protected resource when the access
+	// token is missing, expired, revoked, or malformed (RFC 6750 §3.1).
+	ErrInvalidToken = errors.New("invalid_token")
+	// ErrInsufficientScope is returned by a protected resource when the access
+	// token does not carry the scope the request requires (RFC 6750 §3.1).
+	ErrInsufficientScope = errors.New("insufficient_scope")
 )
 
 // Descriptions maps each OAuth 2.0 error code to its default human-readable
@@ -76,6 +107,16 @@ var Descriptions = map[error]string{
 	ErrLoginRequired:            "The authorization server requires end-user authentication. This error may be returned when the prompt parameter value in the authentication request is none, but the authentication request cannot be completed without displaying a user interface for end-user authentication",
 	ErrConsentRequired:          "The authorization server requires end-user consent. This error may be returned when the prompt parameter value in the authentication Request is none, but the authentication request cannot be completed without displaying a user interface for end-User consent",
 	ErrAccountSelectionRequired: "The end-user is required to select a session at the Authorization Server.",
+	ErrAuthorizationPending:     "The authorization request is still pending as the end user hasn't yet completed the user-interaction steps",
+	ErrSlowDown:                 "The authorization request is still pending and polling should continue, but the interval must be increased by 5 seconds",
+	ErrExpiredToken:             "The \"device_code\" has expired, and the device authorization session has concluded",
+	ErrInvalidTarget:            "The requested resource or audience is invalid, unknown, or not permitted",
+	ErrInvalidDPoPProof:         "The DPoP proof is missing or invalid",
+	ErrUseDPoPNonce:             "The authorization server requires a nonce in the DPoP proof",
+	ErrInvalidRequestURI:        "The \"request_uri\" in the authorization request returns an error or contains invalid data",
+	ErrInvalidRequestObject:     "The request parameter contains an invalid request object",
+	ErrInvalidToken:             "The access token provided is expired, revoked, malformed, or invalid",
+	ErrInsufficientScope:        "The request requires higher privileges than provided by the access token",
 }
 
 // HttpCodes maps each OAuth 2.0 error code to its HTTP status code.
@@ -95,4 +136,14 @@ var HttpCodes = map[error]int{
 	ErrLoginRequired:            http.StatusUnauthorized,
 	ErrConsentRequired:          http.StatusForbidden,
 	ErrAccountSelectionRequired: http.StatusForbidden,
+	ErrAuthorizationPending:     http.StatusBadRequest,
+	ErrSlowDown:                 http.StatusBadRequest,
+	ErrExpiredToken:             http.StatusBadRequest,
+	ErrInvalidTarget:            http.StatusBadRequest,
+	ErrInvalidDPoPProof:         http.StatusBadRequest,
+	ErrUseDPoPNonce:             http.StatusBadRequest,
+	ErrInvalidRequestURI:        http.StatusBadRequest,
+	ErrInvalidRequestObject:     http.StatusBadRequest,
+	ErrInvalidToken:             http.StatusUnauthorized,
+	ErrInsufficientScope:        http.StatusForbidden,
 }
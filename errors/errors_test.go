@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/tniah/authlib/types"
 )
 
 func TestNewOAuth2Error(t *testing.T) {
@@ -72,12 +73,17 @@ func TestAuthLibError_With(t *testing.T) {
 		WithState("xyz").
 		WithRedirectURI("https://example.com/cb").
 		WithErrorURI("https://example.com/docs").
+		WithFragment().
+		WithResponseMode(types.ResponseModeJWT, "client-1").
 		WithCause(ErrInvalidRequest)
 
 	assert.Equal(t, "custom desc", e.Description)
 	assert.Equal(t, "xyz", e.State)
 	assert.Equal(t, "https://example.com/cb", e.RedirectURI)
 	assert.Equal(t, "https://example.com/docs", e.URI)
+	assert.True(t, e.Fragment)
+	assert.Equal(t, types.ResponseModeJWT, e.ResponseMode)
+	assert.Equal(t, "client-1", e.ClientID)
 	assert.Equal(t, ErrInvalidRequest, e.Cause)
 }
 
@@ -99,6 +105,16 @@ func TestAuthLibError_Response_InvalidClient(t *testing.T) {
 	assert.NotEmpty(t, header.Get("WWW-Authenticate"))
 }
 
+func TestAuthLibError_Response_Bearer(t *testing.T) {
+	status, header, _ := InvalidTokenError().WithDescription(`token "abc" expired`).Response()
+	assert.Equal(t, http.StatusUnauthorized, status)
+	assert.Equal(t, `Bearer error="invalid_token", error_description="token \"abc\" expired"`, header.Get("WWW-Authenticate"))
+
+	status, header, _ = InsufficientScopeError().Response()
+	assert.Equal(t, http.StatusForbidden, status)
+	assert.Contains(t, header.Get("WWW-Authenticate"), `Bearer error="insufficient_scope"`)
+}
+
 func TestToAuthLibError(t *testing.T) {
 	// already an *AuthLibError
 	original := InvalidRequestError().WithDescription("test")
@@ -130,6 +146,15 @@ func TestErrorConstructors(t *testing.T) {
 		{LoginRequiredError, ErrLoginRequired, http.StatusUnauthorized},
 		{ConsentRequiredError, ErrConsentRequired, http.StatusForbidden},
 		{AccountSelectionRequiredError, ErrAccountSelectionRequired, http.StatusForbidden},
+		{AuthorizationPendingError, ErrAuthorizationPending, http.StatusBadRequest},
+		{SlowDownError, ErrSlowDown, http.StatusBadRequest},
+		{ExpiredTokenError, ErrExpiredToken, http.StatusBadRequest},
+		{InvalidTargetError, ErrInvalidTarget, http.StatusBadRequest},
+		{InvalidDPoPProofError, ErrInvalidDPoPProof, http.StatusBadRequest},
+		{InvalidRequestURIError, ErrInvalidRequestURI, http.StatusBadRequest},
+		{InvalidRequestObjectError, ErrInvalidRequestObject, http.StatusBadRequest},
+		{InvalidTokenError, ErrInvalidToken, http.StatusUnauthorized},
+		{InsufficientScopeError, ErrInsufficientScope, http.StatusForbidden},
 	}
 
 	for _, c := range cases {
@@ -138,3 +163,10 @@ func TestErrorConstructors(t *testing.T) {
 		assert.Equal(t, c.httpCode, e.HttpCode, "httpCode mismatch for %v", c.code)
 	}
 }
+
+func TestUseDPoPNonceError(t *testing.T) {
+	e := UseDPoPNonceError("eyJ7S_zG.eyJH0-Z.HX4w-7v")
+	assert.Equal(t, ErrUseDPoPNonce, e.Code)
+	assert.Equal(t, http.StatusBadRequest, e.HttpCode)
+	assert.Equal(t, "eyJ7S_zG.eyJH0-Z.HX4w-7v", e.HttpHeader.Get("DPoP-Nonce"))
+}
@@ -70,6 +70,8 @@ func (m *TokenManager) Save(_ context.Context, token authlibmodels.Token) error
 		entry.SetRefreshTokenExpiresIn(token.GetRefreshTokenExpiresIn())
 		entry.SetUserID(token.GetUserID())
 		entry.SetJwtID(token.GetJwtID())
+		entry.SetFamilyID(token.GetFamilyID())
+		entry.SetRotatedAt(token.GetRotatedAt())
 		if ext, ok := token.(authlibmodels.ExtendableToken); ok {
 			entry.SetExtraData(ext.GetExtraData())
 		}
@@ -113,6 +115,82 @@ func (m *TokenManager) QueryByToken(_ context.Context, token string, hint authli
 	return nil, nil
 }
 
+// QueryByRefreshToken retrieves a token by its refresh token value, including
+// tokens whose refresh token has already been rotated.
+// Returns (nil, nil) when the token does not exist.
+func (m *TokenManager) QueryByRefreshToken(_ context.Context, refreshToken string) (authlibmodels.Token, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	if t, ok := m.byRefreshToken[refreshToken]; ok {
+		return t, nil
+	}
+
+	return nil, nil
+}
+
+// Update persists changes to an existing token, such as its family ID and
+// rotation time after a refresh.
+func (m *TokenManager) Update(_ context.Context, token authlibmodels.Token) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	entry, ok := m.byRefreshToken[token.GetRefreshToken()]
+	if !ok {
+		return nil
+	}
+
+	entry.SetFamilyID(token.GetFamilyID())
+	entry.SetRotatedAt(token.GetRotatedAt())
+	entry.UpdatedAt = time.Now().UTC().Round(time.Second)
+	return nil
+}
+
+// RevokeAccessToken deletes the access token of token. The refresh token
+// stored with it stays usable.
+func (m *TokenManager) RevokeAccessToken(_ context.Context, token authlibmodels.Token) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	delete(m.byAccessToken, token.GetAccessToken())
+	return nil
+}
+
+// RevokeRefreshToken deletes the refresh token of token together with the
+// access token stored with it.
+func (m *TokenManager) RevokeRefreshToken(_ context.Context, token authlibmodels.Token) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	delete(m.byRefreshToken, token.GetRefreshToken())
+	delete(m.byAccessToken, token.GetAccessToken())
+	return nil
+}
+
+// RevokeTokenFamily deletes every token sharing the given family ID.
+func (m *TokenManager) RevokeTokenFamily(_ context.Context, familyID string) error {
+	if familyID == "" {
+		return nil
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	for k, t := range m.byAccessToken {
+		if t.FamilyID == familyID {
+			delete(m.byAccessToken, k)
+		}
+	}
+
+	for k, t := range m.byRefreshToken {
+		if t.FamilyID == familyID {
+			delete(m.byRefreshToken, k)
+		}
+	}
+
+	return nil
+}
+
 // Inspect returns the RFC 7662 §2.2 introspection claims for an active token.
 // The caller (introspection endpoint) merges these with {"active": true}; do not include it here.
 func (m *TokenManager) Inspect(_ authlibmodels.Client, token authlibmodels.Token) map[string]interface{} {
@@ -78,6 +78,20 @@ func (m *UserManager) QueryUserByCode(_ context.Context, code authlibmodels.Auth
 	return u, nil
 }
 
+// QueryUserByToken retrieves the user the given refresh token was issued for.
+// Returns (nil, nil) when no user is found for the token's user ID.
+func (m *UserManager) QueryUserByToken(_ context.Context, token authlibmodels.Token, _ *authlibrequests.TokenRequest) (authlibmodels.User, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	u, ok := m.byID[token.GetUserID()]
+	if !ok {
+		return nil, nil
+	}
+
+	return u, nil
+}
+
 // Authenticate verifies the user's credentials and returns the authenticated user.
 // Returns (nil, nil) when the credentials are invalid.
 func (m *UserManager) Authenticate(username, password string, _ authlibmodels.Client, _ *http.Request) (authlibmodels.User, error) {
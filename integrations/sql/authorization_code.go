@@ -19,6 +19,7 @@ type AuthorizationCode struct {
 	Scopes              []string               `json:"scopes"`
 	Nonce               string                 `json:"nonce"`
 	State               string                 `json:"state"`
+	ClaimsRequest       *types.ClaimsRequest   `json:"claims_request"`
 	AuthTime            time.Time              `json:"auth_time"`
 	ExpiresIn           time.Duration          `json:"expires_in"`
 	CodeChallenge       string                 `json:"code_challenge"`
@@ -92,6 +93,14 @@ func (c *AuthorizationCode) SetState(state string) {
 	c.State = state
 }
 
+func (c *AuthorizationCode) GetClaimsRequest() *types.ClaimsRequest {
+	return c.ClaimsRequest
+}
+
+func (c *AuthorizationCode) SetClaimsRequest(claims *types.ClaimsRequest) {
+	c.ClaimsRequest = claims
+}
+
 func (c *AuthorizationCode) GetAuthTime() time.Time {
 	return c.AuthTime
 }
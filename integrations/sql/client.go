@@ -5,6 +5,7 @@ package sql
 
 import (
 	"crypto/subtle"
+	"encoding/json"
 	"time"
 
 	"github.com/tniah/authlib/models"
@@ -14,25 +15,44 @@ import (
 // Compile-time check that *Client implements models.Client.
 var _ models.Client = (*Client)(nil)
 
+// Compile-time check that *Client implements models.ResponseModeClient.
+var _ models.ResponseModeClient = (*Client)(nil)
+
 type Client struct {
-	ClientName              string    `json:"client_name"`
-	ClientID                string    `json:"client_id"`
-	ClientSecret            string    `json:"client_secret"`
-	RedirectURIs            []string  `json:"redirect_uris"`
-	ResponseTypes           []string  `json:"response_types"`
-	GrantTypes              []string  `json:"grant_types"`
-	Scopes                  []string  `json:"scopes"`
-	TokenEndpointAuthMethod string    `json:"token_endpoint_auth_method"`
-	ClientURI               string    `json:"client_uri"`
-	LogoURI                 string    `json:"logo_uri"`
-	Contacts                []string  `json:"contacts"`
-	TosURI                  string    `json:"tos_uri"`
-	PolicyURI               string    `json:"policy_uri"`
-	JWKsURI                 string    `json:"jwks_uri"`
-	SoftwareID              string    `json:"software_id"`
-	SoftwareVersion         string    `json:"software_version"`
-	CreatedAt               time.Time `json:"created_at"`
-	UpdatedAt               time.Time `json:"updated_at"`
+	ClientName                            string          `json:"client_name"`
+	ClientID                              string          `json:"client_id"`
+	ClientSecret                          string          `json:"client_secret"`
+	RedirectURIs                          []string        `json:"redirect_uris"`
+	ResponseTypes                         []string        `json:"response_types"`
+	ResponseModes                         []string        `json:"response_modes"`
+	GrantTypes                            []string        `json:"grant_types"`
+	Scopes                                []string        `json:"scopes"`
+	TokenEndpointAuthMethod               string          `json:"token_endpoint_auth_method"`
+	ClientURI                             string          `json:"client_uri"`
+	LogoURI                               string          `json:"logo_uri"`
+	Contacts                              []string        `json:"contacts"`
+	TosURI                                string          `json:"tos_uri"`
+	PolicyURI                             string          `json:"policy_uri"`
+	JWKsURI                               string          `json:"jwks_uri"`
+	JWKs                                  json.RawMessage `json:"jwks"`
+	TLSClientAuthSubjectDN                string          `json:"tls_client_auth_subject_dn"`
+	TLSClientAuthSANDNS                   string          `json:"tls_client_auth_san_dns"`
+	TLSClientAuthSANURI                   string          `json:"tls_client_auth_san_uri"`
+	TLSClientAuthSANIP                    string          `json:"tls_client_auth_san_ip"`
+	TLSClientAuthSANEmail                 string          `json:"tls_client_auth_san_email"`
+	TLSClientCertificateBoundAccessTokens bool            `json:"tls_client_certificate_bound_access_tokens"`
+	DPoPBoundAccessTokens                 bool            `json:"dpop_bound_access_tokens"`
+	RequirePushedAuthorizationRequests    bool            `json:"require_pushed_authorization_requests"`
+	RequestObjectSigningAlg               string          `json:"request_object_signing_alg"`
+	RequestURIs                           []string        `json:"request_uris"`
+	RequireSignedRequestObject            bool            `json:"require_signed_request_object"`
+	UserInfoSignedResponseAlg             string          `json:"userinfo_signed_response_alg"`
+	UserInfoEncryptedResponseAlg          string          `json:"userinfo_encrypted_response_alg"`
+	UserInfoEncryptedResponseEnc          string          `json:"userinfo_encrypted_response_enc"`
+	SoftwareID                            string          `json:"software_id"`
+	SoftwareVersion                       string          `json:"software_version"`
+	CreatedAt                             time.Time       `json:"created_at"`
+	UpdatedAt                             time.Time       `json:"updated_at"`
 }
 
 func (c *Client) GetClientName() string {
@@ -63,6 +83,75 @@ func (c *Client) GetRedirectURIs() []string {
 	return c.RedirectURIs
 }
 
+func (c *Client) GetJWKsURI() string {
+	return c.JWKsURI
+}
+
+// GetJWKs returns the inline JWK Set, or nil when none is registered.
+func (c *Client) GetJWKs() []byte {
+	if string(c.JWKs) == "null" {
+		return nil
+	}
+
+	return c.JWKs
+}
+
+func (c *Client) GetTLSClientAuthSubjectDN() string {
+	return c.TLSClientAuthSubjectDN
+}
+
+func (c *Client) GetTLSClientAuthSANDNS() string {
+	return c.TLSClientAuthSANDNS
+}
+
+func (c *Client) GetTLSClientAuthSANURI() string {
+	return c.TLSClientAuthSANURI
+}
+
+func (c *Client) GetTLSClientAuthSANIP() string {
+	return c.TLSClientAuthSANIP
+}
+
+func (c *Client) GetTLSClientAuthSANEmail() string {
+	return c.TLSClientAuthSANEmail
+}
+
+func (c *Client) GetTLSClientCertificateBoundAccessTokens() bool {
+	return c.TLSClientCertificateBoundAccessTokens
+}
+
+func (c *Client) GetDPoPBoundAccessTokens() bool {
+	return c.DPoPBoundAccessTokens
+}
+
+func (c *Client) GetRequirePushedAuthorizationRequests() bool {
+	return c.RequirePushedAuthorizationRequests
+}
+
+func (c *Client) GetRequestObjectSigningAlg() string {
+	return c.RequestObjectSigningAlg
+}
+
+func (c *Client) GetRequestURIs() []string {
+	return c.RequestURIs
+}
+
+func (c *Client) GetRequireSignedRequestObject() bool {
+	return c.RequireSignedRequestObject
+}
+
+func (c *Client) GetUserInfoSignedResponseAlg() string {
+	return c.UserInfoSignedResponseAlg
+}
+
+func (c *Client) GetUserInfoEncryptedResponseAlg() string {
+	return c.UserInfoEncryptedResponseAlg
+}
+
+func (c *Client) GetUserInfoEncryptedResponseEnc() string {
+	return c.UserInfoEncryptedResponseEnc
+}
+
 func (c *Client) GetResponseTypes() types.ResponseTypes {
 	return types.NewResponseTypes(c.ResponseTypes)
 }
@@ -141,7 +230,27 @@ func (c *Client) CheckGrantType(gt types.GrantType) bool {
 
 func (c *Client) CheckResponseType(rt types.ResponseType) bool {
 	for i := range c.ResponseTypes {
-		if c.ResponseTypes[i] == rt.String() {
+		if rt.Equal(types.NewResponseType(c.ResponseTypes[i])) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *Client) GetResponseModes() []string {
+	return c.ResponseModes
+}
+
+// CheckResponseMode reports whether mode is among the client's registered
+// response modes. A client without registered response modes may use any.
+func (c *Client) CheckResponseMode(mode types.ResponseMode) bool {
+	if len(c.ResponseModes) == 0 {
+		return true
+	}
+
+	for i := range c.ResponseModes {
+		if c.ResponseModes[i] == mode.String() {
 			return true
 		}
 	}
@@ -0,0 +1,107 @@
+package sql
+
+import (
+	"time"
+
+	"github.com/tniah/authlib/models"
+	"github.com/tniah/authlib/types"
+)
+
+// Compile-time check that *DeviceCode implements models.DeviceCode.
+var _ models.DeviceCode = (*DeviceCode)(nil)
+
+// DeviceCode is a SQL-backed implementation of models.DeviceCode.
+type DeviceCode struct {
+	DeviceCode   string        `json:"device_code"`
+	UserCode     string        `json:"user_code"`
+	ClientID     string        `json:"client_id"`
+	UserID       string        `json:"user_id"`
+	Scopes       []string      `json:"scopes"`
+	Status       string        `json:"status"`
+	IssuedAt     time.Time     `json:"issued_at"`
+	ExpiresIn    time.Duration `json:"expires_in"`
+	Interval     time.Duration `json:"interval"`
+	LastPolledAt time.Time     `json:"last_polled_at"`
+	CreatedAt    time.Time     `json:"created_at"`
+	UpdatedAt    time.Time     `json:"updated_at"`
+}
+
+func (c *DeviceCode) GetDeviceCode() string {
+	return c.DeviceCode
+}
+
+func (c *DeviceCode) SetDeviceCode(code string) {
+	c.DeviceCode = code
+}
+
+func (c *DeviceCode) GetUserCode() string {
+	return c.UserCode
+}
+
+func (c *DeviceCode) SetUserCode(code string) {
+	c.UserCode = code
+}
+
+func (c *DeviceCode) GetClientID() string {
+	return c.ClientID
+}
+
+func (c *DeviceCode) SetClientID(clientID string) {
+	c.ClientID = clientID
+}
+
+func (c *DeviceCode) GetUserID() string {
+	return c.UserID
+}
+
+func (c *DeviceCode) SetUserID(userID string) {
+	c.UserID = userID
+}
+
+func (c *DeviceCode) GetScopes() types.Scopes {
+	return types.NewScopes(c.Scopes)
+}
+
+func (c *DeviceCode) SetScopes(s types.Scopes) {
+	c.Scopes = s.String()
+}
+
+func (c *DeviceCode) GetStatus() types.DeviceCodeStatus {
+	return types.NewDeviceCodeStatus(c.Status)
+}
+
+func (c *DeviceCode) SetStatus(status types.DeviceCodeStatus) {
+	c.Status = status.String()
+}
+
+func (c *DeviceCode) GetIssuedAt() time.Time {
+	return c.IssuedAt
+}
+
+func (c *DeviceCode) SetIssuedAt(issuedAt time.Time) {
+	c.IssuedAt = issuedAt
+}
+
+func (c *DeviceCode) GetExpiresIn() time.Duration {
+	return c.ExpiresIn
+}
+
+func (c *DeviceCode) SetExpiresIn(expiresIn time.Duration) {
+	c.ExpiresIn = expiresIn
+}
+
+func (c *DeviceCode) GetInterval() time.Duration {
+	return c.Interval
+}
+
+func (c *DeviceCode) SetInterval(interval time.Duration) {
+	c.Interval = interval
+}
+
+func (c *DeviceCode) GetLastPolledAt() time.Time {
+	return c.LastPolledAt
+}
+
+func (c *DeviceCode) SetLastPolledAt(lastPolledAt time.Time) {
+	c.LastPolledAt = lastPolledAt
+}
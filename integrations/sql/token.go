@@ -21,6 +21,8 @@ type Token struct {
 	RefreshTokenExpiresIn time.Duration          `json:"refresh_token_expires_in"`
 	UserID                string                 `json:"user_id"`
 	JwtID                 string                 `json:"jti"`
+	FamilyID              string                 `json:"family_id"`
+	RotatedAt             time.Time              `json:"rotated_at"`
 	Data                  map[string]interface{} `json:"data"`
 	CreatedAt             time.Time              `json:"created_at"`
 	UpdatedAt             time.Time              `json:"updated_at"`
@@ -106,6 +108,22 @@ func (t *Token) SetJwtID(id string) {
 	t.JwtID = id
 }
 
+func (t *Token) GetFamilyID() string {
+	return t.FamilyID
+}
+
+func (t *Token) SetFamilyID(id string) {
+	t.FamilyID = id
+}
+
+func (t *Token) GetRotatedAt() time.Time {
+	return t.RotatedAt
+}
+
+func (t *Token) SetRotatedAt(rotatedAt time.Time) {
+	t.RotatedAt = rotatedAt
+}
+
 func (t *Token) GetExtraData() map[string]interface{} {
 	return t.Data
 }
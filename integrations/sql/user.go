@@ -1,14 +1,43 @@
 package sql
 
-import "github.com/tniah/authlib/models"
+import (
+	"time"
 
-// Compile-time check that *User implements models.User.
-var _ models.User = (*User)(nil)
+	"github.com/tniah/authlib/models"
+)
+
+// Compile-time checks that *User implements models.User,
+// models.ClaimsProvider and models.AuthenticationContext.
+var (
+	_ models.User                  = (*User)(nil)
+	_ models.ClaimsProvider        = (*User)(nil)
+	_ models.AuthenticationContext = (*User)(nil)
+)
 
 type User struct {
-	UserID string `json:"user_id"`
+	UserID   string                 `json:"user_id"`
+	Claims   map[string]interface{} `json:"claims"`
+	AuthTime time.Time              `json:"auth_time"`
+	ACR      string                 `json:"acr"`
+	AMR      []string               `json:"amr"`
 }
 
 func (u *User) GetUserID() string {
 	return u.UserID
 }
+
+func (u *User) GetClaims() map[string]interface{} {
+	return u.Claims
+}
+
+func (u *User) GetAuthTime() time.Time {
+	return u.AuthTime
+}
+
+func (u *User) GetACR() string {
+	return u.ACR
+}
+
+func (u *User) GetAMR() []string {
+	return u.AMR
+}
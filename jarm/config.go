@@ -0,0 +1,82 @@
+package jarm
+
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	autherrors "github.com/tniah/authlib/errors"
+)
+
+// DefaultExpiresIn is the lifetime of a JWT-secured authorization response.
+// The response is consumed right away by the client, so it is kept short
+// (JARM §2.1).
+const DefaultExpiresIn = time.Minute * 10
+
+// Config holds the signing settings of JWT-secured authorization responses.
+// Use NewConfig to get a config with sensible defaults, then chain Set* calls
+// before passing to Must or New.
+type Config struct {
+	issuer           string
+	expiresIn        time.Duration
+	signingKey       []byte
+	signingKeyMethod jwt.SigningMethod
+	signingKeyID     string
+}
+
+// NewConfig returns a Config with a DefaultExpiresIn lifetime.
+func NewConfig() *Config {
+	return &Config{expiresIn: DefaultExpiresIn}
+}
+
+// SetIssuer sets the issuer claim (iss), the identifier of the authorization
+// server.
+func (cfg *Config) SetIssuer(iss string) *Config {
+	cfg.issuer = iss
+	return cfg
+}
+
+// SetExpiresIn sets the lifetime of a response. Default: 10 minutes.
+func (cfg *Config) SetExpiresIn(exp time.Duration) *Config {
+	cfg.expiresIn = exp
+	return cfg
+}
+
+// SetSigningKey sets the signing key, method, and optional key ID used to sign
+// responses.
+func (cfg *Config) SetSigningKey(key []byte, method jwt.SigningMethod, keyID ...string) *Config {
+	cfg.signingKey = key
+	cfg.signingKeyMethod = method
+
+	if len(keyID) > 0 {
+		cfg.signingKeyID = keyID[0]
+	}
+
+	return cfg
+}
+
+// ValidateConfig checks that all required settings are present and returns
+// the first sentinel error encountered. Call this via Must() rather than
+// directly.
+func (cfg *Config) ValidateConfig() error {
+	if cfg.issuer == "" {
+		return autherrors.ErrMissingIssuer
+	}
+
+	if cfg.expiresIn == 0 {
+		return autherrors.ErrMissingExpiresIn
+	}
+
+	if cfg.signingKey == nil {
+		return autherrors.ErrMissingSigningKey
+	}
+
+	if cfg.signingKeyMethod == nil {
+		return autherrors.ErrMissingSigningKeyMethod
+	}
+
+	if cfg.signingKeyMethod == jwt.SigningMethodNone {
+		return autherrors.ErrInsecureSigningMethod
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package jarm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	autherrors "github.com/tniah/authlib/errors"
+)
+
+func TestConfig(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		cfg := NewConfig()
+		assert.Equal(t, DefaultExpiresIn, cfg.expiresIn)
+
+		cfg.SetIssuer("https://as.example.com").
+			SetExpiresIn(time.Minute).
+			SetSigningKey([]byte("secret"), jwt.SigningMethodHS256, "kid-1")
+
+		assert.Equal(t, "https://as.example.com", cfg.issuer)
+		assert.Equal(t, time.Minute, cfg.expiresIn)
+		assert.Equal(t, []byte("secret"), cfg.signingKey)
+		assert.Equal(t, jwt.SigningMethodHS256, cfg.signingKeyMethod)
+		assert.Equal(t, "kid-1", cfg.signingKeyID)
+		assert.NoError(t, cfg.ValidateConfig())
+	})
+
+	t.Run("error", func(t *testing.T) {
+		cfg := NewConfig()
+		assert.ErrorIs(t, cfg.ValidateConfig(), autherrors.ErrMissingIssuer)
+
+		cfg.SetIssuer("https://as.example.com").SetExpiresIn(0)
+		assert.ErrorIs(t, cfg.ValidateConfig(), autherrors.ErrMissingExpiresIn)
+
+		cfg.SetExpiresIn(time.Minute)
+		assert.ErrorIs(t, cfg.ValidateConfig(), autherrors.ErrMissingSigningKey)
+
+		cfg.SetSigningKey([]byte("secret"), nil)
+		assert.ErrorIs(t, cfg.ValidateConfig(), autherrors.ErrMissingSigningKeyMethod)
+
+		cfg.SetSigningKey([]byte("secret"), jwt.SigningMethodNone)
+		assert.ErrorIs(t, cfg.ValidateConfig(), autherrors.ErrInsecureSigningMethod)
+	})
+}
@@ -0,0 +1,130 @@
+// Package jarm implements the JWT Secured Authorization Response Mode for
+// OAuth 2.0 (JARM): authorization responses and error redirects are wrapped in
+// a signed JWT passed as the single "response" parameter, so the client can
+// check where the response comes from and that it was not modified.
+package jarm
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	autherrors "github.com/tniah/authlib/errors"
+	"github.com/tniah/authlib/requests"
+	"github.com/tniah/authlib/types"
+	"github.com/tniah/authlib/utils"
+)
+
+// ParamResponse is the authorization response parameter carrying the JWT
+// (JARM §2.3).
+const ParamResponse = "response"
+
+// Flow writes JWT-secured authorization responses for the response_mode
+// values jwt, query.jwt, fragment.jwt and form_post.jwt. Register it with the
+// grant flows as an extension for successful responses, and with
+// Server.RegisterResponseModeHandler for error redirects.
+type Flow struct {
+	*Config
+}
+
+// New creates a Flow from cfg without validating it. Prefer Must for
+// production use.
+func New(cfg *Config) *Flow {
+	return &Flow{cfg}
+}
+
+// Must returns a validated Flow or an error if any required Config setting is
+// missing.
+func Must(cfg *Config) (*Flow, error) {
+	if err := cfg.ValidateConfig(); err != nil {
+		return nil, err
+	}
+
+	return New(cfg), nil
+}
+
+// ProvideMetadata adds the JWT-secured response modes and the signing
+// algorithm of responses to the authorization server metadata (JARM §3).
+func (f *Flow) ProvideMetadata(md types.Metadata) {
+	md.Add(types.MetadataResponseModesSupported,
+		types.ResponseModeJWT.String(), types.ResponseModeQueryJWT.String(),
+		types.ResponseModeFragmentJWT.String(), types.ResponseModeFormPostJWT.String())
+	md.Add(types.MetadataAuthorizationSigningAlgValuesSupported, f.signingKeyMethod.Alg())
+}
+
+// CheckResponseMode returns true for the JWT-secured response modes.
+func (f *Flow) CheckResponseMode(mode types.ResponseMode) bool {
+	return mode.IsJWT()
+}
+
+// WriteAuthorizationResponse signs params, addressed to the client of r, and
+// sends the JWT to r.RedirectURI the way r.ResponseMode asks for. The jwt
+// response mode uses query.jwt for the code response type and fragment.jwt
+// for response types issuing tokens (JARM §2.3.4).
+func (f *Flow) WriteAuthorizationResponse(r *requests.AuthorizationRequest, rw http.ResponseWriter, params map[string]interface{}) error {
+	mode := r.ResponseMode
+	if mode == types.ResponseModeJWT {
+		mode = types.ResponseModeQueryJWT
+		if r.ResponseType.Has(types.ResponseTypeToken) || r.ResponseType.Has(types.ResponseTypeIDToken) {
+			mode = types.ResponseModeFragmentJWT
+		}
+	}
+
+	return f.write(rw, mode, r.RedirectURI, r.ClientID, params)
+}
+
+// WriteErrorResponse signs the error parameters of err, addressed to
+// err.ClientID, and sends the JWT to err.RedirectURI (JARM §2.4). The jwt
+// response mode uses fragment.jwt for errors returned in the fragment, and
+// query.jwt otherwise.
+func (f *Flow) WriteErrorResponse(rw http.ResponseWriter, err *autherrors.AuthLibError) error {
+	mode := err.ResponseMode
+	if mode == types.ResponseModeJWT {
+		mode = types.ResponseModeQueryJWT
+		if err.Fragment {
+			mode = types.ResponseModeFragmentJWT
+		}
+	}
+
+	return f.write(rw, mode, err.RedirectURI, err.ClientID, err.Data())
+}
+
+// write signs params and sends them to redirectURI in the encoding of mode.
+func (f *Flow) write(rw http.ResponseWriter, mode types.ResponseMode, redirectURI, clientID string, params map[string]interface{}) error {
+	response, err := f.sign(clientID, params)
+	if err != nil {
+		return err
+	}
+
+	data := map[string]interface{}{ParamResponse: response}
+	switch mode {
+	case types.ResponseModeFragmentJWT:
+		return utils.RedirectWithFragment(rw, redirectURI, data)
+	case types.ResponseModeFormPostJWT:
+		return utils.FormPost(rw, redirectURI, data)
+	default:
+		return utils.Redirect(rw, redirectURI, data)
+	}
+}
+
+// sign returns a JWT carrying params together with the iss, aud and exp
+// claims (JARM §2.1). params cannot override those claims.
+func (f *Flow) sign(clientID string, params map[string]interface{}) (string, error) {
+	token, err := utils.NewJWTToken(f.signingKey, f.signingKeyMethod, f.signingKeyID)
+	if err != nil {
+		return "", err
+	}
+
+	claims := utils.JWTClaim{
+		"iss": f.issuer,
+		"aud": clientID,
+		"exp": jwt.NewNumericDate(time.Now().UTC().Add(f.expiresIn).Round(time.Second)),
+	}
+	for k, v := range params {
+		if _, ok := claims[k]; !ok {
+			claims[k] = v
+		}
+	}
+
+	return token.Generate(claims, nil)
+}
@@ -0,0 +1,179 @@
+package jarm
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	autherrors "github.com/tniah/authlib/errors"
+	"github.com/tniah/authlib/requests"
+	"github.com/tniah/authlib/types"
+)
+
+const (
+	testIssuer      = "https://as.example.com"
+	testClientID    = "client-1"
+	testRedirectURI = "https://client.example.com/cb"
+)
+
+func newSigningKey(t *testing.T) (*ecdsa.PrivateKey, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	der, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return key, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})
+}
+
+func newAuthorizationRequest(mode types.ResponseMode, responseType types.ResponseType) *requests.AuthorizationRequest {
+	return &requests.AuthorizationRequest{
+		ClientID:     testClientID,
+		RedirectURI:  testRedirectURI,
+		ResponseType: responseType,
+		ResponseMode: mode,
+		Request:      httptest.NewRequest(http.MethodGet, "/authorize", nil),
+	}
+}
+
+func parseResponse(t *testing.T, key *ecdsa.PrivateKey, response string) jwt.MapClaims {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(response, claims, func(token *jwt.Token) (interface{}, error) {
+		assert.Equal(t, "kid-1", token.Header["kid"])
+		return &key.PublicKey, nil
+	}, jwt.WithValidMethods([]string{"ES256"}), jwt.WithIssuer(testIssuer), jwt.WithAudience(testClientID), jwt.WithExpirationRequired())
+	require.NoError(t, err)
+	return claims
+}
+
+func TestMust(t *testing.T) {
+	_, pemKey := newSigningKey(t)
+
+	f, err := Must(NewConfig().SetIssuer(testIssuer).SetSigningKey(pemKey, jwt.SigningMethodES256))
+	assert.NoError(t, err)
+	assert.NotNil(t, f)
+
+	f, err = Must(NewConfig())
+	assert.ErrorIs(t, err, autherrors.ErrMissingIssuer)
+	assert.Nil(t, f)
+}
+
+func TestFlow_CheckResponseMode(t *testing.T) {
+	f := New(NewConfig())
+	for _, mode := range []types.ResponseMode{types.ResponseModeJWT, types.ResponseModeQueryJWT, types.ResponseModeFragmentJWT, types.ResponseModeFormPostJWT} {
+		assert.Truef(t, f.CheckResponseMode(mode), "case %s", mode)
+	}
+
+	for _, mode := range []types.ResponseMode{"", types.ResponseModeQuery, types.ResponseModeFragment, types.ResponseModeFormPost} {
+		assert.Falsef(t, f.CheckResponseMode(mode), "case %s", mode)
+	}
+}
+
+func TestFlow_WriteAuthorizationResponse(t *testing.T) {
+	key, pemKey := newSigningKey(t)
+	f := New(NewConfig().SetIssuer(testIssuer).SetSigningKey(pemKey, jwt.SigningMethodES256, "kid-1"))
+	params := map[string]interface{}{"code": "abc123", "state": "xyz", "iss": "ignored"}
+
+	t.Run("success_with_query_jwt", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+		err := f.WriteAuthorizationResponse(newAuthorizationRequest(types.ResponseModeQueryJWT, types.ResponseTypeCode), rw, params)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusFound, rw.Code)
+
+		location, err := url.Parse(rw.Header().Get("Location"))
+		require.NoError(t, err)
+		assert.Equal(t, "client.example.com", location.Host)
+		assert.Len(t, location.Query(), 1)
+
+		claims := parseResponse(t, key, location.Query().Get(ParamResponse))
+		assert.Equal(t, "abc123", claims["code"])
+		assert.Equal(t, "xyz", claims["state"])
+		assert.Equal(t, testIssuer, claims["iss"])
+		assert.Contains(t, claims, "iat")
+	})
+
+	t.Run("success_jwt_defaults_to_query_for_code", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+		err := f.WriteAuthorizationResponse(newAuthorizationRequest(types.ResponseModeJWT, types.ResponseTypeCode), rw, params)
+		require.NoError(t, err)
+		assert.Contains(t, rw.Header().Get("Location"), testRedirectURI+"?response=")
+	})
+
+	t.Run("success_jwt_defaults_to_fragment_for_token", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+		err := f.WriteAuthorizationResponse(newAuthorizationRequest(types.ResponseModeJWT, "code id_token"), rw, params)
+		require.NoError(t, err)
+		assert.Contains(t, rw.Header().Get("Location"), testRedirectURI+"#response=")
+	})
+
+	t.Run("success_with_fragment_jwt", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+		err := f.WriteAuthorizationResponse(newAuthorizationRequest(types.ResponseModeFragmentJWT, types.ResponseTypeCode), rw, params)
+		require.NoError(t, err)
+
+		location, err := url.Parse(rw.Header().Get("Location"))
+		require.NoError(t, err)
+		fragment, err := url.ParseQuery(location.Fragment)
+		require.NoError(t, err)
+		parseResponse(t, key, fragment.Get(ParamResponse))
+	})
+
+	t.Run("success_with_form_post_jwt", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+		err := f.WriteAuthorizationResponse(newAuthorizationRequest(types.ResponseModeFormPostJWT, types.ResponseTypeCode), rw, params)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rw.Code)
+		assert.Contains(t, rw.Header().Get("Content-Security-Policy"), "form-action https://client.example.com")
+
+		match := regexp.MustCompile(`name="response" value="([^"]+)"`).FindStringSubmatch(rw.Body.String())
+		require.Len(t, match, 2)
+		parseResponse(t, key, match[1])
+	})
+
+	t.Run("error_on_invalid_signing_key", func(t *testing.T) {
+		f := New(NewConfig().SetIssuer(testIssuer).SetSigningKey([]byte("not a pem"), jwt.SigningMethodES256))
+		err := f.WriteAuthorizationResponse(newAuthorizationRequest(types.ResponseModeQueryJWT, types.ResponseTypeCode), httptest.NewRecorder(), params)
+		assert.Error(t, err)
+	})
+}
+
+func TestFlow_WriteErrorResponse(t *testing.T) {
+	key, pemKey := newSigningKey(t)
+	f := New(NewConfig().SetIssuer(testIssuer).SetSigningKey(pemKey, jwt.SigningMethodES256, "kid-1"))
+
+	t.Run("success_jwt_defaults_to_query", func(t *testing.T) {
+		authErr := autherrors.AccessDeniedError().
+			WithState("xyz").
+			WithRedirectURI(testRedirectURI).
+			WithResponseMode(types.ResponseModeJWT, testClientID)
+
+		rw := httptest.NewRecorder()
+		require.NoError(t, f.WriteErrorResponse(rw, authErr))
+
+		location, err := url.Parse(rw.Header().Get("Location"))
+		require.NoError(t, err)
+		claims := parseResponse(t, key, location.Query().Get(ParamResponse))
+		assert.Equal(t, "access_denied", claims["error"])
+		assert.Equal(t, "xyz", claims["state"])
+	})
+
+	t.Run("success_jwt_uses_fragment_for_fragment_errors", func(t *testing.T) {
+		authErr := autherrors.AccessDeniedError().
+			WithRedirectURI(testRedirectURI).
+			WithFragment().
+			WithResponseMode(types.ResponseModeJWT, testClientID)
+
+		rw := httptest.NewRecorder()
+		require.NoError(t, f.WriteErrorResponse(rw, authErr))
+		assert.Contains(t, rw.Header().Get("Location"), testRedirectURI+"#response=")
+	})
+}
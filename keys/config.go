@@ -0,0 +1,199 @@
+package keys
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/tniah/authlib/utils"
+)
+
+const (
+	// EndpointNameJWKS is the default endpoint name used to register the JWKS
+	// handler with the server.
+	EndpointNameJWKS = "jwks"
+	// DefaultMaxAge is how long clients may cache the key set by default. Keep
+	// it well below the time a next key is published before it is used.
+	DefaultMaxAge = time.Minute * 10
+	// DefaultRotationPeriod is how long a key signs by default before the next
+	// key replaces it.
+	DefaultRotationPeriod = time.Hour * 24 * 30
+	// DefaultPublishAhead is how long a new key is published by default before
+	// it signs.
+	DefaultPublishAhead = time.Hour * 24
+	// DefaultGracePeriod is how long a key stays published by default after it
+	// stopped signing.
+	DefaultGracePeriod = time.Hour * 24
+)
+
+var (
+	ErrEmptyEndpointName = errors.New("endpoint name is empty")
+	ErrNilKeySet         = errors.New("key set is nil")
+
+	ErrNilKeyStore           = errors.New("key store is nil")
+	ErrNilKeyGenerator       = errors.New("key generator is nil")
+	ErrInvalidRotationPeriod = errors.New("rotation period must be positive")
+	ErrInvalidPublishAhead   = errors.New("publish ahead must be non-negative and shorter than the rotation period")
+	ErrInvalidGracePeriod    = errors.New("grace period must be non-negative")
+)
+
+// Config holds all settings for JWKSFlow. Use NewConfig to obtain a value with
+// defaults, then chain Set* calls to configure the key set.
+type Config struct {
+	endpointName string
+	keySet       KeySet
+	maxAge       time.Duration
+}
+
+// NewConfig returns a Config with EndpointNameJWKS as the endpoint name and
+// DefaultMaxAge as the cache lifetime.
+func NewConfig() *Config {
+	return &Config{
+		endpointName: EndpointNameJWKS,
+		maxAge:       DefaultMaxAge,
+	}
+}
+
+// SetEndpointName overrides the endpoint name used by CheckEndpoint. Defaults
+// to EndpointNameJWKS ("jwks").
+func (cfg *Config) SetEndpointName(name string) *Config {
+	cfg.endpointName = name
+	return cfg
+}
+
+// SetKeySet sets the KeySet whose public keys are published.
+func (cfg *Config) SetKeySet(ks KeySet) *Config {
+	cfg.keySet = ks
+	return cfg
+}
+
+// SetMaxAge sets how long clients may cache the key set (Cache-Control
+// max-age). Zero disables caching. Default: DefaultMaxAge.
+func (cfg *Config) SetMaxAge(maxAge time.Duration) *Config {
+	cfg.maxAge = maxAge
+	return cfg
+}
+
+// ValidateConfig returns an error if any required configuration is missing.
+// Call this via MustJWKSFlow rather than directly.
+func (cfg *Config) ValidateConfig() error {
+	if cfg.endpointName == "" {
+		return ErrEmptyEndpointName
+	}
+
+	if utils.IsNil(cfg.keySet) {
+		return ErrNilKeySet
+	}
+
+	return nil
+}
+
+// RotationConfig holds all settings for RotatingKeySet. Use NewRotationConfig
+// to obtain a value with defaults, then chain Set* calls to configure the key
+// store and the schedule.
+type RotationConfig struct {
+	keyStore       KeyStore
+	keyGenerator   KeyGenerator
+	signingMethod  jwt.SigningMethod
+	rotationPeriod time.Duration
+	publishAhead   time.Duration
+	gracePeriod    time.Duration
+	errorHandler   func(err error)
+	now            func() time.Time
+}
+
+// NewRotationConfig returns a RotationConfig generating RS256 keys with
+// GenerateKey on the default schedule: DefaultRotationPeriod,
+// DefaultPublishAhead and DefaultGracePeriod.
+func NewRotationConfig() *RotationConfig {
+	return &RotationConfig{
+		keyGenerator:   generateKey,
+		signingMethod:  jwt.SigningMethodRS256,
+		rotationPeriod: DefaultRotationPeriod,
+		publishAhead:   DefaultPublishAhead,
+		gracePeriod:    DefaultGracePeriod,
+		now:            time.Now,
+	}
+}
+
+// SetKeyStore sets the KeyStore the keys are persisted in. Required.
+func (cfg *RotationConfig) SetKeyStore(store KeyStore) *RotationConfig {
+	cfg.keyStore = store
+	return cfg
+}
+
+// SetKeyGenerator overrides how new keys are created. Default: GenerateKey.
+func (cfg *RotationConfig) SetKeyGenerator(fn KeyGenerator) *RotationConfig {
+	cfg.keyGenerator = fn
+	return cfg
+}
+
+// SetSigningMethod sets the algorithm new keys sign with: RS*, PS*, ES* or
+// EdDSA. Keys already generated keep their algorithm. Default: RS256.
+func (cfg *RotationConfig) SetSigningMethod(method jwt.SigningMethod) *RotationConfig {
+	cfg.signingMethod = method
+	return cfg
+}
+
+// SetRotationPeriod sets how long each key signs before the next one takes
+// over. Default: DefaultRotationPeriod.
+func (cfg *RotationConfig) SetRotationPeriod(d time.Duration) *RotationConfig {
+	cfg.rotationPeriod = d
+	return cfg
+}
+
+// SetPublishAhead sets how long a new key is published before it signs. Keep
+// it longer than the JWKS max age so that verifiers have refreshed their
+// cache by the time the key is used. Default: DefaultPublishAhead.
+func (cfg *RotationConfig) SetPublishAhead(d time.Duration) *RotationConfig {
+	cfg.publishAhead = d
+	return cfg
+}
+
+// SetGracePeriod sets how long a key stays published after it stopped
+// signing. Keep it at least the lifetime of the longest-lived token it signs.
+// Default: DefaultGracePeriod.
+func (cfg *RotationConfig) SetGracePeriod(d time.Duration) *RotationConfig {
+	cfg.gracePeriod = d
+	return cfg
+}
+
+// SetErrorHandler sets the function receiving the errors of the rotations
+// run in the background by RotatingKeySet.Run. The current keys stay in use
+// until the next rotation succeeds.
+func (cfg *RotationConfig) SetErrorHandler(fn func(err error)) *RotationConfig {
+	cfg.errorHandler = fn
+	return cfg
+}
+
+// ValidateConfig returns an error if any required configuration is missing
+// or the schedule is inconsistent. Call this via MustRotatingKeySet rather
+// than directly.
+func (cfg *RotationConfig) ValidateConfig() error {
+	if utils.IsNil(cfg.keyStore) {
+		return ErrNilKeyStore
+	}
+
+	if cfg.keyGenerator == nil {
+		return ErrNilKeyGenerator
+	}
+
+	if utils.IsNil(cfg.signingMethod) || strings.HasPrefix(cfg.signingMethod.Alg(), "HS") {
+		return ErrInvalidSigningMethod
+	}
+
+	if cfg.rotationPeriod <= 0 {
+		return ErrInvalidRotationPeriod
+	}
+
+	if cfg.publishAhead < 0 || cfg.publishAhead >= cfg.rotationPeriod {
+		return ErrInvalidPublishAhead
+	}
+
+	if cfg.gracePeriod < 0 {
+		return ErrInvalidGracePeriod
+	}
+
+	return nil
+}
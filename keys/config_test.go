@@ -0,0 +1,85 @@
+package keys
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewConfig(t *testing.T) {
+	cfg := NewConfig()
+	assert.Equal(t, EndpointNameJWKS, cfg.endpointName)
+	assert.Equal(t, DefaultMaxAge, cfg.maxAge)
+	assert.Nil(t, cfg.keySet)
+}
+
+func TestConfig_Setters(t *testing.T) {
+	ks := NewStaticKeySet(nil)
+	cfg := NewConfig().
+		SetEndpointName("keys").
+		SetKeySet(ks).
+		SetMaxAge(time.Hour)
+
+	assert.Equal(t, "keys", cfg.endpointName)
+	assert.Equal(t, ks, cfg.keySet)
+	assert.Equal(t, time.Hour, cfg.maxAge)
+}
+
+func TestConfig_ValidateConfig(t *testing.T) {
+	assert.NoError(t, NewConfig().SetKeySet(NewStaticKeySet(nil)).ValidateConfig())
+	assert.ErrorIs(t, NewConfig().SetEndpointName("").SetKeySet(NewStaticKeySet(nil)).ValidateConfig(), ErrEmptyEndpointName)
+	assert.ErrorIs(t, NewConfig().ValidateConfig(), ErrNilKeySet)
+	assert.ErrorIs(t, NewConfig().SetKeySet((*StaticKeySet)(nil)).ValidateConfig(), ErrNilKeySet)
+}
+
+func TestNewRotationConfig(t *testing.T) {
+	cfg := NewRotationConfig()
+	assert.Equal(t, jwt.SigningMethodRS256, cfg.signingMethod)
+	assert.Equal(t, DefaultRotationPeriod, cfg.rotationPeriod)
+	assert.Equal(t, DefaultPublishAhead, cfg.publishAhead)
+	assert.Equal(t, DefaultGracePeriod, cfg.gracePeriod)
+	assert.NotNil(t, cfg.keyGenerator)
+	assert.Nil(t, cfg.keyStore)
+}
+
+func TestRotationConfig_Setters(t *testing.T) {
+	store := NewMemoryKeyStore()
+	var handled error
+	cfg := NewRotationConfig().
+		SetKeyStore(store).
+		SetKeyGenerator(func(method jwt.SigningMethod) (*Key, error) { return nil, nil }).
+		SetSigningMethod(jwt.SigningMethodEdDSA).
+		SetRotationPeriod(time.Hour).
+		SetPublishAhead(time.Minute).
+		SetGracePeriod(time.Second).
+		SetErrorHandler(func(err error) { handled = err })
+
+	assert.Equal(t, store, cfg.keyStore)
+	assert.Equal(t, jwt.SigningMethodEdDSA, cfg.signingMethod)
+	assert.Equal(t, time.Hour, cfg.rotationPeriod)
+	assert.Equal(t, time.Minute, cfg.publishAhead)
+	assert.Equal(t, time.Second, cfg.gracePeriod)
+
+	cfg.errorHandler(ErrNoSigningKey)
+	assert.ErrorIs(t, handled, ErrNoSigningKey)
+}
+
+func TestRotationConfig_ValidateConfig(t *testing.T) {
+	valid := func() *RotationConfig {
+		return NewRotationConfig().SetKeyStore(NewMemoryKeyStore())
+	}
+
+	assert.NoError(t, valid().ValidateConfig())
+	assert.NoError(t, valid().SetPublishAhead(0).SetGracePeriod(0).ValidateConfig())
+	assert.ErrorIs(t, NewRotationConfig().ValidateConfig(), ErrNilKeyStore)
+	assert.ErrorIs(t, NewRotationConfig().SetKeyStore((*MemoryKeyStore)(nil)).ValidateConfig(), ErrNilKeyStore)
+	assert.ErrorIs(t, valid().SetKeyGenerator(nil).ValidateConfig(), ErrNilKeyGenerator)
+	assert.ErrorIs(t, valid().SetSigningMethod(nil).ValidateConfig(), ErrInvalidSigningMethod)
+	assert.ErrorIs(t, valid().SetSigningMethod(jwt.SigningMethodHS256).ValidateConfig(), ErrInvalidSigningMethod)
+	assert.ErrorIs(t, valid().SetRotationPeriod(0).ValidateConfig(), ErrInvalidRotationPeriod)
+	assert.ErrorIs(t, valid().SetPublishAhead(-time.Second).ValidateConfig(), ErrInvalidPublishAhead)
+	assert.ErrorIs(t, valid().SetRotationPeriod(time.Hour).SetPublishAhead(time.Hour).ValidateConfig(), ErrInvalidPublishAhead)
+	assert.ErrorIs(t, valid().SetGracePeriod(-time.Second).ValidateConfig(), ErrInvalidGracePeriod)
+}
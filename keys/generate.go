@@ -0,0 +1,63 @@
+package keys
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/tniah/authlib/utils"
+)
+
+// DefaultRSAKeySize is the modulus size, in bits, of RSA keys created by
+// GenerateKey.
+const DefaultRSAKeySize = 2048
+
+// KeyGenerator creates a new signing key for method. GenerateKey is the
+// default; replace it to create keys in an HSM or a key management service.
+type KeyGenerator func(method jwt.SigningMethod) (*Key, error)
+
+// GenerateKey creates a new random key signing with method: an RSA key of
+// DefaultRSAKeySize bits for RS* and PS*, an ECDSA key on the matching curve
+// for ES256, ES384 and ES512, or an Ed25519 key for EdDSA. Returns
+// ErrInvalidSigningMethod for any other method.
+func GenerateKey(method jwt.SigningMethod, keyID ...string) (*Key, error) {
+	if utils.IsNil(method) {
+		return nil, ErrInvalidSigningMethod
+	}
+
+	var (
+		signer crypto.Signer
+		err    error
+	)
+
+	switch alg := method.Alg(); {
+	case strings.HasPrefix(alg, "RS"), strings.HasPrefix(alg, "PS"):
+		signer, err = rsa.GenerateKey(rand.Reader, DefaultRSAKeySize)
+	case alg == jwt.SigningMethodES256.Alg():
+		signer, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case alg == jwt.SigningMethodES384.Alg():
+		signer, err = ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	case alg == jwt.SigningMethodES512.Alg():
+		signer, err = ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	case alg == jwt.SigningMethodEdDSA.Alg():
+		_, signer, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		return nil, ErrInvalidSigningMethod
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return NewKey(signer, method, keyID...)
+}
+
+// generateKey is the default KeyGenerator.
+func generateKey(method jwt.SigningMethod) (*Key, error) {
+	return GenerateKey(method)
+}
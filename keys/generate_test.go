@@ -0,0 +1,61 @@
+package keys
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateKey(t *testing.T) {
+	t.Run("rsa", func(t *testing.T) {
+		key, err := GenerateKey(jwt.SigningMethodPS256)
+		require.NoError(t, err)
+		pub, ok := key.Public().(*rsa.PublicKey)
+		require.True(t, ok)
+		assert.Equal(t, DefaultRSAKeySize, pub.N.BitLen())
+		assert.Equal(t, "PS256", key.JWK().Alg)
+	})
+
+	t.Run("ecdsa_curve_matches_method", func(t *testing.T) {
+		for method, curve := range map[jwt.SigningMethod]elliptic.Curve{
+			jwt.SigningMethodES256: elliptic.P256(),
+			jwt.SigningMethodES384: elliptic.P384(),
+			jwt.SigningMethodES512: elliptic.P521(),
+		} {
+			key, err := GenerateKey(method)
+			require.NoError(t, err)
+			pub, ok := key.Public().(*ecdsa.PublicKey)
+			require.True(t, ok)
+			assert.Equal(t, curve, pub.Curve)
+		}
+	})
+
+	t.Run("ed25519_with_kid", func(t *testing.T) {
+		key, err := GenerateKey(jwt.SigningMethodEdDSA, "ed-1")
+		require.NoError(t, err)
+		assert.IsType(t, ed25519.PublicKey{}, key.Public())
+		assert.Equal(t, "ed-1", key.ID())
+	})
+
+	t.Run("keys_are_random", func(t *testing.T) {
+		a, err := GenerateKey(jwt.SigningMethodES256)
+		require.NoError(t, err)
+		b, err := GenerateKey(jwt.SigningMethodES256)
+		require.NoError(t, err)
+		assert.NotEqual(t, a.ID(), b.ID())
+	})
+
+	t.Run("invalid_method", func(t *testing.T) {
+		_, err := GenerateKey(jwt.SigningMethodHS256)
+		assert.ErrorIs(t, err, ErrInvalidSigningMethod)
+
+		_, err = GenerateKey(nil)
+		assert.ErrorIs(t, err, ErrInvalidSigningMethod)
+	})
+}
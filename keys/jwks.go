@@ -0,0 +1,118 @@
+package keys
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	autherrors "github.com/tniah/authlib/errors"
+	"github.com/tniah/authlib/types"
+	"github.com/tniah/authlib/utils"
+)
+
+// JWKSFlow serves the public keys of a KeySet as a JWK Set (RFC 7517 §5),
+// typically at /jwks.json, the jwks_uri of the server metadata. It is
+// registered as an endpoint on the server via Server.RegisterEndpoint and
+// dispatched by Server.EndpointResponse when the endpoint name matches.
+type JWKSFlow struct {
+	*Config
+}
+
+// NewJWKSFlow creates a JWKSFlow from cfg without validating it. Prefer
+// MustJWKSFlow for production use.
+func NewJWKSFlow(cfg *Config) *JWKSFlow {
+	return &JWKSFlow{cfg}
+}
+
+// MustJWKSFlow creates a JWKSFlow after validating cfg. Returns an error if
+// any required configuration is missing.
+func MustJWKSFlow(cfg *Config) (*JWKSFlow, error) {
+	if err := cfg.ValidateConfig(); err != nil {
+		return nil, err
+	}
+
+	return NewJWKSFlow(cfg), nil
+}
+
+// CheckEndpoint reports whether name matches the configured endpoint name.
+// The server calls this to route requests to the correct registered endpoint.
+func (f *JWKSFlow) CheckEndpoint(name string) bool {
+	if f.endpointName == "" {
+		return false
+	}
+
+	return name == f.endpointName
+}
+
+// EndpointResponse writes the JWK Set. The response may be cached for the
+// configured max age and carries an ETag, so that clients revalidating with
+// If-None-Match get 304 Not Modified while the keys are unchanged.
+func (f *JWKSFlow) EndpointResponse(r *http.Request, rw http.ResponseWriter) error {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return autherrors.InvalidRequestError().WithDescription(fmt.Sprintf("unsupported http method \"%s\"", r.Method))
+	}
+
+	set, err := f.JWKSet(r)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(set)
+	if err != nil {
+		return err
+	}
+
+	sum := sha256.Sum256(body)
+	etag := `"` + base64.RawURLEncoding.EncodeToString(sum[:16]) + `"`
+
+	rw.Header().Set("Content-Type", types.ContentTypeJSON.String())
+	rw.Header().Set("ETag", etag)
+	if seconds := int(f.maxAge.Seconds()); seconds > 0 {
+		rw.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", seconds))
+	} else {
+		rw.Header().Set("Cache-Control", "no-cache")
+	}
+
+	if matchETag(r.Header.Get("If-None-Match"), etag) {
+		rw.WriteHeader(http.StatusNotModified)
+		return nil
+	}
+
+	rw.WriteHeader(http.StatusOK)
+	if r.Method == http.MethodHead {
+		return nil
+	}
+
+	_, err = rw.Write(body)
+	return err
+}
+
+// JWKSet returns the public keys of the key set as a JWK Set.
+func (f *JWKSFlow) JWKSet(r *http.Request) (*utils.JWKSet, error) {
+	keys, err := f.keySet.PublicKeys(r.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	set := &utils.JWKSet{Keys: make([]utils.JWK, 0, len(keys))}
+	for _, k := range keys {
+		set.Keys = append(set.Keys, k.JWK())
+	}
+
+	return set, nil
+}
+
+// matchETag reports whether the If-None-Match header value matches etag.
+func matchETag(header, etag string) bool {
+	for _, v := range strings.Split(header, ",") {
+		v = strings.TrimPrefix(strings.TrimSpace(v), "W/")
+		if v == etag || v == "*" {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,87 @@
+package keys
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tniah/authlib"
+	autherrors "github.com/tniah/authlib/errors"
+	"github.com/tniah/authlib/utils"
+)
+
+func TestMustJWKSFlow(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		f, err := MustJWKSFlow(NewConfig().SetKeySet(NewStaticKeySet(newKey(t))))
+		assert.NoError(t, err)
+		assert.True(t, f.CheckEndpoint(EndpointNameJWKS))
+		assert.False(t, f.CheckEndpoint("introspection"))
+	})
+
+	t.Run("error_on_invalid_config", func(t *testing.T) {
+		f, err := MustJWKSFlow(NewConfig())
+		assert.Nil(t, f)
+		assert.ErrorIs(t, err, ErrNilKeySet)
+	})
+}
+
+func TestJWKSFlow_EndpointResponse(t *testing.T) {
+	active, next := newKey(t, "active"), newKey(t, "next")
+	f := NewJWKSFlow(NewConfig().SetKeySet(NewStaticKeySet(active).SetNextKeys(next)))
+
+	srv := authlib.NewServer()
+	srv.RegisterEndpoint(f)
+
+	t.Run("success", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+		err := srv.EndpointResponse(httptest.NewRequest(http.MethodGet, "/jwks.json", nil), rw, EndpointNameJWKS)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rw.Code)
+		assert.Contains(t, rw.Header().Get("Content-Type"), "application/json")
+		assert.Equal(t, "public, max-age=600", rw.Header().Get("Cache-Control"))
+		assert.NotEmpty(t, rw.Header().Get("ETag"))
+
+		set, err := utils.ParseJWKSet(rw.Body.Bytes())
+		require.NoError(t, err)
+		assert.Equal(t, []utils.JWK{active.JWK(), next.JWK()}, set.Keys)
+
+		pub, err := set.SigningKeys("next", "ES256")[0].PublicKey()
+		require.NoError(t, err)
+		assert.Equal(t, next.Public(), pub)
+	})
+
+	t.Run("not_modified", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+		require.NoError(t, f.EndpointResponse(httptest.NewRequest(http.MethodGet, "/jwks.json", nil), rw))
+
+		hr := httptest.NewRequest(http.MethodGet, "/jwks.json", nil)
+		hr.Header.Set("If-None-Match", `"other", `+rw.Header().Get("ETag"))
+		rw2 := httptest.NewRecorder()
+		require.NoError(t, f.EndpointResponse(hr, rw2))
+		assert.Equal(t, http.StatusNotModified, rw2.Code)
+		assert.Empty(t, rw2.Body.Bytes())
+	})
+
+	t.Run("head", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+		require.NoError(t, f.EndpointResponse(httptest.NewRequest(http.MethodHead, "/jwks.json", nil), rw))
+		assert.Equal(t, http.StatusOK, rw.Code)
+		assert.Empty(t, rw.Body.Bytes())
+	})
+
+	t.Run("caching_disabled", func(t *testing.T) {
+		f := NewJWKSFlow(NewConfig().SetKeySet(NewStaticKeySet(active)).SetMaxAge(time.Duration(0)))
+		rw := httptest.NewRecorder()
+		require.NoError(t, f.EndpointResponse(httptest.NewRequest(http.MethodGet, "/jwks.json", nil), rw))
+		assert.Equal(t, "no-cache", rw.Header().Get("Cache-Control"))
+	})
+
+	t.Run("error_when_method_is_not_get", func(t *testing.T) {
+		err := f.EndpointResponse(httptest.NewRequest(http.MethodPost, "/jwks.json", nil), httptest.NewRecorder())
+		assert.Equal(t, autherrors.ErrInvalidRequest, autherrors.ToAuthLibError(err).Code)
+	})
+}
@@ -0,0 +1,149 @@
+// Package keys manages the asymmetric keys that sign JWTs (access tokens, ID
+// Tokens) and publishes their public parts as a JSON Web Key Set (RFC 7517
+// §5), so that relying parties and resource servers can verify signatures
+// without sharing key material out of band.
+package keys
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"errors"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/tniah/authlib/utils"
+)
+
+var (
+	// ErrNilSigner is returned by NewKey when the private key is nil.
+	ErrNilSigner = errors.New("signer is nil")
+	// ErrInvalidSigningMethod is returned by NewKey when the signing method is
+	// missing, symmetric, or does not match the type or curve of the key.
+	ErrInvalidSigningMethod = errors.New("signing method does not match the key")
+)
+
+// Key is an asymmetric signing key: the private key, the algorithm it signs
+// with, and its key ID. Keys are immutable; create them with NewKey or
+// ParsePrivateKeyPEM.
+type Key struct {
+	id     string
+	method jwt.SigningMethod
+	signer crypto.Signer
+	jwk    utils.JWK
+}
+
+// NewKey returns a Key signing with signer, an *rsa.PrivateKey,
+// *ecdsa.PrivateKey, ed25519.PrivateKey, or any other crypto.Signer such as an
+// ExternalSigner backed by a KMS, using method. When keyID is
+// omitted, the kid is the RFC 7638 thumbprint of the public key, so the same
+// key always gets the same kid.
+func NewKey(signer crypto.Signer, method jwt.SigningMethod, keyID ...string) (*Key, error) {
+	if utils.IsNil(signer) {
+		return nil, ErrNilSigner
+	}
+
+	if utils.IsNil(method) || !matchMethod(signer.Public(), method.Alg()) {
+		return nil, ErrInvalidSigningMethod
+	}
+
+	jwk, err := utils.NewJWK(signer.Public())
+	if err != nil {
+		return nil, err
+	}
+
+	kid := ""
+	if len(keyID) > 0 {
+		kid = keyID[0]
+	}
+
+	if kid == "" {
+		if kid, err = jwk.Thumbprint(); err != nil {
+			return nil, err
+		}
+	}
+
+	jwk.Kid = kid
+	jwk.Alg = method.Alg()
+	jwk.Use = "sig"
+
+	return &Key{
+		id:     kid,
+		method: method,
+		signer: signer,
+		jwk:    jwk,
+	}, nil
+}
+
+// ParsePrivateKeyPEM parses a PEM-encoded private key, the format accepted by
+// SetSigningKey across the library, and returns it as a Key.
+func ParsePrivateKeyPEM(data []byte, method jwt.SigningMethod, keyID ...string) (*Key, error) {
+	if utils.IsNil(method) || strings.HasPrefix(method.Alg(), "HS") {
+		return nil, ErrInvalidSigningMethod
+	}
+
+	key, err := utils.ParseSigningKey(data, method)
+	if err != nil {
+		return nil, err
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, ErrInvalidSigningMethod
+	}
+
+	return NewKey(signer, method, keyID...)
+}
+
+// ID returns the key ID (kid).
+func (k *Key) ID() string {
+	return k.id
+}
+
+// Method returns the signing method.
+func (k *Key) Method() jwt.SigningMethod {
+	return k.method
+}
+
+// Signer returns the private key.
+func (k *Key) Signer() crypto.Signer {
+	return k.signer
+}
+
+// Public returns the public key.
+func (k *Key) Public() crypto.PublicKey {
+	return k.signer.Public()
+}
+
+// JWK returns the public key as a JWK carrying kid, alg and use=sig.
+func (k *Key) JWK() utils.JWK {
+	return k.jwk
+}
+
+// JWTToken returns a JWTToken signing with the key and setting its kid.
+func (k *Key) JWTToken() *utils.JWTToken {
+	return utils.NewJWTTokenWithKey(k.signer, k.method, k.id)
+}
+
+// matchMethod reports whether alg can sign with a key of the type of pub.
+func matchMethod(pub crypto.PublicKey, alg string) bool {
+	switch pub := pub.(type) {
+	case *rsa.PublicKey:
+		return strings.HasPrefix(alg, "RS") || strings.HasPrefix(alg, "PS")
+	case *ecdsa.PublicKey:
+		switch pub.Curve {
+		case elliptic.P256():
+			return alg == jwt.SigningMethodES256.Alg()
+		case elliptic.P384():
+			return alg == jwt.SigningMethodES384.Alg()
+		case elliptic.P521():
+			return alg == jwt.SigningMethodES512.Alg()
+		}
+	case ed25519.PublicKey:
+		return alg == jwt.SigningMethodEdDSA.Alg()
+	}
+
+	return false
+}
@@ -0,0 +1,130 @@
+package keys
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tniah/authlib/utils"
+)
+
+func newECKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return key
+}
+
+func newKey(t *testing.T, keyID ...string) *Key {
+	t.Helper()
+	k, err := NewKey(newECKey(t), jwt.SigningMethodES256, keyID...)
+	require.NoError(t, err)
+	return k
+}
+
+func TestNewKey(t *testing.T) {
+	t.Run("kid_defaults_to_thumbprint", func(t *testing.T) {
+		priv := newECKey(t)
+		k, err := NewKey(priv, jwt.SigningMethodES256)
+		require.NoError(t, err)
+
+		jwk, err := utils.NewJWK(&priv.PublicKey)
+		require.NoError(t, err)
+		thumbprint, err := jwk.Thumbprint()
+		require.NoError(t, err)
+
+		assert.Equal(t, thumbprint, k.ID())
+		assert.Equal(t, jwt.SigningMethodES256, k.Method())
+		assert.Equal(t, priv, k.Signer())
+		assert.Equal(t, &priv.PublicKey, k.Public())
+
+		jwk.Kid, jwk.Alg, jwk.Use = thumbprint, "ES256", "sig"
+		assert.Equal(t, jwk, k.JWK())
+	})
+
+	t.Run("explicit_kid", func(t *testing.T) {
+		k := newKey(t, "key-1")
+		assert.Equal(t, "key-1", k.ID())
+		assert.Equal(t, "key-1", k.JWK().Kid)
+	})
+
+	t.Run("matching_methods", func(t *testing.T) {
+		rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		_, edKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		p384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+		require.NoError(t, err)
+
+		for _, method := range []jwt.SigningMethod{jwt.SigningMethodRS256, jwt.SigningMethodPS512} {
+			_, err = NewKey(rsaKey, method)
+			assert.NoError(t, err)
+		}
+
+		_, err = NewKey(edKey, jwt.SigningMethodEdDSA)
+		assert.NoError(t, err)
+		_, err = NewKey(p384, jwt.SigningMethodES384)
+		assert.NoError(t, err)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		_, err := NewKey(nil, jwt.SigningMethodES256)
+		assert.ErrorIs(t, err, ErrNilSigner)
+
+		_, err = NewKey(newECKey(t), nil)
+		assert.ErrorIs(t, err, ErrInvalidSigningMethod)
+
+		_, err = NewKey(newECKey(t), jwt.SigningMethodES384)
+		assert.ErrorIs(t, err, ErrInvalidSigningMethod)
+
+		_, err = NewKey(newECKey(t), jwt.SigningMethodRS256)
+		assert.ErrorIs(t, err, ErrInvalidSigningMethod)
+	})
+}
+
+func TestParsePrivateKeyPEM(t *testing.T) {
+	priv := newECKey(t)
+	der, err := x509.MarshalECPrivateKey(priv)
+	require.NoError(t, err)
+	data := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})
+
+	t.Run("success", func(t *testing.T) {
+		k, err := ParsePrivateKeyPEM(data, jwt.SigningMethodES256, "key-1")
+		require.NoError(t, err)
+		assert.Equal(t, "key-1", k.ID())
+		assert.True(t, priv.Equal(k.Signer()))
+	})
+
+	t.Run("error_on_hmac", func(t *testing.T) {
+		_, err := ParsePrivateKeyPEM([]byte("secret"), jwt.SigningMethodHS256)
+		assert.ErrorIs(t, err, ErrInvalidSigningMethod)
+	})
+
+	t.Run("error_on_invalid_pem", func(t *testing.T) {
+		_, err := ParsePrivateKeyPEM([]byte("invalid"), jwt.SigningMethodES256)
+		assert.Error(t, err)
+	})
+}
+
+func TestKey_JWTToken(t *testing.T) {
+	priv := newECKey(t)
+	k, err := NewKey(priv, jwt.SigningMethodES256, "key-1")
+	require.NoError(t, err)
+
+	signed, err := k.JWTToken().Generate(utils.JWTClaim{"sub": "user-1"}, nil)
+	require.NoError(t, err)
+
+	token, err := jwt.Parse(signed, func(*jwt.Token) (interface{}, error) {
+		return &priv.PublicKey, nil
+	}, jwt.WithValidMethods([]string{"ES256"}))
+	require.NoError(t, err)
+	assert.Equal(t, "key-1", token.Header["kid"])
+}
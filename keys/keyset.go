@@ -0,0 +1,70 @@
+package keys
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNoSigningKey is returned by KeySet.SigningKey when no key is active.
+var ErrNoSigningKey = errors.New("no active signing key")
+
+// KeySet holds the signing keys of the authorization server. Token generators
+// sign with its current key; the JWKS endpoint publishes all of its keys.
+type KeySet interface {
+	// SigningKey returns the key new tokens are signed with.
+	SigningKey(ctx context.Context) (*Key, error)
+	// PublicKeys returns every key whose signatures must verify: the active
+	// key, keys about to become active, and retiring keys that still signed
+	// unexpired tokens.
+	PublicKeys(ctx context.Context) ([]*Key, error)
+}
+
+// StaticKeySet is a KeySet whose keys are set by the application: one active
+// key, plus the keys published ahead of a planned switch (next) and the keys
+// kept published after it (retiring). Rotate keys by moving them from next to
+// active to retiring across deployments.
+type StaticKeySet struct {
+	active   *Key
+	next     []*Key
+	retiring []*Key
+}
+
+// NewStaticKeySet returns a StaticKeySet signing with active.
+func NewStaticKeySet(active *Key) *StaticKeySet {
+	return &StaticKeySet{active: active}
+}
+
+// SetNextKeys sets the keys published before they become active, so that
+// verifiers have them cached by the time tokens signed with them appear.
+func (s *StaticKeySet) SetNextKeys(keys ...*Key) *StaticKeySet {
+	s.next = keys
+	return s
+}
+
+// SetRetiringKeys sets the keys no longer used to sign but still published
+// until the tokens they signed have expired.
+func (s *StaticKeySet) SetRetiringKeys(keys ...*Key) *StaticKeySet {
+	s.retiring = keys
+	return s
+}
+
+// SigningKey returns the active key, or ErrNoSigningKey when it is nil.
+func (s *StaticKeySet) SigningKey(_ context.Context) (*Key, error) {
+	if s.active == nil {
+		return nil, ErrNoSigningKey
+	}
+
+	return s.active, nil
+}
+
+// PublicKeys returns the active, next and retiring keys, in that order.
+func (s *StaticKeySet) PublicKeys(_ context.Context) ([]*Key, error) {
+	keys := make([]*Key, 0, 1+len(s.next)+len(s.retiring))
+	if s.active != nil {
+		keys = append(keys, s.active)
+	}
+
+	keys = append(keys, s.next...)
+	keys = append(keys, s.retiring...)
+	return keys, nil
+}
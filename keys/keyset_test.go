@@ -0,0 +1,36 @@
+package keys
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStaticKeySet(t *testing.T) {
+	active, next, retiring := newKey(t, "active"), newKey(t, "next"), newKey(t, "retiring")
+
+	t.Run("success", func(t *testing.T) {
+		ks := NewStaticKeySet(active).SetNextKeys(next).SetRetiringKeys(retiring)
+
+		key, err := ks.SigningKey(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, active, key)
+
+		keys, err := ks.PublicKeys(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, []*Key{active, next, retiring}, keys)
+	})
+
+	t.Run("error_without_active_key", func(t *testing.T) {
+		ks := NewStaticKeySet(nil).SetRetiringKeys(retiring)
+
+		_, err := ks.SigningKey(context.Background())
+		assert.ErrorIs(t, err, ErrNoSigningKey)
+
+		keys, err := ks.PublicKeys(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, []*Key{retiring}, keys)
+	})
+}
@@ -0,0 +1,247 @@
+package keys
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// RotatingKeySet is a KeySet that rotates its keys on a schedule. Each key is
+// generated and published PublishAhead before it starts signing, signs for
+// RotationPeriod, and stays published for GracePeriod after the next key took
+// over. Keys are persisted in the configured KeyStore.
+//
+// Call Rotate once at startup, then run Run in the background. When several
+// server instances share a KeyStore, run the rotation in one of them and call
+// Load periodically in the others.
+type RotatingKeySet struct {
+	*RotationConfig
+	rotate  sync.Mutex
+	lock    sync.RWMutex
+	records []*KeyRecord
+}
+
+// NewRotatingKeySet creates a RotatingKeySet from cfg without validating it.
+// Prefer MustRotatingKeySet for production use.
+func NewRotatingKeySet(cfg *RotationConfig) *RotatingKeySet {
+	return &RotatingKeySet{RotationConfig: cfg}
+}
+
+// MustRotatingKeySet creates a RotatingKeySet after validating cfg. Returns an
+// error if any required configuration is missing.
+func MustRotatingKeySet(cfg *RotationConfig) (*RotatingKeySet, error) {
+	if err := cfg.ValidateConfig(); err != nil {
+		return nil, err
+	}
+
+	return NewRotatingKeySet(cfg), nil
+}
+
+// SigningKey returns the key active at the current time, or ErrNoSigningKey
+// when none is, which is the case until Rotate or Load succeeded.
+func (s *RotatingKeySet) SigningKey(_ context.Context) (*Key, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if r := activeRecord(s.records, s.now()); r != nil {
+		return r.Key, nil
+	}
+
+	return nil, ErrNoSigningKey
+}
+
+// PublicKeys returns the keys not expired at the current time: the active key
+// first, then the keys to become active, then the retiring keys, most recent
+// first.
+func (s *RotatingKeySet) PublicKeys(_ context.Context) ([]*Key, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	now := s.now()
+	active := activeRecord(s.records, now)
+
+	records := make([]*KeyRecord, 0, len(s.records))
+	for _, r := range s.records {
+		if r != active && !r.IsExpired(now) {
+			records = append(records, r)
+		}
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].ActivatesAt.After(records[j].ActivatesAt)
+	})
+
+	keys := make([]*Key, 0, len(records)+1)
+	if active != nil {
+		keys = append(keys, active.Key)
+	}
+
+	for _, r := range records {
+		keys = append(keys, r.Key)
+	}
+
+	return keys, nil
+}
+
+// Records returns a copy of the keys and their lifecycle as last loaded.
+func (s *RotatingKeySet) Records() []*KeyRecord {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return copyRecords(s.records)
+}
+
+// Load replaces the keys in memory with the stored keys, without rotating.
+func (s *RotatingKeySet) Load(ctx context.Context) error {
+	records, err := s.keyStore.LoadKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.setRecords(records)
+	return nil
+}
+
+// Rotate loads the stored keys and advances their lifecycle: expired keys are
+// dropped, and a new key is generated when none is active, or when the active
+// key retires within PublishAhead. The keys are saved back when they changed.
+//
+// If no key is active, because the set is new or rotation did not run for
+// longer than PublishAhead, the new key signs immediately. Otherwise it
+// starts signing when the current key retires; when rotation ran late, the
+// current key keeps signing until the new key has been published for
+// PublishAhead.
+func (s *RotatingKeySet) Rotate(ctx context.Context) error {
+	s.rotate.Lock()
+	defer s.rotate.Unlock()
+
+	stored, err := s.keyStore.LoadKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := s.now()
+	changed := false
+
+	records := make([]*KeyRecord, 0, len(stored)+1)
+	for _, r := range copyRecords(stored) {
+		if r.IsExpired(now) {
+			changed = true
+			continue
+		}
+
+		records = append(records, r)
+	}
+
+	latest := latestRecord(records)
+	switch {
+	case latest == nil || !latest.RetiresAt.After(now):
+		r, err := s.newRecord(now, now)
+		if err != nil {
+			return err
+		}
+
+		records = append(records, r)
+		changed = true
+	case !latest.RetiresAt.After(now.Add(s.publishAhead)):
+		activatesAt := now.Add(s.publishAhead)
+		if latest.RetiresAt.Before(activatesAt) {
+			latest.RetiresAt = activatesAt
+			latest.ExpiresAt = activatesAt.Add(s.gracePeriod)
+		}
+
+		r, err := s.newRecord(now, activatesAt)
+		if err != nil {
+			return err
+		}
+
+		records = append(records, r)
+		changed = true
+	}
+
+	if changed {
+		if err = s.keyStore.SaveKeys(ctx, records); err != nil {
+			return err
+		}
+	}
+
+	s.setRecords(records)
+	return nil
+}
+
+// Run calls Rotate immediately, then every interval until ctx is done. It
+// returns the error of the first rotation, so that a misconfigured key store
+// fails at startup; later errors are passed to the error handler and retried
+// at the next interval. Keep interval well below PublishAhead.
+func (s *RotatingKeySet) Run(ctx context.Context, interval time.Duration) error {
+	if err := s.Rotate(ctx); err != nil {
+		return err
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.Rotate(ctx); err != nil && s.errorHandler != nil {
+					s.errorHandler(err)
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (s *RotatingKeySet) newRecord(now, activatesAt time.Time) (*KeyRecord, error) {
+	key, err := s.keyGenerator(s.signingMethod)
+	if err != nil {
+		return nil, err
+	}
+
+	retiresAt := activatesAt.Add(s.rotationPeriod)
+	return &KeyRecord{
+		Key:         key,
+		CreatedAt:   now,
+		ActivatesAt: activatesAt,
+		RetiresAt:   retiresAt,
+		ExpiresAt:   retiresAt.Add(s.gracePeriod),
+	}, nil
+}
+
+func (s *RotatingKeySet) setRecords(records []*KeyRecord) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.records = records
+}
+
+// activeRecord returns the record active at now, preferring the most recently
+// activated one, or nil.
+func activeRecord(records []*KeyRecord, now time.Time) *KeyRecord {
+	var active *KeyRecord
+	for _, r := range records {
+		if r.IsActive(now) && (active == nil || r.ActivatesAt.After(active.ActivatesAt)) {
+			active = r
+		}
+	}
+
+	return active
+}
+
+// latestRecord returns the record activating last, or nil.
+func latestRecord(records []*KeyRecord) *KeyRecord {
+	var latest *KeyRecord
+	for _, r := range records {
+		if latest == nil || r.ActivatesAt.After(latest.ActivatesAt) {
+			latest = r
+		}
+	}
+
+	return latest
+}
@@ -0,0 +1,306 @@
+package keys
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type clock struct {
+	lock sync.Mutex
+	now  time.Time
+}
+
+func (c *clock) Now() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.now
+}
+
+func (c *clock) Advance(d time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.now = c.now.Add(d)
+}
+
+type failingKeyStore struct {
+	loadErr error
+	saveErr error
+}
+
+func (s *failingKeyStore) LoadKeys(_ context.Context) ([]*KeyRecord, error) {
+	return nil, s.loadErr
+}
+
+func (s *failingKeyStore) SaveKeys(_ context.Context, _ []*KeyRecord) error {
+	return s.saveErr
+}
+
+func newRotatingKeySet(t *testing.T, store KeyStore) (*RotatingKeySet, *clock) {
+	t.Helper()
+	c := &clock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	cfg := NewRotationConfig().
+		SetKeyStore(store).
+		SetSigningMethod(jwt.SigningMethodES256).
+		SetRotationPeriod(24 * time.Hour).
+		SetPublishAhead(time.Hour).
+		SetGracePeriod(2 * time.Hour)
+	cfg.now = c.Now
+
+	ks, err := MustRotatingKeySet(cfg)
+	require.NoError(t, err)
+	return ks, c
+}
+
+func keyIDs(t *testing.T, ks KeySet) []string {
+	t.Helper()
+	keys, err := ks.PublicKeys(context.Background())
+	require.NoError(t, err)
+
+	ids := make([]string, 0, len(keys))
+	for _, k := range keys {
+		ids = append(ids, k.ID())
+	}
+
+	return ids
+}
+
+func signingKeyID(t *testing.T, ks KeySet) string {
+	t.Helper()
+	key, err := ks.SigningKey(context.Background())
+	require.NoError(t, err)
+	return key.ID()
+}
+
+func TestMustRotatingKeySet(t *testing.T) {
+	_, err := MustRotatingKeySet(NewRotationConfig())
+	assert.ErrorIs(t, err, ErrNilKeyStore)
+}
+
+func TestRotatingKeySet_Rotate(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("no_key_before_first_rotation", func(t *testing.T) {
+		ks, _ := newRotatingKeySet(t, NewMemoryKeyStore())
+
+		_, err := ks.SigningKey(ctx)
+		assert.ErrorIs(t, err, ErrNoSigningKey)
+		assert.Empty(t, keyIDs(t, ks))
+	})
+
+	t.Run("lifecycle", func(t *testing.T) {
+		store := NewMemoryKeyStore()
+		ks, c := newRotatingKeySet(t, store)
+
+		// Bootstrap: the first key signs immediately.
+		require.NoError(t, ks.Rotate(ctx))
+		first := signingKeyID(t, ks)
+		assert.Equal(t, []string{first}, keyIDs(t, ks))
+
+		stored, err := store.LoadKeys(ctx)
+		require.NoError(t, err)
+		require.Len(t, stored, 1)
+		assert.Equal(t, c.Now(), stored[0].ActivatesAt)
+		assert.Equal(t, c.Now().Add(24*time.Hour), stored[0].RetiresAt)
+		assert.Equal(t, c.Now().Add(26*time.Hour), stored[0].ExpiresAt)
+
+		// Nothing to do until the next key must be published.
+		c.Advance(22 * time.Hour)
+		require.NoError(t, ks.Rotate(ctx))
+		assert.Equal(t, []string{first}, keyIDs(t, ks))
+
+		// Published ahead, but not signing yet.
+		c.Advance(time.Hour)
+		require.NoError(t, ks.Rotate(ctx))
+		ids := keyIDs(t, ks)
+		require.Len(t, ids, 2)
+		assert.Equal(t, first, ids[0])
+		second := ids[1]
+		assert.Equal(t, first, signingKeyID(t, ks))
+
+		// A second rotation in the same window does not add another key.
+		require.NoError(t, ks.Rotate(ctx))
+		assert.Len(t, keyIDs(t, ks), 2)
+
+		// The next key takes over; the old one stays published.
+		c.Advance(time.Hour)
+		assert.Equal(t, second, signingKeyID(t, ks))
+		assert.Equal(t, []string{second, first}, keyIDs(t, ks))
+
+		// The old key is dropped after the grace period.
+		c.Advance(2 * time.Hour)
+		assert.Equal(t, []string{second}, keyIDs(t, ks))
+		require.NoError(t, ks.Rotate(ctx))
+		stored, err = store.LoadKeys(ctx)
+		require.NoError(t, err)
+		require.Len(t, stored, 1)
+		assert.Equal(t, second, stored[0].Key.ID())
+	})
+
+	t.Run("late_rotation_extends_current_key", func(t *testing.T) {
+		ks, c := newRotatingKeySet(t, NewMemoryKeyStore())
+		require.NoError(t, ks.Rotate(ctx))
+		first := signingKeyID(t, ks)
+
+		// Rotation missed the publish-ahead window by 30 minutes.
+		c.Advance(23*time.Hour + 30*time.Minute)
+		require.NoError(t, ks.Rotate(ctx))
+
+		records := ks.Records()
+		require.Len(t, records, 2)
+		assert.Equal(t, c.Now().Add(time.Hour), records[0].RetiresAt)
+		assert.Equal(t, c.Now().Add(time.Hour), records[1].ActivatesAt)
+
+		c.Advance(59 * time.Minute)
+		assert.Equal(t, first, signingKeyID(t, ks))
+		c.Advance(time.Minute)
+		assert.Equal(t, records[1].Key.ID(), signingKeyID(t, ks))
+	})
+
+	t.Run("missed_rotation_signs_with_new_key_immediately", func(t *testing.T) {
+		ks, c := newRotatingKeySet(t, NewMemoryKeyStore())
+		require.NoError(t, ks.Rotate(ctx))
+		first := signingKeyID(t, ks)
+
+		c.Advance(25 * time.Hour)
+		_, err := ks.SigningKey(ctx)
+		assert.ErrorIs(t, err, ErrNoSigningKey)
+
+		require.NoError(t, ks.Rotate(ctx))
+		second := signingKeyID(t, ks)
+		assert.NotEqual(t, first, second)
+		assert.Equal(t, []string{second, first}, keyIDs(t, ks))
+	})
+
+	t.Run("keys_survive_restart", func(t *testing.T) {
+		store := NewMemoryKeyStore()
+		ks, _ := newRotatingKeySet(t, store)
+		require.NoError(t, ks.Rotate(ctx))
+
+		restarted, _ := newRotatingKeySet(t, store)
+		require.NoError(t, restarted.Rotate(ctx))
+		assert.Equal(t, signingKeyID(t, ks), signingKeyID(t, restarted))
+		assert.Len(t, restarted.Records(), 1)
+	})
+
+	t.Run("load_error", func(t *testing.T) {
+		ks, _ := newRotatingKeySet(t, &failingKeyStore{loadErr: errors.New("load failed")})
+		assert.EqualError(t, ks.Rotate(ctx), "load failed")
+		assert.EqualError(t, ks.Load(ctx), "load failed")
+	})
+
+	t.Run("save_error", func(t *testing.T) {
+		ks, _ := newRotatingKeySet(t, &failingKeyStore{saveErr: errors.New("save failed")})
+		assert.EqualError(t, ks.Rotate(ctx), "save failed")
+
+		// Unsaved keys are not used.
+		_, err := ks.SigningKey(ctx)
+		assert.ErrorIs(t, err, ErrNoSigningKey)
+	})
+
+	t.Run("generator_error", func(t *testing.T) {
+		ks, _ := newRotatingKeySet(t, NewMemoryKeyStore())
+		ks.SetKeyGenerator(func(method jwt.SigningMethod) (*Key, error) {
+			return nil, ErrInvalidSigningMethod
+		})
+		assert.ErrorIs(t, ks.Rotate(ctx), ErrInvalidSigningMethod)
+	})
+}
+
+func TestRotatingKeySet_Load(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryKeyStore()
+	leader, c := newRotatingKeySet(t, store)
+	follower, _ := newRotatingKeySet(t, store)
+	follower.now = c.Now
+
+	require.NoError(t, leader.Rotate(ctx))
+	require.NoError(t, follower.Load(ctx))
+	assert.Equal(t, signingKeyID(t, leader), signingKeyID(t, follower))
+
+	c.Advance(23 * time.Hour)
+	require.NoError(t, leader.Rotate(ctx))
+	assert.Len(t, keyIDs(t, follower), 1)
+	require.NoError(t, follower.Load(ctx))
+	assert.Equal(t, keyIDs(t, leader), keyIDs(t, follower))
+}
+
+func TestRotatingKeySet_Run(t *testing.T) {
+	t.Run("rotates_in_background", func(t *testing.T) {
+		ks, c := newRotatingKeySet(t, NewMemoryKeyStore())
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		require.NoError(t, ks.Run(ctx, time.Millisecond))
+		assert.Len(t, keyIDs(t, ks), 1)
+
+		c.Advance(23 * time.Hour)
+		assert.Eventually(t, func() bool {
+			return len(ks.Records()) == 2
+		}, time.Second, time.Millisecond)
+	})
+
+	t.Run("first_rotation_error", func(t *testing.T) {
+		ks, _ := newRotatingKeySet(t, &failingKeyStore{loadErr: errors.New("load failed")})
+		assert.EqualError(t, ks.Run(context.Background(), time.Millisecond), "load failed")
+	})
+
+	t.Run("later_errors_are_handled", func(t *testing.T) {
+		store := &switchingKeyStore{KeyStore: NewMemoryKeyStore()}
+		ks, _ := newRotatingKeySet(t, store)
+		errs := make(chan error, 1)
+		ks.SetErrorHandler(func(err error) {
+			select {
+			case errs <- err:
+			default:
+			}
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		require.NoError(t, ks.Run(ctx, time.Millisecond))
+		require.NotEmpty(t, keyIDs(t, ks))
+
+		store.fail()
+		select {
+		case err := <-errs:
+			assert.EqualError(t, err, "store unavailable")
+		case <-time.After(time.Second):
+			t.Fatal("error handler not called")
+		}
+
+		// The loaded keys stay in use.
+		assert.NotEmpty(t, signingKeyID(t, ks))
+	})
+}
+
+// switchingKeyStore fails every load once fail has been called.
+type switchingKeyStore struct {
+	KeyStore
+	lock    sync.Mutex
+	failing bool
+}
+
+func (s *switchingKeyStore) fail() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.failing = true
+}
+
+func (s *switchingKeyStore) LoadKeys(ctx context.Context) ([]*KeyRecord, error) {
+	s.lock.Lock()
+	failing := s.failing
+	s.lock.Unlock()
+
+	if failing {
+		return nil, errors.New("store unavailable")
+	}
+
+	return s.KeyStore.LoadKeys(ctx)
+}
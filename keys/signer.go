@@ -0,0 +1,58 @@
+package keys
+
+import (
+	"crypto"
+	"crypto/rand"
+	"errors"
+	"io"
+)
+
+// ErrNilSignFunc is returned by ExternalSigner.Sign when no SignFunc is set.
+var ErrNilSignFunc = errors.New("sign function is nil")
+
+// SignFunc signs digest with a private key held outside the process. digest
+// is the hash of the JWT signing input computed with opts.HashFunc(), or the
+// input itself for Ed25519 (opts.HashFunc() == 0). opts is an
+// *rsa.PSSOptions for PS* algorithms. The signature is returned in the
+// crypto.Signer format: PKCS #1 v1.5 or PSS for RSA, ASN.1 DER for ECDSA, and
+// raw for Ed25519, which is what KMS and HSM APIs return.
+type SignFunc func(digest []byte, opts crypto.SignerOpts) ([]byte, error)
+
+// ExternalSigner is a crypto.Signer whose private key never enters the
+// process: Sign delegates to a SignFunc, typically the sign call of a KMS or
+// HSM client. Pass it to NewKey, or to SetSigner of the token generators, like
+// any other private key.
+type ExternalSigner struct {
+	public crypto.PublicKey
+	sign   SignFunc
+}
+
+// NewExternalSigner returns an ExternalSigner for the key pair whose public
+// key is public and whose signatures are produced by sign.
+func NewExternalSigner(public crypto.PublicKey, sign SignFunc) *ExternalSigner {
+	return &ExternalSigner{public: public, sign: sign}
+}
+
+// NewLocalSigner returns an ExternalSigner backed by an in-memory private key:
+// a software stand-in for a KMS in tests and development. Tokens are signed
+// through the same code path as with a remote key service.
+func NewLocalSigner(key crypto.Signer) *ExternalSigner {
+	return NewExternalSigner(key.Public(), func(digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+		return key.Sign(rand.Reader, digest, opts)
+	})
+}
+
+// Public returns the public key.
+func (s *ExternalSigner) Public() crypto.PublicKey {
+	return s.public
+}
+
+// Sign signs digest with the SignFunc. rand is ignored; the key service uses
+// its own source of randomness.
+func (s *ExternalSigner) Sign(_ io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	if s.sign == nil {
+		return nil, ErrNilSignFunc
+	}
+
+	return s.sign(digest, opts)
+}
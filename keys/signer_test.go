@@ -0,0 +1,69 @@
+package keys
+
+import (
+	"crypto"
+	"errors"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tniah/authlib/utils"
+)
+
+func TestExternalSigner(t *testing.T) {
+	t.Run("local_signer_signs_tokens", func(t *testing.T) {
+		priv := newECKey(t)
+		signer := NewLocalSigner(priv)
+		assert.Equal(t, priv.Public(), signer.Public())
+
+		key, err := NewKey(signer, jwt.SigningMethodES256)
+		require.NoError(t, err)
+
+		// The kid is derived from the public key alone.
+		plain, err := NewKey(priv, jwt.SigningMethodES256)
+		require.NoError(t, err)
+		assert.Equal(t, plain.ID(), key.ID())
+
+		tokenStr, err := key.JWTToken().Generate(utils.JWTClaim{"sub": "user-1"}, utils.JWTHeader{})
+		require.NoError(t, err)
+
+		parsed, err := jwt.Parse(tokenStr, func(*jwt.Token) (interface{}, error) {
+			return &priv.PublicKey, nil
+		}, jwt.WithValidMethods([]string{"ES256"}))
+		require.NoError(t, err)
+		assert.Equal(t, key.ID(), parsed.Header["kid"])
+	})
+
+	t.Run("sign_func_receives_digest", func(t *testing.T) {
+		priv := newECKey(t)
+		var got crypto.SignerOpts
+		signer := NewExternalSigner(priv.Public(), func(digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+			got = opts
+			assert.Len(t, digest, 32)
+			return NewLocalSigner(priv).Sign(nil, digest, opts)
+		})
+
+		key, err := NewKey(signer, jwt.SigningMethodES256)
+		require.NoError(t, err)
+		_, err = key.JWTToken().Generate(utils.JWTClaim{}, utils.JWTHeader{})
+		require.NoError(t, err)
+		assert.Equal(t, crypto.SHA256, got.HashFunc())
+	})
+
+	t.Run("sign_func_error", func(t *testing.T) {
+		signer := NewExternalSigner(newECKey(t).Public(), func([]byte, crypto.SignerOpts) ([]byte, error) {
+			return nil, errors.New("access denied")
+		})
+
+		key, err := NewKey(signer, jwt.SigningMethodES256)
+		require.NoError(t, err)
+		_, err = key.JWTToken().Generate(utils.JWTClaim{}, utils.JWTHeader{})
+		assert.ErrorContains(t, err, "access denied")
+	})
+
+	t.Run("nil_sign_func", func(t *testing.T) {
+		_, err := NewExternalSigner(newECKey(t).Public(), nil).Sign(nil, nil, crypto.SHA256)
+		assert.ErrorIs(t, err, ErrNilSignFunc)
+	})
+}
@@ -0,0 +1,208 @@
+package keys
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// KeyRecord is a key together with its lifecycle: it is published from its
+// creation, signs from ActivatesAt until RetiresAt, and stays published until
+// ExpiresAt so that the tokens it signed can still be verified.
+type KeyRecord struct {
+	Key         *Key
+	CreatedAt   time.Time
+	ActivatesAt time.Time
+	RetiresAt   time.Time
+	ExpiresAt   time.Time
+}
+
+// IsActive reports whether the key signs new tokens at now.
+func (r *KeyRecord) IsActive(now time.Time) bool {
+	return !now.Before(r.ActivatesAt) && now.Before(r.RetiresAt)
+}
+
+// IsExpired reports whether the key is no longer published at now.
+func (r *KeyRecord) IsExpired(now time.Time) bool {
+	return !now.Before(r.ExpiresAt)
+}
+
+// KeyStore persists the keys of a RotatingKeySet, so that they survive
+// restarts and can be shared by several server instances.
+type KeyStore interface {
+	// LoadKeys returns every stored key. Return an empty slice when there is
+	// none yet.
+	LoadKeys(ctx context.Context) ([]*KeyRecord, error)
+
+	// SaveKeys replaces the stored keys with records.
+	SaveKeys(ctx context.Context, records []*KeyRecord) error
+}
+
+// MemoryKeyStore is an in-process KeyStore. Keys are lost on restart, so
+// every start publishes a new key; use FileKeyStore or a shared store when
+// tokens must outlive the process.
+type MemoryKeyStore struct {
+	lock    sync.Mutex
+	records []*KeyRecord
+}
+
+// NewMemoryKeyStore returns an empty MemoryKeyStore.
+func NewMemoryKeyStore() *MemoryKeyStore {
+	return &MemoryKeyStore{}
+}
+
+// LoadKeys returns a copy of the stored keys.
+func (s *MemoryKeyStore) LoadKeys(_ context.Context) ([]*KeyRecord, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return copyRecords(s.records), nil
+}
+
+// SaveKeys replaces the stored keys with a copy of records.
+func (s *MemoryKeyStore) SaveKeys(_ context.Context, records []*KeyRecord) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.records = copyRecords(records)
+	return nil
+}
+
+// FileKeyStore is a KeyStore keeping the keys in a JSON file, private keys
+// PEM-encoded in PKCS #8. The file is written atomically with mode 0600; it
+// holds private keys, so protect it like any other secret.
+type FileKeyStore struct {
+	lock sync.Mutex
+	path string
+}
+
+// NewFileKeyStore returns a FileKeyStore reading and writing the file at path.
+// The file is created on the first save.
+func NewFileKeyStore(path string) *FileKeyStore {
+	return &FileKeyStore{path: path}
+}
+
+type fileKeys struct {
+	Keys []fileKey `json:"keys"`
+}
+
+type fileKey struct {
+	KeyID       string    `json:"kid"`
+	Algorithm   string    `json:"alg"`
+	PrivateKey  string    `json:"private_key"`
+	CreatedAt   time.Time `json:"created_at"`
+	ActivatesAt time.Time `json:"activates_at"`
+	RetiresAt   time.Time `json:"retires_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// LoadKeys reads the keys from the file. A missing file holds no keys.
+func (s *FileKeyStore) LoadKeys(_ context.Context) ([]*KeyRecord, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return []*KeyRecord{}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var f fileKeys
+	if err = json.Unmarshal(data, &f); err != nil {
+		return nil, err
+	}
+
+	records := make([]*KeyRecord, 0, len(f.Keys))
+	for _, k := range f.Keys {
+		method := jwt.GetSigningMethod(k.Algorithm)
+		if method == nil {
+			return nil, ErrInvalidSigningMethod
+		}
+
+		key, err := ParsePrivateKeyPEM([]byte(k.PrivateKey), method, k.KeyID)
+		if err != nil {
+			return nil, err
+		}
+
+		records = append(records, &KeyRecord{
+			Key:         key,
+			CreatedAt:   k.CreatedAt,
+			ActivatesAt: k.ActivatesAt,
+			RetiresAt:   k.RetiresAt,
+			ExpiresAt:   k.ExpiresAt,
+		})
+	}
+
+	return records, nil
+}
+
+// SaveKeys writes records to a temporary file and renames it over the file,
+// so that a crash never leaves a partially written key file behind.
+func (s *FileKeyStore) SaveKeys(_ context.Context, records []*KeyRecord) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	f := fileKeys{Keys: make([]fileKey, 0, len(records))}
+	for _, r := range records {
+		der, err := x509.MarshalPKCS8PrivateKey(r.Key.Signer())
+		if err != nil {
+			return err
+		}
+
+		f.Keys = append(f.Keys, fileKey{
+			KeyID:       r.Key.ID(),
+			Algorithm:   r.Key.Method().Alg(),
+			PrivateKey:  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+			CreatedAt:   r.CreatedAt,
+			ActivatesAt: r.ActivatesAt,
+			RetiresAt:   r.RetiresAt,
+			ExpiresAt:   r.ExpiresAt,
+		})
+	}
+
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), s.path)
+}
+
+// copyRecords returns a copy of records that the caller may modify. Keys are
+// immutable and shared.
+func copyRecords(records []*KeyRecord) []*KeyRecord {
+	out := make([]*KeyRecord, 0, len(records))
+	for _, r := range records {
+		c := *r
+		out = append(out, &c)
+	}
+
+	return out
+}
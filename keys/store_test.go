@@ -0,0 +1,126 @@
+package keys
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRecord(t *testing.T, key *Key, activatesAt time.Time) *KeyRecord {
+	t.Helper()
+	return &KeyRecord{
+		Key:         key,
+		CreatedAt:   activatesAt.Add(-time.Hour),
+		ActivatesAt: activatesAt,
+		RetiresAt:   activatesAt.Add(24 * time.Hour),
+		ExpiresAt:   activatesAt.Add(48 * time.Hour),
+	}
+}
+
+func TestKeyRecord(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	r := newRecord(t, newKey(t), now)
+
+	assert.False(t, r.IsActive(now.Add(-time.Second)))
+	assert.True(t, r.IsActive(now))
+	assert.False(t, r.IsActive(r.RetiresAt))
+	assert.False(t, r.IsExpired(r.RetiresAt))
+	assert.True(t, r.IsExpired(r.ExpiresAt))
+}
+
+func TestMemoryKeyStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryKeyStore()
+
+	records, err := store.LoadKeys(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, records)
+
+	r := newRecord(t, newKey(t), time.Now())
+	require.NoError(t, store.SaveKeys(ctx, []*KeyRecord{r}))
+
+	// Changes to saved or loaded records do not leak into the store.
+	r.RetiresAt = time.Time{}
+	records, err = store.LoadKeys(ctx)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.False(t, records[0].RetiresAt.IsZero())
+
+	records[0].ExpiresAt = time.Time{}
+	records, err = store.LoadKeys(ctx)
+	require.NoError(t, err)
+	assert.False(t, records[0].ExpiresAt.IsZero())
+}
+
+func TestFileKeyStore(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("missing_file_holds_no_keys", func(t *testing.T) {
+		store := NewFileKeyStore(filepath.Join(t.TempDir(), "keys.json"))
+		records, err := store.LoadKeys(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, records)
+	})
+
+	t.Run("round_trip", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "keys.json")
+		store := NewFileKeyStore(path)
+
+		now := time.Now().UTC().Truncate(time.Second)
+		var saved []*KeyRecord
+		for _, method := range []jwt.SigningMethod{jwt.SigningMethodRS256, jwt.SigningMethodES384, jwt.SigningMethodEdDSA} {
+			key, err := GenerateKey(method)
+			require.NoError(t, err)
+			saved = append(saved, newRecord(t, key, now))
+		}
+
+		require.NoError(t, store.SaveKeys(ctx, saved))
+
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+		loaded, err := NewFileKeyStore(path).LoadKeys(ctx)
+		require.NoError(t, err)
+		require.Len(t, loaded, len(saved))
+		for i, r := range loaded {
+			assert.Equal(t, saved[i].Key.JWK(), r.Key.JWK())
+			assert.Equal(t, saved[i].Key.Method(), r.Key.Method())
+			assert.True(t, saved[i].ActivatesAt.Equal(r.ActivatesAt))
+			assert.True(t, saved[i].RetiresAt.Equal(r.RetiresAt))
+			assert.True(t, saved[i].ExpiresAt.Equal(r.ExpiresAt))
+		}
+
+		// No temporary file is left behind.
+		entries, err := os.ReadDir(filepath.Dir(path))
+		require.NoError(t, err)
+		assert.Len(t, entries, 1)
+	})
+
+	t.Run("invalid_file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "keys.json")
+		require.NoError(t, os.WriteFile(path, []byte("not json"), 0o600))
+
+		_, err := NewFileKeyStore(path).LoadKeys(ctx)
+		assert.Error(t, err)
+	})
+
+	t.Run("unknown_algorithm", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "keys.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"keys":[{"kid":"k","alg":"XX1"}]}`), 0o600))
+
+		_, err := NewFileKeyStore(path).LoadKeys(ctx)
+		assert.ErrorIs(t, err, ErrInvalidSigningMethod)
+	})
+
+	t.Run("missing_directory", func(t *testing.T) {
+		store := NewFileKeyStore(filepath.Join(t.TempDir(), "missing", "keys.json"))
+		assert.Error(t, store.SaveKeys(ctx, nil))
+	})
+}
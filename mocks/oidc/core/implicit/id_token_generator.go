@@ -0,0 +1,95 @@
+// Code generated by mockery v2.53.6. DO NOT EDIT.
+
+package implicit
+
+import (
+	context "context"
+
+	authorizationcode "github.com/tniah/authlib/oidc/core/authorization_code"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockIDTokenGenerator is an autogenerated mock type for the IDTokenGenerator type
+type MockIDTokenGenerator struct {
+	mock.Mock
+}
+
+type MockIDTokenGenerator_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockIDTokenGenerator) EXPECT() *MockIDTokenGenerator_Expecter {
+	return &MockIDTokenGenerator_Expecter{mock: &_m.Mock}
+}
+
+// GenerateIDToken provides a mock function with given fields: ctx, req
+func (_m *MockIDTokenGenerator) GenerateIDToken(ctx context.Context, req *authorizationcode.IDTokenRequest) (string, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GenerateIDToken")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *authorizationcode.IDTokenRequest) (string, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *authorizationcode.IDTokenRequest) string); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *authorizationcode.IDTokenRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockIDTokenGenerator_GenerateIDToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GenerateIDToken'
+type MockIDTokenGenerator_GenerateIDToken_Call struct {
+	*mock.Call
+}
+
+// GenerateIDToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - req *authorizationcode.IDTokenRequest
+func (_e *MockIDTokenGenerator_Expecter) GenerateIDToken(ctx interface{}, req interface{}) *MockIDTokenGenerator_GenerateIDToken_Call {
+	return &MockIDTokenGenerator_GenerateIDToken_Call{Call: _e.mock.On("GenerateIDToken", ctx, req)}
+}
+
+func (_c *MockIDTokenGenerator_GenerateIDToken_Call) Run(run func(ctx context.Context, req *authorizationcode.IDTokenRequest)) *MockIDTokenGenerator_GenerateIDToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*authorizationcode.IDTokenRequest))
+	})
+	return _c
+}
+
+func (_c *MockIDTokenGenerator_GenerateIDToken_Call) Return(_a0 string, _a1 error) *MockIDTokenGenerator_GenerateIDToken_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockIDTokenGenerator_GenerateIDToken_Call) RunAndReturn(run func(context.Context, *authorizationcode.IDTokenRequest) (string, error)) *MockIDTokenGenerator_GenerateIDToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockIDTokenGenerator creates a new instance of MockIDTokenGenerator. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockIDTokenGenerator(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockIDTokenGenerator {
+	mock := &MockIDTokenGenerator{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
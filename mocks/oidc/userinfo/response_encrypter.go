@@ -0,0 +1,97 @@
+// Code generated by mockery v2.53.6. DO NOT EDIT.
+
+package userinfo
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	models "github.com/tniah/authlib/models"
+)
+
+// MockResponseEncrypter is an autogenerated mock type for the ResponseEncrypter type
+type MockResponseEncrypter struct {
+	mock.Mock
+}
+
+type MockResponseEncrypter_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockResponseEncrypter) EXPECT() *MockResponseEncrypter_Expecter {
+	return &MockResponseEncrypter_Expecter{mock: &_m.Mock}
+}
+
+// Encrypt provides a mock function with given fields: ctx, client, payload, alg, enc
+func (_m *MockResponseEncrypter) Encrypt(ctx context.Context, client models.Client, payload []byte, alg string, enc string) (string, error) {
+	ret := _m.Called(ctx, client, payload, alg, enc)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Encrypt")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.Client, []byte, string, string) (string, error)); ok {
+		return rf(ctx, client, payload, alg, enc)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.Client, []byte, string, string) string); ok {
+		r0 = rf(ctx, client, payload, alg, enc)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.Client, []byte, string, string) error); ok {
+		r1 = rf(ctx, client, payload, alg, enc)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockResponseEncrypter_Encrypt_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Encrypt'
+type MockResponseEncrypter_Encrypt_Call struct {
+	*mock.Call
+}
+
+// Encrypt is a helper method to define mock.On call
+//   - ctx context.Context
+//   - client models.Client
+//   - payload []byte
+//   - alg string
+//   - enc string
+func (_e *MockResponseEncrypter_Expecter) Encrypt(ctx interface{}, client interface{}, payload interface{}, alg interface{}, enc interface{}) *MockResponseEncrypter_Encrypt_Call {
+	return &MockResponseEncrypter_Encrypt_Call{Call: _e.mock.On("Encrypt", ctx, client, payload, alg, enc)}
+}
+
+func (_c *MockResponseEncrypter_Encrypt_Call) Run(run func(ctx context.Context, client models.Client, payload []byte, alg string, enc string)) *MockResponseEncrypter_Encrypt_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.Client), args[2].([]byte), args[3].(string), args[4].(string))
+	})
+	return _c
+}
+
+func (_c *MockResponseEncrypter_Encrypt_Call) Return(_a0 string, _a1 error) *MockResponseEncrypter_Encrypt_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockResponseEncrypter_Encrypt_Call) RunAndReturn(run func(context.Context, models.Client, []byte, string, string) (string, error)) *MockResponseEncrypter_Encrypt_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockResponseEncrypter creates a new instance of MockResponseEncrypter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockResponseEncrypter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockResponseEncrypter {
+	mock := &MockResponseEncrypter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
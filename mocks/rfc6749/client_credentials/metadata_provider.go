@@ -0,0 +1,68 @@
+// Code generated by mockery v2.53.6. DO NOT EDIT.
+
+package clientcredentials
+
+import (
+	mock "github.com/stretchr/testify/mock"
+	types "github.com/tniah/authlib/types"
+)
+
+// MockMetadataProvider is an autogenerated mock type for the MetadataProvider type
+type MockMetadataProvider struct {
+	mock.Mock
+}
+
+type MockMetadataProvider_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockMetadataProvider) EXPECT() *MockMetadataProvider_Expecter {
+	return &MockMetadataProvider_Expecter{mock: &_m.Mock}
+}
+
+// ProvideMetadata provides a mock function with given fields: md
+func (_m *MockMetadataProvider) ProvideMetadata(md types.Metadata) {
+	_m.Called(md)
+}
+
+// MockMetadataProvider_ProvideMetadata_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ProvideMetadata'
+type MockMetadataProvider_ProvideMetadata_Call struct {
+	*mock.Call
+}
+
+// ProvideMetadata is a helper method to define mock.On call
+//   - md types.Metadata
+func (_e *MockMetadataProvider_Expecter) ProvideMetadata(md interface{}) *MockMetadataProvider_ProvideMetadata_Call {
+	return &MockMetadataProvider_ProvideMetadata_Call{Call: _e.mock.On("ProvideMetadata", md)}
+}
+
+func (_c *MockMetadataProvider_ProvideMetadata_Call) Run(run func(md types.Metadata)) *MockMetadataProvider_ProvideMetadata_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(types.Metadata))
+	})
+	return _c
+}
+
+func (_c *MockMetadataProvider_ProvideMetadata_Call) Return() *MockMetadataProvider_ProvideMetadata_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockMetadataProvider_ProvideMetadata_Call) RunAndReturn(run func(types.Metadata)) *MockMetadataProvider_ProvideMetadata_Call {
+	_c.Run(run)
+	return _c
+}
+
+// NewMockMetadataProvider creates a new instance of MockMetadataProvider. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockMetadataProvider(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockMetadataProvider {
+	mock := &MockMetadataProvider{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,85 @@
+// Code generated by mockery v2.53.6. DO NOT EDIT.
+
+package rfc8414
+
+import (
+	http "net/http"
+
+	mock "github.com/stretchr/testify/mock"
+
+	types "github.com/tniah/authlib/types"
+)
+
+// MockMetadataProcessor is an autogenerated mock type for the MetadataProcessor type
+type MockMetadataProcessor struct {
+	mock.Mock
+}
+
+type MockMetadataProcessor_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockMetadataProcessor) EXPECT() *MockMetadataProcessor_Expecter {
+	return &MockMetadataProcessor_Expecter{mock: &_m.Mock}
+}
+
+// ProcessMetadata provides a mock function with given fields: r, md
+func (_m *MockMetadataProcessor) ProcessMetadata(r *http.Request, md types.Metadata) error {
+	ret := _m.Called(r, md)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ProcessMetadata")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*http.Request, types.Metadata) error); ok {
+		r0 = rf(r, md)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockMetadataProcessor_ProcessMetadata_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ProcessMetadata'
+type MockMetadataProcessor_ProcessMetadata_Call struct {
+	*mock.Call
+}
+
+// ProcessMetadata is a helper method to define mock.On call
+//   - r *http.Request
+//   - md types.Metadata
+func (_e *MockMetadataProcessor_Expecter) ProcessMetadata(r interface{}, md interface{}) *MockMetadataProcessor_ProcessMetadata_Call {
+	return &MockMetadataProcessor_ProcessMetadata_Call{Call: _e.mock.On("ProcessMetadata", r, md)}
+}
+
+func (_c *MockMetadataProcessor_ProcessMetadata_Call) Run(run func(r *http.Request, md types.Metadata)) *MockMetadataProcessor_ProcessMetadata_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*http.Request), args[1].(types.Metadata))
+	})
+	return _c
+}
+
+func (_c *MockMetadataProcessor_ProcessMetadata_Call) Return(_a0 error) *MockMetadataProcessor_ProcessMetadata_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockMetadataProcessor_ProcessMetadata_Call) RunAndReturn(run func(*http.Request, types.Metadata) error) *MockMetadataProcessor_ProcessMetadata_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockMetadataProcessor creates a new instance of MockMetadataProcessor. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockMetadataProcessor(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockMetadataProcessor {
+	mock := &MockMetadataProcessor{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
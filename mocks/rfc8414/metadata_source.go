@@ -0,0 +1,83 @@
+// Code generated by mockery v2.53.6. DO NOT EDIT.
+
+package rfc8414
+
+import (
+	mock "github.com/stretchr/testify/mock"
+
+	types "github.com/tniah/authlib/types"
+)
+
+// MockMetadataSource is an autogenerated mock type for the MetadataSource type
+type MockMetadataSource struct {
+	mock.Mock
+}
+
+type MockMetadataSource_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockMetadataSource) EXPECT() *MockMetadataSource_Expecter {
+	return &MockMetadataSource_Expecter{mock: &_m.Mock}
+}
+
+// Metadata provides a mock function with no fields
+func (_m *MockMetadataSource) Metadata() types.Metadata {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Metadata")
+	}
+
+	var r0 types.Metadata
+	if rf, ok := ret.Get(0).(func() types.Metadata); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(types.Metadata)
+		}
+	}
+
+	return r0
+}
+
+// MockMetadataSource_Metadata_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Metadata'
+type MockMetadataSource_Metadata_Call struct {
+	*mock.Call
+}
+
+// Metadata is a helper method to define mock.On call
+func (_e *MockMetadataSource_Expecter) Metadata() *MockMetadataSource_Metadata_Call {
+	return &MockMetadataSource_Metadata_Call{Call: _e.mock.On("Metadata")}
+}
+
+func (_c *MockMetadataSource_Metadata_Call) Run(run func()) *MockMetadataSource_Metadata_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockMetadataSource_Metadata_Call) Return(_a0 types.Metadata) *MockMetadataSource_Metadata_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockMetadataSource_Metadata_Call) RunAndReturn(run func() types.Metadata) *MockMetadataSource_Metadata_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockMetadataSource creates a new instance of MockMetadataSource. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockMetadataSource(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockMetadataSource {
+	mock := &MockMetadataSource{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,117 @@
+// Code generated by mockery v2.53.6. DO NOT EDIT.
+
+package rfc8628
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockAttemptLimiter is an autogenerated mock type for the AttemptLimiter type
+type MockAttemptLimiter struct {
+	mock.Mock
+}
+
+type MockAttemptLimiter_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockAttemptLimiter) EXPECT() *MockAttemptLimiter_Expecter {
+	return &MockAttemptLimiter_Expecter{mock: &_m.Mock}
+}
+
+// Allow provides a mock function with given fields: ctx, key
+func (_m *MockAttemptLimiter) Allow(ctx context.Context, key string) bool {
+	ret := _m.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Allow")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = rf(ctx, key)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// MockAttemptLimiter_Allow_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Allow'
+type MockAttemptLimiter_Allow_Call struct {
+	*mock.Call
+}
+
+// Allow is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+func (_e *MockAttemptLimiter_Expecter) Allow(ctx interface{}, key interface{}) *MockAttemptLimiter_Allow_Call {
+	return &MockAttemptLimiter_Allow_Call{Call: _e.mock.On("Allow", ctx, key)}
+}
+
+func (_c *MockAttemptLimiter_Allow_Call) Run(run func(ctx context.Context, key string)) *MockAttemptLimiter_Allow_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockAttemptLimiter_Allow_Call) Return(_a0 bool) *MockAttemptLimiter_Allow_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockAttemptLimiter_Allow_Call) RunAndReturn(run func(context.Context, string) bool) *MockAttemptLimiter_Allow_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Fail provides a mock function with given fields: ctx, key
+func (_m *MockAttemptLimiter) Fail(ctx context.Context, key string) {
+	_m.Called(ctx, key)
+}
+
+// MockAttemptLimiter_Fail_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Fail'
+type MockAttemptLimiter_Fail_Call struct {
+	*mock.Call
+}
+
+// Fail is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+func (_e *MockAttemptLimiter_Expecter) Fail(ctx interface{}, key interface{}) *MockAttemptLimiter_Fail_Call {
+	return &MockAttemptLimiter_Fail_Call{Call: _e.mock.On("Fail", ctx, key)}
+}
+
+func (_c *MockAttemptLimiter_Fail_Call) Run(run func(ctx context.Context, key string)) *MockAttemptLimiter_Fail_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockAttemptLimiter_Fail_Call) Return() *MockAttemptLimiter_Fail_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockAttemptLimiter_Fail_Call) RunAndReturn(run func(context.Context, string)) *MockAttemptLimiter_Fail_Call {
+	_c.Run(run)
+	return _c
+}
+
+// NewMockAttemptLimiter creates a new instance of MockAttemptLimiter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockAttemptLimiter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockAttemptLimiter {
+	mock := &MockAttemptLimiter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,81 @@
+// Code generated by mockery v2.53.6. DO NOT EDIT.
+
+package rfc8693
+
+import (
+	mock "github.com/stretchr/testify/mock"
+	requests "github.com/tniah/authlib/requests"
+)
+
+// MockExchangePolicy is an autogenerated mock type for the ExchangePolicy type
+type MockExchangePolicy struct {
+	mock.Mock
+}
+
+type MockExchangePolicy_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockExchangePolicy) EXPECT() *MockExchangePolicy_Expecter {
+	return &MockExchangePolicy_Expecter{mock: &_m.Mock}
+}
+
+// CheckExchange provides a mock function with given fields: r
+func (_m *MockExchangePolicy) CheckExchange(r *requests.TokenRequest) error {
+	ret := _m.Called(r)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CheckExchange")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*requests.TokenRequest) error); ok {
+		r0 = rf(r)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockExchangePolicy_CheckExchange_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CheckExchange'
+type MockExchangePolicy_CheckExchange_Call struct {
+	*mock.Call
+}
+
+// CheckExchange is a helper method to define mock.On call
+//   - r *requests.TokenRequest
+func (_e *MockExchangePolicy_Expecter) CheckExchange(r interface{}) *MockExchangePolicy_CheckExchange_Call {
+	return &MockExchangePolicy_CheckExchange_Call{Call: _e.mock.On("CheckExchange", r)}
+}
+
+func (_c *MockExchangePolicy_CheckExchange_Call) Run(run func(r *requests.TokenRequest)) *MockExchangePolicy_CheckExchange_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*requests.TokenRequest))
+	})
+	return _c
+}
+
+func (_c *MockExchangePolicy_CheckExchange_Call) Return(_a0 error) *MockExchangePolicy_CheckExchange_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockExchangePolicy_CheckExchange_Call) RunAndReturn(run func(*requests.TokenRequest) error) *MockExchangePolicy_CheckExchange_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockExchangePolicy creates a new instance of MockExchangePolicy. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockExchangePolicy(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockExchangePolicy {
+	mock := &MockExchangePolicy{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
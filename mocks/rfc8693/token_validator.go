@@ -0,0 +1,100 @@
+// Code generated by mockery v2.53.6. DO NOT EDIT.
+
+package rfc8693
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	requests "github.com/tniah/authlib/requests"
+
+	types "github.com/tniah/authlib/types"
+)
+
+// MockTokenValidator is an autogenerated mock type for the TokenValidator type
+type MockTokenValidator struct {
+	mock.Mock
+}
+
+type MockTokenValidator_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockTokenValidator) EXPECT() *MockTokenValidator_Expecter {
+	return &MockTokenValidator_Expecter{mock: &_m.Mock}
+}
+
+// ValidateToken provides a mock function with given fields: ctx, token, tokenType, r
+func (_m *MockTokenValidator) ValidateToken(ctx context.Context, token string, tokenType types.TokenTypeIdentifier, r *requests.TokenRequest) (*requests.ExchangeToken, error) {
+	ret := _m.Called(ctx, token, tokenType, r)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ValidateToken")
+	}
+
+	var r0 *requests.ExchangeToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, types.TokenTypeIdentifier, *requests.TokenRequest) (*requests.ExchangeToken, error)); ok {
+		return rf(ctx, token, tokenType, r)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, types.TokenTypeIdentifier, *requests.TokenRequest) *requests.ExchangeToken); ok {
+		r0 = rf(ctx, token, tokenType, r)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*requests.ExchangeToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, types.TokenTypeIdentifier, *requests.TokenRequest) error); ok {
+		r1 = rf(ctx, token, tokenType, r)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockTokenValidator_ValidateToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ValidateToken'
+type MockTokenValidator_ValidateToken_Call struct {
+	*mock.Call
+}
+
+// ValidateToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - token string
+//   - tokenType types.TokenTypeIdentifier
+//   - r *requests.TokenRequest
+func (_e *MockTokenValidator_Expecter) ValidateToken(ctx interface{}, token interface{}, tokenType interface{}, r interface{}) *MockTokenValidator_ValidateToken_Call {
+	return &MockTokenValidator_ValidateToken_Call{Call: _e.mock.On("ValidateToken", ctx, token, tokenType, r)}
+}
+
+func (_c *MockTokenValidator_ValidateToken_Call) Run(run func(ctx context.Context, token string, tokenType types.TokenTypeIdentifier, r *requests.TokenRequest)) *MockTokenValidator_ValidateToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(types.TokenTypeIdentifier), args[3].(*requests.TokenRequest))
+	})
+	return _c
+}
+
+func (_c *MockTokenValidator_ValidateToken_Call) Return(_a0 *requests.ExchangeToken, _a1 error) *MockTokenValidator_ValidateToken_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockTokenValidator_ValidateToken_Call) RunAndReturn(run func(context.Context, string, types.TokenTypeIdentifier, *requests.TokenRequest) (*requests.ExchangeToken, error)) *MockTokenValidator_ValidateToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockTokenValidator creates a new instance of MockTokenValidator. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockTokenValidator(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockTokenValidator {
+	mock := &MockTokenValidator{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
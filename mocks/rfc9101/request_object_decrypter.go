@@ -0,0 +1,95 @@
+// Code generated by mockery v2.53.6. DO NOT EDIT.
+
+package rfc9101
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	models "github.com/tniah/authlib/models"
+)
+
+// MockRequestObjectDecrypter is an autogenerated mock type for the RequestObjectDecrypter type
+type MockRequestObjectDecrypter struct {
+	mock.Mock
+}
+
+type MockRequestObjectDecrypter_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockRequestObjectDecrypter) EXPECT() *MockRequestObjectDecrypter_Expecter {
+	return &MockRequestObjectDecrypter_Expecter{mock: &_m.Mock}
+}
+
+// Decrypt provides a mock function with given fields: ctx, client, jwe
+func (_m *MockRequestObjectDecrypter) Decrypt(ctx context.Context, client models.Client, jwe string) (string, error) {
+	ret := _m.Called(ctx, client, jwe)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Decrypt")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.Client, string) (string, error)); ok {
+		return rf(ctx, client, jwe)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.Client, string) string); ok {
+		r0 = rf(ctx, client, jwe)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.Client, string) error); ok {
+		r1 = rf(ctx, client, jwe)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockRequestObjectDecrypter_Decrypt_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Decrypt'
+type MockRequestObjectDecrypter_Decrypt_Call struct {
+	*mock.Call
+}
+
+// Decrypt is a helper method to define mock.On call
+//   - ctx context.Context
+//   - client models.Client
+//   - jwe string
+func (_e *MockRequestObjectDecrypter_Expecter) Decrypt(ctx interface{}, client interface{}, jwe interface{}) *MockRequestObjectDecrypter_Decrypt_Call {
+	return &MockRequestObjectDecrypter_Decrypt_Call{Call: _e.mock.On("Decrypt", ctx, client, jwe)}
+}
+
+func (_c *MockRequestObjectDecrypter_Decrypt_Call) Run(run func(ctx context.Context, client models.Client, jwe string)) *MockRequestObjectDecrypter_Decrypt_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.Client), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockRequestObjectDecrypter_Decrypt_Call) Return(_a0 string, _a1 error) *MockRequestObjectDecrypter_Decrypt_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockRequestObjectDecrypter_Decrypt_Call) RunAndReturn(run func(context.Context, models.Client, string) (string, error)) *MockRequestObjectDecrypter_Decrypt_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockRequestObjectDecrypter creates a new instance of MockRequestObjectDecrypter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockRequestObjectDecrypter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockRequestObjectDecrypter {
+	mock := &MockRequestObjectDecrypter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,93 @@
+// Code generated by mockery v2.53.6. DO NOT EDIT.
+
+package rfc9126
+
+import (
+	mock "github.com/stretchr/testify/mock"
+	requests "github.com/tniah/authlib/requests"
+)
+
+// MockAuthorizationRequestResolver is an autogenerated mock type for the AuthorizationRequestResolver type
+type MockAuthorizationRequestResolver struct {
+	mock.Mock
+}
+
+type MockAuthorizationRequestResolver_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockAuthorizationRequestResolver) EXPECT() *MockAuthorizationRequestResolver_Expecter {
+	return &MockAuthorizationRequestResolver_Expecter{mock: &_m.Mock}
+}
+
+// ResolveAuthorizationRequest provides a mock function with given fields: r
+func (_m *MockAuthorizationRequestResolver) ResolveAuthorizationRequest(r *requests.AuthorizationRequest) (*requests.AuthorizationRequest, error) {
+	ret := _m.Called(r)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResolveAuthorizationRequest")
+	}
+
+	var r0 *requests.AuthorizationRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*requests.AuthorizationRequest) (*requests.AuthorizationRequest, error)); ok {
+		return rf(r)
+	}
+	if rf, ok := ret.Get(0).(func(*requests.AuthorizationRequest) *requests.AuthorizationRequest); ok {
+		r0 = rf(r)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*requests.AuthorizationRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*requests.AuthorizationRequest) error); ok {
+		r1 = rf(r)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAuthorizationRequestResolver_ResolveAuthorizationRequest_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResolveAuthorizationRequest'
+type MockAuthorizationRequestResolver_ResolveAuthorizationRequest_Call struct {
+	*mock.Call
+}
+
+// ResolveAuthorizationRequest is a helper method to define mock.On call
+//   - r *requests.AuthorizationRequest
+func (_e *MockAuthorizationRequestResolver_Expecter) ResolveAuthorizationRequest(r interface{}) *MockAuthorizationRequestResolver_ResolveAuthorizationRequest_Call {
+	return &MockAuthorizationRequestResolver_ResolveAuthorizationRequest_Call{Call: _e.mock.On("ResolveAuthorizationRequest", r)}
+}
+
+func (_c *MockAuthorizationRequestResolver_ResolveAuthorizationRequest_Call) Run(run func(r *requests.AuthorizationRequest)) *MockAuthorizationRequestResolver_ResolveAuthorizationRequest_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*requests.AuthorizationRequest))
+	})
+	return _c
+}
+
+func (_c *MockAuthorizationRequestResolver_ResolveAuthorizationRequest_Call) Return(_a0 *requests.AuthorizationRequest, _a1 error) *MockAuthorizationRequestResolver_ResolveAuthorizationRequest_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAuthorizationRequestResolver_ResolveAuthorizationRequest_Call) RunAndReturn(run func(*requests.AuthorizationRequest) (*requests.AuthorizationRequest, error)) *MockAuthorizationRequestResolver_ResolveAuthorizationRequest_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockAuthorizationRequestResolver creates a new instance of MockAuthorizationRequestResolver. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockAuthorizationRequestResolver(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockAuthorizationRequestResolver {
+	mock := &MockAuthorizationRequestResolver{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
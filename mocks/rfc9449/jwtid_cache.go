@@ -0,0 +1,97 @@
+// Code generated by mockery v2.53.6. DO NOT EDIT.
+
+package rfc9449
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// MockJWTIDCache is an autogenerated mock type for the JWTIDCache type
+type MockJWTIDCache struct {
+	mock.Mock
+}
+
+type MockJWTIDCache_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockJWTIDCache) EXPECT() *MockJWTIDCache_Expecter {
+	return &MockJWTIDCache_Expecter{mock: &_m.Mock}
+}
+
+// Use provides a mock function with given fields: ctx, issuer, jti, expiresAt
+func (_m *MockJWTIDCache) Use(ctx context.Context, issuer string, jti string, expiresAt time.Time) (bool, error) {
+	ret := _m.Called(ctx, issuer, jti, expiresAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Use")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) (bool, error)); ok {
+		return rf(ctx, issuer, jti, expiresAt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) bool); ok {
+		r0 = rf(ctx, issuer, jti, expiresAt)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, time.Time) error); ok {
+		r1 = rf(ctx, issuer, jti, expiresAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockJWTIDCache_Use_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Use'
+type MockJWTIDCache_Use_Call struct {
+	*mock.Call
+}
+
+// Use is a helper method to define mock.On call
+//   - ctx context.Context
+//   - issuer string
+//   - jti string
+//   - expiresAt time.Time
+func (_e *MockJWTIDCache_Expecter) Use(ctx interface{}, issuer interface{}, jti interface{}, expiresAt interface{}) *MockJWTIDCache_Use_Call {
+	return &MockJWTIDCache_Use_Call{Call: _e.mock.On("Use", ctx, issuer, jti, expiresAt)}
+}
+
+func (_c *MockJWTIDCache_Use_Call) Run(run func(ctx context.Context, issuer string, jti string, expiresAt time.Time)) *MockJWTIDCache_Use_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockJWTIDCache_Use_Call) Return(_a0 bool, _a1 error) *MockJWTIDCache_Use_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockJWTIDCache_Use_Call) RunAndReturn(run func(context.Context, string, string, time.Time) (bool, error)) *MockJWTIDCache_Use_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockJWTIDCache creates a new instance of MockJWTIDCache. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockJWTIDCache(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockJWTIDCache {
+	mock := &MockJWTIDCache{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
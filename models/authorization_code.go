@@ -46,6 +46,12 @@ type AuthorizationCode interface {
 	GetState() string
 	SetState(state string)
 
+	// GetClaimsRequest / SetClaimsRequest get and set the OIDC claims request
+	// parameter (OIDC Core §5.5), honoured when the ID token is issued. Nil
+	// when the client did not send one.
+	GetClaimsRequest() *types.ClaimsRequest
+	SetClaimsRequest(claims *types.ClaimsRequest)
+
 	// GetAuthTime / SetAuthTime get and set the time the user authenticated.
 	GetAuthTime() time.Time
 	SetAuthTime(time.Time)
@@ -47,3 +47,12 @@ type Client interface {
 	// They authenticate at the token endpoint using auth method "none".
 	IsPublic() bool
 }
+
+// ResponseModeClient is implemented by clients that restrict the response_mode
+// values they may use at the authorization endpoint. Clients that do not
+// implement it may use every response mode the server supports.
+type ResponseModeClient interface {
+	// CheckResponseMode reports whether this client is permitted to use the
+	// given response mode.
+	CheckResponseMode(mode types.ResponseMode) bool
+}
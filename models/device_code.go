@@ -0,0 +1,58 @@
+package models
+
+import (
+	"time"
+
+	"github.com/tniah/authlib/types"
+)
+
+// DeviceCode represents a device authorization request issued at the device
+// authorization endpoint (RFC 8628 §3.2). It pairs the device_code polled by
+// the device with the user_code typed in by the end-user, and tracks the
+// user's decision and the device's polling.
+type DeviceCode interface {
+	// GetDeviceCode / SetDeviceCode get and set the device verification code
+	// polled by the device at the token endpoint.
+	GetDeviceCode() string
+	SetDeviceCode(code string)
+
+	// GetUserCode / SetUserCode get and set the end-user verification code.
+	// It is stored normalized: upper case, without separators.
+	GetUserCode() string
+	SetUserCode(code string)
+
+	// GetClientID / SetClientID get and set the client the code was issued to.
+	GetClientID() string
+	SetClientID(clientID string)
+
+	// GetUserID / SetUserID get and set the user who approved or denied the
+	// request. Empty while the request is pending.
+	GetUserID() string
+	SetUserID(userID string)
+
+	// GetScopes / SetScopes get and set the requested scopes.
+	GetScopes() types.Scopes
+	SetScopes(scopes types.Scopes)
+
+	// GetStatus / SetStatus get and set the end-user's decision.
+	GetStatus() types.DeviceCodeStatus
+	SetStatus(status types.DeviceCodeStatus)
+
+	// GetIssuedAt / SetIssuedAt get and set the issuance time.
+	GetIssuedAt() time.Time
+	SetIssuedAt(issuedAt time.Time)
+
+	// GetExpiresIn / SetExpiresIn get and set the lifetime of both codes.
+	GetExpiresIn() time.Duration
+	SetExpiresIn(expiresIn time.Duration)
+
+	// GetInterval / SetInterval get and set the minimum time the device must
+	// wait between polling requests (RFC 8628 §3.5).
+	GetInterval() time.Duration
+	SetInterval(interval time.Duration)
+
+	// GetLastPolledAt / SetLastPolledAt get and set the time of the device's
+	// last polling request. Zero until the first poll.
+	GetLastPolledAt() time.Time
+	SetLastPolledAt(lastPolledAt time.Time)
+}
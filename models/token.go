@@ -54,6 +54,18 @@ type Token interface {
 	// access tokens. May be empty for opaque tokens.
 	GetJwtID() string
 	SetJwtID(id string)
+
+	// GetFamilyID / SetFamilyID get and set the identifier shared by every
+	// token descended from the same original grant through refresh token
+	// rotation. Used to revoke the whole family when a rotated refresh token
+	// is replayed. Empty until the token is first refreshed.
+	GetFamilyID() string
+	SetFamilyID(id string)
+
+	// GetRotatedAt / SetRotatedAt get and set the time the refresh token was
+	// exchanged for a new one. Zero while the refresh token is still current.
+	GetRotatedAt() time.Time
+	SetRotatedAt(rotatedAt time.Time)
 }
 
 // ExtendableToken extends Token with an arbitrary key-value map for storing
@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 // User represents an authenticated end-user. The interface is intentionally
 // minimal — only a stable unique identifier is required — so that any user
 // model (database row, JWT claims, session struct) can satisfy it without
@@ -10,3 +12,32 @@ type User interface {
 	// and stored on authorization codes and tokens.
 	GetUserID() string
 }
+
+// ClaimsProvider is implemented by users that expose claims about themselves,
+// such as the standard claims of OIDC Core §5.1 (name, email, address,
+// phone_number, ...). Return every claim the user has: the claims released to
+// a client are selected from them by the scopes it was granted, and by the
+// claims it requested that some scope maps to.
+type ClaimsProvider interface {
+	// GetClaims returns the claims of the user keyed by claim name. The
+	// address claim is a JSON object (OIDC Core §5.1.1).
+	GetClaims() map[string]interface{}
+}
+
+// AuthenticationContext is implemented by users that carry how the end-user
+// authenticated in the current session. The OpenID Connect flows use it to
+// enforce max_age and acr requirements of the authorization request, and to
+// issue the auth_time, acr and amr claims of the ID Token (OIDC Core §2).
+type AuthenticationContext interface {
+	// GetAuthTime returns the time the end-user last actively authenticated.
+	// Zero when the end-user has not authenticated.
+	GetAuthTime() time.Time
+
+	// GetACR returns the Authentication Context Class Reference satisfied by
+	// the authentication, or an empty string when unknown.
+	GetACR() string
+
+	// GetAMR returns the identifiers of the authentication methods used, such
+	// as "pwd" and "otp" (RFC 8176).
+	GetAMR() []string
+}
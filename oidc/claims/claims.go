@@ -0,0 +1,168 @@
+// Package claims selects the claims about an end-user that a client may
+// receive, from the scopes it was granted (OIDC Core §5.4). The ID Token
+// generator and the UserInfo endpoint share it, so that both release the same
+// claims for the same scopes; any other handler can reuse it.
+package claims
+
+import (
+	"sort"
+
+	"github.com/tniah/authlib/models"
+	"github.com/tniah/authlib/types"
+	"github.com/tniah/authlib/utils"
+)
+
+var (
+	// ProfileClaims are the claims requested by the profile scope.
+	ProfileClaims = []string{
+		"name", "family_name", "given_name", "middle_name", "nickname",
+		"preferred_username", "profile", "picture", "website", "gender",
+		"birthdate", "zoneinfo", "locale", "updated_at",
+	}
+	// EmailClaims are the claims requested by the email scope.
+	EmailClaims = []string{"email", "email_verified"}
+	// AddressClaims are the claims requested by the address scope.
+	AddressClaims = []string{"address"}
+	// PhoneClaims are the claims requested by the phone scope.
+	PhoneClaims = []string{"phone_number", "phone_number_verified"}
+)
+
+// StandardScopeClaims returns the standard scopes mapped to the claims they
+// request (OIDC Core §5.4).
+func StandardScopeClaims() map[types.Scope][]string {
+	return map[types.Scope][]string{
+		types.ScopeProfile: ProfileClaims,
+		types.ScopeEmail:   EmailClaims,
+		types.ScopeAddress: AddressClaims,
+		types.ScopePhone:   PhoneClaims,
+	}
+}
+
+// Builder selects the claims released for a set of granted scopes. Use
+// NewBuilder to obtain one with the standard scopes, then SetScopeClaims to
+// map custom scopes.
+type Builder struct {
+	scopeClaims map[types.Scope][]string
+}
+
+// NewBuilder returns a Builder mapping the standard scopes to their claims.
+func NewBuilder() *Builder {
+	return &Builder{scopeClaims: StandardScopeClaims()}
+}
+
+// SetScopeClaims maps scope to claims, replacing any previous mapping of
+// scope. Call it with no claims to stop releasing claims for scope.
+func (b *Builder) SetScopeClaims(scope types.Scope, claims ...string) *Builder {
+	if len(claims) == 0 {
+		delete(b.scopeClaims, scope)
+		return b
+	}
+
+	b.scopeClaims[scope] = claims
+	return b
+}
+
+// ClaimNames returns the names of the claims released for scopes, sorted.
+func (b *Builder) ClaimNames(scopes types.Scopes) []string {
+	var names []string
+	for _, scope := range scopes {
+		names = append(names, b.scopeClaims[scope]...)
+	}
+
+	return uniqueSorted(names)
+}
+
+// SupportedClaims returns the names of every claim the Builder can release,
+// sorted, for the claims_supported metadata.
+func (b *Builder) SupportedClaims() []string {
+	var names []string
+	for _, claims := range b.scopeClaims {
+		names = append(names, claims...)
+	}
+
+	return uniqueSorted(names)
+}
+
+// SupportedScopes returns the scopes mapped to claims, sorted, for the
+// scopes_supported metadata.
+func (b *Builder) SupportedScopes() []string {
+	names := make([]string, 0, len(b.scopeClaims))
+	for scope := range b.scopeClaims {
+		names = append(names, scope.String())
+	}
+
+	return uniqueSorted(names)
+}
+
+// Build returns the claims of claims released for scopes. A claim is released
+// only when one of the granted scopes maps to it; nil and empty string values
+// are left out (OIDC Core §5.3.2). claims is not modified.
+func (b *Builder) Build(claims map[string]interface{}, scopes types.Scopes) map[string]interface{} {
+	return b.BuildRequested(claims, scopes, nil)
+}
+
+// BuildRequested is Build that also releases the claims requested
+// individually with the claims parameter (OIDC Core §5.5), whether or not a
+// granted scope maps to them. Only claims mapped to some scope, i.e. listed by
+// SupportedClaims, can be requested; other names are ignored.
+func (b *Builder) BuildRequested(claims map[string]interface{}, scopes types.Scopes, requested map[string]*types.ClaimRequest) map[string]interface{} {
+	names := b.ClaimNames(scopes)
+	if len(requested) > 0 {
+		supported := make(map[string]bool)
+		for _, name := range b.SupportedClaims() {
+			supported[name] = true
+		}
+
+		for name := range requested {
+			if supported[name] {
+				names = append(names, name)
+			}
+		}
+	}
+
+	out := make(map[string]interface{})
+	for _, name := range names {
+		v, ok := claims[name]
+		if !ok || v == nil || v == "" {
+			continue
+		}
+
+		out[name] = v
+	}
+
+	return out
+}
+
+// UserClaims returns the claims of user released for scopes, or an empty map
+// when user does not implement models.ClaimsProvider.
+func (b *Builder) UserClaims(user models.User, scopes types.Scopes) map[string]interface{} {
+	return b.UserClaimsRequested(user, scopes, nil)
+}
+
+// UserClaimsRequested is UserClaims that also releases the claims requested
+// individually with the claims parameter, as BuildRequested does.
+func (b *Builder) UserClaimsRequested(user models.User, scopes types.Scopes, requested map[string]*types.ClaimRequest) map[string]interface{} {
+	if utils.IsNil(user) {
+		return map[string]interface{}{}
+	}
+
+	p, ok := user.(models.ClaimsProvider)
+	if !ok {
+		return map[string]interface{}{}
+	}
+
+	return b.BuildRequested(p.GetClaims(), scopes, requested)
+}
+
+func uniqueSorted(names []string) []string {
+	sort.Strings(names)
+
+	out := names[:0]
+	for i, name := range names {
+		if i == 0 || name != names[i-1] {
+			out = append(out, name)
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,138 @@
+package claims
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/tniah/authlib/integrations/sql"
+	"github.com/tniah/authlib/types"
+)
+
+func newUserClaims() map[string]interface{} {
+	return map[string]interface{}{
+		"name":           "Jane Doe",
+		"nickname":       "",
+		"picture":        nil,
+		"email":          "jane@example.com",
+		"email_verified": true,
+		"phone_number":   "+1 555 0100",
+		"tenant":         "acme",
+	}
+}
+
+func TestBuilder_Build(t *testing.T) {
+	t.Run("claims of the granted scopes", func(t *testing.T) {
+		claims := newUserClaims()
+
+		out := NewBuilder().Build(claims, types.NewScopes([]string{"openid", "profile", "email"}))
+		assert.Equal(t, map[string]interface{}{
+			"name":           "Jane Doe",
+			"email":          "jane@example.com",
+			"email_verified": true,
+		}, out)
+		assert.Equal(t, newUserClaims(), claims, "input must not be modified")
+	})
+
+	t.Run("no scopes", func(t *testing.T) {
+		out := NewBuilder().Build(newUserClaims(), nil)
+		assert.Empty(t, out)
+		assert.NotNil(t, out)
+	})
+
+	t.Run("custom scope", func(t *testing.T) {
+		b := NewBuilder().SetScopeClaims("tenant", "tenant")
+
+		out := b.Build(newUserClaims(), types.NewScopes([]string{"openid", "tenant"}))
+		assert.Equal(t, map[string]interface{}{"tenant": "acme"}, out)
+	})
+
+	t.Run("removed scope", func(t *testing.T) {
+		b := NewBuilder().SetScopeClaims(types.ScopeEmail)
+
+		out := b.Build(newUserClaims(), types.NewScopes([]string{"openid", "email"}))
+		assert.Empty(t, out)
+	})
+}
+
+func TestBuilder_BuildRequested(t *testing.T) {
+	requested := map[string]*types.ClaimRequest{
+		"phone_number": {Essential: true},
+		"nickname":     nil,
+		"birthdate":    nil,
+		"tenant":       nil,
+	}
+
+	out := NewBuilder().BuildRequested(newUserClaims(), types.NewScopes([]string{"openid", "email"}), requested)
+	assert.Equal(t, map[string]interface{}{
+		"email":          "jane@example.com",
+		"email_verified": true,
+		"phone_number":   "+1 555 0100",
+	}, out)
+}
+
+func TestBuilder_ClaimNames(t *testing.T) {
+	b := NewBuilder().SetScopeClaims("contact", "email", "phone_number")
+
+	names := b.ClaimNames(types.NewScopes([]string{"email", "contact", "unknown"}))
+	assert.Equal(t, []string{"email", "email_verified", "phone_number"}, names)
+	assert.Empty(t, b.ClaimNames(types.NewScopes([]string{"openid"})))
+}
+
+func TestBuilder_SupportedClaims(t *testing.T) {
+	b := NewBuilder()
+
+	supported := b.SupportedClaims()
+	assert.Len(t, supported, len(ProfileClaims)+len(EmailClaims)+len(AddressClaims)+len(PhoneClaims))
+	assert.IsIncreasing(t, supported)
+	assert.Contains(t, supported, "address")
+
+	b.SetScopeClaims("tenant", "tenant", "email")
+	assert.Contains(t, b.SupportedClaims(), "tenant")
+	assert.Len(t, b.SupportedClaims(), len(supported)+1)
+}
+
+func TestBuilder_SupportedScopes(t *testing.T) {
+	b := NewBuilder()
+	assert.Equal(t, []string{"address", "email", "phone", "profile"}, b.SupportedScopes())
+
+	b.SetScopeClaims("tenant", "tenant").SetScopeClaims(types.ScopeAddress)
+	assert.Equal(t, []string{"email", "phone", "profile", "tenant"}, b.SupportedScopes())
+}
+
+func TestBuilder_UserClaims(t *testing.T) {
+	scopes := types.NewScopes([]string{"openid", "phone"})
+
+	t.Run("claims provider", func(t *testing.T) {
+		user := &sql.User{UserID: uuid.NewString(), Claims: newUserClaims()}
+
+		out := NewBuilder().UserClaims(user, scopes)
+		assert.Equal(t, map[string]interface{}{"phone_number": "+1 555 0100"}, out)
+	})
+
+	t.Run("user without claims", func(t *testing.T) {
+		out := NewBuilder().UserClaims(&sql.User{UserID: uuid.NewString()}, scopes)
+		assert.Empty(t, out)
+		assert.NotNil(t, out)
+	})
+
+	t.Run("requested claims", func(t *testing.T) {
+		user := &sql.User{UserID: uuid.NewString(), Claims: newUserClaims()}
+		b := NewBuilder().SetScopeClaims("tenant", "tenant")
+
+		out := b.UserClaimsRequested(user, scopes, map[string]*types.ClaimRequest{"tenant": nil, "name": nil})
+		assert.Equal(t, map[string]interface{}{"phone_number": "+1 555 0100", "tenant": "acme", "name": "Jane Doe"}, out)
+	})
+
+	t.Run("unmapped requested claims", func(t *testing.T) {
+		user := &sql.User{UserID: uuid.NewString(), Claims: newUserClaims()}
+
+		out := NewBuilder().UserClaimsRequested(user, scopes, map[string]*types.ClaimRequest{"tenant": nil})
+		assert.Equal(t, map[string]interface{}{"phone_number": "+1 555 0100"}, out)
+	})
+
+	t.Run("nil user", func(t *testing.T) {
+		var user *sql.User
+		assert.Empty(t, NewBuilder().UserClaims(user, scopes))
+	})
+}
@@ -4,10 +4,13 @@
 package authorizationcode
 
 import (
+	"crypto"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	autherrors "github.com/tniah/authlib/errors"
+	"github.com/tniah/authlib/keys"
+	"github.com/tniah/authlib/oidc/claims"
 	"github.com/tniah/authlib/utils"
 )
 
@@ -28,18 +31,29 @@ type Config struct {
 	signingKey          []byte
 	signingKeyMethod    jwt.SigningMethod
 	signingKeyID        string
+	signer              crypto.Signer
 	signingKeyGenerator SigningKeyGenerator
+	keySet              keys.KeySet
+	keyCache            *utils.SigningKeyCache
+	claimsBuilder       *claims.Builder
 	extraClaimGenerator ExtraClaimGenerator
 	existNonce          ExistNonce
+	acrValuesSupported  []string
+	requireACRValues    bool
 }
 
 // NewConfig returns a Config with secure defaults:
 //   - nonce is required (OIDC Core §3.1.2.1).
 //   - ID Token lifetime is 60 minutes.
+//   - PEM signing keys are parsed once and cached.
+//   - Claims of users implementing models.ClaimsProvider are released for the
+//     standard scopes (OIDC Core §5.4).
 func NewConfig() *Config {
 	return &Config{
-		requireNonce: true,
-		expiresIn:    DefaultExpiresIn,
+		requireNonce:  true,
+		expiresIn:     DefaultExpiresIn,
+		keyCache:      utils.NewSigningKeyCache(utils.DefaultSigningKeyCacheSize),
+		claimsBuilder: claims.NewBuilder(),
 	}
 }
 
@@ -91,13 +105,61 @@ func (cfg *Config) SetSigningKey(key []byte, method jwt.SigningMethod, keyID ...
 	return cfg
 }
 
+// SetSigner sets a static signing key that is already parsed: an
+// *rsa.PrivateKey, *ecdsa.PrivateKey, ed25519.PrivateKey, or any other
+// crypto.Signer such as a keys.ExternalSigner backed by a KMS, with its method
+// and optional key ID. Takes precedence over SetSigningKey when set.
+func (cfg *Config) SetSigner(signer crypto.Signer, method jwt.SigningMethod, keyID ...string) *Config {
+	cfg.signer = signer
+	cfg.signingKeyMethod = method
+
+	if len(keyID) > 0 {
+		cfg.signingKeyID = keyID[0]
+	}
+
+	return cfg
+}
+
 // SetSigningKeyGenerator sets a dynamic signing key resolver. When set, it is
-// called per-request instead of using the static signing key.
+// called per-request instead of using the static signing key or signer. The
+// keys it returns are parsed once and cached by fingerprint.
 func (cfg *Config) SetSigningKeyGenerator(fn SigningKeyGenerator) *Config {
 	cfg.signingKeyGenerator = fn
 	return cfg
 }
 
+// SetKeySet signs ID Tokens with the current key of ks, whose public keys are
+// published by the JWKS endpoint. Takes precedence over SetSigningKey,
+// SetSigner and SetSigningKeyGenerator when set.
+func (cfg *Config) SetKeySet(ks keys.KeySet) *Config {
+	cfg.keySet = ks
+	return cfg
+}
+
+// SetClaimsBuilder sets the Builder selecting the claims of the user released
+// in the ID Token for the granted scopes. Defaults to claims.NewBuilder(); set
+// nil to leave user claims out of the ID Token.
+func (cfg *Config) SetClaimsBuilder(b *claims.Builder) *Config {
+	cfg.claimsBuilder = b
+	return cfg
+}
+
+// SetACRValuesSupported sets the Authentication Context Class References the
+// server can satisfy, published as acr_values_supported (OpenID Connect
+// Discovery 1.0 §3).
+func (cfg *Config) SetACRValuesSupported(values ...string) *Config {
+	cfg.acrValuesSupported = values
+	return cfg
+}
+
+// SetRequireACRValues controls whether the authentication must satisfy one of
+// the acr_values of the request. Default: false, as acr_values are voluntary
+// (OIDC Core §3.1.2.1); an essential acr claim is always required.
+func (cfg *Config) SetRequireACRValues(value bool) *Config {
+	cfg.requireACRValues = value
+	return cfg
+}
+
 // SetExtraClaimGenerator sets a function that returns additional claims to
 // merge into the ID Token. Extra claims may not override standard claims
 // (iss, sub, aud, exp, iat, auth_time, nonce).
@@ -125,11 +187,12 @@ func (cfg *Config) ValidateConfig() error {
 		return autherrors.ErrMissingExpiresIn
 	}
 
-	if cfg.signingKey == nil && utils.IsNil(cfg.signingKeyGenerator) {
+	staticKey := cfg.signingKey != nil || !utils.IsNil(cfg.signer)
+	if !staticKey && utils.IsNil(cfg.signingKeyGenerator) && utils.IsNil(cfg.keySet) {
 		return autherrors.ErrMissingSigningKey
 	}
 
-	if cfg.signingKey != nil && utils.IsNil(cfg.signingKeyMethod) {
+	if staticKey && utils.IsNil(cfg.signingKeyMethod) {
 		return autherrors.ErrMissingSigningKeyMethod
 	}
 
@@ -1,19 +1,25 @@
 package authorizationcode
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
 	"testing"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 	autherrors "github.com/tniah/authlib/errors"
+	"github.com/tniah/authlib/keys"
 	"github.com/tniah/authlib/mocks/oidc/core/authorization_code"
+	"github.com/tniah/authlib/oidc/claims"
 )
 
 func TestConfig(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		cfg := NewConfig()
 		assert.Equal(t, DefaultExpiresIn, cfg.expiresIn)
+		assert.NotNil(t, cfg.keyCache)
 
 		cfg.SetRequireNonce(false)
 		assert.False(t, cfg.requireNonce)
@@ -37,12 +43,35 @@ func TestConfig(t *testing.T) {
 		assert.Equal(t, jwt.SigningMethodHS256, cfg.signingKeyMethod)
 		assert.Equal(t, "my-kid", cfg.signingKeyID)
 
+		priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		assert.NoError(t, err)
+		cfg.SetSigner(priv, jwt.SigningMethodES256, "signer-kid")
+		assert.Equal(t, priv, cfg.signer)
+		assert.Equal(t, jwt.SigningMethodES256, cfg.signingKeyMethod)
+		assert.Equal(t, "signer-kid", cfg.signingKeyID)
+
 		cfg.SetSigningKeyGenerator(oidc.NewMockSigningKeyGenerator(t).Execute)
 		assert.NotNil(t, cfg.signingKeyGenerator)
 
+		ks := keys.NewStaticKeySet(nil)
+		cfg.SetKeySet(ks)
+		assert.Equal(t, ks, cfg.keySet)
+
 		extraGen := oidc.NewMockExtraClaimGenerator(t).Execute
 		cfg.SetExtraClaimGenerator(extraGen)
 		assert.NotNil(t, cfg.extraClaimGenerator)
+
+		assert.NotNil(t, cfg.claimsBuilder)
+		b := claims.NewBuilder()
+		cfg.SetClaimsBuilder(b)
+		assert.Same(t, b, cfg.claimsBuilder)
+
+		assert.False(t, cfg.requireACRValues)
+		cfg.SetRequireACRValues(true)
+		assert.True(t, cfg.requireACRValues)
+
+		cfg.SetACRValuesSupported("urn:acr:silver")
+		assert.Equal(t, []string{"urn:acr:silver"}, cfg.acrValuesSupported)
 	})
 
 	t.Run("error", func(t *testing.T) {
@@ -60,8 +89,20 @@ func TestConfig(t *testing.T) {
 		err = cfg.ValidateConfig()
 		assert.ErrorIs(t, err, autherrors.ErrMissingSigningKey)
 
+		cfg.SetKeySet(keys.NewStaticKeySet(nil))
+		assert.NoError(t, cfg.ValidateConfig())
+
+		cfg.SetKeySet(nil)
 		cfg.SetSigningKey([]byte("test"), nil)
 		err = cfg.ValidateConfig()
 		assert.ErrorIs(t, err, autherrors.ErrMissingSigningKeyMethod)
+
+		priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		assert.NoError(t, err)
+		cfg = NewConfig().SetIssuer("https://example.com").SetSigner(priv, nil)
+		assert.ErrorIs(t, cfg.ValidateConfig(), autherrors.ErrMissingSigningKeyMethod)
+
+		cfg.SetSigner(priv, jwt.SigningMethodES256)
+		assert.NoError(t, cfg.ValidateConfig())
 	})
 }
@@ -3,6 +3,7 @@ package authorizationcode
 import (
 	"context"
 	"errors"
+	"slices"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -13,11 +14,37 @@ import (
 	"github.com/tniah/authlib/utils"
 )
 
+// subjectTypePublic is the only subject identifier type issued: sub is the
+// same user ID for every client (OIDC Core §8).
+const subjectTypePublic = "public"
+
+// idTokenClaims are the claims GenerateIDToken sets in ID Tokens.
+var idTokenClaims = []string{"sub", "iss", "aud", "exp", "iat", "auth_time", "nonce", "acr", "amr"}
+
+// Keys of the authentication context stored in the extra data of the
+// authorization code, for the ID Token issued at the token endpoint.
+const (
+	extraAuthTime = "auth_time"
+	extraACR      = "acr"
+	extraAMR      = "amr"
+)
+
+// ExtraUserInfoClaims is the key of the token extra data holding the userinfo
+// member of the claims parameter, as the JSON of a types.ClaimsRequest, for
+// the UserInfo endpoint.
+const ExtraUserInfoClaims = "userinfo_claims"
+
 var (
 	// ErrNilAuthorizationCode is returned when the authorization code is nil.
 	ErrNilAuthorizationCode = errors.New("authorization code is nil")
 	// ErrMissingUserID is returned when the user ID is empty.
 	ErrMissingUserID = errors.New("user ID is empty")
+	// ErrSubjectMismatch is returned when the claims parameter requests an ID
+	// Token for another end-user.
+	ErrSubjectMismatch = errors.New("the end-user is not the requested subject")
+	// ErrUnmetACR is returned when the claims parameter requests an essential
+	// acr the authentication does not meet.
+	ErrUnmetACR = errors.New("the requested authentication context class is not met")
 )
 
 // Flow implements the OIDC ID Token extension for the Authorization Code grant.
@@ -41,6 +68,49 @@ func Must(cfg *Config) (*Flow, error) {
 	return New(cfg), nil
 }
 
+// ProvideMetadata adds the openid scope, the public subject type, the claims
+// of the ID Token, the scopes and claims of the claims Builder, its signing
+// algorithm, the supported acr values and the support of the claims parameter
+// to the authorization server metadata (OpenID Connect Discovery 1.0 §3). The
+// algorithms are only known with a KeySet or a static signing key, not with a
+// SigningKeyGenerator.
+func (f *Flow) ProvideMetadata(md types.Metadata) {
+	md.Add(types.MetadataScopesSupported, types.ScopeOpenID.String())
+	md.Add(types.MetadataSubjectTypesSupported, subjectTypePublic)
+	md.Add(types.MetadataClaimsSupported, idTokenClaims...)
+	if b := f.claimsBuilder; b != nil {
+		md.Add(types.MetadataScopesSupported, b.SupportedScopes()...)
+		md.Add(types.MetadataClaimsSupported, b.SupportedClaims()...)
+	}
+	md.Add(types.MetadataIDTokenSigningAlgValuesSupported, f.signingAlgs()...)
+	md.Add(types.MetadataACRValuesSupported, f.acrValuesSupported...)
+	md.Set(types.MetadataClaimsParameterSupported, true)
+}
+
+// signingAlgs returns the algorithms ID Tokens are signed with: those of every
+// published key of the KeySet, or that of the static signing key.
+func (f *Flow) signingAlgs() []string {
+	if !utils.IsNil(f.keySet) {
+		keys, err := f.keySet.PublicKeys(context.Background())
+		if err != nil {
+			return nil
+		}
+
+		algs := make([]string, 0, len(keys))
+		for _, k := range keys {
+			algs = append(algs, k.Method().Alg())
+		}
+
+		return algs
+	}
+
+	if (f.signingKey != nil || !utils.IsNil(f.signer)) && !utils.IsNil(f.signingKeyMethod) {
+		return []string{f.signingKeyMethod.Alg()}
+	}
+
+	return nil
+}
+
 // ValidateAuthorizationRequest validates OIDC-specific parameters in the
 // authorization request. It is a no-op when the openid scope is absent.
 func (f *Flow) ValidateAuthorizationRequest(r *requests.AuthorizationRequest) error {
@@ -66,6 +136,13 @@ func (f *Flow) ValidateAuthorizationRequest(r *requests.AuthorizationRequest) er
 // ValidateConsentRequest re-runs authorization request validation then enforces
 // prompt and user-presence rules. When prompt is absent and user is nil, it
 // defaults to prompt=login so the handler can redirect to the login page.
+//
+// When the authentication of the user does not meet the request — the user is
+// not the sub requested with the claims parameter, the authentication is older
+// than max_age, or does not satisfy the required acr values — prompt=login is
+// added the same way to demand re-authentication, or login_required is
+// returned for prompt=none. max_age and acr values are only checked for users
+// implementing models.AuthenticationContext.
 func (f *Flow) ValidateConsentRequest(r *requests.AuthorizationRequest) error {
 	if err := f.ValidateAuthorizationRequest(r); err != nil {
 		return err
@@ -92,23 +169,127 @@ func (f *Flow) ValidateConsentRequest(r *requests.AuthorizationRequest) error {
 		return autherrors.AccountSelectionRequiredError().WithState(r.State).WithRedirectURI(r.RedirectURI)
 	}
 
+	if !utils.IsNil(user) && f.requiresAuthentication(r) {
+		if r.Prompts.ContainNone() {
+			return autherrors.LoginRequiredError().WithState(r.State).WithRedirectURI(r.RedirectURI)
+		}
+
+		if !r.Prompts.ContainLogin() {
+			r.Prompts = append(r.Prompts, types.PromptLogin)
+		}
+	}
+
 	return nil
 }
 
-// ProcessAuthorizationCode stores the nonce from the authorization request
-// into the authorization code before it is persisted.
+// RequestedACRValues returns the acr values requested for the ID Token, in
+// order of preference, and whether the authentication must satisfy one of
+// them. The values of an acr claim requested with the claims parameter take
+// precedence over acr_values; they are required when the claim is essential
+// (OIDC Core §5.5.1.1). acr_values are required with SetRequireACRValues.
+// Handlers use it to pick the authentication to ask the user for.
+func (f *Flow) RequestedACRValues(r *requests.AuthorizationRequest) ([]string, bool) {
+	if r.Claims != nil {
+		if c := r.Claims.IDToken["acr"]; c != nil && (c.Essential || c.Value != nil || len(c.Values) > 0) {
+			var values []string
+			for _, v := range append([]interface{}{c.Value}, c.Values...) {
+				if s, ok := v.(string); ok && s != "" {
+					values = append(values, s)
+				}
+			}
+
+			return values, c.Essential
+		}
+	}
+
+	return r.ACRValues, f.requireACRValues && len(r.ACRValues) > 0
+}
+
+// requiresAuthentication reports whether the user must authenticate again to
+// meet the request: when the user is not the requested sub (OIDC Core
+// §5.5.1.1), when the last authentication is older than max_age (OIDC Core
+// §3.1.2.1), or when its acr is not one of the required acr values.
+func (f *Flow) requiresAuthentication(r *requests.AuthorizationRequest) bool {
+	if r.Claims != nil && !r.Claims.IDToken["sub"].Allows(r.User.GetUserID()) {
+		return true
+	}
+
+	ac, ok := r.User.(models.AuthenticationContext)
+	if !ok {
+		return false
+	}
+
+	if r.MaxAge != nil {
+		authTime := ac.GetAuthTime()
+		maxAge := time.Duration(*r.MaxAge) * time.Second
+		if authTime.IsZero() || time.Since(authTime) > maxAge {
+			return true
+		}
+	}
+
+	values, required := f.RequestedACRValues(r)
+	if !required {
+		return false
+	}
+
+	acr := ac.GetACR()
+	if len(values) == 0 {
+		return acr == ""
+	}
+
+	return !slices.Contains(values, acr)
+}
+
+// ProcessAuthorizationCode stores the nonce and the claims parameter from the
+// authorization request into the authorization code before it is persisted.
+// When the user implements models.AuthenticationContext and the code
+// implements models.ExtendableAuthorizationCode, the auth_time, acr and amr of
+// the authentication are stored in its extra data for the ID Token issued at
+// the token endpoint.
 func (f *Flow) ProcessAuthorizationCode(r *requests.AuthorizationRequest, authCode models.AuthorizationCode, params map[string]interface{}) error {
 	if utils.IsNil(authCode) {
 		return ErrNilAuthorizationCode
 	}
 
 	authCode.SetNonce(r.Nonce)
+	authCode.SetClaimsRequest(r.Claims)
+
+	ac, ok := r.User.(models.AuthenticationContext)
+	if !ok || utils.IsNil(ac) {
+		return nil
+	}
+
+	ext, ok := authCode.(models.ExtendableAuthorizationCode)
+	if !ok {
+		return nil
+	}
+
+	data := ext.GetExtraData()
+	if data == nil {
+		data = make(map[string]interface{})
+	}
+
+	if authTime := ac.GetAuthTime(); !authTime.IsZero() {
+		data[extraAuthTime] = authTime.Unix()
+	}
+	if acr := ac.GetACR(); acr != "" {
+		data[extraACR] = acr
+	}
+	if amr := ac.GetAMR(); len(amr) > 0 {
+		data[extraAMR] = amr
+	}
+
+	ext.SetExtraData(data)
 	return nil
 }
 
 // ProcessToken generates an ID Token and adds it to the token response data
-// under the "id_token" key. It is a no-op when the openid scope is absent.
-func (f *Flow) ProcessToken(r *requests.TokenRequest, _ models.Token, data map[string]interface{}) error {
+// under the "id_token" key. When the claims parameter of the authorization
+// request has a userinfo member and the token implements
+// models.ExtendableToken, the member is stored in its extra data under
+// ExtraUserInfoClaims for the UserInfo endpoint. It is a no-op when the openid
+// scope is absent.
+func (f *Flow) ProcessToken(r *requests.TokenRequest, token models.Token, data map[string]interface{}) error {
 	if isOIDCReq := r.Scopes.ContainOpenID(); !isOIDCReq {
 		return nil
 	}
@@ -123,9 +304,31 @@ func (f *Flow) ProcessToken(r *requests.TokenRequest, _ models.Token, data map[s
 	}
 
 	data["id_token"] = idToken
+	setTokenUserInfoClaims(token, r.AuthCode.GetClaimsRequest())
 	return nil
 }
 
+// setTokenUserInfoClaims stores the userinfo member of cr in the extra data of
+// token.
+func setTokenUserInfoClaims(token models.Token, cr *types.ClaimsRequest) {
+	if cr == nil || len(cr.UserInfo) == 0 {
+		return
+	}
+
+	ext, ok := token.(models.ExtendableToken)
+	if !ok || utils.IsNil(ext) {
+		return
+	}
+
+	extra := ext.GetExtraData()
+	if extra == nil {
+		extra = make(map[string]interface{})
+	}
+
+	extra[ExtraUserInfoClaims] = (&types.ClaimsRequest{UserInfo: cr.UserInfo}).String()
+	ext.SetExtraData(extra)
+}
+
 // validateNonce checks that nonce is present (when required) and has not been
 // used before (when ExistNonce is configured).
 func (f *Flow) validateNonce(r *requests.AuthorizationRequest) error {
@@ -165,14 +368,32 @@ func (f *Flow) validatePrompt(r *requests.AuthorizationRequest) error {
 	return nil
 }
 
-// genIDToken builds and signs an ID Token for the given token request.
-// Extra claims from ExtraClaimGenerator are merged first; standard claims
-// (iss, sub, aud, exp, iat, auth_time, nonce) are set afterward and always
-// take precedence over any extra claim with the same key.
-func (f *Flow) genIDToken(r *requests.TokenRequest) (string, error) {
-	client := r.Client
-	user := r.User
-	authCode := r.AuthCode
+// GenerateIDToken builds and signs an ID Token from req. The claims of the
+// user released for the granted scopes and requested with the claims
+// parameter come first, then extra claims from ExtraClaimGenerator; standard
+// claims (iss, sub, aud, exp, iat, auth_time, nonce, c_hash, at_hash) are set
+// afterward and always take precedence over any user or extra claim with the
+// same key, as do acr and amr when known. Other flows issuing ID Tokens (e.g.
+// the hybrid flow) reuse it so the signing setup lives in one place.
+//
+// auth_time, acr and amr are taken from req, or else from the user when it
+// implements models.AuthenticationContext; auth_time defaults to now.
+//
+// When the claims parameter requests a sub value other than the user's, or
+// an essential acr that the acr claim does not satisfy, the authentication
+// failed (OIDC Core §5.5.1) and access_denied is returned.
+func (f *Flow) GenerateIDToken(ctx context.Context, req *IDTokenRequest) (string, error) {
+	idToken, err := f.generateIDToken(ctx, req)
+	if errors.Is(err, ErrSubjectMismatch) || errors.Is(err, ErrUnmetACR) {
+		return "", autherrors.AccessDeniedError().WithDescription(err.Error())
+	}
+
+	return idToken, err
+}
+
+func (f *Flow) generateIDToken(ctx context.Context, req *IDTokenRequest) (string, error) {
+	client := req.Client
+	user := req.User
 
 	sub := ""
 	if !utils.IsNil(user) {
@@ -185,9 +406,20 @@ func (f *Flow) genIDToken(r *requests.TokenRequest) (string, error) {
 	now := time.Now().UTC().Round(time.Second)
 	claims := utils.JWTClaim{}
 
+	var requested map[string]*types.ClaimRequest
+	if req.ClaimsRequest != nil {
+		requested = req.ClaimsRequest.IDToken
+	}
+
+	if b := f.claimsBuilder; b != nil {
+		for k, v := range b.UserClaimsRequested(user, req.Scopes, requested) {
+			claims[k] = v
+		}
+	}
+
 	// Merge extra claims first so standard claims set below take precedence.
 	if fn := f.extraClaimGenerator; fn != nil {
-		extraClaims, err := fn(r.Request.Context(), r.GrantType.String(), client, user)
+		extraClaims, err := fn(ctx, req.GrantType.String(), client, user)
 		if err != nil {
 			return "", err
 		}
@@ -196,35 +428,68 @@ func (f *Flow) genIDToken(r *requests.TokenRequest) (string, error) {
 		}
 	}
 
-	authTime := authCode.GetAuthTime()
+	authTime, acr, amr := req.AuthTime, req.ACR, req.AMR
+	if ac, ok := user.(models.AuthenticationContext); ok {
+		if authTime.IsZero() {
+			authTime = ac.GetAuthTime()
+		}
+		if acr == "" {
+			acr = ac.GetACR()
+		}
+		if len(amr) == 0 {
+			amr = ac.GetAMR()
+		}
+	}
+
 	if authTime.IsZero() {
 		authTime = now
 	}
 
 	// Standard claims always override any extra claim with the same key.
-	claims["iss"] = f.issuerHandler(r.Request.Context(), client)
+	claims["iss"] = f.issuerHandler(ctx, client)
 	claims["sub"] = sub
 	claims["aud"] = []string{client.GetClientID()}
-	claims["exp"] = jwt.NewNumericDate(now.Add(f.expiresInHandler(r.Request.Context(), r.GrantType.String(), client)))
+	claims["exp"] = jwt.NewNumericDate(now.Add(f.expiresInHandler(ctx, req.GrantType.String(), client)))
 	claims["iat"] = jwt.NewNumericDate(now)
 	claims["auth_time"] = jwt.NewNumericDate(authTime)
 
-	// nonce comes from the authorization code; override any extra claim value.
+	if acr != "" {
+		claims["acr"] = acr
+	}
+	if len(amr) > 0 {
+		claims["amr"] = amr
+	}
+
 	delete(claims, "nonce")
-	if nonce := authCode.GetNonce(); nonce != "" {
-		claims["nonce"] = nonce
+	if req.Nonce != "" {
+		claims["nonce"] = req.Nonce
 	}
 
-	key, method, keyID, err := f.signingKeyHandler(r.Request.Context(), client)
-	if err != nil {
+	if err := checkRequestedClaims(claims, requested); err != nil {
 		return "", err
 	}
 
-	t, err := utils.NewJWTToken(key, method, keyID)
+	t, err := f.jwtToken(ctx, client)
 	if err != nil {
 		return "", err
 	}
 
+	method := t.SigningMethod()
+
+	delete(claims, "c_hash")
+	if req.Code != "" {
+		if claims["c_hash"], err = utils.HalfHash(req.Code, method); err != nil {
+			return "", err
+		}
+	}
+
+	delete(claims, "at_hash")
+	if req.AccessToken != "" {
+		if claims["at_hash"], err = utils.HalfHash(req.AccessToken, method); err != nil {
+			return "", err
+		}
+	}
+
 	idToken, err := t.Generate(claims, utils.JWTHeader{})
 	if err != nil {
 		return "", err
@@ -233,6 +498,80 @@ func (f *Flow) genIDToken(r *requests.TokenRequest) (string, error) {
 	return idToken, nil
 }
 
+// genIDToken builds the ID Token for a code exchange at the token endpoint.
+// auth_time, acr, amr, nonce and the claims parameter come from the
+// authorization code. A failed claims request makes the code an invalid grant.
+func (f *Flow) genIDToken(r *requests.TokenRequest) (string, error) {
+	req := &IDTokenRequest{
+		GrantType:     r.GrantType,
+		Client:        r.Client,
+		User:          r.User,
+		Scopes:        r.Scopes,
+		AuthTime:      r.AuthCode.GetAuthTime(),
+		Nonce:         r.AuthCode.GetNonce(),
+		ClaimsRequest: r.AuthCode.GetClaimsRequest(),
+	}
+	setCodeAuthenticationContext(req, r.AuthCode)
+
+	idToken, err := f.generateIDToken(r.Request.Context(), req)
+	if errors.Is(err, ErrSubjectMismatch) || errors.Is(err, ErrUnmetACR) {
+		return "", autherrors.InvalidGrantError().WithDescription(err.Error())
+	}
+
+	return idToken, err
+}
+
+// setCodeAuthenticationContext sets the auth_time, acr and amr stored on the
+// authorization code by ProcessAuthorizationCode in req. The values may have
+// been through a JSON round trip of the extra data.
+func setCodeAuthenticationContext(req *IDTokenRequest, authCode models.AuthorizationCode) {
+	ext, ok := authCode.(models.ExtendableAuthorizationCode)
+	if !ok {
+		return
+	}
+
+	data := ext.GetExtraData()
+	switch v := data[extraAuthTime].(type) {
+	case int64:
+		req.AuthTime = time.Unix(v, 0).UTC()
+	case float64:
+		req.AuthTime = time.Unix(int64(v), 0).UTC()
+	}
+
+	req.ACR, _ = data[extraACR].(string)
+
+	switch v := data[extraAMR].(type) {
+	case []string:
+		req.AMR = v
+	case []interface{}:
+		for _, m := range v {
+			if s, ok := m.(string); ok {
+				req.AMR = append(req.AMR, s)
+			}
+		}
+	}
+}
+
+// checkRequestedClaims enforces the sub value and the essential acr requested
+// for the ID Token with the claims parameter (OIDC Core §5.5.1). Other values
+// requested for a claim are informational.
+func checkRequestedClaims(claims utils.JWTClaim, requested map[string]*types.ClaimRequest) error {
+	if !requested["sub"].Allows(claims["sub"]) {
+		return ErrSubjectMismatch
+	}
+
+	acr := requested["acr"]
+	if !acr.IsEssential() {
+		return nil
+	}
+
+	if v, ok := claims["acr"]; !ok || v == nil || v == "" || !acr.Allows(v) {
+		return ErrUnmetACR
+	}
+
+	return nil
+}
+
 // issuerHandler returns the issuer, preferring IssuerGenerator over the static value.
 func (f *Flow) issuerHandler(ctx context.Context, client models.Client) string {
 	if fn := f.issuerGenerator; fn != nil {
@@ -251,6 +590,31 @@ func (f *Flow) expiresInHandler(ctx context.Context, grantType string, client mo
 	return f.expiresIn
 }
 
+// jwtToken returns the JWTToken signing the ID Token: the current key of the
+// KeySet when set, otherwise the key returned by SigningKeyGenerator, the
+// signer, or the static key. PEM keys are parsed once and cached.
+func (f *Flow) jwtToken(ctx context.Context, client models.Client) (*utils.JWTToken, error) {
+	if !utils.IsNil(f.keySet) {
+		key, err := f.keySet.SigningKey(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return key.JWTToken(), nil
+	}
+
+	if utils.IsNil(f.signingKeyGenerator) && !utils.IsNil(f.signer) {
+		return utils.NewJWTTokenWithKey(f.signer, f.signingKeyMethod, f.signingKeyID), nil
+	}
+
+	key, method, keyID, err := f.signingKeyHandler(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.keyCache.JWTToken(key, method, keyID)
+}
+
 // signingKeyHandler returns the signing key, method, and key ID, preferring
 // SigningKeyGenerator over the static values.
 func (f *Flow) signingKeyHandler(ctx context.Context, client models.Client) ([]byte, jwt.SigningMethod, string, error) {
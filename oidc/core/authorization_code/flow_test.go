@@ -1,6 +1,14 @@
 package authorizationcode
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
 	"errors"
 	"net/http/httptest"
 	"testing"
@@ -12,9 +20,12 @@ import (
 	"github.com/stretchr/testify/require"
 	autherrors "github.com/tniah/authlib/errors"
 	"github.com/tniah/authlib/integrations/sql"
+	"github.com/tniah/authlib/keys"
 	oidc "github.com/tniah/authlib/mocks/oidc/core/authorization_code"
+	"github.com/tniah/authlib/oidc/claims"
 	"github.com/tniah/authlib/requests"
 	"github.com/tniah/authlib/types"
+	"github.com/tniah/authlib/utils"
 )
 
 var (
@@ -36,6 +47,13 @@ func newFlow(t *testing.T) *Flow {
 	return f
 }
 
+// plainUser is a models.User without authentication context.
+type plainUser string
+
+func (u plainUser) GetUserID() string {
+	return string(u)
+}
+
 // authReq returns an AuthorizationRequest with the given scopes and a real
 // HTTP request (needed when existNonce is configured).
 func authReq(scopes ...string) *requests.AuthorizationRequest {
@@ -225,6 +243,119 @@ func TestFlow_ValidateConsentRequest(t *testing.T) {
 		assert.NoError(t, f.ValidateConsentRequest(r))
 	})
 
+	t.Run("max_age", func(t *testing.T) {
+		f := New(cfg)
+		newReq := func(authAge time.Duration, prompts ...string) *requests.AuthorizationRequest {
+			r := authReq("openid")
+			r.MaxAge = types.NewMaxAge(300)
+			r.Prompts = types.NewPrompts(prompts)
+			r.User = &sql.User{UserID: "user-1", AuthTime: time.Now().Add(-authAge)}
+			return r
+		}
+
+		r := newReq(time.Minute)
+		require.NoError(t, f.ValidateConsentRequest(r))
+		assert.Empty(t, r.Prompts)
+
+		r = newReq(time.Hour, "consent")
+		require.NoError(t, f.ValidateConsentRequest(r))
+		assert.Equal(t, types.Prompts{types.PromptConsent, types.PromptLogin}, r.Prompts)
+
+		r = newReq(time.Hour, "none")
+		err := f.ValidateConsentRequest(r)
+		assert.ErrorIs(t, autherrors.ToAuthLibError(err).Code, autherrors.ErrLoginRequired)
+
+		r = authReq("openid")
+		r.MaxAge = types.NewMaxAge(300)
+		r.User = &sql.User{UserID: "user-1"}
+		require.NoError(t, f.ValidateConsentRequest(r))
+		assert.Equal(t, types.Prompts{types.PromptLogin}, r.Prompts, "unknown auth time")
+	})
+
+	t.Run("prompt_login_keeps_single_login_prompt", func(t *testing.T) {
+		f := New(cfg)
+		r := authReq("openid")
+		r.Prompts = types.NewPrompts([]string{"login"})
+		r.User = &sql.User{UserID: "user-1", AuthTime: time.Now().Add(-time.Minute)}
+		require.NoError(t, f.ValidateConsentRequest(r))
+		assert.Equal(t, types.Prompts{types.PromptLogin}, r.Prompts)
+	})
+
+	t.Run("user_without_authentication_context_not_checked", func(t *testing.T) {
+		f := New(cfg)
+		r := authReq("openid")
+		r.MaxAge = types.NewMaxAge(0)
+		r.User = plainUser("user-1")
+		require.NoError(t, f.ValidateConsentRequest(r))
+		assert.Empty(t, r.Prompts)
+	})
+
+	t.Run("essential_acr", func(t *testing.T) {
+		f := New(cfg)
+		newReq := func(acr string, prompts ...string) *requests.AuthorizationRequest {
+			r := authReq("openid")
+			r.Prompts = types.NewPrompts(prompts)
+			r.ACRValues = []string{"urn:acr:bronze"}
+			r.Claims = &types.ClaimsRequest{IDToken: map[string]*types.ClaimRequest{
+				"acr": {Essential: true, Values: []interface{}{"urn:acr:silver", "urn:acr:gold"}},
+			}}
+			r.User = &sql.User{UserID: "user-1", ACR: acr}
+			return r
+		}
+
+		r := newReq("urn:acr:gold")
+		require.NoError(t, f.ValidateConsentRequest(r))
+		assert.Empty(t, r.Prompts)
+
+		r = newReq("urn:acr:bronze")
+		require.NoError(t, f.ValidateConsentRequest(r))
+		assert.Equal(t, types.Prompts{types.PromptLogin}, r.Prompts)
+
+		r = newReq("", "none")
+		err := f.ValidateConsentRequest(r)
+		assert.ErrorIs(t, autherrors.ToAuthLibError(err).Code, autherrors.ErrLoginRequired)
+	})
+
+	t.Run("requested_subject", func(t *testing.T) {
+		f := New(cfg)
+		newReq := func(sub string, prompts ...string) *requests.AuthorizationRequest {
+			r := authReq("openid")
+			r.Prompts = types.NewPrompts(prompts)
+			r.Claims = &types.ClaimsRequest{IDToken: map[string]*types.ClaimRequest{"sub": {Value: sub}}}
+			r.User = plainUser("user-1")
+			return r
+		}
+
+		r := newReq("user-1")
+		require.NoError(t, f.ValidateConsentRequest(r))
+		assert.Empty(t, r.Prompts)
+
+		r = newReq("user-2")
+		require.NoError(t, f.ValidateConsentRequest(r))
+		assert.Equal(t, types.Prompts{types.PromptLogin}, r.Prompts)
+
+		r = newReq("user-2", "none")
+		err := f.ValidateConsentRequest(r)
+		assert.ErrorIs(t, autherrors.ToAuthLibError(err).Code, autherrors.ErrLoginRequired)
+	})
+
+	t.Run("acr_values", func(t *testing.T) {
+		newReq := func() *requests.AuthorizationRequest {
+			r := authReq("openid")
+			r.ACRValues = []string{"urn:acr:silver"}
+			r.User = &sql.User{UserID: "user-1", ACR: "urn:acr:bronze"}
+			return r
+		}
+
+		r := newReq()
+		require.NoError(t, New(cfg).ValidateConsentRequest(r))
+		assert.Empty(t, r.Prompts, "acr_values are voluntary by default")
+
+		r = newReq()
+		require.NoError(t, New(validConfig().SetRequireNonce(false).SetRequireACRValues(true)).ValidateConsentRequest(r))
+		assert.Equal(t, types.Prompts{types.PromptLogin}, r.Prompts)
+	})
+
 	t.Run("validation_error_from_auth_request_propagates", func(t *testing.T) {
 		// requireNonce=true (default): missing nonce must bubble up.
 		f := New(validConfig())
@@ -235,6 +366,25 @@ func TestFlow_ValidateConsentRequest(t *testing.T) {
 	})
 }
 
+func TestFlow_RequestedACRValues(t *testing.T) {
+	f := newFlow(t)
+	r := authReq("openid")
+	r.ACRValues = []string{"urn:acr:silver"}
+
+	values, required := f.RequestedACRValues(r)
+	assert.Equal(t, []string{"urn:acr:silver"}, values)
+	assert.False(t, required)
+
+	r.Claims = &types.ClaimsRequest{IDToken: map[string]*types.ClaimRequest{"acr": nil}}
+	values, _ = f.RequestedACRValues(r)
+	assert.Equal(t, []string{"urn:acr:silver"}, values, "acr requested in the default manner")
+
+	r.Claims.IDToken["acr"] = &types.ClaimRequest{Essential: true, Value: "urn:acr:gold", Values: []interface{}{"urn:acr:platinum"}}
+	values, required = f.RequestedACRValues(r)
+	assert.Equal(t, []string{"urn:acr:gold", "urn:acr:platinum"}, values)
+	assert.True(t, required)
+}
+
 func TestFlow_ProcessAuthorizationCode(t *testing.T) {
 	f := newFlow(t)
 	r := authReq("openid")
@@ -257,6 +407,27 @@ func TestFlow_ProcessAuthorizationCode(t *testing.T) {
 		require.NoError(t, f.ProcessAuthorizationCode(r, authCode, nil))
 		assert.Equal(t, "", authCode.GetNonce())
 	})
+
+	t.Run("stores_authentication_context", func(t *testing.T) {
+		authTime := time.Now().Add(-time.Minute).UTC().Round(time.Second)
+		r.User = &sql.User{UserID: "user-1", AuthTime: authTime, ACR: "urn:acr:silver", AMR: []string{"pwd", "otp"}}
+		authCode := &sql.AuthorizationCode{Data: map[string]interface{}{"dpop_jkt": "jkt"}}
+		require.NoError(t, f.ProcessAuthorizationCode(r, authCode, nil))
+		assert.Equal(t, map[string]interface{}{
+			"dpop_jkt":  "jkt",
+			"auth_time": authTime.Unix(),
+			"acr":       "urn:acr:silver",
+			"amr":       []string{"pwd", "otp"},
+		}, authCode.GetExtraData())
+		r.User = nil
+	})
+
+	t.Run("stores_claims_request", func(t *testing.T) {
+		r.Claims = &types.ClaimsRequest{IDToken: map[string]*types.ClaimRequest{"email": nil}}
+		authCode := &sql.AuthorizationCode{}
+		require.NoError(t, f.ProcessAuthorizationCode(r, authCode, nil))
+		assert.Same(t, r.Claims, authCode.GetClaimsRequest())
+	})
 }
 
 func TestFlow_ProcessToken(t *testing.T) {
@@ -390,6 +561,69 @@ func TestFlow_ProcessToken(t *testing.T) {
 		assert.Contains(t, err.Error(), "generator error")
 	})
 
+	t.Run("claims_request_from_auth_code", func(t *testing.T) {
+		r := tokenReq()
+		r.User = &sql.User{UserID: "user-1", Claims: map[string]interface{}{"email": "jane@example.com"}}
+		r.AuthCode = &sql.AuthorizationCode{ClaimsRequest: &types.ClaimsRequest{
+			IDToken: map[string]*types.ClaimRequest{"email": nil},
+		}}
+		data := map[string]interface{}{}
+		require.NoError(t, f.ProcessToken(r, nil, data))
+
+		claims := parseIDToken(t, data["id_token"].(string))
+		assert.Equal(t, "jane@example.com", claims["email"])
+	})
+
+	t.Run("userinfo_claims_request_stored_on_token", func(t *testing.T) {
+		r := tokenReq()
+		r.AuthCode = &sql.AuthorizationCode{ClaimsRequest: &types.ClaimsRequest{
+			IDToken:  map[string]*types.ClaimRequest{"email": nil},
+			UserInfo: map[string]*types.ClaimRequest{"given_name": {Essential: true}},
+		}}
+		token := &sql.Token{Data: map[string]interface{}{"cnf": map[string]interface{}{"jkt": "thumbprint"}}}
+		require.NoError(t, f.ProcessToken(r, token, map[string]interface{}{}))
+
+		assert.Contains(t, token.Data, "cnf")
+		cr, err := types.ParseClaimsRequest(token.Data[ExtraUserInfoClaims].(string))
+		require.NoError(t, err)
+		assert.Equal(t, map[string]*types.ClaimRequest{"given_name": {Essential: true}}, cr.UserInfo)
+		assert.Empty(t, cr.IDToken)
+
+		token = &sql.Token{}
+		r.AuthCode = &sql.AuthorizationCode{}
+		require.NoError(t, f.ProcessToken(r, token, map[string]interface{}{}))
+		assert.Empty(t, token.Data)
+	})
+
+	t.Run("authentication_context_from_auth_code", func(t *testing.T) {
+		authTime := time.Now().Add(-time.Hour).UTC().Round(time.Second)
+		r := tokenReq()
+		// Stored extra data after a JSON round trip.
+		r.AuthCode = &sql.AuthorizationCode{AuthTime: time.Now(), Data: map[string]interface{}{
+			"auth_time": float64(authTime.Unix()),
+			"acr":       "urn:acr:silver",
+			"amr":       []interface{}{"pwd", "otp"},
+		}}
+		data := map[string]interface{}{}
+		require.NoError(t, f.ProcessToken(r, nil, data))
+
+		claims := parseIDToken(t, data["id_token"].(string))
+		assert.Equal(t, float64(authTime.Unix()), claims["auth_time"])
+		assert.Equal(t, "urn:acr:silver", claims["acr"])
+		assert.Equal(t, []interface{}{"pwd", "otp"}, claims["amr"])
+	})
+
+	t.Run("unmet_essential_acr_returns_invalid_grant", func(t *testing.T) {
+		r := tokenReq()
+		r.AuthCode = &sql.AuthorizationCode{ClaimsRequest: &types.ClaimsRequest{
+			IDToken: map[string]*types.ClaimRequest{"acr": {Essential: true, Values: []interface{}{"urn:acr:gold"}}},
+		}}
+		err := f.ProcessToken(r, nil, map[string]interface{}{})
+		authErr := autherrors.ToAuthLibError(err)
+		assert.ErrorIs(t, authErr.Code, autherrors.ErrInvalidGrant)
+		assert.Equal(t, ErrUnmetACR.Error(), authErr.Description)
+	})
+
 	t.Run("signing_key_generator_takes_precedence", func(t *testing.T) {
 		gen := oidc.NewMockSigningKeyGenerator(t)
 		gen.EXPECT().Execute(mock.Anything, mock.Anything).
@@ -415,3 +649,378 @@ func TestFlow_ProcessToken(t *testing.T) {
 		assert.Contains(t, err.Error(), "key error")
 	})
 }
+
+func TestFlow_GenerateIDToken(t *testing.T) {
+	f := newFlow(t)
+	ctx := context.Background()
+	req := func() *IDTokenRequest {
+		return &IDTokenRequest{
+			GrantType: types.GrantTypeImplicit,
+			Client:    &sql.Client{ClientID: "client-1"},
+			User:      &sql.User{UserID: "user-1"},
+			Nonce:     "n-0S6",
+		}
+	}
+
+	t.Run("missing_user_returns_error", func(t *testing.T) {
+		r := req()
+		r.User = nil
+		_, err := f.GenerateIDToken(ctx, r)
+		assert.ErrorIs(t, err, ErrMissingUserID)
+	})
+
+	t.Run("hashes_absent_by_default", func(t *testing.T) {
+		idToken, err := f.GenerateIDToken(ctx, req())
+		require.NoError(t, err)
+		claims := parseIDToken(t, idToken)
+		assert.Equal(t, "n-0S6", claims["nonce"])
+		assert.NotContains(t, claims, "c_hash")
+		assert.NotContains(t, claims, "at_hash")
+	})
+
+	t.Run("c_hash_and_at_hash_added", func(t *testing.T) {
+		r := req()
+		r.Code = "code-1"
+		r.AccessToken = "token-1"
+		idToken, err := f.GenerateIDToken(ctx, r)
+		require.NoError(t, err)
+
+		claims := parseIDToken(t, idToken)
+		cHash, _ := utils.HalfHash("code-1", testMethod)
+		atHash, _ := utils.HalfHash("token-1", testMethod)
+		assert.Equal(t, cHash, claims["c_hash"])
+		assert.Equal(t, atHash, claims["at_hash"])
+	})
+
+	t.Run("auth_time_taken_from_request", func(t *testing.T) {
+		r := req()
+		r.AuthTime = time.Now().Add(-time.Hour).UTC().Round(time.Second)
+		idToken, err := f.GenerateIDToken(ctx, r)
+		require.NoError(t, err)
+		claims := parseIDToken(t, idToken)
+		assert.Equal(t, float64(r.AuthTime.Unix()), claims["auth_time"])
+	})
+
+	t.Run("scope_claims_of_user_included", func(t *testing.T) {
+		r := req()
+		r.Scopes = types.NewScopes([]string{"openid", "email"})
+		r.User = &sql.User{UserID: "user-1", Claims: map[string]interface{}{
+			"email":        "jane@example.com",
+			"name":         "Jane Doe",
+			"phone_number": "+1 555 0100",
+			"sub":          "other-user",
+		}}
+		idToken, err := f.GenerateIDToken(ctx, r)
+		require.NoError(t, err)
+
+		claims := parseIDToken(t, idToken)
+		assert.Equal(t, "jane@example.com", claims["email"])
+		assert.Equal(t, "user-1", claims["sub"])
+		assert.NotContains(t, claims, "name")
+		assert.NotContains(t, claims, "phone_number")
+	})
+
+	t.Run("extra_claims_override_scope_claims", func(t *testing.T) {
+		gen := oidc.NewMockExtraClaimGenerator(t)
+		gen.EXPECT().Execute(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(map[string]interface{}{"email": "override@example.com"}, nil)
+
+		f2 := New(validConfig().SetExtraClaimGenerator(gen.Execute))
+		r := req()
+		r.Scopes = types.NewScopes([]string{"openid", "email"})
+		r.User = &sql.User{UserID: "user-1", Claims: map[string]interface{}{"email": "jane@example.com"}}
+		idToken, err := f2.GenerateIDToken(ctx, r)
+		require.NoError(t, err)
+		assert.Equal(t, "override@example.com", parseIDToken(t, idToken)["email"])
+	})
+
+	t.Run("nil_claims_builder_releases_no_scope_claims", func(t *testing.T) {
+		f2 := New(validConfig().SetClaimsBuilder(nil))
+		r := req()
+		r.Scopes = types.NewScopes([]string{"openid", "email"})
+		r.User = &sql.User{UserID: "user-1", Claims: map[string]interface{}{"email": "jane@example.com"}}
+		idToken, err := f2.GenerateIDToken(ctx, r)
+		require.NoError(t, err)
+		assert.NotContains(t, parseIDToken(t, idToken), "email")
+	})
+
+	t.Run("authentication_context_of_user", func(t *testing.T) {
+		r := req()
+		authTime := time.Now().Add(-time.Hour).UTC().Round(time.Second)
+		r.User = &sql.User{UserID: "user-1", AuthTime: authTime, ACR: "urn:acr:gold", AMR: []string{"hwk"}}
+		gen := oidc.NewMockExtraClaimGenerator(t)
+		gen.EXPECT().Execute(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(map[string]interface{}{"acr": "urn:acr:fake"}, nil)
+
+		idToken, err := New(validConfig().SetExtraClaimGenerator(gen.Execute)).GenerateIDToken(ctx, r)
+		require.NoError(t, err)
+
+		claims := parseIDToken(t, idToken)
+		assert.Equal(t, float64(authTime.Unix()), claims["auth_time"])
+		assert.Equal(t, "urn:acr:gold", claims["acr"])
+		assert.Equal(t, []interface{}{"hwk"}, claims["amr"])
+	})
+
+	t.Run("request_authentication_context_takes_precedence", func(t *testing.T) {
+		r := req()
+		r.User = &sql.User{UserID: "user-1", ACR: "urn:acr:gold", AMR: []string{"hwk"}}
+		r.ACR = "urn:acr:silver"
+		r.AMR = []string{"pwd"}
+		idToken, err := f.GenerateIDToken(ctx, r)
+		require.NoError(t, err)
+
+		claims := parseIDToken(t, idToken)
+		assert.Equal(t, "urn:acr:silver", claims["acr"])
+		assert.Equal(t, []interface{}{"pwd"}, claims["amr"])
+	})
+
+	t.Run("requested_claims_included", func(t *testing.T) {
+		r := req()
+		r.Scopes = types.NewScopes([]string{"openid"})
+		r.User = &sql.User{UserID: "user-1", Claims: map[string]interface{}{
+			"email":        "jane@example.com",
+			"phone_number": "+1 555 0100",
+		}}
+		r.ClaimsRequest = &types.ClaimsRequest{
+			IDToken:  map[string]*types.ClaimRequest{"email": {Essential: true}},
+			UserInfo: map[string]*types.ClaimRequest{"phone_number": nil},
+		}
+		idToken, err := f.GenerateIDToken(ctx, r)
+		require.NoError(t, err)
+
+		claims := parseIDToken(t, idToken)
+		assert.Equal(t, "jane@example.com", claims["email"])
+		assert.NotContains(t, claims, "phone_number")
+	})
+
+	t.Run("requested_subject_mismatch_returns_access_denied", func(t *testing.T) {
+		r := req()
+		r.ClaimsRequest = &types.ClaimsRequest{IDToken: map[string]*types.ClaimRequest{"sub": {Value: "user-2"}}}
+		_, err := f.GenerateIDToken(ctx, r)
+		authErr := autherrors.ToAuthLibError(err)
+		assert.ErrorIs(t, authErr.Code, autherrors.ErrAccessDenied)
+		assert.Equal(t, ErrSubjectMismatch.Error(), authErr.Description)
+
+		r.ClaimsRequest.IDToken["sub"].Value = "user-1"
+		_, err = f.GenerateIDToken(ctx, r)
+		assert.NoError(t, err)
+	})
+
+	t.Run("essential_acr", func(t *testing.T) {
+		newReq := func(acr string, requested *types.ClaimRequest) *IDTokenRequest {
+			r := req()
+			r.User = &sql.User{UserID: "user-1", ACR: acr}
+			r.ClaimsRequest = &types.ClaimsRequest{IDToken: map[string]*types.ClaimRequest{"acr": requested}}
+			return r
+		}
+		silverOrGold := []interface{}{"urn:acr:silver", "urn:acr:gold"}
+
+		idToken, err := f.GenerateIDToken(ctx, newReq("urn:acr:gold", &types.ClaimRequest{Essential: true, Values: silverOrGold}))
+		require.NoError(t, err)
+		assert.Equal(t, "urn:acr:gold", parseIDToken(t, idToken)["acr"])
+
+		_, err = f.GenerateIDToken(ctx, newReq("urn:acr:bronze", &types.ClaimRequest{Essential: true, Values: silverOrGold}))
+		assert.ErrorIs(t, autherrors.ToAuthLibError(err).Code, autherrors.ErrAccessDenied)
+
+		_, err = f.GenerateIDToken(ctx, newReq("", &types.ClaimRequest{Essential: true}))
+		assert.ErrorIs(t, autherrors.ToAuthLibError(err).Code, autherrors.ErrAccessDenied)
+
+		idToken, err = f.GenerateIDToken(ctx, newReq("urn:acr:bronze", &types.ClaimRequest{Values: silverOrGold}))
+		require.NoError(t, err, "voluntary acr values are not enforced")
+		assert.Equal(t, "urn:acr:bronze", parseIDToken(t, idToken)["acr"])
+	})
+
+	t.Run("key_set_signs_with_its_current_key", func(t *testing.T) {
+		priv, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+		require.NoError(t, err)
+		key, err := keys.NewKey(priv, jwt.SigningMethodES384)
+		require.NoError(t, err)
+
+		f := New(validConfig().SetKeySet(keys.NewStaticKeySet(key)))
+		r := req()
+		r.AccessToken = "token-1"
+		idToken, err := f.GenerateIDToken(ctx, r)
+		require.NoError(t, err)
+
+		claims := jwt.MapClaims{}
+		token, err := jwt.ParseWithClaims(idToken, claims, func(*jwt.Token) (interface{}, error) {
+			return &priv.PublicKey, nil
+		}, jwt.WithValidMethods([]string{"ES384"}))
+		require.NoError(t, err)
+		assert.Equal(t, key.ID(), token.Header["kid"])
+
+		atHash, _ := utils.HalfHash("token-1", jwt.SigningMethodES384)
+		assert.Equal(t, atHash, claims["at_hash"])
+	})
+
+	t.Run("key_set_error", func(t *testing.T) {
+		f := New(validConfig().SetKeySet(keys.NewStaticKeySet(nil)))
+		_, err := f.GenerateIDToken(ctx, req())
+		assert.ErrorIs(t, err, keys.ErrNoSigningKey)
+	})
+
+	t.Run("signer_signs_without_parsing", func(t *testing.T) {
+		priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		f := New(validConfig().SetSigner(priv, jwt.SigningMethodES256, "signer-kid"))
+		idToken, err := f.GenerateIDToken(ctx, req())
+		require.NoError(t, err)
+		assert.Equal(t, 0, f.keyCache.Len())
+
+		token, err := jwt.Parse(idToken, func(*jwt.Token) (interface{}, error) {
+			return &priv.PublicKey, nil
+		}, jwt.WithValidMethods([]string{"ES256"}))
+		require.NoError(t, err)
+		assert.Equal(t, "signer-kid", token.Header["kid"])
+	})
+
+	t.Run("external_signer", func(t *testing.T) {
+		_, priv, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		key, err := keys.NewKey(keys.NewLocalSigner(priv), jwt.SigningMethodEdDSA)
+		require.NoError(t, err)
+
+		f := New(validConfig().SetKeySet(keys.NewStaticKeySet(key)))
+		r := req()
+		r.AccessToken = "token-1"
+		idToken, err := f.GenerateIDToken(ctx, r)
+		require.NoError(t, err)
+
+		claims := jwt.MapClaims{}
+		token, err := jwt.ParseWithClaims(idToken, claims, func(*jwt.Token) (interface{}, error) {
+			return priv.Public(), nil
+		}, jwt.WithValidMethods([]string{"EdDSA"}))
+		require.NoError(t, err)
+		assert.Equal(t, key.ID(), token.Header["kid"])
+
+		atHash, _ := utils.HalfHash("token-1", jwt.SigningMethodEdDSA)
+		assert.Equal(t, atHash, claims["at_hash"])
+	})
+
+	t.Run("signing_key_parsed_once", func(t *testing.T) {
+		f := newFlow(t)
+		for i := 0; i < 3; i++ {
+			_, err := f.GenerateIDToken(ctx, req())
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 1, f.keyCache.Len())
+	})
+}
+
+func TestFlow_ProvideMetadata(t *testing.T) {
+	t.Run("static_signing_key", func(t *testing.T) {
+		md := types.Metadata{types.MetadataScopesSupported: []string{"profile"}}
+		newFlow(t).ProvideMetadata(md)
+
+		assert.Equal(t, types.Metadata{
+			types.MetadataScopesSupported:                  []string{"profile", "openid", "address", "email", "phone"},
+			types.MetadataSubjectTypesSupported:            []string{"public"},
+			types.MetadataClaimsSupported:                  append([]string{"sub", "iss", "aud", "exp", "iat", "auth_time", "nonce", "acr", "amr"}, claims.NewBuilder().SupportedClaims()...),
+			types.MetadataIDTokenSigningAlgValuesSupported: []string{"HS256"},
+			types.MetadataClaimsParameterSupported:         true,
+		}, md)
+	})
+
+	t.Run("without_claims_builder", func(t *testing.T) {
+		md := types.Metadata{}
+		New(validConfig().SetClaimsBuilder(nil)).ProvideMetadata(md)
+		assert.Equal(t, []string{"openid"}, md[types.MetadataScopesSupported])
+		assert.Equal(t, []string{"sub", "iss", "aud", "exp", "iat", "auth_time", "nonce", "acr", "amr"}, md[types.MetadataClaimsSupported])
+	})
+
+	t.Run("custom_scope_claims", func(t *testing.T) {
+		md := types.Metadata{}
+		New(validConfig().SetClaimsBuilder(claims.NewBuilder().SetScopeClaims("tenant", "tenant_id"))).ProvideMetadata(md)
+		assert.Equal(t, []string{"openid", "address", "email", "phone", "profile", "tenant"}, md[types.MetadataScopesSupported])
+		assert.Contains(t, md[types.MetadataClaimsSupported], "tenant_id")
+	})
+
+	t.Run("acr_values_supported", func(t *testing.T) {
+		md := types.Metadata{}
+		New(validConfig().SetACRValuesSupported("urn:acr:silver", "urn:acr:gold")).ProvideMetadata(md)
+		assert.Equal(t, []string{"urn:acr:silver", "urn:acr:gold"}, md[types.MetadataACRValuesSupported])
+	})
+
+	t.Run("signer", func(t *testing.T) {
+		priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		md := types.Metadata{}
+		New(NewConfig().SetIssuer(testIssuer).SetSigner(priv, jwt.SigningMethodES256)).ProvideMetadata(md)
+		assert.Equal(t, []string{"ES256"}, md[types.MetadataIDTokenSigningAlgValuesSupported])
+	})
+
+	t.Run("key_set", func(t *testing.T) {
+		priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		active, err := keys.NewKey(priv, jwt.SigningMethodES256)
+		require.NoError(t, err)
+		_, edPriv, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		next, err := keys.NewKey(edPriv, jwt.SigningMethodEdDSA)
+		require.NoError(t, err)
+
+		md := types.Metadata{}
+		New(validConfig().SetKeySet(keys.NewStaticKeySet(active).SetNextKeys(next))).ProvideMetadata(md)
+		assert.Equal(t, []string{"ES256", "EdDSA"}, md[types.MetadataIDTokenSigningAlgValuesSupported])
+	})
+
+	t.Run("signing_key_generator", func(t *testing.T) {
+		gen := oidc.NewMockSigningKeyGenerator(t)
+		md := types.Metadata{}
+		New(NewConfig().SetIssuer(testIssuer).SetSigningKeyGenerator(gen.Execute)).ProvideMetadata(md)
+
+		assert.NotContains(t, md, types.MetadataIDTokenSigningAlgValuesSupported)
+		assert.Equal(t, []string{"openid", "address", "email", "phone", "profile"}, md[types.MetadataScopesSupported])
+	})
+}
+
+// BenchmarkFlow_GenerateIDToken compares the ways of supplying an RSA signing
+// key. parse_per_token disables the key cache, which is how every ID Token was
+// signed before keys were cached.
+// Run with: go test ./oidc/core/authorization_code -run '^$' -bench . -benchmem
+func BenchmarkFlow_GenerateIDToken(b *testing.B) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(priv)})
+	key, err := keys.NewKey(priv, jwt.SigningMethodRS256)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	uncached := NewConfig().SetIssuer(testIssuer).SetSigningKey(keyPEM, jwt.SigningMethodRS256, "kid-1")
+	uncached.keyCache = nil
+
+	for _, bm := range []struct {
+		name string
+		cfg  *Config
+	}{
+		{"parse_per_token", uncached},
+		{"cached_pem", NewConfig().SetIssuer(testIssuer).SetSigningKey(keyPEM, jwt.SigningMethodRS256, "kid-1")},
+		{"signer", NewConfig().SetIssuer(testIssuer).SetSigner(priv, jwt.SigningMethodRS256, "kid-1")},
+		{"key_set", NewConfig().SetIssuer(testIssuer).SetKeySet(keys.NewStaticKeySet(key))},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			f := New(bm.cfg)
+			r := &IDTokenRequest{
+				GrantType:   types.GrantTypeAuthorizationCode,
+				Client:      &sql.Client{ClientID: "client-1"},
+				User:        &sql.User{UserID: "user-1"},
+				Nonce:       "n-0S6",
+				AccessToken: "token-1",
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := f.GenerateIDToken(context.Background(), r); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
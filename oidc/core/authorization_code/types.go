@@ -7,8 +7,30 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/tniah/authlib/models"
 	"github.com/tniah/authlib/requests"
+	"github.com/tniah/authlib/types"
 )
 
+// IDTokenRequest carries the inputs for GenerateIDToken. Scopes are the
+// granted scopes selecting the user claims released in the ID Token, and
+// ClaimsRequest is the claims parameter of the authorization request, if any.
+// AuthTime, ACR and AMR describe the authentication of the user; when unset,
+// they are read from a user implementing models.AuthenticationContext.
+// Code and AccessToken are optional; when set, the c_hash and at_hash claims
+// are added (OIDC Core §3.3.2.11).
+type IDTokenRequest struct {
+	GrantType     types.GrantType
+	Client        models.Client
+	User          models.User
+	Scopes        types.Scopes
+	ClaimsRequest *types.ClaimsRequest
+	AuthTime      time.Time
+	ACR           string
+	AMR           []string
+	Nonce         string
+	Code          string
+	AccessToken   string
+}
+
 // IssuerGenerator is a function that returns the issuer (iss) claim value for
 // an ID Token. Use this for per-client or dynamic issuer resolution.
 type IssuerGenerator func(ctx context.Context, client models.Client) string
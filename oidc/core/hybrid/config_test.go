@@ -0,0 +1,121 @@
+package hybrid
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	hybridmock "github.com/tniah/authlib/mocks/oidc/core/hybrid"
+)
+
+func TestNewConfig(t *testing.T) {
+	cfg := NewConfig()
+	assert.Equal(t, []string{http.MethodGet}, cfg.authEndpointHttpMethods)
+	assert.Empty(t, cfg.authReqValidators)
+	assert.Empty(t, cfg.consentReqValidators)
+	assert.Empty(t, cfg.authCodeProcessors)
+	assert.Empty(t, cfg.tokenProcessors)
+	assert.Nil(t, cfg.clientMgr)
+	assert.Nil(t, cfg.authCodeMgr)
+	assert.Nil(t, cfg.tokenMgr)
+	assert.Nil(t, cfg.idTokenGen)
+}
+
+func TestConfig_Setters(t *testing.T) {
+	cfg := NewConfig()
+
+	mockClientMgr := hybridmock.NewMockClientManager(t)
+	cfg.SetClientManager(mockClientMgr)
+	assert.Equal(t, mockClientMgr, cfg.clientMgr)
+
+	mockAuthCodeMgr := hybridmock.NewMockAuthCodeManager(t)
+	cfg.SetAuthCodeManager(mockAuthCodeMgr)
+	assert.Equal(t, mockAuthCodeMgr, cfg.authCodeMgr)
+
+	mockTokenMgr := hybridmock.NewMockTokenManager(t)
+	cfg.SetTokenManager(mockTokenMgr)
+	assert.Equal(t, mockTokenMgr, cfg.tokenMgr)
+
+	mockIDTokenGen := hybridmock.NewMockIDTokenGenerator(t)
+	cfg.SetIDTokenGenerator(mockIDTokenGen)
+	assert.Equal(t, mockIDTokenGen, cfg.idTokenGen)
+
+	cfg.SetAuthEndpointHttpMethods([]string{http.MethodPost})
+	assert.Equal(t, []string{http.MethodPost}, cfg.authEndpointHttpMethods)
+}
+
+func TestConfig_RegisterExtension(t *testing.T) {
+	t.Run("registers_to_single_slice", func(t *testing.T) {
+		cfg := NewConfig()
+		cfg.RegisterExtension(hybridmock.NewMockAuthorizationRequestValidator(t))
+		cfg.RegisterExtension(hybridmock.NewMockConsentRequestValidator(t))
+		cfg.RegisterExtension(hybridmock.NewMockAuthCodeProcessor(t))
+		cfg.RegisterExtension(hybridmock.NewMockTokenProcessor(t))
+
+		assert.Len(t, cfg.authReqValidators, 1)
+		assert.Len(t, cfg.consentReqValidators, 1)
+		assert.Len(t, cfg.authCodeProcessors, 1)
+		assert.Len(t, cfg.tokenProcessors, 1)
+	})
+
+	t.Run("registers_to_all_matching_slices", func(t *testing.T) {
+		type multiExt struct {
+			hybridmock.MockAuthorizationRequestValidator
+			hybridmock.MockAuthCodeProcessor
+		}
+
+		cfg := NewConfig()
+		cfg.RegisterExtension(&multiExt{})
+
+		assert.Len(t, cfg.authReqValidators, 1)
+		assert.Empty(t, cfg.consentReqValidators)
+		assert.Len(t, cfg.authCodeProcessors, 1)
+		assert.Empty(t, cfg.tokenProcessors)
+	})
+
+	t.Run("ignores_non_extension_types", func(t *testing.T) {
+		cfg := NewConfig()
+		cfg.RegisterExtension(struct{}{})
+
+		assert.Empty(t, cfg.authReqValidators)
+		assert.Empty(t, cfg.consentReqValidators)
+		assert.Empty(t, cfg.authCodeProcessors)
+		assert.Empty(t, cfg.tokenProcessors)
+	})
+}
+
+func TestConfig_ValidateConfig(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		cfg := NewConfig().
+			SetClientManager(hybridmock.NewMockClientManager(t)).
+			SetAuthCodeManager(hybridmock.NewMockAuthCodeManager(t)).
+			SetTokenManager(hybridmock.NewMockTokenManager(t)).
+			SetIDTokenGenerator(hybridmock.NewMockIDTokenGenerator(t))
+		assert.NoError(t, cfg.ValidateConfig())
+	})
+
+	t.Run("error_when_client_manager_nil", func(t *testing.T) {
+		cfg := NewConfig()
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrNilClientManager)
+	})
+
+	t.Run("error_when_auth_code_manager_nil", func(t *testing.T) {
+		cfg := NewConfig().SetClientManager(hybridmock.NewMockClientManager(t))
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrNilAuthCodeManager)
+	})
+
+	t.Run("error_when_token_manager_nil", func(t *testing.T) {
+		cfg := NewConfig().
+			SetClientManager(hybridmock.NewMockClientManager(t)).
+			SetAuthCodeManager(hybridmock.NewMockAuthCodeManager(t))
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrNilTokenManager)
+	})
+
+	t.Run("error_when_id_token_generator_nil", func(t *testing.T) {
+		cfg := NewConfig().
+			SetClientManager(hybridmock.NewMockClientManager(t)).
+			SetAuthCodeManager(hybridmock.NewMockAuthCodeManager(t)).
+			SetTokenManager(hybridmock.NewMockTokenManager(t))
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrNilIDTokenGenerator)
+	})
+}
@@ -0,0 +1,276 @@
+package hybrid
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	autherrors "github.com/tniah/authlib/errors"
+	"github.com/tniah/authlib/models"
+	authorizationcode "github.com/tniah/authlib/oidc/core/authorization_code"
+	"github.com/tniah/authlib/oidc/core/internal/frontchannel"
+	"github.com/tniah/authlib/requests"
+	"github.com/tniah/authlib/rfc6749"
+	"github.com/tniah/authlib/types"
+	"github.com/tniah/authlib/utils"
+)
+
+var (
+	// ErrNilAuthCode is returned by genAuthCode when AuthCodeManager.New returns nil.
+	ErrNilAuthCode = errors.New("authorization code is nil")
+	// ErrNilToken is returned by genToken when TokenManager.New returns nil.
+	ErrNilToken = errors.New("token is nil")
+)
+
+// Flow implements the OpenID Connect Hybrid Flow (OIDC Core §3.3) for the
+// response types "code id_token", "code token" and "code id_token token".
+// Everything is returned in the fragment of the redirect URI.
+// It satisfies the server.AuthorizationGrant and server.ConsentGrant interfaces.
+type Flow struct {
+	*Config
+	*rfc6749.TokenFlowMixin
+}
+
+// New creates a Flow from cfg without validating dependencies. Prefer Must for
+// production use to catch missing managers at startup.
+func New(cfg *Config) *Flow {
+	return &Flow{Config: cfg, TokenFlowMixin: &rfc6749.TokenFlowMixin{}}
+}
+
+// Must returns a validated Flow or an error if any required Config dependency
+// is missing. Use this in application startup to fail fast.
+func Must(cfg *Config) (*Flow, error) {
+	if err := cfg.ValidateConfig(); err != nil {
+		return nil, err
+	}
+
+	return New(cfg), nil
+}
+
+// CheckResponseType returns true for the three hybrid response types, in any
+// value order, used by the server dispatcher to route authorization requests.
+func (f *Flow) CheckResponseType(typ types.ResponseType) bool {
+	return typ.Equal(types.ResponseTypeCode+" "+types.ResponseTypeIDToken) ||
+		typ.Equal(types.ResponseTypeCode+" "+types.ResponseTypeToken) ||
+		typ.Equal(types.ResponseTypeCode+" "+types.ResponseTypeIDToken+" "+types.ResponseTypeToken)
+}
+
+// ProvideMetadata adds the hybrid response types, the fragment and form_post
+// response modes, and the implicit grant to the authorization server metadata
+// (RFC 8414 §2), followed by the metadata of the IDTokenGenerator.
+func (f *Flow) ProvideMetadata(md types.Metadata) {
+	md.Add(types.MetadataResponseTypesSupported,
+		(types.ResponseTypeCode + " " + types.ResponseTypeIDToken).String(),
+		(types.ResponseTypeCode + " " + types.ResponseTypeToken).String(),
+		(types.ResponseTypeCode + " " + types.ResponseTypeIDToken + " " + types.ResponseTypeToken).String())
+	md.Add(types.MetadataResponseModesSupported, types.ResponseModeFragment.String(), types.ResponseModeFormPost.String())
+	md.Add(types.MetadataGrantTypesSupported, types.GrantTypeImplicit.String())
+
+	if p, ok := f.idTokenGen.(MetadataProvider); ok {
+		p.ProvideMetadata(md)
+	}
+}
+
+// ValidateAuthorizationRequest validates the incoming /authorize request:
+// HTTP method, client_id, redirect_uri, response_type, scope (openid is
+// mandatory), nonce (mandatory per OIDC Core §3.3.2.11), and any registered
+// AuthorizationRequestValidator extensions. Errors raised once redirect_uri is
+// known are returned in the fragment.
+func (f *Flow) ValidateAuthorizationRequest(r *requests.AuthorizationRequest) error {
+	return frontchannel.WithFragment(f.validateAuthorizationRequest(r))
+}
+
+// ValidateConsentRequest re-runs ValidateAuthorizationRequest and then invokes
+// all registered ConsentRequestValidator extensions.
+func (f *Flow) ValidateConsentRequest(r *requests.AuthorizationRequest) error {
+	if err := f.ValidateAuthorizationRequest(r); err != nil {
+		return err
+	}
+
+	for _, h := range f.consentReqValidators {
+		if err := h.ValidateConsentRequest(r); err != nil {
+			return frontchannel.WithFragment(err)
+		}
+	}
+
+	return nil
+}
+
+// AuthorizationResponse issues the authorization code, the access token when
+// requested, and the ID Token when requested, then returns all parameters to
+// redirect_uri in the fragment (OIDC Core §3.3.2.5), or in an auto-submitted
+// form with response_mode=form_post.
+// Returns access_denied if r.User is nil (i.e. the user did not authenticate).
+func (f *Flow) AuthorizationResponse(r *requests.AuthorizationRequest, rw http.ResponseWriter) error {
+	if utils.IsNil(r.User) {
+		return autherrors.AccessDeniedError().WithState(r.State).WithRedirectURI(r.RedirectURI).WithFragment()
+	}
+
+	authCode, err := f.genAuthCode(r)
+	if err != nil {
+		return err
+	}
+
+	params := map[string]interface{}{
+		"code": authCode.GetCode(),
+	}
+	if r.State != "" {
+		params["state"] = r.State
+	}
+
+	for _, h := range f.authCodeProcessors {
+		if err = h.ProcessAuthorizationCode(r, authCode, params); err != nil {
+			return err
+		}
+	}
+
+	var token models.Token
+	if r.ResponseType.Has(types.ResponseTypeToken) {
+		if token, err = f.genToken(r); err != nil {
+			return err
+		}
+
+		for k, v := range f.StandardTokenData(token) {
+			params[k] = v
+		}
+
+		for _, h := range f.tokenProcessors {
+			if err = h.ProcessImplicitToken(r, token, params); err != nil {
+				return err
+			}
+		}
+	}
+
+	if r.ResponseType.Has(types.ResponseTypeIDToken) {
+		idToken, err := f.genIDToken(r, authCode, token)
+		if err != nil {
+			return err
+		}
+
+		params["id_token"] = idToken
+	}
+
+	if err = f.authCodeMgr.Save(r.Request.Context(), authCode); err != nil {
+		return err
+	}
+
+	if token != nil {
+		if err = f.tokenMgr.Save(r.Request.Context(), token); err != nil {
+			return err
+		}
+	}
+
+	mode := types.ResponseModeFragment
+	if r.ResponseMode.IsFormPost() {
+		mode = types.ResponseModeFormPost
+	}
+
+	return utils.RedirectWithResponseMode(rw, mode, r.RedirectURI, params)
+}
+
+// validateAuthorizationRequest runs the built-in checks and the registered
+// AuthorizationRequestValidator extensions in order.
+func (f *Flow) validateAuthorizationRequest(r *requests.AuthorizationRequest) error {
+	if err := f.checkAuthEndpointHttpMethod(r); err != nil {
+		return err
+	}
+
+	if err := frontchannel.CheckClient(r, f.clientMgr); err != nil {
+		return err
+	}
+
+	if err := frontchannel.ValidateRedirectURI(r); err != nil {
+		return err
+	}
+
+	if err := frontchannel.ValidateResponseType(r, f.CheckResponseType); err != nil {
+		return err
+	}
+
+	if err := rfc6749.ValidateResponseMode(r, false); err != nil {
+		return err
+	}
+
+	if err := frontchannel.ValidateScope(r); err != nil {
+		return err
+	}
+
+	if err := r.ValidateNonce(true); err != nil {
+		return err
+	}
+
+	r.GrantType = types.GrantTypeAuthorizationCode
+	for _, h := range f.authReqValidators {
+		if err := h.ValidateAuthorizationRequest(r); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkAuthEndpointHttpMethod rejects requests whose HTTP method is not in
+// authEndpointHttpMethods (default: GET).
+func (f *Flow) checkAuthEndpointHttpMethod(r *requests.AuthorizationRequest) error {
+	for _, method := range f.authEndpointHttpMethods {
+		if r.Method() == method {
+			return nil
+		}
+	}
+
+	return autherrors.InvalidRequestError().WithDescription(fmt.Sprintf("unsupported http method \"%s\"", r.Method()))
+}
+
+// genAuthCode allocates and populates a new authorization code. The nonce is
+// stored on the code so the ID Token issued at the token endpoint carries it
+// too (OIDC Core §3.3.3.6).
+func (f *Flow) genAuthCode(r *requests.AuthorizationRequest) (models.AuthorizationCode, error) {
+	authCode := f.authCodeMgr.New()
+	if utils.IsNil(authCode) {
+		return nil, ErrNilAuthCode
+	}
+
+	if err := f.authCodeMgr.Generate(authCode, r); err != nil {
+		return nil, err
+	}
+
+	authCode.SetNonce(r.Nonce)
+	return authCode, nil
+}
+
+// genToken allocates and populates a new access token. A refresh token is
+// never issued from the authorization endpoint.
+func (f *Flow) genToken(r *requests.AuthorizationRequest) (models.Token, error) {
+	token := f.tokenMgr.New()
+	if utils.IsNil(token) {
+		return nil, ErrNilToken
+	}
+
+	tr := r.TokenRequest()
+	tr.GrantType = types.GrantTypeImplicit
+	if err := f.tokenMgr.Generate(token, tr, false); err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// genIDToken signs the front-channel ID Token. c_hash is always present since
+// a code is always issued; at_hash is added when an access token is returned
+// alongside (OIDC Core §3.3.2.11).
+func (f *Flow) genIDToken(r *requests.AuthorizationRequest, authCode models.AuthorizationCode, token models.Token) (string, error) {
+	req := &authorizationcode.IDTokenRequest{
+		GrantType:     r.GrantType,
+		Client:        r.Client,
+		User:          r.User,
+		Scopes:        r.Scopes,
+		ClaimsRequest: r.Claims,
+		Nonce:         r.Nonce,
+		Code:          authCode.GetCode(),
+	}
+	if token != nil {
+		req.AccessToken = token.GetAccessToken()
+	}
+
+	return f.idTokenGen.GenerateIDToken(r.Request.Context(), req)
+}
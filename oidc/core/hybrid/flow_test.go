@@ -0,0 +1,384 @@
+package hybrid
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	autherrors "github.com/tniah/authlib/errors"
+	"github.com/tniah/authlib/integrations/sql"
+	hybridmock "github.com/tniah/authlib/mocks/oidc/core/hybrid"
+	authorizationcode "github.com/tniah/authlib/oidc/core/authorization_code"
+	"github.com/tniah/authlib/requests"
+	"github.com/tniah/authlib/types"
+	"github.com/tniah/authlib/utils"
+)
+
+var testKey = []byte("test-secret")
+
+func newAuthReq(method string) *requests.AuthorizationRequest {
+	return &requests.AuthorizationRequest{
+		Request: httptest.NewRequest(method, "/authorize", nil),
+	}
+}
+
+func validClient() *sql.Client {
+	return &sql.Client{
+		ClientID:      "client-1",
+		RedirectURIs:  []string{"https://example.com/cb"},
+		ResponseTypes: []string{"code id_token", "code token", "code id_token token"},
+		Scopes:        []string{"openid", "profile"},
+	}
+}
+
+func idTokenGen(t *testing.T) *authorizationcode.Flow {
+	t.Helper()
+	f, err := authorizationcode.Must(authorizationcode.NewConfig().
+		SetIssuer("https://auth.example.com").
+		SetSigningKey(testKey, jwt.SigningMethodHS256, "kid-1"))
+	require.NoError(t, err)
+	return f
+}
+
+func parseIDToken(t *testing.T, tokenStr string) jwt.MapClaims {
+	t.Helper()
+	tok, err := jwt.Parse(tokenStr, func(_ *jwt.Token) (interface{}, error) {
+		return testKey, nil
+	})
+	require.NoError(t, err)
+	claims, ok := tok.Claims.(jwt.MapClaims)
+	require.True(t, ok)
+	return claims
+}
+
+func parseFragment(t *testing.T, rw *httptest.ResponseRecorder) url.Values {
+	t.Helper()
+	location, err := url.Parse(rw.Header().Get("Location"))
+	require.NoError(t, err)
+	assert.Empty(t, location.RawQuery)
+
+	fragment, err := url.ParseQuery(location.Fragment)
+	require.NoError(t, err)
+	return fragment
+}
+
+func TestFlow_Must(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		cfg := NewConfig().
+			SetClientManager(hybridmock.NewMockClientManager(t)).
+			SetAuthCodeManager(hybridmock.NewMockAuthCodeManager(t)).
+			SetTokenManager(hybridmock.NewMockTokenManager(t)).
+			SetIDTokenGenerator(idTokenGen(t))
+
+		f, err := Must(cfg)
+		require.NoError(t, err)
+		assert.NotNil(t, f)
+	})
+
+	t.Run("error_when_config_invalid", func(t *testing.T) {
+		f, err := Must(NewConfig())
+		require.Error(t, err)
+		assert.Nil(t, f)
+	})
+}
+
+func TestFlow_CheckResponseType(t *testing.T) {
+	f := New(NewConfig())
+	cases := []struct {
+		rt       string
+		expected bool
+	}{
+		{"code id_token", true},
+		{"id_token code", true},
+		{"code token", true},
+		{"token code", true},
+		{"code id_token token", true},
+		{"token id_token code", true},
+		{"code", false},
+		{"token", false},
+		{"id_token", false},
+		{"id_token token", false},
+		{"code code", false},
+		{"code id_token unknown", false},
+		{"", false},
+	}
+	for _, c := range cases {
+		assert.Equalf(t, c.expected, f.CheckResponseType(types.NewResponseType(c.rt)), "response_type %q", c.rt)
+	}
+}
+
+func TestFlow_ValidateAuthorizationRequest(t *testing.T) {
+	mockClientMgr := hybridmock.NewMockClientManager(t)
+	mockValidator := hybridmock.NewMockAuthorizationRequestValidator(t)
+	f := New(NewConfig().SetClientManager(mockClientMgr).RegisterExtension(mockValidator))
+
+	newReq := func() *requests.AuthorizationRequest {
+		r := newAuthReq(http.MethodGet)
+		r.ClientID = "client-1"
+		r.RedirectURI = "https://example.com/cb"
+		r.ResponseType = types.NewResponseType("code id_token")
+		r.Scopes = types.NewScopes([]string{"openid"})
+		r.Nonce = "n-0S6"
+		return r
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockClientMgr.On("QueryByClientID", mock.Anything, "client-1").Return(validClient(), nil).Once()
+		mockValidator.On("ValidateAuthorizationRequest", mock.Anything).Return(nil).Once()
+
+		r := newReq()
+		assert.NoError(t, f.ValidateAuthorizationRequest(r))
+		assert.Equal(t, types.GrantTypeAuthorizationCode, r.GrantType)
+	})
+
+	t.Run("error_when_nonce_missing", func(t *testing.T) {
+		mockClientMgr.On("QueryByClientID", mock.Anything, "client-1").Return(validClient(), nil).Once()
+
+		r := newReq()
+		r.Nonce = ""
+		authErr := autherrors.ToAuthLibError(f.ValidateAuthorizationRequest(r))
+		assert.Equal(t, autherrors.ErrInvalidRequest, authErr.Code)
+		assert.Equal(t, "https://example.com/cb", authErr.RedirectURI)
+		assert.True(t, authErr.Fragment)
+	})
+
+	t.Run("error_when_response_mode_is_query", func(t *testing.T) {
+		mockClientMgr.On("QueryByClientID", mock.Anything, "client-1").Return(validClient(), nil).Once()
+
+		r := newReq()
+		r.ResponseMode = types.ResponseModeQuery
+		authErr := autherrors.ToAuthLibError(f.ValidateAuthorizationRequest(r))
+		assert.Equal(t, autherrors.ErrInvalidRequest, authErr.Code)
+		assert.Empty(t, authErr.RedirectURI)
+	})
+
+	t.Run("extension_error_redirects_in_fragment", func(t *testing.T) {
+		mockClientMgr.On("QueryByClientID", mock.Anything, "client-1").Return(validClient(), nil).Once()
+		mockValidator.On("ValidateAuthorizationRequest", mock.Anything).
+			Return(autherrors.LoginRequiredError().WithRedirectURI("https://example.com/cb")).Once()
+
+		authErr := autherrors.ToAuthLibError(f.ValidateAuthorizationRequest(newReq()))
+		assert.Equal(t, autherrors.ErrLoginRequired, authErr.Code)
+		assert.True(t, authErr.Fragment)
+	})
+
+	t.Run("error_without_redirect_stays_direct", func(t *testing.T) {
+		mockClientMgr.On("QueryByClientID", mock.Anything, "client-1").Return(nil, nil).Once()
+
+		authErr := autherrors.ToAuthLibError(f.ValidateAuthorizationRequest(newReq()))
+		assert.Equal(t, autherrors.ErrInvalidRequest, authErr.Code)
+		assert.False(t, authErr.Fragment)
+	})
+}
+
+func TestFlow_ValidateConsentRequest(t *testing.T) {
+	mockClientMgr := hybridmock.NewMockClientManager(t)
+	mockValidator := hybridmock.NewMockConsentRequestValidator(t)
+	f := New(NewConfig().SetClientManager(mockClientMgr).RegisterExtension(mockValidator))
+
+	mockClientMgr.On("QueryByClientID", mock.Anything, "client-1").Return(validClient(), nil).Once()
+	mockValidator.On("ValidateConsentRequest", mock.Anything).Return(nil).Once()
+
+	r := newAuthReq(http.MethodGet)
+	r.ClientID = "client-1"
+	r.RedirectURI = "https://example.com/cb"
+	r.ResponseType = types.NewResponseType("code token")
+	r.Scopes = types.NewScopes([]string{"openid"})
+	r.Nonce = "n-0S6"
+	assert.NoError(t, f.ValidateConsentRequest(r))
+}
+
+func TestFlow_AuthorizationResponse(t *testing.T) {
+	newReq := func(rt string) *requests.AuthorizationRequest {
+		r := newAuthReq(http.MethodGet)
+		r.Client = validClient()
+		r.User = &sql.User{UserID: "user-1"}
+		r.RedirectURI = "https://example.com/cb"
+		r.ResponseType = types.NewResponseType(rt)
+		r.State = "xyz"
+		r.Nonce = "n-0S6"
+		r.GrantType = types.GrantTypeAuthorizationCode
+		r.Scopes = types.NewScopes([]string{"openid"})
+		return r
+	}
+
+	t.Run("code_id_token", func(t *testing.T) {
+		mockAuthCodeMgr := hybridmock.NewMockAuthCodeManager(t)
+		mockProcessor := hybridmock.NewMockAuthCodeProcessor(t)
+		f := New(NewConfig().
+			SetAuthCodeManager(mockAuthCodeMgr).
+			SetIDTokenGenerator(idTokenGen(t)).
+			RegisterExtension(mockProcessor))
+
+		authCode := &sql.AuthorizationCode{Code: "code-1", AuthTime: time.Now()}
+		mockAuthCodeMgr.On("New").Return(authCode).Once()
+		mockAuthCodeMgr.On("Generate", authCode, mock.Anything).Return(nil).Once()
+		mockProcessor.On("ProcessAuthorizationCode", mock.Anything, authCode, mock.Anything).Return(nil).Once()
+		mockAuthCodeMgr.On("Save", mock.Anything, authCode).Return(nil).Once()
+
+		rw := httptest.NewRecorder()
+		require.NoError(t, f.AuthorizationResponse(newReq("id_token code"), rw))
+		assert.Equal(t, http.StatusFound, rw.Code)
+		assert.Equal(t, "n-0S6", authCode.Nonce)
+
+		fragment := parseFragment(t, rw)
+		assert.Equal(t, "code-1", fragment.Get("code"))
+		assert.Equal(t, "xyz", fragment.Get("state"))
+		assert.Empty(t, fragment.Get("access_token"))
+
+		claims := parseIDToken(t, fragment.Get("id_token"))
+		cHash, _ := utils.HalfHash("code-1", jwt.SigningMethodHS256)
+		assert.Equal(t, cHash, claims["c_hash"])
+		assert.Equal(t, "n-0S6", claims["nonce"])
+		assert.Equal(t, "user-1", claims["sub"])
+		assert.NotContains(t, claims, "at_hash")
+	})
+
+	t.Run("code_id_token_with_form_post", func(t *testing.T) {
+		mockAuthCodeMgr := hybridmock.NewMockAuthCodeManager(t)
+		f := New(NewConfig().
+			SetAuthCodeManager(mockAuthCodeMgr).
+			SetIDTokenGenerator(idTokenGen(t)))
+
+		authCode := &sql.AuthorizationCode{Code: "code-1", AuthTime: time.Now()}
+		mockAuthCodeMgr.On("New").Return(authCode).Once()
+		mockAuthCodeMgr.On("Generate", authCode, mock.Anything).Return(nil).Once()
+		mockAuthCodeMgr.On("Save", mock.Anything, authCode).Return(nil).Once()
+
+		r := newReq("code id_token")
+		r.ResponseMode = types.ResponseModeFormPost
+		rw := httptest.NewRecorder()
+		require.NoError(t, f.AuthorizationResponse(r, rw))
+		assert.Equal(t, http.StatusOK, rw.Code)
+		assert.Empty(t, rw.Header().Get("Location"))
+		assert.Contains(t, rw.Body.String(), `name="code" value="code-1"`)
+		assert.Contains(t, rw.Body.String(), `name="id_token"`)
+	})
+
+	t.Run("code_token", func(t *testing.T) {
+		mockAuthCodeMgr := hybridmock.NewMockAuthCodeManager(t)
+		mockTokenMgr := hybridmock.NewMockTokenManager(t)
+		mockIDTokenGen := hybridmock.NewMockIDTokenGenerator(t)
+		mockProcessor := hybridmock.NewMockTokenProcessor(t)
+		f := New(NewConfig().
+			SetAuthCodeManager(mockAuthCodeMgr).
+			SetTokenManager(mockTokenMgr).
+			SetIDTokenGenerator(mockIDTokenGen).
+			RegisterExtension(mockProcessor))
+
+		authCode := &sql.AuthorizationCode{Code: "code-1"}
+		token := &sql.Token{TokenType: "Bearer", AccessToken: "access-token", RefreshToken: "refresh-token"}
+		mockAuthCodeMgr.On("New").Return(authCode).Once()
+		mockAuthCodeMgr.On("Generate", authCode, mock.Anything).Return(nil).Once()
+		mockTokenMgr.On("New").Return(token).Once()
+		mockTokenMgr.On("Generate", token, mock.MatchedBy(func(r *requests.TokenRequest) bool {
+			return r.GrantType.IsImplicit()
+		}), false).Return(nil).Once()
+		mockProcessor.On("ProcessImplicitToken", mock.Anything, token, mock.Anything).Return(nil).Once()
+		mockAuthCodeMgr.On("Save", mock.Anything, authCode).Return(nil).Once()
+		mockTokenMgr.On("Save", mock.Anything, token).Return(nil).Once()
+
+		rw := httptest.NewRecorder()
+		require.NoError(t, f.AuthorizationResponse(newReq("code token"), rw))
+
+		fragment := parseFragment(t, rw)
+		assert.Equal(t, "code-1", fragment.Get("code"))
+		assert.Equal(t, "access-token", fragment.Get("access_token"))
+		assert.Equal(t, "Bearer", fragment.Get("token_type"))
+		assert.Empty(t, fragment.Get("id_token"))
+	})
+
+	t.Run("code_id_token_token", func(t *testing.T) {
+		mockAuthCodeMgr := hybridmock.NewMockAuthCodeManager(t)
+		mockTokenMgr := hybridmock.NewMockTokenManager(t)
+		f := New(NewConfig().
+			SetAuthCodeManager(mockAuthCodeMgr).
+			SetTokenManager(mockTokenMgr).
+			SetIDTokenGenerator(idTokenGen(t)))
+
+		authCode := &sql.AuthorizationCode{Code: "code-1"}
+		token := &sql.Token{TokenType: "Bearer", AccessToken: "access-token"}
+		mockAuthCodeMgr.On("New").Return(authCode).Once()
+		mockAuthCodeMgr.On("Generate", authCode, mock.Anything).Return(nil).Once()
+		mockTokenMgr.On("New").Return(token).Once()
+		mockTokenMgr.On("Generate", token, mock.Anything, false).Return(nil).Once()
+		mockAuthCodeMgr.On("Save", mock.Anything, authCode).Return(nil).Once()
+		mockTokenMgr.On("Save", mock.Anything, token).Return(nil).Once()
+
+		rw := httptest.NewRecorder()
+		require.NoError(t, f.AuthorizationResponse(newReq("code id_token token"), rw))
+
+		fragment := parseFragment(t, rw)
+		claims := parseIDToken(t, fragment.Get("id_token"))
+		cHash, _ := utils.HalfHash("code-1", jwt.SigningMethodHS256)
+		atHash, _ := utils.HalfHash("access-token", jwt.SigningMethodHS256)
+		assert.Equal(t, cHash, claims["c_hash"])
+		assert.Equal(t, atHash, claims["at_hash"])
+	})
+
+	t.Run("error_when_user_nil", func(t *testing.T) {
+		f := New(NewConfig())
+		r := newReq("code id_token")
+		r.User = nil
+		authErr := autherrors.ToAuthLibError(f.AuthorizationResponse(r, httptest.NewRecorder()))
+		assert.Equal(t, autherrors.ErrAccessDenied, authErr.Code)
+		assert.True(t, authErr.Fragment)
+	})
+
+	t.Run("error_when_gen_auth_code_fails", func(t *testing.T) {
+		mockAuthCodeMgr := hybridmock.NewMockAuthCodeManager(t)
+		f := New(NewConfig().SetAuthCodeManager(mockAuthCodeMgr))
+
+		mockAuthCodeMgr.On("New").Return(nil).Once()
+		assert.ErrorIs(t, f.AuthorizationResponse(newReq("code id_token"), httptest.NewRecorder()), ErrNilAuthCode)
+	})
+
+	t.Run("error_when_gen_token_fails", func(t *testing.T) {
+		mockAuthCodeMgr := hybridmock.NewMockAuthCodeManager(t)
+		mockTokenMgr := hybridmock.NewMockTokenManager(t)
+		f := New(NewConfig().SetAuthCodeManager(mockAuthCodeMgr).SetTokenManager(mockTokenMgr))
+
+		mockAuthCodeMgr.On("New").Return(&sql.AuthorizationCode{}).Once()
+		mockAuthCodeMgr.On("Generate", mock.Anything, mock.Anything).Return(nil).Once()
+		mockTokenMgr.On("New").Return(nil).Once()
+		assert.ErrorIs(t, f.AuthorizationResponse(newReq("code token"), httptest.NewRecorder()), ErrNilToken)
+	})
+
+	t.Run("error_when_id_token_fails", func(t *testing.T) {
+		mockAuthCodeMgr := hybridmock.NewMockAuthCodeManager(t)
+		mockIDTokenGen := hybridmock.NewMockIDTokenGenerator(t)
+		f := New(NewConfig().SetAuthCodeManager(mockAuthCodeMgr).SetIDTokenGenerator(mockIDTokenGen))
+
+		mockAuthCodeMgr.On("New").Return(&sql.AuthorizationCode{}).Once()
+		mockAuthCodeMgr.On("Generate", mock.Anything, mock.Anything).Return(nil).Once()
+		mockIDTokenGen.On("GenerateIDToken", mock.Anything, mock.Anything).Return("", errors.New("sign error")).Once()
+		assert.ErrorContains(t, f.AuthorizationResponse(newReq("code id_token"), httptest.NewRecorder()), "sign error")
+	})
+
+	t.Run("error_when_save_fails", func(t *testing.T) {
+		mockAuthCodeMgr := hybridmock.NewMockAuthCodeManager(t)
+		f := New(NewConfig().SetAuthCodeManager(mockAuthCodeMgr).SetIDTokenGenerator(idTokenGen(t)))
+
+		mockAuthCodeMgr.On("New").Return(&sql.AuthorizationCode{Code: "code-1"}).Once()
+		mockAuthCodeMgr.On("Generate", mock.Anything, mock.Anything).Return(nil).Once()
+		mockAuthCodeMgr.On("Save", mock.Anything, mock.Anything).Return(errors.New("db error")).Once()
+		assert.ErrorContains(t, f.AuthorizationResponse(newReq("code id_token"), httptest.NewRecorder()), "db error")
+	})
+}
+
+func TestFlow_ProvideMetadata(t *testing.T) {
+	md := types.Metadata{}
+	New(NewConfig().SetIDTokenGenerator(idTokenGen(t))).ProvideMetadata(md)
+
+	assert.Equal(t, []string{"code id_token", "code token", "code id_token token"}, md[types.MetadataResponseTypesSupported])
+	assert.Equal(t, []string{"fragment", "form_post"}, md[types.MetadataResponseModesSupported])
+	assert.Equal(t, []string{"implicit"}, md[types.MetadataGrantTypesSupported])
+	assert.Equal(t, []string{"HS256"}, md[types.MetadataIDTokenSigningAlgValuesSupported])
+}
@@ -0,0 +1,85 @@
+package hybrid
+
+import (
+	"context"
+
+	"github.com/tniah/authlib/models"
+	authorizationcode "github.com/tniah/authlib/oidc/core/authorization_code"
+	"github.com/tniah/authlib/requests"
+	"github.com/tniah/authlib/types"
+)
+
+// ClientManager handles client lookup at the authorization endpoint.
+type ClientManager interface {
+	// QueryByClientID retrieves the client with the given client_id.
+	// Return (nil, nil) when the client does not exist.
+	QueryByClientID(ctx context.Context, clientID string) (models.Client, error)
+}
+
+// AuthCodeManager generates and persists authorization codes. The code is
+// later redeemed at the token endpoint by the Authorization Code flow, so the
+// same manager implementation can usually back both flows.
+type AuthCodeManager interface {
+	// New allocates a blank AuthorizationCode ready to be populated by Generate.
+	New() models.AuthorizationCode
+
+	// Generate populates authCode with a random code value, expiry, and any
+	// request-derived data (client_id, redirect_uri, scopes, user_id).
+	Generate(authCode models.AuthorizationCode, r *requests.AuthorizationRequest) error
+
+	// Save persists authCode to the backing store.
+	Save(ctx context.Context, code models.AuthorizationCode) error
+}
+
+// TokenManager generates and persists the access token returned for the
+// "code token" and "code id_token token" response types. A refresh token is
+// never issued from the authorization endpoint.
+type TokenManager interface {
+	// New allocates a blank Token ready to be populated by Generate.
+	New() models.Token
+
+	// Generate populates token with a value, expiry, scopes, and client/user
+	// binding. includeRefreshToken is always false for this flow.
+	Generate(token models.Token, r *requests.TokenRequest, includeRefreshToken bool) error
+
+	// Save persists the token to the backing store.
+	Save(ctx context.Context, token models.Token) error
+}
+
+// IDTokenGenerator signs the ID Token returned from the authorization
+// endpoint. *authorizationcode.Flow satisfies it, so the issuer, lifetime and
+// signing key configured for the code flow are reused as-is.
+type IDTokenGenerator interface {
+	GenerateIDToken(ctx context.Context, req *authorizationcode.IDTokenRequest) (string, error)
+}
+
+// MetadataProvider is implemented by an IDTokenGenerator that describes the ID
+// Tokens it signs in the server metadata, such as *authorizationcode.Flow.
+type MetadataProvider interface {
+	ProvideMetadata(md types.Metadata)
+}
+
+// AuthorizationRequestValidator is an extension hook called during
+// ValidateAuthorizationRequest, after the built-in checks pass.
+type AuthorizationRequestValidator interface {
+	ValidateAuthorizationRequest(r *requests.AuthorizationRequest) error
+}
+
+// ConsentRequestValidator is an extension hook called during
+// ValidateConsentRequest, after the built-in checks pass.
+type ConsentRequestValidator interface {
+	ValidateConsentRequest(r *requests.AuthorizationRequest) error
+}
+
+// AuthCodeProcessor is an extension hook called after the authorization code
+// is generated and before it is saved.
+type AuthCodeProcessor interface {
+	ProcessAuthorizationCode(r *requests.AuthorizationRequest, authCode models.AuthorizationCode, params map[string]interface{}) error
+}
+
+// TokenProcessor is an extension hook called after the access token is
+// generated and before it is saved. It shares its method with the implicit
+// flow's TokenProcessor, so one extension can serve both flows.
+type TokenProcessor interface {
+	ProcessImplicitToken(r *requests.AuthorizationRequest, token models.Token, params map[string]interface{}) error
+}
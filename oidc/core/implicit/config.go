@@ -0,0 +1,103 @@
+// Package implicit implements the OpenID Connect Implicit Flow (OIDC Core §3.2).
+// The authorization endpoint returns an ID Token, and optionally an access
+// token, in the redirect URI fragment. No authorization code is issued.
+package implicit
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/tniah/authlib/utils"
+)
+
+// Sentinel errors returned by ValidateConfig when a required dependency is missing.
+var (
+	ErrNilClientManager    = errors.New("client manager is nil")
+	ErrNilIDTokenGenerator = errors.New("id token generator is nil")
+)
+
+// Config holds all dependencies and extension hooks for the OIDC Implicit flow.
+// Use NewConfig() to get a config with sensible defaults, then chain Set*/RegisterExtension
+// calls before passing to Must() or New().
+type Config struct {
+	clientMgr  ClientManager
+	tokenMgr   TokenManager
+	idTokenGen IDTokenGenerator
+
+	authEndpointHttpMethods []string
+
+	// Extension slices are executed in registration order.
+	authReqValidators    []AuthorizationRequestValidator
+	consentReqValidators []ConsentRequestValidator
+	tokenProcessors      []TokenProcessor
+}
+
+// NewConfig returns a Config with the following defaults:
+//   - Accepts GET on /authorize.
+func NewConfig() *Config {
+	return &Config{
+		authEndpointHttpMethods: []string{http.MethodGet},
+		authReqValidators:       []AuthorizationRequestValidator{},
+		consentReqValidators:    []ConsentRequestValidator{},
+		tokenProcessors:         []TokenProcessor{},
+	}
+}
+
+// SetClientManager sets the client lookup manager.
+func (cfg *Config) SetClientManager(mgr ClientManager) *Config {
+	cfg.clientMgr = mgr
+	return cfg
+}
+
+// SetTokenManager sets the access token generation and persistence manager.
+// Optional: without it the flow only serves response_type=id_token.
+func (cfg *Config) SetTokenManager(mgr TokenManager) *Config {
+	cfg.tokenMgr = mgr
+	return cfg
+}
+
+// SetIDTokenGenerator sets the ID Token signer, typically a
+// *authorizationcode.Flow so the code flow's issuer, signing key and extra
+// claims are shared.
+func (cfg *Config) SetIDTokenGenerator(gen IDTokenGenerator) *Config {
+	cfg.idTokenGen = gen
+	return cfg
+}
+
+// SetAuthEndpointHttpMethods overrides the HTTP methods accepted at /authorize.
+// Default: [GET].
+func (cfg *Config) SetAuthEndpointHttpMethods(methods []string) *Config {
+	cfg.authEndpointHttpMethods = methods
+	return cfg
+}
+
+// RegisterExtension adds ext to every extension slice whose interface it satisfies.
+func (cfg *Config) RegisterExtension(ext interface{}) *Config {
+	if h, ok := ext.(AuthorizationRequestValidator); ok {
+		cfg.authReqValidators = append(cfg.authReqValidators, h)
+	}
+
+	if h, ok := ext.(ConsentRequestValidator); ok {
+		cfg.consentReqValidators = append(cfg.consentReqValidators, h)
+	}
+
+	if h, ok := ext.(TokenProcessor); ok {
+		cfg.tokenProcessors = append(cfg.tokenProcessors, h)
+	}
+
+	return cfg
+}
+
+// ValidateConfig checks that all required dependencies are set and returns the
+// first sentinel error encountered. Call this via Must() rather than directly.
+func (cfg *Config) ValidateConfig() error {
+	if utils.IsNil(cfg.clientMgr) {
+		return ErrNilClientManager
+	}
+
+	if utils.IsNil(cfg.idTokenGen) {
+		return ErrNilIDTokenGenerator
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package implicit
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	implicitmock "github.com/tniah/authlib/mocks/oidc/core/implicit"
+)
+
+func TestNewConfig(t *testing.T) {
+	cfg := NewConfig()
+	assert.Equal(t, []string{http.MethodGet}, cfg.authEndpointHttpMethods)
+	assert.Empty(t, cfg.authReqValidators)
+	assert.Empty(t, cfg.consentReqValidators)
+	assert.Empty(t, cfg.tokenProcessors)
+	assert.Nil(t, cfg.clientMgr)
+	assert.Nil(t, cfg.tokenMgr)
+	assert.Nil(t, cfg.idTokenGen)
+}
+
+func TestConfig_Setters(t *testing.T) {
+	cfg := NewConfig()
+
+	mockClientMgr := implicitmock.NewMockClientManager(t)
+	cfg.SetClientManager(mockClientMgr)
+	assert.Equal(t, mockClientMgr, cfg.clientMgr)
+
+	mockTokenMgr := implicitmock.NewMockTokenManager(t)
+	cfg.SetTokenManager(mockTokenMgr)
+	assert.Equal(t, mockTokenMgr, cfg.tokenMgr)
+
+	mockIDTokenGen := implicitmock.NewMockIDTokenGenerator(t)
+	cfg.SetIDTokenGenerator(mockIDTokenGen)
+	assert.Equal(t, mockIDTokenGen, cfg.idTokenGen)
+
+	cfg.SetAuthEndpointHttpMethods([]string{http.MethodPost})
+	assert.Equal(t, []string{http.MethodPost}, cfg.authEndpointHttpMethods)
+}
+
+func TestConfig_RegisterExtension(t *testing.T) {
+	t.Run("registers_to_single_slice", func(t *testing.T) {
+		cfg := NewConfig()
+		cfg.RegisterExtension(implicitmock.NewMockAuthorizationRequestValidator(t))
+		cfg.RegisterExtension(implicitmock.NewMockConsentRequestValidator(t))
+		cfg.RegisterExtension(implicitmock.NewMockTokenProcessor(t))
+
+		assert.Len(t, cfg.authReqValidators, 1)
+		assert.Len(t, cfg.consentReqValidators, 1)
+		assert.Len(t, cfg.tokenProcessors, 1)
+	})
+
+	t.Run("ignores_non_extension_types", func(t *testing.T) {
+		cfg := NewConfig()
+		cfg.RegisterExtension(struct{}{})
+
+		assert.Empty(t, cfg.authReqValidators)
+		assert.Empty(t, cfg.consentReqValidators)
+		assert.Empty(t, cfg.tokenProcessors)
+	})
+}
+
+func TestConfig_ValidateConfig(t *testing.T) {
+	t.Run("success_without_token_manager", func(t *testing.T) {
+		cfg := NewConfig().
+			SetClientManager(implicitmock.NewMockClientManager(t)).
+			SetIDTokenGenerator(implicitmock.NewMockIDTokenGenerator(t))
+		assert.NoError(t, cfg.ValidateConfig())
+	})
+
+	t.Run("error_when_client_manager_nil", func(t *testing.T) {
+		cfg := NewConfig()
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrNilClientManager)
+	})
+
+	t.Run("error_when_id_token_generator_nil", func(t *testing.T) {
+		cfg := NewConfig().SetClientManager(implicitmock.NewMockClientManager(t))
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrNilIDTokenGenerator)
+	})
+}
@@ -0,0 +1,318 @@
+package implicit
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	autherrors "github.com/tniah/authlib/errors"
+	"github.com/tniah/authlib/integrations/sql"
+	implicitmock "github.com/tniah/authlib/mocks/oidc/core/implicit"
+	authorizationcode "github.com/tniah/authlib/oidc/core/authorization_code"
+	"github.com/tniah/authlib/requests"
+	"github.com/tniah/authlib/types"
+	"github.com/tniah/authlib/utils"
+)
+
+var testKey = []byte("test-secret")
+
+func newAuthReq(method string) *requests.AuthorizationRequest {
+	return &requests.AuthorizationRequest{
+		Request: httptest.NewRequest(method, "/authorize", nil),
+	}
+}
+
+func validClient() *sql.Client {
+	return &sql.Client{
+		ClientID:      "client-1",
+		RedirectURIs:  []string{"https://example.com/cb"},
+		ResponseTypes: []string{"id_token", "id_token token"},
+		Scopes:        []string{"openid", "profile"},
+	}
+}
+
+func idTokenGen(t *testing.T) *authorizationcode.Flow {
+	t.Helper()
+	f, err := authorizationcode.Must(authorizationcode.NewConfig().
+		SetIssuer("https://auth.example.com").
+		SetSigningKey(testKey, jwt.SigningMethodHS256, "kid-1"))
+	require.NoError(t, err)
+	return f
+}
+
+func parseIDToken(t *testing.T, tokenStr string) jwt.MapClaims {
+	t.Helper()
+	tok, err := jwt.Parse(tokenStr, func(_ *jwt.Token) (interface{}, error) {
+		return testKey, nil
+	})
+	require.NoError(t, err)
+	claims, ok := tok.Claims.(jwt.MapClaims)
+	require.True(t, ok)
+	return claims
+}
+
+func parseFragment(t *testing.T, rw *httptest.ResponseRecorder) url.Values {
+	t.Helper()
+	location, err := url.Parse(rw.Header().Get("Location"))
+	require.NoError(t, err)
+	assert.Empty(t, location.RawQuery)
+
+	fragment, err := url.ParseQuery(location.Fragment)
+	require.NoError(t, err)
+	return fragment
+}
+
+func TestFlow_Must(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		cfg := NewConfig().
+			SetClientManager(implicitmock.NewMockClientManager(t)).
+			SetIDTokenGenerator(idTokenGen(t))
+
+		f, err := Must(cfg)
+		require.NoError(t, err)
+		assert.NotNil(t, f)
+	})
+
+	t.Run("error_when_config_invalid", func(t *testing.T) {
+		f, err := Must(NewConfig())
+		require.Error(t, err)
+		assert.Nil(t, f)
+	})
+}
+
+func TestFlow_CheckResponseType(t *testing.T) {
+	t.Run("with_token_manager", func(t *testing.T) {
+		f := New(NewConfig().SetTokenManager(implicitmock.NewMockTokenManager(t)))
+		cases := []struct {
+			rt       string
+			expected bool
+		}{
+			{"id_token", true},
+			{"id_token token", true},
+			{"token id_token", true},
+			{"token", false},
+			{"code", false},
+			{"code id_token", false},
+			{"", false},
+		}
+		for _, c := range cases {
+			assert.Equalf(t, c.expected, f.CheckResponseType(types.NewResponseType(c.rt)), "response_type %q", c.rt)
+		}
+	})
+
+	t.Run("id_token_only_without_token_manager", func(t *testing.T) {
+		f := New(NewConfig())
+		assert.True(t, f.CheckResponseType(types.ResponseTypeIDToken))
+		assert.False(t, f.CheckResponseType(types.NewResponseType("id_token token")))
+	})
+}
+
+func TestFlow_ValidateAuthorizationRequest(t *testing.T) {
+	mockClientMgr := implicitmock.NewMockClientManager(t)
+	mockValidator := implicitmock.NewMockAuthorizationRequestValidator(t)
+	f := New(NewConfig().SetClientManager(mockClientMgr).RegisterExtension(mockValidator))
+
+	newReq := func() *requests.AuthorizationRequest {
+		r := newAuthReq(http.MethodGet)
+		r.ClientID = "client-1"
+		r.RedirectURI = "https://example.com/cb"
+		r.ResponseType = types.ResponseTypeIDToken
+		r.Scopes = types.NewScopes([]string{"openid"})
+		r.Nonce = "n-0S6"
+		return r
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockClientMgr.On("QueryByClientID", mock.Anything, "client-1").Return(validClient(), nil).Once()
+		mockValidator.On("ValidateAuthorizationRequest", mock.Anything).Return(nil).Once()
+
+		r := newReq()
+		assert.NoError(t, f.ValidateAuthorizationRequest(r))
+		assert.Equal(t, types.GrantTypeImplicit, r.GrantType)
+	})
+
+	t.Run("error_when_nonce_missing", func(t *testing.T) {
+		mockClientMgr.On("QueryByClientID", mock.Anything, "client-1").Return(validClient(), nil).Once()
+
+		r := newReq()
+		r.Nonce = ""
+		authErr := autherrors.ToAuthLibError(f.ValidateAuthorizationRequest(r))
+		assert.Equal(t, autherrors.ErrInvalidRequest, authErr.Code)
+		assert.Equal(t, "https://example.com/cb", authErr.RedirectURI)
+		assert.True(t, authErr.Fragment)
+	})
+
+	t.Run("error_when_token_requested_without_token_manager", func(t *testing.T) {
+		mockClientMgr.On("QueryByClientID", mock.Anything, "client-1").Return(validClient(), nil).Once()
+
+		r := newReq()
+		r.ResponseType = types.NewResponseType("id_token token")
+		authErr := autherrors.ToAuthLibError(f.ValidateAuthorizationRequest(r))
+		assert.Equal(t, autherrors.ErrUnsupportedResponseType, authErr.Code)
+		assert.True(t, authErr.Fragment)
+	})
+
+	t.Run("error_when_response_mode_is_query", func(t *testing.T) {
+		mockClientMgr.On("QueryByClientID", mock.Anything, "client-1").Return(validClient(), nil).Once()
+
+		r := newReq()
+		r.ResponseMode = types.ResponseModeQuery
+		authErr := autherrors.ToAuthLibError(f.ValidateAuthorizationRequest(r))
+		assert.Equal(t, autherrors.ErrInvalidRequest, authErr.Code)
+		assert.Empty(t, authErr.RedirectURI)
+	})
+
+	t.Run("error_when_validator_fails", func(t *testing.T) {
+		mockClientMgr.On("QueryByClientID", mock.Anything, "client-1").Return(validClient(), nil).Once()
+		mockValidator.On("ValidateAuthorizationRequest", mock.Anything).Return(errors.New("validator error")).Once()
+
+		assert.ErrorContains(t, f.ValidateAuthorizationRequest(newReq()), "validator error")
+	})
+}
+
+func TestFlow_ValidateConsentRequest(t *testing.T) {
+	mockClientMgr := implicitmock.NewMockClientManager(t)
+	mockValidator := implicitmock.NewMockConsentRequestValidator(t)
+	f := New(NewConfig().SetClientManager(mockClientMgr).RegisterExtension(mockValidator))
+
+	mockClientMgr.On("QueryByClientID", mock.Anything, "client-1").Return(validClient(), nil).Once()
+	mockValidator.On("ValidateConsentRequest", mock.Anything).
+		Return(autherrors.ConsentRequiredError().WithRedirectURI("https://example.com/cb")).Once()
+
+	r := newAuthReq(http.MethodGet)
+	r.ClientID = "client-1"
+	r.RedirectURI = "https://example.com/cb"
+	r.ResponseType = types.ResponseTypeIDToken
+	r.Scopes = types.NewScopes([]string{"openid"})
+	r.Nonce = "n-0S6"
+	authErr := autherrors.ToAuthLibError(f.ValidateConsentRequest(r))
+	assert.Equal(t, autherrors.ErrConsentRequired, authErr.Code)
+	assert.True(t, authErr.Fragment)
+}
+
+func TestFlow_AuthorizationResponse(t *testing.T) {
+	newReq := func(rt string) *requests.AuthorizationRequest {
+		r := newAuthReq(http.MethodGet)
+		r.Client = validClient()
+		r.User = &sql.User{UserID: "user-1"}
+		r.RedirectURI = "https://example.com/cb"
+		r.ResponseType = types.NewResponseType(rt)
+		r.State = "xyz"
+		r.Nonce = "n-0S6"
+		r.GrantType = types.GrantTypeImplicit
+		r.Scopes = types.NewScopes([]string{"openid"})
+		return r
+	}
+
+	t.Run("id_token", func(t *testing.T) {
+		f := New(NewConfig().SetIDTokenGenerator(idTokenGen(t)))
+
+		rw := httptest.NewRecorder()
+		require.NoError(t, f.AuthorizationResponse(newReq("id_token"), rw))
+		assert.Equal(t, http.StatusFound, rw.Code)
+
+		fragment := parseFragment(t, rw)
+		assert.Equal(t, "xyz", fragment.Get("state"))
+		assert.Empty(t, fragment.Get("access_token"))
+		assert.Empty(t, fragment.Get("code"))
+
+		claims := parseIDToken(t, fragment.Get("id_token"))
+		assert.Equal(t, "n-0S6", claims["nonce"])
+		assert.Equal(t, "user-1", claims["sub"])
+		assert.NotContains(t, claims, "at_hash")
+		assert.NotContains(t, claims, "c_hash")
+	})
+
+	t.Run("id_token_token", func(t *testing.T) {
+		mockTokenMgr := implicitmock.NewMockTokenManager(t)
+		mockProcessor := implicitmock.NewMockTokenProcessor(t)
+		f := New(NewConfig().
+			SetTokenManager(mockTokenMgr).
+			SetIDTokenGenerator(idTokenGen(t)).
+			RegisterExtension(mockProcessor))
+
+		token := &sql.Token{TokenType: "Bearer", AccessToken: "access-token"}
+		mockTokenMgr.On("New").Return(token).Once()
+		mockTokenMgr.On("Generate", token, mock.MatchedBy(func(r *requests.TokenRequest) bool {
+			return r.GrantType.IsImplicit()
+		}), false).Return(nil).Once()
+		mockProcessor.On("ProcessImplicitToken", mock.Anything, token, mock.Anything).Return(nil).Once()
+		mockTokenMgr.On("Save", mock.Anything, token).Return(nil).Once()
+
+		rw := httptest.NewRecorder()
+		require.NoError(t, f.AuthorizationResponse(newReq("token id_token"), rw))
+
+		fragment := parseFragment(t, rw)
+		assert.Equal(t, "access-token", fragment.Get("access_token"))
+		assert.Equal(t, "Bearer", fragment.Get("token_type"))
+
+		claims := parseIDToken(t, fragment.Get("id_token"))
+		atHash, _ := utils.HalfHash("access-token", jwt.SigningMethodHS256)
+		assert.Equal(t, atHash, claims["at_hash"])
+	})
+
+	t.Run("error_when_user_nil", func(t *testing.T) {
+		f := New(NewConfig())
+		r := newReq("id_token")
+		r.User = nil
+		authErr := autherrors.ToAuthLibError(f.AuthorizationResponse(r, httptest.NewRecorder()))
+		assert.Equal(t, autherrors.ErrAccessDenied, authErr.Code)
+		assert.True(t, authErr.Fragment)
+	})
+
+	t.Run("error_when_gen_token_fails", func(t *testing.T) {
+		mockTokenMgr := implicitmock.NewMockTokenManager(t)
+		f := New(NewConfig().SetTokenManager(mockTokenMgr))
+
+		mockTokenMgr.On("New").Return(nil).Once()
+		assert.ErrorIs(t, f.AuthorizationResponse(newReq("id_token token"), httptest.NewRecorder()), ErrNilToken)
+	})
+
+	t.Run("error_when_id_token_fails", func(t *testing.T) {
+		mockIDTokenGen := implicitmock.NewMockIDTokenGenerator(t)
+		f := New(NewConfig().SetIDTokenGenerator(mockIDTokenGen))
+
+		mockIDTokenGen.On("GenerateIDToken", mock.Anything, mock.Anything).Return("", errors.New("sign error")).Once()
+		assert.ErrorContains(t, f.AuthorizationResponse(newReq("id_token"), httptest.NewRecorder()), "sign error")
+	})
+
+	t.Run("error_when_save_fails", func(t *testing.T) {
+		mockTokenMgr := implicitmock.NewMockTokenManager(t)
+		f := New(NewConfig().SetTokenManager(mockTokenMgr).SetIDTokenGenerator(idTokenGen(t)))
+
+		mockTokenMgr.On("New").Return(&sql.Token{AccessToken: "access-token"}).Once()
+		mockTokenMgr.On("Generate", mock.Anything, mock.Anything, false).Return(nil).Once()
+		mockTokenMgr.On("Save", mock.Anything, mock.Anything).Return(errors.New("db error")).Once()
+		assert.ErrorContains(t, f.AuthorizationResponse(newReq("id_token token"), httptest.NewRecorder()), "db error")
+	})
+}
+
+func TestFlow_ProvideMetadata(t *testing.T) {
+	t.Run("id_token_only", func(t *testing.T) {
+		md := types.Metadata{}
+		New(NewConfig().SetIDTokenGenerator(implicitmock.NewMockIDTokenGenerator(t))).ProvideMetadata(md)
+
+		assert.Equal(t, types.Metadata{
+			types.MetadataResponseTypesSupported: []string{"id_token"},
+			types.MetadataResponseModesSupported: []string{"fragment", "form_post"},
+			types.MetadataGrantTypesSupported:    []string{"implicit"},
+		}, md)
+	})
+
+	t.Run("with_token_manager_and_id_token_generator_metadata", func(t *testing.T) {
+		md := types.Metadata{}
+		New(NewConfig().
+			SetIDTokenGenerator(idTokenGen(t)).
+			SetTokenManager(implicitmock.NewMockTokenManager(t))).ProvideMetadata(md)
+
+		assert.Equal(t, []string{"id_token", "id_token token"}, md[types.MetadataResponseTypesSupported])
+		assert.Equal(t, []string{"public"}, md[types.MetadataSubjectTypesSupported])
+		assert.Equal(t, []string{"HS256"}, md[types.MetadataIDTokenSigningAlgValuesSupported])
+	})
+}
@@ -0,0 +1,64 @@
+package implicit
+
+import (
+	"context"
+
+	"github.com/tniah/authlib/models"
+	authorizationcode "github.com/tniah/authlib/oidc/core/authorization_code"
+	"github.com/tniah/authlib/requests"
+	"github.com/tniah/authlib/types"
+)
+
+// ClientManager handles client lookup at the authorization endpoint.
+type ClientManager interface {
+	// QueryByClientID retrieves the client with the given client_id.
+	// Return (nil, nil) when the client does not exist.
+	QueryByClientID(ctx context.Context, clientID string) (models.Client, error)
+}
+
+// TokenManager generates and persists the access token returned for the
+// "id_token token" response type. A refresh token is never issued from the
+// authorization endpoint.
+type TokenManager interface {
+	// New allocates a blank Token ready to be populated by Generate.
+	New() models.Token
+
+	// Generate populates token with a value, expiry, scopes, and client/user
+	// binding. includeRefreshToken is always false for this flow.
+	Generate(token models.Token, r *requests.TokenRequest, includeRefreshToken bool) error
+
+	// Save persists the token to the backing store.
+	Save(ctx context.Context, token models.Token) error
+}
+
+// IDTokenGenerator signs the ID Token returned from the authorization
+// endpoint. *authorizationcode.Flow satisfies it, so the issuer, lifetime,
+// signing key and extra claims configured for the code flow are reused as-is.
+type IDTokenGenerator interface {
+	GenerateIDToken(ctx context.Context, req *authorizationcode.IDTokenRequest) (string, error)
+}
+
+// MetadataProvider is implemented by an IDTokenGenerator that describes the ID
+// Tokens it signs in the server metadata, such as *authorizationcode.Flow.
+type MetadataProvider interface {
+	ProvideMetadata(md types.Metadata)
+}
+
+// AuthorizationRequestValidator is an extension hook called during
+// ValidateAuthorizationRequest, after the built-in checks pass.
+type AuthorizationRequestValidator interface {
+	ValidateAuthorizationRequest(r *requests.AuthorizationRequest) error
+}
+
+// ConsentRequestValidator is an extension hook called during
+// ValidateConsentRequest, after the built-in checks pass.
+type ConsentRequestValidator interface {
+	ValidateConsentRequest(r *requests.AuthorizationRequest) error
+}
+
+// TokenProcessor is an extension hook called after the access token is
+// generated and before it is saved. It shares its method with the OAuth 2.0
+// implicit flow's TokenProcessor, so one extension can serve both flows.
+type TokenProcessor interface {
+	ProcessImplicitToken(r *requests.AuthorizationRequest, token models.Token, params map[string]interface{}) error
+}
@@ -0,0 +1,107 @@
+// Package frontchannel holds the authorization request checks shared by the
+// OpenID Connect flows that answer in the fragment of the redirect URI: the
+// Implicit Flow (OIDC Core §3.2) and the Hybrid Flow (OIDC Core §3.3).
+package frontchannel
+
+import (
+	"context"
+	"errors"
+
+	autherrors "github.com/tniah/authlib/errors"
+	"github.com/tniah/authlib/models"
+	"github.com/tniah/authlib/requests"
+	"github.com/tniah/authlib/types"
+	"github.com/tniah/authlib/utils"
+)
+
+// ClientManager looks up the client of an authorization request.
+type ClientManager interface {
+	// QueryByClientID retrieves the client with the given client_id.
+	// Return (nil, nil) when the client does not exist.
+	QueryByClientID(ctx context.Context, clientID string) (models.Client, error)
+}
+
+// CheckClient validates client_id and loads the client record into r.Client.
+func CheckClient(r *requests.AuthorizationRequest, mgr ClientManager) error {
+	if err := r.ValidateClientID(true); err != nil {
+		return err
+	}
+
+	client, err := mgr.QueryByClientID(r.Request.Context(), r.ClientID)
+	if err != nil {
+		return err
+	}
+
+	if utils.IsNil(client) {
+		return autherrors.InvalidRequestError().
+			WithDescription("No client was found that matches \"client_id\" value").
+			WithState(r.State)
+	}
+
+	r.Client = client
+	return nil
+}
+
+// ValidateRedirectURI ensures redirect_uri is present and registered for the
+// client. OIDC Core §3.2.2.1 and §3.3.2.1 make redirect_uri mandatory, so
+// there is no fallback to the client's default.
+func ValidateRedirectURI(r *requests.AuthorizationRequest) error {
+	if err := r.ValidateRedirectURI(true); err != nil {
+		return err
+	}
+
+	if allowed := r.Client.CheckRedirectURI(r.RedirectURI); !allowed {
+		return autherrors.InvalidRequestError().
+			WithDescription("\"redirect_uri\" is not supported by client").
+			WithState(r.State)
+	}
+
+	return nil
+}
+
+// ValidateResponseType verifies response_type is one the flow serves, as
+// reported by supported, and that the client is permitted to use it.
+func ValidateResponseType(r *requests.AuthorizationRequest, supported func(types.ResponseType) bool) error {
+	if err := r.ValidateResponseType(true); err != nil {
+		return err
+	}
+
+	if valid := supported(r.ResponseType); !valid {
+		return autherrors.UnsupportedResponseTypeError().WithState(r.State).WithRedirectURI(r.RedirectURI)
+	}
+
+	if allowed := r.Client.CheckResponseType(r.ResponseType); !allowed {
+		return autherrors.UnauthorizedClientError().WithState(r.State).WithRedirectURI(r.RedirectURI)
+	}
+
+	return nil
+}
+
+// ValidateScope filters the requested scopes through the client's allowed
+// list and requires openid to survive the filter (OIDC Core §3.2.2.1,
+// §3.3.2.1).
+func ValidateScope(r *requests.AuthorizationRequest) error {
+	allowed := r.Client.GetAllowedScopes(r.Scopes)
+	if !allowed.ContainOpenID() {
+		return autherrors.InvalidScopeError().
+			WithDescription("\"openid\" scope is required").
+			WithState(r.State).
+			WithRedirectURI(r.RedirectURI)
+	}
+
+	r.Scopes = allowed
+	return nil
+}
+
+// WithFragment marks a redirecting AuthLibError as a fragment redirect. The
+// implicit and hybrid flows answer in the fragment (OIDC Core §3.2.2.6,
+// §3.3.2.6), including errors raised by shared validators and extensions that
+// default to the query.
+func WithFragment(err error) error {
+	var authErr *autherrors.AuthLibError
+	if errors.As(err, &authErr) && authErr.RedirectURI != "" {
+		authErr.WithFragment()
+	}
+
+	return err
+}
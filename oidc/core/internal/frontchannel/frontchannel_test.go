@@ -0,0 +1,169 @@
+package frontchannel
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	autherrors "github.com/tniah/authlib/errors"
+	"github.com/tniah/authlib/integrations/sql"
+	"github.com/tniah/authlib/models"
+	"github.com/tniah/authlib/requests"
+	"github.com/tniah/authlib/types"
+)
+
+type clientManager map[string]models.Client
+
+func (m clientManager) QueryByClientID(_ context.Context, clientID string) (models.Client, error) {
+	return m[clientID], nil
+}
+
+func newAuthReq() *requests.AuthorizationRequest {
+	return &requests.AuthorizationRequest{
+		Request:     httptest.NewRequest(http.MethodGet, "/authorize", nil),
+		RedirectURI: "https://example.com/cb",
+		State:       "xyz",
+	}
+}
+
+func validClient() *sql.Client {
+	return &sql.Client{
+		ClientID:      "client-1",
+		RedirectURIs:  []string{"https://example.com/cb"},
+		ResponseTypes: []string{"id_token", "code id_token"},
+		Scopes:        []string{"openid", "profile"},
+	}
+}
+
+func TestCheckClient(t *testing.T) {
+	mgr := clientManager{"client-1": validClient()}
+
+	t.Run("success", func(t *testing.T) {
+		r := newAuthReq()
+		r.ClientID = "client-1"
+		assert.NoError(t, CheckClient(r, mgr))
+		assert.Equal(t, "client-1", r.Client.GetClientID())
+	})
+
+	t.Run("error_when_missing", func(t *testing.T) {
+		authErr := autherrors.ToAuthLibError(CheckClient(newAuthReq(), mgr))
+		assert.Equal(t, autherrors.ErrInvalidRequest, authErr.Code)
+	})
+
+	t.Run("error_when_unknown", func(t *testing.T) {
+		r := newAuthReq()
+		r.ClientID = "unknown"
+		authErr := autherrors.ToAuthLibError(CheckClient(r, mgr))
+		assert.Equal(t, autherrors.ErrInvalidRequest, authErr.Code)
+		assert.Empty(t, authErr.RedirectURI)
+	})
+}
+
+func TestValidateRedirectURI(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		r := newAuthReq()
+		r.Client = validClient()
+		assert.NoError(t, ValidateRedirectURI(r))
+	})
+
+	t.Run("error_when_missing", func(t *testing.T) {
+		r := newAuthReq()
+		r.Client = validClient()
+		r.RedirectURI = ""
+		authErr := autherrors.ToAuthLibError(ValidateRedirectURI(r))
+		assert.Equal(t, autherrors.ErrInvalidRequest, authErr.Code)
+		assert.Empty(t, r.RedirectURI)
+	})
+
+	t.Run("error_when_uri_not_registered", func(t *testing.T) {
+		r := newAuthReq()
+		r.Client = validClient()
+		r.RedirectURI = "https://evil.example.com/cb"
+		authErr := autherrors.ToAuthLibError(ValidateRedirectURI(r))
+		assert.Equal(t, autherrors.ErrInvalidRequest, authErr.Code)
+		assert.Empty(t, authErr.RedirectURI)
+	})
+}
+
+func TestValidateResponseType(t *testing.T) {
+	supported := func(typ types.ResponseType) bool {
+		return typ.Equal("code id_token") || typ.Equal("code token")
+	}
+	newReq := func(rt string) *requests.AuthorizationRequest {
+		r := newAuthReq()
+		r.Client = validClient()
+		r.ResponseType = types.NewResponseType(rt)
+		return r
+	}
+
+	t.Run("success_in_any_order", func(t *testing.T) {
+		assert.NoError(t, ValidateResponseType(newReq("id_token code"), supported))
+	})
+
+	t.Run("error_when_missing", func(t *testing.T) {
+		authErr := autherrors.ToAuthLibError(ValidateResponseType(newReq(""), supported))
+		assert.Equal(t, autherrors.ErrInvalidRequest, authErr.Code)
+	})
+
+	t.Run("error_when_not_supported", func(t *testing.T) {
+		authErr := autherrors.ToAuthLibError(ValidateResponseType(newReq("code"), supported))
+		assert.Equal(t, autherrors.ErrUnsupportedResponseType, authErr.Code)
+		assert.Equal(t, "https://example.com/cb", authErr.RedirectURI)
+	})
+
+	t.Run("error_when_client_not_allowed", func(t *testing.T) {
+		authErr := autherrors.ToAuthLibError(ValidateResponseType(newReq("code token"), supported))
+		assert.Equal(t, autherrors.ErrUnauthorizedClient, authErr.Code)
+		assert.Equal(t, "https://example.com/cb", authErr.RedirectURI)
+	})
+}
+
+func TestValidateScope(t *testing.T) {
+	newReq := func(scopes ...string) *requests.AuthorizationRequest {
+		r := newAuthReq()
+		r.Client = validClient()
+		r.Scopes = types.NewScopes(scopes)
+		return r
+	}
+
+	t.Run("success_filters_scopes", func(t *testing.T) {
+		r := newReq("openid", "admin")
+		assert.NoError(t, ValidateScope(r))
+		assert.Equal(t, types.NewScopes([]string{"openid"}), r.Scopes)
+	})
+
+	t.Run("error_when_openid_missing", func(t *testing.T) {
+		authErr := autherrors.ToAuthLibError(ValidateScope(newReq("profile")))
+		assert.Equal(t, autherrors.ErrInvalidScope, authErr.Code)
+		assert.Equal(t, "xyz", authErr.State)
+	})
+
+	t.Run("error_when_scope_omitted", func(t *testing.T) {
+		authErr := autherrors.ToAuthLibError(ValidateScope(newReq()))
+		assert.Equal(t, autherrors.ErrInvalidScope, authErr.Code)
+	})
+}
+
+func TestWithFragment(t *testing.T) {
+	t.Run("redirecting_error", func(t *testing.T) {
+		err := WithFragment(autherrors.InvalidScopeError().WithRedirectURI("https://example.com/cb"))
+		assert.True(t, autherrors.ToAuthLibError(err).Fragment)
+	})
+
+	t.Run("non_redirecting_error", func(t *testing.T) {
+		err := WithFragment(autherrors.InvalidRequestError())
+		assert.False(t, autherrors.ToAuthLibError(err).Fragment)
+	})
+
+	t.Run("other_error", func(t *testing.T) {
+		err := errors.New("boom")
+		assert.Equal(t, err, WithFragment(err))
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		assert.NoError(t, WithFragment(nil))
+	})
+}
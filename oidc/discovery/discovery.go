@@ -0,0 +1,121 @@
+// Package discovery implements the OpenID Provider configuration endpoint
+// (OpenID Connect Discovery 1.0 §4). The metadata is generated the same way as
+// the RFC 8414 authorization server metadata, from the components registered
+// on the server, and is served at /.well-known/openid-configuration.
+package discovery
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	autherrors "github.com/tniah/authlib/errors"
+	"github.com/tniah/authlib/rfc8414"
+	"github.com/tniah/authlib/types"
+	"github.com/tniah/authlib/utils"
+)
+
+const (
+	// EndpointNameOpenIDConfiguration is the default endpoint name used to
+	// register the OpenID Provider configuration handler with the server.
+	EndpointNameOpenIDConfiguration = "openid_configuration"
+	// WellKnownPath is the well-known URI suffix of the OpenID Provider
+	// configuration (OpenID Connect Discovery 1.0 §4).
+	WellKnownPath = "/.well-known/openid-configuration"
+)
+
+// ErrIncompleteMetadata is returned when the generated metadata lacks a field
+// required by OpenID Connect Discovery 1.0 §3, e.g. because no OpenID Connect
+// flow is registered or jwks_uri is not set.
+var ErrIncompleteMetadata = errors.New("openid provider metadata is incomplete")
+
+// requiredFields are the OpenID Provider metadata fields that must be present
+// (OpenID Connect Discovery 1.0 §3). token_endpoint is omitted since an
+// implicit-only provider does not have one.
+var requiredFields = []string{
+	types.MetadataIssuer,
+	types.MetadataAuthorizationEndpoint,
+	types.MetadataJWKSURI,
+	types.MetadataResponseTypesSupported,
+	types.MetadataSubjectTypesSupported,
+	types.MetadataIDTokenSigningAlgValuesSupported,
+}
+
+// NewConfig returns an RFC 8414 metadata Config with
+// EndpointNameOpenIDConfiguration as the endpoint name. Chain the rfc8414
+// setters on it to set the issuer, the metadata source and the endpoint URLs,
+// including jwks_uri, userinfo_endpoint and end_session_endpoint.
+func NewConfig() *rfc8414.Config {
+	return rfc8414.NewConfig().SetEndpointName(EndpointNameOpenIDConfiguration)
+}
+
+// OpenIDProviderMetadataFlow implements the OpenID Provider configuration
+// endpoint. It is registered as an endpoint on the server via
+// Server.RegisterEndpoint and dispatched by Server.EndpointResponse when the
+// endpoint name matches.
+type OpenIDProviderMetadataFlow struct {
+	*rfc8414.AuthorizationServerMetadataFlow
+}
+
+// NewOpenIDProviderMetadataFlow creates an OpenIDProviderMetadataFlow from cfg
+// without validating it. Prefer MustOpenIDProviderMetadataFlow for production
+// use.
+func NewOpenIDProviderMetadataFlow(cfg *rfc8414.Config) *OpenIDProviderMetadataFlow {
+	return &OpenIDProviderMetadataFlow{rfc8414.NewAuthorizationServerMetadataFlow(cfg)}
+}
+
+// MustOpenIDProviderMetadataFlow creates an OpenIDProviderMetadataFlow after
+// validating cfg. Returns an error if any required configuration is missing.
+func MustOpenIDProviderMetadataFlow(cfg *rfc8414.Config) (*OpenIDProviderMetadataFlow, error) {
+	if err := cfg.ValidateConfig(); err != nil {
+		return nil, err
+	}
+
+	return NewOpenIDProviderMetadataFlow(cfg), nil
+}
+
+// WellKnownURL returns the URL the configuration must be served at: the issuer
+// with WellKnownPath appended (OpenID Connect Discovery 1.0 §4.1), unlike RFC
+// 8414 which inserts its well-known path before the issuer path.
+func (f *OpenIDProviderMetadataFlow) WellKnownURL() string {
+	u := f.AuthorizationServerMetadataFlow.WellKnownURL()
+	if u == "" {
+		return ""
+	}
+
+	return strings.Replace(u, rfc8414.WellKnownPath, "", 1) + WellKnownPath
+}
+
+// EndpointResponse handles a configuration request and writes the OpenID
+// Provider metadata as a JSON object (OpenID Connect Discovery 1.0 §4.2).
+func (f *OpenIDProviderMetadataFlow) EndpointResponse(r *http.Request, rw http.ResponseWriter) error {
+	if r.Method != http.MethodGet {
+		return autherrors.InvalidRequestError().WithDescription(fmt.Sprintf("unsupported http method \"%s\"", r.Method))
+	}
+
+	md, err := f.Metadata(r)
+	if err != nil {
+		return err
+	}
+
+	return utils.JSONResponse(rw, md, http.StatusOK)
+}
+
+// Metadata builds the OpenID Provider metadata served for r, exactly as the
+// RFC 8414 endpoint does. Returns ErrIncompleteMetadata if a required field is
+// missing.
+func (f *OpenIDProviderMetadataFlow) Metadata(r *http.Request) (types.Metadata, error) {
+	md, err := f.AuthorizationServerMetadataFlow.Metadata(r)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, field := range requiredFields {
+		if _, ok := md[field]; !ok {
+			return nil, fmt.Errorf("%w: missing \"%s\"", ErrIncompleteMetadata, field)
+		}
+	}
+
+	return md, nil
+}
@@ -0,0 +1,126 @@
+package discovery
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tniah/authlib"
+	autherrors "github.com/tniah/authlib/errors"
+	oidcflow "github.com/tniah/authlib/oidc/core/authorization_code"
+	authorizationcode "github.com/tniah/authlib/rfc6749/authorization_code"
+	"github.com/tniah/authlib/rfc8414"
+	"github.com/tniah/authlib/types"
+)
+
+func newServer(t *testing.T) *authlib.Server {
+	t.Helper()
+	oidc, err := oidcflow.Must(oidcflow.NewConfig().
+		SetIssuer("https://as.example.com").
+		SetSigningKey([]byte("secret"), jwt.SigningMethodHS256))
+	require.NoError(t, err)
+
+	srv := authlib.NewServer()
+	srv.RegisterGrant(authorizationcode.New(authorizationcode.NewConfig().RegisterExtension(oidc)))
+	return srv
+}
+
+func newConfig(srv *authlib.Server) *rfc8414.Config {
+	return NewConfig().
+		SetIssuer("https://as.example.com").
+		SetMetadataSource(srv).
+		SetEndpoint(types.MetadataAuthorizationEndpoint, "https://as.example.com/authorize").
+		SetEndpoint(types.MetadataTokenEndpoint, "https://as.example.com/token").
+		SetEndpoint(types.MetadataJWKSURI, "https://as.example.com/jwks").
+		SetEndpoint(types.MetadataUserInfoEndpoint, "https://as.example.com/userinfo").
+		SetEndpoint(types.MetadataEndSessionEndpoint, "https://as.example.com/logout")
+}
+
+func TestMustOpenIDProviderMetadataFlow(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		f, err := MustOpenIDProviderMetadataFlow(newConfig(newServer(t)))
+		assert.NoError(t, err)
+		assert.True(t, f.CheckEndpoint(EndpointNameOpenIDConfiguration))
+	})
+
+	t.Run("error_on_invalid_config", func(t *testing.T) {
+		f, err := MustOpenIDProviderMetadataFlow(NewConfig())
+		assert.Nil(t, f)
+		assert.ErrorIs(t, err, autherrors.ErrMissingIssuer)
+	})
+}
+
+func TestOpenIDProviderMetadataFlow_WellKnownURL(t *testing.T) {
+	f := NewOpenIDProviderMetadataFlow(NewConfig().SetIssuer("https://as.example.com"))
+	assert.Equal(t, "https://as.example.com/.well-known/openid-configuration", f.WellKnownURL())
+
+	f = NewOpenIDProviderMetadataFlow(NewConfig().SetIssuer("https://as.example.com/tenant/"))
+	assert.Equal(t, "https://as.example.com/tenant/.well-known/openid-configuration", f.WellKnownURL())
+}
+
+func TestOpenIDProviderMetadataFlow_Metadata(t *testing.T) {
+	hr := httptest.NewRequest(http.MethodGet, WellKnownPath, nil)
+
+	t.Run("success", func(t *testing.T) {
+		f := NewOpenIDProviderMetadataFlow(newConfig(newServer(t)).
+			SetField(types.MetadataScopesSupported, []string{"openid", "profile", "email"}))
+
+		md, err := f.Metadata(hr)
+		require.NoError(t, err)
+		assert.Equal(t, "https://as.example.com/jwks", md[types.MetadataJWKSURI])
+		assert.Equal(t, "https://as.example.com/userinfo", md[types.MetadataUserInfoEndpoint])
+		assert.Equal(t, "https://as.example.com/logout", md[types.MetadataEndSessionEndpoint])
+		assert.Equal(t, []string{"openid", "profile", "email"}, md[types.MetadataScopesSupported])
+		assert.Equal(t, []string{"public"}, md[types.MetadataSubjectTypesSupported])
+		assert.Equal(t, []string{"HS256"}, md[types.MetadataIDTokenSigningAlgValuesSupported])
+		assert.Contains(t, md[types.MetadataClaimsSupported], "sub")
+	})
+
+	t.Run("error_when_jwks_uri_is_missing", func(t *testing.T) {
+		f := NewOpenIDProviderMetadataFlow(NewConfig().
+			SetIssuer("https://as.example.com").
+			SetMetadataSource(newServer(t)).
+			SetEndpoint(types.MetadataAuthorizationEndpoint, "https://as.example.com/authorize"))
+
+		_, err := f.Metadata(hr)
+		assert.ErrorIs(t, err, ErrIncompleteMetadata)
+		assert.ErrorContains(t, err, types.MetadataJWKSURI)
+	})
+
+	t.Run("error_without_openid_connect_flow", func(t *testing.T) {
+		srv := authlib.NewServer()
+		srv.RegisterGrant(authorizationcode.New(authorizationcode.NewConfig()))
+
+		_, err := NewOpenIDProviderMetadataFlow(newConfig(srv)).Metadata(hr)
+		assert.ErrorIs(t, err, ErrIncompleteMetadata)
+	})
+}
+
+func TestOpenIDProviderMetadataFlow_EndpointResponse(t *testing.T) {
+	srv := newServer(t)
+	f, err := MustOpenIDProviderMetadataFlow(newConfig(srv))
+	require.NoError(t, err)
+	srv.RegisterEndpoint(f)
+
+	t.Run("success", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+		err := srv.EndpointResponse(httptest.NewRequest(http.MethodGet, WellKnownPath, nil), rw, EndpointNameOpenIDConfiguration)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rw.Code)
+
+		var body map[string]interface{}
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &body))
+		assert.Equal(t, "https://as.example.com", body["issuer"])
+		assert.Equal(t, []interface{}{"code"}, body["response_types_supported"])
+		assert.Equal(t, []interface{}{"openid", "address", "email", "phone", "profile"}, body["scopes_supported"])
+	})
+
+	t.Run("error_when_method_is_not_get", func(t *testing.T) {
+		err := f.EndpointResponse(httptest.NewRequest(http.MethodPost, WellKnownPath, nil), httptest.NewRecorder())
+		assert.Equal(t, autherrors.ErrInvalidRequest, autherrors.ToAuthLibError(err).Code)
+	})
+}
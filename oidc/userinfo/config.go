@@ -0,0 +1,140 @@
+// Package userinfo implements the OpenID Connect UserInfo endpoint (OIDC Core
+// §5.3). It accepts bearer access tokens carrying the openid scope and returns
+// the claims about the end-user the token was issued for, as JSON or as a
+// signed and optionally encrypted JWT.
+package userinfo
+
+import (
+	"errors"
+
+	"github.com/tniah/authlib/keys"
+	"github.com/tniah/authlib/oidc/claims"
+	"github.com/tniah/authlib/utils"
+)
+
+// EndpointNameUserInfo is the default endpoint name used to register the
+// UserInfo handler with the server.
+const EndpointNameUserInfo = "userinfo"
+
+var (
+	ErrEmptyEndpointName  = errors.New("endpoint name is empty")
+	ErrNilTokenValidator  = errors.New("token validator is nil")
+	ErrNilClaimsProvider  = errors.New("claims provider is nil")
+	ErrNilClaimsBuilder   = errors.New("claims builder is nil")
+	ErrEmptyIssuer        = errors.New("issuer is empty")
+	ErrNilClientManager   = errors.New("client manager is nil")
+	ErrUnsupportedSignAlg = errors.New("userinfo signing algorithm is not supported")
+)
+
+// Config holds all settings for UserInfoFlow. Use NewConfig to obtain a value
+// with defaults, then chain Set* calls to configure it.
+type Config struct {
+	endpointName   string
+	tokenValidator TokenValidator
+	claimsProvider ClaimsProvider
+	claimsBuilder  *claims.Builder
+	clientManager  ClientManager
+	issuer         string
+	keySet         keys.KeySet
+	encrypter      ResponseEncrypter
+}
+
+// NewConfig returns a Config with EndpointNameUserInfo as the endpoint name,
+// releasing the claims of the standard scopes (OIDC Core §5.4).
+func NewConfig() *Config {
+	return &Config{
+		endpointName:  EndpointNameUserInfo,
+		claimsBuilder: claims.NewBuilder(),
+	}
+}
+
+// SetEndpointName overrides the endpoint name used by CheckEndpoint. Defaults
+// to EndpointNameUserInfo ("userinfo").
+func (cfg *Config) SetEndpointName(name string) *Config {
+	cfg.endpointName = name
+	return cfg
+}
+
+// SetTokenValidator registers the TokenValidator used to validate the access
+// token: an OpaqueTokenValidator, a JWTTokenValidator, or your own.
+func (cfg *Config) SetTokenValidator(v TokenValidator) *Config {
+	cfg.tokenValidator = v
+	return cfg
+}
+
+// SetClaimsProvider registers the ClaimsProvider that returns the claims about
+// the end-user.
+func (cfg *Config) SetClaimsProvider(p ClaimsProvider) *Config {
+	cfg.claimsProvider = p
+	return cfg
+}
+
+// SetClaimsBuilder sets the Builder selecting the claims released for the
+// scopes of the access token. Defaults to claims.NewBuilder(); map custom
+// scopes on it to release custom claims. Use the Builder of the ID Token
+// generator so that both release the same claims.
+func (cfg *Config) SetClaimsBuilder(b *claims.Builder) *Config {
+	cfg.claimsBuilder = b
+	return cfg
+}
+
+// SetClientManager registers the ClientManager used to look up the client of
+// the access token. Required for signed or encrypted responses; without it,
+// every response is plain JSON.
+func (cfg *Config) SetClientManager(mgr ClientManager) *Config {
+	cfg.clientManager = mgr
+	return cfg
+}
+
+// SetIssuer sets the iss claim of signed responses. Required with SetKeySet.
+func (cfg *Config) SetIssuer(issuer string) *Config {
+	cfg.issuer = issuer
+	return cfg
+}
+
+// SetKeySet sets the keys signed responses are signed with, for clients that
+// registered userinfo_signed_response_alg. The response is signed with the
+// current key, which must use the registered algorithm.
+func (cfg *Config) SetKeySet(ks keys.KeySet) *Config {
+	cfg.keySet = ks
+	return cfg
+}
+
+// SetEncrypter registers the ResponseEncrypter used for clients that
+// registered userinfo_encrypted_response_alg. Without it, such clients get a
+// server_error.
+func (cfg *Config) SetEncrypter(e ResponseEncrypter) *Config {
+	cfg.encrypter = e
+	return cfg
+}
+
+// ValidateConfig returns an error if any required configuration is missing.
+// Call this via MustUserInfoFlow rather than directly.
+func (cfg *Config) ValidateConfig() error {
+	if cfg.endpointName == "" {
+		return ErrEmptyEndpointName
+	}
+
+	if utils.IsNil(cfg.tokenValidator) {
+		return ErrNilTokenValidator
+	}
+
+	if utils.IsNil(cfg.claimsProvider) {
+		return ErrNilClaimsProvider
+	}
+
+	if cfg.claimsBuilder == nil {
+		return ErrNilClaimsBuilder
+	}
+
+	signed := !utils.IsNil(cfg.keySet)
+	if signed && cfg.issuer == "" {
+		return ErrEmptyIssuer
+	}
+
+	if (signed || !utils.IsNil(cfg.encrypter)) && utils.IsNil(cfg.clientManager) {
+		return ErrNilClientManager
+	}
+
+	return nil
+}
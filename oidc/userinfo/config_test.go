@@ -0,0 +1,65 @@
+package userinfo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tniah/authlib/keys"
+	mock "github.com/tniah/authlib/mocks/oidc/userinfo"
+	"github.com/tniah/authlib/oidc/claims"
+)
+
+func TestConfig(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		cfg := NewConfig()
+		assert.Equal(t, EndpointNameUserInfo, cfg.endpointName)
+
+		cfg.SetEndpointName("test-endpoint-name").
+			SetTokenValidator(&stubTokenValidator{}).
+			SetClaimsProvider(mock.NewMockClaimsProvider(t)).
+			SetClientManager(mock.NewMockClientManager(t)).
+			SetIssuer("https://op.example.com").
+			SetKeySet(keys.NewStaticKeySet(nil)).
+			SetEncrypter(mock.NewMockResponseEncrypter(t))
+
+		assert.Equal(t, "test-endpoint-name", cfg.endpointName)
+		assert.Equal(t, "https://op.example.com", cfg.issuer)
+		assert.NotNil(t, cfg.tokenValidator)
+		assert.NotNil(t, cfg.claimsProvider)
+		assert.NotNil(t, cfg.clientManager)
+		assert.NotNil(t, cfg.keySet)
+		assert.NotNil(t, cfg.encrypter)
+		require.NoError(t, cfg.ValidateConfig())
+	})
+
+	t.Run("error", func(t *testing.T) {
+		cfg := NewConfig()
+		cfg.SetEndpointName("")
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrEmptyEndpointName)
+
+		cfg.SetEndpointName(EndpointNameUserInfo)
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrNilTokenValidator)
+
+		cfg.SetTokenValidator(&stubTokenValidator{})
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrNilClaimsProvider)
+
+		cfg.SetClaimsProvider(mock.NewMockClaimsProvider(t)).SetClaimsBuilder(nil)
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrNilClaimsBuilder)
+
+		cfg.SetClaimsBuilder(claims.NewBuilder())
+		assert.NoError(t, cfg.ValidateConfig())
+
+		cfg.SetKeySet(keys.NewStaticKeySet(nil))
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrEmptyIssuer)
+
+		cfg.SetIssuer("https://op.example.com")
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrNilClientManager)
+
+		cfg.SetKeySet(nil).SetEncrypter(mock.NewMockResponseEncrypter(t))
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrNilClientManager)
+
+		cfg.SetClientManager(mock.NewMockClientManager(t))
+		assert.NoError(t, cfg.ValidateConfig())
+	})
+}
@@ -0,0 +1,66 @@
+package userinfo
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	autherrors "github.com/tniah/authlib/errors"
+	"github.com/tniah/authlib/utils"
+)
+
+// tokenTypeBearer is the Bearer authorization scheme (RFC 6750 §2.1).
+const tokenTypeBearer = "Bearer"
+
+// Request holds the parsed parameters of a UserInfo request.
+type Request struct {
+	AccessToken string
+
+	Token   *AccessToken
+	Request *http.Request
+}
+
+// NewRequestFromHTTP parses a UserInfo request, taking the access token from
+// the Authorization header (RFC 6750 §2.1) or from the access_token parameter
+// of a form-encoded POST body (RFC 6750 §2.2). Tokens in the query string
+// (RFC 6750 §2.3) are not accepted.
+func NewRequestFromHTTP(r *http.Request) (*Request, error) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		return nil, autherrors.InvalidRequestError().WithDescription(fmt.Sprintf("unsupported http method \"%s\"", r.Method))
+	}
+
+	req := &Request{Request: r}
+
+	header := r.Header.Get("Authorization")
+	if header != "" {
+		scheme, token, ok := strings.Cut(header, " ")
+		if !ok || !strings.EqualFold(scheme, tokenTypeBearer) {
+			return nil, autherrors.InvalidTokenError().WithDescription("authorization scheme must be \"Bearer\"")
+		}
+
+		req.AccessToken = strings.TrimSpace(token)
+	}
+
+	if r.Method == http.MethodPost {
+		if ct, err := utils.ContentType(r); err == nil && ct.IsXWWWFormUrlencoded() {
+			if err = r.ParseForm(); err != nil {
+				return nil, autherrors.InvalidRequestError().WithCause(err)
+			}
+
+			if values, ok := r.PostForm["access_token"]; ok {
+				// RFC 6750 §2: clients must not use more than one method.
+				if header != "" || len(values) > 1 {
+					return nil, autherrors.InvalidRequestError().WithDescription("access token must be sent in exactly one place")
+				}
+
+				req.AccessToken = values[0]
+			}
+		}
+	}
+
+	if req.AccessToken == "" {
+		return nil, autherrors.InvalidTokenError().WithDescription("access token is missing")
+	}
+
+	return req, nil
+}
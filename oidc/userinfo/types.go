@@ -0,0 +1,97 @@
+package userinfo
+
+import (
+	"context"
+	"time"
+
+	"github.com/tniah/authlib/models"
+	"github.com/tniah/authlib/types"
+)
+
+// AccessToken is a validated access token presented at the UserInfo endpoint.
+type AccessToken struct {
+	// Subject is the identifier of the end-user the token was issued for.
+	Subject string
+	// ClientID is the client the token was issued to.
+	ClientID string
+	// Scopes are the scopes granted to the token.
+	Scopes types.Scopes
+	// ExpiresAt is the time the token expires. Zero when unknown.
+	ExpiresAt time.Time
+	// Confirmation is the cnf claim of a sender-constrained token, if any.
+	Confirmation map[string]interface{}
+	// ClaimsRequest are the claims requested individually for the UserInfo
+	// response with the userinfo member of the claims parameter (OIDC Core
+	// §5.5), if any.
+	ClaimsRequest map[string]*types.ClaimRequest
+
+	// Token is the stored token, set by OpaqueTokenValidator.
+	Token models.Token
+	// Claims are the verified claims of a JWT access token, set by
+	// JWTTokenValidator.
+	Claims map[string]interface{}
+}
+
+// TokenValidator validates the access token presented at the UserInfo
+// endpoint. OpaqueTokenValidator looks tokens up with a TokenManager;
+// JWTTokenValidator verifies RFC 9068 JWT access tokens.
+type TokenValidator interface {
+	// ValidateAccessToken returns the access token identified by token.
+	// Return (nil, nil) or an invalid_token error when the token is unknown,
+	// expired or revoked; any other error is answered with server_error.
+	ValidateAccessToken(ctx context.Context, token string) (*AccessToken, error)
+}
+
+// TokenManager looks up stored access tokens. It has the same signature as
+// the rfc7662 TokenManager lookup, so one implementation serves both
+// endpoints.
+type TokenManager interface {
+	// QueryByToken looks up the token by its string value. hint is always
+	// access_token. Returns nil without an error when the token does not
+	// exist or has been revoked.
+	QueryByToken(ctx context.Context, token string, hint types.TokenTypeHint) (models.Token, error)
+}
+
+// ClaimsProvider returns the claims about an end-user (OIDC Core §5.1).
+type ClaimsProvider interface {
+	// GetUserClaims returns the claims of the user identified by userID.
+	// Only the claims the Builder maps to the granted scopes are released,
+	// so the provider may return every claim of the user. Return (nil, nil)
+	// when the user no longer exists.
+	GetUserClaims(ctx context.Context, userID string, scopes types.Scopes) (map[string]interface{}, error)
+}
+
+// ClientManager looks up the client an access token was issued to, to read
+// its UserInfo response registration.
+type ClientManager interface {
+	// QueryByClientID retrieves a client by its client_id. Return (nil, nil)
+	// when it does not exist.
+	QueryByClientID(ctx context.Context, clientID string) (models.Client, error)
+}
+
+// ResponseEncrypter encrypts UserInfo responses to the client (OIDC Core
+// §5.3.2). Implement it with the JWE library and keys of your choice.
+type ResponseEncrypter interface {
+	// Encrypt returns the JWE compact serialization of payload, a signed JWT
+	// or a JSON object, encrypted to client with the key management
+	// algorithm alg and the content encryption algorithm enc.
+	Encrypt(ctx context.Context, client models.Client, payload []byte, alg, enc string) (string, error)
+}
+
+// UserInfoSignedResponseAlgProvider is implemented by clients that can
+// register the userinfo_signed_response_alg metadata (OIDC Dynamic Client
+// Registration §2). When it returns a non-empty value, UserInfo responses to
+// the client are JWTs signed with that algorithm.
+type UserInfoSignedResponseAlgProvider interface {
+	GetUserInfoSignedResponseAlg() string
+}
+
+// UserInfoEncryptedResponseAlgProvider is implemented by clients that can
+// register the userinfo_encrypted_response_alg and
+// userinfo_encrypted_response_enc metadata (OIDC Dynamic Client Registration
+// §2). When the alg is non-empty, UserInfo responses to the client are
+// encrypted; the enc defaults to A128CBC-HS256.
+type UserInfoEncryptedResponseAlgProvider interface {
+	GetUserInfoEncryptedResponseAlg() string
+	GetUserInfoEncryptedResponseEnc() string
+}
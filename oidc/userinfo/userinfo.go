@@ -0,0 +1,269 @@
+package userinfo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	autherrors "github.com/tniah/authlib/errors"
+	"github.com/tniah/authlib/models"
+	"github.com/tniah/authlib/rfc9449"
+	"github.com/tniah/authlib/types"
+	"github.com/tniah/authlib/utils"
+)
+
+// DefaultEncryptedResponseEnc is the content encryption algorithm used when a
+// client registered userinfo_encrypted_response_alg without
+// userinfo_encrypted_response_enc (OIDC Dynamic Client Registration §2).
+const DefaultEncryptedResponseEnc = "A128CBC-HS256"
+
+// UserInfoFlow implements the OpenID Connect UserInfo endpoint. It is
+// registered as an endpoint on the server via Server.RegisterEndpoint and
+// dispatched by Server.EndpointResponse when the endpoint name matches.
+type UserInfoFlow struct {
+	*Config
+}
+
+// NewUserInfoFlow creates a UserInfoFlow from cfg without validating it.
+// Prefer MustUserInfoFlow for production use.
+func NewUserInfoFlow(cfg *Config) *UserInfoFlow {
+	return &UserInfoFlow{cfg}
+}
+
+// MustUserInfoFlow creates a UserInfoFlow after validating cfg. Returns an
+// error if any required configuration is missing.
+func MustUserInfoFlow(cfg *Config) (*UserInfoFlow, error) {
+	if err := cfg.ValidateConfig(); err != nil {
+		return nil, err
+	}
+
+	return NewUserInfoFlow(cfg), nil
+}
+
+// CheckEndpoint reports whether name matches the configured endpoint name.
+// The server calls this to route requests to the correct registered endpoint.
+func (f *UserInfoFlow) CheckEndpoint(name string) bool {
+	if f.endpointName == "" {
+		return false
+	}
+
+	return name == f.endpointName
+}
+
+// ProvideMetadata adds userinfo_signing_alg_values_supported, the algorithms
+// of the key set, to the OpenID Provider metadata (OpenID Connect Discovery
+// 1.0 §3). The userinfo_endpoint URL is set on the discovery Config.
+func (f *UserInfoFlow) ProvideMetadata(md types.Metadata) {
+	if utils.IsNil(f.keySet) {
+		return
+	}
+
+	keys, err := f.keySet.PublicKeys(context.Background())
+	if err != nil {
+		return
+	}
+
+	for _, k := range keys {
+		md.Add(types.MetadataUserInfoSigningAlgValuesSupported, k.Method().Alg())
+	}
+}
+
+// EndpointResponse handles a UserInfo request (OIDC Core §5.3.1). It validates
+// the access token, checks that it grants the openid scope, and writes the
+// claims about the end-user as JSON, or as a signed and optionally encrypted
+// JWT when the client registered it (OIDC Core §5.3.2).
+func (f *UserInfoFlow) EndpointResponse(r *http.Request, rw http.ResponseWriter) error {
+	req, err := NewRequestFromHTTP(r)
+	if err != nil {
+		return err
+	}
+
+	if err = f.authenticateToken(req); err != nil {
+		return err
+	}
+
+	claims, err := f.userClaims(req)
+	if err != nil {
+		return err
+	}
+
+	client, err := f.client(req)
+	if err != nil {
+		return err
+	}
+
+	signAlg, encAlg, enc := responseAlgs(client)
+	if signAlg == "" && encAlg == "" {
+		return utils.JSONResponse(rw, claims, http.StatusOK)
+	}
+
+	payload, err := f.responsePayload(req, client, claims, signAlg, encAlg, enc)
+	if err != nil {
+		return err
+	}
+
+	rw.Header().Set("Content-Type", types.ContentTypeJWT.String())
+	rw.Header().Set("Cache-Control", "no-store")
+	rw.Header().Set("Pragma", "no-cache")
+	rw.WriteHeader(http.StatusOK)
+	_, err = rw.Write(payload)
+	return err
+}
+
+// authenticateToken validates the access token and checks that it was issued
+// for an end-user with the openid scope (OIDC Core §5.3).
+func (f *UserInfoFlow) authenticateToken(r *Request) error {
+	token, err := f.tokenValidator.ValidateAccessToken(r.Request.Context(), r.AccessToken)
+	if err != nil {
+		return autherrors.ToAuthLibError(err)
+	}
+
+	if token == nil {
+		return autherrors.InvalidTokenError().WithDescription("access token is unknown")
+	}
+
+	// RFC 9449 §7.2: a DPoP-bound token must not be accepted as a bearer
+	// token.
+	if _, ok := token.Confirmation[rfc9449.ConfirmationJKT]; ok {
+		return autherrors.InvalidTokenError().WithDescription("access token is bound to a DPoP key")
+	}
+
+	if token.Subject == "" {
+		return autherrors.InvalidTokenError().WithDescription("access token was not issued for an end-user")
+	}
+
+	if !token.Scopes.ContainOpenID() {
+		return autherrors.InsufficientScopeError().WithDescription("access token does not grant the \"openid\" scope")
+	}
+
+	r.Token = token
+	return nil
+}
+
+// userClaims returns the claims of the end-user released for the granted
+// scopes and the claims requested for the UserInfo response with the claims
+// parameter. sub is always the subject of the access token (OIDC Core
+// §5.3.2).
+func (f *UserInfoFlow) userClaims(r *Request) (map[string]interface{}, error) {
+	claims, err := f.claimsProvider.GetUserClaims(r.Request.Context(), r.Token.Subject, r.Token.Scopes)
+	if err != nil {
+		return nil, autherrors.ToAuthLibError(err)
+	}
+
+	if claims == nil {
+		return nil, autherrors.InvalidTokenError().WithDescription("end-user is unknown")
+	}
+
+	claims = f.claimsBuilder.BuildRequested(claims, r.Token.Scopes, r.Token.ClaimsRequest)
+	claims["sub"] = r.Token.Subject
+	return claims, nil
+}
+
+// client returns the client the access token was issued to, or nil when no
+// ClientManager is set.
+func (f *UserInfoFlow) client(r *Request) (models.Client, error) {
+	if utils.IsNil(f.clientManager) {
+		return nil, nil
+	}
+
+	client, err := f.clientManager.QueryByClientID(r.Request.Context(), r.Token.ClientID)
+	if err != nil {
+		return nil, autherrors.ToAuthLibError(err)
+	}
+
+	if utils.IsNil(client) {
+		return nil, autherrors.InvalidTokenError().WithDescription("client of the access token is unknown")
+	}
+
+	return client, nil
+}
+
+// responsePayload signs claims with signAlg, then encrypts the JWT, or the
+// JSON claims when the response is not signed, with encAlg and enc.
+func (f *UserInfoFlow) responsePayload(r *Request, client models.Client, claims map[string]interface{}, signAlg, encAlg, enc string) ([]byte, error) {
+	ctx := r.Request.Context()
+
+	var (
+		payload []byte
+		err     error
+	)
+
+	if signAlg != "" {
+		payload, err = f.sign(ctx, client, claims, signAlg)
+	} else {
+		payload, err = json.Marshal(claims)
+	}
+	if err != nil {
+		return nil, autherrors.ToAuthLibError(err)
+	}
+
+	if encAlg == "" {
+		return payload, nil
+	}
+
+	if utils.IsNil(f.encrypter) {
+		return nil, autherrors.InternalServerError().WithDescription("encrypted userinfo responses are not supported")
+	}
+
+	jwe, err := f.encrypter.Encrypt(ctx, client, payload, encAlg, enc)
+	if err != nil {
+		return nil, autherrors.ToAuthLibError(err)
+	}
+
+	return []byte(jwe), nil
+}
+
+// sign returns claims as a JWT signed with the current key of the key set,
+// with the iss and aud claims required of signed responses (OIDC Core
+// §5.3.2).
+func (f *UserInfoFlow) sign(ctx context.Context, client models.Client, claims map[string]interface{}, alg string) ([]byte, error) {
+	if utils.IsNil(f.keySet) {
+		return nil, fmt.Errorf("%w: \"%s\"", ErrUnsupportedSignAlg, alg)
+	}
+
+	key, err := f.keySet.SigningKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if key.Method().Alg() != alg {
+		return nil, fmt.Errorf("%w: \"%s\"", ErrUnsupportedSignAlg, alg)
+	}
+
+	signed := make(utils.JWTClaim, len(claims)+2)
+	for k, v := range claims {
+		signed[k] = v
+	}
+
+	signed["iss"] = f.issuer
+	signed["aud"] = client.GetClientID()
+
+	token, err := key.JWTToken().Generate(signed, utils.JWTHeader{})
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(token), nil
+}
+
+// responseAlgs returns the UserInfo response algorithms registered by client.
+func responseAlgs(client models.Client) (signAlg, encAlg, enc string) {
+	if utils.IsNil(client) {
+		return "", "", ""
+	}
+
+	if p, ok := client.(UserInfoSignedResponseAlgProvider); ok {
+		signAlg = p.GetUserInfoSignedResponseAlg()
+	}
+
+	if p, ok := client.(UserInfoEncryptedResponseAlgProvider); ok {
+		if encAlg = p.GetUserInfoEncryptedResponseAlg(); encAlg != "" {
+			if enc = p.GetUserInfoEncryptedResponseEnc(); enc == "" {
+				enc = DefaultEncryptedResponseEnc
+			}
+		}
+	}
+
+	return signAlg, encAlg, enc
+}
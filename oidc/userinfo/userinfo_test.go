@@ -0,0 +1,351 @@
+package userinfo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	autherrors "github.com/tniah/authlib/errors"
+	"github.com/tniah/authlib/integrations/sql"
+	"github.com/tniah/authlib/keys"
+	mockuserinfo "github.com/tniah/authlib/mocks/oidc/userinfo"
+	"github.com/tniah/authlib/oidc/claims"
+	"github.com/tniah/authlib/types"
+)
+
+const testIssuer = "https://op.example.com"
+
+// stubTokenValidator returns a fixed access token or error for "my-token".
+type stubTokenValidator struct {
+	token *AccessToken
+	err   error
+}
+
+func (v *stubTokenValidator) ValidateAccessToken(_ context.Context, token string) (*AccessToken, error) {
+	if token != "my-token" {
+		return nil, autherrors.InvalidTokenError()
+	}
+
+	return v.token, v.err
+}
+
+func newAccessToken(scopes ...string) *AccessToken {
+	return &AccessToken{
+		Subject:  uuid.NewString(),
+		ClientID: uuid.NewString(),
+		Scopes:   types.NewScopes(scopes),
+	}
+}
+
+func newUserInfoRequest(token string) *http.Request {
+	r := httptest.NewRequest(http.MethodGet, "/userinfo", nil)
+	r.Header.Set("Authorization", "Bearer "+token)
+	return r
+}
+
+func userClaims() map[string]interface{} {
+	return map[string]interface{}{
+		"sub":          "ignored",
+		"name":         "Jane Doe",
+		"email":        "jane@example.com",
+		"phone_number": "+1 555 0100",
+		"tenant":       "acme",
+	}
+}
+
+func TestNewRequestFromHTTP(t *testing.T) {
+	t.Run("authorization header", func(t *testing.T) {
+		r := newUserInfoRequest("my-token")
+		req, err := NewRequestFromHTTP(r)
+		require.NoError(t, err)
+		assert.Equal(t, "my-token", req.AccessToken)
+
+		r.Header.Set("Authorization", "bearer my-token")
+		req, err = NewRequestFromHTTP(r)
+		require.NoError(t, err)
+		assert.Equal(t, "my-token", req.AccessToken)
+	})
+
+	t.Run("form body", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/userinfo", strings.NewReader("access_token=my-token"))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req, err := NewRequestFromHTTP(r)
+		require.NoError(t, err)
+		assert.Equal(t, "my-token", req.AccessToken)
+	})
+
+	t.Run("token in header and body", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/userinfo", strings.NewReader("access_token=my-token"))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		r.Header.Set("Authorization", "Bearer my-token")
+		_, err := NewRequestFromHTTP(r)
+		assert.ErrorIs(t, autherrors.ToAuthLibError(err).Code, autherrors.ErrInvalidRequest)
+	})
+
+	t.Run("query string is not accepted", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/userinfo?access_token=my-token", nil)
+		_, err := NewRequestFromHTTP(r)
+		assert.ErrorIs(t, autherrors.ToAuthLibError(err).Code, autherrors.ErrInvalidToken)
+	})
+
+	t.Run("other authorization scheme", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/userinfo", nil)
+		r.Header.Set("Authorization", "DPoP my-token")
+		_, err := NewRequestFromHTTP(r)
+		assert.ErrorIs(t, autherrors.ToAuthLibError(err).Code, autherrors.ErrInvalidToken)
+	})
+
+	t.Run("unsupported method", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPut, "/userinfo", nil)
+		_, err := NewRequestFromHTTP(r)
+		assert.ErrorIs(t, autherrors.ToAuthLibError(err).Code, autherrors.ErrInvalidRequest)
+	})
+}
+
+func TestUserInfoFlow_EndpointResponse(t *testing.T) {
+	key, err := keys.GenerateKey(jwt.SigningMethodES256, "key-1")
+	require.NoError(t, err)
+
+	newFlow := func(t *testing.T, at *AccessToken, claims map[string]interface{}) (*UserInfoFlow, *Config) {
+		validator := &stubTokenValidator{token: at}
+
+		provider := mockuserinfo.NewMockClaimsProvider(t)
+		if claims != nil {
+			provider.On("GetUserClaims", mock.Anything, at.Subject, at.Scopes).Return(claims, nil).Maybe()
+		}
+
+		cfg := NewConfig().SetTokenValidator(validator).SetClaimsProvider(provider)
+		return NewUserInfoFlow(cfg), cfg
+	}
+
+	withClient := func(t *testing.T, cfg *Config, client *sql.Client) {
+		clientMgr := mockuserinfo.NewMockClientManager(t)
+		clientMgr.On("QueryByClientID", mock.Anything, client.ClientID).Return(client, nil).Once()
+		cfg.SetClientManager(clientMgr)
+	}
+
+	t.Run("claims of the granted scopes", func(t *testing.T) {
+		at := newAccessToken("openid", "profile", "email")
+		h, _ := newFlow(t, at, userClaims())
+
+		rw := httptest.NewRecorder()
+		require.NoError(t, h.EndpointResponse(newUserInfoRequest("my-token"), rw))
+		assert.Equal(t, http.StatusOK, rw.Code)
+		assert.Equal(t, types.ContentTypeJSON.String(), rw.Header().Get("Content-Type"))
+		assert.Equal(t, "no-store", rw.Header().Get("Cache-Control"))
+
+		var body map[string]interface{}
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &body))
+		assert.Equal(t, at.Subject, body["sub"])
+		assert.Equal(t, "Jane Doe", body["name"])
+		assert.Equal(t, "jane@example.com", body["email"])
+		assert.NotContains(t, body, "phone_number")
+		assert.NotContains(t, body, "tenant", "claims of no granted scope are not released")
+	})
+
+	t.Run("custom scope claims", func(t *testing.T) {
+		at := newAccessToken("openid", "tenant")
+		h, cfg := newFlow(t, at, userClaims())
+		cfg.SetClaimsBuilder(claims.NewBuilder().SetScopeClaims("tenant", "tenant"))
+
+		rw := httptest.NewRecorder()
+		require.NoError(t, h.EndpointResponse(newUserInfoRequest("my-token"), rw))
+
+		var body map[string]interface{}
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &body))
+		assert.Equal(t, map[string]interface{}{"sub": at.Subject, "tenant": "acme"}, body)
+	})
+
+	t.Run("requested claims", func(t *testing.T) {
+		at := newAccessToken("openid", "email")
+		at.ClaimsRequest = map[string]*types.ClaimRequest{"phone_number": {Essential: true}, "tenant": nil}
+		h, _ := newFlow(t, at, userClaims())
+
+		rw := httptest.NewRecorder()
+		require.NoError(t, h.EndpointResponse(newUserInfoRequest("my-token"), rw))
+
+		var body map[string]interface{}
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &body))
+		assert.Equal(t, map[string]interface{}{
+			"sub":          at.Subject,
+			"email":        "jane@example.com",
+			"phone_number": "+1 555 0100",
+		}, body, "unmapped claims are not released")
+	})
+
+	t.Run("openid scope is required", func(t *testing.T) {
+		at := newAccessToken("profile")
+		h, _ := newFlow(t, at, nil)
+
+		err := h.EndpointResponse(newUserInfoRequest("my-token"), httptest.NewRecorder())
+		authErr := autherrors.ToAuthLibError(err)
+		assert.ErrorIs(t, authErr.Code, autherrors.ErrInsufficientScope)
+		_, header, _ := authErr.Response()
+		assert.Contains(t, header.Get("WWW-Authenticate"), `Bearer error="insufficient_scope"`)
+	})
+
+	t.Run("token without end-user", func(t *testing.T) {
+		at := newAccessToken("openid")
+		at.Subject = ""
+		h, _ := newFlow(t, at, nil)
+
+		err := h.EndpointResponse(newUserInfoRequest("my-token"), httptest.NewRecorder())
+		assert.ErrorIs(t, autherrors.ToAuthLibError(err).Code, autherrors.ErrInvalidToken)
+	})
+
+	t.Run("dpop bound token", func(t *testing.T) {
+		at := newAccessToken("openid")
+		at.Confirmation = map[string]interface{}{"jkt": "thumbprint"}
+		h, _ := newFlow(t, at, nil)
+
+		err := h.EndpointResponse(newUserInfoRequest("my-token"), httptest.NewRecorder())
+		assert.ErrorIs(t, autherrors.ToAuthLibError(err).Code, autherrors.ErrInvalidToken)
+	})
+
+	t.Run("unknown token", func(t *testing.T) {
+		h, _ := newFlow(t, nil, nil)
+
+		err := h.EndpointResponse(newUserInfoRequest("my-token"), httptest.NewRecorder())
+		assert.ErrorIs(t, autherrors.ToAuthLibError(err).Code, autherrors.ErrInvalidToken)
+	})
+
+	t.Run("token lookup fails", func(t *testing.T) {
+		h := NewUserInfoFlow(NewConfig().SetTokenValidator(&stubTokenValidator{err: errors.New("db down")}))
+
+		err := h.EndpointResponse(newUserInfoRequest("my-token"), httptest.NewRecorder())
+		assert.ErrorIs(t, autherrors.ToAuthLibError(err).Code, autherrors.ErrServerError)
+	})
+
+	t.Run("unknown end-user", func(t *testing.T) {
+		at := newAccessToken("openid")
+		h, _ := newFlow(t, at, nil)
+		h.claimsProvider.(*mockuserinfo.MockClaimsProvider).On("GetUserClaims", mock.Anything, at.Subject, at.Scopes).Return(nil, nil).Once()
+
+		err := h.EndpointResponse(newUserInfoRequest("my-token"), httptest.NewRecorder())
+		assert.ErrorIs(t, autherrors.ToAuthLibError(err).Code, autherrors.ErrInvalidToken)
+	})
+
+	t.Run("signed response", func(t *testing.T) {
+		at := newAccessToken("openid", "email")
+		h, cfg := newFlow(t, at, userClaims())
+		cfg.SetIssuer(testIssuer).SetKeySet(keys.NewStaticKeySet(key))
+		withClient(t, cfg, &sql.Client{ClientID: at.ClientID, UserInfoSignedResponseAlg: "ES256"})
+
+		rw := httptest.NewRecorder()
+		require.NoError(t, h.EndpointResponse(newUserInfoRequest("my-token"), rw))
+		assert.Equal(t, types.ContentTypeJWT.String(), rw.Header().Get("Content-Type"))
+		assert.Equal(t, "no-store", rw.Header().Get("Cache-Control"))
+
+		claims := jwt.MapClaims{}
+		token, err := jwt.ParseWithClaims(rw.Body.String(), claims, func(*jwt.Token) (interface{}, error) {
+			return key.Public(), nil
+		}, jwt.WithValidMethods([]string{"ES256"}), jwt.WithIssuer(testIssuer), jwt.WithAudience(at.ClientID))
+		require.NoError(t, err)
+		assert.Equal(t, "key-1", token.Header["kid"])
+		assert.Equal(t, at.Subject, claims["sub"])
+		assert.Equal(t, "jane@example.com", claims["email"])
+		assert.NotContains(t, claims, "name")
+	})
+
+	t.Run("signed response with unsupported algorithm", func(t *testing.T) {
+		at := newAccessToken("openid")
+		h, cfg := newFlow(t, at, userClaims())
+		cfg.SetIssuer(testIssuer).SetKeySet(keys.NewStaticKeySet(key))
+		withClient(t, cfg, &sql.Client{ClientID: at.ClientID, UserInfoSignedResponseAlg: "RS256"})
+
+		err := h.EndpointResponse(newUserInfoRequest("my-token"), httptest.NewRecorder())
+		authErr := autherrors.ToAuthLibError(err)
+		assert.ErrorIs(t, authErr.Code, autherrors.ErrServerError)
+		assert.ErrorIs(t, authErr.Cause, ErrUnsupportedSignAlg)
+	})
+
+	t.Run("signed and encrypted response", func(t *testing.T) {
+		at := newAccessToken("openid")
+		h, cfg := newFlow(t, at, userClaims())
+		client := &sql.Client{ClientID: at.ClientID, UserInfoSignedResponseAlg: "ES256", UserInfoEncryptedResponseAlg: "RSA-OAEP-256"}
+		withClient(t, cfg, client)
+
+		encrypter := mockuserinfo.NewMockResponseEncrypter(t)
+		encrypter.On("Encrypt", mock.Anything, client, mock.MatchedBy(func(payload []byte) bool {
+			return strings.Count(string(payload), ".") == 2
+		}), "RSA-OAEP-256", DefaultEncryptedResponseEnc).Return("jwe", nil).Once()
+		cfg.SetIssuer(testIssuer).SetKeySet(keys.NewStaticKeySet(key)).SetEncrypter(encrypter)
+
+		rw := httptest.NewRecorder()
+		require.NoError(t, h.EndpointResponse(newUserInfoRequest("my-token"), rw))
+		assert.Equal(t, types.ContentTypeJWT.String(), rw.Header().Get("Content-Type"))
+		assert.Equal(t, "jwe", rw.Body.String())
+	})
+
+	t.Run("encrypted response", func(t *testing.T) {
+		at := newAccessToken("openid")
+		h, cfg := newFlow(t, at, userClaims())
+		client := &sql.Client{ClientID: at.ClientID, UserInfoEncryptedResponseAlg: "ECDH-ES", UserInfoEncryptedResponseEnc: "A256GCM"}
+		withClient(t, cfg, client)
+
+		encrypter := mockuserinfo.NewMockResponseEncrypter(t)
+		encrypter.On("Encrypt", mock.Anything, client, mock.MatchedBy(func(payload []byte) bool {
+			var claims map[string]interface{}
+			return json.Unmarshal(payload, &claims) == nil && claims["sub"] == at.Subject
+		}), "ECDH-ES", "A256GCM").Return("jwe", nil).Once()
+		cfg.SetEncrypter(encrypter)
+
+		rw := httptest.NewRecorder()
+		require.NoError(t, h.EndpointResponse(newUserInfoRequest("my-token"), rw))
+		assert.Equal(t, "jwe", rw.Body.String())
+	})
+
+	t.Run("encrypted response without encrypter", func(t *testing.T) {
+		at := newAccessToken("openid")
+		h, cfg := newFlow(t, at, userClaims())
+		withClient(t, cfg, &sql.Client{ClientID: at.ClientID, UserInfoEncryptedResponseAlg: "ECDH-ES"})
+
+		err := h.EndpointResponse(newUserInfoRequest("my-token"), httptest.NewRecorder())
+		assert.ErrorIs(t, autherrors.ToAuthLibError(err).Code, autherrors.ErrServerError)
+	})
+
+	t.Run("unknown client", func(t *testing.T) {
+		at := newAccessToken("openid")
+		h, cfg := newFlow(t, at, userClaims())
+		clientMgr := mockuserinfo.NewMockClientManager(t)
+		clientMgr.On("QueryByClientID", mock.Anything, at.ClientID).Return(nil, nil).Once()
+		cfg.SetClientManager(clientMgr)
+
+		err := h.EndpointResponse(newUserInfoRequest("my-token"), httptest.NewRecorder())
+		assert.ErrorIs(t, autherrors.ToAuthLibError(err).Code, autherrors.ErrInvalidToken)
+	})
+}
+
+func TestUserInfoFlow_CheckEndpoint(t *testing.T) {
+	h := NewUserInfoFlow(NewConfig())
+	assert.True(t, h.CheckEndpoint(EndpointNameUserInfo))
+	assert.False(t, h.CheckEndpoint("my-endpoint"))
+
+	h = NewUserInfoFlow(NewConfig().SetEndpointName(""))
+	assert.False(t, h.CheckEndpoint(""))
+}
+
+func TestUserInfoFlow_ProvideMetadata(t *testing.T) {
+	active, err := keys.GenerateKey(jwt.SigningMethodES256)
+	require.NoError(t, err)
+
+	next, err := keys.GenerateKey(jwt.SigningMethodEdDSA)
+	require.NoError(t, err)
+
+	md := types.Metadata{}
+	NewUserInfoFlow(NewConfig()).ProvideMetadata(md)
+	assert.NotContains(t, md, types.MetadataUserInfoSigningAlgValuesSupported)
+
+	cfg := NewConfig().SetKeySet(keys.NewStaticKeySet(active).SetNextKeys(next))
+	NewUserInfoFlow(cfg).ProvideMetadata(md)
+	assert.Equal(t, []string{"ES256", "EdDSA"}, md[types.MetadataUserInfoSigningAlgValuesSupported])
+}
@@ -0,0 +1,184 @@
+package userinfo
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	autherrors "github.com/tniah/authlib/errors"
+	"github.com/tniah/authlib/keys"
+	"github.com/tniah/authlib/models"
+	authorizationcode "github.com/tniah/authlib/oidc/core/authorization_code"
+	"github.com/tniah/authlib/types"
+	"github.com/tniah/authlib/utils"
+)
+
+var (
+	errInvalidTokenType = errors.New("token is not a JWT access token")
+	errUnknownKey       = errors.New("token is not signed with a known key")
+)
+
+// OpaqueTokenValidator validates access tokens by looking them up with a
+// TokenManager, as issued by the rfc6750 opaque token generator or any other
+// token stored by the authorization server.
+type OpaqueTokenValidator struct {
+	tokenManager TokenManager
+	now          func() time.Time
+}
+
+// NewOpaqueTokenValidator returns an OpaqueTokenValidator looking tokens up
+// with mgr.
+func NewOpaqueTokenValidator(mgr TokenManager) *OpaqueTokenValidator {
+	return &OpaqueTokenValidator{tokenManager: mgr, now: time.Now}
+}
+
+// ValidateAccessToken looks token up and rejects it with invalid_token when it
+// is unknown, is not an access token, or has expired. The cnf claim and the
+// userinfo member of the claims parameter are read from the extra data of
+// tokens implementing models.ExtendableToken.
+func (v *OpaqueTokenValidator) ValidateAccessToken(ctx context.Context, token string) (*AccessToken, error) {
+	tok, err := v.tokenManager.QueryByToken(ctx, token, types.TokenTypeHintAccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	// The lookup may also match refresh tokens; only the access token of the
+	// record grants access to the UserInfo endpoint.
+	if utils.IsNil(tok) || tok.GetAccessToken() != token {
+		return nil, autherrors.InvalidTokenError().WithDescription("access token is unknown")
+	}
+
+	expiresAt := tok.GetIssuedAt().Add(tok.GetAccessTokenExpiresIn())
+	if expiresAt.Before(v.now().UTC().Round(time.Second)) {
+		return nil, autherrors.InvalidTokenError().WithDescription("access token has expired")
+	}
+
+	at := &AccessToken{
+		Subject:   tok.GetUserID(),
+		ClientID:  tok.GetClientID(),
+		Scopes:    tok.GetScopes(),
+		ExpiresAt: expiresAt,
+		Token:     tok,
+	}
+
+	if ext, ok := tok.(models.ExtendableToken); ok {
+		extra := ext.GetExtraData()
+		at.Confirmation, _ = extra["cnf"].(map[string]interface{})
+		if s, ok := extra[authorizationcode.ExtraUserInfoClaims].(string); ok {
+			if cr, err := types.ParseClaimsRequest(s); err == nil {
+				at.ClaimsRequest = cr.UserInfo
+			}
+		}
+	}
+
+	return at, nil
+}
+
+// JWTTokenValidator validates JWT access tokens (RFC 9068) by verifying their
+// signature with the public keys of a keys.KeySet, typically the one the
+// rfc9068 generator signs with.
+type JWTTokenValidator struct {
+	keySet    keys.KeySet
+	issuer    string
+	audiences []string
+	leeway    time.Duration
+}
+
+// NewJWTTokenValidator returns a JWTTokenValidator accepting tokens issued by
+// issuer, signed with a public key of ks, and whose aud claim contains one of
+// audiences, the identifiers of the UserInfo endpoint as a resource server.
+// Every token is rejected when audiences is empty, since the resource server
+// must check aud (RFC 9068 §4).
+func NewJWTTokenValidator(ks keys.KeySet, issuer string, audiences []string) *JWTTokenValidator {
+	return &JWTTokenValidator{keySet: ks, issuer: issuer, audiences: audiences}
+}
+
+// SetAudiences replaces the accepted aud values.
+func (v *JWTTokenValidator) SetAudiences(audiences ...string) *JWTTokenValidator {
+	v.audiences = audiences
+	return v
+}
+
+// SetLeeway sets the clock skew tolerated when checking exp, nbf and iat.
+func (v *JWTTokenValidator) SetLeeway(leeway time.Duration) *JWTTokenValidator {
+	v.leeway = leeway
+	return v
+}
+
+// ValidateAccessToken verifies the typ header (at+jwt), the signature with the
+// key named by kid, the issuer, the expiry and the audience of token (RFC 9068
+// §4). JWT access tokens cannot be revoked; keep their lifetime
+// short, or use an OpaqueTokenValidator when revocation matters.
+func (v *JWTTokenValidator) ValidateAccessToken(ctx context.Context, token string) (*AccessToken, error) {
+	if len(v.audiences) == 0 {
+		return nil, autherrors.InvalidTokenError().WithDescription("no audience is accepted")
+	}
+
+	var keyErr error
+	keyFunc := func(t *jwt.Token) (interface{}, error) {
+		key, err := v.verificationKey(ctx, t)
+		keyErr = err
+		return key, err
+	}
+
+	parser := jwt.NewParser(
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuer(v.issuer),
+		jwt.WithAudience(v.audiences...),
+		jwt.WithLeeway(v.leeway),
+	)
+
+	claims := jwt.MapClaims{}
+	if _, err := parser.ParseWithClaims(token, claims, keyFunc); err != nil {
+		// Failing to load the key set is not the client's fault.
+		if keyErr != nil && !errors.Is(keyErr, errUnknownKey) && !errors.Is(keyErr, errInvalidTokenType) {
+			return nil, keyErr
+		}
+
+		return nil, autherrors.InvalidTokenError().WithDescription("access token is invalid").WithCause(err)
+	}
+
+	sub, _ := claims.GetSubject()
+	clientID, _ := claims["client_id"].(string)
+	scope, _ := claims["scope"].(string)
+	exp, _ := claims.GetExpirationTime()
+	cnf, _ := claims["cnf"].(map[string]interface{})
+
+	return &AccessToken{
+		Subject:      sub,
+		ClientID:     clientID,
+		Scopes:       types.NewScopes(strings.Fields(scope)),
+		ExpiresAt:    exp.Time,
+		Confirmation: cnf,
+		Claims:       claims,
+	}, nil
+}
+
+// verificationKey checks that t is a JWT access token and returns the public
+// key named by its kid. The header alg must be the algorithm of that key,
+// which rules out "none" and algorithm confusion.
+func (v *JWTTokenValidator) verificationKey(ctx context.Context, t *jwt.Token) (interface{}, error) {
+	// RFC 9068 §4: the typ header must be at+jwt, with or without the
+	// application/ prefix, compared case-insensitively.
+	typ, _ := t.Header["typ"].(string)
+	typ = strings.TrimPrefix(strings.ToLower(typ), "application/")
+	if typ != "at+jwt" {
+		return nil, errInvalidTokenType
+	}
+
+	kid, _ := t.Header["kid"].(string)
+	publicKeys, err := v.keySet.PublicKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, k := range publicKeys {
+		if k.ID() == kid && k.Method().Alg() == t.Method.Alg() {
+			return k.Public(), nil
+		}
+	}
+
+	return nil, errUnknownKey
+}
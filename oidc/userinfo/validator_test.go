@@ -0,0 +1,220 @@
+package userinfo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	autherrors "github.com/tniah/authlib/errors"
+	"github.com/tniah/authlib/integrations/sql"
+	"github.com/tniah/authlib/keys"
+	mockuserinfo "github.com/tniah/authlib/mocks/oidc/userinfo"
+	"github.com/tniah/authlib/types"
+	"github.com/tniah/authlib/utils"
+)
+
+// failingKeySet is a keys.KeySet whose keys cannot be loaded.
+type failingKeySet struct{}
+
+func (failingKeySet) SigningKey(context.Context) (*keys.Key, error) {
+	return nil, errors.New("key store unavailable")
+}
+
+func (failingKeySet) PublicKeys(context.Context) ([]*keys.Key, error) {
+	return nil, errors.New("key store unavailable")
+}
+
+func TestOpaqueTokenValidator_ValidateAccessToken(t *testing.T) {
+	newToken := func() *sql.Token {
+		return &sql.Token{
+			AccessToken:          uuid.NewString(),
+			RefreshToken:         uuid.NewString(),
+			ClientID:             uuid.NewString(),
+			UserID:               uuid.NewString(),
+			Scopes:               []string{"openid", "email"},
+			IssuedAt:             time.Now().UTC().Round(time.Second),
+			AccessTokenExpiresIn: time.Hour,
+		}
+	}
+
+	newValidator := func(t *testing.T, value string, tok *sql.Token, err error) *OpaqueTokenValidator {
+		mgr := mockuserinfo.NewMockTokenManager(t)
+		if tok == nil {
+			mgr.On("QueryByToken", mock.Anything, value, types.TokenTypeHintAccessToken).Return(nil, err).Once()
+		} else {
+			mgr.On("QueryByToken", mock.Anything, value, types.TokenTypeHintAccessToken).Return(tok, err).Once()
+		}
+
+		return NewOpaqueTokenValidator(mgr)
+	}
+
+	t.Run("success", func(t *testing.T) {
+		tok := newToken()
+		tok.Data = map[string]interface{}{
+			"cnf":             map[string]interface{}{"x5t#S256": "thumbprint"},
+			"userinfo_claims": `{"userinfo":{"given_name":{"essential":true},"nickname":null}}`,
+		}
+
+		at, err := newValidator(t, tok.AccessToken, tok, nil).ValidateAccessToken(context.Background(), tok.AccessToken)
+		require.NoError(t, err)
+		assert.Equal(t, tok.UserID, at.Subject)
+		assert.Equal(t, tok.ClientID, at.ClientID)
+		assert.Equal(t, types.NewScopes(tok.Scopes), at.Scopes)
+		assert.Equal(t, tok.IssuedAt.Add(time.Hour), at.ExpiresAt)
+		assert.Equal(t, "thumbprint", at.Confirmation["x5t#S256"])
+		assert.Equal(t, map[string]*types.ClaimRequest{"given_name": {Essential: true}, "nickname": nil}, at.ClaimsRequest)
+		assert.Same(t, tok, at.Token)
+	})
+
+	t.Run("unknown token", func(t *testing.T) {
+		_, err := newValidator(t, "unknown", nil, nil).ValidateAccessToken(context.Background(), "unknown")
+		assert.ErrorIs(t, autherrors.ToAuthLibError(err).Code, autherrors.ErrInvalidToken)
+	})
+
+	t.Run("refresh token", func(t *testing.T) {
+		tok := newToken()
+		_, err := newValidator(t, tok.RefreshToken, tok, nil).ValidateAccessToken(context.Background(), tok.RefreshToken)
+		assert.ErrorIs(t, autherrors.ToAuthLibError(err).Code, autherrors.ErrInvalidToken)
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		tok := newToken()
+		tok.IssuedAt = tok.IssuedAt.Add(-2 * time.Hour)
+		_, err := newValidator(t, tok.AccessToken, tok, nil).ValidateAccessToken(context.Background(), tok.AccessToken)
+		assert.ErrorIs(t, autherrors.ToAuthLibError(err).Code, autherrors.ErrInvalidToken)
+	})
+
+	t.Run("lookup error", func(t *testing.T) {
+		lookupErr := errors.New("db down")
+		_, err := newValidator(t, "token", nil, lookupErr).ValidateAccessToken(context.Background(), "token")
+		assert.ErrorIs(t, err, lookupErr)
+	})
+}
+
+// testAudiences are the aud values the UserInfo endpoint accepts in tests.
+var testAudiences = []string{"https://api.example.com"}
+
+func TestJWTTokenValidator_ValidateAccessToken(t *testing.T) {
+	key, err := keys.GenerateKey(jwt.SigningMethodES256, "key-1")
+	require.NoError(t, err)
+
+	other, err := keys.GenerateKey(jwt.SigningMethodES256, "key-2")
+	require.NoError(t, err)
+
+	ks := keys.NewStaticKeySet(key)
+	subject := uuid.NewString()
+
+	sign := func(t *testing.T, k *keys.Key, typ string, override utils.JWTClaim) string {
+		claims := utils.JWTClaim{
+			"iss":       testIssuer,
+			"sub":       subject,
+			"aud":       "https://api.example.com",
+			"client_id": "client-1",
+			"scope":     "openid profile",
+			"exp":       time.Now().Add(time.Hour).Unix(),
+			"jti":       uuid.NewString(),
+		}
+		for name, v := range override {
+			claims[name] = v
+		}
+
+		token, err := k.JWTToken().Generate(claims, utils.JWTHeader{"typ": typ})
+		require.NoError(t, err)
+		return token
+	}
+
+	t.Run("success", func(t *testing.T) {
+		token := sign(t, key, "at+JWT", utils.JWTClaim{"cnf": map[string]interface{}{"jkt": "thumbprint"}})
+
+		at, err := NewJWTTokenValidator(ks, testIssuer, testAudiences).ValidateAccessToken(context.Background(), token)
+		require.NoError(t, err)
+		assert.Equal(t, subject, at.Subject)
+		assert.Equal(t, "client-1", at.ClientID)
+		assert.Equal(t, types.NewScopes([]string{"openid", "profile"}), at.Scopes)
+		assert.False(t, at.ExpiresAt.IsZero())
+		assert.Equal(t, "thumbprint", at.Confirmation["jkt"])
+		assert.Equal(t, testIssuer, at.Claims["iss"])
+	})
+
+	t.Run("audiences set later", func(t *testing.T) {
+		_, err := NewJWTTokenValidator(ks, testIssuer, nil).
+			SetAudiences("https://userinfo.example.com", "https://api.example.com").
+			ValidateAccessToken(context.Background(), sign(t, key, "at+jwt", nil))
+		assert.NoError(t, err)
+	})
+
+	t.Run("media type prefix", func(t *testing.T) {
+		_, err := NewJWTTokenValidator(ks, testIssuer, testAudiences).ValidateAccessToken(context.Background(), sign(t, key, "application/at+jwt", nil))
+		assert.NoError(t, err)
+	})
+
+	cases := []struct {
+		name      string
+		token     func(t *testing.T) string
+		validator *JWTTokenValidator
+	}{
+		{
+			name:      "not an access token",
+			token:     func(t *testing.T) string { return sign(t, key, "JWT", nil) },
+			validator: NewJWTTokenValidator(ks, testIssuer, testAudiences),
+		},
+		{
+			name:      "unknown key",
+			token:     func(t *testing.T) string { return sign(t, other, "at+jwt", nil) },
+			validator: NewJWTTokenValidator(ks, testIssuer, testAudiences),
+		},
+		{
+			name:      "other issuer",
+			token:     func(t *testing.T) string { return sign(t, key, "at+jwt", nil) },
+			validator: NewJWTTokenValidator(ks, "https://other.example.com", testAudiences),
+		},
+		{
+			name: "expired",
+			token: func(t *testing.T) string {
+				return sign(t, key, "at+jwt", utils.JWTClaim{"exp": time.Now().Add(-time.Minute).Unix()})
+			},
+			validator: NewJWTTokenValidator(ks, testIssuer, testAudiences),
+		},
+		{
+			name:      "other audience",
+			token:     func(t *testing.T) string { return sign(t, key, "at+jwt", nil) },
+			validator: NewJWTTokenValidator(ks, testIssuer, []string{"https://other.example.com"}),
+		},
+		{
+			name:      "no accepted audience",
+			token:     func(t *testing.T) string { return sign(t, key, "at+jwt", nil) },
+			validator: NewJWTTokenValidator(ks, testIssuer, nil),
+		},
+		{
+			name:      "malformed",
+			token:     func(*testing.T) string { return "not-a-jwt" },
+			validator: NewJWTTokenValidator(ks, testIssuer, testAudiences),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := c.validator.ValidateAccessToken(context.Background(), c.token(t))
+			assert.ErrorIs(t, autherrors.ToAuthLibError(err).Code, autherrors.ErrInvalidToken)
+		})
+	}
+
+	t.Run("expired within leeway", func(t *testing.T) {
+		token := sign(t, key, "at+jwt", utils.JWTClaim{"exp": time.Now().Add(-time.Minute).Unix()})
+		_, err := NewJWTTokenValidator(ks, testIssuer, testAudiences).SetLeeway(2*time.Minute).ValidateAccessToken(context.Background(), token)
+		assert.NoError(t, err)
+	})
+
+	t.Run("key set error", func(t *testing.T) {
+		token := sign(t, key, "at+jwt", nil)
+		_, err := NewJWTTokenValidator(failingKeySet{}, testIssuer, testAudiences).ValidateAccessToken(context.Background(), token)
+		require.Error(t, err)
+		assert.ErrorIs(t, autherrors.ToAuthLibError(err).Code, autherrors.ErrServerError)
+	})
+}
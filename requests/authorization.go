@@ -2,6 +2,7 @@ package requests
 
 import (
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 
@@ -32,9 +33,26 @@ type AuthorizationRequest struct {
 	LoginHint    string
 	ACRValues    types.SpaceDelimitedArray
 
+	// Claims requests individual claims in the ID Token and from the UserInfo
+	// endpoint (OIDC Core §5.5). Nil when the claims parameter is absent.
+	Claims *types.ClaimsRequest
+
 	CodeChallenge       string
 	CodeChallengeMethod types.CodeChallengeMethod
 
+	// DPoPJKT is the JWK thumbprint of the DPoP key the authorization code
+	// is to be bound to (RFC 9449 §10).
+	DPoPJKT string
+
+	// RequestURI references the authorization request parameters stored by
+	// the authorization server, e.g. at the pushed authorization request
+	// endpoint (RFC 9126 §4). It is kept after the request is resolved.
+	RequestURI string
+
+	// RequestObject is the JWT carrying the authorization request parameters
+	// passed by value (RFC 9101 §5.1).
+	RequestObject string
+
 	Client models.Client
 	User   models.User
 
@@ -44,7 +62,7 @@ type AuthorizationRequest struct {
 // NewAuthorizationRequestFromHttp parses an authorization request from an
 // HTTP request. It reads all standard OAuth 2.0 and OIDC parameters from the
 // URL query string. Returns an error only if max_age is present but cannot be
-// parsed as a non-negative integer.
+// parsed as a non-negative integer, or if claims is present but invalid.
 func NewAuthorizationRequestFromHttp(r *http.Request) (*AuthorizationRequest, error) {
 	authReq := &AuthorizationRequest{
 		ResponseType:        types.NewResponseType(r.FormValue("response_type")),
@@ -62,6 +80,9 @@ func NewAuthorizationRequestFromHttp(r *http.Request) (*AuthorizationRequest, er
 		ACRValues:           strings.Fields(r.FormValue("acr_values")),
 		CodeChallenge:       r.FormValue("code_challenge"),
 		CodeChallengeMethod: types.NewCodeChallengeMethod(r.FormValue("code_challenge_method")),
+		DPoPJKT:             r.FormValue("dpop_jkt"),
+		RequestURI:          r.FormValue("request_uri"),
+		RequestObject:       r.FormValue("request"),
 		Request:             r,
 	}
 
@@ -76,9 +97,38 @@ func NewAuthorizationRequestFromHttp(r *http.Request) (*AuthorizationRequest, er
 		authReq.MaxAge = types.NewMaxAge(uint(ma))
 	}
 
+	if claims := r.FormValue("claims"); claims != "" {
+		cr, err := types.ParseClaimsRequest(claims)
+		if err != nil {
+			return nil, autherrors.InvalidRequestError().
+				WithDescription(err.Error()).
+				WithState(authReq.State)
+		}
+
+		authReq.Claims = cr
+	}
+
 	return authReq, nil
 }
 
+// NewAuthorizationRequestFromValues parses an authorization request whose
+// parameters were not sent on r itself, e.g. a pushed authorization request
+// (RFC 9126). The returned request carries a clone of r with params as its
+// query string, so extensions reading the HTTP request see the same values.
+func NewAuthorizationRequestFromValues(r *http.Request, params url.Values) (*AuthorizationRequest, error) {
+	hr := r.Clone(r.Context())
+	u := *r.URL
+	u.RawQuery = params.Encode()
+	hr.URL = &u
+	hr.Form = nil
+	hr.PostForm = nil
+	hr.Body = http.NoBody
+	hr.ContentLength = 0
+	hr.Header.Del("Content-Type")
+
+	return NewAuthorizationRequestFromHttp(hr)
+}
+
 // ValidateResponseType returns an error if response_type is missing. Required
 // by default; pass false to treat it as optional.
 func (r *AuthorizationRequest) ValidateResponseType(required ...bool) error {
@@ -139,6 +189,17 @@ func (r *AuthorizationRequest) ValidateResponseMode(required ...bool) error {
 	return nil
 }
 
+// EffectiveResponseMode returns response_mode, or the default response mode
+// of response_type when it was omitted (OAuth 2.0 Multiple Response Type
+// Encoding Practices §2.1).
+func (r *AuthorizationRequest) EffectiveResponseMode() types.ResponseMode {
+	if r.ResponseMode.IsEmpty() {
+		return r.ResponseType.DefaultResponseMode()
+	}
+
+	return r.ResponseMode
+}
+
 // ValidateDisplay returns an error if display is missing. Not required by
 // default; pass true to enforce it.
 func (r *AuthorizationRequest) ValidateDisplay(required ...bool) error {
@@ -169,3 +230,19 @@ func (r *AuthorizationRequest) ValidatePrompts(required ...bool) error {
 func (r *AuthorizationRequest) Method() string {
 	return r.Request.Method
 }
+
+// TokenRequest returns a TokenRequest carrying the grant type, client,
+// redirect URI, scopes, and user of r. Flows that issue tokens directly from
+// the authorization endpoint (implicit, hybrid) use it to drive token
+// generators written against TokenRequest.
+func (r *AuthorizationRequest) TokenRequest() *TokenRequest {
+	return &TokenRequest{
+		GrantType:   r.GrantType,
+		ClientID:    r.ClientID,
+		RedirectURI: r.RedirectURI,
+		Scopes:      r.Scopes,
+		Client:      r.Client,
+		User:        r.User,
+		Request:     r.Request,
+	}
+}
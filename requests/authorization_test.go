@@ -1,11 +1,15 @@
 package requests
 
 import (
+	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	autherrors "github.com/tniah/authlib/errors"
+	"github.com/tniah/authlib/integrations/sql"
 	"github.com/tniah/authlib/types"
 )
 
@@ -23,6 +27,46 @@ func TestNewAuthorizationRequestFromHttp(t *testing.T) {
 		assert.Equal(t, types.NewMaxAge(300), req.MaxAge)
 	})
 
+	t.Run("dpop_jkt", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/?response_type=code&dpop_jkt=NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs", nil)
+		req, err := NewAuthorizationRequestFromHttp(r)
+		assert.NoError(t, err)
+		assert.Equal(t, "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs", req.DPoPJKT)
+	})
+
+	t.Run("request_uri", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/?client_id=myclient&request_uri=urn:ietf:params:oauth:request_uri:6esc_11ACC5bwc014ltc14eY22c", nil)
+		req, err := NewAuthorizationRequestFromHttp(r)
+		assert.NoError(t, err)
+		assert.Equal(t, "urn:ietf:params:oauth:request_uri:6esc_11ACC5bwc014ltc14eY22c", req.RequestURI)
+	})
+
+	t.Run("request", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/?client_id=myclient&request=eyJhbGciOiJub25lIn0.eyJjbGllbnRfaWQiOiJteWNsaWVudCJ9.", nil)
+		req, err := NewAuthorizationRequestFromHttp(r)
+		assert.NoError(t, err)
+		assert.Equal(t, "eyJhbGciOiJub25lIn0.eyJjbGllbnRfaWQiOiJteWNsaWVudCJ9.", req.RequestObject)
+	})
+
+	t.Run("claims", func(t *testing.T) {
+		claims := url.QueryEscape(`{"id_token":{"acr":{"essential":true,"values":["urn:acr:silver"]}},"userinfo":{"email":null}}`)
+		r := httptest.NewRequest("GET", "/?response_type=code&claims="+claims, nil)
+		req, err := NewAuthorizationRequestFromHttp(r)
+		assert.NoError(t, err)
+		assert.True(t, req.Claims.IDToken["acr"].IsEssential())
+		assert.Contains(t, req.Claims.UserInfo, "email")
+	})
+
+	t.Run("invalid claims returns error", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/?state=xyz&claims="+url.QueryEscape(`{"id_token":"acr"}`), nil)
+		req, err := NewAuthorizationRequestFromHttp(r)
+		authErr := autherrors.ToAuthLibError(err)
+		assert.Equal(t, autherrors.ErrInvalidRequest, authErr.Code)
+		assert.Equal(t, types.ErrInvalidClaimsTarget.Error(), authErr.Description)
+		assert.Equal(t, "xyz", authErr.State)
+		assert.Nil(t, req)
+	})
+
 	t.Run("invalid max_age returns error", func(t *testing.T) {
 		r := httptest.NewRequest("GET", "/?max_age=abc", nil)
 		req, err := NewAuthorizationRequestFromHttp(r)
@@ -32,6 +76,25 @@ func TestNewAuthorizationRequestFromHttp(t *testing.T) {
 	})
 }
 
+func TestNewAuthorizationRequestFromValues(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/authorize?client_id=other", strings.NewReader("client_id=other"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	params := url.Values{
+		"response_type": {"code"},
+		"client_id":     {"myclient"},
+		"state":         {"xyz"},
+	}
+	req, err := NewAuthorizationRequestFromValues(r, params)
+	assert.NoError(t, err)
+	assert.Equal(t, types.ResponseTypeCode, req.ResponseType)
+	assert.Equal(t, "myclient", req.ClientID)
+	assert.Equal(t, "xyz", req.State)
+	assert.Equal(t, http.MethodPost, req.Method())
+	assert.Equal(t, "myclient", req.Request.FormValue("client_id"))
+	assert.Equal(t, "other", r.URL.Query().Get("client_id"))
+}
+
 func TestAuthorizationRequest_ValidateResponseType(t *testing.T) {
 	req := &AuthorizationRequest{}
 	err := req.ValidateResponseType()
@@ -97,6 +160,17 @@ func TestAuthorizationRequest_ValidateResponseMode(t *testing.T) {
 	assert.NoError(t, req.ValidateResponseMode(true))
 }
 
+func TestAuthorizationRequest_EffectiveResponseMode(t *testing.T) {
+	r := &AuthorizationRequest{ResponseType: types.ResponseTypeCode}
+	assert.Equal(t, types.ResponseModeQuery, r.EffectiveResponseMode())
+
+	r.ResponseType = types.NewResponseType("code id_token")
+	assert.Equal(t, types.ResponseModeFragment, r.EffectiveResponseMode())
+
+	r.ResponseMode = types.ResponseModeFormPost
+	assert.Equal(t, types.ResponseModeFormPost, r.EffectiveResponseMode())
+}
+
 func TestAuthorizationRequest_ValidateDisplay(t *testing.T) {
 	req := &AuthorizationRequest{}
 	assert.NoError(t, req.ValidateDisplay())
@@ -120,3 +194,27 @@ func TestAuthorizationRequest_ValidatePrompts(t *testing.T) {
 	req.Prompts = types.NewPrompts([]string{"login"})
 	assert.NoError(t, req.ValidatePrompts(true))
 }
+
+func TestAuthorizationRequest_TokenRequest(t *testing.T) {
+	hr := httptest.NewRequest(http.MethodGet, "/authorize", nil)
+	client := &sql.Client{ClientID: "client123"}
+	user := &sql.User{}
+	req := &AuthorizationRequest{
+		GrantType:   types.GrantTypeImplicit,
+		ClientID:    "client123",
+		RedirectURI: "https://example.com/cb",
+		Scopes:      types.NewScopes([]string{"read"}),
+		Client:      client,
+		User:        user,
+		Request:     hr,
+	}
+
+	tokenReq := req.TokenRequest()
+	assert.Equal(t, types.GrantTypeImplicit, tokenReq.GrantType)
+	assert.Equal(t, "client123", tokenReq.ClientID)
+	assert.Equal(t, "https://example.com/cb", tokenReq.RedirectURI)
+	assert.Equal(t, req.Scopes, tokenReq.Scopes)
+	assert.Equal(t, client, tokenReq.Client)
+	assert.Equal(t, user, tokenReq.User)
+	assert.Equal(t, hr, tokenReq.Request)
+}
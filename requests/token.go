@@ -1,7 +1,9 @@
 package requests
 
 import (
+	"fmt"
 	"net/http"
+	"net/url"
 	"strings"
 
 	autherrors "github.com/tniah/authlib/errors"
@@ -22,30 +24,101 @@ type TokenRequest struct {
 	Username string
 	Password string
 
+	RefreshToken string
+
+	DeviceCode string
+
+	// Assertion is the JWT presented with the JWT bearer grant (RFC 7523 §2.1).
+	Assertion string
+
+	// Token exchange parameters (RFC 8693 §2.1). resource and audience may
+	// be repeated.
+	SubjectToken       string
+	SubjectTokenType   types.TokenTypeIdentifier
+	ActorToken         string
+	ActorTokenType     types.TokenTypeIdentifier
+	RequestedTokenType types.TokenTypeIdentifier
+	Resources          []string
+	Audiences          []string
+
 	ClientAuthMethod types.ClientAuthMethod
 	CodeVerifier     string
 
 	Client   models.Client
 	User     models.User
 	AuthCode models.AuthorizationCode
+	// Token is the previously issued token resolved from refresh_token by
+	// the refresh token grant.
+	Token models.Token
+	// Subject and Actor are the validated subject_token and actor_token of a
+	// token exchange request. Actor is nil when no actor_token was sent.
+	Subject *ExchangeToken
+	Actor   *ExchangeToken
+	// Act is the actor claim (RFC 8693 §4.1) set by the token exchange grant
+	// when the issued token is delegated. nil for impersonation and for
+	// every other grant.
+	Act map[string]interface{}
+	// AssertionClaims are the verified claims of the JWT bearer assertion,
+	// set by the JWT bearer grant once the signature and claims are checked.
+	AssertionClaims map[string]interface{}
+	// Confirmation is the cnf claim (RFC 7800 §3.1) binding the issued token
+	// to a key held by the client, e.g. the x5t#S256 certificate thumbprint
+	// set by mutual-TLS (RFC 8705 §3.1). nil for unbound bearer tokens.
+	Confirmation map[string]interface{}
 
 	Request *http.Request
 }
 
+// ExchangeToken describes a subject or actor token of a token exchange
+// request (RFC 8693 §2.1) once it has been validated.
+type ExchangeToken struct {
+	// Subject identifies the principal the token represents (its sub).
+	Subject string
+
+	// ClientID is the client the token was issued to, when known
+	// (client_id or azp).
+	ClientID string
+
+	// Scopes are the scopes granted to the token. Leave empty for tokens that
+	// carry no scope, such as ID tokens.
+	Scopes types.Scopes
+
+	// Act is the act claim already carried by the token, i.e. the existing
+	// delegation chain (RFC 8693 §4.1). nil when the token is not delegated.
+	Act map[string]interface{}
+
+	// MayAct is the may_act claim of the token (RFC 8693 §4.4), naming the
+	// parties allowed to act for the subject. nil when absent.
+	MayAct map[string]interface{}
+}
+
 // NewTokenRequestFromHttp parses a token request from an HTTP request body,
 // reading all standard OAuth 2.0 token endpoint parameters from the POST form values.
 func NewTokenRequestFromHttp(r *http.Request) *TokenRequest {
-	return &TokenRequest{
-		GrantType:    types.NewGrantType(r.PostFormValue("grant_type")),
-		Code:         r.PostFormValue("code"),
-		RedirectURI:  r.PostFormValue("redirect_uri"),
-		ClientID:     r.PostFormValue("client_id"),
-		Scopes:       types.NewScopes(strings.Fields(r.PostFormValue("scope"))),
-		Username:     r.PostFormValue("username"),
-		Password:     r.PostFormValue("password"),
-		CodeVerifier: r.PostFormValue("code_verifier"),
-		Request:      r,
+	req := &TokenRequest{
+		GrantType:          types.NewGrantType(r.PostFormValue("grant_type")),
+		Code:               r.PostFormValue("code"),
+		RedirectURI:        r.PostFormValue("redirect_uri"),
+		ClientID:           r.PostFormValue("client_id"),
+		Scopes:             types.NewScopes(strings.Fields(r.PostFormValue("scope"))),
+		Username:           r.PostFormValue("username"),
+		Password:           r.PostFormValue("password"),
+		RefreshToken:       r.PostFormValue("refresh_token"),
+		DeviceCode:         r.PostFormValue("device_code"),
+		Assertion:          r.PostFormValue("assertion"),
+		SubjectToken:       r.PostFormValue("subject_token"),
+		SubjectTokenType:   types.NewTokenTypeIdentifier(r.PostFormValue("subject_token_type")),
+		ActorToken:         r.PostFormValue("actor_token"),
+		ActorTokenType:     types.NewTokenTypeIdentifier(r.PostFormValue("actor_token_type")),
+		RequestedTokenType: types.NewTokenTypeIdentifier(r.PostFormValue("requested_token_type")),
+		CodeVerifier:       r.PostFormValue("code_verifier"),
+		Request:            r,
 	}
+
+	// PostFormValue above has parsed the body, so PostForm is populated.
+	req.Resources = r.PostForm["resource"]
+	req.Audiences = r.PostForm["audience"]
+	return req
 }
 
 // ValidateGrantType returns an error if grant_type is missing or empty.
@@ -95,6 +168,74 @@ func (r *TokenRequest) ValidatePassword() error {
 	return nil
 }
 
+// ValidateRefreshToken returns an error if refresh_token is missing or empty.
+func (r *TokenRequest) ValidateRefreshToken() error {
+	if r.RefreshToken == "" {
+		return autherrors.InvalidRequestError().WithDescription("missing \"refresh_token\" in request")
+	}
+
+	return nil
+}
+
+// ValidateDeviceCode returns an error if device_code is missing or empty.
+func (r *TokenRequest) ValidateDeviceCode() error {
+	if r.DeviceCode == "" {
+		return autherrors.InvalidRequestError().WithDescription("missing \"device_code\" in request")
+	}
+
+	return nil
+}
+
+// ValidateAssertion returns an error if assertion is missing or empty.
+func (r *TokenRequest) ValidateAssertion() error {
+	if r.Assertion == "" {
+		return autherrors.InvalidRequestError().WithDescription("missing \"assertion\" in request")
+	}
+
+	return nil
+}
+
+// ValidateSubjectToken returns an error if subject_token or
+// subject_token_type is missing (RFC 8693 §2.1).
+func (r *TokenRequest) ValidateSubjectToken() error {
+	if r.SubjectToken == "" {
+		return autherrors.InvalidRequestError().WithDescription("missing \"subject_token\" in request")
+	}
+
+	if r.SubjectTokenType.IsEmpty() {
+		return autherrors.InvalidRequestError().WithDescription("missing \"subject_token_type\" in request")
+	}
+
+	return nil
+}
+
+// ValidateActorToken returns an error if only one of actor_token and
+// actor_token_type is present (RFC 8693 §2.1). Both are optional.
+func (r *TokenRequest) ValidateActorToken() error {
+	if r.ActorToken != "" && r.ActorTokenType.IsEmpty() {
+		return autherrors.InvalidRequestError().WithDescription("missing \"actor_token_type\" in request")
+	}
+
+	if r.ActorToken == "" && !r.ActorTokenType.IsEmpty() {
+		return autherrors.InvalidRequestError().WithDescription("\"actor_token_type\" must not be present without \"actor_token\"")
+	}
+
+	return nil
+}
+
+// ValidateResources returns an error if a resource parameter is not an
+// absolute URI or carries a fragment (RFC 8693 §2.1, RFC 8707 §2).
+func (r *TokenRequest) ValidateResources() error {
+	for _, resource := range r.Resources {
+		u, err := url.Parse(resource)
+		if err != nil || !u.IsAbs() || strings.Contains(resource, "#") {
+			return autherrors.InvalidTargetError().WithDescription(fmt.Sprintf("invalid \"resource\" value \"%s\"", resource))
+		}
+	}
+
+	return nil
+}
+
 // Method returns the HTTP method of the underlying request.
 func (r *TokenRequest) Method() string {
 	return r.Request.Method
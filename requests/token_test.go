@@ -11,7 +11,7 @@ import (
 )
 
 func TestNewTokenRequestFromHttp(t *testing.T) {
-	body := strings.NewReader("grant_type=authorization_code&code=mycode&redirect_uri=https://example.com/cb&client_id=myclient&scope=openid+email&username=alice&password=secret&code_verifier=myverifier")
+	body := strings.NewReader("grant_type=authorization_code&code=mycode&redirect_uri=https://example.com/cb&client_id=myclient&scope=openid+email&username=alice&password=secret&refresh_token=myrefresh&code_verifier=myverifier&device_code=mydevice&assertion=myassertion")
 	r := httptest.NewRequest("POST", "/token", body)
 	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
@@ -24,10 +24,33 @@ func TestNewTokenRequestFromHttp(t *testing.T) {
 	assert.Contains(t, req.Scopes.String(), "email")
 	assert.Equal(t, "alice", req.Username)
 	assert.Equal(t, "secret", req.Password)
+	assert.Equal(t, "myrefresh", req.RefreshToken)
 	assert.Equal(t, "myverifier", req.CodeVerifier)
+	assert.Equal(t, "mydevice", req.DeviceCode)
+	assert.Equal(t, "myassertion", req.Assertion)
 	assert.Equal(t, r, req.Request)
 }
 
+func TestNewTokenRequestFromHttp_TokenExchange(t *testing.T) {
+	body := strings.NewReader("grant_type=urn:ietf:params:oauth:grant-type:token-exchange" +
+		"&subject_token=subject&subject_token_type=urn:ietf:params:oauth:token-type:access_token" +
+		"&actor_token=actor&actor_token_type=urn:ietf:params:oauth:token-type:jwt" +
+		"&requested_token_type=urn:ietf:params:oauth:token-type:access_token" +
+		"&resource=https://a.example.com&resource=https://b.example.com&audience=orders")
+	r := httptest.NewRequest("POST", "/token", body)
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	req := NewTokenRequestFromHttp(r)
+	assert.Equal(t, types.GrantTypeTokenExchange, req.GrantType)
+	assert.Equal(t, "subject", req.SubjectToken)
+	assert.Equal(t, types.TokenTypeIdentifierAccessToken, req.SubjectTokenType)
+	assert.Equal(t, "actor", req.ActorToken)
+	assert.Equal(t, types.TokenTypeIdentifierJWT, req.ActorTokenType)
+	assert.Equal(t, types.TokenTypeIdentifierAccessToken, req.RequestedTokenType)
+	assert.Equal(t, []string{"https://a.example.com", "https://b.example.com"}, req.Resources)
+	assert.Equal(t, []string{"orders"}, req.Audiences)
+}
+
 func TestTokenRequest_ValidateGrantType(t *testing.T) {
 	req := &TokenRequest{}
 	err := req.ValidateGrantType()
@@ -87,3 +110,76 @@ func TestTokenRequest_ValidatePassword(t *testing.T) {
 	req.Password = "secret"
 	assert.NoError(t, req.ValidatePassword())
 }
+
+func TestTokenRequest_ValidateRefreshToken(t *testing.T) {
+	req := &TokenRequest{}
+	err := req.ValidateRefreshToken()
+	authErr := autherrors.ToAuthLibError(err)
+	assert.Equal(t, autherrors.ErrInvalidRequest, authErr.Code)
+
+	req.RefreshToken = "myrefresh"
+	assert.NoError(t, req.ValidateRefreshToken())
+}
+
+func TestTokenRequest_ValidateDeviceCode(t *testing.T) {
+	req := &TokenRequest{}
+	err := req.ValidateDeviceCode()
+	authErr := autherrors.ToAuthLibError(err)
+	assert.Equal(t, autherrors.ErrInvalidRequest, authErr.Code)
+
+	req.DeviceCode = "mydevice"
+	assert.NoError(t, req.ValidateDeviceCode())
+}
+
+func TestTokenRequest_ValidateAssertion(t *testing.T) {
+	req := &TokenRequest{}
+	err := req.ValidateAssertion()
+	authErr := autherrors.ToAuthLibError(err)
+	assert.Equal(t, autherrors.ErrInvalidRequest, authErr.Code)
+
+	req.Assertion = "myassertion"
+	assert.NoError(t, req.ValidateAssertion())
+}
+
+func TestTokenRequest_ValidateSubjectToken(t *testing.T) {
+	req := &TokenRequest{}
+	err := req.ValidateSubjectToken()
+	assert.Equal(t, autherrors.ErrInvalidRequest, autherrors.ToAuthLibError(err).Code)
+
+	req.SubjectToken = "subject"
+	err = req.ValidateSubjectToken()
+	assert.Equal(t, autherrors.ErrInvalidRequest, autherrors.ToAuthLibError(err).Code)
+
+	req.SubjectTokenType = types.TokenTypeIdentifierAccessToken
+	assert.NoError(t, req.ValidateSubjectToken())
+}
+
+func TestTokenRequest_ValidateActorToken(t *testing.T) {
+	req := &TokenRequest{}
+	assert.NoError(t, req.ValidateActorToken())
+
+	req.ActorToken = "actor"
+	err := req.ValidateActorToken()
+	assert.Equal(t, autherrors.ErrInvalidRequest, autherrors.ToAuthLibError(err).Code)
+
+	req.ActorTokenType = types.TokenTypeIdentifierJWT
+	assert.NoError(t, req.ValidateActorToken())
+
+	req.ActorToken = ""
+	err = req.ValidateActorToken()
+	assert.Equal(t, autherrors.ErrInvalidRequest, autherrors.ToAuthLibError(err).Code)
+}
+
+func TestTokenRequest_ValidateResources(t *testing.T) {
+	req := &TokenRequest{}
+	assert.NoError(t, req.ValidateResources())
+
+	req.Resources = []string{"https://api.example.com/orders"}
+	assert.NoError(t, req.ValidateResources())
+
+	for _, resource := range []string{"orders", "/orders", "https://api.example.com/#frag"} {
+		req.Resources = []string{resource}
+		err := req.ValidateResources()
+		assert.Equal(t, autherrors.ErrInvalidTarget, autherrors.ToAuthLibError(err).Code, resource)
+	}
+}
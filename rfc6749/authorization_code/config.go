@@ -52,6 +52,8 @@ type Config struct {
 	authCodeProcessors   []AuthCodeProcessor
 	tokenReqValidators   []TokenRequestValidator
 	tokenProcessors      []TokenProcessor
+	responseModeHandlers []ResponseModeHandler
+	metadataProviders    []MetadataProvider
 
 	// supportedClientAuthMethods controls which authentication methods are
 	// accepted at the token endpoint (basic, post, none).
@@ -79,6 +81,8 @@ func NewConfig() *Config {
 		authCodeProcessors:       []AuthCodeProcessor{},
 		tokenReqValidators:       []TokenRequestValidator{},
 		tokenProcessors:          []TokenProcessor{},
+		responseModeHandlers:     []ResponseModeHandler{},
+		metadataProviders:        []MetadataProvider{},
 		omittedScopePolicy:       OmittedScopePolicyReject,
 	}
 }
@@ -147,6 +151,14 @@ func (cfg *Config) RegisterExtension(ext interface{}) *Config {
 		cfg.tokenProcessors = append(cfg.tokenProcessors, h)
 	}
 
+	if h, ok := ext.(ResponseModeHandler); ok {
+		cfg.responseModeHandlers = append(cfg.responseModeHandlers, h)
+	}
+
+	if h, ok := ext.(MetadataProvider); ok {
+		cfg.metadataProviders = append(cfg.metadataProviders, h)
+	}
+
 	return cfg
 }
 
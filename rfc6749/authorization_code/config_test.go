@@ -23,6 +23,8 @@ func TestNewConfig(t *testing.T) {
 	assert.Empty(t, cfg.authCodeProcessors)
 	assert.Empty(t, cfg.tokenReqValidators)
 	assert.Empty(t, cfg.tokenProcessors)
+	assert.Empty(t, cfg.responseModeHandlers)
+	assert.Empty(t, cfg.metadataProviders)
 	assert.Nil(t, cfg.clientMgr)
 	assert.Nil(t, cfg.userMgr)
 	assert.Nil(t, cfg.authCodeMgr)
@@ -70,22 +72,28 @@ func TestConfig_RegisterExtension(t *testing.T) {
 		cfg.RegisterExtension(authcodemock.NewMockAuthCodeProcessor(t))
 		cfg.RegisterExtension(authcodemock.NewMockTokenRequestValidator(t))
 		cfg.RegisterExtension(authcodemock.NewMockTokenProcessor(t))
+		cfg.RegisterExtension(authcodemock.NewMockResponseModeHandler(t))
+		cfg.RegisterExtension(authcodemock.NewMockMetadataProvider(t))
 
 		assert.Len(t, cfg.authReqValidators, 1)
 		assert.Len(t, cfg.consentReqValidators, 1)
 		assert.Len(t, cfg.authCodeProcessors, 1)
 		assert.Len(t, cfg.tokenReqValidators, 1)
 		assert.Len(t, cfg.tokenProcessors, 1)
+		assert.Len(t, cfg.responseModeHandlers, 1)
+		assert.Len(t, cfg.metadataProviders, 1)
 	})
 
 	t.Run("registers_to_all_matching_slices", func(t *testing.T) {
-		// multiExt implements all 5 extension interfaces at once.
+		// multiExt implements all 7 extension interfaces at once.
 		type multiExt struct {
 			authcodemock.MockAuthorizationRequestValidator
 			authcodemock.MockConsentRequestValidator
 			authcodemock.MockAuthCodeProcessor
 			authcodemock.MockTokenRequestValidator
 			authcodemock.MockTokenProcessor
+			authcodemock.MockResponseModeHandler
+			authcodemock.MockMetadataProvider
 		}
 
 		cfg := NewConfig()
@@ -96,6 +104,8 @@ func TestConfig_RegisterExtension(t *testing.T) {
 		assert.Len(t, cfg.authCodeProcessors, 1)
 		assert.Len(t, cfg.tokenReqValidators, 1)
 		assert.Len(t, cfg.tokenProcessors, 1)
+		assert.Len(t, cfg.responseModeHandlers, 1)
+		assert.Len(t, cfg.metadataProviders, 1)
 	})
 
 	t.Run("ignores_non_extension_types", func(t *testing.T) {
@@ -107,6 +117,8 @@ func TestConfig_RegisterExtension(t *testing.T) {
 		assert.Empty(t, cfg.authCodeProcessors)
 		assert.Empty(t, cfg.tokenReqValidators)
 		assert.Empty(t, cfg.tokenProcessors)
+		assert.Empty(t, cfg.responseModeHandlers)
+		assert.Empty(t, cfg.metadataProviders)
 	})
 }
 
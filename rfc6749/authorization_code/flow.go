@@ -59,8 +59,25 @@ func (f *Flow) CheckResponseType(typ types.ResponseType) bool {
 	return typ.IsCode()
 }
 
+// ProvideMetadata adds the code response type, the authorization_code grant,
+// the query, fragment and form_post response modes, and the client
+// authentication methods accepted at the token endpoint to the authorization
+// server metadata (RFC 8414 §2), followed by the metadata of registered
+// MetadataProvider extensions.
+func (f *Flow) ProvideMetadata(md types.Metadata) {
+	md.Add(types.MetadataResponseTypesSupported, types.ResponseTypeCode.String())
+	md.Add(types.MetadataResponseModesSupported, types.ResponseModeQuery.String(), types.ResponseModeFragment.String(), types.ResponseModeFormPost.String())
+	md.Add(types.MetadataGrantTypesSupported, types.GrantTypeAuthorizationCode.String())
+	md.AddClientAuthMethods(types.MetadataTokenEndpointAuthMethodsSupported, f.supportedClientAuthMethods)
+
+	for _, p := range f.metadataProviders {
+		p.ProvideMetadata(md)
+	}
+}
+
 // ValidateAuthorizationRequest validates the incoming /authorize request:
-// HTTP method, client_id, redirect_uri, response_type, and any registered
+// HTTP method, client_id, redirect_uri, response_type, response_mode, scope,
+// and any registered
 // AuthorizationRequestValidator extensions (e.g. PKCE, OIDC).
 func (f *Flow) ValidateAuthorizationRequest(r *requests.AuthorizationRequest) error {
 	if err := f.checkAuthEndpointHttpMethod(r); err != nil {
@@ -79,6 +96,10 @@ func (f *Flow) ValidateAuthorizationRequest(r *requests.AuthorizationRequest) er
 		return err
 	}
 
+	if err := f.validateResponseMode(r); err != nil {
+		return err
+	}
+
 	if err := f.validateScope(r); err != nil {
 		return err
 	}
@@ -111,8 +132,10 @@ func (f *Flow) ValidateConsentRequest(r *requests.AuthorizationRequest) error {
 }
 
 // AuthorizationResponse generates the authorization code, runs AuthCodeProcessor
-// extensions, saves the code, and redirects the user-agent back to redirect_uri
-// with code and state parameters (RFC 6749 §4.1.2).
+// extensions, saves the code, and returns code and state to redirect_uri
+// (RFC 6749 §4.1.2) using the requested response_mode: a registered
+// ResponseModeHandler when one supports it, otherwise query (the default),
+// fragment or form_post.
 // Returns access_denied if r.User is nil (i.e. the user did not authenticate).
 func (f *Flow) AuthorizationResponse(r *requests.AuthorizationRequest, rw http.ResponseWriter) error {
 	if utils.IsNil(r.User) {
@@ -141,7 +164,11 @@ func (f *Flow) AuthorizationResponse(r *requests.AuthorizationRequest, rw http.R
 		return err
 	}
 
-	return utils.Redirect(rw, r.RedirectURI, params)
+	if h := f.responseModeHandler(r.ResponseMode); h != nil {
+		return h.WriteAuthorizationResponse(r, rw, params)
+	}
+
+	return utils.RedirectWithResponseMode(rw, r.EffectiveResponseMode(), r.RedirectURI, params)
 }
 
 // ValidateTokenRequest validates the /token request: HTTP method, grant_type,
@@ -320,6 +347,29 @@ func (f *Flow) validateScope(r *requests.AuthorizationRequest) error {
 	return nil
 }
 
+// validateResponseMode rejects a response_mode that is unknown, not permitted
+// for the client, or JWT-secured (JARM) without a registered
+// ResponseModeHandler supporting it.
+func (f *Flow) validateResponseMode(r *requests.AuthorizationRequest) error {
+	return rfc6749.ValidateResponseMode(r, f.responseModeHandler(r.ResponseMode) != nil)
+}
+
+// responseModeHandler returns the first registered ResponseModeHandler that
+// supports mode, or nil if none match.
+func (f *Flow) responseModeHandler(mode types.ResponseMode) ResponseModeHandler {
+	if mode.IsEmpty() {
+		return nil
+	}
+
+	for _, h := range f.responseModeHandlers {
+		if h.CheckResponseMode(mode) {
+			return h
+		}
+	}
+
+	return nil
+}
+
 // genAuthCode allocates and populates a new authorization code via AuthCodeManager.
 func (f *Flow) genAuthCode(r *requests.AuthorizationRequest) (models.AuthorizationCode, error) {
 	authCode := f.authCodeMgr.New()
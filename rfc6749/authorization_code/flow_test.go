@@ -692,6 +692,146 @@ func TestFlow_AuthorizationResponse(t *testing.T) {
 	})
 }
 
+func TestFlow_validateResponseMode(t *testing.T) {
+	mockHandler := authcodemock.NewMockResponseModeHandler(t)
+	f := New(NewConfig().RegisterExtension(mockHandler))
+
+	t.Run("success_without_response_mode", func(t *testing.T) {
+		r := newAuthReq(http.MethodGet)
+		assert.NoError(t, f.validateResponseMode(r))
+	})
+
+	t.Run("success_when_handler_supports_mode", func(t *testing.T) {
+		mockHandler.On("CheckResponseMode", types.ResponseModeQueryJWT).Return(true).Once()
+
+		r := newAuthReq(http.MethodGet)
+		r.ResponseMode = types.ResponseModeQueryJWT
+		assert.NoError(t, f.validateResponseMode(r))
+	})
+
+	t.Run("error_when_no_handler_supports_jwt_mode", func(t *testing.T) {
+		mockHandler.On("CheckResponseMode", types.ResponseModeJWT).Return(false).Once()
+
+		r := newAuthReq(http.MethodGet)
+		r.ResponseMode = types.ResponseModeJWT
+		r.RedirectURI = "https://example.com/cb"
+		err := f.validateResponseMode(r)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid_request")
+	})
+
+	t.Run("success_with_form_post", func(t *testing.T) {
+		mockHandler.On("CheckResponseMode", types.ResponseModeFormPost).Return(false).Once()
+
+		r := newAuthReq(http.MethodGet)
+		r.Client = validClient()
+		r.ResponseType = types.ResponseTypeCode
+		r.ResponseMode = types.ResponseModeFormPost
+		assert.NoError(t, f.validateResponseMode(r))
+	})
+
+	t.Run("error_when_mode_is_unknown", func(t *testing.T) {
+		mockHandler.On("CheckResponseMode", types.ResponseMode("web_message")).Return(false).Once()
+
+		r := newAuthReq(http.MethodGet)
+		r.ResponseMode = "web_message"
+		err := f.validateResponseMode(r)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid_request")
+	})
+
+	t.Run("error_when_client_does_not_allow_mode", func(t *testing.T) {
+		mockHandler.On("CheckResponseMode", types.ResponseModeFragment).Return(false).Once()
+
+		client := validClient()
+		client.ResponseModes = []string{"query", "form_post"}
+		r := newAuthReq(http.MethodGet)
+		r.Client = client
+		r.ResponseType = types.ResponseTypeCode
+		r.ResponseMode = types.ResponseModeFragment
+		err := f.validateResponseMode(r)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid_request")
+	})
+}
+
+func TestFlow_AuthorizationResponse_WithResponseModeHandler(t *testing.T) {
+	mockAuthCodeMgr := authcodemock.NewMockAuthCodeManager(t)
+	mockHandler := authcodemock.NewMockResponseModeHandler(t)
+	f := New(NewConfig().
+		SetAuthCodeManager(mockAuthCodeMgr).
+		RegisterExtension(mockHandler))
+
+	newReq := func(mode types.ResponseMode) *requests.AuthorizationRequest {
+		r := newAuthReq(http.MethodGet)
+		r.RedirectURI = "https://example.com/cb"
+		r.ResponseMode = mode
+		r.State = "xyz"
+		r.User = &sql.User{UserID: "user-1"}
+		return r
+	}
+
+	t.Run("success_delegates_to_handler", func(t *testing.T) {
+		code := &sql.AuthorizationCode{Code: "generated-code"}
+		mockAuthCodeMgr.On("New").Return(code).Once()
+		mockAuthCodeMgr.On("Generate", mock.Anything, mock.Anything).Return(nil).Once()
+		mockAuthCodeMgr.On("Save", mock.Anything, mock.Anything).Return(nil).Once()
+		mockHandler.On("CheckResponseMode", types.ResponseModeJWT).Return(true).Once()
+		mockHandler.On("WriteAuthorizationResponse", mock.Anything, mock.Anything, map[string]interface{}{
+			"code":  "generated-code",
+			"state": "xyz",
+		}).Return(nil).Once()
+
+		rw := httptest.NewRecorder()
+		err := f.AuthorizationResponse(newReq(types.ResponseModeJWT), rw)
+		assert.NoError(t, err)
+		assert.Empty(t, rw.Header().Get("Location"))
+	})
+
+	t.Run("success_falls_back_to_redirect", func(t *testing.T) {
+		code := &sql.AuthorizationCode{Code: "generated-code"}
+		mockAuthCodeMgr.On("New").Return(code).Once()
+		mockAuthCodeMgr.On("Generate", mock.Anything, mock.Anything).Return(nil).Once()
+		mockAuthCodeMgr.On("Save", mock.Anything, mock.Anything).Return(nil).Once()
+		mockHandler.On("CheckResponseMode", types.ResponseModeQuery).Return(false).Once()
+
+		rw := httptest.NewRecorder()
+		err := f.AuthorizationResponse(newReq(types.ResponseModeQuery), rw)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusFound, rw.Code)
+		assert.Contains(t, rw.Header().Get("Location"), "code=generated-code")
+	})
+
+	t.Run("success_posts_form", func(t *testing.T) {
+		code := &sql.AuthorizationCode{Code: "generated-code"}
+		mockAuthCodeMgr.On("New").Return(code).Once()
+		mockAuthCodeMgr.On("Generate", mock.Anything, mock.Anything).Return(nil).Once()
+		mockAuthCodeMgr.On("Save", mock.Anything, mock.Anything).Return(nil).Once()
+		mockHandler.On("CheckResponseMode", types.ResponseModeFormPost).Return(false).Once()
+
+		rw := httptest.NewRecorder()
+		err := f.AuthorizationResponse(newReq(types.ResponseModeFormPost), rw)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rw.Code)
+		assert.Empty(t, rw.Header().Get("Location"))
+		assert.Contains(t, rw.Body.String(), `name="code" value="generated-code"`)
+		assert.Contains(t, rw.Body.String(), `name="state" value="xyz"`)
+	})
+
+	t.Run("error_when_handler_fails", func(t *testing.T) {
+		code := &sql.AuthorizationCode{Code: "generated-code"}
+		mockAuthCodeMgr.On("New").Return(code).Once()
+		mockAuthCodeMgr.On("Generate", mock.Anything, mock.Anything).Return(nil).Once()
+		mockAuthCodeMgr.On("Save", mock.Anything, mock.Anything).Return(nil).Once()
+		mockHandler.On("CheckResponseMode", types.ResponseModeJWT).Return(true).Once()
+		mockHandler.On("WriteAuthorizationResponse", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("sign error")).Once()
+
+		err := f.AuthorizationResponse(newReq(types.ResponseModeJWT), httptest.NewRecorder())
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "sign error")
+	})
+}
+
 func TestFlow_TokenResponse(t *testing.T) {
 	mockUserMgr := authcodemock.NewMockUserManager(t)
 	mockAuthCodeMgr := authcodemock.NewMockAuthCodeManager(t)
@@ -879,3 +1019,22 @@ func TestFlow_ValidateTokenRequest_WithExtension(t *testing.T) {
 		assert.Contains(t, err.Error(), "pkce error")
 	})
 }
+
+func TestFlow_ProvideMetadata(t *testing.T) {
+	provider := authcodemock.NewMockMetadataProvider(t)
+	provider.On("ProvideMetadata", mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(0).(types.Metadata).Add(types.MetadataCodeChallengeMethodsSupported, "S256")
+	}).Once()
+
+	f := New(NewConfig().RegisterExtension(provider))
+	md := types.Metadata{}
+	f.ProvideMetadata(md)
+
+	assert.Equal(t, types.Metadata{
+		types.MetadataResponseTypesSupported:            []string{"code"},
+		types.MetadataResponseModesSupported:            []string{"query", "fragment", "form_post"},
+		types.MetadataGrantTypesSupported:               []string{"authorization_code"},
+		types.MetadataTokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "none"},
+		types.MetadataCodeChallengeMethodsSupported:     []string{"S256"},
+	}, md)
+}
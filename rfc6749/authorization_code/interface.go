@@ -95,3 +95,20 @@ type TokenRequestValidator interface {
 type TokenProcessor interface {
 	ProcessToken(r *requests.TokenRequest, token models.Token, data map[string]interface{}) error
 }
+
+// ResponseModeHandler is an extension hook that writes the authorization
+// response for the response_mode values it supports, instead of the default
+// query string redirect (e.g. JARM wraps the parameters in a signed JWT).
+// The first registered handler whose CheckResponseMode returns true is used.
+type ResponseModeHandler interface {
+	CheckResponseMode(mode types.ResponseMode) bool
+	WriteAuthorizationResponse(r *requests.AuthorizationRequest, rw http.ResponseWriter, params map[string]interface{}) error
+}
+
+// MetadataProvider is an extension hook that describes the capabilities of an
+// extension in the authorization server metadata (RFC 8414 §2), such as the
+// code_challenge_methods_supported of PKCE. The flow forwards its own
+// ProvideMetadata call to every registered provider.
+type MetadataProvider interface {
+	ProvideMetadata(md types.Metadata)
+}
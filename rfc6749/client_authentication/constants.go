@@ -2,6 +2,10 @@ package clientauth
 
 import "errors"
 
+// ClientAssertionTypeJWTBearer is the client_assertion_type sent with
+// client_secret_jwt and private_key_jwt authentication (RFC 7523 §2.2).
+const ClientAssertionTypeJWTBearer = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+
 var (
 	// ErrInvalidClient is returned whenever client authentication fails —
 	// client not found, wrong secret, unsupported auth method, etc.
@@ -10,4 +14,8 @@ var (
 
 	// ErrNilClientStore is returned by MustClientStore when a nil store is provided.
 	ErrNilClientStore = errors.New("client store is nil")
+
+	// ErrEmptyAudiences is returned by the Must constructors of the JWT
+	// handlers when no audience is provided.
+	ErrEmptyAudiences = errors.New("audiences are empty")
 )
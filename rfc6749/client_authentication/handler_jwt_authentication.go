@@ -0,0 +1,168 @@
+package clientauth
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/tniah/authlib/models"
+	"github.com/tniah/authlib/utils"
+)
+
+// DefaultMaxAssertionLifetime is the furthest in the future the exp claim of
+// a client assertion may lie. It bounds how long JWTIDCache has to remember
+// a jti.
+const DefaultMaxAssertionLifetime = 5 * time.Minute
+
+// JWTBaseHandler holds the checks shared by the client_secret_jwt and
+// private_key_jwt handlers (RFC 7523 §2.2, §3; OIDC Core §9). Embed it in
+// concrete handler types; they supply the signing methods and the key.
+type JWTBaseHandler struct {
+	*BaseHandler
+	audiences            []string
+	leeway               time.Duration
+	maxAssertionLifetime time.Duration
+	jwtIDCache           JWTIDCache
+}
+
+// keyFunc returns the key verifying t for client.
+type keyFunc func(r *http.Request, client models.Client, t *jwt.Token) (interface{}, error)
+
+func newJWTBaseHandler() *JWTBaseHandler {
+	return &JWTBaseHandler{
+		BaseHandler:          &BaseHandler{},
+		maxAssertionLifetime: DefaultMaxAssertionLifetime,
+		jwtIDCache:           utils.NewMemoryJWTIDCache(),
+	}
+}
+
+// SetAudiences sets the values accepted in the aud claim. Use the URL of the
+// token endpoint and/or the issuer identifier of this authorization server.
+func (h *JWTBaseHandler) SetAudiences(audiences []string) {
+	h.audiences = audiences
+}
+
+// MustAudiences sets the accepted audiences and returns ErrEmptyAudiences if
+// audiences is empty.
+func (h *JWTBaseHandler) MustAudiences(audiences []string) error {
+	if len(audiences) == 0 {
+		return ErrEmptyAudiences
+	}
+
+	h.SetAudiences(audiences)
+	return nil
+}
+
+// SetLeeway sets the clock skew tolerated when checking exp, nbf, and iat.
+// Default: 0.
+func (h *JWTBaseHandler) SetLeeway(leeway time.Duration) {
+	h.leeway = leeway
+}
+
+// SetMaxAssertionLifetime overrides how far in the future exp may lie.
+// Default: DefaultMaxAssertionLifetime.
+func (h *JWTBaseHandler) SetMaxAssertionLifetime(d time.Duration) {
+	h.maxAssertionLifetime = d
+}
+
+// SetJWTIDCache overrides the replay cache. Default: an in-memory cache,
+// suitable for a single server instance.
+func (h *JWTBaseHandler) SetJWTIDCache(cache JWTIDCache) {
+	h.jwtIDCache = cache
+}
+
+// authenticate reads client_assertion from the POST form body, looks up the
+// client named by its sub claim, verifies the signature with key, and checks
+// the claims: iss and sub equal the client_id, aud names this server, exp is
+// present and not too far ahead, and jti is present and unused. Any failure
+// yields ErrInvalidClient, except errors from the store and JWTIDCache. With
+// no audiences configured every assertion is rejected, since aud must be
+// checked (RFC 7523 §3).
+func (h *JWTBaseHandler) authenticate(r *http.Request, methods []string, key keyFunc) (models.Client, error) {
+	if len(h.audiences) == 0 {
+		return nil, ErrInvalidClient
+	}
+
+	if r.Method != http.MethodPost {
+		return nil, ErrInvalidClient
+	}
+
+	ct, err := utils.ContentType(r)
+	if err != nil || !ct.IsXWWWFormUrlencoded() {
+		return nil, ErrInvalidClient
+	}
+
+	if r.PostFormValue("client_assertion_type") != ClientAssertionTypeJWTBearer {
+		return nil, ErrInvalidClient
+	}
+
+	assertion := r.PostFormValue("client_assertion")
+	if assertion == "" {
+		return nil, ErrInvalidClient
+	}
+
+	// The claims are read unverified only to find the client whose key
+	// verifies the signature below.
+	unverified, _, err := jwt.NewParser().ParseUnverified(assertion, jwt.MapClaims{})
+	if err != nil {
+		return nil, ErrInvalidClient
+	}
+
+	clientID, _ := unverified.Claims.GetSubject()
+	if clientID == "" {
+		return nil, ErrInvalidClient
+	}
+
+	// client_id is optional alongside an assertion, but must match it when sent.
+	if id := r.PostFormValue("client_id"); id != "" && id != clientID {
+		return nil, ErrInvalidClient
+	}
+
+	client, err := h.store.QueryByClientID(r.Context(), clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	if utils.IsNil(client) {
+		return nil, ErrInvalidClient
+	}
+
+	parser := jwt.NewParser(
+		jwt.WithValidMethods(methods),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(h.leeway),
+		jwt.WithAudience(h.audiences...),
+		jwt.WithIssuer(clientID),
+		jwt.WithSubject(clientID),
+	)
+
+	claims := jwt.MapClaims{}
+	if _, err = parser.ParseWithClaims(assertion, claims, func(t *jwt.Token) (interface{}, error) {
+		return key(r, client, t)
+	}); err != nil {
+		return nil, ErrInvalidClient
+	}
+
+	exp, _ := claims.GetExpirationTime()
+	if exp.After(time.Now().Add(h.maxAssertionLifetime + h.leeway)) {
+		return nil, ErrInvalidClient
+	}
+
+	// OIDC Core §9: jti is required for client assertions.
+	jti, _ := claims["jti"].(string)
+	if jti == "" {
+		return nil, ErrInvalidClient
+	}
+
+	ok, err := h.jwtIDCache.Use(r.Context(), clientID, jti, exp.Add(h.leeway))
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return nil, ErrInvalidClient
+	}
+
+	return client, nil
+}
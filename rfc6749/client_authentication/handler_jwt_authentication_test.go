@@ -0,0 +1,90 @@
+package clientauth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rfc6749 "github.com/tniah/authlib/mocks/rfc6749/client_authentication"
+	"github.com/tniah/authlib/utils"
+)
+
+const (
+	assertionClientID = "jwt-client"
+	assertionAudience = "https://as.example.com/token"
+)
+
+// newAssertionRequest builds a token request carrying assertion as a
+// client_assertion, plus any extra form values.
+func newAssertionRequest(assertion string, extra url.Values) *http.Request {
+	form := url.Values{
+		"client_assertion_type": {ClientAssertionTypeJWTBearer},
+		"client_assertion":      {assertion},
+	}
+	for k, v := range extra {
+		form[k] = v
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/token", strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return r
+}
+
+// assertionClaims returns valid client assertion claims for assertionClientID.
+func assertionClaims(jti string) jwt.MapClaims {
+	now := time.Now()
+	return jwt.MapClaims{
+		"iss": assertionClientID,
+		"sub": assertionClientID,
+		"aud": assertionAudience,
+		"exp": now.Add(time.Minute).Unix(),
+		"iat": now.Unix(),
+		"jti": jti,
+	}
+}
+
+func signClaims(t *testing.T, claims jwt.MapClaims, method jwt.SigningMethod, key interface{}, kid string) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+
+	assertion, err := token.SignedString(key)
+	require.NoError(t, err)
+	return assertion
+}
+
+func TestJWTBaseHandler(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		h := newJWTBaseHandler()
+		assert.Equal(t, DefaultMaxAssertionLifetime, h.maxAssertionLifetime)
+		assert.IsType(t, &utils.MemoryJWTIDCache{}, h.jwtIDCache)
+	})
+
+	t.Run("setters", func(t *testing.T) {
+		h := newJWTBaseHandler()
+		cache := rfc6749.NewMockJWTIDCache(t)
+
+		h.SetAudiences([]string{assertionAudience})
+		h.SetLeeway(time.Second)
+		h.SetMaxAssertionLifetime(time.Hour)
+		h.SetJWTIDCache(cache)
+
+		assert.Equal(t, []string{assertionAudience}, h.audiences)
+		assert.Equal(t, time.Second, h.leeway)
+		assert.Equal(t, time.Hour, h.maxAssertionLifetime)
+		assert.Equal(t, cache, h.jwtIDCache)
+	})
+
+	t.Run("error_empty_audiences", func(t *testing.T) {
+		h := newJWTBaseHandler()
+		assert.ErrorIs(t, h.MustAudiences(nil), ErrEmptyAudiences)
+	})
+}
@@ -0,0 +1,84 @@
+package clientauth
+
+import (
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/tniah/authlib/models"
+	"github.com/tniah/authlib/types"
+)
+
+// privateKeyJWTMethods are the asymmetric algorithms accepted for
+// private_key_jwt.
+var privateKeyJWTMethods = []string{
+	jwt.SigningMethodRS256.Alg(), jwt.SigningMethodRS384.Alg(), jwt.SigningMethodRS512.Alg(),
+	jwt.SigningMethodPS256.Alg(), jwt.SigningMethodPS384.Alg(), jwt.SigningMethodPS512.Alg(),
+	jwt.SigningMethodES256.Alg(), jwt.SigningMethodES384.Alg(), jwt.SigningMethodES512.Alg(),
+	jwt.SigningMethodEdDSA.Alg(),
+}
+
+// PrivateKeyJWTAuthHandler implements private_key_jwt authentication (OIDC
+// Core §9, RFC 7523 §2.2). The client sends a JWT signed with one of its
+// private keys as client_assertion; the matching public key is taken from the
+// client's registered JWK Set, inline or fetched from its jwks_uri. The
+// client must implement JWKSProvider.
+type PrivateKeyJWTAuthHandler struct {
+	*JWTBaseHandler
+	fetcher JWKSFetcher
+}
+
+// NewPrivateKeyJWTAuthHandler creates a PrivateKeyJWTAuthHandler with the
+// given store, accepting assertions whose aud contains one of audiences; all
+// assertions are rejected when audiences is empty. jwks_uri documents are
+// fetched with a CachingJWKSFetcher.
+func NewPrivateKeyJWTAuthHandler(store ClientStore, audiences []string) *PrivateKeyJWTAuthHandler {
+	h := &PrivateKeyJWTAuthHandler{
+		JWTBaseHandler: newJWTBaseHandler(),
+		fetcher:        NewCachingJWKSFetcher(nil),
+	}
+	h.SetClientStore(store)
+	h.SetAudiences(audiences)
+	return h
+}
+
+// MustPrivateKeyJWTAuthHandler creates a PrivateKeyJWTAuthHandler and returns
+// an error if store is nil or audiences is empty.
+func MustPrivateKeyJWTAuthHandler(store ClientStore, audiences []string) (*PrivateKeyJWTAuthHandler, error) {
+	h := &PrivateKeyJWTAuthHandler{
+		JWTBaseHandler: newJWTBaseHandler(),
+		fetcher:        NewCachingJWKSFetcher(nil),
+	}
+
+	if err := h.MustClientStore(store); err != nil {
+		return nil, err
+	}
+
+	if err := h.MustAudiences(audiences); err != nil {
+		return nil, err
+	}
+
+	return h, nil
+}
+
+// SetJWKSFetcher overrides how jwks_uri documents are retrieved.
+func (h *PrivateKeyJWTAuthHandler) SetJWKSFetcher(fetcher JWKSFetcher) {
+	h.fetcher = fetcher
+}
+
+// Method returns private_key_jwt.
+func (h *PrivateKeyJWTAuthHandler) Method() types.ClientAuthMethod {
+	return types.ClientPrivateKeyJWTAuthentication
+}
+
+// Authenticate verifies the client assertion with the client's public key.
+// Returns ErrInvalidClient if the assertion is missing or invalid, or no key
+// of the client matches it.
+func (h *PrivateKeyJWTAuthHandler) Authenticate(r *http.Request) (models.Client, error) {
+	return h.authenticate(r, privateKeyJWTMethods, h.verificationKey)
+}
+
+// verificationKey selects the key of the client's JWK Set matching the kid
+// and alg of the assertion with ClientJWKSKey.
+func (h *PrivateKeyJWTAuthHandler) verificationKey(r *http.Request, client models.Client, t *jwt.Token) (interface{}, error) {
+	return ClientJWKSKey(r.Context(), h.fetcher, client, t)
+}
@@ -0,0 +1,216 @@
+package clientauth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/tniah/authlib/integrations/sql"
+	rfc6749 "github.com/tniah/authlib/mocks/rfc6749/client_authentication"
+	"github.com/tniah/authlib/types"
+	"github.com/tniah/authlib/utils"
+)
+
+func rsaJWK(key *rsa.PublicKey, kid string) utils.JWK {
+	return utils.JWK{
+		Kty: "RSA",
+		Kid: kid,
+		N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+func ecJWK(key *ecdsa.PublicKey, kid string) utils.JWK {
+	return utils.JWK{
+		Kty: "EC",
+		Kid: kid,
+		Crv: "P-256",
+		X:   base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, 32))),
+		Y:   base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(make([]byte, 32))),
+	}
+}
+
+func TestNewPrivateKeyJWTAuthHandler(t *testing.T) {
+	store := rfc6749.NewMockClientStore(t)
+	h := NewPrivateKeyJWTAuthHandler(store, []string{assertionAudience})
+	assert.NotNil(t, h)
+	assert.NotNil(t, h.store)
+	assert.IsType(t, &CachingJWKSFetcher{}, h.fetcher)
+
+	fetcher := rfc6749.NewMockJWKSFetcher(t)
+	h.SetJWKSFetcher(fetcher)
+	assert.Equal(t, fetcher, h.fetcher)
+}
+
+func TestMustPrivateKeyJWTAuthHandler(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		h, err := MustPrivateKeyJWTAuthHandler(rfc6749.NewMockClientStore(t), []string{assertionAudience})
+		assert.NoError(t, err)
+		assert.NotNil(t, h)
+	})
+
+	t.Run("error_nil_store", func(t *testing.T) {
+		h, err := MustPrivateKeyJWTAuthHandler(nil, []string{assertionAudience})
+		assert.Nil(t, h)
+		assert.ErrorIs(t, err, ErrNilClientStore)
+	})
+
+	t.Run("error_empty_audiences", func(t *testing.T) {
+		h, err := MustPrivateKeyJWTAuthHandler(rfc6749.NewMockClientStore(t), nil)
+		assert.Nil(t, h)
+		assert.ErrorIs(t, err, ErrEmptyAudiences)
+	})
+}
+
+func TestPrivateKeyJWTAuthHandler_Method(t *testing.T) {
+	h := NewPrivateKeyJWTAuthHandler(rfc6749.NewMockClientStore(t), []string{assertionAudience})
+	assert.Equal(t, types.ClientPrivateKeyJWTAuthentication, h.Method())
+}
+
+func TestPrivateKeyJWTAuthHandler_Authenticate(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	const jwksURI = "https://client.example.com/jwks.json"
+	jwks := &utils.JWKSet{Keys: []utils.JWK{rsaJWK(&rsaKey.PublicKey, "rsa-1"), ecJWK(&ecKey.PublicKey, "ec-1")}}
+	inlineJWKs, err := json.Marshal(jwks)
+	require.NoError(t, err)
+
+	newHandler := func(t *testing.T, client *sql.Client) *PrivateKeyJWTAuthHandler {
+		store := rfc6749.NewMockClientStore(t)
+		store.On("QueryByClientID", mock.Anything, assertionClientID).Return(client, nil).Once()
+		return NewPrivateKeyJWTAuthHandler(store, []string{assertionAudience})
+	}
+
+	t.Run("success_with_inline_jwks", func(t *testing.T) {
+		client := &sql.Client{ClientID: assertionClientID, JWKs: inlineJWKs}
+		h := newHandler(t, client)
+
+		assertion := signClaims(t, assertionClaims("jti-1"), jwt.SigningMethodES256, ecKey, "ec-1")
+		authenticated, err := h.Authenticate(newAssertionRequest(assertion, nil))
+		assert.NoError(t, err)
+		assert.Equal(t, client, authenticated)
+	})
+
+	t.Run("success_with_jwks_uri", func(t *testing.T) {
+		client := &sql.Client{ClientID: assertionClientID, JWKsURI: jwksURI}
+		h := newHandler(t, client)
+		fetcher := rfc6749.NewMockJWKSFetcher(t)
+		fetcher.On("FetchJWKS", mock.Anything, jwksURI, false).Return(jwks, nil).Once()
+		h.SetJWKSFetcher(fetcher)
+
+		assertion := signClaims(t, assertionClaims("jti-1"), jwt.SigningMethodRS256, rsaKey, "rsa-1")
+		authenticated, err := h.Authenticate(newAssertionRequest(assertion, nil))
+		assert.NoError(t, err)
+		assert.Equal(t, client, authenticated)
+	})
+
+	t.Run("success_refreshes_jwks_on_unknown_kid", func(t *testing.T) {
+		rotated, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+
+		client := &sql.Client{ClientID: assertionClientID, JWKsURI: jwksURI}
+		h := newHandler(t, client)
+		fetcher := rfc6749.NewMockJWKSFetcher(t)
+		fetcher.On("FetchJWKS", mock.Anything, jwksURI, false).Return(jwks, nil).Once()
+		fetcher.On("FetchJWKS", mock.Anything, jwksURI, true).Return(&utils.JWKSet{Keys: []utils.JWK{rsaJWK(&rotated.PublicKey, "rsa-2")}}, nil).Once()
+		h.SetJWKSFetcher(fetcher)
+
+		assertion := signClaims(t, assertionClaims("jti-1"), jwt.SigningMethodRS256, rotated, "rsa-2")
+		authenticated, err := h.Authenticate(newAssertionRequest(assertion, nil))
+		assert.NoError(t, err)
+		assert.Equal(t, client, authenticated)
+	})
+
+	t.Run("success_without_kid_when_one_key_matches", func(t *testing.T) {
+		single, err := json.Marshal(&utils.JWKSet{Keys: []utils.JWK{rsaJWK(&rsaKey.PublicKey, "")}})
+		require.NoError(t, err)
+		h := newHandler(t, &sql.Client{ClientID: assertionClientID, JWKs: single})
+
+		assertion := signClaims(t, assertionClaims("jti-1"), jwt.SigningMethodRS256, rsaKey, "")
+		_, err = h.Authenticate(newAssertionRequest(assertion, nil))
+		assert.NoError(t, err)
+	})
+
+	t.Run("error_without_audiences", func(t *testing.T) {
+		h := NewPrivateKeyJWTAuthHandler(rfc6749.NewMockClientStore(t), nil)
+
+		assertion := signClaims(t, assertionClaims("jti-1"), jwt.SigningMethodES256, ecKey, "ec-1")
+		client, err := h.Authenticate(newAssertionRequest(assertion, nil))
+		assert.Nil(t, client)
+		assert.ErrorIs(t, err, ErrInvalidClient)
+	})
+
+	t.Run("error_on_wrong_key", func(t *testing.T) {
+		h := newHandler(t, &sql.Client{ClientID: assertionClientID, JWKs: inlineJWKs})
+		other, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+
+		assertion := signClaims(t, assertionClaims("jti-1"), jwt.SigningMethodRS256, other, "rsa-1")
+		client, err := h.Authenticate(newAssertionRequest(assertion, nil))
+		assert.Nil(t, client)
+		assert.ErrorIs(t, err, ErrInvalidClient)
+	})
+
+	t.Run("error_on_unknown_kid", func(t *testing.T) {
+		h := newHandler(t, &sql.Client{ClientID: assertionClientID, JWKs: inlineJWKs})
+
+		assertion := signClaims(t, assertionClaims("jti-1"), jwt.SigningMethodRS256, rsaKey, "rsa-9")
+		client, err := h.Authenticate(newAssertionRequest(assertion, nil))
+		assert.Nil(t, client)
+		assert.ErrorIs(t, err, ErrInvalidClient)
+	})
+
+	t.Run("error_on_ambiguous_key_without_kid", func(t *testing.T) {
+		h := newHandler(t, &sql.Client{ClientID: assertionClientID, JWKs: inlineJWKs})
+
+		assertion := signClaims(t, assertionClaims("jti-1"), jwt.SigningMethodRS256, rsaKey, "")
+		client, err := h.Authenticate(newAssertionRequest(assertion, nil))
+		assert.Nil(t, client)
+		assert.ErrorIs(t, err, ErrInvalidClient)
+	})
+
+	t.Run("error_on_hmac_assertion", func(t *testing.T) {
+		// Guards against algorithm confusion: an HMAC assertion keyed with the
+		// client's public JWK Set must not verify.
+		h := newHandler(t, &sql.Client{ClientID: assertionClientID, JWKs: inlineJWKs})
+
+		assertion := signClaims(t, assertionClaims("jti-1"), jwt.SigningMethodHS256, inlineJWKs, "rsa-1")
+		client, err := h.Authenticate(newAssertionRequest(assertion, nil))
+		assert.Nil(t, client)
+		assert.ErrorIs(t, err, ErrInvalidClient)
+	})
+
+	t.Run("error_when_client_has_no_jwks", func(t *testing.T) {
+		h := newHandler(t, &sql.Client{ClientID: assertionClientID})
+
+		assertion := signClaims(t, assertionClaims("jti-1"), jwt.SigningMethodRS256, rsaKey, "rsa-1")
+		client, err := h.Authenticate(newAssertionRequest(assertion, nil))
+		assert.Nil(t, client)
+		assert.ErrorIs(t, err, ErrInvalidClient)
+	})
+
+	t.Run("error_when_fetch_fails", func(t *testing.T) {
+		h := newHandler(t, &sql.Client{ClientID: assertionClientID, JWKsURI: jwksURI})
+		fetcher := rfc6749.NewMockJWKSFetcher(t)
+		fetcher.On("FetchJWKS", mock.Anything, jwksURI, false).Return(nil, errors.New("unreachable")).Once()
+		h.SetJWKSFetcher(fetcher)
+
+		assertion := signClaims(t, assertionClaims("jti-1"), jwt.SigningMethodRS256, rsaKey, "rsa-1")
+		client, err := h.Authenticate(newAssertionRequest(assertion, nil))
+		assert.Nil(t, client)
+		assert.ErrorIs(t, err, ErrInvalidClient)
+	})
+}
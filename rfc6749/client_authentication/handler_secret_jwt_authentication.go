@@ -0,0 +1,76 @@
+package clientauth
+
+import (
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/tniah/authlib/models"
+	"github.com/tniah/authlib/types"
+)
+
+// secretJWTMethods are the HMAC algorithms accepted for client_secret_jwt.
+var secretJWTMethods = []string{
+	jwt.SigningMethodHS256.Alg(),
+	jwt.SigningMethodHS384.Alg(),
+	jwt.SigningMethodHS512.Alg(),
+}
+
+// SecretJWTAuthHandler implements client_secret_jwt authentication (OIDC Core
+// §9, RFC 7523 §2.2). The client sends a JWT signed with HMAC using its
+// client secret as client_assertion. The client must implement
+// ClientSecretProvider.
+type SecretJWTAuthHandler struct {
+	*JWTBaseHandler
+}
+
+// NewSecretJWTAuthHandler creates a SecretJWTAuthHandler with the given store,
+// accepting assertions whose aud contains one of audiences; all assertions are
+// rejected when audiences is empty.
+func NewSecretJWTAuthHandler(store ClientStore, audiences []string) *SecretJWTAuthHandler {
+	h := &SecretJWTAuthHandler{
+		JWTBaseHandler: newJWTBaseHandler(),
+	}
+	h.SetClientStore(store)
+	h.SetAudiences(audiences)
+	return h
+}
+
+// MustSecretJWTAuthHandler creates a SecretJWTAuthHandler and returns an error
+// if store is nil or audiences is empty.
+func MustSecretJWTAuthHandler(store ClientStore, audiences []string) (*SecretJWTAuthHandler, error) {
+	h := &SecretJWTAuthHandler{
+		JWTBaseHandler: newJWTBaseHandler(),
+	}
+
+	if err := h.MustClientStore(store); err != nil {
+		return nil, err
+	}
+
+	if err := h.MustAudiences(audiences); err != nil {
+		return nil, err
+	}
+
+	return h, nil
+}
+
+// Method returns client_secret_jwt.
+func (h *SecretJWTAuthHandler) Method() types.ClientAuthMethod {
+	return types.ClientSecretJWTAuthentication
+}
+
+// Authenticate verifies the client assertion with the client secret. Returns
+// ErrInvalidClient if the assertion is missing or invalid, or the client has
+// no secret.
+func (h *SecretJWTAuthHandler) Authenticate(r *http.Request) (models.Client, error) {
+	return h.authenticate(r, secretJWTMethods, h.verificationKey)
+}
+
+// verificationKey returns the client secret as the HMAC key.
+func (h *SecretJWTAuthHandler) verificationKey(_ *http.Request, client models.Client, _ *jwt.Token) (interface{}, error) {
+	p, ok := client.(ClientSecretProvider)
+	if !ok || p.GetClientSecret() == "" {
+		return nil, ErrNoVerificationKey
+	}
+
+	return []byte(p.GetClientSecret()), nil
+}
@@ -0,0 +1,200 @@
+package clientauth
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tniah/authlib/integrations/sql"
+	rfc6749 "github.com/tniah/authlib/mocks/rfc6749/client_authentication"
+	"github.com/tniah/authlib/types"
+)
+
+func TestNewSecretJWTAuthHandler(t *testing.T) {
+	store := rfc6749.NewMockClientStore(t)
+	h := NewSecretJWTAuthHandler(store, []string{assertionAudience})
+	assert.NotNil(t, h)
+	assert.NotNil(t, h.store)
+	assert.Equal(t, []string{assertionAudience}, h.audiences)
+}
+
+func TestMustSecretJWTAuthHandler(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		store := rfc6749.NewMockClientStore(t)
+		h, err := MustSecretJWTAuthHandler(store, []string{assertionAudience})
+		assert.NoError(t, err)
+		assert.NotNil(t, h)
+	})
+
+	t.Run("error_nil_store", func(t *testing.T) {
+		h, err := MustSecretJWTAuthHandler(nil, []string{assertionAudience})
+		assert.Nil(t, h)
+		assert.ErrorIs(t, err, ErrNilClientStore)
+	})
+
+	t.Run("error_empty_audiences", func(t *testing.T) {
+		h, err := MustSecretJWTAuthHandler(rfc6749.NewMockClientStore(t), nil)
+		assert.Nil(t, h)
+		assert.ErrorIs(t, err, ErrEmptyAudiences)
+	})
+}
+
+func TestSecretJWTAuthHandler_Method(t *testing.T) {
+	h := NewSecretJWTAuthHandler(rfc6749.NewMockClientStore(t), []string{assertionAudience})
+	assert.Equal(t, types.ClientSecretJWTAuthentication, h.Method())
+}
+
+func TestSecretJWTAuthHandler_Authenticate(t *testing.T) {
+	secret := []byte("a-client-secret-of-at-least-32-bytes")
+	mockClient := &sql.Client{
+		ClientID:                assertionClientID,
+		ClientSecret:            string(secret),
+		TokenEndpointAuthMethod: types.ClientSecretJWTAuthentication.String(),
+	}
+
+	newHandler := func(t *testing.T, client *sql.Client) *SecretJWTAuthHandler {
+		store := rfc6749.NewMockClientStore(t)
+		store.On("QueryByClientID", mock.Anything, assertionClientID).Return(client, nil).Maybe()
+		return NewSecretJWTAuthHandler(store, []string{"https://as.example.com", assertionAudience})
+	}
+
+	t.Run("success", func(t *testing.T) {
+		h := newHandler(t, mockClient)
+		assertion := signClaims(t, assertionClaims("jti-1"), jwt.SigningMethodHS256, secret, "")
+
+		client, err := h.Authenticate(newAssertionRequest(assertion, url.Values{"client_id": {assertionClientID}}))
+		assert.NoError(t, err)
+		assert.Equal(t, mockClient, client)
+	})
+
+	t.Run("error_on_replay", func(t *testing.T) {
+		h := newHandler(t, mockClient)
+		assertion := signClaims(t, assertionClaims("jti-1"), jwt.SigningMethodHS256, secret, "")
+
+		_, err := h.Authenticate(newAssertionRequest(assertion, nil))
+		assert.NoError(t, err)
+
+		client, err := h.Authenticate(newAssertionRequest(assertion, nil))
+		assert.Nil(t, client)
+		assert.ErrorIs(t, err, ErrInvalidClient)
+	})
+
+	t.Run("error_on_invalid_assertion", func(t *testing.T) {
+		withClaims := func(modify func(c jwt.MapClaims)) func(t *testing.T) *http.Request {
+			return func(t *testing.T) *http.Request {
+				claims := assertionClaims("jti-1")
+				modify(claims)
+				return newAssertionRequest(signClaims(t, claims, jwt.SigningMethodHS256, secret, ""), nil)
+			}
+		}
+
+		cases := []struct {
+			name    string
+			request func(t *testing.T) *http.Request
+		}{
+			{"wrong_http_method", func(t *testing.T) *http.Request {
+				r := newAssertionRequest(signClaims(t, assertionClaims("jti-1"), jwt.SigningMethodHS256, secret, ""), nil)
+				r.Method = http.MethodGet
+				return r
+			}},
+			{"wrong_content_type", func(t *testing.T) *http.Request {
+				r := newAssertionRequest(signClaims(t, assertionClaims("jti-1"), jwt.SigningMethodHS256, secret, ""), nil)
+				r.Header.Set("Content-Type", "application/json")
+				return r
+			}},
+			{"wrong_assertion_type", func(t *testing.T) *http.Request {
+				return newAssertionRequest(signClaims(t, assertionClaims("jti-1"), jwt.SigningMethodHS256, secret, ""), url.Values{
+					"client_assertion_type": {"urn:ietf:params:oauth:client-assertion-type:saml2-bearer"},
+				})
+			}},
+			{"missing_assertion", func(t *testing.T) *http.Request { return newAssertionRequest("", nil) }},
+			{"malformed_assertion", func(t *testing.T) *http.Request { return newAssertionRequest("not-a-jwt", nil) }},
+			{"client_id_mismatch", func(t *testing.T) *http.Request {
+				return newAssertionRequest(signClaims(t, assertionClaims("jti-1"), jwt.SigningMethodHS256, secret, ""), url.Values{
+					"client_id": {"other-client"},
+				})
+			}},
+			{"wrong_secret", func(t *testing.T) *http.Request {
+				return newAssertionRequest(signClaims(t, assertionClaims("jti-1"), jwt.SigningMethodHS256, []byte("wrong-secret"), ""), nil)
+			}},
+			{"unsigned", func(t *testing.T) *http.Request {
+				return newAssertionRequest(signClaims(t, assertionClaims("jti-1"), jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, ""), nil)
+			}},
+			{"missing_sub", withClaims(func(c jwt.MapClaims) { delete(c, "sub") })},
+			{"iss_differs_from_sub", withClaims(func(c jwt.MapClaims) { c["iss"] = "other-client" })},
+			{"wrong_audience", withClaims(func(c jwt.MapClaims) { c["aud"] = "https://other.example.com/token" })},
+			{"missing_exp", withClaims(func(c jwt.MapClaims) { delete(c, "exp") })},
+			{"expired", withClaims(func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Minute).Unix() })},
+			{"exp_too_far_ahead", withClaims(func(c jwt.MapClaims) { c["exp"] = time.Now().Add(time.Hour).Unix() })},
+			{"missing_jti", withClaims(func(c jwt.MapClaims) { delete(c, "jti") })},
+		}
+
+		for _, c := range cases {
+			t.Run(c.name, func(t *testing.T) {
+				h := newHandler(t, mockClient)
+				client, err := h.Authenticate(c.request(t))
+				assert.Nil(t, client)
+				assert.ErrorIs(t, err, ErrInvalidClient)
+			})
+		}
+	})
+
+	t.Run("error_without_audiences", func(t *testing.T) {
+		h := NewSecretJWTAuthHandler(rfc6749.NewMockClientStore(t), nil)
+
+		assertion := signClaims(t, assertionClaims("jti-1"), jwt.SigningMethodHS256, secret, "")
+		client, err := h.Authenticate(newAssertionRequest(assertion, nil))
+		assert.Nil(t, client)
+		assert.ErrorIs(t, err, ErrInvalidClient)
+	})
+
+	t.Run("error_client_not_found", func(t *testing.T) {
+		store := rfc6749.NewMockClientStore(t)
+		store.On("QueryByClientID", mock.Anything, assertionClientID).Return(nil, nil).Once()
+		h := NewSecretJWTAuthHandler(store, []string{assertionAudience})
+
+		assertion := signClaims(t, assertionClaims("jti-1"), jwt.SigningMethodHS256, secret, "")
+		client, err := h.Authenticate(newAssertionRequest(assertion, nil))
+		assert.Nil(t, client)
+		assert.ErrorIs(t, err, ErrInvalidClient)
+	})
+
+	t.Run("error_client_without_secret", func(t *testing.T) {
+		h := newHandler(t, &sql.Client{ClientID: assertionClientID})
+
+		assertion := signClaims(t, assertionClaims("jti-1"), jwt.SigningMethodHS256, []byte(""), "")
+		client, err := h.Authenticate(newAssertionRequest(assertion, nil))
+		assert.Nil(t, client)
+		assert.ErrorIs(t, err, ErrInvalidClient)
+	})
+
+	t.Run("error_store_returns_error", func(t *testing.T) {
+		storeErr := errors.New("db error")
+		store := rfc6749.NewMockClientStore(t)
+		store.On("QueryByClientID", mock.Anything, assertionClientID).Return(nil, storeErr).Once()
+		h := NewSecretJWTAuthHandler(store, []string{assertionAudience})
+
+		assertion := signClaims(t, assertionClaims("jti-1"), jwt.SigningMethodHS256, secret, "")
+		client, err := h.Authenticate(newAssertionRequest(assertion, nil))
+		assert.Nil(t, client)
+		assert.ErrorIs(t, err, storeErr)
+	})
+
+	t.Run("error_jwt_id_cache_returns_error", func(t *testing.T) {
+		cacheErr := errors.New("cache error")
+		cache := rfc6749.NewMockJWTIDCache(t)
+		cache.On("Use", mock.Anything, assertionClientID, "jti-1", mock.AnythingOfType("time.Time")).Return(false, cacheErr).Once()
+		h := newHandler(t, mockClient)
+		h.SetJWTIDCache(cache)
+
+		assertion := signClaims(t, assertionClaims("jti-1"), jwt.SigningMethodHS256, secret, "")
+		client, err := h.Authenticate(newAssertionRequest(assertion, nil))
+		assert.Nil(t, client)
+		assert.ErrorIs(t, err, cacheErr)
+	})
+}
@@ -0,0 +1,127 @@
+package clientauth
+
+import (
+	"bytes"
+	"crypto/x509"
+	"net/http"
+
+	"github.com/tniah/authlib/models"
+	"github.com/tniah/authlib/types"
+	"github.com/tniah/authlib/utils"
+)
+
+// SelfSignedTLSAuthHandler implements self-signed certificate mutual-TLS
+// authentication (RFC 8705 §2.2). The certificate is not chain-verified;
+// instead it must be one the client registered in the x5c parameter of a key
+// in its JWK Set, inline or fetched from its jwks_uri. The client must
+// implement JWKSProvider.
+//
+// The TLS server has to accept certificates it cannot verify, e.g. with
+// tls.RequireAnyClientCert or tls.RequestClientCert.
+type SelfSignedTLSAuthHandler struct {
+	*BaseHandler
+	fetcher JWKSFetcher
+}
+
+// NewSelfSignedTLSAuthHandler creates a SelfSignedTLSAuthHandler with the
+// given store. jwks_uri documents are fetched with a CachingJWKSFetcher.
+func NewSelfSignedTLSAuthHandler(store ClientStore) *SelfSignedTLSAuthHandler {
+	h := &SelfSignedTLSAuthHandler{
+		BaseHandler: &BaseHandler{},
+		fetcher:     NewCachingJWKSFetcher(nil),
+	}
+
+	h.SetClientStore(store)
+	return h
+}
+
+// MustSelfSignedTLSAuthHandler creates a SelfSignedTLSAuthHandler and
+// returns an error if store is nil.
+func MustSelfSignedTLSAuthHandler(store ClientStore) (*SelfSignedTLSAuthHandler, error) {
+	h := &SelfSignedTLSAuthHandler{
+		BaseHandler: &BaseHandler{},
+		fetcher:     NewCachingJWKSFetcher(nil),
+	}
+
+	if err := h.MustClientStore(store); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// SetJWKSFetcher overrides how jwks_uri documents are retrieved.
+func (h *SelfSignedTLSAuthHandler) SetJWKSFetcher(fetcher JWKSFetcher) {
+	h.fetcher = fetcher
+}
+
+// Method returns self_signed_tls_client_auth.
+func (h *SelfSignedTLSAuthHandler) Method() types.ClientAuthMethod {
+	return types.ClientSelfSignedTLSAuthentication
+}
+
+// Authenticate looks up the client named by client_id and checks that the
+// client certificate is registered in the client's JWK Set. Returns
+// ErrInvalidClient if no certificate was presented, the JWK Set cannot be
+// loaded, or the certificate is not registered.
+func (h *SelfSignedTLSAuthHandler) Authenticate(r *http.Request) (models.Client, error) {
+	client, cert, err := h.certificateClient(r)
+	if err != nil {
+		return nil, err
+	}
+
+	p, ok := client.(JWKSProvider)
+	if !ok {
+		return nil, ErrInvalidClient
+	}
+
+	if jwks := p.GetJWKs(); len(jwks) > 0 {
+		set, err := utils.ParseJWKSet(jwks)
+		if err != nil {
+			return nil, ErrInvalidClient
+		}
+
+		if !registeredCertificate(set, cert) {
+			return nil, ErrInvalidClient
+		}
+
+		return client, nil
+	}
+
+	uri := p.GetJWKsURI()
+	if uri == "" {
+		return nil, ErrInvalidClient
+	}
+
+	set, err := h.fetcher.FetchJWKS(r.Context(), uri, false)
+	if err != nil {
+		return nil, ErrInvalidClient
+	}
+
+	if registeredCertificate(set, cert) {
+		return client, nil
+	}
+
+	// The client may have registered a new certificate since the set was cached.
+	if set, err = h.fetcher.FetchJWKS(r.Context(), uri, true); err != nil {
+		return nil, ErrInvalidClient
+	}
+
+	if !registeredCertificate(set, cert) {
+		return nil, ErrInvalidClient
+	}
+
+	return client, nil
+}
+
+// registeredCertificate reports whether cert is the first x5c certificate of
+// a key in set.
+func registeredCertificate(set *utils.JWKSet, cert *x509.Certificate) bool {
+	for _, k := range set.Keys {
+		c, err := k.Certificate()
+		if err == nil && bytes.Equal(c.Raw, cert.Raw) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,158 @@
+package clientauth
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/tniah/authlib/integrations/sql"
+	rfc6749 "github.com/tniah/authlib/mocks/rfc6749/client_authentication"
+	"github.com/tniah/authlib/types"
+	"github.com/tniah/authlib/utils"
+)
+
+func certificateJWK(cert *x509.Certificate) utils.JWK {
+	return utils.JWK{Kty: "EC", X5c: []string{base64.StdEncoding.EncodeToString(cert.Raw)}}
+}
+
+func TestNewSelfSignedTLSAuthHandler(t *testing.T) {
+	store := rfc6749.NewMockClientStore(t)
+	h := NewSelfSignedTLSAuthHandler(store)
+	assert.NotNil(t, h)
+	assert.NotNil(t, h.store)
+	assert.IsType(t, &CachingJWKSFetcher{}, h.fetcher)
+
+	fetcher := rfc6749.NewMockJWKSFetcher(t)
+	h.SetJWKSFetcher(fetcher)
+	assert.Equal(t, fetcher, h.fetcher)
+}
+
+func TestMustSelfSignedTLSAuthHandler(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		h, err := MustSelfSignedTLSAuthHandler(rfc6749.NewMockClientStore(t))
+		assert.NoError(t, err)
+		assert.NotNil(t, h)
+	})
+
+	t.Run("error_nil_store", func(t *testing.T) {
+		h, err := MustSelfSignedTLSAuthHandler(nil)
+		assert.Nil(t, h)
+		assert.ErrorIs(t, err, ErrNilClientStore)
+	})
+}
+
+func TestSelfSignedTLSAuthHandler_Method(t *testing.T) {
+	h := NewSelfSignedTLSAuthHandler(rfc6749.NewMockClientStore(t))
+	assert.Equal(t, types.ClientSelfSignedTLSAuthentication, h.Method())
+}
+
+func TestSelfSignedTLSAuthHandler_Authenticate(t *testing.T) {
+	cert, _ := newCertificate(t, &x509.Certificate{Subject: pkix.Name{CommonName: "client"}}, nil, nil)
+	other, _ := newCertificate(t, &x509.Certificate{Subject: pkix.Name{CommonName: "other"}}, nil, nil)
+
+	const jwksURI = "https://client.example.com/jwks.json"
+	jwks := &utils.JWKSet{Keys: []utils.JWK{{Kty: "EC", Kid: "no-x5c"}, certificateJWK(cert)}}
+	inlineJWKs, err := json.Marshal(jwks)
+	require.NoError(t, err)
+
+	newHandler := func(t *testing.T, client *sql.Client) *SelfSignedTLSAuthHandler {
+		store := rfc6749.NewMockClientStore(t)
+		store.On("QueryByClientID", mock.Anything, tlsClientID).Return(client, nil).Once()
+		return NewSelfSignedTLSAuthHandler(store)
+	}
+
+	t.Run("success_with_inline_jwks", func(t *testing.T) {
+		client := &sql.Client{ClientID: tlsClientID, JWKs: inlineJWKs}
+		h := newHandler(t, client)
+
+		authenticated, err := h.Authenticate(newTLSRequest(tlsClientID, cert))
+		assert.NoError(t, err)
+		assert.Equal(t, client, authenticated)
+	})
+
+	t.Run("success_with_jwks_uri", func(t *testing.T) {
+		client := &sql.Client{ClientID: tlsClientID, JWKsURI: jwksURI}
+		h := newHandler(t, client)
+		fetcher := rfc6749.NewMockJWKSFetcher(t)
+		fetcher.On("FetchJWKS", mock.Anything, jwksURI, false).Return(jwks, nil).Once()
+		h.SetJWKSFetcher(fetcher)
+
+		authenticated, err := h.Authenticate(newTLSRequest(tlsClientID, cert))
+		assert.NoError(t, err)
+		assert.Equal(t, client, authenticated)
+	})
+
+	t.Run("success_refreshes_jwks_on_unknown_certificate", func(t *testing.T) {
+		client := &sql.Client{ClientID: tlsClientID, JWKsURI: jwksURI}
+		h := newHandler(t, client)
+		fetcher := rfc6749.NewMockJWKSFetcher(t)
+		fetcher.On("FetchJWKS", mock.Anything, jwksURI, false).Return(jwks, nil).Once()
+		fetcher.On("FetchJWKS", mock.Anything, jwksURI, true).Return(&utils.JWKSet{Keys: []utils.JWK{certificateJWK(other)}}, nil).Once()
+		h.SetJWKSFetcher(fetcher)
+
+		authenticated, err := h.Authenticate(newTLSRequest(tlsClientID, other))
+		assert.NoError(t, err)
+		assert.Equal(t, client, authenticated)
+	})
+
+	t.Run("error_on_unregistered_certificate", func(t *testing.T) {
+		h := newHandler(t, &sql.Client{ClientID: tlsClientID, JWKs: inlineJWKs})
+
+		client, err := h.Authenticate(newTLSRequest(tlsClientID, other))
+		assert.Nil(t, client)
+		assert.ErrorIs(t, err, ErrInvalidClient)
+	})
+
+	t.Run("error_on_unregistered_certificate_after_refresh", func(t *testing.T) {
+		h := newHandler(t, &sql.Client{ClientID: tlsClientID, JWKsURI: jwksURI})
+		fetcher := rfc6749.NewMockJWKSFetcher(t)
+		fetcher.On("FetchJWKS", mock.Anything, jwksURI, false).Return(jwks, nil).Once()
+		fetcher.On("FetchJWKS", mock.Anything, jwksURI, true).Return(jwks, nil).Once()
+		h.SetJWKSFetcher(fetcher)
+
+		client, err := h.Authenticate(newTLSRequest(tlsClientID, other))
+		assert.Nil(t, client)
+		assert.ErrorIs(t, err, ErrInvalidClient)
+	})
+
+	t.Run("error_when_client_has_no_jwks", func(t *testing.T) {
+		h := newHandler(t, &sql.Client{ClientID: tlsClientID})
+
+		client, err := h.Authenticate(newTLSRequest(tlsClientID, cert))
+		assert.Nil(t, client)
+		assert.ErrorIs(t, err, ErrInvalidClient)
+	})
+
+	t.Run("error_on_invalid_inline_jwks", func(t *testing.T) {
+		h := newHandler(t, &sql.Client{ClientID: tlsClientID, JWKs: json.RawMessage(`"not-a-set"`)})
+
+		client, err := h.Authenticate(newTLSRequest(tlsClientID, cert))
+		assert.Nil(t, client)
+		assert.ErrorIs(t, err, ErrInvalidClient)
+	})
+
+	t.Run("error_when_fetch_fails", func(t *testing.T) {
+		h := newHandler(t, &sql.Client{ClientID: tlsClientID, JWKsURI: jwksURI})
+		fetcher := rfc6749.NewMockJWKSFetcher(t)
+		fetcher.On("FetchJWKS", mock.Anything, jwksURI, false).Return(nil, errors.New("unreachable")).Once()
+		h.SetJWKSFetcher(fetcher)
+
+		client, err := h.Authenticate(newTLSRequest(tlsClientID, cert))
+		assert.Nil(t, client)
+		assert.ErrorIs(t, err, ErrInvalidClient)
+	})
+
+	t.Run("error_without_certificate", func(t *testing.T) {
+		h := NewSelfSignedTLSAuthHandler(rfc6749.NewMockClientStore(t))
+
+		client, err := h.Authenticate(newTLSRequest(tlsClientID))
+		assert.Nil(t, client)
+		assert.ErrorIs(t, err, ErrInvalidClient)
+	})
+}
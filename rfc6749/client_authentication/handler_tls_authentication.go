@@ -0,0 +1,195 @@
+package clientauth
+
+import (
+	"crypto/x509"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/tniah/authlib/models"
+	"github.com/tniah/authlib/rfc8705"
+	"github.com/tniah/authlib/types"
+	"github.com/tniah/authlib/utils"
+)
+
+// TLSClientAuthHandler implements PKI mutual-TLS authentication (RFC 8705
+// §2.1). The client proves possession of a certificate issued by a trusted
+// CA during the TLS handshake; the certificate must carry the subject DN or
+// subject alternative name registered for the client. The client must
+// implement TLSClientAuthProvider.
+//
+// The certificate chain is taken as verified when the TLS server was
+// configured to verify client certificates (tls.VerifyClientCertIfGiven with
+// ClientCAs). When the server only requests certificates, call SetClientCAs
+// so the handler verifies the chain itself.
+type TLSClientAuthHandler struct {
+	*BaseHandler
+	clientCAs *x509.CertPool
+}
+
+// NewTLSClientAuthHandler creates a TLSClientAuthHandler with the given store.
+func NewTLSClientAuthHandler(store ClientStore) *TLSClientAuthHandler {
+	h := &TLSClientAuthHandler{
+		BaseHandler: &BaseHandler{},
+	}
+
+	h.SetClientStore(store)
+	return h
+}
+
+// MustTLSClientAuthHandler creates a TLSClientAuthHandler and returns an
+// error if store is nil.
+func MustTLSClientAuthHandler(store ClientStore) (*TLSClientAuthHandler, error) {
+	h := &TLSClientAuthHandler{
+		BaseHandler: &BaseHandler{},
+	}
+
+	if err := h.MustClientStore(store); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// SetClientCAs sets the CAs client certificates must chain to. When nil (the
+// default), the handler relies on the chains verified by the TLS server.
+func (h *TLSClientAuthHandler) SetClientCAs(pool *x509.CertPool) {
+	h.clientCAs = pool
+}
+
+// Method returns tls_client_auth.
+func (h *TLSClientAuthHandler) Method() types.ClientAuthMethod {
+	return types.ClientTLSAuthentication
+}
+
+// Authenticate looks up the client named by client_id and checks that the
+// client certificate is trusted and matches the client's registered subject.
+// Returns ErrInvalidClient if no certificate was presented, its chain is not
+// verified, or it does not match.
+func (h *TLSClientAuthHandler) Authenticate(r *http.Request) (models.Client, error) {
+	client, cert, err := h.certificateClient(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if !h.verified(r, cert) {
+		return nil, ErrInvalidClient
+	}
+
+	p, ok := client.(TLSClientAuthProvider)
+	if !ok || !matchCertificateSubject(p, cert) {
+		return nil, ErrInvalidClient
+	}
+
+	return client, nil
+}
+
+// verified reports whether cert chains to a trusted CA.
+func (h *TLSClientAuthHandler) verified(r *http.Request, cert *x509.Certificate) bool {
+	if h.clientCAs == nil {
+		return len(r.TLS.VerifiedChains) > 0
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, c := range r.TLS.PeerCertificates[1:] {
+		intermediates.AddCert(c)
+	}
+
+	_, err := cert.Verify(x509.VerifyOptions{
+		Roots:         h.clientCAs,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	return err == nil
+}
+
+// certificateClient returns the client named by the client_id form parameter,
+// which RFC 8705 §2 requires with both mutual-TLS methods, together with the
+// certificate it presented.
+func (h *BaseHandler) certificateClient(r *http.Request) (models.Client, *x509.Certificate, error) {
+	if r.Method != http.MethodPost {
+		return nil, nil, ErrInvalidClient
+	}
+
+	ct, err := utils.ContentType(r)
+	if err != nil {
+		return nil, nil, ErrInvalidClient
+	}
+
+	if valid := ct.IsXWWWFormUrlencoded(); !valid {
+		return nil, nil, ErrInvalidClient
+	}
+
+	cert := rfc8705.PeerCertificate(r)
+	if cert == nil {
+		return nil, nil, ErrInvalidClient
+	}
+
+	clientID := r.PostFormValue("client_id")
+	if clientID == "" {
+		return nil, nil, ErrInvalidClient
+	}
+
+	client, err := h.store.QueryByClientID(r.Context(), clientID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if utils.IsNil(client) {
+		return nil, nil, ErrInvalidClient
+	}
+
+	return client, cert, nil
+}
+
+// matchCertificateSubject reports whether cert carries the subject registered
+// by the client. Metadata naming no subject, or more than one, never matches.
+func matchCertificateSubject(p TLSClientAuthProvider, cert *x509.Certificate) bool {
+	var (
+		n       int
+		matched bool
+	)
+
+	if dn := p.GetTLSClientAuthSubjectDN(); dn != "" {
+		n++
+		matched = cert.Subject.String() == dn
+	}
+
+	if name := p.GetTLSClientAuthSANDNS(); name != "" {
+		n++
+		for _, v := range cert.DNSNames {
+			if strings.EqualFold(v, name) {
+				matched = true
+			}
+		}
+	}
+
+	if uri := p.GetTLSClientAuthSANURI(); uri != "" {
+		n++
+		for _, v := range cert.URIs {
+			if v.String() == uri {
+				matched = true
+			}
+		}
+	}
+
+	if addr := p.GetTLSClientAuthSANIP(); addr != "" {
+		n++
+		ip := net.ParseIP(addr)
+		for _, v := range cert.IPAddresses {
+			if ip != nil && v.Equal(ip) {
+				matched = true
+			}
+		}
+	}
+
+	if email := p.GetTLSClientAuthSANEmail(); email != "" {
+		n++
+		for _, v := range cert.EmailAddresses {
+			if v == email {
+				matched = true
+			}
+		}
+	}
+
+	return n == 1 && matched
+}
@@ -0,0 +1,256 @@
+package clientauth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/tniah/authlib/integrations/sql"
+	rfc6749 "github.com/tniah/authlib/mocks/rfc6749/client_authentication"
+	"github.com/tniah/authlib/types"
+)
+
+const tlsClientID = "tls-client"
+
+// newCertificate issues a certificate from tmpl signed by parent, or a
+// self-signed one when parent is nil.
+func newCertificate(t *testing.T, tmpl, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	require.NoError(t, err)
+
+	tmpl.SerialNumber = serial
+	tmpl.NotBefore = time.Now().Add(-time.Minute)
+	tmpl.NotAfter = time.Now().Add(time.Hour)
+	if parent == nil {
+		parent, parentKey = tmpl, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert, key
+}
+
+// newTLSRequest builds a form POST carrying client_id, made over mutual TLS
+// with certs when any is given.
+func newTLSRequest(clientID string, certs ...*x509.Certificate) *http.Request {
+	form := url.Values{"client_id": {clientID}}
+	r := httptest.NewRequest(http.MethodPost, "/token", strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if len(certs) > 0 {
+		r.TLS = &tls.ConnectionState{PeerCertificates: certs}
+	}
+	return r
+}
+
+func TestNewTLSClientAuthHandler(t *testing.T) {
+	store := rfc6749.NewMockClientStore(t)
+	h := NewTLSClientAuthHandler(store)
+	assert.NotNil(t, h)
+	assert.NotNil(t, h.store)
+	assert.Nil(t, h.clientCAs)
+
+	pool := x509.NewCertPool()
+	h.SetClientCAs(pool)
+	assert.Equal(t, pool, h.clientCAs)
+}
+
+func TestMustTLSClientAuthHandler(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		h, err := MustTLSClientAuthHandler(rfc6749.NewMockClientStore(t))
+		assert.NoError(t, err)
+		assert.NotNil(t, h)
+	})
+
+	t.Run("error_nil_store", func(t *testing.T) {
+		h, err := MustTLSClientAuthHandler(nil)
+		assert.Nil(t, h)
+		assert.ErrorIs(t, err, ErrNilClientStore)
+	})
+}
+
+func TestTLSClientAuthHandler_Method(t *testing.T) {
+	h := NewTLSClientAuthHandler(rfc6749.NewMockClientStore(t))
+	assert.Equal(t, types.ClientTLSAuthentication, h.Method())
+}
+
+func TestTLSClientAuthHandler_Authenticate(t *testing.T) {
+	ca, caKey := newCertificate(t, &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, nil, nil)
+	leaf, _ := newCertificate(t, &x509.Certificate{
+		Subject:        pkix.Name{CommonName: "client", Organization: []string{"Example"}},
+		DNSNames:       []string{"client.example.com"},
+		IPAddresses:    []net.IP{net.ParseIP("192.0.2.10")},
+		EmailAddresses: []string{"client@example.com"},
+		URIs:           []*url.URL{{Scheme: "spiffe", Host: "example.com", Path: "/client"}},
+		ExtKeyUsage:    []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, ca, caKey)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ca)
+
+	newHandler := func(t *testing.T, client *sql.Client) *TLSClientAuthHandler {
+		store := rfc6749.NewMockClientStore(t)
+		store.On("QueryByClientID", mock.Anything, tlsClientID).Return(client, nil).Once()
+		h := NewTLSClientAuthHandler(store)
+		h.SetClientCAs(pool)
+		return h
+	}
+
+	t.Run("success_with_matching_subject", func(t *testing.T) {
+		cases := []struct {
+			name   string
+			client *sql.Client
+		}{
+			{"subject_dn", &sql.Client{ClientID: tlsClientID, TLSClientAuthSubjectDN: "CN=client,O=Example"}},
+			{"san_dns", &sql.Client{ClientID: tlsClientID, TLSClientAuthSANDNS: "CLIENT.example.com"}},
+			{"san_uri", &sql.Client{ClientID: tlsClientID, TLSClientAuthSANURI: "spiffe://example.com/client"}},
+			{"san_ip", &sql.Client{ClientID: tlsClientID, TLSClientAuthSANIP: "192.0.2.10"}},
+			{"san_email", &sql.Client{ClientID: tlsClientID, TLSClientAuthSANEmail: "client@example.com"}},
+		}
+
+		for _, c := range cases {
+			t.Run(c.name, func(t *testing.T) {
+				h := newHandler(t, c.client)
+
+				authenticated, err := h.Authenticate(newTLSRequest(tlsClientID, leaf))
+				assert.NoError(t, err)
+				assert.Equal(t, c.client, authenticated)
+			})
+		}
+	})
+
+	t.Run("success_with_chain_verified_by_tls_server", func(t *testing.T) {
+		client := &sql.Client{ClientID: tlsClientID, TLSClientAuthSANDNS: "client.example.com"}
+		store := rfc6749.NewMockClientStore(t)
+		store.On("QueryByClientID", mock.Anything, tlsClientID).Return(client, nil).Once()
+		h := NewTLSClientAuthHandler(store)
+
+		r := newTLSRequest(tlsClientID, leaf)
+		r.TLS.VerifiedChains = [][]*x509.Certificate{{leaf, ca}}
+		authenticated, err := h.Authenticate(r)
+		assert.NoError(t, err)
+		assert.Equal(t, client, authenticated)
+	})
+
+	t.Run("error_when_chain_is_not_verified_by_tls_server", func(t *testing.T) {
+		store := rfc6749.NewMockClientStore(t)
+		store.On("QueryByClientID", mock.Anything, tlsClientID).Return(&sql.Client{ClientID: tlsClientID, TLSClientAuthSANDNS: "client.example.com"}, nil).Once()
+		h := NewTLSClientAuthHandler(store)
+
+		client, err := h.Authenticate(newTLSRequest(tlsClientID, leaf))
+		assert.Nil(t, client)
+		assert.ErrorIs(t, err, ErrInvalidClient)
+	})
+
+	t.Run("error_on_untrusted_certificate", func(t *testing.T) {
+		selfSigned, _ := newCertificate(t, &x509.Certificate{
+			Subject:     pkix.Name{CommonName: "client", Organization: []string{"Example"}},
+			ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		}, nil, nil)
+		h := newHandler(t, &sql.Client{ClientID: tlsClientID, TLSClientAuthSubjectDN: "CN=client,O=Example"})
+
+		client, err := h.Authenticate(newTLSRequest(tlsClientID, selfSigned))
+		assert.Nil(t, client)
+		assert.ErrorIs(t, err, ErrInvalidClient)
+	})
+
+	t.Run("error_on_mismatched_subject", func(t *testing.T) {
+		h := newHandler(t, &sql.Client{ClientID: tlsClientID, TLSClientAuthSANDNS: "other.example.com"})
+
+		client, err := h.Authenticate(newTLSRequest(tlsClientID, leaf))
+		assert.Nil(t, client)
+		assert.ErrorIs(t, err, ErrInvalidClient)
+	})
+
+	t.Run("error_on_ambiguous_metadata", func(t *testing.T) {
+		h := newHandler(t, &sql.Client{
+			ClientID:               tlsClientID,
+			TLSClientAuthSubjectDN: "CN=client,O=Example",
+			TLSClientAuthSANDNS:    "client.example.com",
+		})
+
+		client, err := h.Authenticate(newTLSRequest(tlsClientID, leaf))
+		assert.Nil(t, client)
+		assert.ErrorIs(t, err, ErrInvalidClient)
+	})
+
+	t.Run("error_when_client_has_no_metadata", func(t *testing.T) {
+		h := newHandler(t, &sql.Client{ClientID: tlsClientID})
+
+		client, err := h.Authenticate(newTLSRequest(tlsClientID, leaf))
+		assert.Nil(t, client)
+		assert.ErrorIs(t, err, ErrInvalidClient)
+	})
+
+	t.Run("error_without_certificate", func(t *testing.T) {
+		h := NewTLSClientAuthHandler(rfc6749.NewMockClientStore(t))
+
+		client, err := h.Authenticate(newTLSRequest(tlsClientID))
+		assert.Nil(t, client)
+		assert.ErrorIs(t, err, ErrInvalidClient)
+	})
+
+	t.Run("error_without_client_id", func(t *testing.T) {
+		h := NewTLSClientAuthHandler(rfc6749.NewMockClientStore(t))
+
+		client, err := h.Authenticate(newTLSRequest("", leaf))
+		assert.Nil(t, client)
+		assert.ErrorIs(t, err, ErrInvalidClient)
+	})
+
+	t.Run("error_on_get_request", func(t *testing.T) {
+		h := NewTLSClientAuthHandler(rfc6749.NewMockClientStore(t))
+		r := newTLSRequest(tlsClientID, leaf)
+		r.Method = http.MethodGet
+
+		client, err := h.Authenticate(r)
+		assert.Nil(t, client)
+		assert.ErrorIs(t, err, ErrInvalidClient)
+	})
+
+	t.Run("error_when_client_not_found", func(t *testing.T) {
+		store := rfc6749.NewMockClientStore(t)
+		store.On("QueryByClientID", mock.Anything, tlsClientID).Return(nil, nil).Once()
+		h := NewTLSClientAuthHandler(store)
+
+		client, err := h.Authenticate(newTLSRequest(tlsClientID, leaf))
+		assert.Nil(t, client)
+		assert.ErrorIs(t, err, ErrInvalidClient)
+	})
+
+	t.Run("error_when_store_fails", func(t *testing.T) {
+		storeErr := errors.New("db down")
+		store := rfc6749.NewMockClientStore(t)
+		store.On("QueryByClientID", mock.Anything, tlsClientID).Return(nil, storeErr).Once()
+		h := NewTLSClientAuthHandler(store)
+
+		client, err := h.Authenticate(newTLSRequest(tlsClientID, leaf))
+		assert.Nil(t, client)
+		assert.ErrorIs(t, err, storeErr)
+	})
+}
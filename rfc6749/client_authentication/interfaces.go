@@ -3,9 +3,11 @@ package clientauth
 import (
 	"context"
 	"net/http"
+	"time"
 
 	"github.com/tniah/authlib/models"
 	"github.com/tniah/authlib/types"
+	"github.com/tniah/authlib/utils"
 )
 
 // ClientStore is the data access layer for client lookup. Implement this
@@ -26,3 +28,50 @@ type Handler interface {
 	// Return ErrInvalidClient on any authentication failure.
 	Authenticate(r *http.Request) (models.Client, error)
 }
+
+// ClientSecretProvider is implemented by clients that can use
+// client_secret_jwt. The HMAC can only be verified with the secret itself, so
+// it must be stored in a recoverable form rather than hashed.
+type ClientSecretProvider interface {
+	GetClientSecret() string
+}
+
+// JWKSProvider is implemented by clients that can use private_key_jwt.
+// GetJWKs returns the inline JWK Set (the jwks client metadata), or nil;
+// GetJWKsURI returns the URL of the client's JWK Set, used when no inline set
+// is registered (RFC 7591 §2).
+type JWKSProvider interface {
+	GetJWKs() []byte
+	GetJWKsURI() string
+}
+
+// TLSClientAuthProvider is implemented by clients that can use
+// tls_client_auth. Exactly one of the values must be set: the subject
+// distinguished name, or the DNS name, URI, IP address, or email address
+// subject alternative name the client certificate must carry (RFC 8705
+// §2.1.2). The subject DN is compared with the RFC 2253 string form produced
+// by pkix.Name.String.
+type TLSClientAuthProvider interface {
+	GetTLSClientAuthSubjectDN() string
+	GetTLSClientAuthSANDNS() string
+	GetTLSClientAuthSANURI() string
+	GetTLSClientAuthSANIP() string
+	GetTLSClientAuthSANEmail() string
+}
+
+// JWTIDCache remembers the jti of accepted client assertions so that each
+// assertion can be used only once (RFC 7523 §3, item 7). utils.MemoryJWTIDCache
+// satisfies it.
+type JWTIDCache interface {
+	// Use records jti for issuer until expiresAt. It returns false when the
+	// pair has already been recorded and has not expired yet.
+	Use(ctx context.Context, issuer, jti string, expiresAt time.Time) (bool, error)
+}
+
+// JWKSFetcher retrieves the JWK Set published at a client's jwks_uri.
+type JWKSFetcher interface {
+	// FetchJWKS returns the JWK Set at uri. forceRefresh asks to bypass any
+	// cached copy; it is set when the assertion names a kid missing from the
+	// cached set, i.e. the client has rotated its keys.
+	FetchJWKS(ctx context.Context, uri string, forceRefresh bool) (*utils.JWKSet, error)
+}
@@ -0,0 +1,194 @@
+package clientauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/tniah/authlib/models"
+	"github.com/tniah/authlib/utils"
+)
+
+// Defaults for CachingJWKSFetcher.
+const (
+	DefaultJWKSCacheTTL        = time.Hour
+	DefaultJWKSRefreshInterval = time.Minute
+	DefaultJWKSFetchTimeout    = 10 * time.Second
+
+	// maxJWKSSize caps the size of a fetched JWK Set document.
+	maxJWKSSize = 1 << 20
+)
+
+var (
+	// ErrInsecureJWKSURI is returned by CachingJWKSFetcher when jwks_uri does
+	// not use https (RFC 7591 §2).
+	ErrInsecureJWKSURI = errors.New("jwks_uri must use https")
+	// ErrJWKSUnavailable is returned by CachingJWKSFetcher when the JWK Set
+	// cannot be retrieved.
+	ErrJWKSUnavailable = errors.New("jwks unavailable")
+	// ErrNoVerificationKey is returned by ClientJWKSKey when no single key of
+	// the client's JWK Set matches the JWT.
+	ErrNoVerificationKey = errors.New("no verification key")
+)
+
+// ClientJWKSKey returns the public key of client's JWK Set, inline or fetched
+// from its jwks_uri with fetcher, matching the kid and alg of t. When t names
+// a kid missing from the cached set, the set is fetched again, as the client
+// may have rotated its keys. Without a kid, the set must hold exactly one
+// candidate key. The client must implement JWKSProvider. Shared by
+// private_key_jwt client authentication and signed request objects.
+func ClientJWKSKey(ctx context.Context, fetcher JWKSFetcher, client models.Client, t *jwt.Token) (interface{}, error) {
+	p, ok := client.(JWKSProvider)
+	if !ok {
+		return nil, ErrNoVerificationKey
+	}
+
+	kid, _ := t.Header["kid"].(string)
+	alg := t.Method.Alg()
+
+	var keys []utils.JWK
+	if jwks := p.GetJWKs(); len(jwks) > 0 {
+		set, err := utils.ParseJWKSet(jwks)
+		if err != nil {
+			return nil, err
+		}
+
+		keys = set.SigningKeys(kid, alg)
+	} else if uri := p.GetJWKsURI(); uri != "" {
+		set, err := fetcher.FetchJWKS(ctx, uri, false)
+		if err != nil {
+			return nil, err
+		}
+
+		keys = set.SigningKeys(kid, alg)
+		if len(keys) == 0 && kid != "" {
+			if set, err = fetcher.FetchJWKS(ctx, uri, true); err != nil {
+				return nil, err
+			}
+
+			keys = set.SigningKeys(kid, alg)
+		}
+	}
+
+	if len(keys) != 1 {
+		return nil, ErrNoVerificationKey
+	}
+
+	return keys[0].PublicKey()
+}
+
+// CachingJWKSFetcher is a JWKSFetcher that keeps each fetched JWK Set for
+// ttl. Forced refreshes, used when a client rotates its keys, happen at most
+// once per refreshInterval per URI, so assertions naming unknown kids cannot
+// make the server fetch on every request.
+type CachingJWKSFetcher struct {
+	lock            sync.Mutex
+	client          *http.Client
+	ttl             time.Duration
+	refreshInterval time.Duration
+	entries         map[string]*jwksEntry
+}
+
+type jwksEntry struct {
+	set       *utils.JWKSet
+	fetchedAt time.Time
+}
+
+// NewCachingJWKSFetcher returns a CachingJWKSFetcher using client, or an
+// http.Client with DefaultJWKSFetchTimeout when client is nil.
+func NewCachingJWKSFetcher(client *http.Client) *CachingJWKSFetcher {
+	if client == nil {
+		client = &http.Client{Timeout: DefaultJWKSFetchTimeout}
+	}
+
+	return &CachingJWKSFetcher{
+		client:          client,
+		ttl:             DefaultJWKSCacheTTL,
+		refreshInterval: DefaultJWKSRefreshInterval,
+		entries:         make(map[string]*jwksEntry),
+	}
+}
+
+// SetTTL overrides how long a fetched JWK Set is used. Default: DefaultJWKSCacheTTL.
+func (f *CachingJWKSFetcher) SetTTL(ttl time.Duration) {
+	f.ttl = ttl
+}
+
+// SetRefreshInterval overrides the minimum time between two fetches of the
+// same URI. Default: DefaultJWKSRefreshInterval.
+func (f *CachingJWKSFetcher) SetRefreshInterval(d time.Duration) {
+	f.refreshInterval = d
+}
+
+// FetchJWKS returns the cached JWK Set of uri, fetching it when it is missing,
+// older than ttl, or when forceRefresh is set and it is older than
+// refreshInterval.
+func (f *CachingJWKSFetcher) FetchJWKS(ctx context.Context, uri string, forceRefresh bool) (*utils.JWKSet, error) {
+	f.lock.Lock()
+	entry, ok := f.entries[uri]
+	f.lock.Unlock()
+
+	if ok {
+		age := time.Since(entry.fetchedAt)
+		if age < f.ttl && (!forceRefresh || age < f.refreshInterval) {
+			return entry.set, nil
+		}
+	}
+
+	set, err := f.fetch(ctx, uri)
+	if err != nil {
+		return nil, err
+	}
+
+	f.lock.Lock()
+	f.entries[uri] = &jwksEntry{set: set, fetchedAt: time.Now()}
+	f.lock.Unlock()
+
+	return set, nil
+}
+
+// fetch retrieves and parses the JWK Set at uri.
+func (f *CachingJWKSFetcher) fetch(ctx context.Context, uri string) (*utils.JWKSet, error) {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "https" {
+		return nil, ErrInsecureJWKSURI
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrJWKSUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: unexpected status %d", ErrJWKSUnavailable, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxJWKSSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrJWKSUnavailable, err)
+	}
+
+	if len(body) > maxJWKSSize {
+		return nil, fmt.Errorf("%w: document too large", ErrJWKSUnavailable)
+	}
+
+	set, err := utils.ParseJWKSet(body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrJWKSUnavailable, err)
+	}
+
+	return set, nil
+}
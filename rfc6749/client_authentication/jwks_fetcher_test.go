@@ -0,0 +1,99 @@
+package clientauth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCachingJWKSFetcher(t *testing.T) {
+	f := NewCachingJWKSFetcher(nil)
+	assert.Equal(t, DefaultJWKSFetchTimeout, f.client.Timeout)
+	assert.Equal(t, DefaultJWKSCacheTTL, f.ttl)
+	assert.Equal(t, DefaultJWKSRefreshInterval, f.refreshInterval)
+
+	f.SetTTL(time.Minute)
+	f.SetRefreshInterval(time.Second)
+	assert.Equal(t, time.Minute, f.ttl)
+	assert.Equal(t, time.Second, f.refreshInterval)
+}
+
+func TestCachingJWKSFetcher_FetchJWKS(t *testing.T) {
+	ctx := context.Background()
+
+	newServer := func(t *testing.T, status int, body string) (*httptest.Server, *atomic.Int32) {
+		var hits atomic.Int32
+		srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hits.Add(1)
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(body))
+		}))
+		t.Cleanup(srv.Close)
+		return srv, &hits
+	}
+
+	t.Run("caches_until_ttl", func(t *testing.T) {
+		srv, hits := newServer(t, http.StatusOK, `{"keys":[{"kty":"RSA","kid":"k1"}]}`)
+		f := NewCachingJWKSFetcher(srv.Client())
+
+		set, err := f.FetchJWKS(ctx, srv.URL, false)
+		require.NoError(t, err)
+		assert.Equal(t, "k1", set.Keys[0].Kid)
+
+		_, err = f.FetchJWKS(ctx, srv.URL, false)
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), hits.Load())
+
+		f.SetTTL(0)
+		_, err = f.FetchJWKS(ctx, srv.URL, false)
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), hits.Load())
+	})
+
+	t.Run("rate_limits_forced_refresh", func(t *testing.T) {
+		srv, hits := newServer(t, http.StatusOK, `{"keys":[]}`)
+		f := NewCachingJWKSFetcher(srv.Client())
+
+		_, err := f.FetchJWKS(ctx, srv.URL, false)
+		require.NoError(t, err)
+		_, err = f.FetchJWKS(ctx, srv.URL, true)
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), hits.Load())
+
+		f.SetRefreshInterval(0)
+		_, err = f.FetchJWKS(ctx, srv.URL, true)
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), hits.Load())
+	})
+
+	t.Run("error_on_http_uri", func(t *testing.T) {
+		f := NewCachingJWKSFetcher(nil)
+		_, err := f.FetchJWKS(ctx, "http://client.example.com/jwks.json", false)
+		assert.ErrorIs(t, err, ErrInsecureJWKSURI)
+	})
+
+	t.Run("error_on_unexpected_status", func(t *testing.T) {
+		srv, _ := newServer(t, http.StatusNotFound, "")
+		_, err := NewCachingJWKSFetcher(srv.Client()).FetchJWKS(ctx, srv.URL, false)
+		assert.ErrorIs(t, err, ErrJWKSUnavailable)
+	})
+
+	t.Run("error_on_invalid_document", func(t *testing.T) {
+		srv, _ := newServer(t, http.StatusOK, "not-json")
+		_, err := NewCachingJWKSFetcher(srv.Client()).FetchJWKS(ctx, srv.URL, false)
+		assert.ErrorIs(t, err, ErrJWKSUnavailable)
+	})
+
+	t.Run("error_on_oversized_document", func(t *testing.T) {
+		srv, _ := newServer(t, http.StatusOK, `{"keys":[],"pad":"`+strings.Repeat("a", maxJWKSSize)+`"}`)
+		_, err := NewCachingJWKSFetcher(srv.Client()).FetchJWKS(ctx, srv.URL, false)
+		assert.ErrorIs(t, err, ErrJWKSUnavailable)
+	})
+}
@@ -41,6 +41,7 @@ type Config struct {
 	// Extension slices are executed in registration order.
 	tokenReqValidators []TokenRequestValidator
 	tokenProcessors    []TokenProcessor
+	metadataProviders  []MetadataProvider
 
 	// supportedClientAuthMethods controls which client authentication methods
 	// are accepted at the token endpoint. Client Credentials defaults to basic auth only.
@@ -60,6 +61,7 @@ func NewConfig() *Config {
 		tokenEndpointHttpMethods: []string{http.MethodPost},
 		tokenReqValidators:       []TokenRequestValidator{},
 		tokenProcessors:          []TokenProcessor{},
+		metadataProviders:        []MetadataProvider{},
 		supportedClientAuthMethods: map[types.ClientAuthMethod]bool{
 			types.ClientBasicAuthentication: true,
 		},
@@ -103,7 +105,8 @@ func (cfg *Config) SetOmittedScopePolicy(p OmittedScopePolicy) *Config {
 }
 
 // RegisterExtension adds ext to every extension slice whose interface it satisfies.
-// A single object may implement both TokenRequestValidator and TokenProcessor.
+// A single object may implement TokenRequestValidator, TokenProcessor and
+// MetadataProvider.
 func (cfg *Config) RegisterExtension(ext interface{}) *Config {
 	if h, ok := ext.(TokenRequestValidator); ok {
 		cfg.tokenReqValidators = append(cfg.tokenReqValidators, h)
@@ -113,6 +116,10 @@ func (cfg *Config) RegisterExtension(ext interface{}) *Config {
 		cfg.tokenProcessors = append(cfg.tokenProcessors, h)
 	}
 
+	if h, ok := ext.(MetadataProvider); ok {
+		cfg.metadataProviders = append(cfg.metadataProviders, h)
+	}
+
 	return cfg
 }
 
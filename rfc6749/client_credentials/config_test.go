@@ -18,6 +18,7 @@ func TestNewConfig(t *testing.T) {
 	assert.Equal(t, OmittedScopePolicyReject, cfg.omittedScopePolicy)
 	assert.Empty(t, cfg.tokenReqValidators)
 	assert.Empty(t, cfg.tokenProcessors)
+	assert.Empty(t, cfg.metadataProviders)
 	assert.Nil(t, cfg.clientMgr)
 	assert.Nil(t, cfg.tokenMgr)
 }
@@ -49,15 +50,18 @@ func TestConfig_RegisterExtension(t *testing.T) {
 		cfg := NewConfig()
 		cfg.RegisterExtension(ccmock.NewMockTokenRequestValidator(t))
 		cfg.RegisterExtension(ccmock.NewMockTokenProcessor(t))
+		cfg.RegisterExtension(ccmock.NewMockMetadataProvider(t))
 
 		assert.Len(t, cfg.tokenReqValidators, 1)
 		assert.Len(t, cfg.tokenProcessors, 1)
+		assert.Len(t, cfg.metadataProviders, 1)
 	})
 
 	t.Run("registers_to_all_matching_slices", func(t *testing.T) {
 		type multiExt struct {
 			ccmock.MockTokenRequestValidator
 			ccmock.MockTokenProcessor
+			ccmock.MockMetadataProvider
 		}
 
 		cfg := NewConfig()
@@ -65,6 +69,7 @@ func TestConfig_RegisterExtension(t *testing.T) {
 
 		assert.Len(t, cfg.tokenReqValidators, 1)
 		assert.Len(t, cfg.tokenProcessors, 1)
+		assert.Len(t, cfg.metadataProviders, 1)
 	})
 
 	t.Run("ignores_non_extension_types", func(t *testing.T) {
@@ -73,6 +78,7 @@ func TestConfig_RegisterExtension(t *testing.T) {
 
 		assert.Empty(t, cfg.tokenReqValidators)
 		assert.Empty(t, cfg.tokenProcessors)
+		assert.Empty(t, cfg.metadataProviders)
 	})
 }
 
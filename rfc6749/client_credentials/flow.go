@@ -49,6 +49,19 @@ func (f *Flow) CheckGrantType(gt types.GrantType) bool {
 	return gt.IsClientCredentials()
 }
 
+// ProvideMetadata adds the client_credentials grant and the client
+// authentication methods accepted at the token endpoint to the authorization
+// server metadata (RFC 8414 §2), followed by the metadata of registered
+// MetadataProvider extensions.
+func (f *Flow) ProvideMetadata(md types.Metadata) {
+	md.Add(types.MetadataGrantTypesSupported, types.GrantTypeClientCredentials.String())
+	md.AddClientAuthMethods(types.MetadataTokenEndpointAuthMethodsSupported, f.supportedClientAuthMethods)
+
+	for _, p := range f.metadataProviders {
+		p.ProvideMetadata(md)
+	}
+}
+
 // ValidateTokenRequest runs the full validation pipeline for an incoming token
 // request: HTTP method → grant_type → client authentication → scope →
 // registered extension validators. Returns the first error encountered.
@@ -465,3 +465,20 @@ func TestFlow_ValidateTokenRequest_WithExtension(t *testing.T) {
 		mockValidator.AssertExpectations(t)
 	})
 }
+
+func TestFlow_ProvideMetadata(t *testing.T) {
+	provider := ccmock.NewMockMetadataProvider(t)
+	provider.On("ProvideMetadata", mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(0).(types.Metadata).Set(types.MetadataTLSClientCertificateBoundAccessTokens, true)
+	}).Once()
+
+	f := New(NewConfig().RegisterExtension(provider))
+	md := types.Metadata{types.MetadataGrantTypesSupported: []string{"authorization_code"}}
+	f.ProvideMetadata(md)
+
+	assert.Equal(t, types.Metadata{
+		types.MetadataGrantTypesSupported:                   []string{"authorization_code", "client_credentials"},
+		types.MetadataTokenEndpointAuthMethodsSupported:     []string{"client_secret_basic"},
+		types.MetadataTLSClientCertificateBoundAccessTokens: true,
+	}, md)
+}
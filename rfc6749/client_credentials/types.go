@@ -43,3 +43,11 @@ type TokenRequestValidator interface {
 type TokenProcessor interface {
 	ProcessToken(r *requests.TokenRequest, token models.Token, data map[string]interface{}) error
 }
+
+// MetadataProvider is an extension hook that describes the capabilities of an
+// extension in the authorization server metadata (RFC 8414 §2), such as the
+// DPoP signing algorithms. The flow forwards its own ProvideMetadata call to
+// every registered provider.
+type MetadataProvider interface {
+	ProvideMetadata(md types.Metadata)
+}
@@ -0,0 +1,133 @@
+package implicit
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/tniah/authlib/utils"
+)
+
+// Sentinel errors returned by ValidateConfig when a required dependency is missing.
+var (
+	ErrNilClientManager = errors.New("client manager is nil")
+	ErrNilTokenManager  = errors.New("token manager is nil")
+)
+
+// OmittedScopePolicy controls how the authorization endpoint behaves when the
+// client does not include a scope parameter in the request (RFC 6749 §3.3).
+type OmittedScopePolicy int
+
+const (
+	// OmittedScopePolicyReject rejects the request with invalid_scope when
+	// the scope parameter is absent. This is the default.
+	OmittedScopePolicyReject OmittedScopePolicy = iota
+
+	// OmittedScopePolicyUseClientDefault grants the client's full registered
+	// scope list when the scope parameter is absent.
+	OmittedScopePolicyUseClientDefault
+)
+
+// Config holds all dependencies and extension hooks for the Implicit flow.
+// Use NewConfig() to get a config with sensible defaults, then chain Set*/RegisterExtension
+// calls before passing to Must() or New().
+type Config struct {
+	clientMgr ClientManager
+	tokenMgr  TokenManager
+
+	authEndpointHttpMethods []string
+
+	// Extension slices are executed in registration order.
+	authReqValidators    []AuthorizationRequestValidator
+	consentReqValidators []ConsentRequestValidator
+	tokenProcessors      []TokenProcessor
+
+	// omittedScopePolicy controls the behavior when the client omits the scope
+	// parameter at /authorize (RFC 6749 §3.3). Default: OmittedScopePolicyReject.
+	omittedScopePolicy OmittedScopePolicy
+
+	// enabled controls whether the flow accepts response_type=token at all.
+	// Strict deployments following RFC 9700 §2.1.2 can switch it off.
+	enabled bool
+}
+
+// NewConfig returns a Config with the following defaults:
+//   - Accepts GET on /authorize.
+//   - Omitted scope: reject with invalid_scope (OmittedScopePolicyReject).
+//   - The flow is enabled.
+func NewConfig() *Config {
+	return &Config{
+		authEndpointHttpMethods: []string{http.MethodGet},
+		authReqValidators:       []AuthorizationRequestValidator{},
+		consentReqValidators:    []ConsentRequestValidator{},
+		tokenProcessors:         []TokenProcessor{},
+		omittedScopePolicy:      OmittedScopePolicyReject,
+		enabled:                 true,
+	}
+}
+
+// SetClientManager sets the client lookup manager.
+func (cfg *Config) SetClientManager(mgr ClientManager) *Config {
+	cfg.clientMgr = mgr
+	return cfg
+}
+
+// SetTokenManager sets the token generation and persistence manager.
+func (cfg *Config) SetTokenManager(mgr TokenManager) *Config {
+	cfg.tokenMgr = mgr
+	return cfg
+}
+
+// SetAuthEndpointHttpMethods overrides the HTTP methods accepted at /authorize.
+// Default: [GET].
+func (cfg *Config) SetAuthEndpointHttpMethods(methods []string) *Config {
+	cfg.authEndpointHttpMethods = methods
+	return cfg
+}
+
+// SetOmittedScopePolicy sets the behavior when the client omits the scope
+// parameter at /authorize (RFC 6749 §3.3). Available values:
+//   - OmittedScopePolicyReject (default): reject with invalid_scope.
+//   - OmittedScopePolicyUseClientDefault: grant the client's full registered scope list.
+func (cfg *Config) SetOmittedScopePolicy(p OmittedScopePolicy) *Config {
+	cfg.omittedScopePolicy = p
+	return cfg
+}
+
+// SetEnabled switches the flow on or off. When disabled, the flow no longer
+// claims response_type=token and the server answers unsupported_response_type.
+// Default: true.
+func (cfg *Config) SetEnabled(enabled bool) *Config {
+	cfg.enabled = enabled
+	return cfg
+}
+
+// RegisterExtension adds ext to every extension slice whose interface it satisfies.
+func (cfg *Config) RegisterExtension(ext interface{}) *Config {
+	if h, ok := ext.(AuthorizationRequestValidator); ok {
+		cfg.authReqValidators = append(cfg.authReqValidators, h)
+	}
+
+	if h, ok := ext.(ConsentRequestValidator); ok {
+		cfg.consentReqValidators = append(cfg.consentReqValidators, h)
+	}
+
+	if h, ok := ext.(TokenProcessor); ok {
+		cfg.tokenProcessors = append(cfg.tokenProcessors, h)
+	}
+
+	return cfg
+}
+
+// ValidateConfig checks that all required dependencies are set and returns the
+// first sentinel error encountered. Call this via Must() rather than directly.
+func (cfg *Config) ValidateConfig() error {
+	if utils.IsNil(cfg.clientMgr) {
+		return ErrNilClientManager
+	}
+
+	if utils.IsNil(cfg.tokenMgr) {
+		return ErrNilTokenManager
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package implicit
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	implicitmock "github.com/tniah/authlib/mocks/rfc6749/implicit"
+)
+
+func TestNewConfig(t *testing.T) {
+	cfg := NewConfig()
+	assert.Equal(t, []string{http.MethodGet}, cfg.authEndpointHttpMethods)
+	assert.Equal(t, OmittedScopePolicyReject, cfg.omittedScopePolicy)
+	assert.True(t, cfg.enabled)
+	assert.Empty(t, cfg.authReqValidators)
+	assert.Empty(t, cfg.consentReqValidators)
+	assert.Empty(t, cfg.tokenProcessors)
+	assert.Nil(t, cfg.clientMgr)
+	assert.Nil(t, cfg.tokenMgr)
+}
+
+func TestConfig_Setters(t *testing.T) {
+	cfg := NewConfig()
+
+	mockClientMgr := implicitmock.NewMockClientManager(t)
+	cfg.SetClientManager(mockClientMgr)
+	assert.Equal(t, mockClientMgr, cfg.clientMgr)
+
+	mockTokenMgr := implicitmock.NewMockTokenManager(t)
+	cfg.SetTokenManager(mockTokenMgr)
+	assert.Equal(t, mockTokenMgr, cfg.tokenMgr)
+
+	cfg.SetAuthEndpointHttpMethods([]string{http.MethodPost})
+	assert.Equal(t, []string{http.MethodPost}, cfg.authEndpointHttpMethods)
+
+	cfg.SetOmittedScopePolicy(OmittedScopePolicyUseClientDefault)
+	assert.Equal(t, OmittedScopePolicyUseClientDefault, cfg.omittedScopePolicy)
+
+	cfg.SetEnabled(false)
+	assert.False(t, cfg.enabled)
+}
+
+func TestConfig_RegisterExtension(t *testing.T) {
+	t.Run("registers_to_single_slice", func(t *testing.T) {
+		cfg := NewConfig()
+		cfg.RegisterExtension(implicitmock.NewMockAuthorizationRequestValidator(t))
+		cfg.RegisterExtension(implicitmock.NewMockConsentRequestValidator(t))
+		cfg.RegisterExtension(implicitmock.NewMockTokenProcessor(t))
+
+		assert.Len(t, cfg.authReqValidators, 1)
+		assert.Len(t, cfg.consentReqValidators, 1)
+		assert.Len(t, cfg.tokenProcessors, 1)
+	})
+
+	t.Run("registers_to_all_matching_slices", func(t *testing.T) {
+		type multiExt struct {
+			implicitmock.MockAuthorizationRequestValidator
+			implicitmock.MockTokenProcessor
+		}
+
+		cfg := NewConfig()
+		cfg.RegisterExtension(&multiExt{})
+
+		assert.Len(t, cfg.authReqValidators, 1)
+		assert.Empty(t, cfg.consentReqValidators)
+		assert.Len(t, cfg.tokenProcessors, 1)
+	})
+
+	t.Run("ignores_non_extension_types", func(t *testing.T) {
+		cfg := NewConfig()
+		cfg.RegisterExtension(struct{}{})
+
+		assert.Empty(t, cfg.authReqValidators)
+		assert.Empty(t, cfg.consentReqValidators)
+		assert.Empty(t, cfg.tokenProcessors)
+	})
+}
+
+func TestConfig_ValidateConfig(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		cfg := NewConfig().
+			SetClientManager(implicitmock.NewMockClientManager(t)).
+			SetTokenManager(implicitmock.NewMockTokenManager(t))
+		assert.NoError(t, cfg.ValidateConfig())
+	})
+
+	t.Run("error_when_client_manager_nil", func(t *testing.T) {
+		cfg := NewConfig()
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrNilClientManager)
+	})
+
+	t.Run("error_when_token_manager_nil", func(t *testing.T) {
+		cfg := NewConfig().SetClientManager(implicitmock.NewMockClientManager(t))
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrNilTokenManager)
+	})
+}
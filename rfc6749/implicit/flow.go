@@ -0,0 +1,273 @@
+package implicit
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	autherrors "github.com/tniah/authlib/errors"
+	"github.com/tniah/authlib/models"
+	"github.com/tniah/authlib/requests"
+	"github.com/tniah/authlib/rfc6749"
+	"github.com/tniah/authlib/types"
+	"github.com/tniah/authlib/utils"
+)
+
+// ErrNilToken is returned by genToken when TokenManager.New returns nil.
+var ErrNilToken = errors.New("token is nil")
+
+// Flow implements the Implicit Grant (RFC 6749 §4.2). The access token is
+// issued directly from the authorization endpoint and returned in the
+// fragment of the redirect URI. No refresh token is ever issued.
+// It satisfies the server.AuthorizationGrant and server.ConsentGrant interfaces.
+type Flow struct {
+	*Config
+	*rfc6749.TokenFlowMixin
+}
+
+// New creates a Flow from cfg without validating dependencies. Prefer Must for
+// production use to catch missing managers at startup.
+func New(cfg *Config) *Flow {
+	return &Flow{Config: cfg, TokenFlowMixin: &rfc6749.TokenFlowMixin{}}
+}
+
+// Must returns a validated Flow or an error if any required Config dependency
+// is missing. Use this in application startup to fail fast.
+func Must(cfg *Config) (*Flow, error) {
+	if err := cfg.ValidateConfig(); err != nil {
+		return nil, err
+	}
+
+	return New(cfg), nil
+}
+
+// CheckResponseType returns true for response_type=token while the flow is
+// enabled, used by the server dispatcher to route authorization requests.
+func (f *Flow) CheckResponseType(typ types.ResponseType) bool {
+	return f.enabled && typ.IsToken()
+}
+
+// ProvideMetadata adds the token response type, the implicit grant, and the
+// fragment and form_post response modes to the authorization server metadata
+// (RFC 8414 §2). Nothing is added while the flow is disabled.
+func (f *Flow) ProvideMetadata(md types.Metadata) {
+	if !f.enabled {
+		return
+	}
+
+	md.Add(types.MetadataResponseTypesSupported, types.ResponseTypeToken.String())
+	md.Add(types.MetadataResponseModesSupported, types.ResponseModeFragment.String(), types.ResponseModeFormPost.String())
+	md.Add(types.MetadataGrantTypesSupported, types.GrantTypeImplicit.String())
+}
+
+// ValidateAuthorizationRequest validates the incoming /authorize request:
+// HTTP method, client_id, redirect_uri, response_type, response_mode, scope,
+// and any registered AuthorizationRequestValidator extensions.
+func (f *Flow) ValidateAuthorizationRequest(r *requests.AuthorizationRequest) error {
+	if err := f.checkAuthEndpointHttpMethod(r); err != nil {
+		return err
+	}
+
+	if err := f.checkClient(r); err != nil {
+		return err
+	}
+
+	if err := f.validateRedirectURI(r); err != nil {
+		return err
+	}
+
+	if err := f.validateResponseType(r); err != nil {
+		return err
+	}
+
+	if err := rfc6749.ValidateResponseMode(r, false); err != nil {
+		return err
+	}
+
+	if err := f.validateScope(r); err != nil {
+		return err
+	}
+
+	r.GrantType = types.GrantTypeImplicit
+	for _, h := range f.authReqValidators {
+		if err := h.ValidateAuthorizationRequest(r); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ValidateConsentRequest re-runs ValidateAuthorizationRequest and then invokes
+// all registered ConsentRequestValidator extensions.
+func (f *Flow) ValidateConsentRequest(r *requests.AuthorizationRequest) error {
+	if err := f.ValidateAuthorizationRequest(r); err != nil {
+		return err
+	}
+
+	for _, h := range f.consentReqValidators {
+		if err := h.ValidateConsentRequest(r); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AuthorizationResponse generates the access token, runs TokenProcessor
+// extensions, saves the token, and returns the token parameters to
+// redirect_uri in the fragment (RFC 6749 §4.2.2), or in an auto-submitted form
+// with response_mode=form_post.
+// Returns access_denied if r.User is nil (i.e. the user did not authenticate).
+func (f *Flow) AuthorizationResponse(r *requests.AuthorizationRequest, rw http.ResponseWriter) error {
+	if utils.IsNil(r.User) {
+		return autherrors.AccessDeniedError().WithState(r.State).WithRedirectURI(r.RedirectURI).WithFragment()
+	}
+
+	token, err := f.genToken(r)
+	if err != nil {
+		return err
+	}
+
+	params := f.StandardTokenData(token)
+	if r.State != "" {
+		params["state"] = r.State
+	}
+
+	for _, h := range f.tokenProcessors {
+		if err = h.ProcessImplicitToken(r, token, params); err != nil {
+			return err
+		}
+	}
+
+	if err = f.tokenMgr.Save(r.Request.Context(), token); err != nil {
+		return err
+	}
+
+	mode := types.ResponseModeFragment
+	if r.ResponseMode.IsFormPost() {
+		mode = types.ResponseModeFormPost
+	}
+
+	return utils.RedirectWithResponseMode(rw, mode, r.RedirectURI, params)
+}
+
+// checkAuthEndpointHttpMethod rejects requests whose HTTP method is not in
+// authEndpointHttpMethods (default: GET).
+func (f *Flow) checkAuthEndpointHttpMethod(r *requests.AuthorizationRequest) error {
+	for _, method := range f.authEndpointHttpMethods {
+		if r.Method() == method {
+			return nil
+		}
+	}
+
+	return autherrors.InvalidRequestError().WithDescription(fmt.Sprintf("unsupported http method \"%s\"", r.Method()))
+}
+
+// checkClient validates client_id and loads the client record into r.Client.
+func (f *Flow) checkClient(r *requests.AuthorizationRequest) error {
+	if err := r.ValidateClientID(true); err != nil {
+		return err
+	}
+
+	client, err := f.clientMgr.QueryByClientID(r.Request.Context(), r.ClientID)
+	if err != nil {
+		return err
+	}
+
+	if utils.IsNil(client) {
+		return autherrors.InvalidRequestError().
+			WithDescription("No client was found that matches \"client_id\" value").
+			WithState(r.State)
+	}
+
+	r.Client = client
+	return nil
+}
+
+// validateRedirectURI ensures redirect_uri is present and registered for the
+// client. Falls back to the client's default redirect URI when omitted.
+func (f *Flow) validateRedirectURI(r *requests.AuthorizationRequest) error {
+	if r.RedirectURI == "" {
+		r.RedirectURI = r.Client.GetDefaultRedirectURI()
+		if r.RedirectURI == "" {
+			return autherrors.InvalidRequestError().
+				WithDescription("Missing \"redirect_uri\" in request").
+				WithState(r.State)
+		}
+
+		return nil
+	}
+
+	if allowed := r.Client.CheckRedirectURI(r.RedirectURI); !allowed {
+		return autherrors.InvalidRequestError().
+			WithDescription("\"redirect_uri\" is not supported by client").
+			WithState(r.State)
+	}
+
+	return nil
+}
+
+// validateResponseType verifies response_type=token, that the flow is enabled,
+// and that the client is permitted to use this response type.
+func (f *Flow) validateResponseType(r *requests.AuthorizationRequest) error {
+	if err := r.ValidateResponseType(true); err != nil {
+		return err
+	}
+
+	if valid := f.CheckResponseType(r.ResponseType); !valid {
+		return autherrors.UnsupportedResponseTypeError().WithState(r.State).WithRedirectURI(r.RedirectURI).WithFragment()
+	}
+
+	if allowed := r.Client.CheckResponseType(r.ResponseType); !allowed {
+		return autherrors.UnauthorizedClientError().WithState(r.State).WithRedirectURI(r.RedirectURI).WithFragment()
+	}
+
+	return nil
+}
+
+// validateScope filters the requested scopes through the client's allowed list.
+// When the scope parameter is absent, the behavior is governed by
+// Config.omittedScopePolicy (RFC 6749 §3.3).
+func (f *Flow) validateScope(r *requests.AuthorizationRequest) error {
+	if len(r.Scopes) == 0 {
+		switch f.omittedScopePolicy {
+		case OmittedScopePolicyUseClientDefault:
+			r.Scopes = r.Client.GetScopes()
+			return nil
+		default:
+			return autherrors.InvalidScopeError().
+				WithDescription("scope is required").
+				WithState(r.State).
+				WithRedirectURI(r.RedirectURI).
+				WithFragment()
+		}
+	}
+
+	allowed := r.Client.GetAllowedScopes(r.Scopes)
+	if len(allowed) == 0 {
+		return autherrors.InvalidScopeError().
+			WithDescription("none of the requested scopes are permitted for this client").
+			WithState(r.State).
+			WithRedirectURI(r.RedirectURI).
+			WithFragment()
+	}
+
+	r.Scopes = allowed
+	return nil
+}
+
+// genToken allocates and populates a new access token. A refresh token is
+// never included (RFC 6749 §4.2.2).
+func (f *Flow) genToken(r *requests.AuthorizationRequest) (models.Token, error) {
+	token := f.tokenMgr.New()
+	if utils.IsNil(token) {
+		return nil, ErrNilToken
+	}
+
+	if err := f.tokenMgr.Generate(token, r.TokenRequest(), false); err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
@@ -0,0 +1,407 @@
+package implicit
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	autherrors "github.com/tniah/authlib/errors"
+	"github.com/tniah/authlib/integrations/sql"
+	implicitmock "github.com/tniah/authlib/mocks/rfc6749/implicit"
+	"github.com/tniah/authlib/requests"
+	"github.com/tniah/authlib/types"
+)
+
+func newAuthReq(method string) *requests.AuthorizationRequest {
+	return &requests.AuthorizationRequest{
+		Request: httptest.NewRequest(method, "/authorize", nil),
+	}
+}
+
+func validClient() *sql.Client {
+	return &sql.Client{
+		ClientID:      "client-1",
+		RedirectURIs:  []string{"https://example.com/cb"},
+		ResponseTypes: []string{"token"},
+		Scopes:        []string{"read", "write"},
+	}
+}
+
+func TestFlow_Must(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		cfg := NewConfig().
+			SetClientManager(implicitmock.NewMockClientManager(t)).
+			SetTokenManager(implicitmock.NewMockTokenManager(t))
+
+		f, err := Must(cfg)
+		require.NoError(t, err)
+		assert.NotNil(t, f)
+	})
+
+	t.Run("error_when_config_invalid", func(t *testing.T) {
+		f, err := Must(NewConfig())
+		require.Error(t, err)
+		assert.Nil(t, f)
+	})
+}
+
+func TestFlow_CheckResponseType(t *testing.T) {
+	f := New(NewConfig())
+	cases := []struct {
+		rt       types.ResponseType
+		expected bool
+	}{
+		{types.ResponseTypeToken, true},
+		{types.ResponseTypeCode, false},
+		{types.NewResponseType(""), false},
+	}
+	for i, c := range cases {
+		assert.Equalf(t, c.expected, f.CheckResponseType(c.rt), "case %d", i)
+	}
+
+	t.Run("false_when_disabled", func(t *testing.T) {
+		f := New(NewConfig().SetEnabled(false))
+		assert.False(t, f.CheckResponseType(types.ResponseTypeToken))
+	})
+}
+
+func TestFlow_checkAuthEndpointHttpMethod(t *testing.T) {
+	f := New(NewConfig())
+
+	t.Run("success", func(t *testing.T) {
+		assert.NoError(t, f.checkAuthEndpointHttpMethod(newAuthReq(http.MethodGet)))
+	})
+
+	t.Run("error_when_method_not_allowed", func(t *testing.T) {
+		err := f.checkAuthEndpointHttpMethod(newAuthReq(http.MethodPost))
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid_request")
+	})
+}
+
+func TestFlow_checkClient(t *testing.T) {
+	mockClientMgr := implicitmock.NewMockClientManager(t)
+	f := New(NewConfig().SetClientManager(mockClientMgr))
+
+	t.Run("success", func(t *testing.T) {
+		client := validClient()
+		mockClientMgr.On("QueryByClientID", mock.Anything, "client-1").Return(client, nil).Once()
+
+		r := newAuthReq(http.MethodGet)
+		r.ClientID = "client-1"
+		assert.NoError(t, f.checkClient(r))
+		assert.Equal(t, client, r.Client)
+	})
+
+	t.Run("error_when_client_id_missing", func(t *testing.T) {
+		err := f.checkClient(newAuthReq(http.MethodGet))
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid_request")
+	})
+
+	t.Run("error_when_client_not_found", func(t *testing.T) {
+		mockClientMgr.On("QueryByClientID", mock.Anything, "unknown").Return(nil, nil).Once()
+
+		r := newAuthReq(http.MethodGet)
+		r.ClientID = "unknown"
+		err := f.checkClient(r)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid_request")
+	})
+
+	t.Run("error_when_manager_fails", func(t *testing.T) {
+		mockClientMgr.On("QueryByClientID", mock.Anything, "client-1").Return(nil, errors.New("db error")).Once()
+
+		r := newAuthReq(http.MethodGet)
+		r.ClientID = "client-1"
+		assert.ErrorContains(t, f.checkClient(r), "db error")
+	})
+}
+
+func TestFlow_validateRedirectURI(t *testing.T) {
+	f := New(NewConfig())
+
+	t.Run("success_with_registered_uri", func(t *testing.T) {
+		r := newAuthReq(http.MethodGet)
+		r.Client = validClient()
+		r.RedirectURI = "https://example.com/cb"
+		assert.NoError(t, f.validateRedirectURI(r))
+	})
+
+	t.Run("success_falls_back_to_default", func(t *testing.T) {
+		r := newAuthReq(http.MethodGet)
+		r.Client = validClient()
+		assert.NoError(t, f.validateRedirectURI(r))
+		assert.Equal(t, "https://example.com/cb", r.RedirectURI)
+	})
+
+	t.Run("error_when_uri_not_registered", func(t *testing.T) {
+		r := newAuthReq(http.MethodGet)
+		r.Client = validClient()
+		r.RedirectURI = "https://evil.example.com/cb"
+		err := f.validateRedirectURI(r)
+		authErr := autherrors.ToAuthLibError(err)
+		assert.Equal(t, autherrors.ErrInvalidRequest, authErr.Code)
+		assert.Empty(t, authErr.RedirectURI)
+	})
+}
+
+func TestFlow_validateResponseType(t *testing.T) {
+	newReq := func() *requests.AuthorizationRequest {
+		r := newAuthReq(http.MethodGet)
+		r.Client = validClient()
+		r.RedirectURI = "https://example.com/cb"
+		r.ResponseType = types.ResponseTypeToken
+		return r
+	}
+
+	t.Run("success", func(t *testing.T) {
+		f := New(NewConfig())
+		assert.NoError(t, f.validateResponseType(newReq()))
+	})
+
+	t.Run("error_when_disabled", func(t *testing.T) {
+		f := New(NewConfig().SetEnabled(false))
+		err := f.validateResponseType(newReq())
+		authErr := autherrors.ToAuthLibError(err)
+		assert.Equal(t, autherrors.ErrUnsupportedResponseType, authErr.Code)
+		assert.True(t, authErr.Fragment)
+	})
+
+	t.Run("error_when_client_not_allowed", func(t *testing.T) {
+		f := New(NewConfig())
+		r := newReq()
+		r.Client.(*sql.Client).ResponseTypes = []string{"code"}
+		err := f.validateResponseType(r)
+		authErr := autherrors.ToAuthLibError(err)
+		assert.Equal(t, autherrors.ErrUnauthorizedClient, authErr.Code)
+		assert.Equal(t, "https://example.com/cb", authErr.RedirectURI)
+		assert.True(t, authErr.Fragment)
+	})
+}
+
+func TestFlow_validateScope(t *testing.T) {
+	newReq := func(scopes ...string) *requests.AuthorizationRequest {
+		r := newAuthReq(http.MethodGet)
+		r.Client = validClient()
+		r.RedirectURI = "https://example.com/cb"
+		r.Scopes = types.NewScopes(scopes)
+		return r
+	}
+
+	t.Run("success_filters_scopes", func(t *testing.T) {
+		f := New(NewConfig())
+		r := newReq("read", "admin")
+		assert.NoError(t, f.validateScope(r))
+		assert.Equal(t, types.NewScopes([]string{"read"}), r.Scopes)
+	})
+
+	t.Run("success_uses_client_default", func(t *testing.T) {
+		f := New(NewConfig().SetOmittedScopePolicy(OmittedScopePolicyUseClientDefault))
+		r := newReq()
+		assert.NoError(t, f.validateScope(r))
+		assert.Equal(t, types.NewScopes([]string{"read", "write"}), r.Scopes)
+	})
+
+	t.Run("error_when_scope_omitted", func(t *testing.T) {
+		f := New(NewConfig())
+		authErr := autherrors.ToAuthLibError(f.validateScope(newReq()))
+		assert.Equal(t, autherrors.ErrInvalidScope, authErr.Code)
+		assert.True(t, authErr.Fragment)
+	})
+
+	t.Run("error_when_no_scope_allowed", func(t *testing.T) {
+		f := New(NewConfig())
+		authErr := autherrors.ToAuthLibError(f.validateScope(newReq("admin")))
+		assert.Equal(t, autherrors.ErrInvalidScope, authErr.Code)
+		assert.True(t, authErr.Fragment)
+	})
+}
+
+func TestFlow_ValidateAuthorizationRequest(t *testing.T) {
+	mockClientMgr := implicitmock.NewMockClientManager(t)
+	mockValidator := implicitmock.NewMockAuthorizationRequestValidator(t)
+	f := New(NewConfig().SetClientManager(mockClientMgr).RegisterExtension(mockValidator))
+
+	newReq := func() *requests.AuthorizationRequest {
+		r := newAuthReq(http.MethodGet)
+		r.ClientID = "client-1"
+		r.ResponseType = types.ResponseTypeToken
+		r.Scopes = types.NewScopes([]string{"read"})
+		return r
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockClientMgr.On("QueryByClientID", mock.Anything, "client-1").Return(validClient(), nil).Once()
+		mockValidator.On("ValidateAuthorizationRequest", mock.Anything).Return(nil).Once()
+
+		r := newReq()
+		assert.NoError(t, f.ValidateAuthorizationRequest(r))
+		assert.Equal(t, types.GrantTypeImplicit, r.GrantType)
+	})
+
+	t.Run("error_when_response_mode_is_query", func(t *testing.T) {
+		mockClientMgr.On("QueryByClientID", mock.Anything, "client-1").Return(validClient(), nil).Once()
+
+		r := newReq()
+		r.ResponseMode = types.ResponseModeQuery
+		authErr := autherrors.ToAuthLibError(f.ValidateAuthorizationRequest(r))
+		assert.Equal(t, autherrors.ErrInvalidRequest, authErr.Code)
+		assert.Empty(t, authErr.RedirectURI)
+	})
+
+	t.Run("error_when_validator_fails", func(t *testing.T) {
+		mockClientMgr.On("QueryByClientID", mock.Anything, "client-1").Return(validClient(), nil).Once()
+		mockValidator.On("ValidateAuthorizationRequest", mock.Anything).Return(errors.New("validator error")).Once()
+
+		assert.ErrorContains(t, f.ValidateAuthorizationRequest(newReq()), "validator error")
+	})
+}
+
+func TestFlow_ValidateConsentRequest(t *testing.T) {
+	mockClientMgr := implicitmock.NewMockClientManager(t)
+	mockValidator := implicitmock.NewMockConsentRequestValidator(t)
+	f := New(NewConfig().SetClientManager(mockClientMgr).RegisterExtension(mockValidator))
+
+	mockClientMgr.On("QueryByClientID", mock.Anything, "client-1").Return(validClient(), nil).Once()
+	mockValidator.On("ValidateConsentRequest", mock.Anything).Return(nil).Once()
+
+	r := newAuthReq(http.MethodGet)
+	r.ClientID = "client-1"
+	r.ResponseType = types.ResponseTypeToken
+	r.Scopes = types.NewScopes([]string{"read"})
+	assert.NoError(t, f.ValidateConsentRequest(r))
+}
+
+func TestFlow_AuthorizationResponse(t *testing.T) {
+	newReq := func() *requests.AuthorizationRequest {
+		r := newAuthReq(http.MethodGet)
+		r.Client = validClient()
+		r.User = &sql.User{}
+		r.RedirectURI = "https://example.com/cb"
+		r.State = "xyz"
+		r.GrantType = types.GrantTypeImplicit
+		r.Scopes = types.NewScopes([]string{"read"})
+		return r
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockTokenMgr := implicitmock.NewMockTokenManager(t)
+		mockProcessor := implicitmock.NewMockTokenProcessor(t)
+		f := New(NewConfig().SetTokenManager(mockTokenMgr).RegisterExtension(mockProcessor))
+
+		token := &sql.Token{TokenType: "Bearer", AccessToken: "access-token"}
+		mockTokenMgr.On("New").Return(token).Once()
+		mockTokenMgr.On("Generate", token, mock.MatchedBy(func(r *requests.TokenRequest) bool {
+			return r.GrantType.IsImplicit()
+		}), false).Return(nil).Once()
+		mockProcessor.On("ProcessImplicitToken", mock.Anything, token, mock.Anything).Return(nil).Once()
+		mockTokenMgr.On("Save", mock.Anything, token).Return(nil).Once()
+
+		rw := httptest.NewRecorder()
+		require.NoError(t, f.AuthorizationResponse(newReq(), rw))
+		assert.Equal(t, http.StatusFound, rw.Code)
+
+		location, err := url.Parse(rw.Header().Get("Location"))
+		require.NoError(t, err)
+		assert.Empty(t, location.RawQuery)
+
+		fragment, err := url.ParseQuery(location.Fragment)
+		require.NoError(t, err)
+		assert.Equal(t, "access-token", fragment.Get("access_token"))
+		assert.Equal(t, "Bearer", fragment.Get("token_type"))
+		assert.Equal(t, "xyz", fragment.Get("state"))
+		assert.Empty(t, fragment.Get("refresh_token"))
+	})
+
+	t.Run("success_with_form_post", func(t *testing.T) {
+		mockTokenMgr := implicitmock.NewMockTokenManager(t)
+		f := New(NewConfig().SetTokenManager(mockTokenMgr))
+
+		token := &sql.Token{TokenType: "Bearer", AccessToken: "access-token"}
+		mockTokenMgr.On("New").Return(token).Once()
+		mockTokenMgr.On("Generate", token, mock.Anything, false).Return(nil).Once()
+		mockTokenMgr.On("Save", mock.Anything, token).Return(nil).Once()
+
+		r := newReq()
+		r.ResponseMode = types.ResponseModeFormPost
+		rw := httptest.NewRecorder()
+		require.NoError(t, f.AuthorizationResponse(r, rw))
+		assert.Equal(t, http.StatusOK, rw.Code)
+		assert.Empty(t, rw.Header().Get("Location"))
+		assert.Contains(t, rw.Body.String(), `action="https://example.com/cb"`)
+		assert.Contains(t, rw.Body.String(), `name="access_token" value="access-token"`)
+		assert.Contains(t, rw.Body.String(), `name="state" value="xyz"`)
+	})
+
+	t.Run("error_when_user_nil", func(t *testing.T) {
+		f := New(NewConfig())
+		r := newReq()
+		r.User = nil
+		authErr := autherrors.ToAuthLibError(f.AuthorizationResponse(r, httptest.NewRecorder()))
+		assert.Equal(t, autherrors.ErrAccessDenied, authErr.Code)
+		assert.True(t, authErr.Fragment)
+	})
+
+	t.Run("error_when_gen_token_fails", func(t *testing.T) {
+		mockTokenMgr := implicitmock.NewMockTokenManager(t)
+		f := New(NewConfig().SetTokenManager(mockTokenMgr))
+
+		mockTokenMgr.On("New").Return(nil).Once()
+		assert.ErrorIs(t, f.AuthorizationResponse(newReq(), httptest.NewRecorder()), ErrNilToken)
+	})
+
+	t.Run("error_when_generate_fails", func(t *testing.T) {
+		mockTokenMgr := implicitmock.NewMockTokenManager(t)
+		f := New(NewConfig().SetTokenManager(mockTokenMgr))
+
+		mockTokenMgr.On("New").Return(&sql.Token{}).Once()
+		mockTokenMgr.On("Generate", mock.Anything, mock.Anything, false).Return(errors.New("gen error")).Once()
+		assert.ErrorContains(t, f.AuthorizationResponse(newReq(), httptest.NewRecorder()), "gen error")
+	})
+
+	t.Run("error_when_processor_fails", func(t *testing.T) {
+		mockTokenMgr := implicitmock.NewMockTokenManager(t)
+		mockProcessor := implicitmock.NewMockTokenProcessor(t)
+		f := New(NewConfig().SetTokenManager(mockTokenMgr).RegisterExtension(mockProcessor))
+
+		mockTokenMgr.On("New").Return(&sql.Token{}).Once()
+		mockTokenMgr.On("Generate", mock.Anything, mock.Anything, false).Return(nil).Once()
+		mockProcessor.On("ProcessImplicitToken", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("processor error")).Once()
+		assert.ErrorContains(t, f.AuthorizationResponse(newReq(), httptest.NewRecorder()), "processor error")
+	})
+
+	t.Run("error_when_save_fails", func(t *testing.T) {
+		mockTokenMgr := implicitmock.NewMockTokenManager(t)
+		f := New(NewConfig().SetTokenManager(mockTokenMgr))
+
+		mockTokenMgr.On("New").Return(&sql.Token{}).Once()
+		mockTokenMgr.On("Generate", mock.Anything, mock.Anything, false).Return(nil).Once()
+		mockTokenMgr.On("Save", mock.Anything, mock.Anything).Return(errors.New("db error")).Once()
+		assert.ErrorContains(t, f.AuthorizationResponse(newReq(), httptest.NewRecorder()), "db error")
+	})
+}
+
+func TestFlow_ProvideMetadata(t *testing.T) {
+	t.Run("enabled", func(t *testing.T) {
+		md := types.Metadata{}
+		New(NewConfig()).ProvideMetadata(md)
+
+		assert.Equal(t, types.Metadata{
+			types.MetadataResponseTypesSupported: []string{"token"},
+			types.MetadataResponseModesSupported: []string{"fragment", "form_post"},
+			types.MetadataGrantTypesSupported:    []string{"implicit"},
+		}, md)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		md := types.Metadata{}
+		New(NewConfig().SetEnabled(false)).ProvideMetadata(md)
+		assert.Empty(t, md)
+	})
+}
@@ -0,0 +1,48 @@
+package implicit
+
+import (
+	"context"
+
+	"github.com/tniah/authlib/models"
+	"github.com/tniah/authlib/requests"
+)
+
+// ClientManager handles client lookup at the authorization endpoint.
+type ClientManager interface {
+	// QueryByClientID retrieves the client with the given client_id.
+	// Return (nil, nil) when the client does not exist.
+	QueryByClientID(ctx context.Context, clientID string) (models.Client, error)
+}
+
+// TokenManager generates and persists access tokens. The implicit flow never
+// requests a refresh token (RFC 6749 §4.2.2).
+type TokenManager interface {
+	// New allocates a blank Token ready to be populated by Generate.
+	New() models.Token
+
+	// Generate populates token with a value, expiry, scopes, and client/user
+	// binding. includeRefreshToken is always false for this flow.
+	Generate(token models.Token, r *requests.TokenRequest, includeRefreshToken bool) error
+
+	// Save persists the token to the backing store.
+	Save(ctx context.Context, token models.Token) error
+}
+
+// AuthorizationRequestValidator is an extension hook called during
+// ValidateAuthorizationRequest, after the built-in checks pass.
+type AuthorizationRequestValidator interface {
+	ValidateAuthorizationRequest(r *requests.AuthorizationRequest) error
+}
+
+// ConsentRequestValidator is an extension hook called during
+// ValidateConsentRequest, after the built-in checks pass.
+type ConsentRequestValidator interface {
+	ValidateConsentRequest(r *requests.AuthorizationRequest) error
+}
+
+// TokenProcessor is an extension hook called after the access token is
+// generated and before it is saved. Use it to add extra parameters to the
+// fragment response.
+type TokenProcessor interface {
+	ProcessImplicitToken(r *requests.AuthorizationRequest, token models.Token, params map[string]interface{}) error
+}
@@ -0,0 +1,162 @@
+package refreshtoken
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/tniah/authlib/types"
+	"github.com/tniah/authlib/utils"
+)
+
+// Sentinel errors returned by ValidateConfig when a required dependency is missing.
+var (
+	ErrNilClientManager       = errors.New("client manager is nil")
+	ErrNilUserManager         = errors.New("user manager is nil")
+	ErrNilTokenManager        = errors.New("token manager is nil")
+	ErrEmptyClientAuthMethods = errors.New("client auth methods are empty")
+	ErrNegativeGracePeriod    = errors.New("reuse grace period must not be negative")
+)
+
+// Config holds all dependencies and extension hooks for the Refresh Token grant.
+// Use NewConfig() to get a config with sensible defaults, then chain Set*/RegisterExtension
+// calls before passing to Must() or New().
+type Config struct {
+	clientMgr ClientManager
+	userMgr   UserManager
+	tokenMgr  TokenManager
+
+	tokenEndpointHttpMethods []string
+
+	// Extension slices are executed in registration order.
+	tokenReqValidators []TokenRequestValidator
+	tokenProcessors    []TokenProcessor
+	metadataProviders  []MetadataProvider
+
+	// supportedClientAuthMethods controls which authentication methods are
+	// accepted at the token endpoint (basic, post, none).
+	supportedClientAuthMethods map[types.ClientAuthMethod]bool
+
+	// rotateRefreshToken controls whether a new refresh token is issued on
+	// every refresh, invalidating the presented one (RFC 9700 §4.14.2).
+	rotateRefreshToken bool
+
+	// reuseGracePeriod is how long a rotated refresh token may still be
+	// exchanged before replay is treated as token theft.
+	reuseGracePeriod time.Duration
+}
+
+// NewConfig returns a Config with secure defaults:
+//   - Accepts POST on /token.
+//   - Supports basic and none client authentication methods.
+//   - Refresh token rotation is enabled.
+//   - No reuse grace period: any replay of a rotated refresh token revokes its family.
+func NewConfig() *Config {
+	return &Config{
+		supportedClientAuthMethods: map[types.ClientAuthMethod]bool{
+			types.ClientBasicAuthentication: true,
+			types.ClientNoneAuthentication:  true,
+		},
+		tokenEndpointHttpMethods: []string{http.MethodPost},
+		tokenReqValidators:       []TokenRequestValidator{},
+		tokenProcessors:          []TokenProcessor{},
+		metadataProviders:        []MetadataProvider{},
+		rotateRefreshToken:       true,
+	}
+}
+
+// SetClientManager sets the client authentication manager.
+func (cfg *Config) SetClientManager(mgr ClientManager) *Config {
+	cfg.clientMgr = mgr
+	return cfg
+}
+
+// SetUserManager sets the user resolver used to look up the resource owner
+// associated with the refresh token.
+func (cfg *Config) SetUserManager(mgr UserManager) *Config {
+	cfg.userMgr = mgr
+	return cfg
+}
+
+// SetTokenManager sets the token lookup, generation, and persistence manager.
+func (cfg *Config) SetTokenManager(mgr TokenManager) *Config {
+	cfg.tokenMgr = mgr
+	return cfg
+}
+
+// SetTokenEndpointHttpMethods overrides the HTTP methods accepted at /token.
+// Default: [POST].
+func (cfg *Config) SetTokenEndpointHttpMethods(methods []string) *Config {
+	cfg.tokenEndpointHttpMethods = methods
+	return cfg
+}
+
+// SetSupportedClientAuthMethods overrides which client authentication methods
+// are accepted at the token endpoint. Default: basic and none.
+func (cfg *Config) SetSupportedClientAuthMethods(methods map[types.ClientAuthMethod]bool) *Config {
+	cfg.supportedClientAuthMethods = methods
+	return cfg
+}
+
+// SetRotateRefreshToken controls refresh token rotation. When enabled (the
+// default), every refresh issues a new refresh token and marks the presented
+// one as rotated. When disabled, only a new access token is issued and the
+// client keeps using its current refresh token.
+func (cfg *Config) SetRotateRefreshToken(value bool) *Config {
+	cfg.rotateRefreshToken = value
+	return cfg
+}
+
+// SetReuseGracePeriod sets how long a rotated refresh token remains
+// exchangeable. Requests inside the window are served normally, which lets
+// parallel refreshes from the same client succeed; replay after the window
+// revokes the whole token family. Default: 0 (no grace period).
+func (cfg *Config) SetReuseGracePeriod(d time.Duration) *Config {
+	cfg.reuseGracePeriod = d
+	return cfg
+}
+
+// RegisterExtension adds ext to every extension slice whose interface it satisfies.
+// A single object may implement TokenRequestValidator, TokenProcessor and
+// MetadataProvider.
+func (cfg *Config) RegisterExtension(ext interface{}) *Config {
+	if h, ok := ext.(TokenRequestValidator); ok {
+		cfg.tokenReqValidators = append(cfg.tokenReqValidators, h)
+	}
+
+	if h, ok := ext.(TokenProcessor); ok {
+		cfg.tokenProcessors = append(cfg.tokenProcessors, h)
+	}
+
+	if h, ok := ext.(MetadataProvider); ok {
+		cfg.metadataProviders = append(cfg.metadataProviders, h)
+	}
+
+	return cfg
+}
+
+// ValidateConfig checks that all required dependencies are set and returns the
+// first sentinel error encountered. Call this via Must() rather than directly.
+func (cfg *Config) ValidateConfig() error {
+	if utils.IsNil(cfg.clientMgr) {
+		return ErrNilClientManager
+	}
+
+	if utils.IsNil(cfg.userMgr) {
+		return ErrNilUserManager
+	}
+
+	if utils.IsNil(cfg.tokenMgr) {
+		return ErrNilTokenManager
+	}
+
+	if len(cfg.supportedClientAuthMethods) == 0 {
+		return ErrEmptyClientAuthMethods
+	}
+
+	if cfg.reuseGracePeriod < 0 {
+		return ErrNegativeGracePeriod
+	}
+
+	return nil
+}
@@ -0,0 +1,134 @@
+package refreshtoken
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	refreshtoken "github.com/tniah/authlib/mocks/rfc6749/refresh_token"
+	"github.com/tniah/authlib/types"
+)
+
+func TestNewConfig(t *testing.T) {
+	cfg := NewConfig()
+	assert.Equal(t, []string{http.MethodPost}, cfg.tokenEndpointHttpMethods)
+	assert.Equal(t, map[types.ClientAuthMethod]bool{
+		types.ClientBasicAuthentication: true,
+		types.ClientNoneAuthentication:  true,
+	}, cfg.supportedClientAuthMethods)
+	assert.True(t, cfg.rotateRefreshToken)
+	assert.Zero(t, cfg.reuseGracePeriod)
+	assert.Empty(t, cfg.tokenReqValidators)
+	assert.Empty(t, cfg.tokenProcessors)
+	assert.Empty(t, cfg.metadataProviders)
+	assert.Nil(t, cfg.clientMgr)
+	assert.Nil(t, cfg.userMgr)
+	assert.Nil(t, cfg.tokenMgr)
+}
+
+func TestConfig_Setters(t *testing.T) {
+	cfg := NewConfig()
+
+	mockClientMgr := refreshtoken.NewMockClientManager(t)
+	cfg.SetClientManager(mockClientMgr)
+	assert.Equal(t, mockClientMgr, cfg.clientMgr)
+
+	mockUserMgr := refreshtoken.NewMockUserManager(t)
+	cfg.SetUserManager(mockUserMgr)
+	assert.Equal(t, mockUserMgr, cfg.userMgr)
+
+	mockTokenMgr := refreshtoken.NewMockTokenManager(t)
+	cfg.SetTokenManager(mockTokenMgr)
+	assert.Equal(t, mockTokenMgr, cfg.tokenMgr)
+
+	methods := map[types.ClientAuthMethod]bool{types.ClientPostAuthentication: true}
+	cfg.SetSupportedClientAuthMethods(methods)
+	assert.Equal(t, methods, cfg.supportedClientAuthMethods)
+
+	cfg.SetTokenEndpointHttpMethods([]string{http.MethodPut})
+	assert.Equal(t, []string{http.MethodPut}, cfg.tokenEndpointHttpMethods)
+
+	cfg.SetRotateRefreshToken(false)
+	assert.False(t, cfg.rotateRefreshToken)
+
+	cfg.SetReuseGracePeriod(30 * time.Second)
+	assert.Equal(t, 30*time.Second, cfg.reuseGracePeriod)
+}
+
+func TestConfig_RegisterExtension(t *testing.T) {
+	t.Run("registers_to_single_slice", func(t *testing.T) {
+		cfg := NewConfig()
+		cfg.RegisterExtension(refreshtoken.NewMockTokenRequestValidator(t))
+		cfg.RegisterExtension(refreshtoken.NewMockTokenProcessor(t))
+		cfg.RegisterExtension(refreshtoken.NewMockMetadataProvider(t))
+
+		assert.Len(t, cfg.tokenReqValidators, 1)
+		assert.Len(t, cfg.tokenProcessors, 1)
+		assert.Len(t, cfg.metadataProviders, 1)
+	})
+
+	t.Run("registers_to_all_matching_slices", func(t *testing.T) {
+		type multiExt struct {
+			refreshtoken.MockTokenRequestValidator
+			refreshtoken.MockTokenProcessor
+			refreshtoken.MockMetadataProvider
+		}
+
+		cfg := NewConfig()
+		cfg.RegisterExtension(&multiExt{})
+
+		assert.Len(t, cfg.tokenReqValidators, 1)
+		assert.Len(t, cfg.tokenProcessors, 1)
+		assert.Len(t, cfg.metadataProviders, 1)
+	})
+
+	t.Run("ignores_non_extension_types", func(t *testing.T) {
+		cfg := NewConfig()
+		cfg.RegisterExtension(struct{}{})
+
+		assert.Empty(t, cfg.tokenReqValidators)
+		assert.Empty(t, cfg.tokenProcessors)
+		assert.Empty(t, cfg.metadataProviders)
+	})
+}
+
+func TestConfig_ValidateConfig(t *testing.T) {
+	newValidConfig := func() *Config {
+		return NewConfig().
+			SetClientManager(refreshtoken.NewMockClientManager(t)).
+			SetUserManager(refreshtoken.NewMockUserManager(t)).
+			SetTokenManager(refreshtoken.NewMockTokenManager(t))
+	}
+
+	t.Run("success", func(t *testing.T) {
+		assert.NoError(t, newValidConfig().ValidateConfig())
+	})
+
+	t.Run("error_when_client_manager_nil", func(t *testing.T) {
+		cfg := NewConfig()
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrNilClientManager)
+	})
+
+	t.Run("error_when_user_manager_nil", func(t *testing.T) {
+		cfg := NewConfig().SetClientManager(refreshtoken.NewMockClientManager(t))
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrNilUserManager)
+	})
+
+	t.Run("error_when_token_manager_nil", func(t *testing.T) {
+		cfg := NewConfig().
+			SetClientManager(refreshtoken.NewMockClientManager(t)).
+			SetUserManager(refreshtoken.NewMockUserManager(t))
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrNilTokenManager)
+	})
+
+	t.Run("error_when_client_auth_methods_empty", func(t *testing.T) {
+		cfg := newValidConfig().SetSupportedClientAuthMethods(nil)
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrEmptyClientAuthMethods)
+	})
+
+	t.Run("error_when_grace_period_negative", func(t *testing.T) {
+		cfg := newValidConfig().SetReuseGracePeriod(-time.Second)
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrNegativeGracePeriod)
+	})
+}
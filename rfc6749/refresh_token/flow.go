@@ -0,0 +1,310 @@
+package refreshtoken
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	autherrors "github.com/tniah/authlib/errors"
+	"github.com/tniah/authlib/models"
+	"github.com/tniah/authlib/requests"
+	"github.com/tniah/authlib/rfc6749"
+	"github.com/tniah/authlib/types"
+	"github.com/tniah/authlib/utils"
+)
+
+// EndpointToken is the endpoint name passed to ClientManager.Authenticate so
+// that the client store can apply per-endpoint auth method policies.
+const EndpointToken = "token"
+
+// ErrNilToken is returned by genToken when TokenManager.New returns nil.
+var ErrNilToken = errors.New("token is nil")
+
+// familyNamespace is the UUID namespace of the family IDs derived from
+// refresh tokens that were issued without one.
+var familyNamespace = uuid.MustParse("3d8f5a34-6c1e-4b7a-9f0d-2e5c8b1a7d46")
+
+// Flow implements the Refresh Token grant (RFC 6749 §6) with optional refresh
+// token rotation and reuse detection (RFC 9700 §4.14.2).
+type Flow struct {
+	*Config
+	*rfc6749.TokenFlowMixin
+}
+
+// New creates a Flow without validating config. Use Must for production use.
+func New(cfg *Config) *Flow {
+	return &Flow{Config: cfg, TokenFlowMixin: &rfc6749.TokenFlowMixin{}}
+}
+
+// Must returns a validated Flow or an error if the config is incomplete.
+func Must(cfg *Config) (*Flow, error) {
+	if err := cfg.ValidateConfig(); err != nil {
+		return nil, err
+	}
+
+	return New(cfg), nil
+}
+
+// CheckGrantType reports whether this flow handles the given grant_type.
+func (f *Flow) CheckGrantType(gt types.GrantType) bool {
+	return gt.IsRefreshToken()
+}
+
+// ProvideMetadata adds the refresh_token grant and the client authentication
+// methods accepted at the token endpoint to the authorization server metadata
+// (RFC 8414 §2), followed by the metadata of registered MetadataProvider
+// extensions.
+func (f *Flow) ProvideMetadata(md types.Metadata) {
+	md.Add(types.MetadataGrantTypesSupported, types.GrantTypeRefreshToken.String())
+	md.AddClientAuthMethods(types.MetadataTokenEndpointAuthMethodsSupported, f.supportedClientAuthMethods)
+
+	for _, p := range f.metadataProviders {
+		p.ProvideMetadata(md)
+	}
+}
+
+// ValidateTokenRequest validates the /token request: HTTP method, grant_type,
+// refresh_token, client authentication, refresh token state, scope, the
+// resource owner, and any registered TokenRequestValidator extensions.
+func (f *Flow) ValidateTokenRequest(r *requests.TokenRequest) error {
+	if err := f.checkParams(r); err != nil {
+		return err
+	}
+
+	if err := f.authenticateClient(r); err != nil {
+		return err
+	}
+
+	if err := f.validateRefreshToken(r); err != nil {
+		return err
+	}
+
+	if err := f.validateScope(r); err != nil {
+		return err
+	}
+
+	if err := f.queryUserByToken(r); err != nil {
+		return err
+	}
+
+	for _, h := range f.tokenReqValidators {
+		if err := h.ValidateTokenRequest(r); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// TokenResponse issues a new access token (and a new refresh token when
+// rotation is enabled), runs TokenProcessor extensions, persists the new token,
+// records the rotation on the presented token, and writes the JSON response
+// (RFC 6749 §5.1).
+func (f *Flow) TokenResponse(r *requests.TokenRequest, rw http.ResponseWriter) error {
+	token, err := f.genToken(r)
+	if err != nil {
+		return err
+	}
+
+	data := f.StandardTokenData(token)
+	for _, h := range f.tokenProcessors {
+		if err = h.ProcessToken(r, token, data); err != nil {
+			return err
+		}
+	}
+
+	if err = f.tokenMgr.Save(r.Request.Context(), token); err != nil {
+		return err
+	}
+
+	if err = f.markRotated(r, token); err != nil {
+		return err
+	}
+
+	return f.HandleTokenResponse(rw, data)
+}
+
+// checkParams validates the HTTP method, grant_type, and refresh_token before
+// any manager calls are made.
+func (f *Flow) checkParams(r *requests.TokenRequest) error {
+	if err := f.checkTokenEndpointHttpMethod(r); err != nil {
+		return err
+	}
+
+	if err := f.validateGrantType(r); err != nil {
+		return err
+	}
+
+	if err := r.ValidateRefreshToken(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// checkTokenEndpointHttpMethod rejects requests whose HTTP method is not in
+// tokenEndpointHttpMethods (default: POST).
+func (f *Flow) checkTokenEndpointHttpMethod(r *requests.TokenRequest) error {
+	for _, method := range f.tokenEndpointHttpMethods {
+		if r.Method() == method {
+			return nil
+		}
+	}
+
+	return autherrors.InvalidRequestError().WithDescription(fmt.Sprintf("unsupported http method \"%s\"", r.Method()))
+}
+
+// validateGrantType checks that grant_type is present and equals "refresh_token".
+func (f *Flow) validateGrantType(r *requests.TokenRequest) error {
+	if err := r.ValidateGrantType(); err != nil {
+		return err
+	}
+
+	if valid := r.GrantType.IsRefreshToken(); !valid {
+		return autherrors.UnsupportedGrantTypeError()
+	}
+
+	return nil
+}
+
+// authenticateClient delegates to ClientManager.Authenticate, then verifies the
+// client is permitted to use the refresh_token grant.
+func (f *Flow) authenticateClient(r *requests.TokenRequest) error {
+	client, err := f.clientMgr.Authenticate(r.Request, f.supportedClientAuthMethods, EndpointToken)
+	if err != nil {
+		return err
+	}
+
+	if utils.IsNil(client) {
+		return autherrors.InvalidClientError()
+	}
+
+	if allowed := client.CheckGrantType(types.GrantTypeRefreshToken); !allowed {
+		return autherrors.UnauthorizedClientError().WithDescription("The client is not authorized to use grant type \"refresh_token\"")
+	}
+
+	r.Client = client
+	return nil
+}
+
+// validateRefreshToken verifies the refresh token: existence, client binding,
+// expiry, and rotation state (RFC 6749 §6). A rotated token is accepted only
+// within the reuse grace period; outside it, the whole token family is revoked.
+// Populates r.Token on success.
+func (f *Flow) validateRefreshToken(r *requests.TokenRequest) error {
+	ctx := r.Request.Context()
+	token, err := f.tokenMgr.QueryByRefreshToken(ctx, r.RefreshToken)
+	if err != nil {
+		return err
+	}
+
+	if utils.IsNil(token) {
+		return autherrors.InvalidGrantError().WithDescription("Invalid \"refresh_token\" in request")
+	}
+
+	// RFC 6749 §6: the refresh token MUST be bound to the authenticated client.
+	if token.GetClientID() != r.Client.GetClientID() {
+		return autherrors.InvalidGrantError().WithDescription("\"refresh_token\" was not issued to this client")
+	}
+
+	now := time.Now().UTC().Round(time.Second)
+
+	// A zero lifetime means the refresh token does not expire.
+	if exp := token.GetRefreshTokenExpiresIn(); exp > 0 && token.GetIssuedAt().Add(exp).Before(now) {
+		return autherrors.InvalidGrantError().WithDescription("\"refresh_token\" has been expired")
+	}
+
+	if rotatedAt := token.GetRotatedAt(); !rotatedAt.IsZero() && now.Sub(rotatedAt) > f.reuseGracePeriod {
+		// RFC 9700 §4.14.2: replay of a rotated refresh token indicates that
+		// either the client or an attacker holds a stale copy; revoke the family.
+		if err = f.tokenMgr.RevokeTokenFamily(ctx, familyID(token)); err != nil {
+			return err
+		}
+
+		return autherrors.InvalidGrantError().WithDescription("\"refresh_token\" has already been used")
+	}
+
+	r.Token = token
+	return nil
+}
+
+// validateScope enforces RFC 6749 §6: the requested scope MUST NOT include any
+// scope not originally granted. When the scope parameter is omitted, the
+// original scope is reused.
+func (f *Flow) validateScope(r *requests.TokenRequest) error {
+	granted := r.Token.GetScopes()
+	if len(r.Scopes) == 0 {
+		r.Scopes = granted
+		return nil
+	}
+
+	for _, scope := range r.Scopes {
+		if !granted.Contain(scope) {
+			return autherrors.InvalidScopeError().WithDescription("the requested scope exceeds the scope originally granted")
+		}
+	}
+
+	return nil
+}
+
+// queryUserByToken resolves the resource owner the refresh token was issued for
+// and populates r.User. Returns invalid_grant if no user is found.
+func (f *Flow) queryUserByToken(r *requests.TokenRequest) error {
+	if r.Token.GetUserID() == "" {
+		return autherrors.InvalidGrantError().WithDescription("No user could be found associated with this refresh token")
+	}
+
+	user, err := f.userMgr.QueryUserByToken(r.Request.Context(), r.Token, r)
+	if err != nil {
+		return err
+	}
+
+	if utils.IsNil(user) {
+		return autherrors.InvalidGrantError().WithDescription("No user could be found associated with this refresh token")
+	}
+
+	r.User = user
+	return nil
+}
+
+// genToken allocates and populates a new token in the same family as the
+// presented refresh token. A new refresh token is included only when rotation
+// is enabled.
+func (f *Flow) genToken(r *requests.TokenRequest) (models.Token, error) {
+	token := f.tokenMgr.New()
+	if utils.IsNil(token) {
+		return nil, ErrNilToken
+	}
+
+	if err := f.tokenMgr.Generate(token, r, f.rotateRefreshToken); err != nil {
+		return nil, err
+	}
+
+	token.SetFamilyID(familyID(r.Token))
+	return token, nil
+}
+
+// markRotated stores the family ID on the presented token and, when rotation
+// is enabled, records the time it was first rotated. The first rotation time
+// is kept on replay within the grace period so the window cannot be extended.
+func (f *Flow) markRotated(r *requests.TokenRequest, token models.Token) error {
+	r.Token.SetFamilyID(token.GetFamilyID())
+	if f.rotateRefreshToken && r.Token.GetRotatedAt().IsZero() {
+		r.Token.SetRotatedAt(time.Now().UTC().Round(time.Second))
+	}
+
+	return f.tokenMgr.Update(r.Request.Context(), r.Token)
+}
+
+// familyID returns the family ID of token. A token issued without one starts
+// a family whose ID is derived from the refresh token, so concurrent refreshes
+// of the same token within the grace period join the same family.
+func familyID(token models.Token) string {
+	if id := token.GetFamilyID(); id != "" {
+		return id
+	}
+
+	return uuid.NewSHA1(familyNamespace, []byte(token.GetRefreshToken())).String()
+}
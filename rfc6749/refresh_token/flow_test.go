@@ -0,0 +1,635 @@
+package refreshtoken
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/tniah/authlib/integrations/sql"
+	refreshtoken "github.com/tniah/authlib/mocks/rfc6749/refresh_token"
+	"github.com/tniah/authlib/requests"
+	"github.com/tniah/authlib/types"
+)
+
+func TestFlow_Must(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockClientMgr := refreshtoken.NewMockClientManager(t)
+		mockUserMgr := refreshtoken.NewMockUserManager(t)
+		mockTokMgr := refreshtoken.NewMockTokenManager(t)
+
+		f, err := Must(NewConfig().SetClientManager(mockClientMgr).SetUserManager(mockUserMgr).SetTokenManager(mockTokMgr))
+		require.NoError(t, err)
+		assert.NotNil(t, f)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		f, err := Must(NewConfig().SetClientManager(nil))
+		require.Error(t, err)
+		assert.Nil(t, f)
+	})
+}
+
+func TestFlow_CheckGrantType(t *testing.T) {
+	f := New(NewConfig())
+	cases := []struct {
+		grantType types.GrantType
+		expected  bool
+	}{
+		{
+			types.GrantTypeRefreshToken,
+			true,
+		},
+		{
+			types.GrantTypeROPC,
+			false,
+		},
+		{
+			types.NewGrantType(""),
+			false,
+		},
+	}
+
+	for i, c := range cases {
+		valid := f.CheckGrantType(c.grantType)
+		assert.Equalf(t, c.expected, valid, "case %d failed: expected=%t, actual=%t", i, c.expected, valid)
+	}
+}
+
+func TestFlow_checkParams(t *testing.T) {
+	f := New(NewConfig())
+	t.Run("success", func(t *testing.T) {
+		r := &requests.TokenRequest{
+			Request:      httptest.NewRequest(http.MethodPost, "/", nil),
+			GrantType:    types.GrantTypeRefreshToken,
+			RefreshToken: "myrefresh",
+		}
+		err := f.checkParams(r)
+		assert.NoError(t, err)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		cases := []struct {
+			r     *requests.TokenRequest
+			error string
+		}{
+			{
+				&requests.TokenRequest{
+					Request: httptest.NewRequest(http.MethodDelete, "/", nil),
+				},
+				"invalid_request",
+			},
+			{
+				&requests.TokenRequest{
+					Request:   httptest.NewRequest(http.MethodPost, "/", nil),
+					GrantType: "",
+				},
+				"invalid_request",
+			},
+			{
+				&requests.TokenRequest{
+					Request:   httptest.NewRequest(http.MethodPost, "/", nil),
+					GrantType: types.GrantTypeROPC,
+				},
+				"unsupported_grant_type",
+			},
+			{
+				&requests.TokenRequest{
+					Request:   httptest.NewRequest(http.MethodPost, "/", nil),
+					GrantType: types.GrantTypeRefreshToken,
+				},
+				"invalid_request",
+			},
+		}
+		for i, c := range cases {
+			err := f.checkParams(c.r)
+			assert.Error(t, err)
+			assert.Containsf(t, err.Error(), c.error, "case %d failed", i)
+		}
+	})
+}
+
+func TestFlow_authenticateClient(t *testing.T) {
+	mockClientMgr := refreshtoken.NewMockClientManager(t)
+	f := New(NewConfig().SetClientManager(mockClientMgr))
+
+	newReq := func() *requests.TokenRequest {
+		return &requests.TokenRequest{
+			Request: httptest.NewRequest(http.MethodPost, "/", nil),
+		}
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockClient := &sql.Client{
+			GrantTypes: []string{types.GrantTypeRefreshToken.String()},
+		}
+		mockClientMgr.On("Authenticate", mock.Anything, mock.Anything, EndpointToken).Return(mockClient, nil).Once()
+
+		r := newReq()
+		err := f.authenticateClient(r)
+		assert.NoError(t, err)
+		assert.Equal(t, mockClient, r.Client)
+	})
+
+	t.Run("error_when_client_not_found", func(t *testing.T) {
+		mockClientMgr.On("Authenticate", mock.Anything, mock.Anything, EndpointToken).Return(nil, nil).Once()
+
+		err := f.authenticateClient(newReq())
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid_client")
+	})
+
+	t.Run("error_when_manager_returns_error", func(t *testing.T) {
+		mockClientMgr.On("Authenticate", mock.Anything, mock.Anything, EndpointToken).Return(nil, errors.New("unexpected")).Once()
+
+		err := f.authenticateClient(newReq())
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "unexpected")
+	})
+
+	t.Run("error_when_grant_type_is_unsupported", func(t *testing.T) {
+		mockClient := &sql.Client{
+			GrantTypes: []string{types.GrantTypeAuthorizationCode.String()},
+		}
+		mockClientMgr.On("Authenticate", mock.Anything, mock.Anything, EndpointToken).Return(mockClient, nil).Once()
+
+		err := f.authenticateClient(newReq())
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "unauthorized_client")
+	})
+}
+
+func TestFlow_validateRefreshToken(t *testing.T) {
+	newReq := func() *requests.TokenRequest {
+		return &requests.TokenRequest{
+			RefreshToken: "myrefresh",
+			Client:       &sql.Client{ClientID: "client123"},
+			Request:      httptest.NewRequest(http.MethodPost, "/", nil),
+		}
+	}
+
+	newToken := func() *sql.Token {
+		return &sql.Token{
+			RefreshToken:          "myrefresh",
+			ClientID:              "client123",
+			IssuedAt:              time.Now().UTC(),
+			RefreshTokenExpiresIn: time.Hour,
+			FamilyID:              "family123",
+		}
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockTokenMgr := refreshtoken.NewMockTokenManager(t)
+		f := New(NewConfig().SetTokenManager(mockTokenMgr))
+
+		token := newToken()
+		mockTokenMgr.On("QueryByRefreshToken", mock.Anything, "myrefresh").Return(token, nil).Once()
+
+		r := newReq()
+		err := f.validateRefreshToken(r)
+		assert.NoError(t, err)
+		assert.Equal(t, token, r.Token)
+	})
+
+	t.Run("success_without_expiry", func(t *testing.T) {
+		mockTokenMgr := refreshtoken.NewMockTokenManager(t)
+		f := New(NewConfig().SetTokenManager(mockTokenMgr))
+
+		token := newToken()
+		token.IssuedAt = time.Now().UTC().Add(-48 * time.Hour)
+		token.RefreshTokenExpiresIn = 0
+		mockTokenMgr.On("QueryByRefreshToken", mock.Anything, "myrefresh").Return(token, nil).Once()
+
+		err := f.validateRefreshToken(newReq())
+		assert.NoError(t, err)
+	})
+
+	t.Run("success_when_rotated_within_grace_period", func(t *testing.T) {
+		mockTokenMgr := refreshtoken.NewMockTokenManager(t)
+		f := New(NewConfig().SetTokenManager(mockTokenMgr).SetReuseGracePeriod(time.Minute))
+
+		token := newToken()
+		token.RotatedAt = time.Now().UTC().Add(-10 * time.Second)
+		mockTokenMgr.On("QueryByRefreshToken", mock.Anything, "myrefresh").Return(token, nil).Once()
+
+		err := f.validateRefreshToken(newReq())
+		assert.NoError(t, err)
+	})
+
+	t.Run("error_when_manager_returns_error", func(t *testing.T) {
+		mockTokenMgr := refreshtoken.NewMockTokenManager(t)
+		f := New(NewConfig().SetTokenManager(mockTokenMgr))
+
+		mockTokenMgr.On("QueryByRefreshToken", mock.Anything, "myrefresh").Return(nil, errors.New("db error")).Once()
+
+		err := f.validateRefreshToken(newReq())
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "db error")
+	})
+
+	t.Run("error_when_token_not_found", func(t *testing.T) {
+		mockTokenMgr := refreshtoken.NewMockTokenManager(t)
+		f := New(NewConfig().SetTokenManager(mockTokenMgr))
+
+		mockTokenMgr.On("QueryByRefreshToken", mock.Anything, "myrefresh").Return(nil, nil).Once()
+
+		err := f.validateRefreshToken(newReq())
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid_grant")
+	})
+
+	t.Run("error_when_client_mismatch", func(t *testing.T) {
+		mockTokenMgr := refreshtoken.NewMockTokenManager(t)
+		f := New(NewConfig().SetTokenManager(mockTokenMgr))
+
+		token := newToken()
+		token.ClientID = "other"
+		mockTokenMgr.On("QueryByRefreshToken", mock.Anything, "myrefresh").Return(token, nil).Once()
+
+		err := f.validateRefreshToken(newReq())
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid_grant")
+	})
+
+	t.Run("error_when_token_expired", func(t *testing.T) {
+		mockTokenMgr := refreshtoken.NewMockTokenManager(t)
+		f := New(NewConfig().SetTokenManager(mockTokenMgr))
+
+		token := newToken()
+		token.IssuedAt = time.Now().UTC().Add(-2 * time.Hour)
+		mockTokenMgr.On("QueryByRefreshToken", mock.Anything, "myrefresh").Return(token, nil).Once()
+
+		err := f.validateRefreshToken(newReq())
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid_grant")
+	})
+
+	t.Run("error_and_revokes_family_when_rotated_token_replayed", func(t *testing.T) {
+		mockTokenMgr := refreshtoken.NewMockTokenManager(t)
+		f := New(NewConfig().SetTokenManager(mockTokenMgr))
+
+		token := newToken()
+		token.RotatedAt = time.Now().UTC().Add(-time.Minute)
+		mockTokenMgr.On("QueryByRefreshToken", mock.Anything, "myrefresh").Return(token, nil).Once()
+		mockTokenMgr.On("RevokeTokenFamily", mock.Anything, "family123").Return(nil).Once()
+
+		r := newReq()
+		err := f.validateRefreshToken(r)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid_grant")
+		assert.Nil(t, r.Token)
+	})
+
+	t.Run("error_when_revoke_family_fails", func(t *testing.T) {
+		mockTokenMgr := refreshtoken.NewMockTokenManager(t)
+		f := New(NewConfig().SetTokenManager(mockTokenMgr))
+
+		token := newToken()
+		token.RotatedAt = time.Now().UTC().Add(-time.Minute)
+		mockTokenMgr.On("QueryByRefreshToken", mock.Anything, "myrefresh").Return(token, nil).Once()
+		mockTokenMgr.On("RevokeTokenFamily", mock.Anything, "family123").Return(errors.New("db error")).Once()
+
+		err := f.validateRefreshToken(newReq())
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "db error")
+	})
+}
+
+func TestFlow_validateScope(t *testing.T) {
+	f := New(NewConfig())
+	token := &sql.Token{Scopes: []string{"read", "write"}}
+
+	t.Run("success_when_scope_omitted", func(t *testing.T) {
+		r := &requests.TokenRequest{Token: token}
+		err := f.validateScope(r)
+		assert.NoError(t, err)
+		assert.Equal(t, token.GetScopes(), r.Scopes)
+	})
+
+	t.Run("success_when_scope_narrowed", func(t *testing.T) {
+		r := &requests.TokenRequest{Token: token, Scopes: types.NewScopes([]string{"read"})}
+		err := f.validateScope(r)
+		assert.NoError(t, err)
+		assert.Equal(t, types.NewScopes([]string{"read"}), r.Scopes)
+	})
+
+	t.Run("error_when_scope_exceeds_original", func(t *testing.T) {
+		r := &requests.TokenRequest{Token: token, Scopes: types.NewScopes([]string{"read", "admin"})}
+		err := f.validateScope(r)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid_scope")
+	})
+}
+
+func TestFlow_queryUserByToken(t *testing.T) {
+	mockUser := &sql.User{}
+	mockUserMgr := refreshtoken.NewMockUserManager(t)
+	f := New(NewConfig().SetUserManager(mockUserMgr))
+
+	newReq := func(userID string) *requests.TokenRequest {
+		return &requests.TokenRequest{
+			Token:   &sql.Token{UserID: userID},
+			Request: httptest.NewRequest(http.MethodPost, "/", nil),
+		}
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockUserMgr.On("QueryUserByToken", mock.Anything, mock.Anything, mock.Anything).Return(mockUser, nil).Once()
+
+		r := newReq("user123")
+		err := f.queryUserByToken(r)
+		assert.NoError(t, err)
+		assert.Equal(t, mockUser, r.User)
+	})
+
+	t.Run("error_when_token_has_no_user", func(t *testing.T) {
+		err := f.queryUserByToken(newReq(""))
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid_grant")
+	})
+
+	t.Run("error_when_user_not_found", func(t *testing.T) {
+		mockUserMgr.On("QueryUserByToken", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil).Once()
+
+		err := f.queryUserByToken(newReq("user123"))
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid_grant")
+	})
+
+	t.Run("error_when_manager_returns_error", func(t *testing.T) {
+		mockUserMgr.On("QueryUserByToken", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("db error")).Once()
+
+		err := f.queryUserByToken(newReq("user123"))
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "db error")
+	})
+}
+
+func TestFlow_ValidateTokenRequest(t *testing.T) {
+	mockClientMgr := refreshtoken.NewMockClientManager(t)
+	mockUserMgr := refreshtoken.NewMockUserManager(t)
+	mockTokenMgr := refreshtoken.NewMockTokenManager(t)
+	mockValidator := refreshtoken.NewMockTokenRequestValidator(t)
+	f := New(NewConfig().
+		SetClientManager(mockClientMgr).
+		SetUserManager(mockUserMgr).
+		SetTokenManager(mockTokenMgr).
+		RegisterExtension(mockValidator))
+
+	mockClient := &sql.Client{
+		ClientID:   "client123",
+		GrantTypes: []string{types.GrantTypeRefreshToken.String()},
+	}
+
+	newReq := func() *requests.TokenRequest {
+		return &requests.TokenRequest{
+			GrantType:    types.GrantTypeRefreshToken,
+			RefreshToken: "myrefresh",
+			Request:      httptest.NewRequest(http.MethodPost, "/oauth/token", nil),
+		}
+	}
+
+	newToken := func() *sql.Token {
+		return &sql.Token{
+			RefreshToken:          "myrefresh",
+			ClientID:              "client123",
+			UserID:                "user123",
+			Scopes:                []string{"read"},
+			IssuedAt:              time.Now().UTC(),
+			RefreshTokenExpiresIn: time.Hour,
+		}
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockClientMgr.On("Authenticate", mock.Anything, mock.Anything, EndpointToken).Return(mockClient, nil).Once()
+		mockTokenMgr.On("QueryByRefreshToken", mock.Anything, "myrefresh").Return(newToken(), nil).Once()
+		mockUserMgr.On("QueryUserByToken", mock.Anything, mock.Anything, mock.Anything).Return(&sql.User{}, nil).Once()
+		mockValidator.On("ValidateTokenRequest", mock.Anything).Return(nil).Once()
+
+		r := newReq()
+		err := f.ValidateTokenRequest(r)
+		assert.NoError(t, err)
+		assert.Equal(t, types.NewScopes([]string{"read"}), r.Scopes)
+	})
+
+	t.Run("error_when_validator_fails", func(t *testing.T) {
+		mockClientMgr.On("Authenticate", mock.Anything, mock.Anything, EndpointToken).Return(mockClient, nil).Once()
+		mockTokenMgr.On("QueryByRefreshToken", mock.Anything, "myrefresh").Return(newToken(), nil).Once()
+		mockUserMgr.On("QueryUserByToken", mock.Anything, mock.Anything, mock.Anything).Return(&sql.User{}, nil).Once()
+		mockValidator.On("ValidateTokenRequest", mock.Anything).Return(errors.New("validator error")).Once()
+
+		err := f.ValidateTokenRequest(newReq())
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "validator error")
+	})
+
+	t.Run("error_when_params_invalid", func(t *testing.T) {
+		r := newReq()
+		r.RefreshToken = ""
+		err := f.ValidateTokenRequest(r)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid_request")
+	})
+}
+
+func TestFlow_TokenResponse(t *testing.T) {
+	newReq := func(old *sql.Token) *requests.TokenRequest {
+		return &requests.TokenRequest{
+			GrantType: types.GrantTypeRefreshToken,
+			Client:    &sql.Client{},
+			Token:     old,
+			Request:   httptest.NewRequest(http.MethodPost, "/oauth/token", nil),
+		}
+	}
+
+	t.Run("success_rotates_and_starts_family", func(t *testing.T) {
+		mockTokenMgr := refreshtoken.NewMockTokenManager(t)
+		f := New(NewConfig().SetTokenManager(mockTokenMgr))
+
+		old := &sql.Token{}
+		newTok := &sql.Token{}
+		mockTokenMgr.On("New").Return(newTok).Once()
+		mockTokenMgr.On("Generate", newTok, mock.Anything, true).Return(nil).Once()
+		mockTokenMgr.On("Save", mock.Anything, newTok).Return(nil).Once()
+		mockTokenMgr.On("Update", mock.Anything, old).Return(nil).Once()
+
+		err := f.TokenResponse(newReq(old), httptest.NewRecorder())
+		assert.NoError(t, err)
+		assert.NotEmpty(t, newTok.FamilyID)
+		assert.Equal(t, newTok.FamilyID, old.FamilyID)
+		assert.False(t, old.RotatedAt.IsZero())
+		assert.True(t, newTok.RotatedAt.IsZero())
+	})
+
+	t.Run("success_keeps_family_and_first_rotation_time", func(t *testing.T) {
+		mockTokenMgr := refreshtoken.NewMockTokenManager(t)
+		f := New(NewConfig().SetTokenManager(mockTokenMgr))
+
+		rotatedAt := time.Now().UTC().Add(-5 * time.Second).Round(time.Second)
+		old := &sql.Token{FamilyID: "family123", RotatedAt: rotatedAt}
+		newTok := &sql.Token{}
+		mockTokenMgr.On("New").Return(newTok).Once()
+		mockTokenMgr.On("Generate", newTok, mock.Anything, true).Return(nil).Once()
+		mockTokenMgr.On("Save", mock.Anything, newTok).Return(nil).Once()
+		mockTokenMgr.On("Update", mock.Anything, old).Return(nil).Once()
+
+		err := f.TokenResponse(newReq(old), httptest.NewRecorder())
+		assert.NoError(t, err)
+		assert.Equal(t, "family123", newTok.FamilyID)
+		assert.Equal(t, rotatedAt, old.RotatedAt)
+	})
+
+	t.Run("concurrent_refreshes_share_family_revoked_on_reuse", func(t *testing.T) {
+		mockTokenMgr := refreshtoken.NewMockTokenManager(t)
+		f := New(NewConfig().SetTokenManager(mockTokenMgr).SetReuseGracePeriod(10 * time.Second))
+
+		// Each request loads its own copy of the presented token.
+		olds := []*sql.Token{{RefreshToken: "myrefresh"}, {RefreshToken: "myrefresh"}}
+		newToks := []*sql.Token{{}, {}}
+		for i := range olds {
+			mockTokenMgr.On("New").Return(newToks[i]).Once()
+			mockTokenMgr.On("Generate", newToks[i], mock.Anything, true).Return(nil).Once()
+			mockTokenMgr.On("Save", mock.Anything, newToks[i]).Return(nil).Once()
+			mockTokenMgr.On("Update", mock.Anything, olds[i]).Return(nil).Once()
+		}
+
+		var wg sync.WaitGroup
+		for _, old := range olds {
+			wg.Add(1)
+			go func(old *sql.Token) {
+				defer wg.Done()
+				assert.NoError(t, f.TokenResponse(newReq(old), httptest.NewRecorder()))
+			}(old)
+		}
+		wg.Wait()
+
+		family := olds[0].FamilyID
+		assert.NotEmpty(t, family)
+		assert.Equal(t, family, olds[1].FamilyID)
+		assert.Equal(t, family, newToks[0].FamilyID)
+		assert.Equal(t, family, newToks[1].FamilyID)
+
+		reused := olds[1]
+		reused.ClientID = "client123"
+		reused.RotatedAt = time.Now().UTC().Add(-time.Minute)
+		mockTokenMgr.On("QueryByRefreshToken", mock.Anything, "myrefresh").Return(reused, nil).Once()
+		mockTokenMgr.On("RevokeTokenFamily", mock.Anything, family).Return(nil).Once()
+
+		r := &requests.TokenRequest{
+			GrantType:    types.GrantTypeRefreshToken,
+			RefreshToken: "myrefresh",
+			Client:       &sql.Client{ClientID: "client123"},
+			Request:      httptest.NewRequest(http.MethodPost, "/oauth/token", nil),
+		}
+		err := f.validateRefreshToken(r)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid_grant")
+	})
+
+	t.Run("success_without_rotation", func(t *testing.T) {
+		mockTokenMgr := refreshtoken.NewMockTokenManager(t)
+		f := New(NewConfig().SetTokenManager(mockTokenMgr).SetRotateRefreshToken(false))
+
+		old := &sql.Token{}
+		newTok := &sql.Token{}
+		mockTokenMgr.On("New").Return(newTok).Once()
+		mockTokenMgr.On("Generate", newTok, mock.Anything, false).Return(nil).Once()
+		mockTokenMgr.On("Save", mock.Anything, newTok).Return(nil).Once()
+		mockTokenMgr.On("Update", mock.Anything, old).Return(nil).Once()
+
+		err := f.TokenResponse(newReq(old), httptest.NewRecorder())
+		assert.NoError(t, err)
+		assert.True(t, old.RotatedAt.IsZero())
+	})
+
+	t.Run("error_when_gen_token_fails", func(t *testing.T) {
+		mockTokenMgr := refreshtoken.NewMockTokenManager(t)
+		f := New(NewConfig().SetTokenManager(mockTokenMgr))
+
+		mockTokenMgr.On("New").Return(nil).Once()
+
+		err := f.TokenResponse(newReq(&sql.Token{}), httptest.NewRecorder())
+		assert.ErrorIs(t, err, ErrNilToken)
+	})
+
+	t.Run("error_when_save_fails", func(t *testing.T) {
+		mockTokenMgr := refreshtoken.NewMockTokenManager(t)
+		f := New(NewConfig().SetTokenManager(mockTokenMgr))
+
+		mockTokenMgr.On("New").Return(&sql.Token{}).Once()
+		mockTokenMgr.On("Generate", mock.Anything, mock.Anything, true).Return(nil).Once()
+		mockTokenMgr.On("Save", mock.Anything, mock.Anything).Return(errors.New("db error")).Once()
+
+		err := f.TokenResponse(newReq(&sql.Token{}), httptest.NewRecorder())
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "db error")
+	})
+
+	t.Run("error_when_update_fails", func(t *testing.T) {
+		mockTokenMgr := refreshtoken.NewMockTokenManager(t)
+		f := New(NewConfig().SetTokenManager(mockTokenMgr))
+
+		mockTokenMgr.On("New").Return(&sql.Token{}).Once()
+		mockTokenMgr.On("Generate", mock.Anything, mock.Anything, true).Return(nil).Once()
+		mockTokenMgr.On("Save", mock.Anything, mock.Anything).Return(nil).Once()
+		mockTokenMgr.On("Update", mock.Anything, mock.Anything).Return(errors.New("update error")).Once()
+
+		err := f.TokenResponse(newReq(&sql.Token{}), httptest.NewRecorder())
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "update error")
+	})
+}
+
+func TestFlow_TokenResponse_WithProcessor(t *testing.T) {
+	mockTokenMgr := refreshtoken.NewMockTokenManager(t)
+	mockProcessor := refreshtoken.NewMockTokenProcessor(t)
+	f := New(NewConfig().SetTokenManager(mockTokenMgr).RegisterExtension(mockProcessor))
+
+	newReq := func() *requests.TokenRequest {
+		return &requests.TokenRequest{
+			Client:  &sql.Client{},
+			Token:   &sql.Token{},
+			Request: httptest.NewRequest(http.MethodPost, "/oauth/token", nil),
+		}
+	}
+
+	t.Run("success_processor_called", func(t *testing.T) {
+		mockTokenMgr.On("New").Return(&sql.Token{}).Once()
+		mockTokenMgr.On("Generate", mock.Anything, mock.Anything, true).Return(nil).Once()
+		mockProcessor.On("ProcessToken", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+		mockTokenMgr.On("Save", mock.Anything, mock.Anything).Return(nil).Once()
+		mockTokenMgr.On("Update", mock.Anything, mock.Anything).Return(nil).Once()
+
+		err := f.TokenResponse(newReq(), httptest.NewRecorder())
+		assert.NoError(t, err)
+	})
+
+	t.Run("error_when_processor_fails", func(t *testing.T) {
+		mockTokenMgr.On("New").Return(&sql.Token{}).Once()
+		mockTokenMgr.On("Generate", mock.Anything, mock.Anything, true).Return(nil).Once()
+		mockProcessor.On("ProcessToken", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("processor error")).Once()
+
+		err := f.TokenResponse(newReq(), httptest.NewRecorder())
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "processor error")
+	})
+}
+
+func TestFlow_ProvideMetadata(t *testing.T) {
+	provider := refreshtoken.NewMockMetadataProvider(t)
+	provider.On("ProvideMetadata", mock.Anything).Once()
+
+	f := New(NewConfig().RegisterExtension(provider))
+	md := types.Metadata{}
+	f.ProvideMetadata(md)
+
+	assert.Equal(t, types.Metadata{
+		types.MetadataGrantTypesSupported:               []string{"refresh_token"},
+		types.MetadataTokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "none"},
+	}, md)
+}
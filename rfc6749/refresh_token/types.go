@@ -0,0 +1,74 @@
+package refreshtoken
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/tniah/authlib/models"
+	"github.com/tniah/authlib/requests"
+	"github.com/tniah/authlib/types"
+)
+
+// ClientManager authenticates the client application at the token endpoint.
+// Typically backed by clientauth.Manager from rfc6749/client_authentication.
+type ClientManager interface {
+	// Authenticate validates the client credentials carried in the request using
+	// one of the permitted authMethods. Returns the authenticated client or an error.
+	Authenticate(r *http.Request, supportedMethods map[types.ClientAuthMethod]bool, endpoint string) (models.Client, error)
+}
+
+// UserManager resolves the resource owner linked to a refresh token.
+type UserManager interface {
+	// QueryUserByToken returns the user the refresh token was issued for.
+	// Return (nil, nil) when the user no longer exists or has been disabled;
+	// the flow treats this as an invalid_grant error.
+	QueryUserByToken(ctx context.Context, token models.Token, r *requests.TokenRequest) (models.User, error)
+}
+
+// TokenManager looks up, generates, and persists tokens for the refresh token grant.
+type TokenManager interface {
+	// New allocates a blank Token ready to be populated by Generate.
+	New() models.Token
+
+	// QueryByRefreshToken retrieves the token record carrying refreshToken.
+	// Return (nil, nil) when the refresh token does not exist or has been revoked.
+	// Rotated refresh tokens must still be returned so that replay can be detected.
+	QueryByRefreshToken(ctx context.Context, refreshToken string) (models.Token, error)
+
+	// Generate populates token with a value, expiry, scopes, and client/user
+	// binding. includeRefreshToken is true when refresh token rotation is enabled.
+	Generate(token models.Token, r *requests.TokenRequest, includeRefreshToken bool) error
+
+	// Save persists the newly issued token to the backing store.
+	Save(ctx context.Context, token models.Token) error
+
+	// Update persists changes to an existing token record. Called on the
+	// refreshed token to store its family ID and rotation time.
+	Update(ctx context.Context, token models.Token) error
+
+	// RevokeTokenFamily revokes every access and refresh token sharing
+	// familyID. Called when a rotated refresh token is replayed outside the
+	// grace period, which indicates the token may have been stolen.
+	RevokeTokenFamily(ctx context.Context, familyID string) error
+}
+
+// TokenRequestValidator is an extension hook called during ValidateTokenRequest,
+// after the built-in checks pass.
+type TokenRequestValidator interface {
+	ValidateTokenRequest(r *requests.TokenRequest) error
+}
+
+// TokenProcessor is an extension hook called after the token is generated and
+// before the response is written. Use it to add extra fields to the token
+// response (e.g. attaching a custom claim).
+type TokenProcessor interface {
+	ProcessToken(r *requests.TokenRequest, token models.Token, data map[string]interface{}) error
+}
+
+// MetadataProvider is an extension hook that describes the capabilities of an
+// extension in the authorization server metadata (RFC 8414 §2), such as the
+// DPoP signing algorithms. The flow forwards its own ProvideMetadata call to
+// every registered provider.
+type MetadataProvider interface {
+	ProvideMetadata(md types.Metadata)
+}
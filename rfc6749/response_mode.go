@@ -0,0 +1,48 @@
+package rfc6749
+
+import (
+	"fmt"
+
+	autherrors "github.com/tniah/authlib/errors"
+	"github.com/tniah/authlib/models"
+	"github.com/tniah/authlib/requests"
+)
+
+// ValidateResponseMode checks the response_mode of an authorization request
+// (OAuth 2.0 Multiple Response Type Encoding Practices §2.1, OAuth 2.0 Form
+// Post Response Mode). An omitted response_mode is always accepted.
+//
+// handled reports whether a registered ResponseModeHandler writes responses
+// for this mode; JWT-secured modes are only accepted when it does. Tokens must
+// not be returned in the query string, so response_mode=query is rejected for
+// response types that issue tokens from the authorization endpoint. Clients
+// implementing models.ResponseModeClient must be permitted to use the mode.
+//
+// Errors are not redirected, since they cannot be encoded the way the client
+// asked for.
+func ValidateResponseMode(r *requests.AuthorizationRequest, handled bool) error {
+	mode := r.ResponseMode
+	if mode.IsEmpty() {
+		return nil
+	}
+
+	if !handled && (mode.IsJWT() || !mode.IsValid()) {
+		return autherrors.InvalidRequestError().
+			WithDescription(fmt.Sprintf("unsupported \"response_mode\" \"%s\"", mode)).
+			WithState(r.State)
+	}
+
+	if mode.IsQuery() && r.ResponseType.DefaultResponseMode().IsFragment() {
+		return autherrors.InvalidRequestError().
+			WithDescription(fmt.Sprintf("\"response_mode\" \"%s\" is not allowed for \"response_type\" \"%s\"", mode, r.ResponseType)).
+			WithState(r.State)
+	}
+
+	if c, ok := r.Client.(models.ResponseModeClient); ok && !c.CheckResponseMode(mode) {
+		return autherrors.InvalidRequestError().
+			WithDescription(fmt.Sprintf("\"response_mode\" \"%s\" is not allowed for client", mode)).
+			WithState(r.State)
+	}
+
+	return nil
+}
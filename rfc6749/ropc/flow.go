@@ -44,6 +44,14 @@ func (f *Flow) CheckGrantType(gt types.GrantType) bool {
 	return gt.IsROPC()
 }
 
+// ProvideMetadata adds the password grant and the client authentication
+// methods accepted at the token endpoint to the authorization server metadata
+// (RFC 8414 §2).
+func (f *Flow) ProvideMetadata(md types.Metadata) {
+	md.Add(types.MetadataGrantTypesSupported, types.GrantTypeROPC.String())
+	md.AddClientAuthMethods(types.MetadataTokenEndpointAuthMethodsSupported, f.supportedClientAuthMethods)
+}
+
 // ValidateTokenRequest validates the /token request: HTTP method, grant_type,
 // username, password, client authentication, scope, and any registered
 // TokenRequestValidator extensions.
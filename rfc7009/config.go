@@ -0,0 +1,107 @@
+package rfc7009
+
+import (
+	"errors"
+
+	"github.com/tniah/authlib/types"
+	"github.com/tniah/authlib/utils"
+)
+
+// EndpointNameTokenRevocation is the default endpoint name used to register
+// the revocation handler with the server.
+const EndpointNameTokenRevocation = "revocation"
+
+var (
+	ErrEmptyEndpointName      = errors.New("endpoint name is empty")
+	ErrNilClientManager       = errors.New("client manager is nil")
+	ErrNilTokenManager        = errors.New("token manager is nil")
+	ErrEmptyClientAuthMethods = errors.New("supported client auth methods are empty")
+	ErrEmptyTokenTypes        = errors.New("supported token types are empty")
+)
+
+// Config holds all settings for TokenRevocationFlow. Use NewConfig to obtain
+// a value with secure defaults, then chain Set* calls to configure managers.
+type Config struct {
+	endpointName               string
+	clientManager              ClientManager
+	tokenManager               TokenManager
+	supportedClientAuthMethods map[types.ClientAuthMethod]bool
+	supportedTokenTypes        map[types.TokenTypeHint]bool
+}
+
+// NewConfig returns a Config with EndpointNameTokenRevocation as the endpoint
+// name, client_secret_basic as the default client authentication method, and
+// revocation of both access and refresh tokens enabled.
+func NewConfig() *Config {
+	return &Config{
+		supportedClientAuthMethods: map[types.ClientAuthMethod]bool{
+			types.ClientBasicAuthentication: true,
+		},
+		supportedTokenTypes: map[types.TokenTypeHint]bool{
+			types.TokenTypeHintAccessToken:  true,
+			types.TokenTypeHintRefreshToken: true,
+		},
+		endpointName: EndpointNameTokenRevocation,
+	}
+}
+
+// SetEndpointName overrides the endpoint name used by CheckEndpoint. Defaults
+// to EndpointNameTokenRevocation ("revocation").
+func (cfg *Config) SetEndpointName(name string) *Config {
+	cfg.endpointName = name
+	return cfg
+}
+
+// SetClientManager registers the ClientManager used to authenticate the caller.
+func (cfg *Config) SetClientManager(mgr ClientManager) *Config {
+	cfg.clientManager = mgr
+	return cfg
+}
+
+// SetTokenManager registers the TokenManager used to look up and invalidate
+// tokens.
+func (cfg *Config) SetTokenManager(mgr TokenManager) *Config {
+	cfg.tokenManager = mgr
+	return cfg
+}
+
+// SetSupportedClientAuthMethods overrides the set of client authentication
+// methods accepted at the revocation endpoint.
+func (cfg *Config) SetSupportedClientAuthMethods(methods map[types.ClientAuthMethod]bool) *Config {
+	cfg.supportedClientAuthMethods = methods
+	return cfg
+}
+
+// SetSupportedTokenTypes overrides the token types the server is able to
+// revoke. Revoking any other type is answered with unsupported_token_type
+// (RFC 7009 §2.2.1).
+func (cfg *Config) SetSupportedTokenTypes(tokenTypes map[types.TokenTypeHint]bool) *Config {
+	cfg.supportedTokenTypes = tokenTypes
+	return cfg
+}
+
+// ValidateConfig returns an error if any required configuration is missing.
+// Call this via MustTokenRevocationFlow rather than directly.
+func (cfg *Config) ValidateConfig() error {
+	if cfg.endpointName == "" {
+		return ErrEmptyEndpointName
+	}
+
+	if utils.IsNil(cfg.clientManager) {
+		return ErrNilClientManager
+	}
+
+	if utils.IsNil(cfg.tokenManager) {
+		return ErrNilTokenManager
+	}
+
+	if len(cfg.supportedClientAuthMethods) == 0 {
+		return ErrEmptyClientAuthMethods
+	}
+
+	if len(cfg.supportedTokenTypes) == 0 {
+		return ErrEmptyTokenTypes
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package rfc7009
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	mock "github.com/tniah/authlib/mocks/rfc7009"
+	"github.com/tniah/authlib/types"
+)
+
+func TestConfig(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		cfg := NewConfig()
+		assert.Equal(t, EndpointNameTokenRevocation, cfg.endpointName)
+		assert.Equal(t, map[types.TokenTypeHint]bool{
+			types.TokenTypeHintAccessToken:  true,
+			types.TokenTypeHintRefreshToken: true,
+		}, cfg.supportedTokenTypes)
+
+		expected := Config{
+			endpointName:  "test-endpoint-name",
+			clientManager: mock.NewMockClientManager(t),
+			tokenManager:  mock.NewMockTokenManager(t),
+			supportedClientAuthMethods: map[types.ClientAuthMethod]bool{
+				types.ClientBasicAuthentication: true,
+				types.ClientPostAuthentication:  true,
+			},
+			supportedTokenTypes: map[types.TokenTypeHint]bool{
+				types.TokenTypeHintRefreshToken: true,
+			},
+		}
+
+		cfg.SetEndpointName(expected.endpointName)
+		cfg.SetClientManager(expected.clientManager)
+		cfg.SetTokenManager(expected.tokenManager)
+		cfg.SetSupportedClientAuthMethods(expected.supportedClientAuthMethods)
+		cfg.SetSupportedTokenTypes(expected.supportedTokenTypes)
+
+		assert.Equal(t, expected, *cfg)
+		assert.NoError(t, cfg.ValidateConfig())
+	})
+
+	t.Run("error", func(t *testing.T) {
+		cfg := NewConfig()
+		cfg.SetEndpointName("")
+		err := cfg.ValidateConfig()
+		assert.ErrorIs(t, err, ErrEmptyEndpointName)
+
+		cfg.SetEndpointName("test")
+		cfg.SetClientManager(nil)
+		err = cfg.ValidateConfig()
+		assert.ErrorIs(t, err, ErrNilClientManager)
+
+		cfg.SetClientManager(mock.NewMockClientManager(t))
+		cfg.SetTokenManager(nil)
+		err = cfg.ValidateConfig()
+		assert.ErrorIs(t, err, ErrNilTokenManager)
+
+		cfg.SetTokenManager(mock.NewMockTokenManager(t))
+		cfg.SetSupportedClientAuthMethods(map[types.ClientAuthMethod]bool{})
+		err = cfg.ValidateConfig()
+		assert.ErrorIs(t, err, ErrEmptyClientAuthMethods)
+
+		cfg.SetSupportedClientAuthMethods(map[types.ClientAuthMethod]bool{types.ClientBasicAuthentication: true})
+		cfg.SetSupportedTokenTypes(nil)
+		err = cfg.ValidateConfig()
+		assert.ErrorIs(t, err, ErrEmptyTokenTypes)
+	})
+}
@@ -0,0 +1,163 @@
+package rfc7009
+
+import (
+	"fmt"
+	"net/http"
+
+	autherrors "github.com/tniah/authlib/errors"
+	"github.com/tniah/authlib/types"
+	"github.com/tniah/authlib/utils"
+)
+
+// TokenRevocationFlow implements RFC 7009 token revocation. It is registered
+// as an endpoint on the server via Server.RegisterEndpoint and dispatched by
+// Server.EndpointResponse when the endpoint name matches.
+type TokenRevocationFlow struct {
+	*Config
+}
+
+// NewTokenRevocationFlow creates a TokenRevocationFlow from cfg without
+// validating it. Prefer MustTokenRevocationFlow for production use.
+func NewTokenRevocationFlow(cfg *Config) *TokenRevocationFlow {
+	return &TokenRevocationFlow{cfg}
+}
+
+// MustTokenRevocationFlow creates a TokenRevocationFlow after validating cfg.
+// Returns an error if any required configuration is missing.
+func MustTokenRevocationFlow(cfg *Config) (*TokenRevocationFlow, error) {
+	if err := cfg.ValidateConfig(); err != nil {
+		return nil, err
+	}
+
+	return NewTokenRevocationFlow(cfg), nil
+}
+
+// CheckEndpoint reports whether name matches the configured endpoint name.
+// The server calls this to route requests to the correct registered endpoint.
+func (f *TokenRevocationFlow) CheckEndpoint(name string) bool {
+	if f.endpointName == "" {
+		return false
+	}
+
+	return name == f.endpointName
+}
+
+// ProvideMetadata adds the client authentication methods accepted at the
+// revocation endpoint to the authorization server metadata (RFC 8414 §2).
+func (f *TokenRevocationFlow) ProvideMetadata(md types.Metadata) {
+	md.AddClientAuthMethods(types.MetadataRevocationEndpointAuthMethodsSupported, f.supportedClientAuthMethods)
+}
+
+// EndpointResponse handles a revocation request. It authenticates the caller,
+// looks up the token, verifies it was issued to the caller, revokes it, and
+// answers 200 OK (RFC 7009 §2.2). An unknown token is also answered with 200.
+func (f *TokenRevocationFlow) EndpointResponse(r *http.Request, rw http.ResponseWriter) error {
+	client, err := f.clientManager.Authenticate(r, f.supportedClientAuthMethods, f.endpointName)
+	if err != nil {
+		return autherrors.ToAuthLibError(err)
+	}
+
+	if utils.IsNil(client) {
+		return autherrors.InvalidClientError()
+	}
+
+	req := NewRequestFromHTTP(r)
+	req.Client = client
+
+	if err = f.authenticateToken(req); err != nil {
+		return err
+	}
+
+	if err = f.revokeToken(req); err != nil {
+		return err
+	}
+
+	return utils.JSONResponse(rw, map[string]interface{}{}, http.StatusOK)
+}
+
+// authenticateToken validates request parameters, looks up the token, and
+// verifies that it was issued to the calling client (RFC 7009 §2.1).
+func (f *TokenRevocationFlow) authenticateToken(r *Request) error {
+	if err := f.checkParams(r); err != nil {
+		return err
+	}
+
+	token, err := f.tokenManager.QueryByToken(r.Request.Context(), r.Token, r.TokenTypeHint)
+	if err != nil {
+		return err
+	}
+
+	if utils.IsNil(token) {
+		return nil
+	}
+
+	if token.GetClientID() != r.Client.GetClientID() {
+		return autherrors.UnauthorizedClientError().WithDescription("token was not issued to this client")
+	}
+
+	r.Tok = token
+	return nil
+}
+
+// checkParams validates HTTP method, content type, token presence, and the
+// token_type_hint per RFC 7009 §2.1. Unknown hint values are ignored; a known
+// hint naming a type the server cannot revoke is rejected with
+// unsupported_token_type (RFC 7009 §2.2.1).
+func (f *TokenRevocationFlow) checkParams(r *Request) error {
+	if err := r.ValidateHTTPMethod(); err != nil {
+		return err
+	}
+
+	if err := r.ValidateContentType(); err != nil {
+		return err
+	}
+
+	if err := r.ValidateToken(); err != nil {
+		return err
+	}
+
+	if r.TokenTypeHint.IsValid() && !f.supportedTokenTypes[r.TokenTypeHint] {
+		return autherrors.UnsupportedTokenType().WithDescription(fmt.Sprintf("revocation of \"%s\" is not supported", r.TokenTypeHint))
+	}
+
+	return nil
+}
+
+// revokeToken invalidates r.Tok. Revoking a refresh token also revokes every
+// access token issued from the same grant (RFC 7009 §2.1). Does nothing when
+// the token was not found.
+func (f *TokenRevocationFlow) revokeToken(r *Request) error {
+	if utils.IsNil(r.Tok) {
+		return nil
+	}
+
+	tokenType := f.tokenType(r)
+	if !f.supportedTokenTypes[tokenType] {
+		return autherrors.UnsupportedTokenType().WithDescription(fmt.Sprintf("revocation of \"%s\" is not supported", tokenType))
+	}
+
+	ctx := r.Request.Context()
+	if tokenType.IsAccessToken() {
+		return f.tokenManager.RevokeAccessToken(ctx, r.Tok)
+	}
+
+	if err := f.tokenManager.RevokeRefreshToken(ctx, r.Tok); err != nil {
+		return err
+	}
+
+	if familyID := r.Tok.GetFamilyID(); familyID != "" {
+		return f.tokenManager.RevokeTokenFamily(ctx, familyID)
+	}
+
+	return nil
+}
+
+// tokenType reports whether the submitted value is the refresh token or the
+// access token of r.Tok. The hint is not trusted; the stored values decide.
+func (f *TokenRevocationFlow) tokenType(r *Request) types.TokenTypeHint {
+	if refreshToken := r.Tok.GetRefreshToken(); refreshToken != "" && refreshToken == r.Token {
+		return types.TokenTypeHintRefreshToken
+	}
+
+	return types.TokenTypeHintAccessToken
+}
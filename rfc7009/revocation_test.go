@@ -0,0 +1,256 @@
+package rfc7009
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	autherrors "github.com/tniah/authlib/errors"
+	"github.com/tniah/authlib/integrations/sql"
+	"github.com/tniah/authlib/mocks/rfc7009"
+	"github.com/tniah/authlib/types"
+)
+
+func newFormRequest(body string) *http.Request {
+	hr := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	hr.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return hr
+}
+
+func TestTokenRevocationFlow_EndpointResponse(t *testing.T) {
+	mockClient := &sql.Client{
+		ClientID: uuid.NewString(),
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockToken := &sql.Token{
+			AccessToken: "my-token",
+			ClientID:    mockClient.ClientID,
+		}
+
+		mockTokenMgr := rfc7009.NewMockTokenManager(t)
+		mockTokenMgr.On("QueryByToken", mock.Anything, "my-token", types.TokenTypeHintAccessToken).Return(mockToken, nil).Once()
+		mockTokenMgr.On("RevokeAccessToken", mock.Anything, mockToken).Return(nil).Once()
+
+		mockClientMgr := rfc7009.NewMockClientManager(t)
+		mockClientMgr.On("Authenticate", mock.AnythingOfType("*http.Request"), mock.AnythingOfType("map[types.ClientAuthMethod]bool"), EndpointNameTokenRevocation).Return(mockClient, nil).Once()
+
+		h := NewTokenRevocationFlow(NewConfig().SetClientManager(mockClientMgr).SetTokenManager(mockTokenMgr))
+		rw := httptest.NewRecorder()
+		err := h.EndpointResponse(newFormRequest("token=my-token&token_type_hint=access_token"), rw)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rw.Code)
+	})
+
+	t.Run("success_when_token_not_found", func(t *testing.T) {
+		mockTokenMgr := rfc7009.NewMockTokenManager(t)
+		mockTokenMgr.On("QueryByToken", mock.Anything, "my-token", mock.AnythingOfType("types.TokenTypeHint")).Return(nil, nil).Once()
+
+		mockClientMgr := rfc7009.NewMockClientManager(t)
+		mockClientMgr.On("Authenticate", mock.Anything, mock.Anything, mock.AnythingOfType("string")).Return(mockClient, nil).Once()
+
+		h := NewTokenRevocationFlow(NewConfig().SetClientManager(mockClientMgr).SetTokenManager(mockTokenMgr))
+		rw := httptest.NewRecorder()
+		err := h.EndpointResponse(newFormRequest("token=my-token"), rw)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rw.Code)
+	})
+
+	t.Run("error_when_client_authentication_fails", func(t *testing.T) {
+		mockClientMgr := rfc7009.NewMockClientManager(t)
+		mockClientMgr.On("Authenticate", mock.Anything, mock.Anything, mock.AnythingOfType("string")).Return(nil, nil).Once()
+
+		h := NewTokenRevocationFlow(NewConfig().SetClientManager(mockClientMgr))
+		err := h.EndpointResponse(newFormRequest("token=my-token"), httptest.NewRecorder())
+		authErr := autherrors.ToAuthLibError(err)
+		assert.Equal(t, autherrors.ErrInvalidClient, authErr.Code)
+	})
+}
+
+func TestTokenRevocationFlow_CheckEndpoint(t *testing.T) {
+	h := NewTokenRevocationFlow(NewConfig())
+	cases := []struct {
+		name     string
+		expected bool
+	}{
+		{
+			"introspection",
+			false,
+		},
+		{
+			EndpointNameTokenRevocation,
+			true,
+		},
+	}
+	for i, test := range cases {
+		ret := h.CheckEndpoint(test.name)
+		assert.Equalf(t, test.expected, ret, "case %d failed", i)
+	}
+}
+
+func TestTokenRevocationFlow_authenticateToken(t *testing.T) {
+	mockClient := &sql.Client{
+		ClientID: uuid.NewString(),
+	}
+	mockTokenMgr := rfc7009.NewMockTokenManager(t)
+	h := NewTokenRevocationFlow(NewConfig().SetTokenManager(mockTokenMgr))
+
+	t.Run("success", func(t *testing.T) {
+		mockToken := &sql.Token{ClientID: mockClient.ClientID}
+		mockTokenMgr.On("QueryByToken", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("types.TokenTypeHint")).Return(mockToken, nil).Once()
+
+		r := NewRequestFromHTTP(newFormRequest("token=my-token"))
+		r.Client = mockClient
+		err := h.authenticateToken(r)
+		assert.NoError(t, err)
+		assert.Equal(t, mockToken, r.Tok)
+	})
+
+	t.Run("error_when_token_issued_to_another_client", func(t *testing.T) {
+		mockToken := &sql.Token{ClientID: uuid.NewString()}
+		mockTokenMgr.On("QueryByToken", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("types.TokenTypeHint")).Return(mockToken, nil).Once()
+
+		r := NewRequestFromHTTP(newFormRequest("token=my-token"))
+		r.Client = mockClient
+		err := h.authenticateToken(r)
+		authErr := autherrors.ToAuthLibError(err)
+		assert.Equal(t, autherrors.ErrUnauthorizedClient, authErr.Code)
+		assert.Nil(t, r.Tok)
+	})
+
+	t.Run("error_when_manager_returns_error", func(t *testing.T) {
+		mockTokenMgr.On("QueryByToken", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("types.TokenTypeHint")).Return(nil, errors.New("db error")).Once()
+
+		r := NewRequestFromHTTP(newFormRequest("token=my-token"))
+		r.Client = mockClient
+		err := h.authenticateToken(r)
+		assert.ErrorContains(t, err, "db error")
+	})
+}
+
+func TestTokenRevocationFlow_checkParams(t *testing.T) {
+	h := NewTokenRevocationFlow(NewConfig())
+
+	t.Run("success", func(t *testing.T) {
+		r := NewRequestFromHTTP(newFormRequest("token=my-token&token_type_hint=refresh_token"))
+		err := h.checkParams(r)
+		assert.NoError(t, err)
+	})
+
+	t.Run("error_when_http_method_is_disallowed", func(t *testing.T) {
+		r := NewRequestFromHTTP(httptest.NewRequest(http.MethodGet, "/", nil))
+		err := h.checkParams(r)
+		authErr := autherrors.ToAuthLibError(err)
+		assert.Equal(t, "request must be \"POST\"", authErr.Description)
+	})
+
+	t.Run("error_when_media_type_is_not_supported", func(t *testing.T) {
+		hr := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{\"token\":\"my-token\"}"))
+		hr.Header.Set("Content-Type", "application/json")
+		err := h.checkParams(NewRequestFromHTTP(hr))
+		authErr := autherrors.ToAuthLibError(err)
+		assert.Equal(t, "content type must be \"application/x-www-form-urlencoded\"", authErr.Description)
+	})
+
+	t.Run("error_when_token_is_missing", func(t *testing.T) {
+		err := h.checkParams(NewRequestFromHTTP(newFormRequest("token_type_hint=access_token")))
+		authErr := autherrors.ToAuthLibError(err)
+		assert.Equal(t, autherrors.ErrInvalidRequest, authErr.Code)
+	})
+
+	t.Run("unknown_token_hint_is_ignored", func(t *testing.T) {
+		err := h.checkParams(NewRequestFromHTTP(newFormRequest("token=my-token&token_type_hint=my-hint")))
+		assert.NoError(t, err)
+	})
+
+	t.Run("error_when_token_hint_is_unsupported", func(t *testing.T) {
+		h := NewTokenRevocationFlow(NewConfig().SetSupportedTokenTypes(map[types.TokenTypeHint]bool{
+			types.TokenTypeHintRefreshToken: true,
+		}))
+		err := h.checkParams(NewRequestFromHTTP(newFormRequest("token=my-token&token_type_hint=access_token")))
+		authErr := autherrors.ToAuthLibError(err)
+		assert.Equal(t, autherrors.ErrUnsupportedTokenType, authErr.Code)
+	})
+}
+
+func TestTokenRevocationFlow_revokeToken(t *testing.T) {
+	newRequest := func(token string) *Request {
+		return NewRequestFromHTTP(newFormRequest("token=" + token))
+	}
+
+	t.Run("success_when_token_is_nil", func(t *testing.T) {
+		h := NewTokenRevocationFlow(NewConfig())
+		err := h.revokeToken(newRequest("my-token"))
+		assert.NoError(t, err)
+	})
+
+	t.Run("success_access_token", func(t *testing.T) {
+		mockTokenMgr := rfc7009.NewMockTokenManager(t)
+		h := NewTokenRevocationFlow(NewConfig().SetTokenManager(mockTokenMgr))
+
+		mockToken := &sql.Token{AccessToken: "access", RefreshToken: "refresh", FamilyID: "family"}
+		mockTokenMgr.On("RevokeAccessToken", mock.Anything, mockToken).Return(nil).Once()
+
+		r := newRequest("access")
+		r.Tok = mockToken
+		err := h.revokeToken(r)
+		assert.NoError(t, err)
+	})
+
+	t.Run("success_refresh_token_cascades_to_family", func(t *testing.T) {
+		mockTokenMgr := rfc7009.NewMockTokenManager(t)
+		h := NewTokenRevocationFlow(NewConfig().SetTokenManager(mockTokenMgr))
+
+		mockToken := &sql.Token{AccessToken: "access", RefreshToken: "refresh", FamilyID: "family"}
+		mockTokenMgr.On("RevokeRefreshToken", mock.Anything, mockToken).Return(nil).Once()
+		mockTokenMgr.On("RevokeTokenFamily", mock.Anything, "family").Return(nil).Once()
+
+		r := newRequest("refresh")
+		r.Tok = mockToken
+		err := h.revokeToken(r)
+		assert.NoError(t, err)
+	})
+
+	t.Run("success_refresh_token_without_family", func(t *testing.T) {
+		mockTokenMgr := rfc7009.NewMockTokenManager(t)
+		h := NewTokenRevocationFlow(NewConfig().SetTokenManager(mockTokenMgr))
+
+		mockToken := &sql.Token{AccessToken: "access", RefreshToken: "refresh"}
+		mockTokenMgr.On("RevokeRefreshToken", mock.Anything, mockToken).Return(nil).Once()
+
+		r := newRequest("refresh")
+		r.Tok = mockToken
+		err := h.revokeToken(r)
+		assert.NoError(t, err)
+	})
+
+	t.Run("error_when_refresh_token_revocation_fails", func(t *testing.T) {
+		mockTokenMgr := rfc7009.NewMockTokenManager(t)
+		h := NewTokenRevocationFlow(NewConfig().SetTokenManager(mockTokenMgr))
+
+		mockToken := &sql.Token{AccessToken: "access", RefreshToken: "refresh", FamilyID: "family"}
+		mockTokenMgr.On("RevokeRefreshToken", mock.Anything, mockToken).Return(errors.New("db error")).Once()
+
+		r := newRequest("refresh")
+		r.Tok = mockToken
+		err := h.revokeToken(r)
+		assert.ErrorContains(t, err, "db error")
+	})
+
+	t.Run("error_when_token_type_is_unsupported", func(t *testing.T) {
+		h := NewTokenRevocationFlow(NewConfig().SetSupportedTokenTypes(map[types.TokenTypeHint]bool{
+			types.TokenTypeHintRefreshToken: true,
+		}))
+
+		r := newRequest("access")
+		r.Tok = &sql.Token{AccessToken: "access", RefreshToken: "refresh"}
+		err := h.revokeToken(r)
+		authErr := autherrors.ToAuthLibError(err)
+		assert.Equal(t, autherrors.ErrUnsupportedTokenType, authErr.Code)
+	})
+}
@@ -0,0 +1,38 @@
+package rfc7009
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/tniah/authlib/models"
+	"github.com/tniah/authlib/types"
+)
+
+// ClientManager authenticates the client calling the revocation endpoint.
+type ClientManager interface {
+	// Authenticate verifies the client credentials and returns the authenticated
+	// client. endpointName identifies the endpoint being accessed (used for
+	// method-specific logic in multi-endpoint setups).
+	Authenticate(r *http.Request, authMethods map[types.ClientAuthMethod]bool, endpointName string) (models.Client, error)
+}
+
+// TokenManager looks up and invalidates tokens.
+type TokenManager interface {
+	// QueryByToken looks up the token by its string value. hint is the
+	// token_type_hint from the request (maybe empty). Returns nil without
+	// an error when the token does not exist.
+	QueryByToken(ctx context.Context, token string, hint types.TokenTypeHint) (models.Token, error)
+
+	// RevokeAccessToken invalidates the access token of token only. The
+	// refresh token stored with it, if any, stays usable.
+	RevokeAccessToken(ctx context.Context, token models.Token) error
+
+	// RevokeRefreshToken invalidates the refresh token of token together with
+	// the access token stored with it.
+	RevokeRefreshToken(ctx context.Context, token models.Token) error
+
+	// RevokeTokenFamily invalidates every token sharing familyID, i.e. every
+	// access token issued from the same grant through refresh. Called after
+	// RevokeRefreshToken when the token belongs to a family.
+	RevokeTokenFamily(ctx context.Context, familyID string) error
+}
@@ -0,0 +1,238 @@
+package rfc7523
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	autherrors "github.com/tniah/authlib/errors"
+	"github.com/tniah/authlib/types"
+	"github.com/tniah/authlib/utils"
+)
+
+// DefaultMaxAssertionLifetime is the furthest in the future the exp claim of
+// an assertion may lie. Longer-lived assertions are rejected (RFC 7523 §3,
+// item 4), which also bounds how long JWTIDCache has to remember a jti.
+const DefaultMaxAssertionLifetime = time.Hour
+
+// Sentinel errors returned by ValidateConfig when a required dependency is missing.
+var (
+	ErrNilClientManager         = errors.New("client manager is nil")
+	ErrNilUserManager           = errors.New("user manager is nil")
+	ErrNilTokenManager          = errors.New("token manager is nil")
+	ErrNilJWTIDCache            = errors.New("jwt id cache is nil")
+	ErrNoTrustedIssuers         = errors.New("no trusted issuers configured")
+	ErrEmptyAudiences           = errors.New("audiences are empty")
+	ErrEmptyClientAuthMethods   = errors.New("client auth methods are empty")
+	ErrInvalidAssertionLifetime = errors.New("max assertion lifetime must be positive")
+)
+
+// issuerKey is the verification key material configured for a trusted issuer.
+type issuerKey struct {
+	key    []byte
+	method jwt.SigningMethod
+}
+
+// Config holds all dependencies and extension hooks for the JWT Bearer grant.
+// Use NewConfig() to get a config with sensible defaults, then chain
+// Set*/RegisterExtension calls before passing to Must() or New().
+type Config struct {
+	clientMgr  ClientManager
+	userMgr    UserManager
+	tokenMgr   TokenManager
+	jwtIDCache JWTIDCache
+
+	// trustedIssuers maps an issuer to its verification key. Ignored when
+	// issuerKeyResolver is set.
+	trustedIssuers    map[string]issuerKey
+	issuerKeyResolver IssuerKeyResolver
+
+	// audiences are the values accepted in the aud claim, identifying this
+	// authorization server (RFC 7523 §3, item 3).
+	audiences []string
+
+	leeway               time.Duration
+	maxAssertionLifetime time.Duration
+	requireJWTID         bool
+
+	tokenEndpointHttpMethods []string
+
+	// Extension slices are executed in registration order.
+	tokenReqValidators []TokenRequestValidator
+	tokenProcessors    []TokenProcessor
+
+	// supportedClientAuthMethods controls which authentication methods are
+	// accepted at the token endpoint (basic, post, none).
+	supportedClientAuthMethods map[types.ClientAuthMethod]bool
+}
+
+// NewConfig returns a Config with secure defaults:
+//   - Accepts POST on /token.
+//   - Supports basic and none client authentication; the assertion itself is
+//     the authorization grant, so client authentication is optional
+//     (RFC 7523 §3.1).
+//   - Requires a jti claim and rejects replayed assertions using an in-memory
+//     JWTIDCache.
+//   - Rejects assertions expiring more than DefaultMaxAssertionLifetime ahead.
+func NewConfig() *Config {
+	return &Config{
+		trustedIssuers:       make(map[string]issuerKey),
+		jwtIDCache:           utils.NewMemoryJWTIDCache(),
+		maxAssertionLifetime: DefaultMaxAssertionLifetime,
+		requireJWTID:         true,
+		supportedClientAuthMethods: map[types.ClientAuthMethod]bool{
+			types.ClientBasicAuthentication: true,
+			types.ClientNoneAuthentication:  true,
+		},
+		tokenEndpointHttpMethods: []string{http.MethodPost},
+		tokenReqValidators:       []TokenRequestValidator{},
+		tokenProcessors:          []TokenProcessor{},
+	}
+}
+
+// SetClientManager sets the client authentication manager.
+func (cfg *Config) SetClientManager(mgr ClientManager) *Config {
+	cfg.clientMgr = mgr
+	return cfg
+}
+
+// SetUserManager sets the user resolver used to map the sub claim to a user.
+func (cfg *Config) SetUserManager(mgr UserManager) *Config {
+	cfg.userMgr = mgr
+	return cfg
+}
+
+// SetTokenManager sets the token generation and persistence manager.
+func (cfg *Config) SetTokenManager(mgr TokenManager) *Config {
+	cfg.tokenMgr = mgr
+	return cfg
+}
+
+// SetTrustedIssuer trusts assertions signed by issuer with key, a PEM-encoded
+// public key or the shared secret for HMAC methods. Calling it again for the
+// same issuer replaces its key. Ignored when SetIssuerKeyResolver is set.
+func (cfg *Config) SetTrustedIssuer(issuer string, key []byte, method jwt.SigningMethod) *Config {
+	cfg.trustedIssuers[issuer] = issuerKey{key: key, method: method}
+	return cfg
+}
+
+// SetIssuerKeyResolver registers a per-request verification key hook, e.g.
+// backed by a JWKS endpoint. Takes precedence over SetTrustedIssuer when set.
+func (cfg *Config) SetIssuerKeyResolver(fn IssuerKeyResolver) *Config {
+	cfg.issuerKeyResolver = fn
+	return cfg
+}
+
+// SetAudiences sets the values accepted in the aud claim. Use the issuer
+// identifier of this authorization server and/or the URL of its token
+// endpoint. Required.
+func (cfg *Config) SetAudiences(audiences []string) *Config {
+	cfg.audiences = audiences
+	return cfg
+}
+
+// SetLeeway sets the clock skew tolerated when checking exp, nbf, and iat.
+// Default: 0.
+func (cfg *Config) SetLeeway(leeway time.Duration) *Config {
+	cfg.leeway = leeway
+	return cfg
+}
+
+// SetMaxAssertionLifetime overrides how far in the future exp may lie.
+// Default: DefaultMaxAssertionLifetime.
+func (cfg *Config) SetMaxAssertionLifetime(d time.Duration) *Config {
+	cfg.maxAssertionLifetime = d
+	return cfg
+}
+
+// SetRequireJWTID controls whether assertions without a jti claim are
+// rejected. Assertions carrying a jti are always checked against JWTIDCache.
+// Default: true.
+func (cfg *Config) SetRequireJWTID(required bool) *Config {
+	cfg.requireJWTID = required
+	return cfg
+}
+
+// SetJWTIDCache overrides the replay cache. Default: utils.NewMemoryJWTIDCache().
+func (cfg *Config) SetJWTIDCache(cache JWTIDCache) *Config {
+	cfg.jwtIDCache = cache
+	return cfg
+}
+
+// SetTokenEndpointHttpMethods overrides the HTTP methods accepted at /token.
+// Default: [POST].
+func (cfg *Config) SetTokenEndpointHttpMethods(methods []string) *Config {
+	cfg.tokenEndpointHttpMethods = methods
+	return cfg
+}
+
+// SetSupportedClientAuthMethods overrides which client authentication methods
+// are accepted at the token endpoint. Default: basic, none.
+func (cfg *Config) SetSupportedClientAuthMethods(methods map[types.ClientAuthMethod]bool) *Config {
+	cfg.supportedClientAuthMethods = methods
+	return cfg
+}
+
+// RegisterExtension adds ext to every extension slice whose interface it satisfies.
+// A single object may implement both TokenRequestValidator and TokenProcessor.
+func (cfg *Config) RegisterExtension(ext interface{}) *Config {
+	if h, ok := ext.(TokenRequestValidator); ok {
+		cfg.tokenReqValidators = append(cfg.tokenReqValidators, h)
+	}
+
+	if h, ok := ext.(TokenProcessor); ok {
+		cfg.tokenProcessors = append(cfg.tokenProcessors, h)
+	}
+
+	return cfg
+}
+
+// ValidateConfig checks that all required dependencies are set and returns the
+// first sentinel error encountered. Call this via Must() rather than directly.
+func (cfg *Config) ValidateConfig() error {
+	if utils.IsNil(cfg.clientMgr) {
+		return ErrNilClientManager
+	}
+
+	if utils.IsNil(cfg.userMgr) {
+		return ErrNilUserManager
+	}
+
+	if utils.IsNil(cfg.tokenMgr) {
+		return ErrNilTokenManager
+	}
+
+	if utils.IsNil(cfg.jwtIDCache) {
+		return ErrNilJWTIDCache
+	}
+
+	if len(cfg.trustedIssuers) == 0 && cfg.issuerKeyResolver == nil {
+		return ErrNoTrustedIssuers
+	}
+
+	for _, k := range cfg.trustedIssuers {
+		if k.method == nil {
+			return autherrors.ErrMissingSigningKeyMethod
+		}
+
+		// Unsigned assertions cannot be trusted (RFC 7523 §3, item 9).
+		if k.method == jwt.SigningMethodNone {
+			return autherrors.ErrInsecureSigningMethod
+		}
+	}
+
+	if len(cfg.audiences) == 0 {
+		return ErrEmptyAudiences
+	}
+
+	if cfg.maxAssertionLifetime <= 0 {
+		return ErrInvalidAssertionLifetime
+	}
+
+	if len(cfg.supportedClientAuthMethods) == 0 {
+		return ErrEmptyClientAuthMethods
+	}
+
+	return nil
+}
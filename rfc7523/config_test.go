@@ -0,0 +1,107 @@
+package rfc7523
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	autherrors "github.com/tniah/authlib/errors"
+	mock "github.com/tniah/authlib/mocks/rfc7523"
+	"github.com/tniah/authlib/types"
+	"github.com/tniah/authlib/utils"
+)
+
+func TestConfig(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		cfg := NewConfig()
+		assert.Equal(t, []string{http.MethodPost}, cfg.tokenEndpointHttpMethods)
+		assert.Equal(t, map[types.ClientAuthMethod]bool{
+			types.ClientBasicAuthentication: true,
+			types.ClientNoneAuthentication:  true,
+		}, cfg.supportedClientAuthMethods)
+		assert.Equal(t, DefaultMaxAssertionLifetime, cfg.maxAssertionLifetime)
+		assert.True(t, cfg.requireJWTID)
+		assert.IsType(t, &utils.MemoryJWTIDCache{}, cfg.jwtIDCache)
+
+		clientMgr := mock.NewMockClientManager(t)
+		userMgr := mock.NewMockUserManager(t)
+		tokenMgr := mock.NewMockTokenManager(t)
+		jwtIDCache := mock.NewMockJWTIDCache(t)
+		validator := mock.NewMockTokenRequestValidator(t)
+		processor := mock.NewMockTokenProcessor(t)
+		resolver := func(ctx context.Context, issuer, keyID string) ([]byte, jwt.SigningMethod, error) {
+			return nil, nil, nil
+		}
+
+		cfg.SetClientManager(clientMgr).
+			SetUserManager(userMgr).
+			SetTokenManager(tokenMgr).
+			SetJWTIDCache(jwtIDCache).
+			SetTrustedIssuer("https://idp.example.com", []byte("secret"), jwt.SigningMethodHS256).
+			SetIssuerKeyResolver(resolver).
+			SetAudiences([]string{"https://as.example.com"}).
+			SetLeeway(time.Second).
+			SetMaxAssertionLifetime(time.Minute).
+			SetRequireJWTID(false).
+			SetTokenEndpointHttpMethods([]string{http.MethodPut}).
+			SetSupportedClientAuthMethods(map[types.ClientAuthMethod]bool{types.ClientPostAuthentication: true}).
+			RegisterExtension(validator).
+			RegisterExtension(processor)
+
+		assert.Equal(t, clientMgr, cfg.clientMgr)
+		assert.Equal(t, userMgr, cfg.userMgr)
+		assert.Equal(t, tokenMgr, cfg.tokenMgr)
+		assert.Equal(t, jwtIDCache, cfg.jwtIDCache)
+		assert.Equal(t, map[string]issuerKey{
+			"https://idp.example.com": {key: []byte("secret"), method: jwt.SigningMethodHS256},
+		}, cfg.trustedIssuers)
+		assert.NotNil(t, cfg.issuerKeyResolver)
+		assert.Equal(t, []string{"https://as.example.com"}, cfg.audiences)
+		assert.Equal(t, time.Second, cfg.leeway)
+		assert.Equal(t, time.Minute, cfg.maxAssertionLifetime)
+		assert.False(t, cfg.requireJWTID)
+		assert.Equal(t, []string{http.MethodPut}, cfg.tokenEndpointHttpMethods)
+		assert.Equal(t, map[types.ClientAuthMethod]bool{types.ClientPostAuthentication: true}, cfg.supportedClientAuthMethods)
+		assert.Equal(t, []TokenRequestValidator{validator}, cfg.tokenReqValidators)
+		assert.Equal(t, []TokenProcessor{processor}, cfg.tokenProcessors)
+		assert.NoError(t, cfg.ValidateConfig())
+	})
+
+	t.Run("error", func(t *testing.T) {
+		cfg := NewConfig()
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrNilClientManager)
+
+		cfg.SetClientManager(mock.NewMockClientManager(t))
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrNilUserManager)
+
+		cfg.SetUserManager(mock.NewMockUserManager(t))
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrNilTokenManager)
+
+		cfg.SetTokenManager(mock.NewMockTokenManager(t))
+		cfg.SetJWTIDCache(nil)
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrNilJWTIDCache)
+
+		cfg.SetJWTIDCache(utils.NewMemoryJWTIDCache())
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrNoTrustedIssuers)
+
+		cfg.SetTrustedIssuer("https://idp.example.com", []byte("secret"), nil)
+		assert.ErrorIs(t, cfg.ValidateConfig(), autherrors.ErrMissingSigningKeyMethod)
+
+		cfg.SetTrustedIssuer("https://idp.example.com", []byte("secret"), jwt.SigningMethodNone)
+		assert.ErrorIs(t, cfg.ValidateConfig(), autherrors.ErrInsecureSigningMethod)
+
+		cfg.SetTrustedIssuer("https://idp.example.com", []byte("secret"), jwt.SigningMethodHS256)
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrEmptyAudiences)
+
+		cfg.SetAudiences([]string{"https://as.example.com"})
+		cfg.SetMaxAssertionLifetime(0)
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrInvalidAssertionLifetime)
+
+		cfg.SetMaxAssertionLifetime(time.Minute)
+		cfg.SetSupportedClientAuthMethods(nil)
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrEmptyClientAuthMethods)
+	})
+}
@@ -0,0 +1,351 @@
+package rfc7523
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	autherrors "github.com/tniah/authlib/errors"
+	"github.com/tniah/authlib/models"
+	"github.com/tniah/authlib/requests"
+	"github.com/tniah/authlib/rfc6749"
+	"github.com/tniah/authlib/types"
+	"github.com/tniah/authlib/utils"
+)
+
+// EndpointToken is the endpoint name passed to ClientManager.Authenticate so
+// that the client store can apply per-endpoint auth method policies.
+const EndpointToken = "token"
+
+var (
+	// ErrNilToken is returned by genToken when TokenManager.New returns nil.
+	ErrNilToken = errors.New("token is nil")
+
+	// errUntrustedIssuer and errUnexpectedSigningMethod are returned by the
+	// key function and surface to the client as invalid_grant.
+	errUntrustedIssuer         = errors.New("untrusted issuer")
+	errUnexpectedSigningMethod = errors.New("unexpected signing method")
+)
+
+// Flow implements the JWT Bearer authorization grant (RFC 7523 §2.1). The
+// client presents a JWT signed by a trusted issuer and receives an access
+// token for the user named in its sub claim.
+type Flow struct {
+	*Config
+	*rfc6749.TokenFlowMixin
+}
+
+// New creates a Flow without validating config. Use Must for production use.
+func New(cfg *Config) *Flow {
+	return &Flow{Config: cfg, TokenFlowMixin: &rfc6749.TokenFlowMixin{}}
+}
+
+// Must returns a validated Flow or an error if the config is incomplete.
+func Must(cfg *Config) (*Flow, error) {
+	if err := cfg.ValidateConfig(); err != nil {
+		return nil, err
+	}
+
+	return New(cfg), nil
+}
+
+// CheckGrantType reports whether this flow handles the given grant_type.
+func (f *Flow) CheckGrantType(gt types.GrantType) bool {
+	return gt.IsJWTBearer()
+}
+
+// ProvideMetadata adds the JWT bearer grant and the client authentication
+// methods accepted at the token endpoint to the authorization server metadata
+// (RFC 8414 §2).
+func (f *Flow) ProvideMetadata(md types.Metadata) {
+	md.Add(types.MetadataGrantTypesSupported, types.GrantTypeJWTBearer.String())
+	md.AddClientAuthMethods(types.MetadataTokenEndpointAuthMethodsSupported, f.supportedClientAuthMethods)
+}
+
+// ValidateTokenRequest validates the /token request: HTTP method, grant_type,
+// assertion presence, client authentication, the assertion's signature and
+// claims, scope, the user named by sub, and any registered
+// TokenRequestValidator extensions.
+func (f *Flow) ValidateTokenRequest(r *requests.TokenRequest) error {
+	if err := f.checkTokenEndpointHttpMethod(r); err != nil {
+		return err
+	}
+
+	if err := f.validateGrantType(r); err != nil {
+		return err
+	}
+
+	if err := r.ValidateAssertion(); err != nil {
+		return err
+	}
+
+	if err := f.authenticateClient(r); err != nil {
+		return err
+	}
+
+	if err := f.validateAssertion(r); err != nil {
+		return err
+	}
+
+	if err := f.validateScope(r); err != nil {
+		return err
+	}
+
+	if err := f.queryUserByAssertion(r); err != nil {
+		return err
+	}
+
+	for _, h := range f.tokenReqValidators {
+		if err := h.ValidateTokenRequest(r); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// TokenResponse issues the access token, runs TokenProcessor extensions,
+// persists the token, and writes the JSON response (RFC 6749 §5.1). No
+// refresh token is issued.
+func (f *Flow) TokenResponse(r *requests.TokenRequest, rw http.ResponseWriter) error {
+	token, err := f.genToken(r)
+	if err != nil {
+		return err
+	}
+
+	data := f.StandardTokenData(token)
+	for _, h := range f.tokenProcessors {
+		if err = h.ProcessToken(r, token, data); err != nil {
+			return err
+		}
+	}
+
+	if err = f.tokenMgr.Save(r.Request.Context(), token); err != nil {
+		return err
+	}
+
+	return f.HandleTokenResponse(rw, data)
+}
+
+// checkTokenEndpointHttpMethod rejects requests whose HTTP method is not in
+// tokenEndpointHttpMethods (default: POST).
+func (f *Flow) checkTokenEndpointHttpMethod(r *requests.TokenRequest) error {
+	for _, method := range f.tokenEndpointHttpMethods {
+		if r.Method() == method {
+			return nil
+		}
+	}
+
+	return autherrors.InvalidRequestError().WithDescription(fmt.Sprintf("unsupported http method \"%s\"", r.Method()))
+}
+
+// validateGrantType checks that grant_type is present and equals
+// "urn:ietf:params:oauth:grant-type:jwt-bearer".
+func (f *Flow) validateGrantType(r *requests.TokenRequest) error {
+	if err := r.ValidateGrantType(); err != nil {
+		return err
+	}
+
+	if valid := r.GrantType.IsJWTBearer(); !valid {
+		return autherrors.UnsupportedGrantTypeError()
+	}
+
+	return nil
+}
+
+// authenticateClient delegates to ClientManager.Authenticate, then verifies the
+// client is permitted to use the JWT bearer grant.
+func (f *Flow) authenticateClient(r *requests.TokenRequest) error {
+	client, err := f.clientMgr.Authenticate(r.Request, f.supportedClientAuthMethods, EndpointToken)
+	if err != nil {
+		return err
+	}
+
+	if utils.IsNil(client) {
+		return autherrors.InvalidClientError()
+	}
+
+	if allowed := client.CheckGrantType(types.GrantTypeJWTBearer); !allowed {
+		return autherrors.UnauthorizedClientError().WithDescription("The client is not authorized to use grant type \"urn:ietf:params:oauth:grant-type:jwt-bearer\"")
+	}
+
+	r.Client = client
+	return nil
+}
+
+// validateAssertion verifies the assertion's signature against the key of its
+// issuer and checks its claims (RFC 7523 §3): iss and sub are present, aud
+// names this server, exp is present and not too far ahead, nbf and iat are
+// not in the future, and jti has not been seen before. Populates
+// r.AssertionClaims. A bad assertion is rejected with invalid_grant
+// (RFC 7523 §3.1).
+func (f *Flow) validateAssertion(r *requests.TokenRequest) error {
+	ctx := r.Request.Context()
+
+	// resolveErr keeps failures of IssuerKeyResolver apart from bad
+	// assertions: the former are server errors, not invalid_grant.
+	var resolveErr error
+	keyFunc := func(t *jwt.Token) (interface{}, error) {
+		key, err := f.verificationKey(ctx, t)
+		if err != nil && !errors.Is(err, errUntrustedIssuer) && !errors.Is(err, errUnexpectedSigningMethod) {
+			resolveErr = err
+		}
+
+		return key, err
+	}
+
+	parser := jwt.NewParser(
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(f.leeway),
+		jwt.WithAudience(f.audiences...),
+	)
+
+	claims := jwt.MapClaims{}
+	if _, err := parser.ParseWithClaims(r.Assertion, claims, keyFunc); err != nil {
+		if resolveErr != nil {
+			return resolveErr
+		}
+
+		return autherrors.InvalidGrantError().WithDescription("the \"assertion\" is invalid").WithCause(err)
+	}
+
+	if sub, _ := claims.GetSubject(); sub == "" {
+		return autherrors.InvalidGrantError().WithDescription("missing \"sub\" in \"assertion\"")
+	}
+
+	exp, _ := claims.GetExpirationTime()
+	if exp.After(time.Now().Add(f.maxAssertionLifetime + f.leeway)) {
+		return autherrors.InvalidGrantError().WithDescription("the \"assertion\" expires too far in the future")
+	}
+
+	if err := f.checkJWTID(ctx, claims, exp.Time); err != nil {
+		return err
+	}
+
+	r.AssertionClaims = claims
+	return nil
+}
+
+// verificationKey looks up the key of the assertion's issuer and checks that
+// the assertion is signed with the method configured for it. The header alg
+// is never trusted on its own, which rules out "none" and algorithm
+// confusion.
+func (f *Flow) verificationKey(ctx context.Context, t *jwt.Token) (interface{}, error) {
+	iss, _ := t.Claims.GetIssuer()
+	if iss == "" {
+		return nil, errUntrustedIssuer
+	}
+
+	keyID, _ := t.Header["kid"].(string)
+	key, method, err := f.issuerKeyHandler(ctx, iss, keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if key == nil {
+		return nil, errUntrustedIssuer
+	}
+
+	if method == nil || method == jwt.SigningMethodNone || t.Method.Alg() != method.Alg() {
+		return nil, errUnexpectedSigningMethod
+	}
+
+	return utils.ParseVerificationKey(key, method)
+}
+
+// issuerKeyHandler returns the verification key and method of issuer.
+// Delegates to IssuerKeyResolver if set, otherwise looks up the statically
+// trusted issuers.
+func (f *Flow) issuerKeyHandler(ctx context.Context, issuer, keyID string) ([]byte, jwt.SigningMethod, error) {
+	if fn := f.issuerKeyResolver; fn != nil {
+		return fn(ctx, issuer, keyID)
+	}
+
+	k, ok := f.trustedIssuers[issuer]
+	if !ok {
+		return nil, nil, nil
+	}
+
+	return k.key, k.method, nil
+}
+
+// checkJWTID enforces the jti claim and records it in JWTIDCache so that the
+// assertion cannot be replayed before it expires.
+func (f *Flow) checkJWTID(ctx context.Context, claims jwt.MapClaims, exp time.Time) error {
+	jti, _ := claims["jti"].(string)
+	if jti == "" {
+		if f.requireJWTID {
+			return autherrors.InvalidGrantError().WithDescription("missing \"jti\" in \"assertion\"")
+		}
+
+		return nil
+	}
+
+	iss, _ := claims.GetIssuer()
+	ok, err := f.jwtIDCache.Use(ctx, iss, jti, exp.Add(f.leeway))
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return autherrors.InvalidGrantError().WithDescription("the \"assertion\" has already been used")
+	}
+
+	return nil
+}
+
+// validateScope filters the requested scopes through the client's allowed
+// list. When the scope parameter is omitted, the client's registered scopes
+// are granted.
+func (f *Flow) validateScope(r *requests.TokenRequest) error {
+	if len(r.Scopes) == 0 {
+		r.Scopes = r.Client.GetScopes()
+		return nil
+	}
+
+	allowed := r.Client.GetAllowedScopes(r.Scopes)
+	if len(allowed) == 0 {
+		return autherrors.InvalidScopeError().WithDescription("none of the requested scopes are permitted for this client")
+	}
+
+	r.Scopes = allowed
+	return nil
+}
+
+// queryUserByAssertion resolves the user named by the sub claim and populates
+// r.User. Returns invalid_grant if no user is found.
+func (f *Flow) queryUserByAssertion(r *requests.TokenRequest) error {
+	claims := jwt.MapClaims(r.AssertionClaims)
+	iss, _ := claims.GetIssuer()
+	sub, _ := claims.GetSubject()
+
+	user, err := f.userMgr.QueryUserByAssertion(r.Request.Context(), iss, sub, r)
+	if err != nil {
+		return err
+	}
+
+	if utils.IsNil(user) {
+		return autherrors.InvalidGrantError().WithDescription("No user could be found associated with the \"sub\" of the \"assertion\"")
+	}
+
+	r.User = user
+	return nil
+}
+
+// genToken allocates and populates a new access token.
+func (f *Flow) genToken(r *requests.TokenRequest) (models.Token, error) {
+	token := f.tokenMgr.New()
+	if utils.IsNil(token) {
+		return nil, ErrNilToken
+	}
+
+	if err := f.tokenMgr.Generate(token, r, false); err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
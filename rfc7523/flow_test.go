@@ -0,0 +1,364 @@
+package rfc7523
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	autherrors "github.com/tniah/authlib/errors"
+	"github.com/tniah/authlib/integrations/sql"
+	rfc7523 "github.com/tniah/authlib/mocks/rfc7523"
+	"github.com/tniah/authlib/requests"
+	"github.com/tniah/authlib/types"
+)
+
+const (
+	testIssuer   = "https://idp.example.com"
+	testAudience = "https://as.example.com/token"
+)
+
+var testSecret = []byte("idp-shared-secret")
+
+func signAssertion(t *testing.T, claims jwt.MapClaims, method jwt.SigningMethod, key interface{}) string {
+	t.Helper()
+	assertion, err := jwt.NewWithClaims(method, claims).SignedString(key)
+	require.NoError(t, err)
+	return assertion
+}
+
+func validClaims() jwt.MapClaims {
+	now := time.Now()
+	return jwt.MapClaims{
+		"iss": testIssuer,
+		"sub": "alice",
+		"aud": testAudience,
+		"exp": now.Add(5 * time.Minute).Unix(),
+		"iat": now.Unix(),
+		"jti": "assertion-1",
+	}
+}
+
+func TestFlow_Must(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		f, err := Must(NewConfig().
+			SetClientManager(rfc7523.NewMockClientManager(t)).
+			SetUserManager(rfc7523.NewMockUserManager(t)).
+			SetTokenManager(rfc7523.NewMockTokenManager(t)).
+			SetTrustedIssuer(testIssuer, testSecret, jwt.SigningMethodHS256).
+			SetAudiences([]string{testAudience}))
+		require.NoError(t, err)
+		assert.NotNil(t, f)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		f, err := Must(NewConfig())
+		require.Error(t, err)
+		assert.Nil(t, f)
+	})
+}
+
+func TestFlow_CheckGrantType(t *testing.T) {
+	f := New(NewConfig())
+	assert.True(t, f.CheckGrantType(types.GrantTypeJWTBearer))
+	assert.False(t, f.CheckGrantType(types.GrantTypeClientCredentials))
+	assert.False(t, f.CheckGrantType(types.NewGrantType("")))
+}
+
+func TestFlow_ValidateTokenRequest(t *testing.T) {
+	mockClient := &sql.Client{
+		ClientID:   "backend",
+		GrantTypes: []string{string(types.GrantTypeJWTBearer)},
+		Scopes:     []string{"profile", "email"},
+	}
+	mockUser := &sql.User{UserID: "alice"}
+
+	newRequest := func(assertion string) *requests.TokenRequest {
+		return &requests.TokenRequest{
+			Request:   httptest.NewRequest(http.MethodPost, "/", nil),
+			GrantType: types.GrantTypeJWTBearer,
+			Assertion: assertion,
+		}
+	}
+
+	newConfig := func(t *testing.T) (*Config, *rfc7523.MockUserManager) {
+		mockClientMgr := rfc7523.NewMockClientManager(t)
+		mockClientMgr.On("Authenticate", mock.AnythingOfType("*http.Request"), mock.AnythingOfType("map[types.ClientAuthMethod]bool"), EndpointToken).Return(mockClient, nil).Once()
+
+		mockUserMgr := rfc7523.NewMockUserManager(t)
+		cfg := NewConfig().
+			SetClientManager(mockClientMgr).
+			SetUserManager(mockUserMgr).
+			SetTrustedIssuer(testIssuer, testSecret, jwt.SigningMethodHS256).
+			SetAudiences([]string{"https://as.example.com", testAudience})
+		return cfg, mockUserMgr
+	}
+
+	t.Run("success", func(t *testing.T) {
+		cfg, mockUserMgr := newConfig(t)
+		mockUserMgr.On("QueryUserByAssertion", mock.Anything, testIssuer, "alice", mock.Anything).Return(mockUser, nil).Once()
+
+		r := newRequest(signAssertion(t, validClaims(), jwt.SigningMethodHS256, testSecret))
+		require.NoError(t, New(cfg).ValidateTokenRequest(r))
+		assert.Equal(t, mockClient, r.Client)
+		assert.Equal(t, mockUser, r.User)
+		assert.Equal(t, types.Scopes{"profile", "email"}, r.Scopes)
+		assert.Equal(t, "alice", r.AssertionClaims["sub"])
+	})
+
+	t.Run("success_with_issuer_key_resolver", func(t *testing.T) {
+		privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		der, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+		require.NoError(t, err)
+		publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+		cfg, mockUserMgr := newConfig(t)
+		cfg.SetIssuerKeyResolver(func(ctx context.Context, issuer, keyID string) ([]byte, jwt.SigningMethod, error) {
+			if issuer == testIssuer && keyID == "key-1" {
+				return publicPEM, jwt.SigningMethodRS256, nil
+			}
+			return nil, nil, nil
+		})
+		mockUserMgr.On("QueryUserByAssertion", mock.Anything, testIssuer, "alice", mock.Anything).Return(mockUser, nil).Once()
+
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, validClaims())
+		token.Header["kid"] = "key-1"
+		assertion, err := token.SignedString(privateKey)
+		require.NoError(t, err)
+
+		r := newRequest(assertion)
+		r.Scopes = types.Scopes{"email", "admin"}
+		require.NoError(t, New(cfg).ValidateTokenRequest(r))
+		assert.Equal(t, types.Scopes{"email"}, r.Scopes)
+	})
+
+	t.Run("error_on_algorithm_confusion", func(t *testing.T) {
+		privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		der, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+		require.NoError(t, err)
+		publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+		cfg, _ := newConfig(t)
+		cfg.SetIssuerKeyResolver(func(ctx context.Context, issuer, keyID string) ([]byte, jwt.SigningMethod, error) {
+			return publicPEM, jwt.SigningMethodRS256, nil
+		})
+
+		// Signed with HMAC using the issuer's public key as the secret.
+		r := newRequest(signAssertion(t, validClaims(), jwt.SigningMethodHS256, publicPEM))
+		err = New(cfg).ValidateTokenRequest(r)
+		assert.Equal(t, autherrors.ErrInvalidGrant, autherrors.ToAuthLibError(err).Code)
+	})
+
+	t.Run("error_on_invalid_assertion", func(t *testing.T) {
+		cases := []struct {
+			name      string
+			assertion func(t *testing.T) string
+		}{
+			{"malformed", func(t *testing.T) string { return "not-a-jwt" }},
+			{"untrusted_issuer", func(t *testing.T) string {
+				claims := validClaims()
+				claims["iss"] = "https://evil.example.com"
+				return signAssertion(t, claims, jwt.SigningMethodHS256, testSecret)
+			}},
+			{"missing_issuer", func(t *testing.T) string {
+				claims := validClaims()
+				delete(claims, "iss")
+				return signAssertion(t, claims, jwt.SigningMethodHS256, testSecret)
+			}},
+			{"bad_signature", func(t *testing.T) string {
+				return signAssertion(t, validClaims(), jwt.SigningMethodHS256, []byte("wrong-secret"))
+			}},
+			{"unexpected_signing_method", func(t *testing.T) string {
+				return signAssertion(t, validClaims(), jwt.SigningMethodHS384, testSecret)
+			}},
+			{"unsigned", func(t *testing.T) string {
+				return signAssertion(t, validClaims(), jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType)
+			}},
+			{"missing_sub", func(t *testing.T) string {
+				claims := validClaims()
+				delete(claims, "sub")
+				return signAssertion(t, claims, jwt.SigningMethodHS256, testSecret)
+			}},
+			{"wrong_audience", func(t *testing.T) string {
+				claims := validClaims()
+				claims["aud"] = "https://other.example.com"
+				return signAssertion(t, claims, jwt.SigningMethodHS256, testSecret)
+			}},
+			{"missing_exp", func(t *testing.T) string {
+				claims := validClaims()
+				delete(claims, "exp")
+				return signAssertion(t, claims, jwt.SigningMethodHS256, testSecret)
+			}},
+			{"expired", func(t *testing.T) string {
+				claims := validClaims()
+				claims["exp"] = time.Now().Add(-time.Minute).Unix()
+				return signAssertion(t, claims, jwt.SigningMethodHS256, testSecret)
+			}},
+			{"exp_too_far_ahead", func(t *testing.T) string {
+				claims := validClaims()
+				claims["exp"] = time.Now().Add(2 * DefaultMaxAssertionLifetime).Unix()
+				return signAssertion(t, claims, jwt.SigningMethodHS256, testSecret)
+			}},
+			{"not_yet_valid", func(t *testing.T) string {
+				claims := validClaims()
+				claims["nbf"] = time.Now().Add(time.Minute).Unix()
+				return signAssertion(t, claims, jwt.SigningMethodHS256, testSecret)
+			}},
+			{"missing_jti", func(t *testing.T) string {
+				claims := validClaims()
+				delete(claims, "jti")
+				return signAssertion(t, claims, jwt.SigningMethodHS256, testSecret)
+			}},
+		}
+
+		for _, c := range cases {
+			t.Run(c.name, func(t *testing.T) {
+				cfg, _ := newConfig(t)
+				err := New(cfg).ValidateTokenRequest(newRequest(c.assertion(t)))
+				assert.Equal(t, autherrors.ErrInvalidGrant, autherrors.ToAuthLibError(err).Code)
+			})
+		}
+	})
+
+	t.Run("error_on_replay", func(t *testing.T) {
+		assertion := signAssertion(t, validClaims(), jwt.SigningMethodHS256, testSecret)
+
+		cfg, mockUserMgr := newConfig(t)
+		mockUserMgr.On("QueryUserByAssertion", mock.Anything, testIssuer, "alice", mock.Anything).Return(mockUser, nil).Once()
+		f := New(cfg)
+		require.NoError(t, f.ValidateTokenRequest(newRequest(assertion)))
+
+		mockClientMgr := rfc7523.NewMockClientManager(t)
+		mockClientMgr.On("Authenticate", mock.Anything, mock.Anything, EndpointToken).Return(mockClient, nil).Once()
+		f.SetClientManager(mockClientMgr)
+
+		err := f.ValidateTokenRequest(newRequest(assertion))
+		assert.Equal(t, autherrors.ErrInvalidGrant, autherrors.ToAuthLibError(err).Code)
+	})
+
+	t.Run("success_without_jti_when_not_required", func(t *testing.T) {
+		claims := validClaims()
+		delete(claims, "jti")
+
+		cfg, mockUserMgr := newConfig(t)
+		cfg.SetRequireJWTID(false)
+		mockUserMgr.On("QueryUserByAssertion", mock.Anything, testIssuer, "alice", mock.Anything).Return(mockUser, nil).Once()
+
+		r := newRequest(signAssertion(t, claims, jwt.SigningMethodHS256, testSecret))
+		assert.NoError(t, New(cfg).ValidateTokenRequest(r))
+	})
+
+	t.Run("error_from_issuer_key_resolver", func(t *testing.T) {
+		resolveErr := errors.New("jwks unavailable")
+		cfg, _ := newConfig(t)
+		cfg.SetIssuerKeyResolver(func(ctx context.Context, issuer, keyID string) ([]byte, jwt.SigningMethod, error) {
+			return nil, nil, resolveErr
+		})
+
+		r := newRequest(signAssertion(t, validClaims(), jwt.SigningMethodHS256, testSecret))
+		assert.ErrorIs(t, New(cfg).ValidateTokenRequest(r), resolveErr)
+	})
+
+	t.Run("error_when_assertion_missing", func(t *testing.T) {
+		f := New(NewConfig())
+		err := f.ValidateTokenRequest(newRequest(""))
+		assert.Equal(t, autherrors.ErrInvalidRequest, autherrors.ToAuthLibError(err).Code)
+	})
+
+	t.Run("error_when_grant_type_mismatch", func(t *testing.T) {
+		f := New(NewConfig())
+		r := newRequest("assertion")
+		r.GrantType = types.GrantTypeClientCredentials
+		err := f.ValidateTokenRequest(r)
+		assert.Equal(t, autherrors.ErrUnsupportedGrantType, autherrors.ToAuthLibError(err).Code)
+	})
+
+	t.Run("error_when_grant_type_not_allowed", func(t *testing.T) {
+		mockClientMgr := rfc7523.NewMockClientManager(t)
+		mockClientMgr.On("Authenticate", mock.Anything, mock.Anything, EndpointToken).Return(&sql.Client{ClientID: "other"}, nil).Once()
+
+		f := New(NewConfig().SetClientManager(mockClientMgr))
+		err := f.ValidateTokenRequest(newRequest("assertion"))
+		assert.Equal(t, autherrors.ErrUnauthorizedClient, autherrors.ToAuthLibError(err).Code)
+	})
+
+	t.Run("error_when_scope_not_allowed", func(t *testing.T) {
+		cfg, _ := newConfig(t)
+		r := newRequest(signAssertion(t, validClaims(), jwt.SigningMethodHS256, testSecret))
+		r.Scopes = types.Scopes{"admin"}
+		err := New(cfg).ValidateTokenRequest(r)
+		assert.Equal(t, autherrors.ErrInvalidScope, autherrors.ToAuthLibError(err).Code)
+	})
+
+	t.Run("error_when_user_not_found", func(t *testing.T) {
+		cfg, mockUserMgr := newConfig(t)
+		mockUserMgr.On("QueryUserByAssertion", mock.Anything, testIssuer, "alice", mock.Anything).Return(nil, nil).Once()
+
+		r := newRequest(signAssertion(t, validClaims(), jwt.SigningMethodHS256, testSecret))
+		err := New(cfg).ValidateTokenRequest(r)
+		assert.Equal(t, autherrors.ErrInvalidGrant, autherrors.ToAuthLibError(err).Code)
+	})
+
+	t.Run("error_from_extension", func(t *testing.T) {
+		cfg, mockUserMgr := newConfig(t)
+		mockUserMgr.On("QueryUserByAssertion", mock.Anything, testIssuer, "alice", mock.Anything).Return(mockUser, nil).Once()
+
+		validator := rfc7523.NewMockTokenRequestValidator(t)
+		validator.On("ValidateTokenRequest", mock.Anything).Return(errors.New("rejected")).Once()
+		cfg.RegisterExtension(validator)
+
+		r := newRequest(signAssertion(t, validClaims(), jwt.SigningMethodHS256, testSecret))
+		assert.EqualError(t, New(cfg).ValidateTokenRequest(r), "rejected")
+	})
+}
+
+func TestFlow_TokenResponse(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		r := &requests.TokenRequest{
+			Request:   httptest.NewRequest(http.MethodPost, "/", nil),
+			GrantType: types.GrantTypeJWTBearer,
+		}
+		token := &sql.Token{AccessToken: "issued", TokenType: "Bearer"}
+
+		mockTokenMgr := rfc7523.NewMockTokenManager(t)
+		mockTokenMgr.On("New").Return(token).Once()
+		mockTokenMgr.On("Generate", token, r, false).Return(nil).Once()
+		mockTokenMgr.On("Save", mock.Anything, token).Return(nil).Once()
+
+		processor := rfc7523.NewMockTokenProcessor(t)
+		processor.On("ProcessToken", r, token, mock.Anything).Return(nil).Once()
+
+		f := New(NewConfig().SetTokenManager(mockTokenMgr).RegisterExtension(processor))
+		rw := httptest.NewRecorder()
+		require.NoError(t, f.TokenResponse(r, rw))
+		assert.Equal(t, http.StatusOK, rw.Code)
+
+		var data map[string]interface{}
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &data))
+		assert.Equal(t, "issued", data["access_token"])
+		assert.Equal(t, "Bearer", data["token_type"])
+		assert.NotContains(t, data, "refresh_token")
+	})
+
+	t.Run("error_when_token_is_nil", func(t *testing.T) {
+		mockTokenMgr := rfc7523.NewMockTokenManager(t)
+		mockTokenMgr.On("New").Return(nil).Once()
+
+		f := New(NewConfig().SetTokenManager(mockTokenMgr))
+		err := f.TokenResponse(&requests.TokenRequest{Request: httptest.NewRequest(http.MethodPost, "/", nil)}, httptest.NewRecorder())
+		assert.ErrorIs(t, err, ErrNilToken)
+	})
+}
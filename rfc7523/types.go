@@ -0,0 +1,69 @@
+package rfc7523
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/tniah/authlib/models"
+	"github.com/tniah/authlib/requests"
+	"github.com/tniah/authlib/types"
+)
+
+// ClientManager authenticates the client at the token endpoint.
+type ClientManager interface {
+	// Authenticate verifies the client credentials and returns the authenticated
+	// client. endpointName identifies the endpoint being accessed (used for
+	// method-specific logic in multi-endpoint setups).
+	Authenticate(r *http.Request, authMethods map[types.ClientAuthMethod]bool, endpointName string) (models.Client, error)
+}
+
+// UserManager resolves the user an assertion was issued for.
+type UserManager interface {
+	// QueryUserByAssertion retrieves the user identified by subject, the sub
+	// claim of an assertion signed by issuer. The verified claims are
+	// available in r.AssertionClaims. Return (nil, nil) when no user matches.
+	QueryUserByAssertion(ctx context.Context, issuer, subject string, r *requests.TokenRequest) (models.User, error)
+}
+
+// TokenManager generates and persists the issued token.
+type TokenManager interface {
+	// New allocates a blank Token ready to be populated by Generate.
+	New() models.Token
+
+	// Generate populates token with a value, expiry, scopes, and client/user
+	// binding. includeRefreshToken is always false: the client can present a
+	// fresh assertion instead (RFC 7521 §4.1).
+	Generate(token models.Token, r *requests.TokenRequest, includeRefreshToken bool) error
+
+	// Save persists the token to the backing store.
+	Save(ctx context.Context, token models.Token) error
+}
+
+// IssuerKeyResolver returns the verification key and signing method for
+// assertions signed by issuer. keyID is the kid header of the assertion and
+// may be empty. The key is a PEM-encoded public key, or the shared secret for
+// HMAC methods. Return a nil key when issuer is not trusted.
+type IssuerKeyResolver func(ctx context.Context, issuer, keyID string) ([]byte, jwt.SigningMethod, error)
+
+// JWTIDCache remembers the jti of accepted assertions so that each assertion
+// can be used only once (RFC 7523 §3, item 7). utils.MemoryJWTIDCache
+// satisfies it.
+type JWTIDCache interface {
+	// Use records jti for issuer until expiresAt. It returns false when the
+	// pair has already been recorded and has not expired yet.
+	Use(ctx context.Context, issuer, jti string, expiresAt time.Time) (bool, error)
+}
+
+// TokenRequestValidator is an extension hook called during
+// ValidateTokenRequest, after the built-in checks pass.
+type TokenRequestValidator interface {
+	ValidateTokenRequest(r *requests.TokenRequest) error
+}
+
+// TokenProcessor is an extension hook called after the token is generated and
+// before it is saved. Use it to add extra fields to the token response.
+type TokenProcessor interface {
+	ProcessToken(r *requests.TokenRequest, token models.Token, data map[string]interface{}) error
+}
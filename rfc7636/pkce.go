@@ -25,6 +25,15 @@ func New(opts ...*Options) *ProofKeyForCodeExchangeFlow {
 	return &ProofKeyForCodeExchangeFlow{defaultOpts}
 }
 
+// ProvideMetadata adds the accepted code_challenge_method values to the
+// authorization server metadata (RFC 8414 §2).
+func (f *ProofKeyForCodeExchangeFlow) ProvideMetadata(md types.Metadata) {
+	md.Add(types.MetadataCodeChallengeMethodsSupported, types.CodeChallengeMethodS256.String())
+	if f.allowPlain {
+		md.Add(types.MetadataCodeChallengeMethodsSupported, types.CodeChallengeMethodPlain.String())
+	}
+}
+
 // ValidateAuthorizationRequest checks that the PKCE parameters in the
 // authorization request are well-formed. It is a no-op when neither
 // code_challenge nor code_challenge_method is present, unless PKCE is required
@@ -319,3 +319,13 @@ func TestProofKeyForCodeExchangeFlow_ProcessAuthorizationCode(t *testing.T) {
 		assert.Equal(t, types.CodeChallengeMethodPlain, authCode.GetCodeChallengeMethod())
 	})
 }
+
+func TestProofKeyForCodeExchangeFlow_ProvideMetadata(t *testing.T) {
+	md := types.Metadata{}
+	New().ProvideMetadata(md)
+	assert.Equal(t, []string{"S256", "plain"}, md[types.MetadataCodeChallengeMethodsSupported])
+
+	md = types.Metadata{}
+	New(NewOptions().SetAllowPlain(false)).ProvideMetadata(md)
+	assert.Equal(t, []string{"S256"}, md[types.MetadataCodeChallengeMethodsSupported])
+}
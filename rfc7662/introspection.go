@@ -5,6 +5,8 @@ import (
 	"time"
 
 	autherrors "github.com/tniah/authlib/errors"
+	"github.com/tniah/authlib/models"
+	"github.com/tniah/authlib/types"
 	"github.com/tniah/authlib/utils"
 )
 
@@ -41,6 +43,12 @@ func (f *TokenIntrospectionFlow) CheckEndpoint(name string) bool {
 	return name == f.endpointName
 }
 
+// ProvideMetadata adds the client authentication methods accepted at the
+// introspection endpoint to the authorization server metadata (RFC 8414 §2).
+func (f *TokenIntrospectionFlow) ProvideMetadata(md types.Metadata) {
+	md.AddClientAuthMethods(types.MetadataIntrospectionEndpointAuthMethodsSupported, f.supportedClientAuthMethods)
+}
+
 // EndpointResponse handles an introspection request. It authenticates the
 // caller, looks up the token, checks client permission, and writes the JSON
 // introspection payload (RFC 7662 §2.2) to rw.
@@ -107,6 +115,8 @@ func (f *TokenIntrospectionFlow) checkParams(r *Request) error {
 // introspectionPayload builds the RFC 7662 §2.2 response payload. Returns
 // {"active": false} when the token is not found or has expired. Otherwise,
 // delegates to TokenManager.Inspect for the full claim set and sets active=true.
+// The cnf confirmation stored in the token's extra data is added when Inspect
+// does not return one.
 func (f *TokenIntrospectionFlow) introspectionPayload(r *Request) map[string]interface{} {
 	inactive := map[string]interface{}{"active": false}
 
@@ -123,6 +133,16 @@ func (f *TokenIntrospectionFlow) introspectionPayload(r *Request) map[string]int
 		payload = make(map[string]interface{})
 	}
 
+	// RFC 8705 §3.2: expose the confirmation of sender-constrained tokens so
+	// the resource server can check the binding.
+	if _, ok := payload["cnf"]; !ok {
+		if ext, ok := r.Tok.(models.ExtendableToken); ok {
+			if cnf, ok := ext.GetExtraData()["cnf"]; ok {
+				payload["cnf"] = cnf
+			}
+		}
+	}
+
 	payload["active"] = true
 	return payload
 }
@@ -202,6 +202,25 @@ func TestTokenIntrospectionFlow_introspectionPayload(t *testing.T) {
 		mockTokenMgr.AssertExpectations(t)
 	})
 
+	t.Run("success_with_confirmation", func(t *testing.T) {
+		cnf := map[string]interface{}{"x5t#S256": "bwcK0esc3ACC3DB2Y5_lESsXE8o9ltc05O89jdN-dg2"}
+		mockToken := &sql.Token{
+			ClientID:             mockClient.ClientID,
+			IssuedAt:             time.Now().UTC().Round(time.Second),
+			AccessTokenExpiresIn: time.Hour,
+			Data:                 map[string]interface{}{"cnf": cnf},
+		}
+		mockTokenMgr.On("Inspect", mock.Anything, mock.Anything).Return(map[string]interface{}{"scope": "read"}).Once()
+
+		r := &Request{}
+		r.Tok = mockToken
+		r.Client = mockClient
+
+		payload := h.introspectionPayload(r)
+		assert.Equal(t, true, payload["active"])
+		assert.Equal(t, cnf, payload["cnf"])
+	})
+
 	t.Run("error_when_token_is_invalid", func(t *testing.T) {
 		r := &Request{}
 		r.Client = mockClient
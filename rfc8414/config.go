@@ -0,0 +1,152 @@
+package rfc8414
+
+import (
+	"errors"
+	"net/url"
+
+	"github.com/golang-jwt/jwt/v5"
+	autherrors "github.com/tniah/authlib/errors"
+	"github.com/tniah/authlib/utils"
+)
+
+const (
+	// EndpointNameAuthorizationServerMetadata is the default endpoint name
+	// used to register the metadata handler with the server.
+	EndpointNameAuthorizationServerMetadata = "authorization_server_metadata"
+	// WellKnownPath is the well-known URI suffix of the metadata
+	// (RFC 8414 §3).
+	WellKnownPath = "/.well-known/oauth-authorization-server"
+)
+
+var (
+	ErrEmptyEndpointName = errors.New("endpoint name is empty")
+	ErrNilMetadataSource = errors.New("metadata source is nil")
+	ErrInvalidIssuer     = errors.New("issuer must be an https URL without query or fragment")
+)
+
+// Config holds all settings for Flow. Use NewConfig to obtain a value with
+// defaults, then chain Set* calls to configure the issuer and endpoint URLs.
+type Config struct {
+	endpointName string
+	issuer       string
+	source       MetadataSource
+
+	// endpoints maps metadata fields (e.g. token_endpoint) to the URLs the
+	// application serves the endpoints at.
+	endpoints map[string]string
+
+	// fields are added as-is, after the generated ones.
+	fields map[string]interface{}
+
+	// Extension slices are executed in registration order.
+	processors []MetadataProcessor
+
+	// signingKey, when set, signs the metadata values into signed_metadata
+	// (RFC 8414 §2.1).
+	signingKey       []byte
+	signingKeyMethod jwt.SigningMethod
+	signingKeyID     string
+}
+
+// NewConfig returns a Config with EndpointNameAuthorizationServerMetadata as
+// the endpoint name and unsigned metadata.
+func NewConfig() *Config {
+	return &Config{
+		endpointName: EndpointNameAuthorizationServerMetadata,
+		endpoints:    map[string]string{},
+		fields:       map[string]interface{}{},
+		processors:   []MetadataProcessor{},
+	}
+}
+
+// SetEndpointName overrides the endpoint name used by CheckEndpoint. Defaults
+// to EndpointNameAuthorizationServerMetadata.
+func (cfg *Config) SetEndpointName(name string) *Config {
+	cfg.endpointName = name
+	return cfg
+}
+
+// SetIssuer sets the issuer identifier of the authorization server: an https
+// URL without query or fragment (RFC 8414 §2).
+func (cfg *Config) SetIssuer(iss string) *Config {
+	cfg.issuer = iss
+	return cfg
+}
+
+// SetMetadataSource sets the source of the generated metadata, typically the
+// *authlib.Server the grants and endpoints are registered on.
+func (cfg *Config) SetMetadataSource(src MetadataSource) *Config {
+	cfg.source = src
+	return cfg
+}
+
+// SetEndpoint sets the URL of an endpoint, keyed by its metadata field, e.g.
+// SetEndpoint(types.MetadataTokenEndpoint, "https://as.example.com/token").
+func (cfg *Config) SetEndpoint(field, uri string) *Config {
+	cfg.endpoints[field] = uri
+	return cfg
+}
+
+// SetField sets a metadata field, such as scopes_supported or
+// service_documentation. It takes precedence over the generated value of the
+// same field.
+func (cfg *Config) SetField(field string, value interface{}) *Config {
+	cfg.fields[field] = value
+	return cfg
+}
+
+// SetSigningKey sets the signing key, method, and optional key ID used to
+// publish signed_metadata (RFC 8414 §2.1). Metadata is not signed without a
+// key.
+func (cfg *Config) SetSigningKey(key []byte, method jwt.SigningMethod, keyID ...string) *Config {
+	cfg.signingKey = key
+	cfg.signingKeyMethod = method
+
+	if len(keyID) > 0 {
+		cfg.signingKeyID = keyID[0]
+	}
+
+	return cfg
+}
+
+// RegisterExtension adds ext to every extension slice whose interface it
+// satisfies.
+func (cfg *Config) RegisterExtension(ext interface{}) *Config {
+	if h, ok := ext.(MetadataProcessor); ok {
+		cfg.processors = append(cfg.processors, h)
+	}
+
+	return cfg
+}
+
+// ValidateConfig returns an error if any required configuration is missing.
+// Call this via Must rather than directly.
+func (cfg *Config) ValidateConfig() error {
+	if cfg.endpointName == "" {
+		return ErrEmptyEndpointName
+	}
+
+	if cfg.issuer == "" {
+		return autherrors.ErrMissingIssuer
+	}
+
+	if u, err := url.Parse(cfg.issuer); err != nil || u.Scheme != "https" || u.Host == "" || u.RawQuery != "" || u.Fragment != "" {
+		return ErrInvalidIssuer
+	}
+
+	if utils.IsNil(cfg.source) {
+		return ErrNilMetadataSource
+	}
+
+	if cfg.signingKey != nil {
+		if cfg.signingKeyMethod == nil {
+			return autherrors.ErrMissingSigningKeyMethod
+		}
+
+		if cfg.signingKeyMethod == jwt.SigningMethodNone {
+			return autherrors.ErrInsecureSigningMethod
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package rfc8414
+
+import (
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	autherrors "github.com/tniah/authlib/errors"
+	mock "github.com/tniah/authlib/mocks/rfc8414"
+	"github.com/tniah/authlib/types"
+)
+
+func TestConfig(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		cfg := NewConfig()
+		assert.Equal(t, EndpointNameAuthorizationServerMetadata, cfg.endpointName)
+		assert.Empty(t, cfg.endpoints)
+		assert.Empty(t, cfg.fields)
+		assert.Empty(t, cfg.processors)
+		assert.Nil(t, cfg.signingKey)
+
+		source := mock.NewMockMetadataSource(t)
+		cfg.SetEndpointName("metadata").
+			SetIssuer("https://as.example.com").
+			SetMetadataSource(source).
+			SetEndpoint(types.MetadataTokenEndpoint, "https://as.example.com/token").
+			SetField(types.MetadataScopesSupported, []string{"openid"}).
+			SetSigningKey([]byte("secret"), jwt.SigningMethodHS256, "kid-1").
+			RegisterExtension(mock.NewMockMetadataProcessor(t)).
+			RegisterExtension(struct{}{})
+
+		assert.Equal(t, "metadata", cfg.endpointName)
+		assert.Equal(t, "https://as.example.com", cfg.issuer)
+		assert.Same(t, source, cfg.source)
+		assert.Equal(t, map[string]string{types.MetadataTokenEndpoint: "https://as.example.com/token"}, cfg.endpoints)
+		assert.Equal(t, map[string]interface{}{types.MetadataScopesSupported: []string{"openid"}}, cfg.fields)
+		assert.Equal(t, []byte("secret"), cfg.signingKey)
+		assert.Equal(t, jwt.SigningMethodHS256, cfg.signingKeyMethod)
+		assert.Equal(t, "kid-1", cfg.signingKeyID)
+		assert.Len(t, cfg.processors, 1)
+		assert.NoError(t, cfg.ValidateConfig())
+	})
+
+	t.Run("error", func(t *testing.T) {
+		cfg := NewConfig().SetEndpointName("")
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrEmptyEndpointName)
+
+		cfg.SetEndpointName(EndpointNameAuthorizationServerMetadata)
+		assert.ErrorIs(t, cfg.ValidateConfig(), autherrors.ErrMissingIssuer)
+
+		for _, iss := range []string{
+			"http://as.example.com",
+			"https://",
+			"https://as.example.com?tenant=1",
+			"https://as.example.com#main",
+			"://as.example.com",
+		} {
+			cfg.SetIssuer(iss)
+			assert.ErrorIs(t, cfg.ValidateConfig(), ErrInvalidIssuer, iss)
+		}
+
+		cfg.SetIssuer("https://as.example.com/tenant")
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrNilMetadataSource)
+
+		cfg.SetMetadataSource(mock.NewMockMetadataSource(t))
+		assert.NoError(t, cfg.ValidateConfig())
+
+		cfg.SetSigningKey([]byte("secret"), nil)
+		assert.ErrorIs(t, cfg.ValidateConfig(), autherrors.ErrMissingSigningKeyMethod)
+
+		cfg.SetSigningKey([]byte("secret"), jwt.SigningMethodNone)
+		assert.ErrorIs(t, cfg.ValidateConfig(), autherrors.ErrInsecureSigningMethod)
+	})
+}
@@ -0,0 +1,144 @@
+package rfc8414
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	autherrors "github.com/tniah/authlib/errors"
+	"github.com/tniah/authlib/types"
+	"github.com/tniah/authlib/utils"
+)
+
+// endpointFields maps the metadata fields describing an endpoint to the field
+// of its URL. They are left out while the URL is not set.
+var endpointFields = map[string]string{
+	types.MetadataIntrospectionEndpointAuthMethodsSupported: types.MetadataIntrospectionEndpoint,
+	types.MetadataRevocationEndpointAuthMethodsSupported:    types.MetadataRevocationEndpoint,
+	types.MetadataRequirePushedAuthorizationRequests:        types.MetadataPushedAuthorizationRequestEndpoint,
+	types.MetadataUserInfoSigningAlgValuesSupported:         types.MetadataUserInfoEndpoint,
+}
+
+// AuthorizationServerMetadataFlow implements the RFC 8414 authorization
+// server metadata endpoint. It is registered as an endpoint on the server via
+// Server.RegisterEndpoint and dispatched by Server.EndpointResponse when the
+// endpoint name matches.
+type AuthorizationServerMetadataFlow struct {
+	*Config
+}
+
+// NewAuthorizationServerMetadataFlow creates an
+// AuthorizationServerMetadataFlow from cfg without validating it. Prefer
+// MustAuthorizationServerMetadataFlow for production use.
+func NewAuthorizationServerMetadataFlow(cfg *Config) *AuthorizationServerMetadataFlow {
+	return &AuthorizationServerMetadataFlow{cfg}
+}
+
+// MustAuthorizationServerMetadataFlow creates an
+// AuthorizationServerMetadataFlow after validating cfg. Returns an error if
+// any required configuration is missing.
+func MustAuthorizationServerMetadataFlow(cfg *Config) (*AuthorizationServerMetadataFlow, error) {
+	if err := cfg.ValidateConfig(); err != nil {
+		return nil, err
+	}
+
+	return NewAuthorizationServerMetadataFlow(cfg), nil
+}
+
+// CheckEndpoint reports whether name matches the configured endpoint name.
+// The server calls this to route requests to the correct registered endpoint.
+func (f *AuthorizationServerMetadataFlow) CheckEndpoint(name string) bool {
+	if f.endpointName == "" {
+		return false
+	}
+
+	return name == f.endpointName
+}
+
+// WellKnownURL returns the URL the metadata must be served at: WellKnownPath
+// inserted between the host and the path of the issuer (RFC 8414 §3.1).
+func (f *AuthorizationServerMetadataFlow) WellKnownURL() string {
+	u, err := url.Parse(f.issuer)
+	if err != nil {
+		return ""
+	}
+
+	u.Path = WellKnownPath + strings.TrimSuffix(u.Path, "/")
+	u.RawPath = ""
+	return u.String()
+}
+
+// EndpointResponse handles a metadata request and writes the metadata as a
+// JSON object (RFC 8414 §3.2).
+func (f *AuthorizationServerMetadataFlow) EndpointResponse(r *http.Request, rw http.ResponseWriter) error {
+	if r.Method != http.MethodGet {
+		return autherrors.InvalidRequestError().WithDescription(fmt.Sprintf("unsupported http method \"%s\"", r.Method))
+	}
+
+	md, err := f.Metadata(r)
+	if err != nil {
+		return err
+	}
+
+	return utils.JSONResponse(rw, md, http.StatusOK)
+}
+
+// Metadata builds the metadata served for r: the metadata provided by the
+// MetadataSource, the issuer, the configured endpoint URLs and fields, and
+// the changes of registered MetadataProcessor extensions. Fields describing an
+// endpoint whose URL is not set are left out. With a signing key, the result
+// also carries signed_metadata.
+func (f *AuthorizationServerMetadataFlow) Metadata(r *http.Request) (types.Metadata, error) {
+	md := f.source.Metadata()
+	md.Set(types.MetadataIssuer, f.issuer)
+
+	for field, uri := range f.endpoints {
+		md.Set(field, uri)
+	}
+
+	for field, endpoint := range endpointFields {
+		if _, ok := md[endpoint]; !ok {
+			delete(md, field)
+		}
+	}
+
+	for field, value := range f.fields {
+		md.Set(field, value)
+	}
+
+	for _, h := range f.processors {
+		if err := h.ProcessMetadata(r, md); err != nil {
+			return nil, err
+		}
+	}
+
+	if f.signingKey != nil {
+		signed, err := f.sign(md)
+		if err != nil {
+			return nil, err
+		}
+
+		md.Set(types.MetadataSignedMetadata, signed)
+	}
+
+	return md, nil
+}
+
+// sign returns a JWT carrying the metadata values as claims, including iss
+// (RFC 8414 §2.1).
+func (f *AuthorizationServerMetadataFlow) sign(md types.Metadata) (string, error) {
+	token, err := utils.NewJWTToken(f.signingKey, f.signingKeyMethod, f.signingKeyID)
+	if err != nil {
+		return "", err
+	}
+
+	claims := utils.JWTClaim{"iss": f.issuer}
+	for field, value := range md {
+		if field != types.MetadataSignedMetadata {
+			claims[field] = value
+		}
+	}
+
+	return token.Generate(claims, nil)
+}
@@ -0,0 +1,177 @@
+package rfc8414
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/tniah/authlib"
+	autherrors "github.com/tniah/authlib/errors"
+	rfc8414mock "github.com/tniah/authlib/mocks/rfc8414"
+	authorizationcode "github.com/tniah/authlib/rfc6749/authorization_code"
+	"github.com/tniah/authlib/rfc7009"
+	"github.com/tniah/authlib/rfc7636"
+	"github.com/tniah/authlib/rfc7662"
+	"github.com/tniah/authlib/types"
+)
+
+func newMetadataFlow(t *testing.T, md types.Metadata) *AuthorizationServerMetadataFlow {
+	source := rfc8414mock.NewMockMetadataSource(t)
+	source.On("Metadata").Return(md).Maybe()
+
+	return NewAuthorizationServerMetadataFlow(NewConfig().
+		SetIssuer("https://as.example.com").
+		SetMetadataSource(source))
+}
+
+func TestAuthorizationServerMetadataFlow_CheckEndpoint(t *testing.T) {
+	f := NewAuthorizationServerMetadataFlow(NewConfig())
+	assert.True(t, f.CheckEndpoint(EndpointNameAuthorizationServerMetadata))
+	assert.False(t, f.CheckEndpoint("introspection"))
+
+	f.SetEndpointName("")
+	assert.False(t, f.CheckEndpoint(""))
+}
+
+func TestAuthorizationServerMetadataFlow_WellKnownURL(t *testing.T) {
+	f := NewAuthorizationServerMetadataFlow(NewConfig().SetIssuer("https://as.example.com"))
+	assert.Equal(t, "https://as.example.com/.well-known/oauth-authorization-server", f.WellKnownURL())
+
+	f.SetIssuer("https://as.example.com/tenant/1/")
+	assert.Equal(t, "https://as.example.com/.well-known/oauth-authorization-server/tenant/1", f.WellKnownURL())
+}
+
+func TestAuthorizationServerMetadataFlow_Metadata(t *testing.T) {
+	hr := httptest.NewRequest(http.MethodGet, WellKnownPath, nil)
+
+	t.Run("generated_and_configured_fields", func(t *testing.T) {
+		f := newMetadataFlow(t, types.Metadata{
+			types.MetadataGrantTypesSupported:                       []string{"authorization_code"},
+			types.MetadataScopesSupported:                           []string{"read"},
+			types.MetadataIntrospectionEndpointAuthMethodsSupported: []string{"client_secret_basic"},
+			types.MetadataRevocationEndpointAuthMethodsSupported:    []string{"client_secret_basic"},
+			types.MetadataRequirePushedAuthorizationRequests:        false,
+		})
+		f.SetEndpoint(types.MetadataTokenEndpoint, "https://as.example.com/token").
+			SetEndpoint(types.MetadataIntrospectionEndpoint, "https://as.example.com/introspect").
+			SetField(types.MetadataScopesSupported, []string{"openid", "profile"}).
+			SetField("custom_field", "value")
+
+		md, err := f.Metadata(hr)
+		require.NoError(t, err)
+		assert.Equal(t, types.Metadata{
+			types.MetadataIssuer:                                    "https://as.example.com",
+			types.MetadataTokenEndpoint:                             "https://as.example.com/token",
+			types.MetadataIntrospectionEndpoint:                     "https://as.example.com/introspect",
+			types.MetadataIntrospectionEndpointAuthMethodsSupported: []string{"client_secret_basic"},
+			types.MetadataGrantTypesSupported:                       []string{"authorization_code"},
+			types.MetadataScopesSupported:                           []string{"openid", "profile"},
+			"custom_field":                                          "value",
+		}, md)
+	})
+
+	t.Run("runs_processors", func(t *testing.T) {
+		processor := rfc8414mock.NewMockMetadataProcessor(t)
+		processor.On("ProcessMetadata", hr, mock.Anything).Run(func(args mock.Arguments) {
+			args.Get(1).(types.Metadata).Set(types.MetadataUILocalesSupported, []string{"en", "vi"})
+		}).Return(nil).Once()
+
+		f := newMetadataFlow(t, types.Metadata{})
+		f.RegisterExtension(processor)
+
+		md, err := f.Metadata(hr)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"en", "vi"}, md[types.MetadataUILocalesSupported])
+	})
+
+	t.Run("signs_metadata", func(t *testing.T) {
+		f := newMetadataFlow(t, types.Metadata{
+			types.MetadataGrantTypesSupported: []string{"authorization_code"},
+		})
+		f.SetSigningKey([]byte("secret"), jwt.SigningMethodHS256, "kid-1")
+
+		md, err := f.Metadata(hr)
+		require.NoError(t, err)
+
+		claims := jwt.MapClaims{}
+		token, err := jwt.ParseWithClaims(md[types.MetadataSignedMetadata].(string), claims, func(*jwt.Token) (interface{}, error) {
+			return []byte("secret"), nil
+		}, jwt.WithValidMethods([]string{"HS256"}))
+		require.NoError(t, err)
+		assert.Equal(t, "kid-1", token.Header["kid"])
+		assert.Equal(t, "https://as.example.com", claims["iss"])
+		assert.Equal(t, []interface{}{"authorization_code"}, claims[types.MetadataGrantTypesSupported])
+		assert.NotContains(t, claims, types.MetadataSignedMetadata)
+	})
+
+	t.Run("error_when_processor_fails", func(t *testing.T) {
+		processor := rfc8414mock.NewMockMetadataProcessor(t)
+		processor.On("ProcessMetadata", hr, mock.Anything).Return(errors.New("processor error")).Once()
+
+		f := newMetadataFlow(t, types.Metadata{})
+		f.RegisterExtension(processor)
+
+		_, err := f.Metadata(hr)
+		assert.ErrorContains(t, err, "processor error")
+	})
+
+	t.Run("error_when_signing_key_is_invalid", func(t *testing.T) {
+		f := newMetadataFlow(t, types.Metadata{})
+		f.SetSigningKey([]byte("not a key"), jwt.SigningMethodES256)
+
+		_, err := f.Metadata(hr)
+		assert.Error(t, err)
+	})
+}
+
+func TestAuthorizationServerMetadataFlow_EndpointResponse(t *testing.T) {
+	srv := authlib.NewServer()
+	srv.RegisterGrant(authorizationcode.New(authorizationcode.NewConfig().
+		RegisterExtension(rfc7636.New(rfc7636.NewOptions().SetAllowPlain(false)))))
+	srv.RegisterEndpoint(rfc7662.NewTokenIntrospectionFlow(rfc7662.NewConfig()))
+	srv.RegisterEndpoint(rfc7009.NewTokenRevocationFlow(rfc7009.NewConfig()))
+
+	f, err := MustAuthorizationServerMetadataFlow(NewConfig().
+		SetIssuer("https://as.example.com").
+		SetMetadataSource(srv).
+		SetEndpoint(types.MetadataAuthorizationEndpoint, "https://as.example.com/authorize").
+		SetEndpoint(types.MetadataTokenEndpoint, "https://as.example.com/token").
+		SetEndpoint(types.MetadataIntrospectionEndpoint, "https://as.example.com/introspect"))
+	require.NoError(t, err)
+	srv.RegisterEndpoint(f)
+
+	t.Run("success", func(t *testing.T) {
+		hr := httptest.NewRequest(http.MethodGet, WellKnownPath, nil)
+		rw := httptest.NewRecorder()
+		require.NoError(t, srv.EndpointResponse(hr, rw, EndpointNameAuthorizationServerMetadata))
+		assert.Equal(t, http.StatusOK, rw.Code)
+		assert.Contains(t, rw.Header().Get("Content-Type"), "application/json")
+
+		var body map[string]interface{}
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &body))
+		assert.Equal(t, map[string]interface{}{
+			"issuer":                                        "https://as.example.com",
+			"authorization_endpoint":                        "https://as.example.com/authorize",
+			"token_endpoint":                                "https://as.example.com/token",
+			"introspection_endpoint":                        "https://as.example.com/introspect",
+			"response_types_supported":                      []interface{}{"code"},
+			"response_modes_supported":                      []interface{}{"query", "fragment", "form_post"},
+			"grant_types_supported":                         []interface{}{"authorization_code"},
+			"token_endpoint_auth_methods_supported":         []interface{}{"client_secret_basic", "none"},
+			"code_challenge_methods_supported":              []interface{}{"S256"},
+			"introspection_endpoint_auth_methods_supported": []interface{}{"client_secret_basic"},
+		}, body)
+	})
+
+	t.Run("error_when_method_is_not_get", func(t *testing.T) {
+		hr := httptest.NewRequest(http.MethodPost, WellKnownPath, nil)
+		err := f.EndpointResponse(hr, httptest.NewRecorder())
+		assert.Equal(t, autherrors.ErrInvalidRequest, autherrors.ToAuthLibError(err).Code)
+	})
+}
@@ -0,0 +1,24 @@
+package rfc8414
+
+import (
+	"net/http"
+
+	"github.com/tniah/authlib/types"
+)
+
+// MetadataSource provides the metadata of the grants, endpoints and
+// extensions registered on the authorization server. *authlib.Server
+// implements it.
+type MetadataSource interface {
+	// Metadata returns a new metadata set on every call; the endpoint adds
+	// its own fields to it.
+	Metadata() types.Metadata
+}
+
+// MetadataProcessor is an extension hook called on every metadata request,
+// after the generated and configured fields are set and before the metadata
+// is signed. Use it to add fields that change at runtime, or to remove
+// generated ones.
+type MetadataProcessor interface {
+	ProcessMetadata(r *http.Request, md types.Metadata) error
+}
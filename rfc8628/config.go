@@ -0,0 +1,246 @@
+package rfc8628
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/tniah/authlib/types"
+	"github.com/tniah/authlib/utils"
+)
+
+// EndpointNameDeviceAuthorization is the default endpoint name used to
+// register the device authorization handler with the server.
+const EndpointNameDeviceAuthorization = "device_authorization"
+
+// Defaults applied by NewConfig.
+const (
+	DefaultExpiresIn        = 10 * time.Minute
+	DefaultInterval         = 5 * time.Second
+	DefaultDeviceCodeLength = 48
+)
+
+// Sentinel errors returned by ValidateConfig when a required dependency is missing.
+var (
+	ErrEmptyEndpointName      = errors.New("endpoint name is empty")
+	ErrNilClientManager       = errors.New("client manager is nil")
+	ErrNilDeviceCodeManager   = errors.New("device code manager is nil")
+	ErrNilUserManager         = errors.New("user manager is nil")
+	ErrNilTokenManager        = errors.New("token manager is nil")
+	ErrEmptyClientAuthMethods = errors.New("client auth methods are empty")
+	ErrEmptyVerificationURI   = errors.New("verification uri is empty")
+	ErrNilUserCodeGenerator   = errors.New("user code generator is nil")
+	ErrNilAttemptLimiter      = errors.New("attempt limiter is nil")
+	ErrInvalidExpiresIn       = errors.New("expires in must be positive")
+	ErrInvalidInterval        = errors.New("interval must be positive")
+)
+
+// Config holds all dependencies and extension hooks for the Device
+// Authorization Grant. The same Config is shared by DeviceAuthorizationFlow,
+// Flow, and Verifier. Use NewConfig() to get a config with sensible defaults,
+// then chain Set*/RegisterExtension calls before passing it to the
+// constructors.
+type Config struct {
+	endpointName  string
+	clientMgr     ClientManager
+	deviceCodeMgr DeviceCodeManager
+	userMgr       UserManager
+	tokenMgr      TokenManager
+
+	// verificationURI is the end-user verification page on the authorization
+	// server (RFC 8628 §3.2).
+	verificationURI string
+
+	expiresIn        time.Duration
+	interval         time.Duration
+	deviceCodeLength int
+	userCodeGen      UserCodeGenerator
+	attemptLimiter   AttemptLimiter
+
+	tokenEndpointHttpMethods []string
+
+	// Extension slices are executed in registration order.
+	tokenReqValidators []TokenRequestValidator
+	tokenProcessors    []TokenProcessor
+
+	// supportedClientAuthMethods controls which authentication methods are
+	// accepted at the device authorization and token endpoints.
+	supportedClientAuthMethods map[types.ClientAuthMethod]bool
+}
+
+// NewConfig returns a Config with secure defaults:
+//   - EndpointNameDeviceAuthorization as the endpoint name.
+//   - Device codes expire after 10 minutes; clients poll every 5 seconds.
+//   - 48-character device codes and 8-character consonant user codes.
+//   - At most 5 failed user_code entries per end-user within 15 minutes.
+//   - Accepts POST on /token.
+//   - Supports basic and none client authentication methods.
+func NewConfig() *Config {
+	return &Config{
+		endpointName:     EndpointNameDeviceAuthorization,
+		expiresIn:        DefaultExpiresIn,
+		interval:         DefaultInterval,
+		deviceCodeLength: DefaultDeviceCodeLength,
+		userCodeGen:      GenerateUserCode,
+		attemptLimiter:   NewMemoryAttemptLimiter(DefaultMaxAttempts, DefaultAttemptWindow),
+		supportedClientAuthMethods: map[types.ClientAuthMethod]bool{
+			types.ClientBasicAuthentication: true,
+			types.ClientNoneAuthentication:  true,
+		},
+		tokenEndpointHttpMethods: []string{http.MethodPost},
+		tokenReqValidators:       []TokenRequestValidator{},
+		tokenProcessors:          []TokenProcessor{},
+	}
+}
+
+// SetEndpointName overrides the endpoint name used by CheckEndpoint. Defaults
+// to EndpointNameDeviceAuthorization ("device_authorization").
+func (cfg *Config) SetEndpointName(name string) *Config {
+	cfg.endpointName = name
+	return cfg
+}
+
+// SetClientManager sets the client authentication manager.
+func (cfg *Config) SetClientManager(mgr ClientManager) *Config {
+	cfg.clientMgr = mgr
+	return cfg
+}
+
+// SetDeviceCodeManager sets the manager used to persist device authorization
+// requests.
+func (cfg *Config) SetDeviceCodeManager(mgr DeviceCodeManager) *Config {
+	cfg.deviceCodeMgr = mgr
+	return cfg
+}
+
+// SetUserManager sets the user resolver used to look up the end-user who
+// approved the request.
+func (cfg *Config) SetUserManager(mgr UserManager) *Config {
+	cfg.userMgr = mgr
+	return cfg
+}
+
+// SetTokenManager sets the token generation and persistence manager.
+func (cfg *Config) SetTokenManager(mgr TokenManager) *Config {
+	cfg.tokenMgr = mgr
+	return cfg
+}
+
+// SetVerificationURI sets the absolute URL of the end-user verification page,
+// returned to the device as verification_uri. Required.
+func (cfg *Config) SetVerificationURI(uri string) *Config {
+	cfg.verificationURI = uri
+	return cfg
+}
+
+// SetExpiresIn overrides the lifetime of device and user codes. Default: 10 minutes.
+func (cfg *Config) SetExpiresIn(d time.Duration) *Config {
+	cfg.expiresIn = d
+	return cfg
+}
+
+// SetInterval overrides the minimum time the device must wait between polling
+// requests. Default: 5 seconds.
+func (cfg *Config) SetInterval(d time.Duration) *Config {
+	cfg.interval = d
+	return cfg
+}
+
+// SetDeviceCodeLength overrides the length of generated device codes. Default: 48.
+func (cfg *Config) SetDeviceCodeLength(l int) *Config {
+	cfg.deviceCodeLength = l
+	return cfg
+}
+
+// SetUserCodeGenerator overrides the user_code generator. Default: GenerateUserCode.
+func (cfg *Config) SetUserCodeGenerator(gen UserCodeGenerator) *Config {
+	cfg.userCodeGen = gen
+	return cfg
+}
+
+// SetAttemptLimiter overrides the limiter guarding user_code entry. Default:
+// an in-memory limiter allowing 5 failures per end-user within 15 minutes.
+func (cfg *Config) SetAttemptLimiter(limiter AttemptLimiter) *Config {
+	cfg.attemptLimiter = limiter
+	return cfg
+}
+
+// SetTokenEndpointHttpMethods overrides the HTTP methods accepted at /token.
+// Default: [POST].
+func (cfg *Config) SetTokenEndpointHttpMethods(methods []string) *Config {
+	cfg.tokenEndpointHttpMethods = methods
+	return cfg
+}
+
+// SetSupportedClientAuthMethods overrides which client authentication methods
+// are accepted at the device authorization and token endpoints. Default: basic
+// and none.
+func (cfg *Config) SetSupportedClientAuthMethods(methods map[types.ClientAuthMethod]bool) *Config {
+	cfg.supportedClientAuthMethods = methods
+	return cfg
+}
+
+// RegisterExtension adds ext to every extension slice whose interface it satisfies.
+// A single object may implement both TokenRequestValidator and TokenProcessor.
+func (cfg *Config) RegisterExtension(ext interface{}) *Config {
+	if h, ok := ext.(TokenRequestValidator); ok {
+		cfg.tokenReqValidators = append(cfg.tokenReqValidators, h)
+	}
+
+	if h, ok := ext.(TokenProcessor); ok {
+		cfg.tokenProcessors = append(cfg.tokenProcessors, h)
+	}
+
+	return cfg
+}
+
+// ValidateConfig checks that all required dependencies are set and returns the
+// first sentinel error encountered. Call this via the Must* constructors
+// rather than directly.
+func (cfg *Config) ValidateConfig() error {
+	if cfg.endpointName == "" {
+		return ErrEmptyEndpointName
+	}
+
+	if utils.IsNil(cfg.clientMgr) {
+		return ErrNilClientManager
+	}
+
+	if utils.IsNil(cfg.deviceCodeMgr) {
+		return ErrNilDeviceCodeManager
+	}
+
+	if utils.IsNil(cfg.userMgr) {
+		return ErrNilUserManager
+	}
+
+	if utils.IsNil(cfg.tokenMgr) {
+		return ErrNilTokenManager
+	}
+
+	if len(cfg.supportedClientAuthMethods) == 0 {
+		return ErrEmptyClientAuthMethods
+	}
+
+	if cfg.verificationURI == "" {
+		return ErrEmptyVerificationURI
+	}
+
+	if cfg.userCodeGen == nil {
+		return ErrNilUserCodeGenerator
+	}
+
+	if utils.IsNil(cfg.attemptLimiter) {
+		return ErrNilAttemptLimiter
+	}
+
+	if cfg.expiresIn <= 0 {
+		return ErrInvalidExpiresIn
+	}
+
+	if cfg.interval <= 0 {
+		return ErrInvalidInterval
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package rfc8628
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	mock "github.com/tniah/authlib/mocks/rfc8628"
+	"github.com/tniah/authlib/types"
+)
+
+func TestConfig(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		cfg := NewConfig()
+		assert.Equal(t, EndpointNameDeviceAuthorization, cfg.endpointName)
+		assert.Equal(t, DefaultExpiresIn, cfg.expiresIn)
+		assert.Equal(t, DefaultInterval, cfg.interval)
+		assert.Equal(t, DefaultDeviceCodeLength, cfg.deviceCodeLength)
+		assert.NotNil(t, cfg.userCodeGen)
+		assert.NotNil(t, cfg.attemptLimiter)
+		assert.Equal(t, []string{http.MethodPost}, cfg.tokenEndpointHttpMethods)
+		assert.Equal(t, map[types.ClientAuthMethod]bool{
+			types.ClientBasicAuthentication: true,
+			types.ClientNoneAuthentication:  true,
+		}, cfg.supportedClientAuthMethods)
+
+		clientMgr := mock.NewMockClientManager(t)
+		deviceCodeMgr := mock.NewMockDeviceCodeManager(t)
+		userMgr := mock.NewMockUserManager(t)
+		tokenMgr := mock.NewMockTokenManager(t)
+		limiter := mock.NewMockAttemptLimiter(t)
+
+		cfg.SetEndpointName("device").
+			SetClientManager(clientMgr).
+			SetDeviceCodeManager(deviceCodeMgr).
+			SetUserManager(userMgr).
+			SetTokenManager(tokenMgr).
+			SetVerificationURI("https://example.com/device").
+			SetExpiresIn(time.Minute).
+			SetInterval(time.Second).
+			SetDeviceCodeLength(32).
+			SetAttemptLimiter(limiter).
+			SetTokenEndpointHttpMethods([]string{http.MethodPost, http.MethodPut}).
+			SetSupportedClientAuthMethods(map[types.ClientAuthMethod]bool{types.ClientPostAuthentication: true})
+
+		assert.Equal(t, "device", cfg.endpointName)
+		assert.Equal(t, clientMgr, cfg.clientMgr)
+		assert.Equal(t, deviceCodeMgr, cfg.deviceCodeMgr)
+		assert.Equal(t, userMgr, cfg.userMgr)
+		assert.Equal(t, tokenMgr, cfg.tokenMgr)
+		assert.Equal(t, "https://example.com/device", cfg.verificationURI)
+		assert.Equal(t, time.Minute, cfg.expiresIn)
+		assert.Equal(t, time.Second, cfg.interval)
+		assert.Equal(t, 32, cfg.deviceCodeLength)
+		assert.Equal(t, limiter, cfg.attemptLimiter)
+		assert.Equal(t, []string{http.MethodPost, http.MethodPut}, cfg.tokenEndpointHttpMethods)
+		assert.Equal(t, map[types.ClientAuthMethod]bool{types.ClientPostAuthentication: true}, cfg.supportedClientAuthMethods)
+		assert.NoError(t, cfg.ValidateConfig())
+	})
+
+	t.Run("register_extension", func(t *testing.T) {
+		cfg := NewConfig()
+		validator := mock.NewMockTokenRequestValidator(t)
+		processor := mock.NewMockTokenProcessor(t)
+		cfg.RegisterExtension(validator).RegisterExtension(processor)
+		assert.Equal(t, []TokenRequestValidator{validator}, cfg.tokenReqValidators)
+		assert.Equal(t, []TokenProcessor{processor}, cfg.tokenProcessors)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		cfg := NewConfig().SetEndpointName("")
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrEmptyEndpointName)
+
+		cfg.SetEndpointName(EndpointNameDeviceAuthorization)
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrNilClientManager)
+
+		cfg.SetClientManager(mock.NewMockClientManager(t))
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrNilDeviceCodeManager)
+
+		cfg.SetDeviceCodeManager(mock.NewMockDeviceCodeManager(t))
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrNilUserManager)
+
+		cfg.SetUserManager(mock.NewMockUserManager(t))
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrNilTokenManager)
+
+		cfg.SetTokenManager(mock.NewMockTokenManager(t))
+		cfg.SetSupportedClientAuthMethods(nil)
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrEmptyClientAuthMethods)
+
+		cfg.SetSupportedClientAuthMethods(map[types.ClientAuthMethod]bool{types.ClientNoneAuthentication: true})
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrEmptyVerificationURI)
+
+		cfg.SetVerificationURI("https://example.com/device")
+		cfg.SetUserCodeGenerator(nil)
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrNilUserCodeGenerator)
+
+		cfg.SetUserCodeGenerator(GenerateUserCode)
+		cfg.SetAttemptLimiter(nil)
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrNilAttemptLimiter)
+
+		cfg.SetAttemptLimiter(NewMemoryAttemptLimiter(1, time.Minute))
+		cfg.SetExpiresIn(0)
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrInvalidExpiresIn)
+
+		cfg.SetExpiresIn(time.Minute)
+		cfg.SetInterval(-time.Second)
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrInvalidInterval)
+
+		cfg.SetInterval(time.Second)
+		assert.NoError(t, cfg.ValidateConfig())
+	})
+}
@@ -0,0 +1,213 @@
+package rfc8628
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	autherrors "github.com/tniah/authlib/errors"
+	"github.com/tniah/authlib/models"
+	"github.com/tniah/authlib/types"
+	"github.com/tniah/authlib/utils"
+)
+
+// maxUserCodeAttempts bounds how many user codes are generated before giving
+// up on finding one that is not already in use.
+const maxUserCodeAttempts = 5
+
+var (
+	// ErrNilDeviceCode is returned when DeviceCodeManager.New returns nil.
+	ErrNilDeviceCode = errors.New("device code is nil")
+	// ErrUserCodeCollision is returned when every generated user code is
+	// already in use.
+	ErrUserCodeCollision = errors.New("could not generate a unique user code")
+)
+
+// DeviceAuthorizationFlow implements the device authorization endpoint
+// (RFC 8628 §3.1-§3.2). It is registered as an endpoint on the server via
+// Server.RegisterEndpoint and dispatched by Server.EndpointResponse when the
+// endpoint name matches.
+type DeviceAuthorizationFlow struct {
+	*Config
+}
+
+// NewDeviceAuthorizationFlow creates a DeviceAuthorizationFlow from cfg
+// without validating it. Prefer MustDeviceAuthorizationFlow for production use.
+func NewDeviceAuthorizationFlow(cfg *Config) *DeviceAuthorizationFlow {
+	return &DeviceAuthorizationFlow{cfg}
+}
+
+// MustDeviceAuthorizationFlow creates a DeviceAuthorizationFlow after
+// validating cfg. Returns an error if any required configuration is missing.
+func MustDeviceAuthorizationFlow(cfg *Config) (*DeviceAuthorizationFlow, error) {
+	if err := cfg.ValidateConfig(); err != nil {
+		return nil, err
+	}
+
+	return NewDeviceAuthorizationFlow(cfg), nil
+}
+
+// CheckEndpoint reports whether name matches the configured endpoint name.
+// The server calls this to route requests to the correct registered endpoint.
+func (f *DeviceAuthorizationFlow) CheckEndpoint(name string) bool {
+	if f.endpointName == "" {
+		return false
+	}
+
+	return name == f.endpointName
+}
+
+// EndpointResponse handles a device authorization request. It authenticates
+// the client, resolves the scope, issues a device_code and user_code, and
+// answers with the verification URIs and polling interval (RFC 8628 §3.2).
+func (f *DeviceAuthorizationFlow) EndpointResponse(r *http.Request, rw http.ResponseWriter) error {
+	req := NewRequestFromHTTP(r)
+	if err := f.checkParams(req); err != nil {
+		return err
+	}
+
+	if err := f.authenticateClient(req); err != nil {
+		return err
+	}
+
+	if err := f.validateScope(req); err != nil {
+		return err
+	}
+
+	code, err := f.genDeviceCode(req)
+	if err != nil {
+		return err
+	}
+
+	if err = f.deviceCodeMgr.Save(r.Context(), code); err != nil {
+		return err
+	}
+
+	data, err := f.responseData(code)
+	if err != nil {
+		return err
+	}
+
+	return utils.JSONResponse(rw, data, http.StatusOK)
+}
+
+// checkParams validates the HTTP method and content type before any manager
+// calls are made.
+func (f *DeviceAuthorizationFlow) checkParams(r *Request) error {
+	if err := r.ValidateHTTPMethod(); err != nil {
+		return err
+	}
+
+	return r.ValidateContentType()
+}
+
+// authenticateClient delegates to ClientManager.Authenticate, then verifies
+// the client is permitted to use the device_code grant.
+func (f *DeviceAuthorizationFlow) authenticateClient(r *Request) error {
+	client, err := f.clientMgr.Authenticate(r.Request, f.supportedClientAuthMethods, f.endpointName)
+	if err != nil {
+		return autherrors.ToAuthLibError(err)
+	}
+
+	if utils.IsNil(client) {
+		return autherrors.InvalidClientError()
+	}
+
+	if allowed := client.CheckGrantType(types.GrantTypeDeviceCode); !allowed {
+		return autherrors.UnauthorizedClientError().WithDescription("The client is not authorized to use grant type \"urn:ietf:params:oauth:grant-type:device_code\"")
+	}
+
+	r.Client = client
+	return nil
+}
+
+// validateScope filters the requested scopes through the client's allowed
+// list. When the scope parameter is absent, the client's registered scopes
+// are used.
+func (f *DeviceAuthorizationFlow) validateScope(r *Request) error {
+	if len(r.Scopes) == 0 {
+		r.Scopes = r.Client.GetScopes()
+		return nil
+	}
+
+	allowed := r.Client.GetAllowedScopes(r.Scopes)
+	if len(allowed) == 0 {
+		return autherrors.InvalidScopeError().WithDescription("none of the requested scopes are permitted for this client")
+	}
+
+	r.Scopes = allowed
+	return nil
+}
+
+// genDeviceCode allocates and populates a pending device authorization request.
+func (f *DeviceAuthorizationFlow) genDeviceCode(r *Request) (models.DeviceCode, error) {
+	code := f.deviceCodeMgr.New()
+	if utils.IsNil(code) {
+		return nil, ErrNilDeviceCode
+	}
+
+	deviceCode, err := utils.GenerateRandString(f.deviceCodeLength, utils.SecretCharset)
+	if err != nil {
+		return nil, err
+	}
+
+	userCode, err := f.genUserCode(r)
+	if err != nil {
+		return nil, err
+	}
+
+	code.SetDeviceCode(deviceCode)
+	code.SetUserCode(userCode)
+	code.SetClientID(r.Client.GetClientID())
+	code.SetScopes(r.Scopes)
+	code.SetStatus(types.DeviceCodeStatusPending)
+	code.SetIssuedAt(time.Now().UTC().Round(time.Second))
+	code.SetExpiresIn(f.expiresIn)
+	code.SetInterval(f.interval)
+	return code, nil
+}
+
+// genUserCode generates a normalized user code that is not held by another
+// request. User codes are short enough to collide, unlike device codes.
+func (f *DeviceAuthorizationFlow) genUserCode(r *Request) (string, error) {
+	for i := 0; i < maxUserCodeAttempts; i++ {
+		userCode, err := f.userCodeGen()
+		if err != nil {
+			return "", err
+		}
+
+		userCode = NormalizeUserCode(userCode)
+		existing, err := f.deviceCodeMgr.QueryByUserCode(r.Request.Context(), userCode)
+		if err != nil {
+			return "", err
+		}
+
+		if utils.IsNil(existing) {
+			return userCode, nil
+		}
+	}
+
+	return "", ErrUserCodeCollision
+}
+
+// responseData builds the device authorization response (RFC 8628 §3.2).
+// verification_uri_complete embeds the user code so the end-user can skip
+// typing it; Verifier still requires an explicit confirmation.
+func (f *DeviceAuthorizationFlow) responseData(code models.DeviceCode) (map[string]interface{}, error) {
+	userCode := FormatUserCode(code.GetUserCode())
+	completeURI, err := utils.AddParamsToURI(f.verificationURI, map[string]interface{}{
+		"user_code": userCode,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"device_code":               code.GetDeviceCode(),
+		"user_code":                 userCode,
+		"verification_uri":          f.verificationURI,
+		"verification_uri_complete": completeURI,
+		"expires_in":                int(code.GetExpiresIn().Seconds()),
+		"interval":                  int(code.GetInterval().Seconds()),
+	}, nil
+}
@@ -0,0 +1,210 @@
+package rfc8628
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	autherrors "github.com/tniah/authlib/errors"
+	"github.com/tniah/authlib/integrations/sql"
+	rfc8628 "github.com/tniah/authlib/mocks/rfc8628"
+	"github.com/tniah/authlib/models"
+	"github.com/tniah/authlib/types"
+)
+
+func newFormRequest(method, body string) *http.Request {
+	hr := httptest.NewRequest(method, "/", strings.NewReader(body))
+	hr.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return hr
+}
+
+func TestMustDeviceAuthorizationFlow(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		cfg := NewConfig().
+			SetClientManager(rfc8628.NewMockClientManager(t)).
+			SetDeviceCodeManager(rfc8628.NewMockDeviceCodeManager(t)).
+			SetUserManager(rfc8628.NewMockUserManager(t)).
+			SetTokenManager(rfc8628.NewMockTokenManager(t)).
+			SetVerificationURI("https://example.com/device")
+		f, err := MustDeviceAuthorizationFlow(cfg)
+		require.NoError(t, err)
+		assert.NotNil(t, f)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		f, err := MustDeviceAuthorizationFlow(NewConfig())
+		assert.ErrorIs(t, err, ErrNilClientManager)
+		assert.Nil(t, f)
+	})
+}
+
+func TestDeviceAuthorizationFlow_CheckEndpoint(t *testing.T) {
+	f := NewDeviceAuthorizationFlow(NewConfig())
+	assert.True(t, f.CheckEndpoint(EndpointNameDeviceAuthorization))
+	assert.False(t, f.CheckEndpoint("token"))
+
+	f = NewDeviceAuthorizationFlow(NewConfig().SetEndpointName(""))
+	assert.False(t, f.CheckEndpoint(""))
+}
+
+func TestDeviceAuthorizationFlow_EndpointResponse(t *testing.T) {
+	mockClient := &sql.Client{
+		ClientID:   uuid.NewString(),
+		GrantTypes: []string{string(types.GrantTypeDeviceCode)},
+		Scopes:     []string{"profile", "email"},
+	}
+
+	newFlow := func(clientMgr ClientManager, deviceCodeMgr DeviceCodeManager) *DeviceAuthorizationFlow {
+		return NewDeviceAuthorizationFlow(NewConfig().
+			SetClientManager(clientMgr).
+			SetDeviceCodeManager(deviceCodeMgr).
+			SetVerificationURI("https://example.com/device"))
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockClientMgr := rfc8628.NewMockClientManager(t)
+		mockClientMgr.On("Authenticate", mock.AnythingOfType("*http.Request"), mock.AnythingOfType("map[types.ClientAuthMethod]bool"), EndpointNameDeviceAuthorization).Return(mockClient, nil).Once()
+
+		var saved *sql.DeviceCode
+		mockDeviceCodeMgr := rfc8628.NewMockDeviceCodeManager(t)
+		mockDeviceCodeMgr.On("New").Return(&sql.DeviceCode{}).Once()
+		mockDeviceCodeMgr.On("QueryByUserCode", mock.Anything, mock.AnythingOfType("string")).Return(nil, nil).Once()
+		mockDeviceCodeMgr.On("Save", mock.Anything, mock.AnythingOfType("*sql.DeviceCode")).Run(func(args mock.Arguments) {
+			saved = args.Get(1).(*sql.DeviceCode)
+		}).Return(nil).Once()
+
+		rw := httptest.NewRecorder()
+		f := newFlow(mockClientMgr, mockDeviceCodeMgr)
+		err := f.EndpointResponse(newFormRequest(http.MethodPost, "client_id="+mockClient.ClientID+"&scope=profile+openid"), rw)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rw.Code)
+
+		require.NotNil(t, saved)
+		assert.Len(t, saved.DeviceCode, DefaultDeviceCodeLength)
+		assert.Len(t, saved.UserCode, UserCodeLength)
+		assert.Equal(t, mockClient.ClientID, saved.ClientID)
+		assert.Equal(t, []string{"profile"}, saved.Scopes)
+		assert.Equal(t, string(types.DeviceCodeStatusPending), saved.Status)
+		assert.Equal(t, DefaultExpiresIn, saved.ExpiresIn)
+		assert.Equal(t, DefaultInterval, saved.Interval)
+
+		var data map[string]interface{}
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &data))
+		userCode := FormatUserCode(saved.UserCode)
+		assert.Equal(t, saved.DeviceCode, data["device_code"])
+		assert.Equal(t, userCode, data["user_code"])
+		assert.Equal(t, "https://example.com/device", data["verification_uri"])
+		assert.Equal(t, "https://example.com/device?user_code="+userCode, data["verification_uri_complete"])
+		assert.Equal(t, float64(600), data["expires_in"])
+		assert.Equal(t, float64(5), data["interval"])
+	})
+
+	t.Run("uses_client_scopes_when_omitted", func(t *testing.T) {
+		mockClientMgr := rfc8628.NewMockClientManager(t)
+		mockClientMgr.On("Authenticate", mock.Anything, mock.Anything, mock.Anything).Return(mockClient, nil).Once()
+
+		mockDeviceCodeMgr := rfc8628.NewMockDeviceCodeManager(t)
+		mockDeviceCodeMgr.On("New").Return(&sql.DeviceCode{}).Once()
+		mockDeviceCodeMgr.On("QueryByUserCode", mock.Anything, mock.Anything).Return(nil, nil).Once()
+		mockDeviceCodeMgr.On("Save", mock.Anything, mock.MatchedBy(func(dc models.DeviceCode) bool {
+			return assert.ObjectsAreEqual([]string{"profile", "email"}, dc.GetScopes().String())
+		})).Return(nil).Once()
+
+		f := newFlow(mockClientMgr, mockDeviceCodeMgr)
+		err := f.EndpointResponse(newFormRequest(http.MethodPost, "client_id="+mockClient.ClientID), httptest.NewRecorder())
+		assert.NoError(t, err)
+	})
+
+	t.Run("retries_on_user_code_collision", func(t *testing.T) {
+		mockClientMgr := rfc8628.NewMockClientManager(t)
+		mockClientMgr.On("Authenticate", mock.Anything, mock.Anything, mock.Anything).Return(mockClient, nil).Once()
+
+		codes := []string{"bcdf-ghjk", "LMNPQRST"}
+		mockDeviceCodeMgr := rfc8628.NewMockDeviceCodeManager(t)
+		mockDeviceCodeMgr.On("New").Return(&sql.DeviceCode{}).Once()
+		mockDeviceCodeMgr.On("QueryByUserCode", mock.Anything, "BCDFGHJK").Return(&sql.DeviceCode{}, nil).Once()
+		mockDeviceCodeMgr.On("QueryByUserCode", mock.Anything, "LMNPQRST").Return(nil, nil).Once()
+		mockDeviceCodeMgr.On("Save", mock.Anything, mock.MatchedBy(func(dc models.DeviceCode) bool {
+			return dc.GetUserCode() == "LMNPQRST"
+		})).Return(nil).Once()
+
+		f := newFlow(mockClientMgr, mockDeviceCodeMgr)
+		f.SetUserCodeGenerator(func() (string, error) {
+			code := codes[0]
+			codes = codes[1:]
+			return code, nil
+		})
+		err := f.EndpointResponse(newFormRequest(http.MethodPost, "client_id="+mockClient.ClientID), httptest.NewRecorder())
+		assert.NoError(t, err)
+	})
+
+	t.Run("error_when_user_codes_collide", func(t *testing.T) {
+		mockClientMgr := rfc8628.NewMockClientManager(t)
+		mockClientMgr.On("Authenticate", mock.Anything, mock.Anything, mock.Anything).Return(mockClient, nil).Once()
+
+		mockDeviceCodeMgr := rfc8628.NewMockDeviceCodeManager(t)
+		mockDeviceCodeMgr.On("New").Return(&sql.DeviceCode{}).Once()
+		mockDeviceCodeMgr.On("QueryByUserCode", mock.Anything, mock.Anything).Return(&sql.DeviceCode{}, nil).Times(maxUserCodeAttempts)
+
+		f := newFlow(mockClientMgr, mockDeviceCodeMgr)
+		err := f.EndpointResponse(newFormRequest(http.MethodPost, "client_id="+mockClient.ClientID), httptest.NewRecorder())
+		assert.ErrorIs(t, err, ErrUserCodeCollision)
+	})
+
+	t.Run("error_when_method_is_not_post", func(t *testing.T) {
+		f := newFlow(rfc8628.NewMockClientManager(t), rfc8628.NewMockDeviceCodeManager(t))
+		err := f.EndpointResponse(newFormRequest(http.MethodGet, ""), httptest.NewRecorder())
+		assert.Equal(t, autherrors.ErrInvalidRequest, autherrors.ToAuthLibError(err).Code)
+	})
+
+	t.Run("error_when_content_type_is_not_form", func(t *testing.T) {
+		hr := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{}"))
+		hr.Header.Set("Content-Type", "application/json")
+		f := newFlow(rfc8628.NewMockClientManager(t), rfc8628.NewMockDeviceCodeManager(t))
+		err := f.EndpointResponse(hr, httptest.NewRecorder())
+		assert.Equal(t, autherrors.ErrInvalidRequest, autherrors.ToAuthLibError(err).Code)
+	})
+
+	t.Run("error_when_client_authentication_fails", func(t *testing.T) {
+		mockClientMgr := rfc8628.NewMockClientManager(t)
+		mockClientMgr.On("Authenticate", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("boom")).Once()
+
+		f := newFlow(mockClientMgr, rfc8628.NewMockDeviceCodeManager(t))
+		err := f.EndpointResponse(newFormRequest(http.MethodPost, ""), httptest.NewRecorder())
+		assert.Error(t, err)
+	})
+
+	t.Run("error_when_client_is_nil", func(t *testing.T) {
+		mockClientMgr := rfc8628.NewMockClientManager(t)
+		mockClientMgr.On("Authenticate", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil).Once()
+
+		f := newFlow(mockClientMgr, rfc8628.NewMockDeviceCodeManager(t))
+		err := f.EndpointResponse(newFormRequest(http.MethodPost, ""), httptest.NewRecorder())
+		assert.Equal(t, autherrors.ErrInvalidClient, autherrors.ToAuthLibError(err).Code)
+	})
+
+	t.Run("error_when_grant_type_not_allowed", func(t *testing.T) {
+		mockClientMgr := rfc8628.NewMockClientManager(t)
+		mockClientMgr.On("Authenticate", mock.Anything, mock.Anything, mock.Anything).Return(&sql.Client{ClientID: "other"}, nil).Once()
+
+		f := newFlow(mockClientMgr, rfc8628.NewMockDeviceCodeManager(t))
+		err := f.EndpointResponse(newFormRequest(http.MethodPost, ""), httptest.NewRecorder())
+		assert.Equal(t, autherrors.ErrUnauthorizedClient, autherrors.ToAuthLibError(err).Code)
+	})
+
+	t.Run("error_when_no_scope_allowed", func(t *testing.T) {
+		mockClientMgr := rfc8628.NewMockClientManager(t)
+		mockClientMgr.On("Authenticate", mock.Anything, mock.Anything, mock.Anything).Return(mockClient, nil).Once()
+
+		f := newFlow(mockClientMgr, rfc8628.NewMockDeviceCodeManager(t))
+		err := f.EndpointResponse(newFormRequest(http.MethodPost, "scope=admin"), httptest.NewRecorder())
+		assert.Equal(t, autherrors.ErrInvalidScope, autherrors.ToAuthLibError(err).Code)
+	})
+}
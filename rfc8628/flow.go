@@ -0,0 +1,297 @@
+package rfc8628
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	autherrors "github.com/tniah/authlib/errors"
+	"github.com/tniah/authlib/models"
+	"github.com/tniah/authlib/requests"
+	"github.com/tniah/authlib/rfc6749"
+	"github.com/tniah/authlib/types"
+	"github.com/tniah/authlib/utils"
+)
+
+// EndpointToken is the endpoint name passed to ClientManager.Authenticate so
+// that the client store can apply per-endpoint auth method policies.
+const EndpointToken = "token"
+
+// SlowDownIncrement is added to the polling interval of a device code every
+// time the device polls too quickly (RFC 8628 §3.5).
+const SlowDownIncrement = 5 * time.Second
+
+// ErrNilToken is returned by genToken when TokenManager.New returns nil.
+var ErrNilToken = errors.New("token is nil")
+
+// DeviceCodeFlow implements the Device Code grant at the token endpoint
+// (RFC 8628 §3.4-§3.5). The device polls with its device_code until the
+// end-user has approved or denied the request on the verification page.
+type DeviceCodeFlow struct {
+	*Config
+	*rfc6749.TokenFlowMixin
+}
+
+// NewDeviceCodeFlow creates a DeviceCodeFlow without validating config. Use
+// MustDeviceCodeFlow for production use.
+func NewDeviceCodeFlow(cfg *Config) *DeviceCodeFlow {
+	return &DeviceCodeFlow{Config: cfg, TokenFlowMixin: &rfc6749.TokenFlowMixin{}}
+}
+
+// MustDeviceCodeFlow returns a validated DeviceCodeFlow or an error if the
+// config is incomplete.
+func MustDeviceCodeFlow(cfg *Config) (*DeviceCodeFlow, error) {
+	if err := cfg.ValidateConfig(); err != nil {
+		return nil, err
+	}
+
+	return NewDeviceCodeFlow(cfg), nil
+}
+
+// CheckGrantType reports whether this flow handles the given grant_type.
+func (f *DeviceCodeFlow) CheckGrantType(gt types.GrantType) bool {
+	return gt.IsDeviceCode()
+}
+
+// ProvideMetadata adds the device_code grant and the client authentication
+// methods accepted at the token endpoint to the authorization server metadata
+// (RFC 8414 §2).
+func (f *DeviceCodeFlow) ProvideMetadata(md types.Metadata) {
+	md.Add(types.MetadataGrantTypesSupported, types.GrantTypeDeviceCode.String())
+	md.AddClientAuthMethods(types.MetadataTokenEndpointAuthMethodsSupported, f.supportedClientAuthMethods)
+}
+
+// ValidateTokenRequest validates the /token request: HTTP method, grant_type,
+// device_code, client authentication, device code state, polling interval,
+// the end-user's decision, and any registered TokenRequestValidator extensions.
+func (f *DeviceCodeFlow) ValidateTokenRequest(r *requests.TokenRequest) error {
+	if err := f.checkParams(r); err != nil {
+		return err
+	}
+
+	if err := f.authenticateClient(r); err != nil {
+		return err
+	}
+
+	code, err := f.validateDeviceCode(r)
+	if err != nil {
+		return err
+	}
+
+	if err = f.checkPollingInterval(r, code); err != nil {
+		return err
+	}
+
+	if err = f.checkStatus(r, code); err != nil {
+		return err
+	}
+
+	if err = f.queryUserByDeviceCode(r, code); err != nil {
+		return err
+	}
+
+	for _, h := range f.tokenReqValidators {
+		if err = h.ValidateTokenRequest(r); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// TokenResponse issues the access token (and a refresh token when the client
+// may use the refresh_token grant), runs TokenProcessor extensions, persists
+// the token, deletes the device code so it cannot be exchanged again, and
+// writes the JSON response (RFC 6749 §5.1).
+func (f *DeviceCodeFlow) TokenResponse(r *requests.TokenRequest, rw http.ResponseWriter) error {
+	token, err := f.genToken(r)
+	if err != nil {
+		return err
+	}
+
+	data := f.StandardTokenData(token)
+	for _, h := range f.tokenProcessors {
+		if err = h.ProcessToken(r, token, data); err != nil {
+			return err
+		}
+	}
+
+	ctx := r.Request.Context()
+	if err = f.tokenMgr.Save(ctx, token); err != nil {
+		return err
+	}
+
+	if err = f.deviceCodeMgr.DeleteByDeviceCode(ctx, r.DeviceCode); err != nil {
+		return err
+	}
+
+	return f.HandleTokenResponse(rw, data)
+}
+
+// checkParams validates the HTTP method, grant_type, and device_code before
+// any manager calls are made.
+func (f *DeviceCodeFlow) checkParams(r *requests.TokenRequest) error {
+	if err := f.checkTokenEndpointHttpMethod(r); err != nil {
+		return err
+	}
+
+	if err := f.validateGrantType(r); err != nil {
+		return err
+	}
+
+	return r.ValidateDeviceCode()
+}
+
+// checkTokenEndpointHttpMethod rejects requests whose HTTP method is not in
+// tokenEndpointHttpMethods (default: POST).
+func (f *DeviceCodeFlow) checkTokenEndpointHttpMethod(r *requests.TokenRequest) error {
+	for _, method := range f.tokenEndpointHttpMethods {
+		if r.Method() == method {
+			return nil
+		}
+	}
+
+	return autherrors.InvalidRequestError().WithDescription(fmt.Sprintf("unsupported http method \"%s\"", r.Method()))
+}
+
+// validateGrantType checks that grant_type is present and equals
+// "urn:ietf:params:oauth:grant-type:device_code".
+func (f *DeviceCodeFlow) validateGrantType(r *requests.TokenRequest) error {
+	if err := r.ValidateGrantType(); err != nil {
+		return err
+	}
+
+	if valid := r.GrantType.IsDeviceCode(); !valid {
+		return autherrors.UnsupportedGrantTypeError()
+	}
+
+	return nil
+}
+
+// authenticateClient delegates to ClientManager.Authenticate, then verifies the
+// client is permitted to use the device_code grant.
+func (f *DeviceCodeFlow) authenticateClient(r *requests.TokenRequest) error {
+	client, err := f.clientMgr.Authenticate(r.Request, f.supportedClientAuthMethods, EndpointToken)
+	if err != nil {
+		return err
+	}
+
+	if utils.IsNil(client) {
+		return autherrors.InvalidClientError()
+	}
+
+	if allowed := client.CheckGrantType(types.GrantTypeDeviceCode); !allowed {
+		return autherrors.UnauthorizedClientError().WithDescription("The client is not authorized to use grant type \"urn:ietf:params:oauth:grant-type:device_code\"")
+	}
+
+	r.Client = client
+	return nil
+}
+
+// validateDeviceCode verifies that the device code exists, was issued to the
+// authenticated client, and has not expired (RFC 8628 §3.5).
+func (f *DeviceCodeFlow) validateDeviceCode(r *requests.TokenRequest) (models.DeviceCode, error) {
+	code, err := f.deviceCodeMgr.QueryByDeviceCode(r.Request.Context(), r.DeviceCode)
+	if err != nil {
+		return nil, err
+	}
+
+	if utils.IsNil(code) {
+		return nil, autherrors.InvalidGrantError().WithDescription("Invalid \"device_code\" in request")
+	}
+
+	if code.GetClientID() != r.Client.GetClientID() {
+		return nil, autherrors.InvalidGrantError().WithDescription("\"device_code\" was not issued to this client")
+	}
+
+	if code.GetIssuedAt().Add(code.GetExpiresIn()).Before(time.Now().UTC()) {
+		return nil, autherrors.ExpiredTokenError()
+	}
+
+	return code, nil
+}
+
+// checkPollingInterval records the polling time and answers slow_down when the
+// device polls before the interval has elapsed. Each slow_down permanently
+// adds SlowDownIncrement to the interval of the device code (RFC 8628 §3.5).
+func (f *DeviceCodeFlow) checkPollingInterval(r *requests.TokenRequest, code models.DeviceCode) error {
+	now := time.Now().UTC()
+	lastPolledAt := code.GetLastPolledAt()
+	tooFast := !lastPolledAt.IsZero() && now.Sub(lastPolledAt) < code.GetInterval()
+	if tooFast {
+		code.SetInterval(code.GetInterval() + SlowDownIncrement)
+	}
+
+	code.SetLastPolledAt(now)
+	if err := f.deviceCodeMgr.Update(r.Request.Context(), code); err != nil {
+		return err
+	}
+
+	if tooFast {
+		return autherrors.SlowDownError()
+	}
+
+	return nil
+}
+
+// checkStatus maps the end-user's decision to the polling response. A denied
+// request is deleted so the device code cannot be polled again.
+func (f *DeviceCodeFlow) checkStatus(r *requests.TokenRequest, code models.DeviceCode) error {
+	status := code.GetStatus()
+	if status.IsApproved() {
+		r.Scopes = code.GetScopes()
+		return nil
+	}
+
+	if status.IsDenied() {
+		if err := f.deviceCodeMgr.DeleteByDeviceCode(r.Request.Context(), code.GetDeviceCode()); err != nil {
+			return err
+		}
+
+		// RFC 8628 §3.5 errors are token endpoint errors (RFC 6749 §5.2),
+		// answered with 400 rather than the 403 used at the authorization
+		// endpoint.
+		err := autherrors.AccessDeniedError()
+		err.SetHttpCode(http.StatusBadRequest)
+		return err
+	}
+
+	return autherrors.AuthorizationPendingError()
+}
+
+// queryUserByDeviceCode resolves the end-user who approved the request and
+// populates r.User. Returns invalid_grant if no user is found.
+func (f *DeviceCodeFlow) queryUserByDeviceCode(r *requests.TokenRequest, code models.DeviceCode) error {
+	if code.GetUserID() == "" {
+		return autherrors.InvalidGrantError().WithDescription("No user could be found associated with this device code")
+	}
+
+	user, err := f.userMgr.QueryUserByDeviceCode(r.Request.Context(), code, r)
+	if err != nil {
+		return err
+	}
+
+	if utils.IsNil(user) {
+		return autherrors.InvalidGrantError().WithDescription("No user could be found associated with this device code")
+	}
+
+	r.User = user
+	return nil
+}
+
+// genToken allocates and populates a new token. A refresh token is included
+// when the client may use the refresh_token grant.
+func (f *DeviceCodeFlow) genToken(r *requests.TokenRequest) (models.Token, error) {
+	token := f.tokenMgr.New()
+	if utils.IsNil(token) {
+		return nil, ErrNilToken
+	}
+
+	includeRefreshToken := r.Client.CheckGrantType(types.GrantTypeRefreshToken)
+	if err := f.tokenMgr.Generate(token, r, includeRefreshToken); err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
@@ -0,0 +1,285 @@
+package rfc8628
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	autherrors "github.com/tniah/authlib/errors"
+	"github.com/tniah/authlib/integrations/sql"
+	rfc8628 "github.com/tniah/authlib/mocks/rfc8628"
+	"github.com/tniah/authlib/requests"
+	"github.com/tniah/authlib/types"
+)
+
+func TestMustDeviceCodeFlow(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		cfg := NewConfig().
+			SetClientManager(rfc8628.NewMockClientManager(t)).
+			SetDeviceCodeManager(rfc8628.NewMockDeviceCodeManager(t)).
+			SetUserManager(rfc8628.NewMockUserManager(t)).
+			SetTokenManager(rfc8628.NewMockTokenManager(t)).
+			SetVerificationURI("https://example.com/device")
+		f, err := MustDeviceCodeFlow(cfg)
+		require.NoError(t, err)
+		assert.NotNil(t, f)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		f, err := MustDeviceCodeFlow(NewConfig())
+		require.Error(t, err)
+		assert.Nil(t, f)
+	})
+}
+
+func TestDeviceCodeFlow_CheckGrantType(t *testing.T) {
+	f := NewDeviceCodeFlow(NewConfig())
+	assert.True(t, f.CheckGrantType(types.GrantTypeDeviceCode))
+	assert.False(t, f.CheckGrantType(types.GrantTypeAuthorizationCode))
+	assert.False(t, f.CheckGrantType(types.NewGrantType("")))
+}
+
+func TestDeviceCodeFlow_checkParams(t *testing.T) {
+	f := NewDeviceCodeFlow(NewConfig())
+	t.Run("success", func(t *testing.T) {
+		r := &requests.TokenRequest{
+			Request:    httptest.NewRequest(http.MethodPost, "/", nil),
+			GrantType:  types.GrantTypeDeviceCode,
+			DeviceCode: "my-device-code",
+		}
+		assert.NoError(t, f.checkParams(r))
+	})
+
+	t.Run("error", func(t *testing.T) {
+		cases := []struct {
+			r     *requests.TokenRequest
+			error error
+		}{
+			{
+				&requests.TokenRequest{Request: httptest.NewRequest(http.MethodGet, "/", nil)},
+				autherrors.ErrInvalidRequest,
+			},
+			{
+				&requests.TokenRequest{Request: httptest.NewRequest(http.MethodPost, "/", nil)},
+				autherrors.ErrInvalidRequest,
+			},
+			{
+				&requests.TokenRequest{
+					Request:   httptest.NewRequest(http.MethodPost, "/", nil),
+					GrantType: types.GrantTypeRefreshToken,
+				},
+				autherrors.ErrUnsupportedGrantType,
+			},
+			{
+				&requests.TokenRequest{
+					Request:   httptest.NewRequest(http.MethodPost, "/", nil),
+					GrantType: types.GrantTypeDeviceCode,
+				},
+				autherrors.ErrInvalidRequest,
+			},
+		}
+
+		for i, c := range cases {
+			err := f.checkParams(c.r)
+			assert.Equalf(t, c.error, autherrors.ToAuthLibError(err).Code, "case %d failed", i)
+		}
+	})
+}
+
+func TestDeviceCodeFlow_ValidateTokenRequest(t *testing.T) {
+	mockClient := &sql.Client{
+		ClientID:   "client-id",
+		GrantTypes: []string{string(types.GrantTypeDeviceCode)},
+	}
+	mockUser := &sql.User{UserID: "user-id"}
+
+	newRequest := func() *requests.TokenRequest {
+		return &requests.TokenRequest{
+			Request:    httptest.NewRequest(http.MethodPost, "/", nil),
+			GrantType:  types.GrantTypeDeviceCode,
+			DeviceCode: "my-device-code",
+		}
+	}
+
+	newDeviceCode := func(status types.DeviceCodeStatus) *sql.DeviceCode {
+		return &sql.DeviceCode{
+			DeviceCode: "my-device-code",
+			ClientID:   mockClient.ClientID,
+			UserID:     mockUser.UserID,
+			Scopes:     []string{"profile"},
+			Status:     string(status),
+			IssuedAt:   time.Now().UTC(),
+			ExpiresIn:  time.Minute,
+			Interval:   5 * time.Second,
+		}
+	}
+
+	newFlow := func(t *testing.T, dc *sql.DeviceCode) (*DeviceCodeFlow, *rfc8628.MockDeviceCodeManager, *rfc8628.MockUserManager) {
+		mockClientMgr := rfc8628.NewMockClientManager(t)
+		mockClientMgr.On("Authenticate", mock.AnythingOfType("*http.Request"), mock.AnythingOfType("map[types.ClientAuthMethod]bool"), EndpointToken).Return(mockClient, nil).Once()
+
+		mockDeviceCodeMgr := rfc8628.NewMockDeviceCodeManager(t)
+		mockDeviceCodeMgr.On("QueryByDeviceCode", mock.Anything, "my-device-code").Return(dc, nil).Once()
+
+		mockUserMgr := rfc8628.NewMockUserManager(t)
+		return NewDeviceCodeFlow(NewConfig().
+			SetClientManager(mockClientMgr).
+			SetDeviceCodeManager(mockDeviceCodeMgr).
+			SetUserManager(mockUserMgr)), mockDeviceCodeMgr, mockUserMgr
+	}
+
+	t.Run("success_when_approved", func(t *testing.T) {
+		dc := newDeviceCode(types.DeviceCodeStatusApproved)
+		f, mockDeviceCodeMgr, mockUserMgr := newFlow(t, dc)
+		mockDeviceCodeMgr.On("Update", mock.Anything, dc).Return(nil).Once()
+		mockUserMgr.On("QueryUserByDeviceCode", mock.Anything, dc, mock.AnythingOfType("*requests.TokenRequest")).Return(mockUser, nil).Once()
+
+		r := newRequest()
+		require.NoError(t, f.ValidateTokenRequest(r))
+		assert.Equal(t, mockClient, r.Client)
+		assert.Equal(t, mockUser, r.User)
+		assert.Equal(t, types.Scopes{"profile"}, r.Scopes)
+		assert.False(t, dc.LastPolledAt.IsZero())
+	})
+
+	t.Run("authorization_pending", func(t *testing.T) {
+		dc := newDeviceCode(types.DeviceCodeStatusPending)
+		f, mockDeviceCodeMgr, _ := newFlow(t, dc)
+		mockDeviceCodeMgr.On("Update", mock.Anything, dc).Return(nil).Once()
+
+		err := f.ValidateTokenRequest(newRequest())
+		assert.Equal(t, autherrors.ErrAuthorizationPending, autherrors.ToAuthLibError(err).Code)
+	})
+
+	t.Run("slow_down_when_polling_too_fast", func(t *testing.T) {
+		dc := newDeviceCode(types.DeviceCodeStatusPending)
+		dc.LastPolledAt = time.Now().UTC().Add(-time.Second)
+		f, mockDeviceCodeMgr, _ := newFlow(t, dc)
+		mockDeviceCodeMgr.On("Update", mock.Anything, dc).Return(nil).Once()
+
+		err := f.ValidateTokenRequest(newRequest())
+		assert.Equal(t, autherrors.ErrSlowDown, autherrors.ToAuthLibError(err).Code)
+		assert.Equal(t, 10*time.Second, dc.Interval)
+	})
+
+	t.Run("pending_when_interval_elapsed", func(t *testing.T) {
+		dc := newDeviceCode(types.DeviceCodeStatusPending)
+		dc.LastPolledAt = time.Now().UTC().Add(-6 * time.Second)
+		f, mockDeviceCodeMgr, _ := newFlow(t, dc)
+		mockDeviceCodeMgr.On("Update", mock.Anything, dc).Return(nil).Once()
+
+		err := f.ValidateTokenRequest(newRequest())
+		assert.Equal(t, autherrors.ErrAuthorizationPending, autherrors.ToAuthLibError(err).Code)
+		assert.Equal(t, 5*time.Second, dc.Interval)
+	})
+
+	t.Run("access_denied", func(t *testing.T) {
+		dc := newDeviceCode(types.DeviceCodeStatusDenied)
+		f, mockDeviceCodeMgr, _ := newFlow(t, dc)
+		mockDeviceCodeMgr.On("Update", mock.Anything, dc).Return(nil).Once()
+		mockDeviceCodeMgr.On("DeleteByDeviceCode", mock.Anything, "my-device-code").Return(nil).Once()
+
+		err := f.ValidateTokenRequest(newRequest())
+		authErr := autherrors.ToAuthLibError(err)
+		assert.Equal(t, autherrors.ErrAccessDenied, authErr.Code)
+		assert.Equal(t, http.StatusBadRequest, authErr.HttpCode)
+	})
+
+	t.Run("expired_token", func(t *testing.T) {
+		dc := newDeviceCode(types.DeviceCodeStatusApproved)
+		dc.IssuedAt = time.Now().UTC().Add(-2 * time.Minute)
+		f, _, _ := newFlow(t, dc)
+
+		err := f.ValidateTokenRequest(newRequest())
+		assert.Equal(t, autherrors.ErrExpiredToken, autherrors.ToAuthLibError(err).Code)
+	})
+
+	t.Run("error_when_device_code_not_found", func(t *testing.T) {
+		f, _, _ := newFlow(t, nil)
+		err := f.ValidateTokenRequest(newRequest())
+		assert.Equal(t, autherrors.ErrInvalidGrant, autherrors.ToAuthLibError(err).Code)
+	})
+
+	t.Run("error_when_device_code_issued_to_another_client", func(t *testing.T) {
+		dc := newDeviceCode(types.DeviceCodeStatusApproved)
+		dc.ClientID = "other-client"
+		f, _, _ := newFlow(t, dc)
+
+		err := f.ValidateTokenRequest(newRequest())
+		assert.Equal(t, autherrors.ErrInvalidGrant, autherrors.ToAuthLibError(err).Code)
+	})
+
+	t.Run("error_when_user_not_found", func(t *testing.T) {
+		dc := newDeviceCode(types.DeviceCodeStatusApproved)
+		f, mockDeviceCodeMgr, mockUserMgr := newFlow(t, dc)
+		mockDeviceCodeMgr.On("Update", mock.Anything, dc).Return(nil).Once()
+		mockUserMgr.On("QueryUserByDeviceCode", mock.Anything, dc, mock.Anything).Return(nil, nil).Once()
+
+		err := f.ValidateTokenRequest(newRequest())
+		assert.Equal(t, autherrors.ErrInvalidGrant, autherrors.ToAuthLibError(err).Code)
+	})
+
+	t.Run("error_from_extension", func(t *testing.T) {
+		dc := newDeviceCode(types.DeviceCodeStatusApproved)
+		f, mockDeviceCodeMgr, mockUserMgr := newFlow(t, dc)
+		mockDeviceCodeMgr.On("Update", mock.Anything, dc).Return(nil).Once()
+		mockUserMgr.On("QueryUserByDeviceCode", mock.Anything, dc, mock.Anything).Return(mockUser, nil).Once()
+
+		validator := rfc8628.NewMockTokenRequestValidator(t)
+		validator.On("ValidateTokenRequest", mock.Anything).Return(errors.New("rejected")).Once()
+		f.RegisterExtension(validator)
+
+		err := f.ValidateTokenRequest(newRequest())
+		assert.EqualError(t, err, "rejected")
+	})
+}
+
+func TestDeviceCodeFlow_TokenResponse(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		r := &requests.TokenRequest{
+			Request:    httptest.NewRequest(http.MethodPost, "/", nil),
+			GrantType:  types.GrantTypeDeviceCode,
+			DeviceCode: "my-device-code",
+			Client: &sql.Client{
+				ClientID:   "client-id",
+				GrantTypes: []string{string(types.GrantTypeDeviceCode), string(types.GrantTypeRefreshToken)},
+			},
+			User: &sql.User{UserID: "user-id"},
+		}
+
+		token := &sql.Token{AccessToken: "my-access-token", TokenType: "Bearer"}
+		mockTokenMgr := rfc8628.NewMockTokenManager(t)
+		mockTokenMgr.On("New").Return(token).Once()
+		mockTokenMgr.On("Generate", token, r, true).Return(nil).Once()
+		mockTokenMgr.On("Save", mock.Anything, token).Return(nil).Once()
+
+		mockDeviceCodeMgr := rfc8628.NewMockDeviceCodeManager(t)
+		mockDeviceCodeMgr.On("DeleteByDeviceCode", mock.Anything, "my-device-code").Return(nil).Once()
+
+		processor := rfc8628.NewMockTokenProcessor(t)
+		processor.On("ProcessToken", r, token, mock.AnythingOfType("map[string]interface {}")).Return(nil).Once()
+
+		f := NewDeviceCodeFlow(NewConfig().
+			SetTokenManager(mockTokenMgr).
+			SetDeviceCodeManager(mockDeviceCodeMgr).
+			RegisterExtension(processor))
+		rw := httptest.NewRecorder()
+		require.NoError(t, f.TokenResponse(r, rw))
+		assert.Equal(t, http.StatusOK, rw.Code)
+		assert.Contains(t, rw.Body.String(), "my-access-token")
+	})
+
+	t.Run("error_when_token_is_nil", func(t *testing.T) {
+		mockTokenMgr := rfc8628.NewMockTokenManager(t)
+		mockTokenMgr.On("New").Return(nil).Once()
+
+		f := NewDeviceCodeFlow(NewConfig().SetTokenManager(mockTokenMgr))
+		err := f.TokenResponse(&requests.TokenRequest{Request: httptest.NewRequest(http.MethodPost, "/", nil)}, httptest.NewRecorder())
+		assert.ErrorIs(t, err, ErrNilToken)
+	})
+}
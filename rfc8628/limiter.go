@@ -0,0 +1,72 @@
+package rfc8628
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Defaults for the in-memory AttemptLimiter installed by NewConfig.
+const (
+	DefaultMaxAttempts   = 5
+	DefaultAttemptWindow = 15 * time.Minute
+)
+
+// MemoryAttemptLimiter is an in-process AttemptLimiter allowing maxAttempts
+// failed verifications per key within a fixed window. It suits a single
+// server instance; use a shared store (e.g. Redis) behind AttemptLimiter when
+// running several.
+type MemoryAttemptLimiter struct {
+	lock        sync.Mutex
+	maxAttempts int
+	window      time.Duration
+	attempts    map[string]*attempt
+}
+
+type attempt struct {
+	count   int
+	resetAt time.Time
+}
+
+// NewMemoryAttemptLimiter returns a MemoryAttemptLimiter allowing maxAttempts
+// failures per key within window.
+func NewMemoryAttemptLimiter(maxAttempts int, window time.Duration) *MemoryAttemptLimiter {
+	return &MemoryAttemptLimiter{
+		maxAttempts: maxAttempts,
+		window:      window,
+		attempts:    make(map[string]*attempt),
+	}
+}
+
+// Allow reports whether key is still below maxAttempts in the current window.
+func (l *MemoryAttemptLimiter) Allow(_ context.Context, key string) bool {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	a, ok := l.attempts[key]
+	if !ok {
+		return true
+	}
+
+	if time.Now().After(a.resetAt) {
+		delete(l.attempts, key)
+		return true
+	}
+
+	return a.count < l.maxAttempts
+}
+
+// Fail records a failed attempt for key, opening a new window when needed.
+func (l *MemoryAttemptLimiter) Fail(_ context.Context, key string) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	now := time.Now()
+	a, ok := l.attempts[key]
+	if !ok || now.After(a.resetAt) {
+		a = &attempt{resetAt: now.Add(l.window)}
+		l.attempts[key] = a
+	}
+
+	a.count++
+}
@@ -0,0 +1,31 @@
+package rfc8628
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryAttemptLimiter(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("blocks_after_max_attempts", func(t *testing.T) {
+		l := NewMemoryAttemptLimiter(2, time.Minute)
+		assert.True(t, l.Allow(ctx, "user"))
+		l.Fail(ctx, "user")
+		assert.True(t, l.Allow(ctx, "user"))
+		l.Fail(ctx, "user")
+		assert.False(t, l.Allow(ctx, "user"))
+		assert.True(t, l.Allow(ctx, "other-user"))
+	})
+
+	t.Run("allows_again_after_window", func(t *testing.T) {
+		l := NewMemoryAttemptLimiter(1, time.Millisecond)
+		l.Fail(ctx, "user")
+		assert.False(t, l.Allow(ctx, "user"))
+		time.Sleep(5 * time.Millisecond)
+		assert.True(t, l.Allow(ctx, "user"))
+	})
+}
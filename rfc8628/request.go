@@ -0,0 +1,56 @@
+package rfc8628
+
+import (
+	"net/http"
+	"strings"
+
+	autherrors "github.com/tniah/authlib/errors"
+	"github.com/tniah/authlib/models"
+	"github.com/tniah/authlib/types"
+	"github.com/tniah/authlib/utils"
+)
+
+// Request holds the parsed parameters of an RFC 8628 device authorization
+// request.
+type Request struct {
+	ClientID string
+	Scopes   types.Scopes
+
+	Client  models.Client
+	Request *http.Request
+}
+
+// NewRequestFromHTTP parses a device authorization request from an HTTP
+// request, extracting the client_id and optional scope form values.
+func NewRequestFromHTTP(r *http.Request) *Request {
+	return &Request{
+		ClientID: r.PostFormValue("client_id"),
+		Scopes:   types.NewScopes(strings.Fields(r.PostFormValue("scope"))),
+		Request:  r,
+	}
+}
+
+// ValidateHTTPMethod returns an error if the request method is not POST,
+// as required by RFC 8628 §3.1.
+func (r *Request) ValidateHTTPMethod() error {
+	if r.Request.Method != http.MethodPost {
+		return autherrors.InvalidRequestError().WithDescription("request must be \"POST\"")
+	}
+
+	return nil
+}
+
+// ValidateContentType returns an error if the Content-Type is not
+// application/x-www-form-urlencoded, as required by RFC 8628 §3.1.
+func (r *Request) ValidateContentType() error {
+	ct, err := utils.ContentType(r.Request)
+	if err != nil {
+		return autherrors.InvalidRequestError()
+	}
+
+	if valid := ct.IsXWWWFormUrlencoded(); !valid {
+		return autherrors.InvalidRequestError().WithDescription("content type must be \"application/x-www-form-urlencoded\"")
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package rfc8628
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/tniah/authlib/models"
+	"github.com/tniah/authlib/requests"
+	"github.com/tniah/authlib/types"
+)
+
+// ClientManager authenticates the client at the device authorization and
+// token endpoints.
+type ClientManager interface {
+	// Authenticate verifies the client credentials and returns the authenticated
+	// client. endpointName identifies the endpoint being accessed (used for
+	// method-specific logic in multi-endpoint setups).
+	Authenticate(r *http.Request, authMethods map[types.ClientAuthMethod]bool, endpointName string) (models.Client, error)
+}
+
+// DeviceCodeManager persists device authorization requests.
+type DeviceCodeManager interface {
+	// New allocates a blank DeviceCode ready to be populated by the flow.
+	New() models.DeviceCode
+
+	// QueryByDeviceCode retrieves the request matching deviceCode.
+	// Return (nil, nil) when it does not exist.
+	QueryByDeviceCode(ctx context.Context, deviceCode string) (models.DeviceCode, error)
+
+	// QueryByUserCode retrieves the request matching userCode. userCode is
+	// always normalized (see NormalizeUserCode). Return (nil, nil) when it
+	// does not exist.
+	QueryByUserCode(ctx context.Context, userCode string) (models.DeviceCode, error)
+
+	// Save persists a newly issued request.
+	Save(ctx context.Context, code models.DeviceCode) error
+
+	// Update persists changes to an existing request: the end-user's
+	// decision, the polling interval, and the last polling time.
+	Update(ctx context.Context, code models.DeviceCode) error
+
+	// DeleteByDeviceCode removes the request once tokens have been issued or
+	// the end-user has denied it, so the device_code cannot be reused.
+	DeleteByDeviceCode(ctx context.Context, deviceCode string) error
+}
+
+// UserManager resolves the end-user who approved a device authorization request.
+type UserManager interface {
+	// QueryUserByDeviceCode retrieves the user stored on code. Return
+	// (nil, nil) when the user no longer exists.
+	QueryUserByDeviceCode(ctx context.Context, code models.DeviceCode, r *requests.TokenRequest) (models.User, error)
+}
+
+// TokenManager generates and persists access (and optionally refresh) tokens.
+type TokenManager interface {
+	// New allocates a blank Token ready to be populated by Generate.
+	New() models.Token
+
+	// Generate populates token with a value, expiry, scopes, and client/user
+	// binding. includeRefreshToken is true when the client may use the
+	// refresh_token grant.
+	Generate(token models.Token, r *requests.TokenRequest, includeRefreshToken bool) error
+
+	// Save persists the token to the backing store.
+	Save(ctx context.Context, token models.Token) error
+}
+
+// AttemptLimiter throttles user_code entry on the verification page to resist
+// brute force (RFC 8628 §5.1). key identifies the end-user entering codes.
+// Failures are not cleared by a successful entry: an attacker could otherwise
+// reset the counter with user codes of their own devices.
+type AttemptLimiter interface {
+	// Allow reports whether key may attempt another verification.
+	Allow(ctx context.Context, key string) bool
+
+	// Fail records a failed attempt for key.
+	Fail(ctx context.Context, key string)
+}
+
+// UserCodeGenerator returns a new user_code in normalized form (see
+// NormalizeUserCode). The default is GenerateUserCode.
+type UserCodeGenerator func() (string, error)
+
+// TokenRequestValidator is an extension hook called during
+// ValidateTokenRequest, after the built-in checks pass.
+type TokenRequestValidator interface {
+	ValidateTokenRequest(r *requests.TokenRequest) error
+}
+
+// TokenProcessor is an extension hook called after the token is generated and
+// before it is saved. Use it to add extra fields to the token response.
+type TokenProcessor interface {
+	ProcessToken(r *requests.TokenRequest, token models.Token, data map[string]interface{}) error
+}
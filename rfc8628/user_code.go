@@ -0,0 +1,54 @@
+package rfc8628
+
+import (
+	"strings"
+
+	"github.com/tniah/authlib/utils"
+)
+
+const (
+	// UserCodeLength is the number of characters in a generated user_code.
+	UserCodeLength = 8
+	// UserCodeGroupSize is the number of characters between dashes when a
+	// user_code is displayed.
+	UserCodeGroupSize = 4
+)
+
+// UserCodeCharset holds the consonants used for generated user codes
+// (RFC 8628 §6.1). Vowels are left out so codes cannot spell words, and the
+// 20-letter alphabet gives 20^8 (about 2^34.5) combinations for 8 characters.
+var UserCodeCharset = []rune("BCDFGHJKLMNPQRSTVWXZ")
+
+// GenerateUserCode returns a random, normalized user_code of UserCodeLength
+// characters drawn from UserCodeCharset, e.g. "WDJBMJHT".
+func GenerateUserCode() (string, error) {
+	return utils.GenerateRandString(UserCodeLength, UserCodeCharset)
+}
+
+// NormalizeUserCode brings a user_code typed by the end-user to its stored
+// form: upper case, with dashes and whitespace removed. "wdjb-mjht" and
+// "WDJB MJHT" both normalize to "WDJBMJHT" (RFC 8628 §6.1).
+func NormalizeUserCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' || r == '\t' {
+			return -1
+		}
+
+		return r
+	}, strings.ToUpper(strings.TrimSpace(code)))
+}
+
+// FormatUserCode groups a normalized user_code with dashes for display,
+// e.g. "WDJBMJHT" becomes "WDJB-MJHT".
+func FormatUserCode(code string) string {
+	runes := []rune(code)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && i%UserCodeGroupSize == 0 {
+			b.WriteRune('-')
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,51 @@
+package rfc8628
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateUserCode(t *testing.T) {
+	code, err := GenerateUserCode()
+	assert.NoError(t, err)
+	assert.Len(t, code, UserCodeLength)
+	for _, r := range code {
+		assert.True(t, strings.ContainsRune(string(UserCodeCharset), r), "unexpected character %q", r)
+	}
+	assert.Equal(t, code, NormalizeUserCode(code))
+}
+
+func TestNormalizeUserCode(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected string
+	}{
+		{"WDJB-MJHT", "WDJBMJHT"},
+		{"wdjb-mjht", "WDJBMJHT"},
+		{" WDJB MJHT ", "WDJBMJHT"},
+		{"wd-jb-mj-ht", "WDJBMJHT"},
+		{"", ""},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.expected, NormalizeUserCode(c.input), c.input)
+	}
+}
+
+func TestFormatUserCode(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected string
+	}{
+		{"WDJBMJHT", "WDJB-MJHT"},
+		{"WDJBMJHTX", "WDJB-MJHT-X"},
+		{"WDJB", "WDJB"},
+		{"", ""},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.expected, FormatUserCode(c.input), c.input)
+	}
+}
@@ -0,0 +1,134 @@
+package rfc8628
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/tniah/authlib/models"
+	"github.com/tniah/authlib/types"
+	"github.com/tniah/authlib/utils"
+)
+
+// Errors returned by Verifier. They are meant to be rendered by the host
+// application on its verification page, not sent to the device.
+var (
+	ErrNilUser              = errors.New("user is nil")
+	ErrInvalidUserCode      = errors.New("user code is invalid")
+	ErrExpiredUserCode      = errors.New("user code has expired")
+	ErrUserCodeUsed         = errors.New("user code has already been used")
+	ErrTooManyAttempts      = errors.New("too many failed user code attempts")
+	ErrConfirmationRequired = errors.New("the decision must be confirmed with a POST request")
+)
+
+// Verifier backs the end-user verification page (RFC 8628 §3.3). The host
+// application authenticates the end-user, calls Lookup to show which client is
+// asking for which scopes, and submits the end-user's decision to Approve or
+// Deny from a form POST.
+//
+// A user_code pre-filled through verification_uri_complete is never approved
+// by merely opening the link: Approve and Deny accept only POST requests, so
+// an explicit confirmation is required (RFC 8628 §5.4).
+type Verifier struct {
+	*Config
+}
+
+// NewVerifier creates a Verifier from cfg without validating it. Prefer
+// MustVerifier for production use.
+func NewVerifier(cfg *Config) *Verifier {
+	return &Verifier{cfg}
+}
+
+// MustVerifier creates a Verifier after validating cfg. Returns an error if any
+// required configuration is missing.
+func MustVerifier(cfg *Config) (*Verifier, error) {
+	if err := cfg.ValidateConfig(); err != nil {
+		return nil, err
+	}
+
+	return NewVerifier(cfg), nil
+}
+
+// Lookup returns the pending device authorization request matching the
+// user_code query or form parameter, so the host can ask the end-user to confirm it.
+// Matching ignores case, dashes, and whitespace.
+func (v *Verifier) Lookup(r *http.Request, user models.User) (models.DeviceCode, error) {
+	return v.queryByUserCode(r, user, r.FormValue("user_code"))
+}
+
+// Approve grants the device authorization request matching the user_code form
+// parameter on behalf of user. Only POST requests are accepted.
+func (v *Verifier) Approve(r *http.Request, user models.User) (models.DeviceCode, error) {
+	return v.decide(r, user, types.DeviceCodeStatusApproved)
+}
+
+// Deny rejects the device authorization request matching the user_code form
+// parameter on behalf of user. Only POST requests are accepted.
+func (v *Verifier) Deny(r *http.Request, user models.User) (models.DeviceCode, error) {
+	return v.decide(r, user, types.DeviceCodeStatusDenied)
+}
+
+// decide records the end-user's decision. The user_code is read from the
+// request body only, never from the query string of a link.
+func (v *Verifier) decide(r *http.Request, user models.User, status types.DeviceCodeStatus) (models.DeviceCode, error) {
+	if r.Method != http.MethodPost {
+		return nil, ErrConfirmationRequired
+	}
+
+	code, err := v.queryByUserCode(r, user, r.PostFormValue("user_code"))
+	if err != nil {
+		return nil, err
+	}
+
+	code.SetStatus(status)
+	if status.IsApproved() {
+		code.SetUserID(user.GetUserID())
+	}
+
+	if err = v.deviceCodeMgr.Update(r.Context(), code); err != nil {
+		return nil, err
+	}
+
+	return code, nil
+}
+
+// queryByUserCode resolves a pending, unexpired request by user code. Unknown
+// codes count as failed attempts for user; once the AttemptLimiter refuses,
+// no further lookups are made.
+func (v *Verifier) queryByUserCode(r *http.Request, user models.User, userCode string) (models.DeviceCode, error) {
+	if utils.IsNil(user) {
+		return nil, ErrNilUser
+	}
+
+	ctx := r.Context()
+	key := user.GetUserID()
+	if allowed := v.attemptLimiter.Allow(ctx, key); !allowed {
+		return nil, ErrTooManyAttempts
+	}
+
+	userCode = NormalizeUserCode(userCode)
+	if userCode == "" {
+		v.attemptLimiter.Fail(ctx, key)
+		return nil, ErrInvalidUserCode
+	}
+
+	code, err := v.deviceCodeMgr.QueryByUserCode(ctx, userCode)
+	if err != nil {
+		return nil, err
+	}
+
+	if utils.IsNil(code) {
+		v.attemptLimiter.Fail(ctx, key)
+		return nil, ErrInvalidUserCode
+	}
+
+	if code.GetIssuedAt().Add(code.GetExpiresIn()).Before(time.Now().UTC()) {
+		return nil, ErrExpiredUserCode
+	}
+
+	if !code.GetStatus().IsPending() {
+		return nil, ErrUserCodeUsed
+	}
+
+	return code, nil
+}
@@ -0,0 +1,151 @@
+package rfc8628
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/tniah/authlib/integrations/sql"
+	rfc8628 "github.com/tniah/authlib/mocks/rfc8628"
+	"github.com/tniah/authlib/types"
+)
+
+func TestMustVerifier(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		cfg := NewConfig().
+			SetClientManager(rfc8628.NewMockClientManager(t)).
+			SetDeviceCodeManager(rfc8628.NewMockDeviceCodeManager(t)).
+			SetUserManager(rfc8628.NewMockUserManager(t)).
+			SetTokenManager(rfc8628.NewMockTokenManager(t)).
+			SetVerificationURI("https://example.com/device")
+		v, err := MustVerifier(cfg)
+		require.NoError(t, err)
+		assert.NotNil(t, v)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		v, err := MustVerifier(NewConfig())
+		require.Error(t, err)
+		assert.Nil(t, v)
+	})
+}
+
+func TestVerifier(t *testing.T) {
+	mockUser := &sql.User{UserID: "user-id"}
+
+	newDeviceCode := func() *sql.DeviceCode {
+		return &sql.DeviceCode{
+			DeviceCode: "my-device-code",
+			UserCode:   "WDJBMJHT",
+			ClientID:   "client-id",
+			Status:     string(types.DeviceCodeStatusPending),
+			IssuedAt:   time.Now().UTC(),
+			ExpiresIn:  time.Minute,
+		}
+	}
+
+	newVerifier := func(mgr DeviceCodeManager) *Verifier {
+		return NewVerifier(NewConfig().SetDeviceCodeManager(mgr))
+	}
+
+	t.Run("lookup", func(t *testing.T) {
+		dc := newDeviceCode()
+		mockDeviceCodeMgr := rfc8628.NewMockDeviceCodeManager(t)
+		mockDeviceCodeMgr.On("QueryByUserCode", mock.Anything, "WDJBMJHT").Return(dc, nil).Once()
+
+		v := newVerifier(mockDeviceCodeMgr)
+		code, err := v.Lookup(httptest.NewRequest(http.MethodGet, "/device?user_code=wdjb-mjht", nil), mockUser)
+		require.NoError(t, err)
+		assert.Equal(t, dc, code)
+		assert.True(t, code.GetStatus().IsPending())
+	})
+
+	t.Run("approve", func(t *testing.T) {
+		dc := newDeviceCode()
+		mockDeviceCodeMgr := rfc8628.NewMockDeviceCodeManager(t)
+		mockDeviceCodeMgr.On("QueryByUserCode", mock.Anything, "WDJBMJHT").Return(dc, nil).Once()
+		mockDeviceCodeMgr.On("Update", mock.Anything, dc).Return(nil).Once()
+
+		v := newVerifier(mockDeviceCodeMgr)
+		code, err := v.Approve(newFormRequest(http.MethodPost, "user_code=WDJB-MJHT"), mockUser)
+		require.NoError(t, err)
+		assert.True(t, code.GetStatus().IsApproved())
+		assert.Equal(t, mockUser.UserID, code.GetUserID())
+	})
+
+	t.Run("deny", func(t *testing.T) {
+		dc := newDeviceCode()
+		mockDeviceCodeMgr := rfc8628.NewMockDeviceCodeManager(t)
+		mockDeviceCodeMgr.On("QueryByUserCode", mock.Anything, "WDJBMJHT").Return(dc, nil).Once()
+		mockDeviceCodeMgr.On("Update", mock.Anything, dc).Return(nil).Once()
+
+		v := newVerifier(mockDeviceCodeMgr)
+		code, err := v.Deny(newFormRequest(http.MethodPost, "user_code=wdjbmjht"), mockUser)
+		require.NoError(t, err)
+		assert.True(t, code.GetStatus().IsDenied())
+		assert.Empty(t, code.GetUserID())
+	})
+
+	t.Run("error_when_decision_is_not_confirmed", func(t *testing.T) {
+		v := newVerifier(rfc8628.NewMockDeviceCodeManager(t))
+		_, err := v.Approve(httptest.NewRequest(http.MethodGet, "/device?user_code=WDJB-MJHT", nil), mockUser)
+		assert.ErrorIs(t, err, ErrConfirmationRequired)
+	})
+
+	t.Run("error_when_user_code_in_query_string", func(t *testing.T) {
+		v := newVerifier(rfc8628.NewMockDeviceCodeManager(t))
+		_, err := v.Approve(newFormRequest(http.MethodPost, ""), mockUser)
+		assert.ErrorIs(t, err, ErrInvalidUserCode)
+
+		hr := httptest.NewRequest(http.MethodPost, "/device?user_code=WDJB-MJHT", nil)
+		_, err = v.Approve(hr, mockUser)
+		assert.ErrorIs(t, err, ErrInvalidUserCode)
+	})
+
+	t.Run("error_when_user_is_nil", func(t *testing.T) {
+		v := newVerifier(rfc8628.NewMockDeviceCodeManager(t))
+		_, err := v.Lookup(httptest.NewRequest(http.MethodGet, "/device?user_code=WDJB-MJHT", nil), nil)
+		assert.ErrorIs(t, err, ErrNilUser)
+	})
+
+	t.Run("error_when_user_code_expired", func(t *testing.T) {
+		dc := newDeviceCode()
+		dc.IssuedAt = time.Now().UTC().Add(-2 * time.Minute)
+		mockDeviceCodeMgr := rfc8628.NewMockDeviceCodeManager(t)
+		mockDeviceCodeMgr.On("QueryByUserCode", mock.Anything, "WDJBMJHT").Return(dc, nil).Once()
+
+		v := newVerifier(mockDeviceCodeMgr)
+		_, err := v.Lookup(httptest.NewRequest(http.MethodGet, "/device?user_code=WDJB-MJHT", nil), mockUser)
+		assert.ErrorIs(t, err, ErrExpiredUserCode)
+	})
+
+	t.Run("error_when_user_code_used", func(t *testing.T) {
+		dc := newDeviceCode()
+		dc.Status = string(types.DeviceCodeStatusApproved)
+		mockDeviceCodeMgr := rfc8628.NewMockDeviceCodeManager(t)
+		mockDeviceCodeMgr.On("QueryByUserCode", mock.Anything, "WDJBMJHT").Return(dc, nil).Once()
+
+		v := newVerifier(mockDeviceCodeMgr)
+		_, err := v.Approve(newFormRequest(http.MethodPost, "user_code=WDJB-MJHT"), mockUser)
+		assert.ErrorIs(t, err, ErrUserCodeUsed)
+	})
+
+	t.Run("error_when_too_many_attempts", func(t *testing.T) {
+		mockDeviceCodeMgr := rfc8628.NewMockDeviceCodeManager(t)
+		mockDeviceCodeMgr.On("QueryByUserCode", mock.Anything, "BCDFGHJK").Return(nil, nil).Twice()
+
+		v := newVerifier(mockDeviceCodeMgr)
+		v.SetAttemptLimiter(NewMemoryAttemptLimiter(2, time.Minute))
+		for i := 0; i < 2; i++ {
+			_, err := v.Lookup(httptest.NewRequest(http.MethodGet, "/device?user_code=BCDF-GHJK", nil), mockUser)
+			assert.ErrorIs(t, err, ErrInvalidUserCode)
+		}
+
+		_, err := v.Lookup(httptest.NewRequest(http.MethodGet, "/device?user_code=WDJB-MJHT", nil), mockUser)
+		assert.ErrorIs(t, err, ErrTooManyAttempts)
+	})
+}
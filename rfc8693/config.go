@@ -0,0 +1,166 @@
+package rfc8693
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/tniah/authlib/types"
+	"github.com/tniah/authlib/utils"
+)
+
+// Sentinel errors returned by ValidateConfig when a required dependency is missing.
+var (
+	ErrNilClientManager       = errors.New("client manager is nil")
+	ErrNilTokenValidator      = errors.New("token validator is nil")
+	ErrNilUserManager         = errors.New("user manager is nil")
+	ErrNilTokenManager        = errors.New("token manager is nil")
+	ErrNilExchangePolicy      = errors.New("exchange policy is nil")
+	ErrEmptyClientAuthMethods = errors.New("client auth methods are empty")
+	ErrEmptyTokenTypes        = errors.New("supported token types are empty")
+)
+
+// Config holds all dependencies and extension hooks for the Token Exchange
+// grant. Use NewConfig() to get a config with sensible defaults, then chain
+// Set*/RegisterExtension calls before passing to Must() or New().
+type Config struct {
+	clientMgr      ClientManager
+	tokenValidator TokenValidator
+	userMgr        UserManager
+	tokenMgr       TokenManager
+	policy         ExchangePolicy
+
+	tokenEndpointHttpMethods []string
+
+	// Extension slices are executed in registration order.
+	tokenReqValidators []TokenRequestValidator
+	tokenProcessors    []TokenProcessor
+
+	// supportedClientAuthMethods controls which authentication methods are
+	// accepted at the token endpoint (basic, post, none).
+	supportedClientAuthMethods map[types.ClientAuthMethod]bool
+
+	// supportedTokenTypes lists the subject_token_type and actor_token_type
+	// values TokenValidator understands.
+	supportedTokenTypes map[types.TokenTypeIdentifier]bool
+}
+
+// NewConfig returns a Config with secure defaults:
+//   - Accepts POST on /token.
+//   - Supports basic client authentication only; token exchange is meant for
+//     confidential clients such as backend services.
+//   - Accepts access tokens, ID tokens, and JWTs as subject and actor tokens.
+func NewConfig() *Config {
+	return &Config{
+		supportedClientAuthMethods: map[types.ClientAuthMethod]bool{
+			types.ClientBasicAuthentication: true,
+		},
+		supportedTokenTypes: map[types.TokenTypeIdentifier]bool{
+			types.TokenTypeIdentifierAccessToken: true,
+			types.TokenTypeIdentifierIDToken:     true,
+			types.TokenTypeIdentifierJWT:         true,
+		},
+		tokenEndpointHttpMethods: []string{http.MethodPost},
+		tokenReqValidators:       []TokenRequestValidator{},
+		tokenProcessors:          []TokenProcessor{},
+	}
+}
+
+// SetClientManager sets the client authentication manager.
+func (cfg *Config) SetClientManager(mgr ClientManager) *Config {
+	cfg.clientMgr = mgr
+	return cfg
+}
+
+// SetTokenValidator sets the validator for subject and actor tokens.
+func (cfg *Config) SetTokenValidator(validator TokenValidator) *Config {
+	cfg.tokenValidator = validator
+	return cfg
+}
+
+// SetUserManager sets the user resolver used to look up the subject.
+func (cfg *Config) SetUserManager(mgr UserManager) *Config {
+	cfg.userMgr = mgr
+	return cfg
+}
+
+// SetTokenManager sets the token generation and persistence manager.
+func (cfg *Config) SetTokenManager(mgr TokenManager) *Config {
+	cfg.tokenMgr = mgr
+	return cfg
+}
+
+// SetExchangePolicy sets the policy deciding whether a client may exchange a
+// given subject token. Required: there is no allow-all default.
+func (cfg *Config) SetExchangePolicy(policy ExchangePolicy) *Config {
+	cfg.policy = policy
+	return cfg
+}
+
+// SetTokenEndpointHttpMethods overrides the HTTP methods accepted at /token.
+// Default: [POST].
+func (cfg *Config) SetTokenEndpointHttpMethods(methods []string) *Config {
+	cfg.tokenEndpointHttpMethods = methods
+	return cfg
+}
+
+// SetSupportedClientAuthMethods overrides which client authentication methods
+// are accepted at the token endpoint. Default: basic.
+func (cfg *Config) SetSupportedClientAuthMethods(methods map[types.ClientAuthMethod]bool) *Config {
+	cfg.supportedClientAuthMethods = methods
+	return cfg
+}
+
+// SetSupportedTokenTypes overrides the subject and actor token types accepted.
+// Default: access_token, id_token, and jwt.
+func (cfg *Config) SetSupportedTokenTypes(tokenTypes map[types.TokenTypeIdentifier]bool) *Config {
+	cfg.supportedTokenTypes = tokenTypes
+	return cfg
+}
+
+// RegisterExtension adds ext to every extension slice whose interface it satisfies.
+// A single object may implement both TokenRequestValidator and TokenProcessor.
+func (cfg *Config) RegisterExtension(ext interface{}) *Config {
+	if h, ok := ext.(TokenRequestValidator); ok {
+		cfg.tokenReqValidators = append(cfg.tokenReqValidators, h)
+	}
+
+	if h, ok := ext.(TokenProcessor); ok {
+		cfg.tokenProcessors = append(cfg.tokenProcessors, h)
+	}
+
+	return cfg
+}
+
+// ValidateConfig checks that all required dependencies are set and returns the
+// first sentinel error encountered. Call this via Must() rather than directly.
+func (cfg *Config) ValidateConfig() error {
+	if utils.IsNil(cfg.clientMgr) {
+		return ErrNilClientManager
+	}
+
+	if utils.IsNil(cfg.tokenValidator) {
+		return ErrNilTokenValidator
+	}
+
+	if utils.IsNil(cfg.userMgr) {
+		return ErrNilUserManager
+	}
+
+	if utils.IsNil(cfg.tokenMgr) {
+		return ErrNilTokenManager
+	}
+
+	if utils.IsNil(cfg.policy) {
+		return ErrNilExchangePolicy
+	}
+
+	if len(cfg.supportedClientAuthMethods) == 0 {
+		return ErrEmptyClientAuthMethods
+	}
+
+	if len(cfg.supportedTokenTypes) == 0 {
+		return ErrEmptyTokenTypes
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package rfc8693
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	mock "github.com/tniah/authlib/mocks/rfc8693"
+	"github.com/tniah/authlib/types"
+)
+
+func TestConfig(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		cfg := NewConfig()
+		assert.Equal(t, []string{http.MethodPost}, cfg.tokenEndpointHttpMethods)
+		assert.Equal(t, map[types.ClientAuthMethod]bool{types.ClientBasicAuthentication: true}, cfg.supportedClientAuthMethods)
+		assert.Equal(t, map[types.TokenTypeIdentifier]bool{
+			types.TokenTypeIdentifierAccessToken: true,
+			types.TokenTypeIdentifierIDToken:     true,
+			types.TokenTypeIdentifierJWT:         true,
+		}, cfg.supportedTokenTypes)
+
+		clientMgr := mock.NewMockClientManager(t)
+		tokenValidator := mock.NewMockTokenValidator(t)
+		userMgr := mock.NewMockUserManager(t)
+		tokenMgr := mock.NewMockTokenManager(t)
+		policy := mock.NewMockExchangePolicy(t)
+		validator := mock.NewMockTokenRequestValidator(t)
+		processor := mock.NewMockTokenProcessor(t)
+
+		cfg.SetClientManager(clientMgr).
+			SetTokenValidator(tokenValidator).
+			SetUserManager(userMgr).
+			SetTokenManager(tokenMgr).
+			SetExchangePolicy(policy).
+			SetTokenEndpointHttpMethods([]string{http.MethodPut}).
+			SetSupportedClientAuthMethods(map[types.ClientAuthMethod]bool{types.ClientPostAuthentication: true}).
+			SetSupportedTokenTypes(map[types.TokenTypeIdentifier]bool{types.TokenTypeIdentifierJWT: true}).
+			RegisterExtension(validator).
+			RegisterExtension(processor)
+
+		assert.Equal(t, clientMgr, cfg.clientMgr)
+		assert.Equal(t, tokenValidator, cfg.tokenValidator)
+		assert.Equal(t, userMgr, cfg.userMgr)
+		assert.Equal(t, tokenMgr, cfg.tokenMgr)
+		assert.Equal(t, policy, cfg.policy)
+		assert.Equal(t, []string{http.MethodPut}, cfg.tokenEndpointHttpMethods)
+		assert.Equal(t, map[types.ClientAuthMethod]bool{types.ClientPostAuthentication: true}, cfg.supportedClientAuthMethods)
+		assert.Equal(t, map[types.TokenTypeIdentifier]bool{types.TokenTypeIdentifierJWT: true}, cfg.supportedTokenTypes)
+		assert.Equal(t, []TokenRequestValidator{validator}, cfg.tokenReqValidators)
+		assert.Equal(t, []TokenProcessor{processor}, cfg.tokenProcessors)
+		assert.NoError(t, cfg.ValidateConfig())
+	})
+
+	t.Run("error", func(t *testing.T) {
+		cfg := NewConfig()
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrNilClientManager)
+
+		cfg.SetClientManager(mock.NewMockClientManager(t))
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrNilTokenValidator)
+
+		cfg.SetTokenValidator(mock.NewMockTokenValidator(t))
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrNilUserManager)
+
+		cfg.SetUserManager(mock.NewMockUserManager(t))
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrNilTokenManager)
+
+		cfg.SetTokenManager(mock.NewMockTokenManager(t))
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrNilExchangePolicy)
+
+		cfg.SetExchangePolicy(mock.NewMockExchangePolicy(t))
+		cfg.SetSupportedClientAuthMethods(nil)
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrEmptyClientAuthMethods)
+
+		cfg.SetSupportedClientAuthMethods(map[types.ClientAuthMethod]bool{types.ClientBasicAuthentication: true})
+		cfg.SetSupportedTokenTypes(nil)
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrEmptyTokenTypes)
+	})
+}
@@ -0,0 +1,322 @@
+package rfc8693
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	autherrors "github.com/tniah/authlib/errors"
+	"github.com/tniah/authlib/models"
+	"github.com/tniah/authlib/requests"
+	"github.com/tniah/authlib/rfc6749"
+	"github.com/tniah/authlib/types"
+	"github.com/tniah/authlib/utils"
+)
+
+// EndpointToken is the endpoint name passed to ClientManager.Authenticate so
+// that the client store can apply per-endpoint auth method policies.
+const EndpointToken = "token"
+
+// ErrNilToken is returned by genToken when TokenManager.New returns nil.
+var ErrNilToken = errors.New("token is nil")
+
+// Flow implements the Token Exchange grant (RFC 8693). A client presents a
+// subject token, and optionally an actor token, and receives a new access
+// token for the requested resource or audience.
+//
+// Without an actor token the exchange is impersonation: the issued token
+// represents the subject alone. With one it is delegation: the issued token
+// represents the subject and names the actor in its act claim, nested on top
+// of any act claim the subject token already carried (RFC 8693 §1.1, §4.1).
+type Flow struct {
+	*Config
+	*rfc6749.TokenFlowMixin
+}
+
+// New creates a Flow without validating config. Use Must for production use.
+func New(cfg *Config) *Flow {
+	return &Flow{Config: cfg, TokenFlowMixin: &rfc6749.TokenFlowMixin{}}
+}
+
+// Must returns a validated Flow or an error if the config is incomplete.
+func Must(cfg *Config) (*Flow, error) {
+	if err := cfg.ValidateConfig(); err != nil {
+		return nil, err
+	}
+
+	return New(cfg), nil
+}
+
+// CheckGrantType reports whether this flow handles the given grant_type.
+func (f *Flow) CheckGrantType(gt types.GrantType) bool {
+	return gt.IsTokenExchange()
+}
+
+// ProvideMetadata adds the token exchange grant and the client authentication
+// methods accepted at the token endpoint to the authorization server metadata
+// (RFC 8414 §2).
+func (f *Flow) ProvideMetadata(md types.Metadata) {
+	md.Add(types.MetadataGrantTypesSupported, types.GrantTypeTokenExchange.String())
+	md.AddClientAuthMethods(types.MetadataTokenEndpointAuthMethodsSupported, f.supportedClientAuthMethods)
+}
+
+// ValidateTokenRequest validates the /token request: HTTP method, grant_type,
+// token exchange parameters, client authentication, the subject and actor
+// tokens, scope, the ExchangePolicy, the subject user, and any registered
+// TokenRequestValidator extensions.
+func (f *Flow) ValidateTokenRequest(r *requests.TokenRequest) error {
+	if err := f.checkParams(r); err != nil {
+		return err
+	}
+
+	if err := f.authenticateClient(r); err != nil {
+		return err
+	}
+
+	if err := f.validateTokens(r); err != nil {
+		return err
+	}
+
+	if err := f.validateScope(r); err != nil {
+		return err
+	}
+
+	if err := f.policy.CheckExchange(r); err != nil {
+		return err
+	}
+
+	if err := f.queryUserBySubject(r); err != nil {
+		return err
+	}
+
+	r.Act = actClaim(r.Subject, r.Actor)
+
+	for _, h := range f.tokenReqValidators {
+		if err := h.ValidateTokenRequest(r); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// TokenResponse issues the access token, runs TokenProcessor extensions,
+// persists the token, and writes the JSON response including
+// issued_token_type (RFC 8693 §2.2.1). No refresh token is issued.
+func (f *Flow) TokenResponse(r *requests.TokenRequest, rw http.ResponseWriter) error {
+	token, err := f.genToken(r)
+	if err != nil {
+		return err
+	}
+
+	data := f.StandardTokenData(token)
+	data["issued_token_type"] = types.TokenTypeIdentifierAccessToken
+	for _, h := range f.tokenProcessors {
+		if err = h.ProcessToken(r, token, data); err != nil {
+			return err
+		}
+	}
+
+	if err = f.tokenMgr.Save(r.Request.Context(), token); err != nil {
+		return err
+	}
+
+	return f.HandleTokenResponse(rw, data)
+}
+
+// checkParams validates the HTTP method, grant_type, and the token exchange
+// parameters before any manager calls are made.
+func (f *Flow) checkParams(r *requests.TokenRequest) error {
+	if err := f.checkTokenEndpointHttpMethod(r); err != nil {
+		return err
+	}
+
+	if err := f.validateGrantType(r); err != nil {
+		return err
+	}
+
+	if err := r.ValidateSubjectToken(); err != nil {
+		return err
+	}
+
+	if err := r.ValidateActorToken(); err != nil {
+		return err
+	}
+
+	if err := r.ValidateResources(); err != nil {
+		return err
+	}
+
+	// Only access tokens are issued; an empty requested_token_type defaults
+	// to it.
+	if rt := r.RequestedTokenType; !rt.IsEmpty() && !rt.IsAccessToken() {
+		return autherrors.InvalidRequestError().WithDescription(fmt.Sprintf("unsupported \"requested_token_type\" \"%s\"", rt))
+	}
+
+	return nil
+}
+
+// checkTokenEndpointHttpMethod rejects requests whose HTTP method is not in
+// tokenEndpointHttpMethods (default: POST).
+func (f *Flow) checkTokenEndpointHttpMethod(r *requests.TokenRequest) error {
+	for _, method := range f.tokenEndpointHttpMethods {
+		if r.Method() == method {
+			return nil
+		}
+	}
+
+	return autherrors.InvalidRequestError().WithDescription(fmt.Sprintf("unsupported http method \"%s\"", r.Method()))
+}
+
+// validateGrantType checks that grant_type is present and equals
+// "urn:ietf:params:oauth:grant-type:token-exchange".
+func (f *Flow) validateGrantType(r *requests.TokenRequest) error {
+	if err := r.ValidateGrantType(); err != nil {
+		return err
+	}
+
+	if valid := r.GrantType.IsTokenExchange(); !valid {
+		return autherrors.UnsupportedGrantTypeError()
+	}
+
+	return nil
+}
+
+// authenticateClient delegates to ClientManager.Authenticate, then verifies the
+// client is permitted to use the token exchange grant.
+func (f *Flow) authenticateClient(r *requests.TokenRequest) error {
+	client, err := f.clientMgr.Authenticate(r.Request, f.supportedClientAuthMethods, EndpointToken)
+	if err != nil {
+		return err
+	}
+
+	if utils.IsNil(client) {
+		return autherrors.InvalidClientError()
+	}
+
+	if allowed := client.CheckGrantType(types.GrantTypeTokenExchange); !allowed {
+		return autherrors.UnauthorizedClientError().WithDescription("The client is not authorized to use grant type \"urn:ietf:params:oauth:grant-type:token-exchange\"")
+	}
+
+	r.Client = client
+	return nil
+}
+
+// validateTokens validates the subject token and, when present, the actor
+// token, and populates r.Subject and r.Actor.
+func (f *Flow) validateTokens(r *requests.TokenRequest) error {
+	subject, err := f.validateToken(r, r.SubjectToken, r.SubjectTokenType, "subject_token")
+	if err != nil {
+		return err
+	}
+
+	r.Subject = subject
+	if r.ActorToken == "" {
+		return nil
+	}
+
+	actor, err := f.validateToken(r, r.ActorToken, r.ActorTokenType, "actor_token")
+	if err != nil {
+		return err
+	}
+
+	r.Actor = actor
+	return nil
+}
+
+// validateToken checks that tokenType is supported and delegates to
+// TokenValidator. Invalid tokens are rejected with invalid_request
+// (RFC 8693 §2.2.2).
+func (f *Flow) validateToken(r *requests.TokenRequest, token string, tokenType types.TokenTypeIdentifier, param string) (*requests.ExchangeToken, error) {
+	if !f.supportedTokenTypes[tokenType] {
+		return nil, autherrors.InvalidRequestError().WithDescription(fmt.Sprintf("unsupported \"%s_type\" \"%s\"", param, tokenType))
+	}
+
+	info, err := f.tokenValidator.ValidateToken(r.Request.Context(), token, tokenType, r)
+	if err != nil {
+		return nil, err
+	}
+
+	if utils.IsNil(info) {
+		return nil, autherrors.InvalidRequestError().WithDescription(fmt.Sprintf("invalid \"%s\"", param))
+	}
+
+	return info, nil
+}
+
+// validateScope prevents escalation: the requested scope must not exceed the
+// scope of the subject token, when it has one, and is narrowed to the client's
+// allowed scopes. When the scope parameter is omitted, the subject token's
+// scope is requested instead, and is narrowed the same way.
+func (f *Flow) validateScope(r *requests.TokenRequest) error {
+	granted := r.Subject.Scopes
+	if len(r.Scopes) == 0 {
+		r.Scopes = granted
+	} else if len(granted) > 0 {
+		for _, scope := range r.Scopes {
+			if !granted.Contain(scope) {
+				return autherrors.InvalidScopeError().WithDescription("the requested scope exceeds the scope of the \"subject_token\"")
+			}
+		}
+	}
+
+	if len(r.Scopes) == 0 {
+		return nil
+	}
+
+	allowed := r.Client.GetAllowedScopes(r.Scopes)
+	if len(allowed) == 0 {
+		return autherrors.InvalidScopeError().WithDescription("none of the requested scopes are permitted for this client")
+	}
+
+	r.Scopes = allowed
+	return nil
+}
+
+// queryUserBySubject resolves the user the subject token represents and
+// populates r.User. Returns invalid_request if no user is found.
+func (f *Flow) queryUserBySubject(r *requests.TokenRequest) error {
+	user, err := f.userMgr.QueryUserBySubject(r.Request.Context(), r)
+	if err != nil {
+		return err
+	}
+
+	if utils.IsNil(user) {
+		return autherrors.InvalidRequestError().WithDescription("No user could be found associated with the \"subject_token\"")
+	}
+
+	r.User = user
+	return nil
+}
+
+// genToken allocates and populates a new access token.
+func (f *Flow) genToken(r *requests.TokenRequest) (models.Token, error) {
+	token := f.tokenMgr.New()
+	if utils.IsNil(token) {
+		return nil, ErrNilToken
+	}
+
+	if err := f.tokenMgr.Generate(token, r, false); err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// actClaim builds the act claim of the issued token. For delegation the actor
+// becomes the current actor and the subject token's chain is nested under it,
+// so the least recent actor is the most deeply nested (RFC 8693 §4.1). For
+// impersonation the subject token's chain is kept as is, so that exchanging a
+// delegated token does not hide its actors.
+func actClaim(subject, actor *requests.ExchangeToken) map[string]interface{} {
+	if actor == nil {
+		return subject.Act
+	}
+
+	act := map[string]interface{}{"sub": actor.Subject}
+	if subject.Act != nil {
+		act["act"] = subject.Act
+	}
+
+	return act
+}
@@ -0,0 +1,318 @@
+package rfc8693
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	autherrors "github.com/tniah/authlib/errors"
+	"github.com/tniah/authlib/integrations/sql"
+	rfc8693 "github.com/tniah/authlib/mocks/rfc8693"
+	"github.com/tniah/authlib/requests"
+	"github.com/tniah/authlib/types"
+)
+
+func TestFlow_Must(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		f, err := Must(NewConfig().
+			SetClientManager(rfc8693.NewMockClientManager(t)).
+			SetTokenValidator(rfc8693.NewMockTokenValidator(t)).
+			SetUserManager(rfc8693.NewMockUserManager(t)).
+			SetTokenManager(rfc8693.NewMockTokenManager(t)).
+			SetExchangePolicy(rfc8693.NewMockExchangePolicy(t)))
+		require.NoError(t, err)
+		assert.NotNil(t, f)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		f, err := Must(NewConfig())
+		require.Error(t, err)
+		assert.Nil(t, f)
+	})
+}
+
+func TestFlow_CheckGrantType(t *testing.T) {
+	f := New(NewConfig())
+	assert.True(t, f.CheckGrantType(types.GrantTypeTokenExchange))
+	assert.False(t, f.CheckGrantType(types.GrantTypeClientCredentials))
+	assert.False(t, f.CheckGrantType(types.NewGrantType("")))
+}
+
+func TestFlow_checkParams(t *testing.T) {
+	f := New(NewConfig())
+	newRequest := func() *requests.TokenRequest {
+		return &requests.TokenRequest{
+			Request:          httptest.NewRequest(http.MethodPost, "/", nil),
+			GrantType:        types.GrantTypeTokenExchange,
+			SubjectToken:     "subject",
+			SubjectTokenType: types.TokenTypeIdentifierAccessToken,
+		}
+	}
+
+	t.Run("success", func(t *testing.T) {
+		assert.NoError(t, f.checkParams(newRequest()))
+
+		r := newRequest()
+		r.RequestedTokenType = types.TokenTypeIdentifierAccessToken
+		assert.NoError(t, f.checkParams(r))
+	})
+
+	t.Run("error", func(t *testing.T) {
+		cases := []struct {
+			modify func(r *requests.TokenRequest)
+			error  error
+		}{
+			{func(r *requests.TokenRequest) { r.Request = httptest.NewRequest(http.MethodGet, "/", nil) }, autherrors.ErrInvalidRequest},
+			{func(r *requests.TokenRequest) { r.GrantType = types.GrantTypeRefreshToken }, autherrors.ErrUnsupportedGrantType},
+			{func(r *requests.TokenRequest) { r.SubjectToken = "" }, autherrors.ErrInvalidRequest},
+			{func(r *requests.TokenRequest) { r.ActorToken = "actor" }, autherrors.ErrInvalidRequest},
+			{func(r *requests.TokenRequest) { r.Resources = []string{"orders"} }, autherrors.ErrInvalidTarget},
+			{func(r *requests.TokenRequest) { r.RequestedTokenType = types.TokenTypeIdentifierRefreshToken }, autherrors.ErrInvalidRequest},
+		}
+
+		for i, c := range cases {
+			r := newRequest()
+			c.modify(r)
+			err := f.checkParams(r)
+			assert.Equalf(t, c.error, autherrors.ToAuthLibError(err).Code, "case %d failed", i)
+		}
+	})
+}
+
+func TestFlow_ValidateTokenRequest(t *testing.T) {
+	mockClient := &sql.Client{
+		ClientID:   "gateway",
+		GrantTypes: []string{string(types.GrantTypeTokenExchange)},
+		Scopes:     []string{"orders:read", "orders:write"},
+	}
+	mockUser := &sql.User{UserID: "alice"}
+
+	newRequest := func() *requests.TokenRequest {
+		return &requests.TokenRequest{
+			Request:          httptest.NewRequest(http.MethodPost, "/", nil),
+			GrantType:        types.GrantTypeTokenExchange,
+			SubjectToken:     "subject",
+			SubjectTokenType: types.TokenTypeIdentifierAccessToken,
+			Audiences:        []string{"orders"},
+		}
+	}
+
+	type mocks struct {
+		validator *rfc8693.MockTokenValidator
+		userMgr   *rfc8693.MockUserManager
+		policy    *rfc8693.MockExchangePolicy
+	}
+
+	newFlow := func(t *testing.T) (*Flow, mocks) {
+		mockClientMgr := rfc8693.NewMockClientManager(t)
+		mockClientMgr.On("Authenticate", mock.AnythingOfType("*http.Request"), mock.AnythingOfType("map[types.ClientAuthMethod]bool"), EndpointToken).Return(mockClient, nil).Once()
+
+		m := mocks{
+			validator: rfc8693.NewMockTokenValidator(t),
+			userMgr:   rfc8693.NewMockUserManager(t),
+			policy:    rfc8693.NewMockExchangePolicy(t),
+		}
+		f := New(NewConfig().
+			SetClientManager(mockClientMgr).
+			SetTokenValidator(m.validator).
+			SetUserManager(m.userMgr).
+			SetExchangePolicy(m.policy))
+		return f, m
+	}
+
+	t.Run("impersonation", func(t *testing.T) {
+		subject := &requests.ExchangeToken{Subject: "alice", Scopes: types.Scopes{"orders:read", "profile"}}
+		f, m := newFlow(t)
+		m.validator.On("ValidateToken", mock.Anything, "subject", types.TokenTypeIdentifierAccessToken, mock.Anything).Return(subject, nil).Once()
+		m.policy.On("CheckExchange", mock.MatchedBy(func(r *requests.TokenRequest) bool {
+			return r.Subject == subject && r.Actor == nil
+		})).Return(nil).Once()
+		m.userMgr.On("QueryUserBySubject", mock.Anything, mock.Anything).Return(mockUser, nil).Once()
+
+		r := newRequest()
+		r.Scopes = types.Scopes{"orders:read"}
+		require.NoError(t, f.ValidateTokenRequest(r))
+		assert.Equal(t, mockClient, r.Client)
+		assert.Equal(t, mockUser, r.User)
+		assert.Equal(t, types.Scopes{"orders:read"}, r.Scopes)
+		assert.Nil(t, r.Act)
+	})
+
+	t.Run("delegation_nests_act", func(t *testing.T) {
+		subject := &requests.ExchangeToken{
+			Subject: "alice",
+			Act:     map[string]interface{}{"sub": "frontend"},
+		}
+		actor := &requests.ExchangeToken{Subject: "gateway"}
+		f, m := newFlow(t)
+		m.validator.On("ValidateToken", mock.Anything, "subject", types.TokenTypeIdentifierAccessToken, mock.Anything).Return(subject, nil).Once()
+		m.validator.On("ValidateToken", mock.Anything, "actor", types.TokenTypeIdentifierJWT, mock.Anything).Return(actor, nil).Once()
+		m.policy.On("CheckExchange", mock.MatchedBy(func(r *requests.TokenRequest) bool {
+			return r.Subject == subject && r.Actor == actor
+		})).Return(nil).Once()
+		m.userMgr.On("QueryUserBySubject", mock.Anything, mock.Anything).Return(mockUser, nil).Once()
+
+		r := newRequest()
+		r.ActorToken = "actor"
+		r.ActorTokenType = types.TokenTypeIdentifierJWT
+		require.NoError(t, f.ValidateTokenRequest(r))
+		assert.Equal(t, map[string]interface{}{
+			"sub": "gateway",
+			"act": map[string]interface{}{"sub": "frontend"},
+		}, r.Act)
+	})
+
+	t.Run("impersonation_keeps_existing_act", func(t *testing.T) {
+		subject := &requests.ExchangeToken{Subject: "alice", Act: map[string]interface{}{"sub": "frontend"}}
+		f, m := newFlow(t)
+		m.validator.On("ValidateToken", mock.Anything, "subject", types.TokenTypeIdentifierAccessToken, mock.Anything).Return(subject, nil).Once()
+		m.policy.On("CheckExchange", mock.Anything).Return(nil).Once()
+		m.userMgr.On("QueryUserBySubject", mock.Anything, mock.Anything).Return(mockUser, nil).Once()
+
+		r := newRequest()
+		require.NoError(t, f.ValidateTokenRequest(r))
+		assert.Equal(t, map[string]interface{}{"sub": "frontend"}, r.Act)
+	})
+
+	t.Run("error_when_subject_token_type_unsupported", func(t *testing.T) {
+		f, _ := newFlow(t)
+		r := newRequest()
+		r.SubjectTokenType = types.TokenTypeIdentifierSAML2
+
+		err := f.ValidateTokenRequest(r)
+		assert.Equal(t, autherrors.ErrInvalidRequest, autherrors.ToAuthLibError(err).Code)
+	})
+
+	t.Run("error_when_subject_token_invalid", func(t *testing.T) {
+		f, m := newFlow(t)
+		m.validator.On("ValidateToken", mock.Anything, "subject", types.TokenTypeIdentifierAccessToken, mock.Anything).Return(nil, nil).Once()
+
+		err := f.ValidateTokenRequest(newRequest())
+		assert.Equal(t, autherrors.ErrInvalidRequest, autherrors.ToAuthLibError(err).Code)
+	})
+
+	t.Run("error_when_actor_token_invalid", func(t *testing.T) {
+		f, m := newFlow(t)
+		m.validator.On("ValidateToken", mock.Anything, "subject", types.TokenTypeIdentifierAccessToken, mock.Anything).Return(&requests.ExchangeToken{Subject: "alice"}, nil).Once()
+		m.validator.On("ValidateToken", mock.Anything, "actor", types.TokenTypeIdentifierJWT, mock.Anything).Return(nil, nil).Once()
+
+		r := newRequest()
+		r.ActorToken = "actor"
+		r.ActorTokenType = types.TokenTypeIdentifierJWT
+		err := f.ValidateTokenRequest(r)
+		assert.Equal(t, autherrors.ErrInvalidRequest, autherrors.ToAuthLibError(err).Code)
+	})
+
+	t.Run("error_when_scope_exceeds_subject_token", func(t *testing.T) {
+		f, m := newFlow(t)
+		m.validator.On("ValidateToken", mock.Anything, "subject", types.TokenTypeIdentifierAccessToken, mock.Anything).Return(&requests.ExchangeToken{Subject: "alice", Scopes: types.Scopes{"orders:read"}}, nil).Once()
+
+		r := newRequest()
+		r.Scopes = types.Scopes{"orders:write"}
+		err := f.ValidateTokenRequest(r)
+		assert.Equal(t, autherrors.ErrInvalidScope, autherrors.ToAuthLibError(err).Code)
+	})
+
+	t.Run("omitted_scope_drops_subject_scopes_client_is_not_allowed", func(t *testing.T) {
+		subject := &requests.ExchangeToken{Subject: "alice", Scopes: types.Scopes{"orders:read", "admin"}}
+		f, m := newFlow(t)
+		m.validator.On("ValidateToken", mock.Anything, "subject", types.TokenTypeIdentifierAccessToken, mock.Anything).Return(subject, nil).Once()
+		m.policy.On("CheckExchange", mock.Anything).Return(nil).Once()
+		m.userMgr.On("QueryUserBySubject", mock.Anything, mock.Anything).Return(mockUser, nil).Once()
+
+		r := newRequest()
+		require.NoError(t, f.ValidateTokenRequest(r))
+		assert.Equal(t, types.Scopes{"orders:read"}, r.Scopes)
+	})
+
+	t.Run("error_when_omitted_scope_leaves_no_allowed_scope", func(t *testing.T) {
+		f, m := newFlow(t)
+		m.validator.On("ValidateToken", mock.Anything, "subject", types.TokenTypeIdentifierAccessToken, mock.Anything).Return(&requests.ExchangeToken{Subject: "alice", Scopes: types.Scopes{"admin"}}, nil).Once()
+
+		err := f.ValidateTokenRequest(newRequest())
+		assert.Equal(t, autherrors.ErrInvalidScope, autherrors.ToAuthLibError(err).Code)
+	})
+
+	t.Run("error_when_policy_rejects", func(t *testing.T) {
+		f, m := newFlow(t)
+		m.validator.On("ValidateToken", mock.Anything, "subject", types.TokenTypeIdentifierAccessToken, mock.Anything).Return(&requests.ExchangeToken{Subject: "alice"}, nil).Once()
+		m.policy.On("CheckExchange", mock.Anything).Return(autherrors.InvalidTargetError()).Once()
+
+		err := f.ValidateTokenRequest(newRequest())
+		assert.Equal(t, autherrors.ErrInvalidTarget, autherrors.ToAuthLibError(err).Code)
+	})
+
+	t.Run("error_when_user_not_found", func(t *testing.T) {
+		f, m := newFlow(t)
+		m.validator.On("ValidateToken", mock.Anything, "subject", types.TokenTypeIdentifierAccessToken, mock.Anything).Return(&requests.ExchangeToken{Subject: "alice"}, nil).Once()
+		m.policy.On("CheckExchange", mock.Anything).Return(nil).Once()
+		m.userMgr.On("QueryUserBySubject", mock.Anything, mock.Anything).Return(nil, nil).Once()
+
+		err := f.ValidateTokenRequest(newRequest())
+		assert.Equal(t, autherrors.ErrInvalidRequest, autherrors.ToAuthLibError(err).Code)
+	})
+
+	t.Run("error_when_grant_type_not_allowed", func(t *testing.T) {
+		mockClientMgr := rfc8693.NewMockClientManager(t)
+		mockClientMgr.On("Authenticate", mock.Anything, mock.Anything, EndpointToken).Return(&sql.Client{ClientID: "other"}, nil).Once()
+
+		f := New(NewConfig().SetClientManager(mockClientMgr))
+		err := f.ValidateTokenRequest(newRequest())
+		assert.Equal(t, autherrors.ErrUnauthorizedClient, autherrors.ToAuthLibError(err).Code)
+	})
+
+	t.Run("error_from_extension", func(t *testing.T) {
+		f, m := newFlow(t)
+		m.validator.On("ValidateToken", mock.Anything, "subject", types.TokenTypeIdentifierAccessToken, mock.Anything).Return(&requests.ExchangeToken{Subject: "alice"}, nil).Once()
+		m.policy.On("CheckExchange", mock.Anything).Return(nil).Once()
+		m.userMgr.On("QueryUserBySubject", mock.Anything, mock.Anything).Return(mockUser, nil).Once()
+
+		validator := rfc8693.NewMockTokenRequestValidator(t)
+		validator.On("ValidateTokenRequest", mock.Anything).Return(errors.New("rejected")).Once()
+		f.RegisterExtension(validator)
+
+		err := f.ValidateTokenRequest(newRequest())
+		assert.EqualError(t, err, "rejected")
+	})
+}
+
+func TestFlow_TokenResponse(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		r := &requests.TokenRequest{
+			Request:   httptest.NewRequest(http.MethodPost, "/", nil),
+			GrantType: types.GrantTypeTokenExchange,
+		}
+		token := &sql.Token{AccessToken: "exchanged", TokenType: "Bearer"}
+
+		mockTokenMgr := rfc8693.NewMockTokenManager(t)
+		mockTokenMgr.On("New").Return(token).Once()
+		mockTokenMgr.On("Generate", token, r, false).Return(nil).Once()
+		mockTokenMgr.On("Save", mock.Anything, token).Return(nil).Once()
+
+		f := New(NewConfig().SetTokenManager(mockTokenMgr))
+		rw := httptest.NewRecorder()
+		require.NoError(t, f.TokenResponse(r, rw))
+		assert.Equal(t, http.StatusOK, rw.Code)
+
+		var data map[string]interface{}
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &data))
+		assert.Equal(t, "exchanged", data["access_token"])
+		assert.Equal(t, "Bearer", data["token_type"])
+		assert.Equal(t, string(types.TokenTypeIdentifierAccessToken), data["issued_token_type"])
+		assert.NotContains(t, data, "refresh_token")
+	})
+
+	t.Run("error_when_token_is_nil", func(t *testing.T) {
+		mockTokenMgr := rfc8693.NewMockTokenManager(t)
+		mockTokenMgr.On("New").Return(nil).Once()
+
+		f := New(NewConfig().SetTokenManager(mockTokenMgr))
+		err := f.TokenResponse(&requests.TokenRequest{Request: httptest.NewRequest(http.MethodPost, "/", nil)}, httptest.NewRecorder())
+		assert.ErrorIs(t, err, ErrNilToken)
+	})
+}
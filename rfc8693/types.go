@@ -0,0 +1,76 @@
+package rfc8693
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/tniah/authlib/models"
+	"github.com/tniah/authlib/requests"
+	"github.com/tniah/authlib/types"
+)
+
+// ClientManager authenticates the client at the token endpoint.
+type ClientManager interface {
+	// Authenticate verifies the client credentials and returns the authenticated
+	// client. endpointName identifies the endpoint being accessed (used for
+	// method-specific logic in multi-endpoint setups).
+	Authenticate(r *http.Request, authMethods map[types.ClientAuthMethod]bool, endpointName string) (models.Client, error)
+}
+
+// TokenValidator validates subject and actor tokens: signature, issuer,
+// expiry, revocation, and anything else the token type requires.
+type TokenValidator interface {
+	// ValidateToken returns what token represents. Return (nil, nil) when the
+	// token is invalid, expired, or unknown; the request is then rejected with
+	// invalid_request (RFC 8693 §2.2.2).
+	ValidateToken(ctx context.Context, token string, tokenType types.TokenTypeIdentifier, r *requests.TokenRequest) (*requests.ExchangeToken, error)
+}
+
+// UserManager resolves the user the subject token represents.
+type UserManager interface {
+	// QueryUserBySubject retrieves the user matching r.Subject.Subject.
+	// Return (nil, nil) when it does not exist.
+	QueryUserBySubject(ctx context.Context, r *requests.TokenRequest) (models.User, error)
+}
+
+// TokenManager generates and persists the issued token.
+type TokenManager interface {
+	// New allocates a blank Token ready to be populated by Generate.
+	New() models.Token
+
+	// Generate populates token with a value, expiry, scopes, and client/user
+	// binding. includeRefreshToken is always false: exchanged tokens are
+	// meant to be short-lived and audience-restricted.
+	Generate(token models.Token, r *requests.TokenRequest, includeRefreshToken bool) error
+
+	// Save persists the token to the backing store.
+	Save(ctx context.Context, token models.Token) error
+}
+
+// ExchangePolicy decides whether an exchange is allowed. It is called after
+// both tokens are validated and the scope is resolved, with r.Client,
+// r.Subject, and r.Actor set.
+//
+// r.Actor is nil for impersonation, where the issued token represents the
+// subject alone; it is set for delegation, where the issued token records the
+// actor in its act claim. r.Resources and r.Audiences name the target
+// services. r.Subject.MayAct is not enforced by the flow; check it here when
+// the subject tokens carry it.
+type ExchangePolicy interface {
+	// CheckExchange returns nil to allow the exchange. Return an
+	// *errors.AuthLibError such as access_denied, invalid_target, or
+	// invalid_scope to reject it.
+	CheckExchange(r *requests.TokenRequest) error
+}
+
+// TokenRequestValidator is an extension hook called during
+// ValidateTokenRequest, after the built-in checks pass.
+type TokenRequestValidator interface {
+	ValidateTokenRequest(r *requests.TokenRequest) error
+}
+
+// TokenProcessor is an extension hook called after the token is generated and
+// before it is saved. Use it to add extra fields to the token response.
+type TokenProcessor interface {
+	ProcessToken(r *requests.TokenRequest, token models.Token, data map[string]interface{}) error
+}
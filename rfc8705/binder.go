@@ -0,0 +1,120 @@
+package rfc8705
+
+import (
+	"crypto/subtle"
+
+	autherrors "github.com/tniah/authlib/errors"
+	"github.com/tniah/authlib/models"
+	"github.com/tniah/authlib/requests"
+	"github.com/tniah/authlib/types"
+	"github.com/tniah/authlib/utils"
+)
+
+// CertificateBinder binds issued access tokens to the certificate the client
+// presented over mutual TLS (RFC 8705 §3). It implements the
+// TokenRequestValidator and TokenProcessor extension interfaces; register it
+// with each grant via cfg.RegisterExtension.
+type CertificateBinder struct {
+	*Options
+}
+
+// New returns a CertificateBinder with the given Options, or defaults if none
+// are provided.
+func New(opts ...*Options) *CertificateBinder {
+	if len(opts) > 0 && opts[0] != nil {
+		return &CertificateBinder{opts[0]}
+	}
+
+	defaultOpts := NewOptions()
+	return &CertificateBinder{defaultOpts}
+}
+
+// ProvideMetadata adds tls_client_certificate_bound_access_tokens to the
+// authorization server metadata (RFC 8705 §3.3).
+func (b *CertificateBinder) ProvideMetadata(md types.Metadata) {
+	md.Set(types.MetadataTLSClientCertificateBoundAccessTokens, true)
+}
+
+// ValidateTokenRequest sets the x5t#S256 thumbprint of the client certificate
+// in r.Confirmation, so that token generators embed it as the cnf claim. A
+// request without a certificate is rejected when binding is required for the
+// server or the client. When a public client refreshes a certificate-bound
+// token, the certificate must be the one the token is bound to (RFC 8705 §4).
+func (b *CertificateBinder) ValidateTokenRequest(r *requests.TokenRequest) error {
+	cert := PeerCertificate(r.Request)
+	if cert == nil {
+		if b.required || clientRequiresBinding(r.Client) {
+			return autherrors.InvalidRequestError().WithDescription("a client certificate is required to obtain certificate-bound access tokens")
+		}
+
+		if boundThumbprint(r.Client, r.Token) != "" {
+			return autherrors.InvalidGrantError().WithDescription("refresh token is bound to a client certificate")
+		}
+
+		return nil
+	}
+
+	thumbprint := Thumbprint(cert)
+	if bound := boundThumbprint(r.Client, r.Token); bound != "" && subtle.ConstantTimeCompare([]byte(bound), []byte(thumbprint)) != 1 {
+		return autherrors.InvalidGrantError().WithDescription("refresh token is bound to a different client certificate")
+	}
+
+	if r.Confirmation == nil {
+		r.Confirmation = make(map[string]interface{})
+	}
+
+	r.Confirmation[ConfirmationX5tS256] = thumbprint
+	return nil
+}
+
+// ProcessToken records the confirmation in the token's extra data under cnf
+// so that it is persisted and returned by token introspection (RFC 8705
+// §3.2). It is a no-op for tokens that do not implement
+// models.ExtendableToken.
+func (b *CertificateBinder) ProcessToken(r *requests.TokenRequest, token models.Token, _ map[string]interface{}) error {
+	if len(r.Confirmation) == 0 {
+		return nil
+	}
+
+	ext, ok := token.(models.ExtendableToken)
+	if !ok {
+		return nil
+	}
+
+	data := ext.GetExtraData()
+	if data == nil {
+		data = make(map[string]interface{})
+	}
+
+	data["cnf"] = r.Confirmation
+	ext.SetExtraData(data)
+	return nil
+}
+
+// clientRequiresBinding reports whether client registered
+// tls_client_certificate_bound_access_tokens.
+func clientRequiresBinding(client models.Client) bool {
+	if utils.IsNil(client) {
+		return false
+	}
+
+	p, ok := client.(CertificateBoundTokensProvider)
+	return ok && p.GetTLSClientCertificateBoundAccessTokens()
+}
+
+// boundThumbprint returns the certificate thumbprint the refreshed token is
+// bound to. Only refresh tokens of public clients are bound to the
+// certificate; those of confidential clients are bound to the client itself,
+// so that it can rotate its certificate (RFC 8705 §4).
+func boundThumbprint(client models.Client, token models.Token) string {
+	if utils.IsNil(client) || !client.IsPublic() {
+		return ""
+	}
+
+	ext, ok := token.(models.ExtendableToken)
+	if !ok || utils.IsNil(ext) {
+		return ""
+	}
+
+	return ConfirmationThumbprint(ext.GetExtraData())
+}
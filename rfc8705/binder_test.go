@@ -0,0 +1,127 @@
+package rfc8705
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	autherrors "github.com/tniah/authlib/errors"
+	"github.com/tniah/authlib/integrations/sql"
+	"github.com/tniah/authlib/requests"
+	"github.com/tniah/authlib/types"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("no_opts_uses_defaults", func(t *testing.T) {
+		b := New()
+		assert.NotNil(t, b)
+		assert.False(t, b.required)
+	})
+
+	t.Run("with_opts", func(t *testing.T) {
+		b := New(NewOptions().SetRequired(true))
+		assert.True(t, b.required)
+	})
+
+	t.Run("nil_opts_uses_defaults", func(t *testing.T) {
+		b := New(nil)
+		assert.NotNil(t, b)
+		assert.False(t, b.required)
+	})
+}
+
+func TestCertificateBinder_ValidateTokenRequest(t *testing.T) {
+	cert := newCertificate(t, "client")
+	confidential := &sql.Client{ClientID: "confidential", TokenEndpointAuthMethod: string(types.ClientTLSAuthentication)}
+	public := &sql.Client{ClientID: "public", TokenEndpointAuthMethod: string(types.ClientNoneAuthentication)}
+	boundToken := func(c *sql.Client, thumbprint string) *sql.Token {
+		return &sql.Token{
+			ClientID: c.ClientID,
+			Data:     map[string]interface{}{"cnf": map[string]interface{}{ConfirmationX5tS256: thumbprint}},
+		}
+	}
+
+	t.Run("binds_token_to_certificate", func(t *testing.T) {
+		r := &requests.TokenRequest{Client: confidential, Request: newMTLSRequest(cert)}
+		assert.NoError(t, New().ValidateTokenRequest(r))
+		assert.Equal(t, map[string]interface{}{ConfirmationX5tS256: Thumbprint(cert)}, r.Confirmation)
+	})
+
+	t.Run("keeps_other_confirmation_members", func(t *testing.T) {
+		r := &requests.TokenRequest{
+			Client:       confidential,
+			Confirmation: map[string]interface{}{"jkt": "abc"},
+			Request:      newMTLSRequest(cert),
+		}
+		assert.NoError(t, New().ValidateTokenRequest(r))
+		assert.Equal(t, "abc", r.Confirmation["jkt"])
+		assert.Equal(t, Thumbprint(cert), r.Confirmation[ConfirmationX5tS256])
+	})
+
+	t.Run("skips_binding_without_certificate", func(t *testing.T) {
+		r := &requests.TokenRequest{Client: confidential, Request: newMTLSRequest(nil)}
+		assert.NoError(t, New().ValidateTokenRequest(r))
+		assert.Nil(t, r.Confirmation)
+	})
+
+	t.Run("error_without_certificate_when_required", func(t *testing.T) {
+		r := &requests.TokenRequest{Client: confidential, Request: newMTLSRequest(nil)}
+		err := New(NewOptions().SetRequired(true)).ValidateTokenRequest(r)
+		assert.ErrorIs(t, autherrors.ToAuthLibError(err).Code, autherrors.ErrInvalidRequest)
+	})
+
+	t.Run("error_without_certificate_when_client_requires_binding", func(t *testing.T) {
+		client := &sql.Client{ClientID: "bound", TLSClientCertificateBoundAccessTokens: true}
+		r := &requests.TokenRequest{Client: client, Request: newMTLSRequest(nil)}
+		err := New().ValidateTokenRequest(r)
+		assert.ErrorIs(t, autherrors.ToAuthLibError(err).Code, autherrors.ErrInvalidRequest)
+	})
+
+	t.Run("public_client_refreshes_with_bound_certificate", func(t *testing.T) {
+		r := &requests.TokenRequest{Client: public, Token: boundToken(public, Thumbprint(cert)), Request: newMTLSRequest(cert)}
+		assert.NoError(t, New().ValidateTokenRequest(r))
+		assert.Equal(t, Thumbprint(cert), r.Confirmation[ConfirmationX5tS256])
+	})
+
+	t.Run("error_when_public_client_refreshes_with_other_certificate", func(t *testing.T) {
+		r := &requests.TokenRequest{Client: public, Token: boundToken(public, Thumbprint(cert)), Request: newMTLSRequest(newCertificate(t, "other"))}
+		err := New().ValidateTokenRequest(r)
+		assert.ErrorIs(t, autherrors.ToAuthLibError(err).Code, autherrors.ErrInvalidGrant)
+	})
+
+	t.Run("error_when_public_client_refreshes_without_certificate", func(t *testing.T) {
+		r := &requests.TokenRequest{Client: public, Token: boundToken(public, Thumbprint(cert)), Request: newMTLSRequest(nil)}
+		err := New().ValidateTokenRequest(r)
+		assert.ErrorIs(t, autherrors.ToAuthLibError(err).Code, autherrors.ErrInvalidGrant)
+	})
+
+	t.Run("confidential_client_refreshes_with_rotated_certificate", func(t *testing.T) {
+		rotated := newCertificate(t, "rotated")
+		r := &requests.TokenRequest{Client: confidential, Token: boundToken(confidential, Thumbprint(cert)), Request: newMTLSRequest(rotated)}
+		assert.NoError(t, New().ValidateTokenRequest(r))
+		assert.Equal(t, Thumbprint(rotated), r.Confirmation[ConfirmationX5tS256])
+	})
+}
+
+func TestCertificateBinder_ProcessToken(t *testing.T) {
+	cnf := map[string]interface{}{ConfirmationX5tS256: "abc"}
+
+	t.Run("stores_confirmation_in_extra_data", func(t *testing.T) {
+		token := &sql.Token{Data: map[string]interface{}{"foo": "bar"}}
+		r := &requests.TokenRequest{Confirmation: cnf}
+		assert.NoError(t, New().ProcessToken(r, token, map[string]interface{}{}))
+		assert.Equal(t, map[string]interface{}{"foo": "bar", "cnf": cnf}, token.Data)
+	})
+
+	t.Run("initialises_extra_data", func(t *testing.T) {
+		token := &sql.Token{}
+		r := &requests.TokenRequest{Confirmation: cnf}
+		assert.NoError(t, New().ProcessToken(r, token, map[string]interface{}{}))
+		assert.Equal(t, cnf, token.Data["cnf"])
+	})
+
+	t.Run("noop_without_confirmation", func(t *testing.T) {
+		token := &sql.Token{}
+		assert.NoError(t, New().ProcessToken(&requests.TokenRequest{}, token, map[string]interface{}{}))
+		assert.Nil(t, token.Data)
+	})
+}
@@ -0,0 +1,29 @@
+package rfc8705
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"net/http"
+)
+
+// ConfirmationX5tS256 is the cnf member carrying the certificate thumbprint
+// of a certificate-bound token (RFC 8705 §3.1).
+const ConfirmationX5tS256 = "x5t#S256"
+
+// Thumbprint returns the base64url-encoded SHA-256 hash of the DER encoding
+// of cert, the value of the x5t#S256 confirmation method.
+func Thumbprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// PeerCertificate returns the leaf certificate the client presented during
+// the TLS handshake, or nil when the request was not made over mutual TLS.
+func PeerCertificate(r *http.Request) *x509.Certificate {
+	if r == nil || r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+		return nil
+	}
+
+	return r.TLS.PeerCertificates[0]
+}
@@ -0,0 +1,79 @@
+package rfc8705
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newCertificate returns a self-signed client certificate.
+func newCertificate(t *testing.T, cn string) *x509.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert
+}
+
+// newMTLSRequest returns a request made over mutual TLS with cert, or over
+// plain TLS when cert is nil.
+func newMTLSRequest(cert *x509.Certificate) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/token", nil)
+	r.TLS = &tls.ConnectionState{}
+	if cert != nil {
+		r.TLS.PeerCertificates = []*x509.Certificate{cert}
+	}
+	return r
+}
+
+func TestThumbprint(t *testing.T) {
+	cert := newCertificate(t, "client")
+	sum := sha256.Sum256(cert.Raw)
+
+	thumbprint := Thumbprint(cert)
+	assert.Equal(t, base64.RawURLEncoding.EncodeToString(sum[:]), thumbprint)
+	assert.Len(t, thumbprint, 43)
+}
+
+func TestPeerCertificate(t *testing.T) {
+	cert := newCertificate(t, "client")
+
+	t.Run("returns_leaf_certificate", func(t *testing.T) {
+		assert.Equal(t, cert, PeerCertificate(newMTLSRequest(cert)))
+	})
+
+	t.Run("nil_without_client_certificate", func(t *testing.T) {
+		assert.Nil(t, PeerCertificate(newMTLSRequest(nil)))
+	})
+
+	t.Run("nil_without_tls", func(t *testing.T) {
+		assert.Nil(t, PeerCertificate(httptest.NewRequest(http.MethodPost, "/token", nil)))
+	})
+
+	t.Run("nil_request", func(t *testing.T) {
+		assert.Nil(t, PeerCertificate(nil))
+	})
+}
@@ -0,0 +1,23 @@
+package rfc8705
+
+// Options configures certificate binding (RFC 8705 §3).
+type Options struct {
+	// required forces every token request to be made over mutual TLS, so
+	// that no unbound token is ever issued.
+	required bool
+}
+
+// NewOptions returns Options with defaults: a client certificate is only
+// required for clients that registered
+// tls_client_certificate_bound_access_tokens. Tokens requested without one
+// are issued unbound.
+func NewOptions() *Options {
+	return &Options{}
+}
+
+// SetRequired controls whether every token request must present a client
+// certificate.
+func (opts *Options) SetRequired(value bool) *Options {
+	opts.required = value
+	return opts
+}
@@ -0,0 +1,28 @@
+package rfc8705
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewOptions(t *testing.T) {
+	opts := NewOptions()
+	assert.False(t, opts.required)
+}
+
+func TestOptions_SetRequired(t *testing.T) {
+	t.Run("sets_true", func(t *testing.T) {
+		opts := NewOptions()
+		result := opts.SetRequired(true)
+		assert.Equal(t, opts, result)
+		assert.True(t, opts.required)
+	})
+
+	t.Run("sets_false", func(t *testing.T) {
+		opts := NewOptions()
+		result := opts.SetRequired(false)
+		assert.Equal(t, opts, result)
+		assert.False(t, opts.required)
+	})
+}
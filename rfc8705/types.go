@@ -0,0 +1,10 @@
+package rfc8705
+
+// CertificateBoundTokensProvider is implemented by clients that can register
+// the tls_client_certificate_bound_access_tokens metadata (RFC 8705 §3.4).
+// When it returns true, CertificateBinder refuses token requests made
+// without a client certificate, so the client is never issued an unbound
+// token.
+type CertificateBoundTokensProvider interface {
+	GetTLSClientCertificateBoundAccessTokens() bool
+}
@@ -0,0 +1,55 @@
+package rfc8705
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+)
+
+var (
+	// ErrUnboundToken is returned by VerifyCertificateBinding when the token
+	// carries no x5t#S256 confirmation.
+	ErrUnboundToken = errors.New("token is not bound to a client certificate")
+	// ErrMissingClientCertificate is returned by VerifyCertificateBinding when
+	// the request was not made over mutual TLS.
+	ErrMissingClientCertificate = errors.New("missing client certificate")
+	// ErrCertificateMismatch is returned by VerifyCertificateBinding when the
+	// client certificate is not the one the token is bound to.
+	ErrCertificateMismatch = errors.New("client certificate does not match the token binding")
+)
+
+// ConfirmationThumbprint returns the x5t#S256 member of the cnf claim in
+// claims, or "" when there is none. claims is either the payload of a JWT
+// access token or an introspection response.
+func ConfirmationThumbprint(claims map[string]interface{}) string {
+	cnf, ok := claims["cnf"].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+
+	thumbprint, _ := cnf[ConfirmationX5tS256].(string)
+	return thumbprint
+}
+
+// VerifyCertificateBinding is the resource server check of RFC 8705 §3: the
+// certificate presented with r must be the one the access token is bound
+// to. claims is the payload of the JWT access token or the introspection
+// response for it. Resource servers should answer any error with a 401
+// invalid_token response (RFC 6750 §3.1).
+func VerifyCertificateBinding(r *http.Request, claims map[string]interface{}) error {
+	thumbprint := ConfirmationThumbprint(claims)
+	if thumbprint == "" {
+		return ErrUnboundToken
+	}
+
+	cert := PeerCertificate(r)
+	if cert == nil {
+		return ErrMissingClientCertificate
+	}
+
+	if subtle.ConstantTimeCompare([]byte(thumbprint), []byte(Thumbprint(cert))) != 1 {
+		return ErrCertificateMismatch
+	}
+
+	return nil
+}
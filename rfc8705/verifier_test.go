@@ -0,0 +1,41 @@
+package rfc8705
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfirmationThumbprint(t *testing.T) {
+	assert.Equal(t, "abc", ConfirmationThumbprint(map[string]interface{}{"cnf": map[string]interface{}{"x5t#S256": "abc"}}))
+	assert.Empty(t, ConfirmationThumbprint(map[string]interface{}{"cnf": map[string]interface{}{"jkt": "abc"}}))
+	assert.Empty(t, ConfirmationThumbprint(map[string]interface{}{"cnf": "abc"}))
+	assert.Empty(t, ConfirmationThumbprint(nil))
+}
+
+func TestVerifyCertificateBinding(t *testing.T) {
+	cert := newCertificate(t, "client")
+	claims := map[string]interface{}{
+		"sub": "client",
+		"cnf": map[string]interface{}{ConfirmationX5tS256: Thumbprint(cert)},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		assert.NoError(t, VerifyCertificateBinding(newMTLSRequest(cert), claims))
+	})
+
+	t.Run("error_on_unbound_token", func(t *testing.T) {
+		err := VerifyCertificateBinding(newMTLSRequest(cert), map[string]interface{}{"sub": "client"})
+		assert.ErrorIs(t, err, ErrUnboundToken)
+	})
+
+	t.Run("error_without_client_certificate", func(t *testing.T) {
+		err := VerifyCertificateBinding(newMTLSRequest(nil), claims)
+		assert.ErrorIs(t, err, ErrMissingClientCertificate)
+	})
+
+	t.Run("error_on_other_certificate", func(t *testing.T) {
+		err := VerifyCertificateBinding(newMTLSRequest(newCertificate(t, "attacker")), claims)
+		assert.ErrorIs(t, err, ErrCertificateMismatch)
+	})
+}
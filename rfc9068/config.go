@@ -1,10 +1,13 @@
 package rfc9068
 
 import (
+	"crypto"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	autherrors "github.com/tniah/authlib/errors"
+	"github.com/tniah/authlib/keys"
+	"github.com/tniah/authlib/utils"
 )
 
 // DefaultExpiresIn is the JWT access token lifetime used when no
@@ -24,15 +27,22 @@ type GeneratorConfig struct {
 	signingKey          []byte
 	signingKeyMethod    jwt.SigningMethod
 	signingKeyID        string
+	signer              crypto.Signer
 	signingKeyGenerator SigningKeyGenerator
+	keySet              keys.KeySet
+	keyCache            *utils.SigningKeyCache
 	extraClaimGenerator ExtraClaimGenerator
 	jwtIDGenerator      JWTIDGenerator
 }
 
-// NewGeneratorConfig returns a GeneratorConfig with DefaultExpiresIn.
-// All generator hooks are nil, meaning static config values are used.
+// NewGeneratorConfig returns a GeneratorConfig with DefaultExpiresIn and a
+// cache of parsed signing keys. All generator hooks are nil, meaning static
+// config values are used.
 func NewGeneratorConfig() *GeneratorConfig {
-	return &GeneratorConfig{expiresIn: DefaultExpiresIn}
+	return &GeneratorConfig{
+		expiresIn: DefaultExpiresIn,
+		keyCache:  utils.NewSigningKeyCache(utils.DefaultSigningKeyCacheSize),
+	}
 }
 
 // SetIssuer sets the static issuer claim (iss). Ignored when SetIssuerGenerator is set.
@@ -90,13 +100,38 @@ func (cfg *GeneratorConfig) SetSigningKey(key []byte, method jwt.SigningMethod,
 	return cfg
 }
 
+// SetSigner sets a static signing key that is already parsed: an
+// *rsa.PrivateKey, *ecdsa.PrivateKey, ed25519.PrivateKey, or any other
+// crypto.Signer such as a keys.ExternalSigner backed by a KMS, with its
+// algorithm and optional key ID (kid). Takes precedence over SetSigningKey
+// when set.
+func (cfg *GeneratorConfig) SetSigner(signer crypto.Signer, method jwt.SigningMethod, keyID ...string) *GeneratorConfig {
+	cfg.signer = signer
+	cfg.signingKeyMethod = method
+
+	if len(keyID) > 0 {
+		cfg.signingKeyID = keyID[0]
+	}
+
+	return cfg
+}
+
 // SetSigningKeyGenerator registers a per-request signing key hook. Takes
-// precedence over SetSigningKey when set.
+// precedence over SetSigningKey and SetSigner when set. The keys it returns
+// are parsed once and cached by fingerprint.
 func (cfg *GeneratorConfig) SetSigningKeyGenerator(fn SigningKeyGenerator) *GeneratorConfig {
 	cfg.signingKeyGenerator = fn
 	return cfg
 }
 
+// SetKeySet signs tokens with the current key of ks, whose public keys are
+// published by the JWKS endpoint. Takes precedence over SetSigningKey,
+// SetSigner and SetSigningKeyGenerator when set.
+func (cfg *GeneratorConfig) SetKeySet(ks keys.KeySet) *GeneratorConfig {
+	cfg.keySet = ks
+	return cfg
+}
+
 // SetExtraClaimGenerator registers a hook for adding extra claims to the JWT
 // (e.g. roles, tenant ID). Claims returned by this hook are merged into the
 // standard claim set. Protected claims (iss, sub, aud, exp, iat, jti,
@@ -128,16 +163,17 @@ func (cfg *GeneratorConfig) ValidateConfig() error {
 		return autherrors.ErrMissingExpiresIn
 	}
 
-	if cfg.signingKey == nil && cfg.signingKeyGenerator == nil {
+	staticKey := cfg.signingKey != nil || !utils.IsNil(cfg.signer)
+	if !staticKey && cfg.signingKeyGenerator == nil && utils.IsNil(cfg.keySet) {
 		return autherrors.ErrMissingSigningKey
 	}
 
-	if cfg.signingKey != nil && cfg.signingKeyMethod == nil {
+	if staticKey && cfg.signingKeyMethod == nil {
 		return autherrors.ErrMissingSigningKeyMethod
 	}
 
 	// RFC 9068 §2.1: JWT access tokens MUST NOT use "none" as the signing algorithm.
-	if cfg.signingKey != nil && cfg.signingKeyMethod == jwt.SigningMethodNone {
+	if staticKey && cfg.signingKeyMethod == jwt.SigningMethodNone {
 		return autherrors.ErrInsecureSigningMethod
 	}
 
@@ -1,12 +1,16 @@
 package rfc9068
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
 	"testing"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 	autherrors "github.com/tniah/authlib/errors"
+	"github.com/tniah/authlib/keys"
 	"github.com/tniah/authlib/mocks/rfc9068"
 )
 
@@ -14,6 +18,7 @@ func TestConfig(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		cfg := NewGeneratorConfig()
 		assert.Equal(t, DefaultExpiresIn, cfg.expiresIn)
+		assert.NotNil(t, cfg.keyCache)
 
 		cfg.SetIssuer("https://example.com")
 		assert.Equal(t, "https://example.com", cfg.issuer)
@@ -41,6 +46,17 @@ func TestConfig(t *testing.T) {
 		assert.Equal(t, jwt.SigningMethodHS256, cfg.signingKeyMethod)
 		assert.Equal(t, "my-kid", cfg.signingKeyID)
 
+		priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		assert.NoError(t, err)
+		cfg.SetSigner(priv, jwt.SigningMethodES256, "signer-kid")
+		assert.Equal(t, priv, cfg.signer)
+		assert.Equal(t, jwt.SigningMethodES256, cfg.signingKeyMethod)
+		assert.Equal(t, "signer-kid", cfg.signingKeyID)
+
+		ks := keys.NewStaticKeySet(nil)
+		cfg.SetKeySet(ks)
+		assert.Equal(t, ks, cfg.keySet)
+
 		extraGen := rfc9068.NewMockExtraClaimGenerator(t).Execute
 		cfg.SetExtraClaimGenerator(extraGen)
 		assert.NotNil(t, cfg.extraClaimGenerator)
@@ -69,8 +85,26 @@ func TestConfig(t *testing.T) {
 		err = cfg.ValidateConfig()
 		assert.ErrorIs(t, err, autherrors.ErrMissingSigningKey)
 
+		cfg.SetKeySet(keys.NewStaticKeySet(nil))
+		assert.NoError(t, cfg.ValidateConfig())
+
+		cfg.SetKeySet(nil)
 		cfg.SetSigningKey([]byte("test"), nil)
 		err = cfg.ValidateConfig()
 		assert.ErrorIs(t, err, autherrors.ErrMissingSigningKeyMethod)
+
+		priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		assert.NoError(t, err)
+		cfg = NewGeneratorConfig().
+			SetIssuer("https://example.com").
+			SetAudience("https://api.example.com").
+			SetSigner(priv, nil)
+		assert.ErrorIs(t, cfg.ValidateConfig(), autherrors.ErrMissingSigningKeyMethod)
+
+		cfg.SetSigner(priv, jwt.SigningMethodNone)
+		assert.ErrorIs(t, cfg.ValidateConfig(), autherrors.ErrInsecureSigningMethod)
+
+		cfg.SetSigner(priv, jwt.SigningMethodES256)
+		assert.NoError(t, cfg.ValidateConfig())
 	})
 }
@@ -19,7 +19,7 @@ import (
 var protectedClaims = map[string]bool{
 	"iss": true, "sub": true, "aud": true,
 	"exp": true, "iat": true, "jti": true,
-	"client_id": true, "scope": true,
+	"client_id": true, "scope": true, "act": true, "cnf": true,
 }
 
 // ErrNilClient is returned by Generate when the token request carries no client.
@@ -53,6 +53,10 @@ func MustJWTAccessTokenGenerator(cfg *GeneratorConfig) (*JWTAccessTokenGenerator
 // The JWT carries the standard RFC 9068 claims (iss, sub, aud, exp, iat, jti,
 // client_id, scope). Extra claims can be added via GeneratorConfig.SetExtraClaimGenerator.
 // User may be nil (e.g. client credentials); in that case sub is set to client_id.
+// Tokens issued by the token exchange grant also carry the act claim for
+// delegation, and the requested resource/audience as aud (RFC 8693 §4.1).
+// Sender-constrained tokens carry the cnf claim set in r.Confirmation (e.g.
+// the certificate thumbprint of RFC 8705 §3.1).
 func (g *JWTAccessTokenGenerator) Generate(token models.Token, r *requests.TokenRequest) error {
 	client := r.Client
 	if utils.IsNil(client) {
@@ -93,7 +97,7 @@ func (g *JWTAccessTokenGenerator) Generate(token models.Token, r *requests.Token
 	claims := utils.JWTClaim{
 		"iss":       g.issuerHandler(ctx, client),
 		"exp":       jwt.NewNumericDate(issuedAt.Add(expiresIn)),
-		"aud":       g.audienceHandler(ctx, client),
+		"aud":       g.audienceClaim(ctx, r),
 		"client_id": clientID,
 		"iat":       jwt.NewNumericDate(issuedAt),
 		"jti":       jwtID,
@@ -109,6 +113,14 @@ func (g *JWTAccessTokenGenerator) Generate(token models.Token, r *requests.Token
 		claims["scope"] = strings.Join(allowedScopes.String(), " ")
 	}
 
+	if r.Act != nil {
+		claims["act"] = r.Act
+	}
+
+	if len(r.Confirmation) > 0 {
+		claims["cnf"] = r.Confirmation
+	}
+
 	if fn := g.extraClaimGenerator; fn != nil {
 		extraClaims, err := fn(ctx, r.GrantType.String(), client, r.User, allowedScopes)
 		if err != nil {
@@ -123,17 +135,7 @@ func (g *JWTAccessTokenGenerator) Generate(token models.Token, r *requests.Token
 		}
 	}
 
-	signingKey, signingMethod, signingKeyID, err := g.signingKeyHandler(ctx, client)
-	if err != nil {
-		return err
-	}
-
-	// RFC 9068 §2.1: MUST NOT use "none" — guard against signingKeyGenerator returning it.
-	if signingMethod == jwt.SigningMethodNone {
-		return autherrors.ErrInsecureSigningMethod
-	}
-
-	t, err := utils.NewJWTToken(signingKey, signingMethod, signingKeyID)
+	t, err := g.jwtToken(ctx, client)
 	if err != nil {
 		return err
 	}
@@ -167,6 +169,28 @@ func (g *JWTAccessTokenGenerator) audienceHandler(ctx context.Context, client mo
 	return g.audience
 }
 
+// audienceClaim returns the aud claim. For token exchange, the resource and
+// audience parameters name the target services (RFC 8693 §2.1) and replace
+// the configured audience. The grant validates them before tokens are issued;
+// other grants never use them.
+func (g *JWTAccessTokenGenerator) audienceClaim(ctx context.Context, r *requests.TokenRequest) interface{} {
+	if r.GrantType.IsTokenExchange() {
+		targets := make([]string, 0, len(r.Resources)+len(r.Audiences))
+		targets = append(targets, r.Resources...)
+		targets = append(targets, r.Audiences...)
+
+		if len(targets) == 1 {
+			return targets[0]
+		}
+
+		if len(targets) > 1 {
+			return targets
+		}
+	}
+
+	return g.audienceHandler(ctx, r.Client)
+}
+
 // expiresInHandler returns the token lifetime. Delegates to ExpiresInGenerator
 // if set, otherwise returns the static expiresIn value from config.
 func (g *JWTAccessTokenGenerator) expiresInHandler(ctx context.Context, grantType string, client models.Client) time.Duration {
@@ -177,6 +201,40 @@ func (g *JWTAccessTokenGenerator) expiresInHandler(ctx context.Context, grantTyp
 	return g.expiresIn
 }
 
+// jwtToken returns the JWTToken signing the access token: the current key of
+// the KeySet when set, otherwise the key returned by SigningKeyGenerator, the
+// signer, or the static key. PEM keys are parsed once and cached.
+func (g *JWTAccessTokenGenerator) jwtToken(ctx context.Context, client models.Client) (*utils.JWTToken, error) {
+	if !utils.IsNil(g.keySet) {
+		key, err := g.keySet.SigningKey(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return key.JWTToken(), nil
+	}
+
+	if g.signingKeyGenerator == nil && !utils.IsNil(g.signer) {
+		if g.signingKeyMethod == jwt.SigningMethodNone {
+			return nil, autherrors.ErrInsecureSigningMethod
+		}
+
+		return utils.NewJWTTokenWithKey(g.signer, g.signingKeyMethod, g.signingKeyID), nil
+	}
+
+	signingKey, signingMethod, signingKeyID, err := g.signingKeyHandler(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	// RFC 9068 §2.1: MUST NOT use "none" — guard against signingKeyGenerator returning it.
+	if signingMethod == jwt.SigningMethodNone {
+		return nil, autherrors.ErrInsecureSigningMethod
+	}
+
+	return g.keyCache.JWTToken(signingKey, signingMethod, signingKeyID)
+}
+
 // signingKeyHandler returns the signing key, method, and key ID. Delegates to
 // SigningKeyGenerator if set, otherwise returns the static values from config.
 func (g *JWTAccessTokenGenerator) signingKeyHandler(ctx context.Context, client models.Client) ([]byte, jwt.SigningMethod, string, error) {
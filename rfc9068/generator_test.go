@@ -2,6 +2,12 @@ package rfc9068
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
 	"net/http/httptest"
 	"testing"
 	"time"
@@ -11,6 +17,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	autherrors "github.com/tniah/authlib/errors"
 	"github.com/tniah/authlib/integrations/sql"
+	"github.com/tniah/authlib/keys"
 	"github.com/tniah/authlib/models"
 	"github.com/tniah/authlib/requests"
 	"github.com/tniah/authlib/types"
@@ -127,4 +134,243 @@ func TestJWTAccessTokenGenerator(t *testing.T) {
 		err := generator.Generate(mockToken, r)
 		assert.ErrorIs(t, err, autherrors.ErrInsecureSigningMethod)
 	})
+
+	t.Run("token exchange sets act and aud", func(t *testing.T) {
+		mockToken := &sql.Token{}
+		generator := NewJWTAccessTokenGenerator(cfg)
+		act := map[string]interface{}{
+			"sub": "service-a",
+			"act": map[string]interface{}{"sub": "service-b"},
+		}
+		r := &requests.TokenRequest{
+			GrantType: types.GrantTypeTokenExchange,
+			Client:    mockClient,
+			User:      mockUser,
+			Resources: []string{"https://orders.example.com"},
+			Audiences: []string{"billing"},
+			Act:       act,
+			Request:   httptest.NewRequest("POST", "/token", nil),
+		}
+		err := generator.Generate(mockToken, r)
+		assert.NoError(t, err)
+
+		claims := jwt.MapClaims{}
+		_, err = jwt.ParseWithClaims(mockToken.GetAccessToken(), claims, func(*jwt.Token) (interface{}, error) {
+			return []byte("my-secret-key"), nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []interface{}{"https://orders.example.com", "billing"}, claims["aud"])
+		assert.Equal(t, act, claims["act"])
+	})
+
+	t.Run("aud ignores resource outside token exchange", func(t *testing.T) {
+		mockToken := &sql.Token{}
+		generator := NewJWTAccessTokenGenerator(cfg)
+		r := &requests.TokenRequest{
+			GrantType: types.GrantTypeClientCredentials,
+			Client:    mockClient,
+			Resources: []string{"https://orders.example.com"},
+			Request:   httptest.NewRequest("POST", "/token", nil),
+		}
+		err := generator.Generate(mockToken, r)
+		assert.NoError(t, err)
+
+		claims := jwt.MapClaims{}
+		_, err = jwt.ParseWithClaims(mockToken.GetAccessToken(), claims, func(*jwt.Token) (interface{}, error) {
+			return []byte("my-secret-key"), nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "https://api.example.com", claims["aud"])
+		assert.NotContains(t, claims, "act")
+		assert.NotContains(t, claims, "cnf")
+	})
+
+	t.Run("confirmation sets cnf", func(t *testing.T) {
+		mockToken := &sql.Token{}
+		generator := NewJWTAccessTokenGenerator(cfg)
+		cnf := map[string]interface{}{"x5t#S256": "bwcK0esc3ACC3DB2Y5_lESsXE8o9ltc05O89jdN-dg2"}
+		r := &requests.TokenRequest{
+			GrantType:    types.GrantTypeClientCredentials,
+			Client:       mockClient,
+			Confirmation: cnf,
+			Request:      httptest.NewRequest("POST", "/token", nil),
+		}
+		err := generator.Generate(mockToken, r)
+		assert.NoError(t, err)
+
+		claims := jwt.MapClaims{}
+		_, err = jwt.ParseWithClaims(mockToken.GetAccessToken(), claims, func(*jwt.Token) (interface{}, error) {
+			return []byte("my-secret-key"), nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, cnf, claims["cnf"])
+	})
+
+	t.Run("key set signs with its current key", func(t *testing.T) {
+		priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		assert.NoError(t, err)
+		key, err := keys.NewKey(priv, jwt.SigningMethodES256, "rotating-kid")
+		assert.NoError(t, err)
+
+		mockToken := &sql.Token{}
+		generator := NewJWTAccessTokenGenerator(NewGeneratorConfig().
+			SetIssuer("https://example.com").
+			SetAudience("https://api.example.com").
+			SetSigningKey([]byte("my-secret-key"), jwt.SigningMethodHS256, "my-kid-id").
+			SetKeySet(keys.NewStaticKeySet(key)))
+		r := &requests.TokenRequest{
+			GrantType: types.GrantTypeClientCredentials,
+			Client:    mockClient,
+			Request:   httptest.NewRequest("POST", "/token", nil),
+		}
+		assert.NoError(t, generator.Generate(mockToken, r))
+
+		token, err := jwt.Parse(mockToken.GetAccessToken(), func(*jwt.Token) (interface{}, error) {
+			return &priv.PublicKey, nil
+		}, jwt.WithValidMethods([]string{"ES256"}))
+		assert.NoError(t, err)
+		assert.Equal(t, "rotating-kid", token.Header["kid"])
+		assert.Equal(t, "at+JWT", token.Header["typ"])
+	})
+
+	t.Run("key set error", func(t *testing.T) {
+		generator := NewJWTAccessTokenGenerator(NewGeneratorConfig().
+			SetIssuer("https://example.com").
+			SetAudience("https://api.example.com").
+			SetKeySet(keys.NewStaticKeySet(nil)))
+		r := &requests.TokenRequest{
+			GrantType: types.GrantTypeClientCredentials,
+			Client:    mockClient,
+			Request:   httptest.NewRequest("POST", "/token", nil),
+		}
+		err := generator.Generate(&sql.Token{}, r)
+		assert.ErrorIs(t, err, keys.ErrNoSigningKey)
+	})
+
+	t.Run("signer signs without parsing", func(t *testing.T) {
+		priv, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+		assert.NoError(t, err)
+
+		mockToken := &sql.Token{}
+		generator := NewJWTAccessTokenGenerator(NewGeneratorConfig().
+			SetIssuer("https://example.com").
+			SetAudience("https://api.example.com").
+			SetSigningKey([]byte("my-secret-key"), jwt.SigningMethodHS256).
+			SetSigner(priv, jwt.SigningMethodES384, "signer-kid"))
+		r := &requests.TokenRequest{
+			GrantType: types.GrantTypeClientCredentials,
+			Client:    mockClient,
+			Request:   httptest.NewRequest("POST", "/token", nil),
+		}
+		assert.NoError(t, generator.Generate(mockToken, r))
+		assert.Equal(t, 0, generator.keyCache.Len())
+
+		token, err := jwt.Parse(mockToken.GetAccessToken(), func(*jwt.Token) (interface{}, error) {
+			return &priv.PublicKey, nil
+		}, jwt.WithValidMethods([]string{"ES384"}))
+		assert.NoError(t, err)
+		assert.Equal(t, "signer-kid", token.Header["kid"])
+	})
+
+	t.Run("external signer keeps the key in the key service", func(t *testing.T) {
+		priv, err := rsa.GenerateKey(rand.Reader, 2048)
+		assert.NoError(t, err)
+
+		mockToken := &sql.Token{}
+		generator := NewJWTAccessTokenGenerator(NewGeneratorConfig().
+			SetIssuer("https://example.com").
+			SetAudience("https://api.example.com").
+			SetSigner(keys.NewLocalSigner(priv), jwt.SigningMethodPS256, "kms-kid"))
+		r := &requests.TokenRequest{
+			GrantType: types.GrantTypeClientCredentials,
+			Client:    mockClient,
+			Request:   httptest.NewRequest("POST", "/token", nil),
+		}
+		assert.NoError(t, generator.Generate(mockToken, r))
+
+		token, err := jwt.Parse(mockToken.GetAccessToken(), func(*jwt.Token) (interface{}, error) {
+			return &priv.PublicKey, nil
+		}, jwt.WithValidMethods([]string{"PS256"}))
+		assert.NoError(t, err)
+		assert.Equal(t, "kms-kid", token.Header["kid"])
+	})
+
+	t.Run("signing keys are parsed once", func(t *testing.T) {
+		calls := 0
+		generator := NewJWTAccessTokenGenerator(NewGeneratorConfig().
+			SetIssuer("https://example.com").
+			SetAudience("https://api.example.com").
+			SetSigningKeyGenerator(func(_ context.Context, client models.Client) ([]byte, jwt.SigningMethod, string, error) {
+				calls++
+				return []byte("secret-" + client.GetClientID()), jwt.SigningMethodHS256, client.GetClientID(), nil
+			}))
+
+		for _, clientID := range []string{"client-a", "client-b", "client-a"} {
+			r := &requests.TokenRequest{
+				GrantType: types.GrantTypeClientCredentials,
+				Client:    &sql.Client{ClientID: clientID},
+				Request:   httptest.NewRequest("POST", "/token", nil),
+			}
+			assert.NoError(t, generator.Generate(&sql.Token{}, r))
+		}
+
+		assert.Equal(t, 3, calls)
+		assert.Equal(t, 2, generator.keyCache.Len())
+	})
+}
+
+// BenchmarkJWTAccessTokenGenerator_Generate compares the ways of supplying an
+// RSA signing key. parse_per_token disables the key cache, which is how every
+// token was signed before keys were cached.
+// Run with: go test ./rfc9068 -run '^$' -bench . -benchmem
+func BenchmarkJWTAccessTokenGenerator_Generate(b *testing.B) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(priv)})
+	key, err := keys.NewKey(priv, jwt.SigningMethodRS256)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	newConfig := func() *GeneratorConfig {
+		return NewGeneratorConfig().
+			SetIssuer("https://example.com").
+			SetAudience("https://api.example.com")
+	}
+
+	uncached := newConfig().SetSigningKey(keyPEM, jwt.SigningMethodRS256, "kid-1")
+	uncached.keyCache = nil
+
+	for _, bm := range []struct {
+		name string
+		cfg  *GeneratorConfig
+	}{
+		{"parse_per_token", uncached},
+		{"cached_pem", newConfig().SetSigningKey(keyPEM, jwt.SigningMethodRS256, "kid-1")},
+		{"cached_generator", newConfig().SetSigningKeyGenerator(func(_ context.Context, _ models.Client) ([]byte, jwt.SigningMethod, string, error) {
+			return keyPEM, jwt.SigningMethodRS256, "kid-1", nil
+		})},
+		{"signer", newConfig().SetSigner(priv, jwt.SigningMethodRS256, "kid-1")},
+		{"key_set", newConfig().SetKeySet(keys.NewStaticKeySet(key))},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			generator := NewJWTAccessTokenGenerator(bm.cfg)
+			r := &requests.TokenRequest{
+				GrantType: types.GrantTypeClientCredentials,
+				Client:    &sql.Client{ClientID: "client-1"},
+				Request:   httptest.NewRequest("POST", "/token", nil),
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := generator.Generate(&sql.Token{}, r); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
 }
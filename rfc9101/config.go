@@ -0,0 +1,176 @@
+package rfc9101
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	clientauth "github.com/tniah/authlib/rfc6749/client_authentication"
+	"github.com/tniah/authlib/utils"
+)
+
+const (
+	// DefaultLeeway is the clock skew tolerated for the exp and nbf of a
+	// request object, which are set by the client's clock.
+	DefaultLeeway = 30 * time.Second
+	// DefaultFetchTimeout bounds the retrieval of a request_uri.
+	DefaultFetchTimeout = 10 * time.Second
+	// MaxRequestObjectSize caps the size of a fetched request object.
+	MaxRequestObjectSize = 64 << 10
+)
+
+// Sentinel errors returned by ValidateConfig.
+var (
+	ErrNilClientManager    = errors.New("client manager is nil")
+	ErrNilJWKSFetcher      = errors.New("jwks fetcher is nil")
+	ErrNilHTTPClient       = errors.New("http client is nil")
+	ErrEmptySigningMethods = errors.New("signing methods are empty")
+	ErrEmptyAudiences      = errors.New("audiences are empty")
+)
+
+// Config holds the request object checks. Use NewConfig() to get a config
+// with sensible defaults, then chain Set* calls before passing to Must() or
+// New().
+type Config struct {
+	clientMgr  ClientManager
+	fetcher    clientauth.JWKSFetcher
+	httpClient *http.Client
+	decrypter  RequestObjectDecrypter
+
+	// signingMethods are the alg values accepted for request objects.
+	signingMethods []string
+
+	// audiences are the accepted aud values of request objects: the issuer
+	// identifier of the authorization server (RFC 9101 §4, §6.3).
+	audiences []string
+
+	leeway time.Duration
+
+	// allowUnsigned accepts request objects with alg "none".
+	allowUnsigned bool
+
+	// fetchRequestURI enables retrieving request objects passed by
+	// reference from the locations registered by the client.
+	fetchRequestURI bool
+
+	// required rejects every authorization request without a signed request
+	// object (require_signed_request_object, RFC 9101 §10.5).
+	required bool
+}
+
+// NewConfig returns a Config with secure defaults:
+//   - Accepts RS*, PS*, ES*, EdDSA, and HS* request objects; HS* ones are
+//     verified with the client secret.
+//   - Rejects unsigned and encrypted request objects.
+//   - Tolerates DefaultLeeway of clock skew for exp and nbf.
+//   - Does not fetch request_uri locations.
+//   - Fetches client jwks_uri documents with a CachingJWKSFetcher.
+func NewConfig() *Config {
+	return &Config{
+		fetcher:    clientauth.NewCachingJWKSFetcher(nil),
+		httpClient: &http.Client{Timeout: DefaultFetchTimeout},
+		signingMethods: []string{
+			jwt.SigningMethodRS256.Alg(), jwt.SigningMethodRS384.Alg(), jwt.SigningMethodRS512.Alg(),
+			jwt.SigningMethodPS256.Alg(), jwt.SigningMethodPS384.Alg(), jwt.SigningMethodPS512.Alg(),
+			jwt.SigningMethodES256.Alg(), jwt.SigningMethodES384.Alg(), jwt.SigningMethodES512.Alg(),
+			jwt.SigningMethodEdDSA.Alg(),
+			jwt.SigningMethodHS256.Alg(), jwt.SigningMethodHS384.Alg(), jwt.SigningMethodHS512.Alg(),
+		},
+		leeway: DefaultLeeway,
+	}
+}
+
+// SetClientManager sets the manager used to look up the requesting client.
+func (cfg *Config) SetClientManager(mgr ClientManager) *Config {
+	cfg.clientMgr = mgr
+	return cfg
+}
+
+// SetJWKSFetcher overrides how client jwks_uri documents are retrieved.
+func (cfg *Config) SetJWKSFetcher(fetcher clientauth.JWKSFetcher) *Config {
+	cfg.fetcher = fetcher
+	return cfg
+}
+
+// SetHTTPClient overrides the client used to fetch request_uri locations.
+// Default: an http.Client with DefaultFetchTimeout.
+func (cfg *Config) SetHTTPClient(client *http.Client) *Config {
+	cfg.httpClient = client
+	return cfg
+}
+
+// SetDecrypter enables encrypted request objects. Default: nil, encrypted
+// request objects are rejected.
+func (cfg *Config) SetDecrypter(decrypter RequestObjectDecrypter) *Config {
+	cfg.decrypter = decrypter
+	return cfg
+}
+
+// SetSigningMethods overrides the accepted alg values.
+func (cfg *Config) SetSigningMethods(methods []string) *Config {
+	cfg.signingMethods = methods
+	return cfg
+}
+
+// SetAudiences requires the aud of every request object to contain one of
+// audiences, typically the issuer identifier. Required.
+func (cfg *Config) SetAudiences(audiences []string) *Config {
+	cfg.audiences = audiences
+	return cfg
+}
+
+// SetLeeway overrides the clock skew tolerated for exp and nbf. Default:
+// DefaultLeeway.
+func (cfg *Config) SetLeeway(leeway time.Duration) *Config {
+	cfg.leeway = leeway
+	return cfg
+}
+
+// SetAllowUnsigned accepts request objects with alg "none", unless signed
+// request objects are required. Default: false.
+func (cfg *Config) SetAllowUnsigned(allow bool) *Config {
+	cfg.allowUnsigned = allow
+	return cfg
+}
+
+// SetFetchRequestURI enables fetching request objects passed by reference.
+// Only https locations registered by the client (RequestURIsProvider) are
+// fetched. Default: false.
+func (cfg *Config) SetFetchRequestURI(fetch bool) *Config {
+	cfg.fetchRequestURI = fetch
+	return cfg
+}
+
+// SetRequired rejects every authorization request without a signed request
+// object (require_signed_request_object, RFC 9101 §10.5). Default: false.
+func (cfg *Config) SetRequired(required bool) *Config {
+	cfg.required = required
+	return cfg
+}
+
+// ValidateConfig returns an error if any required configuration is missing.
+// Call this via Must rather than directly.
+func (cfg *Config) ValidateConfig() error {
+	if utils.IsNil(cfg.clientMgr) {
+		return ErrNilClientManager
+	}
+
+	if utils.IsNil(cfg.fetcher) {
+		return ErrNilJWKSFetcher
+	}
+
+	if cfg.httpClient == nil {
+		return ErrNilHTTPClient
+	}
+
+	if len(cfg.signingMethods) == 0 {
+		return ErrEmptySigningMethods
+	}
+
+	if len(cfg.audiences) == 0 {
+		return ErrEmptyAudiences
+	}
+
+	return nil
+}
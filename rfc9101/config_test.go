@@ -0,0 +1,73 @@
+package rfc9101
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	mock "github.com/tniah/authlib/mocks/rfc9101"
+	clientauth "github.com/tniah/authlib/rfc6749/client_authentication"
+)
+
+func TestConfig(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		cfg := NewConfig()
+		assert.IsType(t, &clientauth.CachingJWKSFetcher{}, cfg.fetcher)
+		assert.Equal(t, DefaultFetchTimeout, cfg.httpClient.Timeout)
+		assert.Nil(t, cfg.decrypter)
+		assert.Contains(t, cfg.signingMethods, "RS256")
+		assert.Contains(t, cfg.signingMethods, "HS256")
+		assert.NotContains(t, cfg.signingMethods, "none")
+		assert.Equal(t, DefaultLeeway, cfg.leeway)
+		assert.False(t, cfg.allowUnsigned)
+		assert.False(t, cfg.fetchRequestURI)
+		assert.False(t, cfg.required)
+		assert.Empty(t, cfg.audiences)
+
+		clientMgr := mock.NewMockClientManager(t)
+		decrypter := mock.NewMockRequestObjectDecrypter(t)
+		fetcher := clientauth.NewCachingJWKSFetcher(nil)
+		httpClient := &http.Client{}
+
+		cfg.SetClientManager(clientMgr).
+			SetJWKSFetcher(fetcher).
+			SetHTTPClient(httpClient).
+			SetDecrypter(decrypter).
+			SetSigningMethods([]string{"ES256"}).
+			SetAudiences([]string{"https://server.example.com"}).
+			SetLeeway(time.Second).
+			SetAllowUnsigned(true).
+			SetFetchRequestURI(true).
+			SetRequired(true)
+
+		assert.Equal(t, clientMgr, cfg.clientMgr)
+		assert.Equal(t, fetcher, cfg.fetcher)
+		assert.Equal(t, httpClient, cfg.httpClient)
+		assert.Equal(t, decrypter, cfg.decrypter)
+		assert.Equal(t, []string{"ES256"}, cfg.signingMethods)
+		assert.Equal(t, []string{"https://server.example.com"}, cfg.audiences)
+		assert.Equal(t, time.Second, cfg.leeway)
+		assert.True(t, cfg.allowUnsigned)
+		assert.True(t, cfg.fetchRequestURI)
+		assert.True(t, cfg.required)
+		assert.NoError(t, cfg.ValidateConfig())
+	})
+
+	t.Run("error", func(t *testing.T) {
+		cfg := NewConfig()
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrNilClientManager)
+
+		cfg.SetClientManager(mock.NewMockClientManager(t)).SetJWKSFetcher(nil)
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrNilJWKSFetcher)
+
+		cfg.SetJWKSFetcher(clientauth.NewCachingJWKSFetcher(nil)).SetHTTPClient(nil)
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrNilHTTPClient)
+
+		cfg.SetHTTPClient(&http.Client{}).SetSigningMethods(nil)
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrEmptySigningMethods)
+
+		cfg.SetSigningMethods([]string{"RS256"})
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrEmptyAudiences)
+	})
+}
@@ -0,0 +1,234 @@
+package rfc9101
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	autherrors "github.com/tniah/authlib/errors"
+	"github.com/tniah/authlib/models"
+	"github.com/tniah/authlib/requests"
+	"github.com/tniah/authlib/types"
+	"github.com/tniah/authlib/utils"
+)
+
+// ContentTypeRequestObject is the media type of request objects (RFC 9101
+// §10.2), sent as Accept when fetching a request_uri.
+const ContentTypeRequestObject = "application/oauth-authz-req+jwt"
+
+// Flow resolves JWT-secured authorization requests (RFC 9101): authorization
+// request parameters passed in a request object, by value with request or by
+// reference with request_uri. It implements AuthorizationRequestResolver;
+// register it with Server.RegisterAuthorizationRequestResolver.
+type Flow struct {
+	*Config
+}
+
+// New creates a Flow from cfg without validating it. Prefer Must for
+// production use.
+func New(cfg *Config) *Flow {
+	return &Flow{cfg}
+}
+
+// Must returns a validated Flow or an error if any required Config dependency
+// is missing.
+func Must(cfg *Config) (*Flow, error) {
+	if err := cfg.ValidateConfig(); err != nil {
+		return nil, err
+	}
+
+	return New(cfg), nil
+}
+
+// ProvideMetadata adds the request and request_uri parameter support, the
+// accepted request object signing algorithms and
+// require_signed_request_object to the authorization server metadata
+// (RFC 9101 §10.5).
+func (f *Flow) ProvideMetadata(md types.Metadata) {
+	md.Set(types.MetadataRequestParameterSupported, true)
+	md.Set(types.MetadataRequestURIParameterSupported, f.fetchRequestURI)
+	md.Add(types.MetadataRequestObjectSigningAlgValuesSupported, f.signingMethods...)
+	if f.allowUnsigned && !f.required {
+		md.Add(types.MetadataRequestObjectSigningAlgValuesSupported, algNone)
+	}
+
+	md.Set(types.MetadataRequireSignedRequestObject, f.required)
+}
+
+// ResolveAuthorizationRequest replaces an authorization request carrying a
+// request object with the request described by the object, whose parameters
+// take precedence over the ones sent outside of it. Requests without a
+// request object are returned unchanged, unless signed request objects are
+// required by the server or the client. request_uri values using the urn
+// scheme, such as the ones issued for pushed authorization requests, are left
+// to other resolvers.
+func (f *Flow) ResolveAuthorizationRequest(r *requests.AuthorizationRequest) (*requests.AuthorizationRequest, error) {
+	byReference := r.RequestURI != "" && !strings.HasPrefix(r.RequestURI, "urn:")
+	if r.RequestObject == "" && !byReference {
+		return r, f.checkRequired(r)
+	}
+
+	if r.RequestObject != "" && byReference {
+		return nil, autherrors.InvalidRequestError().
+			WithDescription("\"request\" and \"request_uri\" cannot be used together").
+			WithState(r.State)
+	}
+
+	client, err := f.queryClient(r)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := r.Request.Context()
+	object := r.RequestObject
+	if byReference {
+		if object, err = f.fetchRequestObject(ctx, client, r.RequestURI); err != nil {
+			return nil, withState(err, r.State)
+		}
+	}
+
+	claims, err := f.parseRequestObject(ctx, client, object)
+	if err != nil {
+		return nil, withState(err, r.State)
+	}
+
+	_ = r.Request.ParseForm()
+	resolved, err := requests.NewAuthorizationRequestFromValues(r.Request, mergeParams(r.Request.Form, claims))
+	if err != nil {
+		return nil, err
+	}
+
+	resolved.RequestURI = r.RequestURI
+	resolved.RequestObject = object
+	return resolved, nil
+}
+
+// queryClient loads the client named by client_id, which is required with a
+// request object (RFC 9101 §5).
+func (f *Flow) queryClient(r *requests.AuthorizationRequest) (models.Client, error) {
+	if err := r.ValidateClientID(true); err != nil {
+		return nil, err
+	}
+
+	client, err := f.clientMgr.QueryByClientID(r.Request.Context(), r.ClientID)
+	if err != nil {
+		return nil, err
+	}
+
+	if utils.IsNil(client) {
+		return nil, autherrors.InvalidRequestError().
+			WithDescription("No client was found that matches \"client_id\" value").
+			WithState(r.State)
+	}
+
+	return client, nil
+}
+
+// checkRequired rejects an authorization request without a request object
+// when signed request objects are required by the server or the client.
+func (f *Flow) checkRequired(r *requests.AuthorizationRequest) error {
+	required := f.required
+	if !required && r.ClientID != "" {
+		client, err := f.clientMgr.QueryByClientID(r.Request.Context(), r.ClientID)
+		if err != nil {
+			return err
+		}
+
+		required = f.signedRequired(client)
+	}
+
+	if required {
+		return autherrors.InvalidRequestError().
+			WithDescription("authorization requests must use a signed request object").
+			WithState(r.State)
+	}
+
+	return nil
+}
+
+// fetchRequestObject retrieves the request object at requestURI, which must
+// be an https location registered by client (RFC 9101 §5.2.3).
+func (f *Flow) fetchRequestObject(ctx context.Context, client models.Client, requestURI string) (string, error) {
+	if !f.fetchRequestURI {
+		return "", invalidRequestURI("\"request_uri\" is not supported")
+	}
+
+	u, err := url.Parse(requestURI)
+	if err != nil || u.Scheme != "https" {
+		return "", invalidRequestURI("\"request_uri\" must use https")
+	}
+
+	if !registeredRequestURI(client, requestURI) {
+		return "", invalidRequestURI("\"request_uri\" is not registered for the client")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURI, nil)
+	if err != nil {
+		return "", invalidRequestURI("\"request_uri\" is malformed")
+	}
+	req.Header.Set("Accept", ContentTypeRequestObject)
+
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return "", invalidRequestURI("\"request_uri\" cannot be retrieved")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", invalidRequestURI(fmt.Sprintf("\"request_uri\" answered with status %d", resp.StatusCode))
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, MaxRequestObjectSize+1))
+	if err != nil {
+		return "", invalidRequestURI("\"request_uri\" cannot be retrieved")
+	}
+
+	if len(body) > MaxRequestObjectSize {
+		return "", invalidRequestURI("request object at \"request_uri\" is too large")
+	}
+
+	return strings.TrimSpace(string(body)), nil
+}
+
+// registeredRequestURI reports whether client registered requestURI. The
+// fragment is ignored: clients may append one to make each document unique
+// (OIDC Core §6.2).
+func registeredRequestURI(client models.Client, requestURI string) bool {
+	p, ok := client.(RequestURIsProvider)
+	if !ok {
+		return false
+	}
+
+	target := withoutFragment(requestURI)
+	return slices.ContainsFunc(p.GetRequestURIs(), func(uri string) bool {
+		return withoutFragment(uri) == target
+	})
+}
+
+func withoutFragment(uri string) string {
+	if i := strings.IndexByte(uri, '#'); i >= 0 {
+		return uri[:i]
+	}
+
+	return uri
+}
+
+func invalidRequestURI(description string) *autherrors.AuthLibError {
+	return autherrors.InvalidRequestURIError().WithDescription(description)
+}
+
+// withState adds state to the AuthLibError in err, so the client can correlate
+// the error.
+func withState(err error, state string) error {
+	var authErr *autherrors.AuthLibError
+	if errors.As(err, &authErr) {
+		authErr.WithState(state)
+	}
+
+	return err
+}
@@ -0,0 +1,266 @@
+package rfc9101
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	autherrors "github.com/tniah/authlib/errors"
+	"github.com/tniah/authlib/integrations/sql"
+	rfc9101 "github.com/tniah/authlib/mocks/rfc9101"
+	"github.com/tniah/authlib/requests"
+	"github.com/tniah/authlib/types"
+)
+
+func newAuthorizationRequest(t *testing.T, params url.Values) *requests.AuthorizationRequest {
+	r := httptest.NewRequest(http.MethodGet, "/authorize?"+params.Encode(), nil)
+	authReq, err := requests.NewAuthorizationRequestFromHttp(r)
+	require.NoError(t, err)
+	return authReq
+}
+
+func TestMust(t *testing.T) {
+	f, err := Must(newTestConfig().SetClientManager(rfc9101.NewMockClientManager(t)))
+	assert.NoError(t, err)
+	assert.NotNil(t, f)
+
+	f, err = Must(NewConfig())
+	assert.ErrorIs(t, err, ErrNilClientManager)
+	assert.Nil(t, f)
+}
+
+func TestFlow_ResolveAuthorizationRequest(t *testing.T) {
+	key := newClientKey(t, "key-1")
+	client := &sql.Client{ClientID: testClientID, JWKs: key.jwks(t)}
+
+	newFlow := func(t *testing.T, c *sql.Client) *Flow {
+		clientMgr := rfc9101.NewMockClientManager(t)
+		if c != nil {
+			clientMgr.EXPECT().QueryByClientID(mock.Anything, testClientID).Return(c, nil).Maybe()
+		}
+		return New(newTestConfig().SetClientManager(clientMgr))
+	}
+
+	t.Run("plain_request_unchanged", func(t *testing.T) {
+		r := newAuthorizationRequest(t, url.Values{"client_id": {testClientID}, "response_type": {"code"}})
+
+		resolved, err := newFlow(t, client).ResolveAuthorizationRequest(r)
+		assert.NoError(t, err)
+		assert.Same(t, r, resolved)
+	})
+
+	t.Run("urn_request_uri_unchanged", func(t *testing.T) {
+		r := newAuthorizationRequest(t, url.Values{
+			"client_id":   {testClientID},
+			"request_uri": {"urn:ietf:params:oauth:request_uri:abc"},
+		})
+
+		resolved, err := newFlow(t, client).ResolveAuthorizationRequest(r)
+		assert.NoError(t, err)
+		assert.Same(t, r, resolved)
+	})
+
+	t.Run("success_by_value", func(t *testing.T) {
+		claims := objectClaims()
+		claims["scope"] = "openid email"
+		object := key.sign(t, claims)
+		r := newAuthorizationRequest(t, url.Values{
+			"client_id":     {testClientID},
+			"response_type": {"code"},
+			"scope":         {"openid"},
+			"ui_locales":    {"en"},
+			"request":       {object},
+		})
+
+		resolved, err := newFlow(t, client).ResolveAuthorizationRequest(r)
+		require.NoError(t, err)
+		assert.Equal(t, testClientID, resolved.ClientID)
+		assert.Equal(t, "af0ifjsldkj", resolved.State)
+		assert.Equal(t, "https://client.example.org/cb", resolved.RedirectURI)
+		assert.ElementsMatch(t, []string{"openid", "email"}, resolved.Scopes.String())
+		assert.Equal(t, "en", resolved.Request.FormValue("ui_locales"))
+		assert.Empty(t, resolved.Request.FormValue("request"))
+		assert.Equal(t, object, resolved.RequestObject)
+	})
+
+	t.Run("error_required_by_server", func(t *testing.T) {
+		f := newFlow(t, nil)
+		f.SetRequired(true)
+		r := newAuthorizationRequest(t, url.Values{"client_id": {testClientID}, "state": {"xyz"}})
+
+		_, err := f.ResolveAuthorizationRequest(r)
+		assertErrorCode(t, err, autherrors.ErrInvalidRequest)
+		assert.Equal(t, "xyz", autherrors.ToAuthLibError(err).State)
+	})
+
+	t.Run("error_required_by_client", func(t *testing.T) {
+		c := &sql.Client{ClientID: testClientID, RequireSignedRequestObject: true}
+		r := newAuthorizationRequest(t, url.Values{"client_id": {testClientID}})
+
+		_, err := newFlow(t, c).ResolveAuthorizationRequest(r)
+		assertErrorCode(t, err, autherrors.ErrInvalidRequest)
+	})
+
+	t.Run("error_request_and_request_uri", func(t *testing.T) {
+		r := newAuthorizationRequest(t, url.Values{
+			"client_id":   {testClientID},
+			"request":     {key.sign(t, objectClaims())},
+			"request_uri": {"https://client.example.org/request.jwt"},
+		})
+
+		_, err := newFlow(t, client).ResolveAuthorizationRequest(r)
+		assertErrorCode(t, err, autherrors.ErrInvalidRequest)
+	})
+
+	t.Run("error_missing_client_id", func(t *testing.T) {
+		r := newAuthorizationRequest(t, url.Values{"request": {key.sign(t, objectClaims())}})
+
+		_, err := newFlow(t, nil).ResolveAuthorizationRequest(r)
+		assertErrorCode(t, err, autherrors.ErrInvalidRequest)
+	})
+
+	t.Run("error_unknown_client", func(t *testing.T) {
+		clientMgr := rfc9101.NewMockClientManager(t)
+		clientMgr.EXPECT().QueryByClientID(mock.Anything, testClientID).Return(nil, nil).Once()
+		r := newAuthorizationRequest(t, url.Values{"client_id": {testClientID}, "request": {key.sign(t, objectClaims())}})
+
+		_, err := New(newTestConfig().SetClientManager(clientMgr)).ResolveAuthorizationRequest(r)
+		assertErrorCode(t, err, autherrors.ErrInvalidRequest)
+	})
+
+	t.Run("error_on_client_query_failure", func(t *testing.T) {
+		clientMgr := rfc9101.NewMockClientManager(t)
+		clientMgr.EXPECT().QueryByClientID(mock.Anything, testClientID).Return(nil, errors.New("unexpected error")).Once()
+		r := newAuthorizationRequest(t, url.Values{"client_id": {testClientID}, "request": {key.sign(t, objectClaims())}})
+
+		_, err := New(newTestConfig().SetClientManager(clientMgr)).ResolveAuthorizationRequest(r)
+		assert.EqualError(t, err, "unexpected error")
+	})
+
+	t.Run("error_invalid_object_keeps_state", func(t *testing.T) {
+		r := newAuthorizationRequest(t, url.Values{
+			"client_id": {testClientID},
+			"state":     {"xyz"},
+			"request":   {unsignedObject(t, objectClaims())},
+		})
+
+		_, err := newFlow(t, client).ResolveAuthorizationRequest(r)
+		assertErrorCode(t, err, autherrors.ErrInvalidRequestObject)
+		assert.Equal(t, "xyz", autherrors.ToAuthLibError(err).State)
+	})
+}
+
+func TestFlow_ResolveAuthorizationRequest_ByReference(t *testing.T) {
+	key := newClientKey(t, "key-1")
+	object := key.sign(t, objectClaims())
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/request.jwt" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		assert.Equal(t, ContentTypeRequestObject, r.Header.Get("Accept"))
+		w.Header().Set("Content-Type", ContentTypeRequestObject)
+		_, _ = w.Write([]byte(object))
+	}))
+	defer srv.Close()
+
+	requestURI := srv.URL + "/request.jwt"
+	missingURI := srv.URL + "/missing.jwt"
+	client := &sql.Client{
+		ClientID:    testClientID,
+		JWKs:        key.jwks(t),
+		RequestURIs: []string{requestURI, missingURI, "http://client.example.org/request.jwt"},
+	}
+
+	newFlow := func(t *testing.T) *Flow {
+		clientMgr := rfc9101.NewMockClientManager(t)
+		clientMgr.EXPECT().QueryByClientID(mock.Anything, testClientID).Return(client, nil).Once()
+		return New(newTestConfig().
+			SetClientManager(clientMgr).
+			SetHTTPClient(srv.Client()).
+			SetFetchRequestURI(true))
+	}
+
+	t.Run("success", func(t *testing.T) {
+		r := newAuthorizationRequest(t, url.Values{"client_id": {testClientID}, "request_uri": {requestURI + "#a1b2"}})
+
+		resolved, err := newFlow(t).ResolveAuthorizationRequest(r)
+		require.NoError(t, err)
+		assert.Equal(t, "code", resolved.ResponseType.String())
+		assert.Equal(t, "af0ifjsldkj", resolved.State)
+		assert.Equal(t, requestURI+"#a1b2", resolved.RequestURI)
+		assert.Equal(t, object, resolved.RequestObject)
+	})
+
+	errorCases := []struct {
+		name       string
+		requestURI string
+	}{
+		{"not_registered", srv.URL + "/other.jwt"},
+		{"not_https", "http://client.example.org/request.jwt"},
+		{"bad_status", missingURI},
+	}
+
+	for _, tc := range errorCases {
+		t.Run("error_"+tc.name, func(t *testing.T) {
+			r := newAuthorizationRequest(t, url.Values{"client_id": {testClientID}, "request_uri": {tc.requestURI}})
+
+			_, err := newFlow(t).ResolveAuthorizationRequest(r)
+			assertErrorCode(t, err, autherrors.ErrInvalidRequestURI)
+		})
+	}
+
+	t.Run("error_fetching_disabled", func(t *testing.T) {
+		f := newFlow(t)
+		f.SetFetchRequestURI(false)
+		r := newAuthorizationRequest(t, url.Values{"client_id": {testClientID}, "request_uri": {requestURI}})
+
+		_, err := f.ResolveAuthorizationRequest(r)
+		assertErrorCode(t, err, autherrors.ErrInvalidRequestURI)
+	})
+}
+
+func TestFlow_ProvideMetadata(t *testing.T) {
+	t.Run("signed_request_objects", func(t *testing.T) {
+		md := types.Metadata{}
+		New(newTestConfig().SetSigningMethods([]string{"RS256"}).SetRequired(true)).ProvideMetadata(md)
+
+		assert.Equal(t, types.Metadata{
+			types.MetadataRequestParameterSupported:              true,
+			types.MetadataRequestURIParameterSupported:           false,
+			types.MetadataRequestObjectSigningAlgValuesSupported: []string{"RS256"},
+			types.MetadataRequireSignedRequestObject:             true,
+		}, md)
+	})
+
+	t.Run("unsigned_request_objects", func(t *testing.T) {
+		md := types.Metadata{}
+		New(newTestConfig().SetSigningMethods([]string{"RS256"}).SetAllowUnsigned(true).SetFetchRequestURI(true)).ProvideMetadata(md)
+
+		assert.Equal(t, true, md[types.MetadataRequestURIParameterSupported])
+		assert.Equal(t, []string{"RS256", "none"}, md[types.MetadataRequestObjectSigningAlgValuesSupported])
+		assert.Equal(t, false, md[types.MetadataRequireSignedRequestObject])
+	})
+}
+
+func TestWithState(t *testing.T) {
+	t.Run("wrapped_auth_error", func(t *testing.T) {
+		authErr := autherrors.InvalidRequestObjectError()
+		err := withState(fmt.Errorf("resolve: %w", authErr), "xyz")
+		assert.ErrorIs(t, err, authErr)
+		assert.Equal(t, "xyz", authErr.State)
+	})
+
+	t.Run("other_error", func(t *testing.T) {
+		err := errors.New("boom")
+		assert.Equal(t, err, withState(err, "xyz"))
+	})
+}
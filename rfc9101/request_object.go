@@ -0,0 +1,226 @@
+package rfc9101
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	autherrors "github.com/tniah/authlib/errors"
+	"github.com/tniah/authlib/models"
+	clientauth "github.com/tniah/authlib/rfc6749/client_authentication"
+	"github.com/tniah/authlib/utils"
+)
+
+// algNone is the alg of unsigned request objects (RFC 7519 §6).
+const algNone = "none"
+
+// registeredClaims are JWT claims of the request object that are not
+// authorization request parameters.
+var registeredClaims = []string{"iss", "sub", "aud", "exp", "nbf", "iat", "jti"}
+
+var errNoVerificationKey = errors.New("no verification key of the client matches the request object")
+
+// isEncrypted reports whether object is a JWE compact serialization, which
+// has five parts where a JWS has three.
+func isEncrypted(object string) bool {
+	return strings.Count(object, ".") == 4
+}
+
+// parseRequestObject decrypts object if needed, verifies its signature with
+// the keys of client, and returns its claims.
+func (cfg *Config) parseRequestObject(ctx context.Context, client models.Client, object string) (jwt.MapClaims, error) {
+	if isEncrypted(object) {
+		if utils.IsNil(cfg.decrypter) {
+			return nil, invalidRequestObject("encrypted request objects are not supported")
+		}
+
+		plaintext, err := cfg.decrypter.Decrypt(ctx, client, object)
+		if err != nil {
+			return nil, invalidRequestObject("request object cannot be decrypted")
+		}
+
+		object = plaintext
+	}
+
+	header, err := objectHeader(object)
+	if err != nil {
+		return nil, invalidRequestObject("request object is malformed")
+	}
+
+	alg, _ := header["alg"].(string)
+	if registered := clientSigningAlg(client); registered != "" && alg != registered {
+		return nil, invalidRequestObject(fmt.Sprintf("request object must be signed with \"%s\"", registered))
+	}
+
+	claims := jwt.MapClaims{}
+	if alg == algNone {
+		if !cfg.allowUnsigned || cfg.signedRequired(client) {
+			return nil, invalidRequestObject("request object must be signed")
+		}
+
+		_, err = jwt.NewParser(jwt.WithValidMethods([]string{algNone}), jwt.WithLeeway(cfg.leeway)).
+			ParseWithClaims(object, claims, func(*jwt.Token) (interface{}, error) {
+				return jwt.UnsafeAllowNoneSignatureType, nil
+			})
+	} else {
+		_, err = jwt.NewParser(jwt.WithValidMethods(cfg.signingMethods), jwt.WithLeeway(cfg.leeway)).
+			ParseWithClaims(object, claims, func(t *jwt.Token) (interface{}, error) {
+				return cfg.verificationKey(ctx, client, t)
+			})
+	}
+	if err != nil {
+		return nil, invalidRequestObject("request object is invalid")
+	}
+
+	if err = cfg.checkClaims(client, claims); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+// checkClaims verifies that the request object was issued by client for
+// this authorization server (RFC 9101 §4, §5, §6.3): client_id is required
+// and names client, and aud contains one of the configured audiences.
+func (cfg *Config) checkClaims(client models.Client, claims jwt.MapClaims) error {
+	clientID := client.GetClientID()
+	if v, ok := claims["client_id"]; !ok || v != clientID {
+		return invalidRequestObject("\"client_id\" of the request object does not match the request")
+	}
+
+	if v, ok := claims["iss"]; ok && v != clientID {
+		return invalidRequestObject("\"iss\" of the request object must be the client_id")
+	}
+
+	aud, err := claims.GetAudience()
+	if err != nil {
+		return invalidRequestObject("\"aud\" of the request object is malformed")
+	}
+
+	for _, a := range aud {
+		if slices.Contains(cfg.audiences, a) {
+			return nil
+		}
+	}
+
+	return invalidRequestObject("\"aud\" of the request object does not identify this server")
+}
+
+// verificationKey returns the client secret for HS* request objects, and the
+// key of the client's JWK Set matching the kid and alg otherwise, as selected
+// by clientauth.ClientJWKSKey.
+func (cfg *Config) verificationKey(ctx context.Context, client models.Client, t *jwt.Token) (interface{}, error) {
+	if _, ok := t.Method.(*jwt.SigningMethodHMAC); ok {
+		p, ok := client.(clientauth.ClientSecretProvider)
+		if !ok || p.GetClientSecret() == "" {
+			return nil, errNoVerificationKey
+		}
+
+		return []byte(p.GetClientSecret()), nil
+	}
+
+	return clientauth.ClientJWKSKey(ctx, cfg.fetcher, client, t)
+}
+
+// signedRequired reports whether signed request objects are required by the
+// server or by client.
+func (cfg *Config) signedRequired(client models.Client) bool {
+	if cfg.required {
+		return true
+	}
+
+	if utils.IsNil(client) {
+		return false
+	}
+
+	p, ok := client.(SignedRequestObjectProvider)
+	return ok && p.GetRequireSignedRequestObject()
+}
+
+// clientSigningAlg returns the request_object_signing_alg registered by
+// client, or "".
+func clientSigningAlg(client models.Client) string {
+	if p, ok := client.(RequestObjectSigningAlgProvider); ok {
+		return p.GetRequestObjectSigningAlg()
+	}
+
+	return ""
+}
+
+// objectHeader decodes the JOSE header of the compact JWS object.
+func objectHeader(object string) (map[string]interface{}, error) {
+	parts := strings.Split(object, ".")
+	if len(parts) != 3 {
+		return nil, jwt.ErrTokenMalformed
+	}
+
+	data, err := jwt.NewParser().DecodeSegment(parts[0])
+	if err != nil {
+		return nil, err
+	}
+
+	header := map[string]interface{}{}
+	if err = json.Unmarshal(data, &header); err != nil {
+		return nil, err
+	}
+
+	return header, nil
+}
+
+// mergeParams returns params overridden by the claims of the request object,
+// which take precedence (OIDC Core §6.3.3). JWT claims that are not
+// authorization request parameters are left out, as are request and
+// request_uri, which cannot be nested (RFC 9101 §4).
+func mergeParams(params url.Values, claims jwt.MapClaims) url.Values {
+	merged := url.Values{}
+	for k, v := range params {
+		merged[k] = append([]string(nil), v...)
+	}
+
+	for k, v := range claims {
+		if slices.Contains(registeredClaims, k) {
+			continue
+		}
+
+		if s, ok := paramValue(v); ok {
+			merged.Set(k, s)
+		}
+	}
+
+	merged.Del("request")
+	merged.Del("request_uri")
+	return merged
+}
+
+// paramValue converts a claim value to its parameter form. Numbers and
+// booleans are formatted, and JSON objects and arrays, such as the OIDC
+// claims parameter, are serialized.
+func paramValue(v interface{}) (string, bool) {
+	switch val := v.(type) {
+	case nil:
+		return "", false
+	case string:
+		return val, true
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(val), true
+	default:
+		data, err := json.Marshal(val)
+		if err != nil {
+			return "", false
+		}
+
+		return string(data), true
+	}
+}
+
+func invalidRequestObject(description string) *autherrors.AuthLibError {
+	return autherrors.InvalidRequestObjectError().WithDescription(description)
+}
@@ -0,0 +1,240 @@
+package rfc9101
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	autherrors "github.com/tniah/authlib/errors"
+	"github.com/tniah/authlib/integrations/sql"
+	rfc6749 "github.com/tniah/authlib/mocks/rfc6749/client_authentication"
+	rfc9101 "github.com/tniah/authlib/mocks/rfc9101"
+	"github.com/tniah/authlib/utils"
+)
+
+const (
+	testClientID = "s6BhdRkqt3"
+	testIssuer   = "https://server.example.com"
+	testJWKsURI  = "https://client.example.com/jwks.json"
+)
+
+type clientKey struct {
+	priv *ecdsa.PrivateKey
+	kid  string
+}
+
+func newClientKey(t *testing.T, kid string) *clientKey {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return &clientKey{priv: priv, kid: kid}
+}
+
+func (k *clientKey) jwk() utils.JWK {
+	return utils.JWK{
+		Kty: "EC",
+		Kid: k.kid,
+		Crv: "P-256",
+		X:   base64.RawURLEncoding.EncodeToString(k.priv.X.FillBytes(make([]byte, 32))),
+		Y:   base64.RawURLEncoding.EncodeToString(k.priv.Y.FillBytes(make([]byte, 32))),
+	}
+}
+
+func (k *clientKey) jwks(t *testing.T) []byte {
+	data, err := json.Marshal(&utils.JWKSet{Keys: []utils.JWK{k.jwk()}})
+	require.NoError(t, err)
+	return data
+}
+
+func (k *clientKey) sign(t *testing.T, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+	token.Header["kid"] = k.kid
+	object, err := token.SignedString(k.priv)
+	require.NoError(t, err)
+	return object
+}
+
+func unsignedObject(t *testing.T, claims jwt.MapClaims) string {
+	object, err := jwt.NewWithClaims(jwt.SigningMethodNone, claims).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	require.NoError(t, err)
+	return object
+}
+
+func objectClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":           testClientID,
+		"aud":           testIssuer,
+		"client_id":     testClientID,
+		"response_type": "code",
+		"redirect_uri":  "https://client.example.org/cb",
+		"scope":         "openid",
+		"state":         "af0ifjsldkj",
+		"exp":           time.Now().Add(time.Minute).Unix(),
+	}
+}
+
+// newTestConfig returns a NewConfig accepting request objects for testIssuer.
+func newTestConfig() *Config {
+	return NewConfig().SetAudiences([]string{testIssuer})
+}
+
+func assertErrorCode(t *testing.T, err error, code error) {
+	t.Helper()
+	require.Error(t, err)
+	assert.Equal(t, code, autherrors.ToAuthLibError(err).Code)
+}
+
+func TestConfig_parseRequestObject(t *testing.T) {
+	ctx := context.Background()
+	key := newClientKey(t, "key-1")
+	client := &sql.Client{ClientID: testClientID, JWKs: key.jwks(t)}
+
+	t.Run("success_with_inline_jwks", func(t *testing.T) {
+		claims, err := newTestConfig().parseRequestObject(ctx, client, key.sign(t, objectClaims()))
+		require.NoError(t, err)
+		assert.Equal(t, "code", claims["response_type"])
+	})
+
+	t.Run("success_with_jwks_uri", func(t *testing.T) {
+		fetcher := rfc6749.NewMockJWKSFetcher(t)
+		fetcher.EXPECT().FetchJWKS(mock.Anything, testJWKsURI, false).Return(&utils.JWKSet{Keys: []utils.JWK{key.jwk()}}, nil).Once()
+
+		c := &sql.Client{ClientID: testClientID, JWKsURI: testJWKsURI}
+		_, err := newTestConfig().SetJWKSFetcher(fetcher).parseRequestObject(ctx, c, key.sign(t, objectClaims()))
+		assert.NoError(t, err)
+	})
+
+	t.Run("success_refreshes_jwks_on_unknown_kid", func(t *testing.T) {
+		rotated := newClientKey(t, "key-2")
+		fetcher := rfc6749.NewMockJWKSFetcher(t)
+		fetcher.EXPECT().FetchJWKS(mock.Anything, testJWKsURI, false).Return(&utils.JWKSet{Keys: []utils.JWK{key.jwk()}}, nil).Once()
+		fetcher.EXPECT().FetchJWKS(mock.Anything, testJWKsURI, true).Return(&utils.JWKSet{Keys: []utils.JWK{rotated.jwk()}}, nil).Once()
+
+		c := &sql.Client{ClientID: testClientID, JWKsURI: testJWKsURI}
+		_, err := newTestConfig().SetJWKSFetcher(fetcher).parseRequestObject(ctx, c, rotated.sign(t, objectClaims()))
+		assert.NoError(t, err)
+	})
+
+	t.Run("success_with_client_secret", func(t *testing.T) {
+		object, err := jwt.NewWithClaims(jwt.SigningMethodHS256, objectClaims()).SignedString([]byte("secret"))
+		require.NoError(t, err)
+
+		c := &sql.Client{ClientID: testClientID, ClientSecret: "secret"}
+		_, err = newTestConfig().parseRequestObject(ctx, c, object)
+		assert.NoError(t, err)
+	})
+
+	t.Run("success_unsigned_when_allowed", func(t *testing.T) {
+		_, err := newTestConfig().SetAllowUnsigned(true).parseRequestObject(ctx, client, unsignedObject(t, objectClaims()))
+		assert.NoError(t, err)
+	})
+
+	t.Run("success_with_decrypter", func(t *testing.T) {
+		const jwe = "eyJhbGciOiJSU0EtT0FFUCIsImVuYyI6IkEyNTZHQ00ifQ.a.b.c.d"
+		object := key.sign(t, objectClaims())
+		decrypter := rfc9101.NewMockRequestObjectDecrypter(t)
+		decrypter.EXPECT().Decrypt(mock.Anything, client, jwe).Return(object, nil).Once()
+
+		claims, err := newTestConfig().SetDecrypter(decrypter).parseRequestObject(ctx, client, jwe)
+		require.NoError(t, err)
+		assert.Equal(t, testClientID, claims["client_id"])
+	})
+
+	errorCases := []struct {
+		name   string
+		cfg    *Config
+		client *sql.Client
+		object string
+	}{
+		{"malformed", newTestConfig(), client, "not-a-jwt"},
+		{"unsigned_by_default", newTestConfig(), client, unsignedObject(t, objectClaims())},
+		{"unsigned_when_client_requires_signed", newTestConfig().SetAllowUnsigned(true), &sql.Client{ClientID: testClientID, RequireSignedRequestObject: true}, unsignedObject(t, objectClaims())},
+		{"unsigned_when_server_requires_signed", newTestConfig().SetAllowUnsigned(true).SetRequired(true), client, unsignedObject(t, objectClaims())},
+		{"signed_by_other_key", newTestConfig(), client, newClientKey(t, "key-1").sign(t, objectClaims())},
+		{"alg_not_registered_by_client", newTestConfig(), &sql.Client{ClientID: testClientID, JWKs: key.jwks(t), RequestObjectSigningAlg: "RS256"}, key.sign(t, objectClaims())},
+		{"alg_not_accepted", newTestConfig().SetSigningMethods([]string{"RS256"}), client, key.sign(t, objectClaims())},
+		{"client_without_keys", newTestConfig(), &sql.Client{ClientID: testClientID}, key.sign(t, objectClaims())},
+		{"expired", newTestConfig(), client, key.sign(t, func() jwt.MapClaims {
+			c := objectClaims()
+			c["exp"] = time.Now().Add(-time.Hour).Unix()
+			return c
+		}())},
+		{"client_id_mismatch", newTestConfig(), client, key.sign(t, func() jwt.MapClaims {
+			c := objectClaims()
+			c["client_id"] = "other"
+			return c
+		}())},
+		{"iss_mismatch", newTestConfig(), client, key.sign(t, func() jwt.MapClaims {
+			c := objectClaims()
+			c["iss"] = "other"
+			return c
+		}())},
+		{"client_id_missing", newTestConfig(), client, key.sign(t, func() jwt.MapClaims {
+			c := objectClaims()
+			delete(c, "client_id")
+			return c
+		}())},
+		{"aud_missing", newTestConfig(), client, key.sign(t, func() jwt.MapClaims {
+			c := objectClaims()
+			delete(c, "aud")
+			return c
+		}())},
+		{"aud_mismatch", newTestConfig().SetAudiences([]string{"https://other.example.com"}), client, key.sign(t, objectClaims())},
+		{"encrypted_without_decrypter", newTestConfig(), client, "a.b.c.d.e"},
+	}
+
+	for _, tc := range errorCases {
+		t.Run("error_"+tc.name, func(t *testing.T) {
+			_, err := tc.cfg.parseRequestObject(ctx, tc.client, tc.object)
+			assertErrorCode(t, err, autherrors.ErrInvalidRequestObject)
+		})
+	}
+
+	t.Run("error_on_decryption_failure", func(t *testing.T) {
+		decrypter := rfc9101.NewMockRequestObjectDecrypter(t)
+		decrypter.EXPECT().Decrypt(mock.Anything, client, "a.b.c.d.e").Return("", errors.New("bad key")).Once()
+
+		_, err := newTestConfig().SetDecrypter(decrypter).parseRequestObject(ctx, client, "a.b.c.d.e")
+		assertErrorCode(t, err, autherrors.ErrInvalidRequestObject)
+	})
+}
+
+func TestMergeParams(t *testing.T) {
+	params := url.Values{
+		"client_id":   {testClientID},
+		"scope":       {"profile"},
+		"nonce":       {"n-0S6_WzA2Mj"},
+		"request":     {"eyJ..."},
+		"request_uri": {"https://client.example.org/request.jwt"},
+	}
+	claims := jwt.MapClaims{
+		"iss":     testClientID,
+		"exp":     float64(1700000000),
+		"scope":   "openid email",
+		"max_age": float64(300),
+		"claims":  map[string]interface{}{"userinfo": map[string]interface{}{"email": nil}},
+		"ignored": nil,
+		"flag":    true,
+	}
+
+	merged := mergeParams(params, claims)
+	assert.Equal(t, url.Values{
+		"client_id": {testClientID},
+		"scope":     {"openid email"},
+		"nonce":     {"n-0S6_WzA2Mj"},
+		"max_age":   {"300"},
+		"claims":    {`{"userinfo":{"email":null}}`},
+		"flag":      {"true"},
+	}, merged)
+	assert.Equal(t, []string{"profile"}, params["scope"])
+}
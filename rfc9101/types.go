@@ -0,0 +1,47 @@
+package rfc9101
+
+import (
+	"context"
+
+	"github.com/tniah/authlib/models"
+)
+
+// ClientManager looks up the client named by the client_id of an
+// authorization request.
+type ClientManager interface {
+	// QueryByClientID retrieves a client by its client_id. Return (nil, nil)
+	// when it does not exist.
+	QueryByClientID(ctx context.Context, clientID string) (models.Client, error)
+}
+
+// RequestObjectDecrypter decrypts request objects encrypted to the
+// authorization server (RFC 9101 §6.1). Implement it with the JWE library and
+// keys of your choice.
+type RequestObjectDecrypter interface {
+	// Decrypt returns the plaintext of the JWE compact serialization jwe sent
+	// by client: a signed or unsigned request object.
+	Decrypt(ctx context.Context, client models.Client, jwe string) (string, error)
+}
+
+// RequestObjectSigningAlgProvider is implemented by clients that can register
+// the request_object_signing_alg metadata (OIDC Dynamic Client Registration
+// §2). When it returns a non-empty value, request objects of the client must
+// be signed with that algorithm.
+type RequestObjectSigningAlgProvider interface {
+	GetRequestObjectSigningAlg() string
+}
+
+// RequestURIsProvider is implemented by clients that can register the
+// request_uris metadata (OIDC Dynamic Client Registration §2). Only those
+// locations are fetched for the client's request_uri values.
+type RequestURIsProvider interface {
+	GetRequestURIs() []string
+}
+
+// SignedRequestObjectProvider is implemented by clients that can register the
+// require_signed_request_object metadata (RFC 9101 §10.5). When it returns
+// true, the client's authorization requests are only accepted with a signed
+// request object.
+type SignedRequestObjectProvider interface {
+	GetRequireSignedRequestObject() bool
+}
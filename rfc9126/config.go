@@ -0,0 +1,187 @@
+package rfc9126
+
+import (
+	"errors"
+	"time"
+
+	"github.com/tniah/authlib/types"
+	"github.com/tniah/authlib/utils"
+)
+
+const (
+	// EndpointNamePushedAuthorizationRequest is the default endpoint name
+	// used to register the pushed authorization request handler with the
+	// server.
+	EndpointNamePushedAuthorizationRequest = "pushed_authorization_request"
+	// RequestURIPrefix starts every request_uri issued by the endpoint
+	// (RFC 9126 §2.2).
+	RequestURIPrefix = "urn:ietf:params:oauth:request_uri:"
+	// DefaultExpiresIn is the lifetime of a request_uri. RFC 9126 §2.2
+	// suggests a value between 5 and 600 seconds.
+	DefaultExpiresIn = time.Minute
+	// DefaultRequestURILength is the length of the random part of a
+	// request_uri.
+	DefaultRequestURILength = 32
+)
+
+var (
+	ErrEmptyEndpointName      = errors.New("endpoint name is empty")
+	ErrNilClientManager       = errors.New("client manager is nil")
+	ErrNilRequestStore        = errors.New("request store is nil")
+	ErrEmptyGrants            = errors.New("no authorization grant is registered")
+	ErrEmptyClientAuthMethods = errors.New("supported client auth methods are empty")
+	ErrInvalidExpiresIn       = errors.New("expires in must be positive")
+	ErrInvalidRequestURILen   = errors.New("request uri length must be positive")
+)
+
+// Config holds all settings for PushedAuthorizationFlow. Use NewConfig to
+// obtain a value with secure defaults, then chain Set* calls to configure
+// managers.
+type Config struct {
+	endpointName string
+	clientMgr    ClientManager
+	requestStore RequestStore
+
+	// grants validate the pushed parameters. Register the same authorization
+	// flows as with Server.RegisterGrant.
+	grants []AuthorizationGrant
+
+	// resolvers expand the pushed parameters before the grant validates
+	// them. Executed in registration order.
+	resolvers []AuthorizationRequestResolver
+
+	expiresIn        time.Duration
+	requestURILength int
+
+	// supportedClientAuthMethods controls which authentication methods are
+	// accepted at the pushed authorization request endpoint.
+	supportedClientAuthMethods map[types.ClientAuthMethod]bool
+
+	// required rejects every authorization request that does not use a
+	// request_uri issued by the endpoint (RFC 9126 §5).
+	required bool
+}
+
+// NewConfig returns a Config with secure defaults:
+//   - EndpointNamePushedAuthorizationRequest as the endpoint name.
+//   - request_uri values expire after DefaultExpiresIn and are stored in a
+//     MemoryRequestStore.
+//   - Supports basic and none client authentication methods.
+//   - Plain authorization requests are accepted unless the client requires
+//     pushed authorization requests.
+func NewConfig() *Config {
+	return &Config{
+		endpointName:     EndpointNamePushedAuthorizationRequest,
+		requestStore:     NewMemoryRequestStore(),
+		expiresIn:        DefaultExpiresIn,
+		requestURILength: DefaultRequestURILength,
+		supportedClientAuthMethods: map[types.ClientAuthMethod]bool{
+			types.ClientBasicAuthentication: true,
+			types.ClientNoneAuthentication:  true,
+		},
+	}
+}
+
+// SetEndpointName overrides the endpoint name used by CheckEndpoint. Defaults
+// to EndpointNamePushedAuthorizationRequest ("pushed_authorization_request").
+func (cfg *Config) SetEndpointName(name string) *Config {
+	cfg.endpointName = name
+	return cfg
+}
+
+// SetClientManager sets the client authentication manager.
+func (cfg *Config) SetClientManager(mgr ClientManager) *Config {
+	cfg.clientMgr = mgr
+	return cfg
+}
+
+// SetRequestStore overrides the store of pushed requests. Default:
+// NewMemoryRequestStore().
+func (cfg *Config) SetRequestStore(store RequestStore) *Config {
+	cfg.requestStore = store
+	return cfg
+}
+
+// RegisterGrant adds grant to the flows used to validate pushed requests if
+// it implements AuthorizationGrant. Grants are matched in registration order.
+func (cfg *Config) RegisterGrant(grant interface{}) *Config {
+	if g, ok := grant.(AuthorizationGrant); ok {
+		cfg.grants = append(cfg.grants, g)
+	}
+
+	return cfg
+}
+
+// RegisterResolver adds resolver to the resolvers run on the pushed
+// parameters if it implements AuthorizationRequestResolver, e.g. rfc9101.Flow
+// to accept pushed request objects.
+func (cfg *Config) RegisterResolver(resolver interface{}) *Config {
+	if h, ok := resolver.(AuthorizationRequestResolver); ok {
+		cfg.resolvers = append(cfg.resolvers, h)
+	}
+
+	return cfg
+}
+
+// SetExpiresIn overrides the lifetime of a request_uri. Default:
+// DefaultExpiresIn.
+func (cfg *Config) SetExpiresIn(expiresIn time.Duration) *Config {
+	cfg.expiresIn = expiresIn
+	return cfg
+}
+
+// SetRequestURILength overrides the length of the random part of a
+// request_uri. Default: DefaultRequestURILength.
+func (cfg *Config) SetRequestURILength(l int) *Config {
+	cfg.requestURILength = l
+	return cfg
+}
+
+// SetSupportedClientAuthMethods overrides which client authentication methods
+// are accepted at the endpoint. Default: basic, none.
+func (cfg *Config) SetSupportedClientAuthMethods(methods map[types.ClientAuthMethod]bool) *Config {
+	cfg.supportedClientAuthMethods = methods
+	return cfg
+}
+
+// SetRequired rejects every authorization request that does not use a
+// request_uri issued by the endpoint (require_pushed_authorization_requests,
+// RFC 9126 §5). Default: false.
+func (cfg *Config) SetRequired(required bool) *Config {
+	cfg.required = required
+	return cfg
+}
+
+// ValidateConfig returns an error if any required configuration is missing.
+// Call this via MustPushedAuthorizationFlow rather than directly.
+func (cfg *Config) ValidateConfig() error {
+	if cfg.endpointName == "" {
+		return ErrEmptyEndpointName
+	}
+
+	if utils.IsNil(cfg.clientMgr) {
+		return ErrNilClientManager
+	}
+
+	if utils.IsNil(cfg.requestStore) {
+		return ErrNilRequestStore
+	}
+
+	if len(cfg.grants) == 0 {
+		return ErrEmptyGrants
+	}
+
+	if len(cfg.supportedClientAuthMethods) == 0 {
+		return ErrEmptyClientAuthMethods
+	}
+
+	if cfg.expiresIn <= 0 {
+		return ErrInvalidExpiresIn
+	}
+
+	if cfg.requestURILength <= 0 {
+		return ErrInvalidRequestURILen
+	}
+
+	return nil
+}
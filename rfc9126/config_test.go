@@ -0,0 +1,76 @@
+package rfc9126
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	mock "github.com/tniah/authlib/mocks/rfc9126"
+	"github.com/tniah/authlib/types"
+)
+
+func TestConfig(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		cfg := NewConfig()
+		assert.Equal(t, EndpointNamePushedAuthorizationRequest, cfg.endpointName)
+		assert.IsType(t, &MemoryRequestStore{}, cfg.requestStore)
+		assert.Equal(t, DefaultExpiresIn, cfg.expiresIn)
+		assert.Equal(t, DefaultRequestURILength, cfg.requestURILength)
+		assert.Equal(t, map[types.ClientAuthMethod]bool{
+			types.ClientBasicAuthentication: true,
+			types.ClientNoneAuthentication:  true,
+		}, cfg.supportedClientAuthMethods)
+		assert.False(t, cfg.required)
+
+		clientMgr := mock.NewMockClientManager(t)
+		store := NewMemoryRequestStore()
+		grant := mock.NewMockAuthorizationGrant(t)
+		resolver := mock.NewMockAuthorizationRequestResolver(t)
+
+		cfg.SetEndpointName("par").
+			SetClientManager(clientMgr).
+			SetRequestStore(store).
+			RegisterGrant(grant).
+			RegisterGrant(struct{}{}).
+			RegisterResolver(resolver).
+			RegisterResolver(struct{}{}).
+			SetExpiresIn(time.Minute * 5).
+			SetRequestURILength(16).
+			SetSupportedClientAuthMethods(map[types.ClientAuthMethod]bool{types.ClientPostAuthentication: true}).
+			SetRequired(true)
+
+		assert.Equal(t, "par", cfg.endpointName)
+		assert.Equal(t, clientMgr, cfg.clientMgr)
+		assert.Equal(t, store, cfg.requestStore)
+		assert.Equal(t, []AuthorizationGrant{grant}, cfg.grants)
+		assert.Equal(t, []AuthorizationRequestResolver{resolver}, cfg.resolvers)
+		assert.Equal(t, time.Minute*5, cfg.expiresIn)
+		assert.Equal(t, 16, cfg.requestURILength)
+		assert.Equal(t, map[types.ClientAuthMethod]bool{types.ClientPostAuthentication: true}, cfg.supportedClientAuthMethods)
+		assert.True(t, cfg.required)
+		assert.NoError(t, cfg.ValidateConfig())
+	})
+
+	t.Run("error", func(t *testing.T) {
+		cfg := NewConfig().SetEndpointName("")
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrEmptyEndpointName)
+
+		cfg.SetEndpointName(EndpointNamePushedAuthorizationRequest)
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrNilClientManager)
+
+		cfg.SetClientManager(mock.NewMockClientManager(t)).SetRequestStore(nil)
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrNilRequestStore)
+
+		cfg.SetRequestStore(NewMemoryRequestStore())
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrEmptyGrants)
+
+		cfg.RegisterGrant(mock.NewMockAuthorizationGrant(t)).SetSupportedClientAuthMethods(nil)
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrEmptyClientAuthMethods)
+
+		cfg.SetSupportedClientAuthMethods(map[types.ClientAuthMethod]bool{types.ClientBasicAuthentication: true}).SetExpiresIn(0)
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrInvalidExpiresIn)
+
+		cfg.SetExpiresIn(DefaultExpiresIn).SetRequestURILength(0)
+		assert.ErrorIs(t, cfg.ValidateConfig(), ErrInvalidRequestURILen)
+	})
+}
@@ -0,0 +1,305 @@
+package rfc9126
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	autherrors "github.com/tniah/authlib/errors"
+	"github.com/tniah/authlib/models"
+	"github.com/tniah/authlib/requests"
+	"github.com/tniah/authlib/types"
+	"github.com/tniah/authlib/utils"
+)
+
+// PushedAuthorizationFlow implements the pushed authorization request
+// endpoint (RFC 9126 §2). It is registered as an endpoint on the server via
+// Server.RegisterEndpoint and dispatched by Server.EndpointResponse when the
+// endpoint name matches. Registering it also makes the server resolve the
+// request_uri values it issues at the authorization endpoint (RFC 9126 §4).
+type PushedAuthorizationFlow struct {
+	*Config
+}
+
+// NewPushedAuthorizationFlow creates a PushedAuthorizationFlow from cfg
+// without validating it. Prefer MustPushedAuthorizationFlow for production use.
+func NewPushedAuthorizationFlow(cfg *Config) *PushedAuthorizationFlow {
+	return &PushedAuthorizationFlow{cfg}
+}
+
+// MustPushedAuthorizationFlow creates a PushedAuthorizationFlow after
+// validating cfg. Returns an error if any required configuration is missing.
+func MustPushedAuthorizationFlow(cfg *Config) (*PushedAuthorizationFlow, error) {
+	if err := cfg.ValidateConfig(); err != nil {
+		return nil, err
+	}
+
+	return NewPushedAuthorizationFlow(cfg), nil
+}
+
+// CheckEndpoint reports whether name matches the configured endpoint name.
+// The server calls this to route requests to the correct registered endpoint.
+func (f *PushedAuthorizationFlow) CheckEndpoint(name string) bool {
+	if f.endpointName == "" {
+		return false
+	}
+
+	return name == f.endpointName
+}
+
+// ProvideMetadata adds require_pushed_authorization_requests to the
+// authorization server metadata (RFC 9126 §5).
+func (f *PushedAuthorizationFlow) ProvideMetadata(md types.Metadata) {
+	md.Set(types.MetadataRequirePushedAuthorizationRequests, f.required)
+}
+
+// EndpointResponse handles a pushed authorization request. It authenticates
+// the client, validates the parameters with the matching authorization grant,
+// stores them under a new request_uri, and answers 201 Created with the
+// request_uri and its lifetime (RFC 9126 §2.2).
+func (f *PushedAuthorizationFlow) EndpointResponse(r *http.Request, rw http.ResponseWriter) error {
+	req := NewRequestFromHTTP(r)
+	if err := f.checkParams(req); err != nil {
+		return err
+	}
+
+	if err := f.authenticateClient(req); err != nil {
+		return err
+	}
+
+	if err := f.validateAuthorizationRequest(req); err != nil {
+		return err
+	}
+
+	pushed, err := f.genPushedRequest(req)
+	if err != nil {
+		return err
+	}
+
+	if err = f.requestStore.Save(r.Context(), pushed); err != nil {
+		return err
+	}
+
+	data := map[string]interface{}{
+		"request_uri": pushed.RequestURI,
+		"expires_in":  int(f.expiresIn.Seconds()),
+	}
+
+	return utils.JSONResponse(rw, data, http.StatusCreated)
+}
+
+// ResolveAuthorizationRequest replaces an authorization request carrying a
+// request_uri issued by the endpoint with the pushed request (RFC 9126 §4).
+// The request_uri can be resolved until the authorization response is issued,
+// so the authorization and consent steps of the server can each resolve it;
+// see ConsumeAuthorizationRequest. Requests without a request_uri are returned
+// unchanged, unless pushed authorization requests are required by the server
+// or the client; request_uri values issued by others are left to other
+// resolvers.
+func (f *PushedAuthorizationFlow) ResolveAuthorizationRequest(r *requests.AuthorizationRequest) (*requests.AuthorizationRequest, error) {
+	if r.RequestURI == "" {
+		return r, f.checkRequired(r)
+	}
+
+	if !strings.HasPrefix(r.RequestURI, RequestURIPrefix) {
+		return r, nil
+	}
+
+	if err := r.ValidateClientID(true); err != nil {
+		return nil, err
+	}
+
+	pushed, err := f.requestStore.Load(r.Request.Context(), r.RequestURI)
+	if err != nil {
+		return nil, err
+	}
+
+	if pushed == nil || pushed.IsExpired() {
+		return nil, autherrors.InvalidRequestURIError().
+			WithDescription("\"request_uri\" is unknown, expired, or already used").
+			WithState(r.State)
+	}
+
+	if pushed.ClientID != r.ClientID {
+		return nil, autherrors.InvalidRequestURIError().
+			WithDescription("\"request_uri\" was not issued to this client").
+			WithState(r.State)
+	}
+
+	resolved, err := requests.NewAuthorizationRequestFromValues(r.Request, pushed.Params)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved.RequestURI = r.RequestURI
+	return resolved, nil
+}
+
+// ConsumeAuthorizationRequest spends the request_uri issued by the endpoint
+// that r was resolved from, so it can be used only once (RFC 9126 §4). The
+// server calls it just before the authorization response is issued; a
+// request_uri that was already used yields invalid_request_uri. Requests not
+// resolved from a pushed request are left alone.
+func (f *PushedAuthorizationFlow) ConsumeAuthorizationRequest(r *requests.AuthorizationRequest) error {
+	if !strings.HasPrefix(r.RequestURI, RequestURIPrefix) {
+		return nil
+	}
+
+	ok, err := f.requestStore.Consume(r.Request.Context(), r.RequestURI)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return autherrors.InvalidRequestURIError().
+			WithDescription("\"request_uri\" is unknown, expired, or already used").
+			WithState(r.State)
+	}
+
+	return nil
+}
+
+// checkParams validates the HTTP method, content type, and the absence of
+// request_uri before any manager calls are made.
+func (f *PushedAuthorizationFlow) checkParams(r *Request) error {
+	if err := r.ValidateHTTPMethod(); err != nil {
+		return err
+	}
+
+	if err := r.ValidateContentType(); err != nil {
+		return err
+	}
+
+	return r.ValidateRequestURI()
+}
+
+// authenticateClient delegates to ClientManager.Authenticate, then makes sure
+// the client_id of the pushed parameters, if any, names the authenticated
+// client (RFC 9126 §2.1).
+func (f *PushedAuthorizationFlow) authenticateClient(r *Request) error {
+	client, err := f.clientMgr.Authenticate(r.Request, f.supportedClientAuthMethods, f.endpointName)
+	if err != nil {
+		return autherrors.ToAuthLibError(err)
+	}
+
+	if utils.IsNil(client) {
+		return autherrors.InvalidClientError()
+	}
+
+	clientID := r.Params.Get("client_id")
+	if clientID == "" {
+		r.Params.Set("client_id", client.GetClientID())
+	} else if clientID != client.GetClientID() {
+		return autherrors.InvalidRequestError().WithDescription("\"client_id\" does not match the authenticated client")
+	}
+
+	r.Client = client
+	return nil
+}
+
+// validateAuthorizationRequest runs the pushed parameters through the
+// registered resolvers and the authorization grant matching their
+// response_type, as the authorization endpoint would. Errors are answered to
+// the client directly, never by redirect.
+func (f *PushedAuthorizationFlow) validateAuthorizationRequest(r *Request) error {
+	hr := r.Request.Clone(r.Request.Context())
+	hr.Method = http.MethodGet
+
+	authReq, err := requests.NewAuthorizationRequestFromValues(hr, r.Params)
+	if err != nil {
+		return withoutRedirect(err)
+	}
+
+	for _, resolver := range f.resolvers {
+		if authReq, err = resolver.ResolveAuthorizationRequest(authReq); err != nil {
+			return withoutRedirect(err)
+		}
+	}
+
+	grant := f.authorizationGrant(authReq)
+	if grant == nil {
+		return autherrors.UnsupportedResponseTypeError()
+	}
+
+	if err = grant.ValidateAuthorizationRequest(authReq); err != nil {
+		return withoutRedirect(err)
+	}
+
+	return nil
+}
+
+// authorizationGrant returns the first registered grant that supports the
+// requested response_type, or nil if none match.
+func (f *PushedAuthorizationFlow) authorizationGrant(r *requests.AuthorizationRequest) AuthorizationGrant {
+	for _, grant := range f.grants {
+		if grant.CheckResponseType(r.ResponseType) {
+			return grant
+		}
+	}
+
+	return nil
+}
+
+// genPushedRequest allocates a new request_uri for the pushed parameters.
+func (f *PushedAuthorizationFlow) genPushedRequest(r *Request) (*PushedRequest, error) {
+	id, err := utils.GenerateRandString(f.requestURILength, utils.AlphaNum)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PushedRequest{
+		RequestURI: RequestURIPrefix + id,
+		ClientID:   r.Client.GetClientID(),
+		Params:     r.Params,
+		ExpiresAt:  time.Now().Add(f.expiresIn),
+	}, nil
+}
+
+// checkRequired rejects a plain authorization request when pushed
+// authorization requests are required by the server or the client.
+func (f *PushedAuthorizationFlow) checkRequired(r *requests.AuthorizationRequest) error {
+	if f.required {
+		return requiredError(r)
+	}
+
+	if r.ClientID == "" {
+		return nil
+	}
+
+	client, err := f.clientMgr.QueryByClientID(r.Request.Context(), r.ClientID)
+	if err != nil {
+		return err
+	}
+
+	if clientRequiresPAR(client) {
+		return requiredError(r)
+	}
+
+	return nil
+}
+
+// clientRequiresPAR reports whether client registered
+// require_pushed_authorization_requests.
+func clientRequiresPAR(client models.Client) bool {
+	if utils.IsNil(client) {
+		return false
+	}
+
+	p, ok := client.(PushedAuthorizationRequestsProvider)
+	return ok && p.GetRequirePushedAuthorizationRequests()
+}
+
+func requiredError(r *requests.AuthorizationRequest) error {
+	return autherrors.InvalidRequestError().
+		WithDescription("authorization requests must be pushed to the pushed authorization request endpoint").
+		WithState(r.State)
+}
+
+// withoutRedirect drops the redirect target of err: the client called the
+// endpoint directly, so errors are answered in the response body.
+func withoutRedirect(err error) error {
+	authErr := autherrors.ToAuthLibError(err)
+	authErr.RedirectURI = ""
+	authErr.Fragment = false
+	return authErr
+}
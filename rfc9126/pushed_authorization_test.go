@@ -0,0 +1,535 @@
+package rfc9126
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/tniah/authlib"
+	autherrors "github.com/tniah/authlib/errors"
+	"github.com/tniah/authlib/integrations/sql"
+	rfc9126 "github.com/tniah/authlib/mocks/rfc9126"
+	"github.com/tniah/authlib/requests"
+	"github.com/tniah/authlib/types"
+)
+
+const (
+	pushBody     = "response_type=code&client_id=client-1&redirect_uri=https%3A%2F%2Fclient.example.com%2Fcb&state=af0ifjsldkj&client_secret=secret"
+	pushedURI    = RequestURIPrefix + "bwc4JK-ESC0w8acc191e-Y1LTC2"
+	authorizeURI = "/authorize?client_id=client-1&request_uri=" + pushedURI
+)
+
+// failingStore is a RequestStore whose every call fails.
+type failingStore struct{}
+
+func (failingStore) Save(_ context.Context, _ *PushedRequest) error {
+	return errors.New("store down")
+}
+
+func (failingStore) Load(_ context.Context, _ string) (*PushedRequest, error) {
+	return nil, errors.New("store down")
+}
+
+func (failingStore) Consume(_ context.Context, _ string) (bool, error) {
+	return false, errors.New("store down")
+}
+
+// staticStore is a RequestStore that always returns the same request.
+type staticStore struct {
+	req *PushedRequest
+}
+
+func (s *staticStore) Save(_ context.Context, _ *PushedRequest) error {
+	return nil
+}
+
+func (s *staticStore) Load(_ context.Context, _ string) (*PushedRequest, error) {
+	return s.req, nil
+}
+
+func (s *staticStore) Consume(_ context.Context, _ string) (bool, error) {
+	return s.req != nil, nil
+}
+
+// codeGrant is a minimal response_type=code authorization grant.
+type codeGrant struct{}
+
+func (g *codeGrant) CheckResponseType(typ types.ResponseType) bool {
+	return typ.IsCode()
+}
+
+func (g *codeGrant) ValidateAuthorizationRequest(r *requests.AuthorizationRequest) error {
+	return r.ValidateRedirectURI(true)
+}
+
+func (g *codeGrant) ValidateConsentRequest(r *requests.AuthorizationRequest) error {
+	return r.ValidateRedirectURI(true)
+}
+
+func (g *codeGrant) AuthorizationResponse(r *requests.AuthorizationRequest, rw http.ResponseWriter) error {
+	rw.Header().Set("Location", r.RedirectURI+"?code=abc&state="+r.State)
+	rw.WriteHeader(http.StatusFound)
+	return nil
+}
+
+func newPushRequest(method, body string) *http.Request {
+	hr := httptest.NewRequest(method, "/par", strings.NewReader(body))
+	hr.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return hr
+}
+
+func newAuthorizationRequest(t *testing.T, uri string) *requests.AuthorizationRequest {
+	r, err := requests.NewAuthorizationRequestFromHttp(httptest.NewRequest(http.MethodGet, uri, nil))
+	require.NoError(t, err)
+	return r
+}
+
+func pushedRequest(clientID string, expiresAt time.Time) *PushedRequest {
+	return &PushedRequest{
+		RequestURI: pushedURI,
+		ClientID:   clientID,
+		Params: url.Values{
+			"response_type": {"code"},
+			"client_id":     {"client-1"},
+			"redirect_uri":  {"https://client.example.com/cb"},
+			"state":         {"af0ifjsldkj"},
+		},
+		ExpiresAt: expiresAt,
+	}
+}
+
+func assertErrorCode(t *testing.T, err error, code error) {
+	t.Helper()
+	require.Error(t, err)
+	assert.Equal(t, code, autherrors.ToAuthLibError(err).Code)
+}
+
+func TestMustPushedAuthorizationFlow(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		cfg := NewConfig().
+			SetClientManager(rfc9126.NewMockClientManager(t)).
+			RegisterGrant(rfc9126.NewMockAuthorizationGrant(t))
+		f, err := MustPushedAuthorizationFlow(cfg)
+		require.NoError(t, err)
+		assert.NotNil(t, f)
+	})
+
+	t.Run("error_on_invalid_config", func(t *testing.T) {
+		f, err := MustPushedAuthorizationFlow(NewConfig())
+		assert.ErrorIs(t, err, ErrNilClientManager)
+		assert.Nil(t, f)
+	})
+}
+
+func TestPushedAuthorizationFlow_CheckEndpoint(t *testing.T) {
+	f := NewPushedAuthorizationFlow(NewConfig())
+	assert.True(t, f.CheckEndpoint(EndpointNamePushedAuthorizationRequest))
+	assert.False(t, f.CheckEndpoint("token"))
+
+	f.SetEndpointName("")
+	assert.False(t, f.CheckEndpoint(""))
+}
+
+func TestPushedAuthorizationFlow_EndpointResponse(t *testing.T) {
+	client := &sql.Client{ClientID: "client-1"}
+
+	newFlow := func(t *testing.T) (*PushedAuthorizationFlow, *rfc9126.MockClientManager, *rfc9126.MockAuthorizationGrant) {
+		clientMgr := rfc9126.NewMockClientManager(t)
+		grant := rfc9126.NewMockAuthorizationGrant(t)
+		f := NewPushedAuthorizationFlow(NewConfig().SetClientManager(clientMgr).RegisterGrant(grant))
+		return f, clientMgr, grant
+	}
+
+	t.Run("success", func(t *testing.T) {
+		f, clientMgr, grant := newFlow(t)
+		clientMgr.EXPECT().Authenticate(mock.Anything, f.supportedClientAuthMethods, EndpointNamePushedAuthorizationRequest).Return(client, nil).Once()
+		grant.EXPECT().CheckResponseType(types.ResponseTypeCode).Return(true).Once()
+		grant.EXPECT().ValidateAuthorizationRequest(mock.Anything).RunAndReturn(func(r *requests.AuthorizationRequest) error {
+			assert.Equal(t, http.MethodGet, r.Method())
+			assert.Equal(t, "client-1", r.ClientID)
+			assert.Equal(t, "https://client.example.com/cb", r.RedirectURI)
+			return nil
+		}).Once()
+
+		rw := httptest.NewRecorder()
+		require.NoError(t, f.EndpointResponse(newPushRequest(http.MethodPost, pushBody), rw))
+		assert.Equal(t, http.StatusCreated, rw.Code)
+
+		var body map[string]interface{}
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &body))
+		requestURI := body["request_uri"].(string)
+		assert.True(t, strings.HasPrefix(requestURI, RequestURIPrefix))
+		assert.Len(t, strings.TrimPrefix(requestURI, RequestURIPrefix), DefaultRequestURILength)
+		assert.Equal(t, float64(60), body["expires_in"])
+
+		pushed, err := f.requestStore.Load(context.Background(), requestURI)
+		require.NoError(t, err)
+		require.NotNil(t, pushed)
+		assert.Equal(t, "client-1", pushed.ClientID)
+		assert.False(t, pushed.Params.Has("client_secret"))
+		assert.Equal(t, "af0ifjsldkj", pushed.Params.Get("state"))
+	})
+
+	t.Run("sets_client_id_of_authenticated_client", func(t *testing.T) {
+		f, clientMgr, grant := newFlow(t)
+		clientMgr.EXPECT().Authenticate(mock.Anything, mock.Anything, mock.Anything).Return(client, nil).Once()
+		grant.EXPECT().CheckResponseType(types.ResponseTypeCode).Return(true).Once()
+		grant.EXPECT().ValidateAuthorizationRequest(mock.Anything).RunAndReturn(func(r *requests.AuthorizationRequest) error {
+			assert.Equal(t, "client-1", r.ClientID)
+			return nil
+		}).Once()
+
+		rw := httptest.NewRecorder()
+		assert.NoError(t, f.EndpointResponse(newPushRequest(http.MethodPost, "response_type=code"), rw))
+		assert.Equal(t, http.StatusCreated, rw.Code)
+	})
+
+	t.Run("validates_resolved_request", func(t *testing.T) {
+		f, clientMgr, grant := newFlow(t)
+		resolver := rfc9126.NewMockAuthorizationRequestResolver(t)
+		f.RegisterResolver(resolver)
+
+		resolved := &requests.AuthorizationRequest{ResponseType: types.ResponseTypeCode, ClientID: "client-1", State: "resolved"}
+		clientMgr.EXPECT().Authenticate(mock.Anything, mock.Anything, mock.Anything).Return(client, nil).Once()
+		resolver.EXPECT().ResolveAuthorizationRequest(mock.Anything).Return(resolved, nil).Once()
+		grant.EXPECT().CheckResponseType(types.ResponseTypeCode).Return(true).Once()
+		grant.EXPECT().ValidateAuthorizationRequest(resolved).Return(nil).Once()
+
+		rw := httptest.NewRecorder()
+		assert.NoError(t, f.EndpointResponse(newPushRequest(http.MethodPost, "client_id=client-1&request=eyJ..."), rw))
+		assert.Equal(t, http.StatusCreated, rw.Code)
+	})
+
+	t.Run("error_on_resolver_failure", func(t *testing.T) {
+		f, clientMgr, _ := newFlow(t)
+		resolver := rfc9126.NewMockAuthorizationRequestResolver(t)
+		f.RegisterResolver(resolver)
+
+		clientMgr.EXPECT().Authenticate(mock.Anything, mock.Anything, mock.Anything).Return(client, nil).Once()
+		resolver.EXPECT().ResolveAuthorizationRequest(mock.Anything).Return(nil, autherrors.InvalidRequestObjectError().WithRedirectURI("https://client.example.com/cb")).Once()
+
+		err := f.EndpointResponse(newPushRequest(http.MethodPost, "client_id=client-1&request=eyJ..."), httptest.NewRecorder())
+		assertErrorCode(t, err, autherrors.ErrInvalidRequestObject)
+		assert.Empty(t, autherrors.ToAuthLibError(err).RedirectURI)
+	})
+
+	t.Run("error_on_invalid_http_method", func(t *testing.T) {
+		f, _, _ := newFlow(t)
+		err := f.EndpointResponse(newPushRequest(http.MethodGet, pushBody), httptest.NewRecorder())
+		assertErrorCode(t, err, autherrors.ErrInvalidRequest)
+	})
+
+	t.Run("error_on_invalid_content_type", func(t *testing.T) {
+		f, _, _ := newFlow(t)
+		hr := newPushRequest(http.MethodPost, pushBody)
+		hr.Header.Set("Content-Type", "application/json")
+		err := f.EndpointResponse(hr, httptest.NewRecorder())
+		assertErrorCode(t, err, autherrors.ErrInvalidRequest)
+	})
+
+	t.Run("error_on_request_uri", func(t *testing.T) {
+		f, _, _ := newFlow(t)
+		err := f.EndpointResponse(newPushRequest(http.MethodPost, pushBody+"&request_uri="+pushedURI), httptest.NewRecorder())
+		assertErrorCode(t, err, autherrors.ErrInvalidRequest)
+	})
+
+	t.Run("error_on_authentication_failure", func(t *testing.T) {
+		f, clientMgr, _ := newFlow(t)
+		clientMgr.EXPECT().Authenticate(mock.Anything, mock.Anything, mock.Anything).Return(nil, autherrors.InvalidClientError()).Once()
+		err := f.EndpointResponse(newPushRequest(http.MethodPost, pushBody), httptest.NewRecorder())
+		assertErrorCode(t, err, autherrors.ErrInvalidClient)
+	})
+
+	t.Run("error_on_nil_client", func(t *testing.T) {
+		f, clientMgr, _ := newFlow(t)
+		clientMgr.EXPECT().Authenticate(mock.Anything, mock.Anything, mock.Anything).Return(nil, nil).Once()
+		err := f.EndpointResponse(newPushRequest(http.MethodPost, pushBody), httptest.NewRecorder())
+		assertErrorCode(t, err, autherrors.ErrInvalidClient)
+	})
+
+	t.Run("error_on_client_id_mismatch", func(t *testing.T) {
+		f, clientMgr, _ := newFlow(t)
+		clientMgr.EXPECT().Authenticate(mock.Anything, mock.Anything, mock.Anything).Return(&sql.Client{ClientID: "client-2"}, nil).Once()
+		err := f.EndpointResponse(newPushRequest(http.MethodPost, pushBody), httptest.NewRecorder())
+		assertErrorCode(t, err, autherrors.ErrInvalidRequest)
+	})
+
+	t.Run("error_on_unsupported_response_type", func(t *testing.T) {
+		f, clientMgr, grant := newFlow(t)
+		clientMgr.EXPECT().Authenticate(mock.Anything, mock.Anything, mock.Anything).Return(client, nil).Once()
+		grant.EXPECT().CheckResponseType(types.ResponseTypeCode).Return(false).Once()
+		err := f.EndpointResponse(newPushRequest(http.MethodPost, pushBody), httptest.NewRecorder())
+		assertErrorCode(t, err, autherrors.ErrUnsupportedResponseType)
+	})
+
+	t.Run("error_on_invalid_authorization_request_is_not_redirected", func(t *testing.T) {
+		f, clientMgr, grant := newFlow(t)
+		clientMgr.EXPECT().Authenticate(mock.Anything, mock.Anything, mock.Anything).Return(client, nil).Once()
+		grant.EXPECT().CheckResponseType(types.ResponseTypeCode).Return(true).Once()
+		grant.EXPECT().ValidateAuthorizationRequest(mock.Anything).Return(
+			autherrors.InvalidScopeError().WithState("af0ifjsldkj").WithRedirectURI("https://client.example.com/cb"),
+		).Once()
+
+		err := f.EndpointResponse(newPushRequest(http.MethodPost, pushBody), httptest.NewRecorder())
+		assertErrorCode(t, err, autherrors.ErrInvalidScope)
+		assert.Empty(t, autherrors.ToAuthLibError(err).RedirectURI)
+	})
+
+	t.Run("error_on_store_failure", func(t *testing.T) {
+		f, clientMgr, grant := newFlow(t)
+		f.SetRequestStore(failingStore{})
+		clientMgr.EXPECT().Authenticate(mock.Anything, mock.Anything, mock.Anything).Return(client, nil).Once()
+		grant.EXPECT().CheckResponseType(types.ResponseTypeCode).Return(true).Once()
+		grant.EXPECT().ValidateAuthorizationRequest(mock.Anything).Return(nil).Once()
+
+		err := f.EndpointResponse(newPushRequest(http.MethodPost, pushBody), httptest.NewRecorder())
+		assert.EqualError(t, err, "store down")
+	})
+}
+
+func TestPushedAuthorizationFlow_ResolveAuthorizationRequest(t *testing.T) {
+	newFlow := func(t *testing.T, pushed ...*PushedRequest) (*PushedAuthorizationFlow, *rfc9126.MockClientManager) {
+		clientMgr := rfc9126.NewMockClientManager(t)
+		store := NewMemoryRequestStore()
+		for _, req := range pushed {
+			require.NoError(t, store.Save(context.Background(), req))
+		}
+
+		return NewPushedAuthorizationFlow(NewConfig().SetClientManager(clientMgr).SetRequestStore(store)), clientMgr
+	}
+
+	t.Run("resolves_pushed_request_until_used", func(t *testing.T) {
+		f, _ := newFlow(t, pushedRequest("client-1", time.Now().Add(time.Minute)))
+
+		r, err := f.ResolveAuthorizationRequest(newAuthorizationRequest(t, authorizeURI))
+		require.NoError(t, err)
+		assert.Equal(t, types.ResponseTypeCode, r.ResponseType)
+		assert.Equal(t, "client-1", r.ClientID)
+		assert.Equal(t, "https://client.example.com/cb", r.RedirectURI)
+		assert.Equal(t, "af0ifjsldkj", r.State)
+		assert.Equal(t, pushedURI, r.RequestURI)
+		assert.Equal(t, "https://client.example.com/cb", r.Request.FormValue("redirect_uri"))
+
+		r, err = f.ResolveAuthorizationRequest(newAuthorizationRequest(t, authorizeURI))
+		require.NoError(t, err)
+		assert.Equal(t, "af0ifjsldkj", r.State)
+	})
+
+	t.Run("error_on_used_request_uri", func(t *testing.T) {
+		f, _ := newFlow(t, pushedRequest("client-1", time.Now().Add(time.Minute)))
+
+		r, err := f.ResolveAuthorizationRequest(newAuthorizationRequest(t, authorizeURI))
+		require.NoError(t, err)
+		require.NoError(t, f.ConsumeAuthorizationRequest(r))
+
+		_, err = f.ResolveAuthorizationRequest(newAuthorizationRequest(t, authorizeURI))
+		assertErrorCode(t, err, autherrors.ErrInvalidRequestURI)
+	})
+
+	t.Run("ignores_parameters_outside_pushed_request", func(t *testing.T) {
+		f, _ := newFlow(t, pushedRequest("client-1", time.Now().Add(time.Minute)))
+
+		r, err := f.ResolveAuthorizationRequest(newAuthorizationRequest(t, authorizeURI+"&scope=admin&state=other"))
+		require.NoError(t, err)
+		assert.Empty(t, r.Scopes)
+		assert.Equal(t, "af0ifjsldkj", r.State)
+	})
+
+	t.Run("error_without_client_id", func(t *testing.T) {
+		f, _ := newFlow(t, pushedRequest("client-1", time.Now().Add(time.Minute)))
+		_, err := f.ResolveAuthorizationRequest(newAuthorizationRequest(t, "/authorize?request_uri="+pushedURI))
+		assertErrorCode(t, err, autherrors.ErrInvalidRequest)
+	})
+
+	t.Run("error_on_unknown_request_uri", func(t *testing.T) {
+		f, _ := newFlow(t)
+		_, err := f.ResolveAuthorizationRequest(newAuthorizationRequest(t, authorizeURI))
+		assertErrorCode(t, err, autherrors.ErrInvalidRequestURI)
+	})
+
+	t.Run("error_on_expired_request_uri", func(t *testing.T) {
+		f, _ := newFlow(t)
+		f.SetRequestStore(&staticStore{pushedRequest("client-1", time.Now().Add(-time.Second))})
+		_, err := f.ResolveAuthorizationRequest(newAuthorizationRequest(t, authorizeURI))
+		assertErrorCode(t, err, autherrors.ErrInvalidRequestURI)
+	})
+
+	t.Run("error_on_request_uri_of_other_client", func(t *testing.T) {
+		f, _ := newFlow(t, pushedRequest("client-2", time.Now().Add(time.Minute)))
+		_, err := f.ResolveAuthorizationRequest(newAuthorizationRequest(t, authorizeURI))
+		assertErrorCode(t, err, autherrors.ErrInvalidRequestURI)
+
+		pushed, err := f.requestStore.Load(context.Background(), pushedURI)
+		require.NoError(t, err)
+		assert.NotNil(t, pushed, "a failed lookup must not spend the request_uri")
+	})
+
+	t.Run("error_on_store_failure", func(t *testing.T) {
+		f, _ := newFlow(t)
+		f.SetRequestStore(failingStore{})
+		_, err := f.ResolveAuthorizationRequest(newAuthorizationRequest(t, authorizeURI))
+		assert.EqualError(t, err, "store down")
+	})
+
+	t.Run("leaves_other_request_uri", func(t *testing.T) {
+		f, _ := newFlow(t)
+		r := newAuthorizationRequest(t, "/authorize?client_id=client-1&request_uri=https%3A%2F%2Fclient.example.com%2Frequest.jwt")
+		resolved, err := f.ResolveAuthorizationRequest(r)
+		assert.NoError(t, err)
+		assert.Same(t, r, resolved)
+	})
+
+	t.Run("accepts_plain_request", func(t *testing.T) {
+		f, clientMgr := newFlow(t)
+		clientMgr.EXPECT().QueryByClientID(mock.Anything, "client-1").Return(&sql.Client{ClientID: "client-1"}, nil).Once()
+
+		r := newAuthorizationRequest(t, "/authorize?response_type=code&client_id=client-1")
+		resolved, err := f.ResolveAuthorizationRequest(r)
+		assert.NoError(t, err)
+		assert.Same(t, r, resolved)
+	})
+
+	t.Run("accepts_plain_request_of_unknown_client", func(t *testing.T) {
+		f, clientMgr := newFlow(t)
+		clientMgr.EXPECT().QueryByClientID(mock.Anything, "client-1").Return(nil, nil).Once()
+
+		_, err := f.ResolveAuthorizationRequest(newAuthorizationRequest(t, "/authorize?response_type=code&client_id=client-1"))
+		assert.NoError(t, err)
+	})
+
+	t.Run("error_on_plain_request_when_required", func(t *testing.T) {
+		f, _ := newFlow(t)
+		f.SetRequired(true)
+
+		_, err := f.ResolveAuthorizationRequest(newAuthorizationRequest(t, "/authorize?response_type=code&client_id=client-1&state=xyz"))
+		assertErrorCode(t, err, autherrors.ErrInvalidRequest)
+		assert.Equal(t, "xyz", autherrors.ToAuthLibError(err).State)
+	})
+
+	t.Run("error_on_plain_request_when_client_requires_par", func(t *testing.T) {
+		f, clientMgr := newFlow(t)
+		clientMgr.EXPECT().QueryByClientID(mock.Anything, "client-1").
+			Return(&sql.Client{ClientID: "client-1", RequirePushedAuthorizationRequests: true}, nil).Once()
+
+		_, err := f.ResolveAuthorizationRequest(newAuthorizationRequest(t, "/authorize?response_type=code&client_id=client-1"))
+		assertErrorCode(t, err, autherrors.ErrInvalidRequest)
+	})
+
+	t.Run("error_on_client_lookup_failure", func(t *testing.T) {
+		f, clientMgr := newFlow(t)
+		clientMgr.EXPECT().QueryByClientID(mock.Anything, "client-1").Return(nil, errors.New("db down")).Once()
+
+		_, err := f.ResolveAuthorizationRequest(newAuthorizationRequest(t, "/authorize?response_type=code&client_id=client-1"))
+		assert.EqualError(t, err, "db down")
+	})
+}
+
+func TestPushedAuthorizationFlow_ConsumeAuthorizationRequest(t *testing.T) {
+	newFlow := func(t *testing.T) *PushedAuthorizationFlow {
+		store := NewMemoryRequestStore()
+		require.NoError(t, store.Save(context.Background(), pushedRequest("client-1", time.Now().Add(time.Minute))))
+		return NewPushedAuthorizationFlow(NewConfig().SetClientManager(rfc9126.NewMockClientManager(t)).SetRequestStore(store))
+	}
+
+	t.Run("consumes_once", func(t *testing.T) {
+		f := newFlow(t)
+		r, err := f.ResolveAuthorizationRequest(newAuthorizationRequest(t, authorizeURI))
+		require.NoError(t, err)
+
+		assert.NoError(t, f.ConsumeAuthorizationRequest(r))
+
+		err = f.ConsumeAuthorizationRequest(r)
+		assertErrorCode(t, err, autherrors.ErrInvalidRequestURI)
+		assert.Equal(t, "af0ifjsldkj", autherrors.ToAuthLibError(err).State)
+	})
+
+	t.Run("leaves_other_requests", func(t *testing.T) {
+		f := newFlow(t)
+		f.SetRequestStore(failingStore{})
+
+		assert.NoError(t, f.ConsumeAuthorizationRequest(newAuthorizationRequest(t, "/authorize?response_type=code&client_id=client-1")))
+		assert.NoError(t, f.ConsumeAuthorizationRequest(newAuthorizationRequest(t, "/authorize?client_id=client-1&request_uri=https%3A%2F%2Fclient.example.com%2Frequest.jwt")))
+	})
+
+	t.Run("error_on_store_failure", func(t *testing.T) {
+		f := newFlow(t)
+		f.SetRequestStore(failingStore{})
+
+		err := f.ConsumeAuthorizationRequest(newAuthorizationRequest(t, authorizeURI))
+		assert.EqualError(t, err, "store down")
+	})
+}
+
+func TestPushedAuthorizationFlow_Server(t *testing.T) {
+	push := func(t *testing.T, srv *authlib.Server, clientMgr *rfc9126.MockClientManager) string {
+		clientMgr.EXPECT().Authenticate(mock.Anything, mock.Anything, mock.Anything).Return(&sql.Client{ClientID: "client-1"}, nil).Once()
+
+		rw := httptest.NewRecorder()
+		require.NoError(t, srv.EndpointResponse(newPushRequest(http.MethodPost, pushBody), rw, EndpointNamePushedAuthorizationRequest))
+		require.Equal(t, http.StatusCreated, rw.Code)
+
+		var body map[string]interface{}
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &body))
+		return "/authorize?client_id=client-1&request_uri=" + url.QueryEscape(body["request_uri"].(string))
+	}
+
+	newServer := func(t *testing.T) (*authlib.Server, *PushedAuthorizationFlow, *rfc9126.MockClientManager) {
+		clientMgr := rfc9126.NewMockClientManager(t)
+		grant := &codeGrant{}
+		f := NewPushedAuthorizationFlow(NewConfig().SetClientManager(clientMgr).RegisterGrant(grant))
+
+		srv := authlib.NewServer()
+		srv.RegisterGrant(grant)
+		srv.RegisterEndpoint(f)
+		return srv, f, clientMgr
+	}
+
+	t.Run("validate_then_consent_when_required", func(t *testing.T) {
+		srv, f, clientMgr := newServer(t)
+		f.SetRequired(true)
+		uri := push(t, srv, clientMgr)
+
+		_, r, err := srv.ValidateAuthorizationRequest(httptest.NewRequest(http.MethodGet, uri, nil), nil)
+		require.NoError(t, err)
+		assert.Equal(t, "https://client.example.com/cb", r.RedirectURI)
+		assert.Equal(t, "af0ifjsldkj", r.State)
+
+		rw := httptest.NewRecorder()
+		require.NoError(t, srv.CreateConsentResponse(httptest.NewRequest(http.MethodPost, uri, nil), rw, &sql.User{UserID: "user-1"}))
+		assert.Equal(t, http.StatusFound, rw.Code)
+		assert.Equal(t, "https://client.example.com/cb?code=abc&state=af0ifjsldkj", rw.Header().Get("Location"))
+
+		_, _, err = srv.ValidateConsentRequest(httptest.NewRequest(http.MethodPost, uri, nil), &sql.User{UserID: "user-1"})
+		assertErrorCode(t, err, autherrors.ErrInvalidRequestURI)
+	})
+
+	t.Run("error_on_replayed_authorization_response", func(t *testing.T) {
+		srv, _, clientMgr := newServer(t)
+		uri := push(t, srv, clientMgr)
+
+		rw := httptest.NewRecorder()
+		require.NoError(t, srv.CreateAuthorizationResponse(httptest.NewRequest(http.MethodGet, uri, nil), rw, &sql.User{UserID: "user-1"}))
+		assert.Equal(t, http.StatusFound, rw.Code)
+
+		_, _, err := srv.ValidateAuthorizationRequest(httptest.NewRequest(http.MethodGet, uri, nil), &sql.User{UserID: "user-1"})
+		assertErrorCode(t, err, autherrors.ErrInvalidRequestURI)
+	})
+
+	t.Run("error_on_plain_consent_when_required", func(t *testing.T) {
+		srv, f, _ := newServer(t)
+		f.SetRequired(true)
+
+		hr := httptest.NewRequest(http.MethodPost, "/authorize?response_type=code&client_id=client-1&redirect_uri=https%3A%2F%2Fclient.example.com%2Fcb", nil)
+		_, _, err := srv.ValidateConsentRequest(hr, &sql.User{UserID: "user-1"})
+		assertErrorCode(t, err, autherrors.ErrInvalidRequest)
+	})
+}
@@ -0,0 +1,81 @@
+package rfc9126
+
+import (
+	"net/http"
+	"net/url"
+
+	autherrors "github.com/tniah/authlib/errors"
+	"github.com/tniah/authlib/models"
+	"github.com/tniah/authlib/utils"
+)
+
+// clientAuthParams are the form parameters used for client authentication
+// (RFC 6749 §2.3.1, RFC 7523 §2.2). They are not part of the authorization
+// request and are never stored.
+var clientAuthParams = []string{"client_secret", "client_assertion", "client_assertion_type"}
+
+// Request holds the parsed parameters of an RFC 9126 pushed authorization
+// request.
+type Request struct {
+	// Params are the authorization request parameters sent in the body.
+	Params url.Values
+
+	Client  models.Client
+	Request *http.Request
+}
+
+// NewRequestFromHTTP parses a pushed authorization request from an HTTP
+// request. Only parameters sent in the body are considered (RFC 9126 §2.1),
+// and client authentication parameters are left out.
+func NewRequestFromHTTP(r *http.Request) *Request {
+	_ = r.ParseForm()
+
+	params := url.Values{}
+	for k, v := range r.PostForm {
+		params[k] = append([]string(nil), v...)
+	}
+
+	for _, k := range clientAuthParams {
+		params.Del(k)
+	}
+
+	return &Request{
+		Params:  params,
+		Request: r,
+	}
+}
+
+// ValidateHTTPMethod returns an error if the request method is not POST,
+// as required by RFC 9126 §2.1.
+func (r *Request) ValidateHTTPMethod() error {
+	if r.Request.Method != http.MethodPost {
+		return autherrors.InvalidRequestError().WithDescription("request must be \"POST\"")
+	}
+
+	return nil
+}
+
+// ValidateContentType returns an error if the Content-Type is not
+// application/x-www-form-urlencoded, as required by RFC 9126 §2.1.
+func (r *Request) ValidateContentType() error {
+	ct, err := utils.ContentType(r.Request)
+	if err != nil {
+		return autherrors.InvalidRequestError()
+	}
+
+	if valid := ct.IsXWWWFormUrlencoded(); !valid {
+		return autherrors.InvalidRequestError().WithDescription("content type must be \"application/x-www-form-urlencoded\"")
+	}
+
+	return nil
+}
+
+// ValidateRequestURI returns an error if the request carries a request_uri,
+// which cannot be pushed (RFC 9126 §2.1).
+func (r *Request) ValidateRequestURI() error {
+	if r.Params.Has("request_uri") {
+		return autherrors.InvalidRequestError().WithDescription("\"request_uri\" is not allowed in a pushed authorization request")
+	}
+
+	return nil
+}
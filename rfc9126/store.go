@@ -0,0 +1,83 @@
+package rfc9126
+
+import (
+	"context"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// PushedRequest is an authorization request received at the pushed
+// authorization request endpoint.
+type PushedRequest struct {
+	// RequestURI is the reference issued to the client.
+	RequestURI string
+	// ClientID is the client that pushed the request.
+	ClientID string
+	// Params are the authorization request parameters, client
+	// authentication parameters excluded.
+	Params url.Values
+	// ExpiresAt is the time after which RequestURI is rejected.
+	ExpiresAt time.Time
+}
+
+// IsExpired reports whether the request_uri can no longer be used.
+func (r *PushedRequest) IsExpired() bool {
+	return time.Now().After(r.ExpiresAt)
+}
+
+// MemoryRequestStore is an in-process RequestStore. Expired requests are
+// dropped on every call. It suits a single server instance; use a shared
+// store (e.g. Redis) behind RequestStore when running several.
+type MemoryRequestStore struct {
+	lock     sync.Mutex
+	requests map[string]*PushedRequest
+}
+
+// NewMemoryRequestStore returns an empty MemoryRequestStore.
+func NewMemoryRequestStore() *MemoryRequestStore {
+	return &MemoryRequestStore{requests: make(map[string]*PushedRequest)}
+}
+
+// Save stores req under req.RequestURI.
+func (s *MemoryRequestStore) Save(_ context.Context, req *PushedRequest) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.purge()
+	s.requests[req.RequestURI] = req
+	return nil
+}
+
+// Load returns the request stored under requestURI.
+func (s *MemoryRequestStore) Load(_ context.Context, requestURI string) (*PushedRequest, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.purge()
+	return s.requests[requestURI], nil
+}
+
+// Consume deletes the request stored under requestURI and reports whether it
+// was stored.
+func (s *MemoryRequestStore) Consume(_ context.Context, requestURI string) (bool, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.purge()
+	if _, ok := s.requests[requestURI]; !ok {
+		return false, nil
+	}
+
+	delete(s.requests, requestURI)
+	return true, nil
+}
+
+// purge drops expired requests. The caller must hold the lock.
+func (s *MemoryRequestStore) purge() {
+	for k, req := range s.requests {
+		if req.IsExpired() {
+			delete(s.requests, k)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package rfc9126
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryRequestStore(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("loads_until_consumed", func(t *testing.T) {
+		s := NewMemoryRequestStore()
+		req := &PushedRequest{
+			RequestURI: RequestURIPrefix + "abc",
+			ClientID:   "client-1",
+			Params:     url.Values{"response_type": {"code"}},
+			ExpiresAt:  time.Now().Add(time.Minute),
+		}
+		require.NoError(t, s.Save(ctx, req))
+
+		got, err := s.Load(ctx, req.RequestURI)
+		assert.NoError(t, err)
+		assert.Equal(t, req, got)
+
+		got, err = s.Load(ctx, req.RequestURI)
+		assert.NoError(t, err)
+		assert.Equal(t, req, got)
+
+		got, err = s.Load(ctx, RequestURIPrefix+"unknown")
+		assert.NoError(t, err)
+		assert.Nil(t, got)
+
+		ok, err := s.Consume(ctx, req.RequestURI)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+
+		got, err = s.Load(ctx, req.RequestURI)
+		assert.NoError(t, err)
+		assert.Nil(t, got)
+	})
+
+	t.Run("consumes_once", func(t *testing.T) {
+		s := NewMemoryRequestStore()
+		require.NoError(t, s.Save(ctx, &PushedRequest{RequestURI: RequestURIPrefix + "abc", ExpiresAt: time.Now().Add(time.Minute)}))
+
+		ok, err := s.Consume(ctx, RequestURIPrefix+"abc")
+		assert.NoError(t, err)
+		assert.True(t, ok)
+
+		ok, err = s.Consume(ctx, RequestURIPrefix+"abc")
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("forgets_expired_requests", func(t *testing.T) {
+		s := NewMemoryRequestStore()
+		require.NoError(t, s.Save(ctx, &PushedRequest{RequestURI: "expired", ExpiresAt: time.Now().Add(time.Millisecond)}))
+
+		time.Sleep(5 * time.Millisecond)
+		got, err := s.Load(ctx, "expired")
+		assert.NoError(t, err)
+		assert.Nil(t, got)
+		assert.Empty(t, s.requests)
+	})
+}
+
+func TestPushedRequest_IsExpired(t *testing.T) {
+	assert.False(t, (&PushedRequest{ExpiresAt: time.Now().Add(time.Minute)}).IsExpired())
+	assert.True(t, (&PushedRequest{ExpiresAt: time.Now().Add(-time.Second)}).IsExpired())
+}
@@ -0,0 +1,68 @@
+package rfc9126
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/tniah/authlib/models"
+	"github.com/tniah/authlib/requests"
+	"github.com/tniah/authlib/types"
+)
+
+// ClientManager authenticates the client at the pushed authorization request
+// endpoint and looks up the client of plain authorization requests.
+type ClientManager interface {
+	// Authenticate verifies the client credentials and returns the authenticated
+	// client. endpointName identifies the endpoint being accessed (used for
+	// method-specific logic in multi-endpoint setups).
+	Authenticate(r *http.Request, authMethods map[types.ClientAuthMethod]bool, endpointName string) (models.Client, error)
+
+	// QueryByClientID retrieves a client by its client_id. Return (nil, nil)
+	// when it does not exist.
+	QueryByClientID(ctx context.Context, clientID string) (models.Client, error)
+}
+
+// RequestStore persists pushed authorization requests until they are used or
+// expire.
+type RequestStore interface {
+	// Save stores req under req.RequestURI.
+	Save(ctx context.Context, req *PushedRequest) error
+
+	// Load returns the request stored under requestURI, leaving it in place,
+	// so the authorization and consent steps can each resolve it. Return
+	// (nil, nil) when it does not exist.
+	Load(ctx context.Context, requestURI string) (*PushedRequest, error)
+
+	// Consume deletes the request stored under requestURI once the
+	// authorization response is issued, so every request_uri is used only
+	// once (RFC 9126 §4). It reports whether the request was still stored;
+	// it must be atomic, so concurrent calls return true at most once.
+	Consume(ctx context.Context, requestURI string) (bool, error)
+}
+
+// AuthorizationGrant validates the pushed parameters exactly as they would be
+// validated at the authorization endpoint. Every authlib authorization flow
+// satisfies it.
+type AuthorizationGrant interface {
+	// CheckResponseType reports whether this grant handles the given response_type.
+	CheckResponseType(typ types.ResponseType) bool
+	// ValidateAuthorizationRequest validates the authorization request.
+	ValidateAuthorizationRequest(r *requests.AuthorizationRequest) error
+}
+
+// AuthorizationRequestResolver expands pushed parameters passed by reference
+// before they are validated, such as a request object (RFC 9126 §3).
+// rfc9101.Flow satisfies it.
+type AuthorizationRequestResolver interface {
+	// ResolveAuthorizationRequest returns the request to validate in place
+	// of r, or r unchanged when it does not apply.
+	ResolveAuthorizationRequest(r *requests.AuthorizationRequest) (*requests.AuthorizationRequest, error)
+}
+
+// PushedAuthorizationRequestsProvider is implemented by clients that can
+// register the require_pushed_authorization_requests metadata (RFC 9126 §6).
+// When it returns true, the client's authorization requests are only accepted
+// through a request_uri issued by the pushed authorization request endpoint.
+type PushedAuthorizationRequestsProvider interface {
+	GetRequirePushedAuthorizationRequests() bool
+}
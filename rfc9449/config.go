@@ -0,0 +1,135 @@
+package rfc9449
+
+import (
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/tniah/authlib/utils"
+)
+
+const (
+	// DefaultProofLifetime is how long after its iat a DPoP proof is
+	// accepted (RFC 9449 §11.1).
+	DefaultProofLifetime = time.Minute
+	// DefaultLeeway is the clock skew tolerated for the iat of a proof, which
+	// is set by the client's clock.
+	DefaultLeeway = 5 * time.Second
+)
+
+// Sentinel errors returned by ValidateConfig.
+var (
+	ErrNilJWTIDCache        = errors.New("jwt id cache is nil")
+	ErrEmptySigningMethods  = errors.New("signing methods are empty")
+	ErrInvalidProofLifetime = errors.New("proof lifetime must be positive")
+)
+
+// Config holds the DPoP proof checks shared by the token endpoint and the
+// resource server. Use NewConfig() to get a config with sensible defaults,
+// then chain Set* calls before passing to Must() or New().
+type Config struct {
+	jwtIDCache JWTIDCache
+	nonceMgr   NonceManager
+
+	// signingMethods are the alg values accepted for proofs. Only asymmetric
+	// algorithms make sense: the key is carried in the proof itself.
+	signingMethods []string
+
+	proofLifetime time.Duration
+	leeway        time.Duration
+
+	// baseURL is the scheme and authority the htu claim is checked against.
+	// Empty means derive it from the request.
+	baseURL string
+
+	// required rejects token requests made without a DPoP proof.
+	required bool
+}
+
+// NewConfig returns a Config with secure defaults:
+//   - Accepts RS*, PS*, ES*, and EdDSA proofs.
+//   - Accepts proofs up to DefaultProofLifetime old, with DefaultLeeway of
+//     clock skew.
+//   - Rejects replayed proofs using an in-memory JWTIDCache.
+//   - Does not require server-provided nonces.
+//   - Issues unbound Bearer tokens when the token request carries no proof.
+func NewConfig() *Config {
+	return &Config{
+		jwtIDCache: utils.NewMemoryJWTIDCache(),
+		signingMethods: []string{
+			jwt.SigningMethodRS256.Alg(), jwt.SigningMethodRS384.Alg(), jwt.SigningMethodRS512.Alg(),
+			jwt.SigningMethodPS256.Alg(), jwt.SigningMethodPS384.Alg(), jwt.SigningMethodPS512.Alg(),
+			jwt.SigningMethodES256.Alg(), jwt.SigningMethodES384.Alg(), jwt.SigningMethodES512.Alg(),
+			jwt.SigningMethodEdDSA.Alg(),
+		},
+		proofLifetime: DefaultProofLifetime,
+		leeway:        DefaultLeeway,
+	}
+}
+
+// SetJWTIDCache overrides the replay cache. Use a shared store when running
+// several instances. Default: utils.NewMemoryJWTIDCache().
+func (cfg *Config) SetJWTIDCache(cache JWTIDCache) *Config {
+	cfg.jwtIDCache = cache
+	return cfg
+}
+
+// SetNonceManager makes proofs carry a nonce issued by mgr. Proofs without a
+// current nonce are answered with use_dpop_nonce and a fresh nonce in the
+// DPoP-Nonce header. Default: nil (no nonce).
+func (cfg *Config) SetNonceManager(mgr NonceManager) *Config {
+	cfg.nonceMgr = mgr
+	return cfg
+}
+
+// SetSigningMethods overrides the alg values accepted for proofs.
+func (cfg *Config) SetSigningMethods(methods []string) *Config {
+	cfg.signingMethods = methods
+	return cfg
+}
+
+// SetProofLifetime overrides how long after its iat a proof is accepted.
+// Default: DefaultProofLifetime.
+func (cfg *Config) SetProofLifetime(d time.Duration) *Config {
+	cfg.proofLifetime = d
+	return cfg
+}
+
+// SetLeeway sets the clock skew tolerated for iat. Default: DefaultLeeway.
+func (cfg *Config) SetLeeway(leeway time.Duration) *Config {
+	cfg.leeway = leeway
+	return cfg
+}
+
+// SetBaseURL sets the scheme and authority (e.g. "https://as.example.com")
+// the htu claim is checked against, for servers behind a proxy that rewrites
+// them. Default: derived from the request.
+func (cfg *Config) SetBaseURL(baseURL string) *Config {
+	cfg.baseURL = baseURL
+	return cfg
+}
+
+// SetRequired controls whether every token request must carry a DPoP proof.
+// Default: false.
+func (cfg *Config) SetRequired(required bool) *Config {
+	cfg.required = required
+	return cfg
+}
+
+// ValidateConfig checks that all required dependencies are set and returns the
+// first sentinel error encountered. Call this via Must() rather than directly.
+func (cfg *Config) ValidateConfig() error {
+	if utils.IsNil(cfg.jwtIDCache) {
+		return ErrNilJWTIDCache
+	}
+
+	if len(cfg.signingMethods) == 0 {
+		return ErrEmptySigningMethods
+	}
+
+	if cfg.proofLifetime <= 0 {
+		return ErrInvalidProofLifetime
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package rfc9449
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	mock "github.com/tniah/authlib/mocks/rfc9449"
+	"github.com/tniah/authlib/utils"
+)
+
+func TestNewConfig(t *testing.T) {
+	cfg := NewConfig()
+	assert.IsType(t, &utils.MemoryJWTIDCache{}, cfg.jwtIDCache)
+	assert.Nil(t, cfg.nonceMgr)
+	assert.Contains(t, cfg.signingMethods, "ES256")
+	assert.Contains(t, cfg.signingMethods, "EdDSA")
+	assert.NotContains(t, cfg.signingMethods, "HS256")
+	assert.Equal(t, DefaultProofLifetime, cfg.proofLifetime)
+	assert.Equal(t, DefaultLeeway, cfg.leeway)
+	assert.Empty(t, cfg.baseURL)
+	assert.False(t, cfg.required)
+}
+
+func TestConfig_Setters(t *testing.T) {
+	cache := mock.NewMockJWTIDCache(t)
+	nonceMgr := mock.NewMockNonceManager(t)
+
+	cfg := NewConfig().
+		SetJWTIDCache(cache).
+		SetNonceManager(nonceMgr).
+		SetSigningMethods([]string{"ES256"}).
+		SetProofLifetime(time.Minute * 2).
+		SetLeeway(time.Second).
+		SetBaseURL("https://as.example.com").
+		SetRequired(true)
+
+	assert.Equal(t, cache, cfg.jwtIDCache)
+	assert.Equal(t, nonceMgr, cfg.nonceMgr)
+	assert.Equal(t, []string{"ES256"}, cfg.signingMethods)
+	assert.Equal(t, time.Minute*2, cfg.proofLifetime)
+	assert.Equal(t, time.Second, cfg.leeway)
+	assert.Equal(t, "https://as.example.com", cfg.baseURL)
+	assert.True(t, cfg.required)
+}
+
+func TestConfig_ValidateConfig(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		assert.NoError(t, NewConfig().ValidateConfig())
+	})
+
+	t.Run("error_nil_jwt_id_cache", func(t *testing.T) {
+		assert.ErrorIs(t, NewConfig().SetJWTIDCache(nil).ValidateConfig(), ErrNilJWTIDCache)
+	})
+
+	t.Run("error_empty_signing_methods", func(t *testing.T) {
+		assert.ErrorIs(t, NewConfig().SetSigningMethods(nil).ValidateConfig(), ErrEmptySigningMethods)
+	})
+
+	t.Run("error_invalid_proof_lifetime", func(t *testing.T) {
+		assert.ErrorIs(t, NewConfig().SetProofLifetime(0).ValidateConfig(), ErrInvalidProofLifetime)
+	})
+}
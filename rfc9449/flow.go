@@ -0,0 +1,194 @@
+package rfc9449
+
+import (
+	"crypto/subtle"
+	"regexp"
+
+	autherrors "github.com/tniah/authlib/errors"
+	"github.com/tniah/authlib/models"
+	"github.com/tniah/authlib/requests"
+	"github.com/tniah/authlib/types"
+	"github.com/tniah/authlib/utils"
+)
+
+// jktPattern matches a base64url-encoded SHA-256 JWK thumbprint.
+var jktPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{43}$`)
+
+// Flow implements DPoP (RFC 9449) as an extension for the grant flows. It
+// implements the AuthorizationRequestValidator, AuthCodeProcessor,
+// TokenRequestValidator, and TokenProcessor extension interfaces; register
+// it with each grant via cfg.RegisterExtension. It also verifies the proofs
+// sent to resource servers, see VerifyResourceRequest.
+type Flow struct {
+	*Config
+}
+
+// New creates a Flow from cfg without validating it. Prefer Must for
+// production use.
+func New(cfg *Config) *Flow {
+	return &Flow{cfg}
+}
+
+// Must creates a Flow after validating cfg. Returns an error if a required
+// dependency is missing.
+func Must(cfg *Config) (*Flow, error) {
+	if err := cfg.ValidateConfig(); err != nil {
+		return nil, err
+	}
+
+	return New(cfg), nil
+}
+
+// ProvideMetadata adds the accepted proof signing algorithms to the
+// authorization server metadata (RFC 9449 §5.1).
+func (f *Flow) ProvideMetadata(md types.Metadata) {
+	md.Add(types.MetadataDPoPSigningAlgValuesSupported, f.signingMethods...)
+}
+
+// ValidateAuthorizationRequest checks that dpop_jkt, when present, is a JWK
+// thumbprint (RFC 9449 §10).
+func (f *Flow) ValidateAuthorizationRequest(r *requests.AuthorizationRequest) error {
+	if r.DPoPJKT != "" && !jktPattern.MatchString(r.DPoPJKT) {
+		return autherrors.InvalidRequestError().WithDescription("\"dpop_jkt\" is not a valid JWK thumbprint").WithState(r.State)
+	}
+
+	return nil
+}
+
+// ProcessAuthorizationCode stores dpop_jkt in the authorization code's extra
+// data, binding the code to the DPoP key (RFC 9449 §10). The code must
+// implement models.ExtendableAuthorizationCode.
+func (f *Flow) ProcessAuthorizationCode(r *requests.AuthorizationRequest, authCode models.AuthorizationCode, _ map[string]interface{}) error {
+	if r.DPoPJKT == "" {
+		return nil
+	}
+
+	ext, ok := authCode.(models.ExtendableAuthorizationCode)
+	if !ok {
+		return autherrors.InvalidRequestError().WithDescription("\"dpop_jkt\" is not supported").WithState(r.State)
+	}
+
+	data := ext.GetExtraData()
+	if data == nil {
+		data = make(map[string]interface{})
+	}
+
+	data["dpop_jkt"] = r.DPoPJKT
+	ext.SetExtraData(data)
+	return nil
+}
+
+// ValidateTokenRequest verifies the DPoP proof of a token request and sets the
+// JWK thumbprint of its key in r.Confirmation, so that token generators embed
+// it as cnf.jkt. A request without a proof is rejected when DPoP is required
+// for the server or the client, or when the authorization code or the
+// refreshed token is bound to a DPoP key; otherwise it gets Bearer tokens.
+func (f *Flow) ValidateTokenRequest(r *requests.TokenRequest) error {
+	codeJKT := authCodeJKT(r.AuthCode)
+	tokenJKT := boundJKT(r.Client, r.Token)
+
+	if len(r.Request.Header.Values(HeaderDPoP)) == 0 {
+		if f.required || clientRequiresDPoP(r.Client) {
+			return autherrors.InvalidDPoPProofError().WithDescription("missing DPoP proof")
+		}
+
+		if codeJKT != "" {
+			return autherrors.InvalidGrantError().WithDescription("authorization code is bound to a DPoP key")
+		}
+
+		if tokenJKT != "" {
+			return autherrors.InvalidGrantError().WithDescription("refresh token is bound to a DPoP key")
+		}
+
+		return nil
+	}
+
+	proof, err := f.verifyProof(r.Request, "")
+	if err != nil {
+		return err
+	}
+
+	if codeJKT != "" && !sameJKT(codeJKT, proof.Thumbprint) {
+		return autherrors.InvalidGrantError().WithDescription("authorization code is bound to a different DPoP key")
+	}
+
+	if tokenJKT != "" && !sameJKT(tokenJKT, proof.Thumbprint) {
+		return autherrors.InvalidGrantError().WithDescription("refresh token is bound to a different DPoP key")
+	}
+
+	if r.Confirmation == nil {
+		r.Confirmation = make(map[string]interface{})
+	}
+
+	r.Confirmation[ConfirmationJKT] = proof.Thumbprint
+	return nil
+}
+
+// ProcessToken marks a DPoP-bound token with token_type DPoP (RFC 9449 §5)
+// and records the confirmation in the token's extra data under cnf, so that
+// it is persisted and returned by token introspection (RFC 9449 §6.2). The
+// extra data is only written for tokens implementing models.ExtendableToken.
+func (f *Flow) ProcessToken(r *requests.TokenRequest, token models.Token, data map[string]interface{}) error {
+	if _, ok := r.Confirmation[ConfirmationJKT]; !ok {
+		return nil
+	}
+
+	token.SetType(TokenTypeDPoP)
+	data["token_type"] = TokenTypeDPoP
+
+	ext, ok := token.(models.ExtendableToken)
+	if !ok {
+		return nil
+	}
+
+	extra := ext.GetExtraData()
+	if extra == nil {
+		extra = make(map[string]interface{})
+	}
+
+	extra["cnf"] = r.Confirmation
+	ext.SetExtraData(extra)
+	return nil
+}
+
+// clientRequiresDPoP reports whether client registered
+// dpop_bound_access_tokens.
+func clientRequiresDPoP(client models.Client) bool {
+	if utils.IsNil(client) {
+		return false
+	}
+
+	p, ok := client.(DPoPBoundTokensProvider)
+	return ok && p.GetDPoPBoundAccessTokens()
+}
+
+// authCodeJKT returns the dpop_jkt the authorization code is bound to.
+func authCodeJKT(authCode models.AuthorizationCode) string {
+	ext, ok := authCode.(models.ExtendableAuthorizationCode)
+	if !ok || utils.IsNil(ext) {
+		return ""
+	}
+
+	jkt, _ := ext.GetExtraData()["dpop_jkt"].(string)
+	return jkt
+}
+
+// boundJKT returns the JWK thumbprint the refreshed token is bound to. Only
+// refresh tokens of public clients are bound to the DPoP key; those of
+// confidential clients are bound to the client itself (RFC 9449 §5).
+func boundJKT(client models.Client, token models.Token) string {
+	if utils.IsNil(client) || !client.IsPublic() {
+		return ""
+	}
+
+	ext, ok := token.(models.ExtendableToken)
+	if !ok || utils.IsNil(ext) {
+		return ""
+	}
+
+	return ConfirmationThumbprint(ext.GetExtraData())
+}
+
+func sameJKT(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
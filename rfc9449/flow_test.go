@@ -0,0 +1,201 @@
+package rfc9449
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	autherrors "github.com/tniah/authlib/errors"
+	"github.com/tniah/authlib/integrations/sql"
+	"github.com/tniah/authlib/requests"
+	"github.com/tniah/authlib/types"
+)
+
+func TestMust(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		f, err := Must(NewConfig())
+		assert.NoError(t, err)
+		assert.NotNil(t, f)
+	})
+
+	t.Run("error_on_invalid_config", func(t *testing.T) {
+		f, err := Must(NewConfig().SetJWTIDCache(nil))
+		assert.Nil(t, f)
+		assert.ErrorIs(t, err, ErrNilJWTIDCache)
+	})
+}
+
+func TestFlow_ValidateAuthorizationRequest(t *testing.T) {
+	f := New(NewConfig())
+
+	t.Run("success_without_dpop_jkt", func(t *testing.T) {
+		assert.NoError(t, f.ValidateAuthorizationRequest(&requests.AuthorizationRequest{}))
+	})
+
+	t.Run("success_with_dpop_jkt", func(t *testing.T) {
+		r := &requests.AuthorizationRequest{DPoPJKT: "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs"}
+		assert.NoError(t, f.ValidateAuthorizationRequest(r))
+	})
+
+	t.Run("error_on_malformed_dpop_jkt", func(t *testing.T) {
+		r := &requests.AuthorizationRequest{DPoPJKT: "not a thumbprint", State: "xyz"}
+		err := f.ValidateAuthorizationRequest(r)
+		assertErrorCode(t, err, autherrors.ErrInvalidRequest)
+		assert.Equal(t, "xyz", autherrors.ToAuthLibError(err).State)
+	})
+}
+
+func TestFlow_ProcessAuthorizationCode(t *testing.T) {
+	f := New(NewConfig())
+	const jkt = "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs"
+
+	t.Run("stores_dpop_jkt", func(t *testing.T) {
+		code := &sql.AuthorizationCode{}
+		err := f.ProcessAuthorizationCode(&requests.AuthorizationRequest{DPoPJKT: jkt}, code, map[string]interface{}{})
+		assert.NoError(t, err)
+		assert.Equal(t, jkt, code.GetExtraData()["dpop_jkt"])
+	})
+
+	t.Run("noop_without_dpop_jkt", func(t *testing.T) {
+		code := &sql.AuthorizationCode{}
+		err := f.ProcessAuthorizationCode(&requests.AuthorizationRequest{}, code, map[string]interface{}{})
+		assert.NoError(t, err)
+		assert.Nil(t, code.GetExtraData())
+	})
+}
+
+func TestFlow_ValidateTokenRequest(t *testing.T) {
+	key := newProofKey(t)
+	otherKey := newProofKey(t)
+	confidential := &sql.Client{ClientID: "confidential", TokenEndpointAuthMethod: string(types.ClientBasicAuthentication)}
+	public := &sql.Client{ClientID: "public", TokenEndpointAuthMethod: string(types.ClientNoneAuthentication)}
+
+	tokenRequest := func(client *sql.Client, k *proofKey) *requests.TokenRequest {
+		r := newProofRequest(http.MethodPost, tokenEndpoint)
+		if k != nil {
+			r.Header.Set(HeaderDPoP, k.proof(t, proofClaims(http.MethodPost, tokenEndpoint), nil))
+		}
+		return &requests.TokenRequest{Client: client, Request: r}
+	}
+	boundToken := func(jkt string) *sql.Token {
+		return &sql.Token{Data: map[string]interface{}{"cnf": map[string]interface{}{ConfirmationJKT: jkt}}}
+	}
+	boundCode := func(jkt string) *sql.AuthorizationCode {
+		return &sql.AuthorizationCode{Data: map[string]interface{}{"dpop_jkt": jkt}}
+	}
+
+	t.Run("binds_token_to_proof_key", func(t *testing.T) {
+		r := tokenRequest(confidential, key)
+		require.NoError(t, New(NewConfig()).ValidateTokenRequest(r))
+		assert.Equal(t, map[string]interface{}{ConfirmationJKT: key.thumbprint}, r.Confirmation)
+	})
+
+	t.Run("keeps_other_confirmation_members", func(t *testing.T) {
+		r := tokenRequest(confidential, key)
+		r.Confirmation = map[string]interface{}{"x5t#S256": "abc"}
+		require.NoError(t, New(NewConfig()).ValidateTokenRequest(r))
+		assert.Equal(t, "abc", r.Confirmation["x5t#S256"])
+		assert.Equal(t, key.thumbprint, r.Confirmation[ConfirmationJKT])
+	})
+
+	t.Run("skips_binding_without_proof", func(t *testing.T) {
+		r := tokenRequest(confidential, nil)
+		require.NoError(t, New(NewConfig()).ValidateTokenRequest(r))
+		assert.Nil(t, r.Confirmation)
+	})
+
+	t.Run("error_without_proof_when_required", func(t *testing.T) {
+		err := New(NewConfig().SetRequired(true)).ValidateTokenRequest(tokenRequest(confidential, nil))
+		assertErrorCode(t, err, autherrors.ErrInvalidDPoPProof)
+	})
+
+	t.Run("error_without_proof_when_client_requires_dpop", func(t *testing.T) {
+		client := &sql.Client{ClientID: "dpop", DPoPBoundAccessTokens: true}
+		err := New(NewConfig()).ValidateTokenRequest(tokenRequest(client, nil))
+		assertErrorCode(t, err, autherrors.ErrInvalidDPoPProof)
+	})
+
+	t.Run("error_on_invalid_proof", func(t *testing.T) {
+		r := tokenRequest(confidential, nil)
+		r.Request.Header.Set(HeaderDPoP, "not-a-jwt")
+		err := New(NewConfig()).ValidateTokenRequest(r)
+		assertErrorCode(t, err, autherrors.ErrInvalidDPoPProof)
+	})
+
+	t.Run("authorization_code_bound_to_proof_key", func(t *testing.T) {
+		r := tokenRequest(public, key)
+		r.AuthCode = boundCode(key.thumbprint)
+		assert.NoError(t, New(NewConfig()).ValidateTokenRequest(r))
+	})
+
+	t.Run("error_when_authorization_code_is_bound_to_other_key", func(t *testing.T) {
+		r := tokenRequest(public, otherKey)
+		r.AuthCode = boundCode(key.thumbprint)
+		assertErrorCode(t, New(NewConfig()).ValidateTokenRequest(r), autherrors.ErrInvalidGrant)
+	})
+
+	t.Run("error_when_bound_authorization_code_is_sent_without_proof", func(t *testing.T) {
+		r := tokenRequest(public, nil)
+		r.AuthCode = boundCode(key.thumbprint)
+		assertErrorCode(t, New(NewConfig()).ValidateTokenRequest(r), autherrors.ErrInvalidGrant)
+	})
+
+	t.Run("public_client_refreshes_with_bound_key", func(t *testing.T) {
+		r := tokenRequest(public, key)
+		r.Token = boundToken(key.thumbprint)
+		assert.NoError(t, New(NewConfig()).ValidateTokenRequest(r))
+	})
+
+	t.Run("error_when_public_client_refreshes_with_other_key", func(t *testing.T) {
+		r := tokenRequest(public, otherKey)
+		r.Token = boundToken(key.thumbprint)
+		assertErrorCode(t, New(NewConfig()).ValidateTokenRequest(r), autherrors.ErrInvalidGrant)
+	})
+
+	t.Run("error_when_public_client_refreshes_without_proof", func(t *testing.T) {
+		r := tokenRequest(public, nil)
+		r.Token = boundToken(key.thumbprint)
+		assertErrorCode(t, New(NewConfig()).ValidateTokenRequest(r), autherrors.ErrInvalidGrant)
+	})
+
+	t.Run("confidential_client_refreshes_with_new_key", func(t *testing.T) {
+		r := tokenRequest(confidential, otherKey)
+		r.Token = boundToken(key.thumbprint)
+		require.NoError(t, New(NewConfig()).ValidateTokenRequest(r))
+		assert.Equal(t, otherKey.thumbprint, r.Confirmation[ConfirmationJKT])
+	})
+}
+
+func TestFlow_ProcessToken(t *testing.T) {
+	f := New(NewConfig())
+	cnf := map[string]interface{}{ConfirmationJKT: "abc"}
+
+	t.Run("marks_token_as_dpop", func(t *testing.T) {
+		token := &sql.Token{TokenType: "Bearer", Data: map[string]interface{}{"foo": "bar"}}
+		data := map[string]interface{}{"token_type": "Bearer"}
+
+		err := f.ProcessToken(&requests.TokenRequest{Confirmation: cnf}, token, data)
+		assert.NoError(t, err)
+		assert.Equal(t, TokenTypeDPoP, token.TokenType)
+		assert.Equal(t, TokenTypeDPoP, data["token_type"])
+		assert.Equal(t, map[string]interface{}{"foo": "bar", "cnf": cnf}, token.Data)
+	})
+
+	t.Run("noop_without_jkt", func(t *testing.T) {
+		token := &sql.Token{TokenType: "Bearer"}
+		data := map[string]interface{}{"token_type": "Bearer"}
+
+		r := &requests.TokenRequest{Confirmation: map[string]interface{}{"x5t#S256": "abc"}}
+		assert.NoError(t, f.ProcessToken(r, token, data))
+		assert.Equal(t, "Bearer", token.TokenType)
+		assert.Equal(t, "Bearer", data["token_type"])
+		assert.Nil(t, token.Data)
+	})
+}
+
+func TestFlow_ProvideMetadata(t *testing.T) {
+	md := types.Metadata{}
+	New(NewConfig().SetSigningMethods([]string{"ES256", "RS256"})).ProvideMetadata(md)
+	assert.Equal(t, []string{"ES256", "RS256"}, md[types.MetadataDPoPSigningAlgValuesSupported])
+}
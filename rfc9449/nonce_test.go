@@ -0,0 +1,83 @@
+package rfc9449
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHMACNonceManager(t *testing.T) {
+	ctx := context.Background()
+	mgr := NewHMACNonceManager([]byte("0123456789abcdef0123456789abcdef"))
+	assert.Equal(t, DefaultNonceLifetime, mgr.lifetime)
+
+	t.Run("accepts_issued_nonce", func(t *testing.T) {
+		nonce, err := mgr.NewNonce(ctx)
+		require.NoError(t, err)
+
+		valid, err := mgr.ValidNonce(ctx, nonce)
+		assert.NoError(t, err)
+		assert.True(t, valid)
+	})
+
+	t.Run("accepts_nonce_from_instance_sharing_the_key", func(t *testing.T) {
+		nonce, err := NewHMACNonceManager([]byte("0123456789abcdef0123456789abcdef")).NewNonce(ctx)
+		require.NoError(t, err)
+
+		valid, err := mgr.ValidNonce(ctx, nonce)
+		assert.NoError(t, err)
+		assert.True(t, valid)
+	})
+
+	t.Run("rejects_nonce_signed_with_other_key", func(t *testing.T) {
+		nonce, err := NewHMACNonceManager([]byte("another-key")).NewNonce(ctx)
+		require.NoError(t, err)
+
+		valid, err := mgr.ValidNonce(ctx, nonce)
+		assert.NoError(t, err)
+		assert.False(t, valid)
+	})
+
+	t.Run("rejects_expired_nonce", func(t *testing.T) {
+		nonce := mgr.nonce(time.Now().Add(-DefaultNonceLifetime - time.Second))
+
+		valid, err := mgr.ValidNonce(ctx, nonce)
+		assert.NoError(t, err)
+		assert.False(t, valid)
+	})
+
+	t.Run("set_lifetime", func(t *testing.T) {
+		short := NewHMACNonceManager([]byte("key"))
+		short.SetLifetime(time.Second)
+		nonce := short.nonce(time.Now().Add(-time.Second * 2))
+
+		valid, err := short.ValidNonce(ctx, nonce)
+		assert.NoError(t, err)
+		assert.False(t, valid)
+	})
+
+	t.Run("rejects_tampered_timestamp", func(t *testing.T) {
+		nonce, err := mgr.NewNonce(ctx)
+		require.NoError(t, err)
+
+		_, mac, _ := strings.Cut(nonce, ".")
+		later := binary.BigEndian.AppendUint64(nil, uint64(time.Now().Add(time.Hour).Unix()))
+		valid, err := mgr.ValidNonce(ctx, base64.RawURLEncoding.EncodeToString(later)+"."+mac)
+		assert.NoError(t, err)
+		assert.False(t, valid)
+	})
+
+	t.Run("rejects_malformed_nonce", func(t *testing.T) {
+		for _, nonce := range []string{"", "abc", "!!.abc", "AQ.abc"} {
+			valid, err := mgr.ValidNonce(ctx, nonce)
+			assert.NoError(t, err)
+			assert.False(t, valid, nonce)
+		}
+	})
+}
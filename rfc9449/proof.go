@@ -0,0 +1,233 @@
+package rfc9449
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	autherrors "github.com/tniah/authlib/errors"
+	"github.com/tniah/authlib/utils"
+)
+
+const (
+	// HeaderDPoP is the request header carrying the DPoP proof.
+	HeaderDPoP = "DPoP"
+	// HeaderDPoPNonce is the response header carrying a server-provided nonce.
+	HeaderDPoPNonce = "DPoP-Nonce"
+	// TokenTypeDPoP is the token_type of DPoP-bound access tokens, and the
+	// authorization scheme they are presented with (RFC 9449 §5, §7.1).
+	TokenTypeDPoP = "DPoP"
+	// ProofType is the typ header of a DPoP proof (RFC 9449 §4.2).
+	ProofType = "dpop+jwt"
+	// ConfirmationJKT is the cnf member carrying the JWK thumbprint of the
+	// key a token is bound to (RFC 9449 §6.1).
+	ConfirmationJKT = "jkt"
+)
+
+// privateKeyMembers are the JWK members only present in private keys, which
+// must never be sent in a proof.
+var privateKeyMembers = []string{"d", "p", "q", "dp", "dq", "qi", "oth", "k"}
+
+// Proof is a verified DPoP proof.
+type Proof struct {
+	// JWK is the public key the proof was signed with.
+	JWK utils.JWK
+	// Thumbprint is the JWK thumbprint of JWK (RFC 7638), the value bound
+	// to tokens as cnf.jkt.
+	Thumbprint string
+	// ID is the jti of the proof.
+	ID string
+	// IssuedAt is the iat of the proof.
+	IssuedAt time.Time
+	// Nonce is the server-provided nonce of the proof, or "".
+	Nonce string
+}
+
+// verifyProof checks the DPoP proof sent with r (RFC 9449 §4.3). accessToken
+// is the token presented to a resource server, whose hash the proof must
+// carry in ath; it is empty at the token endpoint.
+func (cfg *Config) verifyProof(r *http.Request, accessToken string) (*Proof, error) {
+	values := r.Header.Values(HeaderDPoP)
+	if len(values) == 0 {
+		return nil, autherrors.InvalidDPoPProofError().WithDescription("missing DPoP proof")
+	}
+
+	if len(values) > 1 {
+		return nil, autherrors.InvalidDPoPProofError().WithDescription("multiple DPoP proofs")
+	}
+
+	var key utils.JWK
+	claims := jwt.MapClaims{}
+	parser := jwt.NewParser(jwt.WithValidMethods(cfg.signingMethods), jwt.WithoutClaimsValidation())
+	_, err := parser.ParseWithClaims(values[0], claims, func(t *jwt.Token) (interface{}, error) {
+		if typ, _ := t.Header["typ"].(string); typ != ProofType {
+			return nil, errors.New("typ is not dpop+jwt")
+		}
+
+		var err error
+		if key, err = headerJWK(t.Header["jwk"]); err != nil {
+			return nil, err
+		}
+
+		return key.PublicKey()
+	})
+	if err != nil {
+		return nil, autherrors.InvalidDPoPProofError().WithDescription("invalid DPoP proof").WithCause(err)
+	}
+
+	thumbprint, err := key.Thumbprint()
+	if err != nil {
+		return nil, autherrors.InvalidDPoPProofError().WithDescription("invalid DPoP proof").WithCause(err)
+	}
+
+	jti, _ := claims["jti"].(string)
+	if jti == "" {
+		return nil, autherrors.InvalidDPoPProofError().WithDescription("DPoP proof is missing \"jti\"")
+	}
+
+	if htm, _ := claims["htm"].(string); htm != r.Method {
+		return nil, autherrors.InvalidDPoPProofError().WithDescription("DPoP proof \"htm\" does not match the request method")
+	}
+
+	if htu, _ := claims["htu"].(string); !sameURI(htu, cfg.requestURI(r)) {
+		return nil, autherrors.InvalidDPoPProofError().WithDescription("DPoP proof \"htu\" does not match the request URI")
+	}
+
+	iat, err := claims.GetIssuedAt()
+	if err != nil || iat == nil {
+		return nil, autherrors.InvalidDPoPProofError().WithDescription("DPoP proof is missing \"iat\"")
+	}
+
+	now := time.Now()
+	if iat.After(now.Add(cfg.leeway)) || iat.Add(cfg.proofLifetime+cfg.leeway).Before(now) {
+		return nil, autherrors.InvalidDPoPProofError().WithDescription("DPoP proof is expired or issued in the future")
+	}
+
+	if accessToken != "" {
+		sum := sha256.Sum256([]byte(accessToken))
+		ath, _ := claims["ath"].(string)
+		if subtle.ConstantTimeCompare([]byte(ath), []byte(base64.RawURLEncoding.EncodeToString(sum[:]))) != 1 {
+			return nil, autherrors.InvalidDPoPProofError().WithDescription("DPoP proof \"ath\" does not match the access token")
+		}
+	}
+
+	nonce, _ := claims["nonce"].(string)
+	if err = cfg.checkNonce(r, nonce); err != nil {
+		return nil, err
+	}
+
+	// Checked last so that a rejected proof does not use up its jti.
+	ok, err := cfg.jwtIDCache.Use(r.Context(), thumbprint, jti, iat.Add(cfg.proofLifetime+cfg.leeway))
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return nil, autherrors.InvalidDPoPProofError().WithDescription("DPoP proof has already been used")
+	}
+
+	return &Proof{JWK: key, Thumbprint: thumbprint, ID: jti, IssuedAt: iat.Time, Nonce: nonce}, nil
+}
+
+// checkNonce requires a current server-provided nonce when a NonceManager is
+// configured, answering use_dpop_nonce with a fresh one otherwise.
+func (cfg *Config) checkNonce(r *http.Request, nonce string) error {
+	if utils.IsNil(cfg.nonceMgr) {
+		return nil
+	}
+
+	if nonce != "" {
+		valid, err := cfg.nonceMgr.ValidNonce(r.Context(), nonce)
+		if err != nil {
+			return err
+		}
+
+		if valid {
+			return nil
+		}
+	}
+
+	fresh, err := cfg.nonceMgr.NewNonce(r.Context())
+	if err != nil {
+		return err
+	}
+
+	return autherrors.UseDPoPNonceError(fresh).WithDescription("DPoP proof is missing a current server-provided \"nonce\"")
+}
+
+// requestURI returns the URI the htu claim must match: the request URI
+// without query and fragment.
+func (cfg *Config) requestURI(r *http.Request) string {
+	base := cfg.baseURL
+	if base == "" {
+		scheme := "http"
+		if r.TLS != nil {
+			scheme = "https"
+		}
+		base = scheme + "://" + r.Host
+	}
+
+	return strings.TrimSuffix(base, "/") + r.URL.Path
+}
+
+// headerJWK decodes the jwk header of a proof, rejecting private keys.
+func headerJWK(v interface{}) (utils.JWK, error) {
+	var key utils.JWK
+
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return key, errors.New("missing jwk header")
+	}
+
+	for _, member := range privateKeyMembers {
+		if _, ok := m[member]; ok {
+			return key, errors.New("jwk header holds a private key")
+		}
+	}
+
+	raw, err := json.Marshal(m)
+	if err != nil {
+		return key, err
+	}
+
+	err = json.Unmarshal(raw, &key)
+	return key, err
+}
+
+// sameURI compares two http(s) URIs ignoring query, fragment, the case of
+// scheme and host, and default ports (RFC 9449 §4.3, item 9).
+func sameURI(a, b string) bool {
+	ua, err := url.Parse(a)
+	if err != nil {
+		return false
+	}
+
+	ub, err := url.Parse(b)
+	if err != nil {
+		return false
+	}
+
+	return normalizedOrigin(ua) == normalizedOrigin(ub) && ua.EscapedPath() == ub.EscapedPath()
+}
+
+func normalizedOrigin(u *url.URL) string {
+	scheme := strings.ToLower(u.Scheme)
+	host := strings.ToLower(u.Hostname())
+	port := u.Port()
+	if (scheme == "https" && port == "443") || (scheme == "http" && port == "80") {
+		port = ""
+	}
+
+	if port != "" {
+		host += ":" + port
+	}
+
+	return scheme + "://" + host
+}
@@ -0,0 +1,303 @@
+package rfc9449
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	autherrors "github.com/tniah/authlib/errors"
+	rfc9449 "github.com/tniah/authlib/mocks/rfc9449"
+)
+
+const tokenEndpoint = "https://as.example.com/token"
+
+// proofKey is a client DPoP key.
+type proofKey struct {
+	priv       *ecdsa.PrivateKey
+	jwk        map[string]interface{}
+	thumbprint string
+}
+
+func newProofKey(t *testing.T) *proofKey {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	x := base64.RawURLEncoding.EncodeToString(priv.X.FillBytes(make([]byte, 32)))
+	y := base64.RawURLEncoding.EncodeToString(priv.Y.FillBytes(make([]byte, 32)))
+	sum := sha256.Sum256([]byte(`{"crv":"P-256","kty":"EC","x":"` + x + `","y":"` + y + `"}`))
+
+	return &proofKey{
+		priv:       priv,
+		jwk:        map[string]interface{}{"kty": "EC", "crv": "P-256", "x": x, "y": y},
+		thumbprint: base64.RawURLEncoding.EncodeToString(sum[:]),
+	}
+}
+
+// proof signs claims as a DPoP proof. header entries override the defaults.
+func (k *proofKey) proof(t *testing.T, claims jwt.MapClaims, header map[string]interface{}) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+	token.Header["typ"] = ProofType
+	token.Header["jwk"] = k.jwk
+	for name, v := range header {
+		token.Header[name] = v
+	}
+
+	signed, err := token.SignedString(k.priv)
+	require.NoError(t, err)
+	return signed
+}
+
+// proofClaims returns valid proof claims for method and uri.
+func proofClaims(method, uri string) jwt.MapClaims {
+	return jwt.MapClaims{
+		"jti": uuid.NewString(),
+		"htm": method,
+		"htu": uri,
+		"iat": time.Now().Unix(),
+	}
+}
+
+// newProofRequest returns a request to uri carrying proofs in DPoP headers.
+func newProofRequest(method, uri string, proofs ...string) *http.Request {
+	r := httptest.NewRequest(method, uri, nil)
+	for _, p := range proofs {
+		r.Header.Add(HeaderDPoP, p)
+	}
+	return r
+}
+
+func assertErrorCode(t *testing.T, err error, code error) {
+	t.Helper()
+	require.Error(t, err)
+	assert.ErrorIs(t, autherrors.ToAuthLibError(err).Code, code)
+}
+
+func TestConfig_verifyProof(t *testing.T) {
+	key := newProofKey(t)
+
+	t.Run("success", func(t *testing.T) {
+		claims := proofClaims(http.MethodPost, tokenEndpoint)
+		r := newProofRequest(http.MethodPost, tokenEndpoint, key.proof(t, claims, nil))
+
+		proof, err := NewConfig().verifyProof(r, "")
+		require.NoError(t, err)
+		assert.Equal(t, key.thumbprint, proof.Thumbprint)
+		assert.Equal(t, claims["jti"], proof.ID)
+		assert.Equal(t, "EC", proof.JWK.Kty)
+	})
+
+	t.Run("success_ignores_query_and_default_port", func(t *testing.T) {
+		claims := proofClaims(http.MethodPost, "HTTPS://AS.example.com:443/token")
+		r := newProofRequest(http.MethodPost, tokenEndpoint+"?foo=bar", key.proof(t, claims, nil))
+
+		_, err := NewConfig().verifyProof(r, "")
+		assert.NoError(t, err)
+	})
+
+	t.Run("success_with_base_url", func(t *testing.T) {
+		claims := proofClaims(http.MethodPost, "https://public.example.com/token")
+		r := newProofRequest(http.MethodPost, "http://10.0.0.1:8080/token", key.proof(t, claims, nil))
+
+		_, err := NewConfig().SetBaseURL("https://public.example.com/").verifyProof(r, "")
+		assert.NoError(t, err)
+	})
+
+	t.Run("success_with_access_token_hash", func(t *testing.T) {
+		sum := sha256.Sum256([]byte("access-token"))
+		claims := proofClaims(http.MethodGet, "https://api.example.com/resource")
+		claims["ath"] = base64.RawURLEncoding.EncodeToString(sum[:])
+		r := newProofRequest(http.MethodGet, "https://api.example.com/resource", key.proof(t, claims, nil))
+
+		_, err := NewConfig().verifyProof(r, "access-token")
+		assert.NoError(t, err)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		otherKey := newProofKey(t)
+		withClaim := func(name string, v interface{}) jwt.MapClaims {
+			claims := proofClaims(http.MethodPost, tokenEndpoint)
+			if v == nil {
+				delete(claims, name)
+			} else {
+				claims[name] = v
+			}
+			return claims
+		}
+
+		cases := []struct {
+			name   string
+			proofs func() []string
+		}{
+			{"missing_proof", func() []string { return nil }},
+			{"multiple_proofs", func() []string {
+				return []string{key.proof(t, proofClaims(http.MethodPost, tokenEndpoint), nil), key.proof(t, proofClaims(http.MethodPost, tokenEndpoint), nil)}
+			}},
+			{"malformed_proof", func() []string { return []string{"not-a-jwt"} }},
+			{"wrong_typ", func() []string {
+				return []string{key.proof(t, proofClaims(http.MethodPost, tokenEndpoint), map[string]interface{}{"typ": "JWT"})}
+			}},
+			{"missing_jwk", func() []string {
+				return []string{key.proof(t, proofClaims(http.MethodPost, tokenEndpoint), map[string]interface{}{"jwk": nil})}
+			}},
+			{"private_jwk", func() []string {
+				jwk := map[string]interface{}{"d": "secret"}
+				for k, v := range key.jwk {
+					jwk[k] = v
+				}
+				return []string{key.proof(t, proofClaims(http.MethodPost, tokenEndpoint), map[string]interface{}{"jwk": jwk})}
+			}},
+			{"signed_with_other_key", func() []string {
+				return []string{key.proof(t, proofClaims(http.MethodPost, tokenEndpoint), map[string]interface{}{"jwk": otherKey.jwk})}
+			}},
+			{"missing_jti", func() []string { return []string{key.proof(t, withClaim("jti", nil), nil)} }},
+			{"wrong_htm", func() []string { return []string{key.proof(t, withClaim("htm", http.MethodGet), nil)} }},
+			{"wrong_htu", func() []string {
+				return []string{key.proof(t, withClaim("htu", "https://as.example.com/other"), nil)}
+			}},
+			{"missing_iat", func() []string { return []string{key.proof(t, withClaim("iat", nil), nil)} }},
+			{"expired_iat", func() []string {
+				return []string{key.proof(t, withClaim("iat", time.Now().Add(-DefaultProofLifetime-DefaultLeeway-time.Second).Unix()), nil)}
+			}},
+			{"future_iat", func() []string {
+				return []string{key.proof(t, withClaim("iat", time.Now().Add(time.Minute).Unix()), nil)}
+			}},
+		}
+
+		for _, c := range cases {
+			t.Run(c.name, func(t *testing.T) {
+				r := newProofRequest(http.MethodPost, tokenEndpoint, c.proofs()...)
+
+				proof, err := NewConfig().verifyProof(r, "")
+				assert.Nil(t, proof)
+				assertErrorCode(t, err, autherrors.ErrInvalidDPoPProof)
+			})
+		}
+	})
+
+	t.Run("error_on_hmac_proof", func(t *testing.T) {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, proofClaims(http.MethodPost, tokenEndpoint))
+		token.Header["typ"] = ProofType
+		token.Header["jwk"] = map[string]interface{}{"kty": "oct", "k": "c2VjcmV0"}
+		signed, err := token.SignedString([]byte("secret"))
+		require.NoError(t, err)
+
+		_, err = NewConfig().verifyProof(newProofRequest(http.MethodPost, tokenEndpoint, signed), "")
+		assertErrorCode(t, err, autherrors.ErrInvalidDPoPProof)
+	})
+
+	t.Run("error_on_wrong_access_token_hash", func(t *testing.T) {
+		claims := proofClaims(http.MethodGet, "https://api.example.com/resource")
+		claims["ath"] = "wrong"
+		r := newProofRequest(http.MethodGet, "https://api.example.com/resource", key.proof(t, claims, nil))
+
+		_, err := NewConfig().verifyProof(r, "access-token")
+		assertErrorCode(t, err, autherrors.ErrInvalidDPoPProof)
+	})
+
+	t.Run("error_on_replayed_proof", func(t *testing.T) {
+		cfg := NewConfig()
+		proof := key.proof(t, proofClaims(http.MethodPost, tokenEndpoint), nil)
+
+		_, err := cfg.verifyProof(newProofRequest(http.MethodPost, tokenEndpoint, proof), "")
+		require.NoError(t, err)
+
+		_, err = cfg.verifyProof(newProofRequest(http.MethodPost, tokenEndpoint, proof), "")
+		assertErrorCode(t, err, autherrors.ErrInvalidDPoPProof)
+	})
+
+	t.Run("error_when_jwt_id_cache_fails", func(t *testing.T) {
+		cacheErr := errors.New("cache down")
+		cache := rfc9449.NewMockJWTIDCache(t)
+		cache.On("Use", mock.Anything, key.thumbprint, mock.Anything, mock.Anything).Return(false, cacheErr).Once()
+
+		r := newProofRequest(http.MethodPost, tokenEndpoint, key.proof(t, proofClaims(http.MethodPost, tokenEndpoint), nil))
+		_, err := NewConfig().SetJWTIDCache(cache).verifyProof(r, "")
+		assert.ErrorIs(t, err, cacheErr)
+	})
+
+	t.Run("nonce", func(t *testing.T) {
+		t.Run("success_with_valid_nonce", func(t *testing.T) {
+			nonceMgr := rfc9449.NewMockNonceManager(t)
+			nonceMgr.On("ValidNonce", mock.Anything, "n-1").Return(true, nil).Once()
+
+			claims := proofClaims(http.MethodPost, tokenEndpoint)
+			claims["nonce"] = "n-1"
+			r := newProofRequest(http.MethodPost, tokenEndpoint, key.proof(t, claims, nil))
+
+			proof, err := NewConfig().SetNonceManager(nonceMgr).verifyProof(r, "")
+			require.NoError(t, err)
+			assert.Equal(t, "n-1", proof.Nonce)
+		})
+
+		t.Run("error_use_dpop_nonce_when_missing", func(t *testing.T) {
+			nonceMgr := rfc9449.NewMockNonceManager(t)
+			nonceMgr.On("NewNonce", mock.Anything).Return("n-2", nil).Once()
+
+			r := newProofRequest(http.MethodPost, tokenEndpoint, key.proof(t, proofClaims(http.MethodPost, tokenEndpoint), nil))
+			_, err := NewConfig().SetNonceManager(nonceMgr).verifyProof(r, "")
+			assertErrorCode(t, err, autherrors.ErrUseDPoPNonce)
+			assert.Equal(t, "n-2", autherrors.ToAuthLibError(err).HttpHeader.Get(HeaderDPoPNonce))
+		})
+
+		t.Run("error_use_dpop_nonce_when_stale", func(t *testing.T) {
+			nonceMgr := rfc9449.NewMockNonceManager(t)
+			nonceMgr.On("ValidNonce", mock.Anything, "stale").Return(false, nil).Once()
+			nonceMgr.On("NewNonce", mock.Anything).Return("n-3", nil).Once()
+
+			claims := proofClaims(http.MethodPost, tokenEndpoint)
+			claims["nonce"] = "stale"
+			r := newProofRequest(http.MethodPost, tokenEndpoint, key.proof(t, claims, nil))
+
+			_, err := NewConfig().SetNonceManager(nonceMgr).verifyProof(r, "")
+			assertErrorCode(t, err, autherrors.ErrUseDPoPNonce)
+		})
+
+		t.Run("error_when_nonce_manager_fails", func(t *testing.T) {
+			mgrErr := errors.New("nonce store down")
+			nonceMgr := rfc9449.NewMockNonceManager(t)
+			nonceMgr.On("NewNonce", mock.Anything).Return("", mgrErr).Once()
+
+			r := newProofRequest(http.MethodPost, tokenEndpoint, key.proof(t, proofClaims(http.MethodPost, tokenEndpoint), nil))
+			_, err := NewConfig().SetNonceManager(nonceMgr).verifyProof(r, "")
+			assert.ErrorIs(t, err, mgrErr)
+		})
+
+		t.Run("nonce_failure_does_not_use_up_jti", func(t *testing.T) {
+			nonceMgr := rfc9449.NewMockNonceManager(t)
+			nonceMgr.On("NewNonce", mock.Anything).Return("n-4", nil).Once()
+			cfg := NewConfig().SetNonceManager(nonceMgr)
+
+			claims := proofClaims(http.MethodPost, tokenEndpoint)
+			_, err := cfg.verifyProof(newProofRequest(http.MethodPost, tokenEndpoint, key.proof(t, claims, nil)), "")
+			assertErrorCode(t, err, autherrors.ErrUseDPoPNonce)
+
+			nonceMgr.On("ValidNonce", mock.Anything, "n-4").Return(true, nil).Once()
+			claims["nonce"] = "n-4"
+			_, err = cfg.verifyProof(newProofRequest(http.MethodPost, tokenEndpoint, key.proof(t, claims, nil)), "")
+			assert.NoError(t, err)
+		})
+	})
+}
+
+func TestSameURI(t *testing.T) {
+	assert.True(t, sameURI("https://as.example.com/token", "https://AS.example.com:443/token"))
+	assert.True(t, sameURI("http://localhost:80/token", "http://localhost/token#frag"))
+	assert.True(t, sameURI("https://as.example.com:8443/token", "https://as.example.com:8443/token"))
+	assert.False(t, sameURI("https://as.example.com/token", "http://as.example.com/token"))
+	assert.False(t, sameURI("https://as.example.com:8443/token", "https://as.example.com/token"))
+	assert.False(t, sameURI("https://as.example.com/token", "https://as.example.com/Token"))
+	assert.False(t, sameURI("", "https://as.example.com/token"))
+	assert.False(t, sameURI("://bad", "https://as.example.com/token"))
+}
@@ -0,0 +1,74 @@
+package rfc9449
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+var (
+	// ErrMissingAccessToken is returned by VerifyResourceRequest when no
+	// access token is given.
+	ErrMissingAccessToken = errors.New("missing access token")
+	// ErrUnboundToken is returned by VerifyResourceRequest when the token
+	// carries no jkt confirmation.
+	ErrUnboundToken = errors.New("token is not bound to a DPoP key")
+	// ErrKeyMismatch is returned by VerifyResourceRequest when the proof is
+	// signed with another key than the one the token is bound to.
+	ErrKeyMismatch = errors.New("DPoP proof key does not match the token binding")
+)
+
+// AccessToken returns the access token sent with the DPoP authorization
+// scheme (RFC 9449 §7.1), or "" when there is none.
+func AccessToken(r *http.Request) string {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, TokenTypeDPoP) {
+		return ""
+	}
+
+	return strings.TrimSpace(token)
+}
+
+// ConfirmationThumbprint returns the jkt member of the cnf claim in claims,
+// or "" when there is none. claims is either the payload of a JWT access
+// token or an introspection response.
+func ConfirmationThumbprint(claims map[string]interface{}) string {
+	cnf, ok := claims["cnf"].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+
+	jkt, _ := cnf[ConfirmationJKT].(string)
+	return jkt
+}
+
+// VerifyResourceRequest is the resource server check of RFC 9449 §7: the
+// request must carry a DPoP proof for its method and URI, including the hash
+// of accessToken, signed with the key the token is bound to. claims is the
+// payload of the JWT access token or the introspection response for it.
+//
+// Proof failures are returned as invalid_dpop_proof or use_dpop_nonce
+// errors; resource servers should answer them, and the sentinel errors of
+// this file, with a 401 response carrying a WWW-Authenticate: DPoP
+// challenge (RFC 9449 §7.1).
+func (f *Flow) VerifyResourceRequest(r *http.Request, accessToken string, claims map[string]interface{}) (*Proof, error) {
+	if accessToken == "" {
+		return nil, ErrMissingAccessToken
+	}
+
+	jkt := ConfirmationThumbprint(claims)
+	if jkt == "" {
+		return nil, ErrUnboundToken
+	}
+
+	proof, err := f.verifyProof(r, accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if !sameJKT(jkt, proof.Thumbprint) {
+		return nil, ErrKeyMismatch
+	}
+
+	return proof, nil
+}
@@ -0,0 +1,72 @@
+package rfc9449
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	autherrors "github.com/tniah/authlib/errors"
+)
+
+func TestAccessToken(t *testing.T) {
+	r := newProofRequest(http.MethodGet, "https://api.example.com/resource")
+	assert.Empty(t, AccessToken(r))
+
+	r.Header.Set("Authorization", "DPoP Kz~8mXK1EalYznwH-LC-1fBAo.4Ljp~zsPE_NeO.gxU")
+	assert.Equal(t, "Kz~8mXK1EalYznwH-LC-1fBAo.4Ljp~zsPE_NeO.gxU", AccessToken(r))
+
+	r.Header.Set("Authorization", "Bearer abc")
+	assert.Empty(t, AccessToken(r))
+}
+
+func TestConfirmationThumbprint(t *testing.T) {
+	assert.Equal(t, "abc", ConfirmationThumbprint(map[string]interface{}{"cnf": map[string]interface{}{"jkt": "abc"}}))
+	assert.Empty(t, ConfirmationThumbprint(map[string]interface{}{"cnf": map[string]interface{}{"x5t#S256": "abc"}}))
+	assert.Empty(t, ConfirmationThumbprint(nil))
+}
+
+func TestFlow_VerifyResourceRequest(t *testing.T) {
+	const (
+		resource    = "https://api.example.com/resource"
+		accessToken = "access-token"
+	)
+	key := newProofKey(t)
+	sum := sha256.Sum256([]byte(accessToken))
+	ath := base64.RawURLEncoding.EncodeToString(sum[:])
+	claims := map[string]interface{}{"cnf": map[string]interface{}{ConfirmationJKT: key.thumbprint}}
+
+	resourceRequest := func(k *proofKey) *http.Request {
+		proofClaims := proofClaims(http.MethodGet, resource)
+		proofClaims["ath"] = ath
+		return newProofRequest(http.MethodGet, resource, k.proof(t, proofClaims, nil))
+	}
+
+	t.Run("success", func(t *testing.T) {
+		proof, err := New(NewConfig()).VerifyResourceRequest(resourceRequest(key), accessToken, claims)
+		require.NoError(t, err)
+		assert.Equal(t, key.thumbprint, proof.Thumbprint)
+	})
+
+	t.Run("error_without_access_token", func(t *testing.T) {
+		_, err := New(NewConfig()).VerifyResourceRequest(resourceRequest(key), "", claims)
+		assert.ErrorIs(t, err, ErrMissingAccessToken)
+	})
+
+	t.Run("error_on_unbound_token", func(t *testing.T) {
+		_, err := New(NewConfig()).VerifyResourceRequest(resourceRequest(key), accessToken, map[string]interface{}{})
+		assert.ErrorIs(t, err, ErrUnboundToken)
+	})
+
+	t.Run("error_on_other_key", func(t *testing.T) {
+		_, err := New(NewConfig()).VerifyResourceRequest(resourceRequest(newProofKey(t)), accessToken, claims)
+		assert.ErrorIs(t, err, ErrKeyMismatch)
+	})
+
+	t.Run("error_on_proof_for_other_token", func(t *testing.T) {
+		_, err := New(NewConfig()).VerifyResourceRequest(resourceRequest(key), "other-token", claims)
+		assertErrorCode(t, err, autherrors.ErrInvalidDPoPProof)
+	})
+}
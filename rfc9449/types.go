@@ -0,0 +1,33 @@
+package rfc9449
+
+import (
+	"context"
+	"time"
+)
+
+// JWTIDCache remembers the jti of accepted DPoP proofs so that each proof can
+// be used only once (RFC 9449 §11.1). utils.MemoryJWTIDCache satisfies it.
+type JWTIDCache interface {
+	// Use records jti for issuer until expiresAt. It returns false when the
+	// pair has already been recorded and has not expired yet. The issuer is
+	// the JWK thumbprint of the proof key.
+	Use(ctx context.Context, issuer, jti string, expiresAt time.Time) (bool, error)
+}
+
+// NonceManager issues and checks the server-provided nonces clients must
+// include in their DPoP proofs (RFC 9449 §8). HMACNonceManager satisfies it.
+type NonceManager interface {
+	// NewNonce returns a fresh nonce, sent to the client in the DPoP-Nonce
+	// header.
+	NewNonce(ctx context.Context) (string, error)
+	// ValidNonce reports whether nonce was issued by NewNonce and is still
+	// current.
+	ValidNonce(ctx context.Context, nonce string) (bool, error)
+}
+
+// DPoPBoundTokensProvider is implemented by clients that can register the
+// dpop_bound_access_tokens metadata (RFC 9449 §5.2). When it returns true,
+// token requests from the client without a DPoP proof are rejected.
+type DPoPBoundTokensProvider interface {
+	GetDPoPBoundAccessTokens() bool
+}
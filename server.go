@@ -2,12 +2,14 @@ package authlib
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 
 	autherrors "github.com/tniah/authlib/errors"
 	"github.com/tniah/authlib/models"
 	"github.com/tniah/authlib/requests"
+	"github.com/tniah/authlib/types"
 	"github.com/tniah/authlib/utils"
 )
 
@@ -21,6 +23,12 @@ type Server struct {
 	consentGrants       []ConsentGrant
 	tokenGrants         []TokenGrant
 	endpoints           []Endpoint
+	// requestResolvers run in registration order on every authorization and
+	// consent request before the grant is selected.
+	requestResolvers []AuthorizationRequestResolver
+	// responseModeHandlers encode error redirects for the response_mode of
+	// the failed authorization request.
+	responseModeHandlers []ResponseModeHandler
 	// errHandler, if set, overrides the default OAuth2 error response logic.
 	errHandler ErrorHandler
 }
@@ -42,13 +50,14 @@ func (srv *Server) AuthorizationGrant(r *requests.AuthorizationRequest) (Authori
 	return nil, autherrors.UnsupportedResponseTypeError()
 }
 
-// ValidateAuthorizationRequest parses the HTTP request, sets the authenticated
-// user, finds the matching AuthorizationGrant, and runs its validation step.
+// ValidateAuthorizationRequest parses the HTTP request, resolves parameters
+// passed by reference (e.g. request_uri), sets the authenticated user, finds
+// the matching AuthorizationGrant, and runs its validation step.
 // It returns the grant and the populated request so the caller can proceed to
 // issue the authorization response. Errors are returned unwrapped; use
 // HandleError to convert them into HTTP responses.
 func (srv *Server) ValidateAuthorizationRequest(hr *http.Request, u models.User) (AuthorizationGrant, *requests.AuthorizationRequest, error) {
-	r, err := requests.NewAuthorizationRequestFromHttp(hr)
+	r, err := srv.authorizationRequest(hr)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -61,7 +70,7 @@ func (srv *Server) ValidateAuthorizationRequest(hr *http.Request, u models.User)
 	}
 
 	if err = grant.ValidateAuthorizationRequest(r); err != nil {
-		return nil, nil, err
+		return nil, nil, withResponseMode(err, r)
 	}
 
 	return grant, r, nil
@@ -77,10 +86,14 @@ func (srv *Server) CreateAuthorizationResponse(hr *http.Request, rw http.Respons
 		return srv.HandleError(hr, rw, err)
 	}
 
-	if err = grant.AuthorizationResponse(r, rw); err != nil {
+	if err = srv.ConsumeAuthorizationRequest(r); err != nil {
 		return srv.HandleError(hr, rw, err)
 	}
 
+	if err = grant.AuthorizationResponse(r, rw); err != nil {
+		return srv.HandleError(hr, rw, withResponseMode(err, r))
+	}
+
 	return nil
 }
 
@@ -96,13 +109,14 @@ func (srv *Server) ConsentGrant(r *requests.AuthorizationRequest) (ConsentGrant,
 	return nil, autherrors.UnsupportedResponseTypeError()
 }
 
-// ValidateConsentRequest parses the HTTP request, sets the authenticated user,
-// finds the matching ConsentGrant, and runs its consent validation step.
+// ValidateConsentRequest parses the HTTP request, resolves parameters passed
+// by reference, sets the authenticated user, finds the matching ConsentGrant,
+// and runs its consent validation step.
 // It returns the grant and the populated request so the caller can proceed to
 // issue the authorization response. Errors are returned unwrapped; use
 // HandleError to convert them into HTTP responses.
 func (srv *Server) ValidateConsentRequest(hr *http.Request, u models.User) (ConsentGrant, *requests.AuthorizationRequest, error) {
-	r, err := requests.NewAuthorizationRequestFromHttp(hr)
+	r, err := srv.authorizationRequest(hr)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -115,7 +129,7 @@ func (srv *Server) ValidateConsentRequest(hr *http.Request, u models.User) (Cons
 	}
 
 	if err = grant.ValidateConsentRequest(r); err != nil {
-		return nil, nil, err
+		return nil, nil, withResponseMode(err, r)
 	}
 
 	return grant, r, nil
@@ -129,13 +143,63 @@ func (srv *Server) CreateConsentResponse(hr *http.Request, rw http.ResponseWrite
 		return srv.HandleError(hr, rw, err)
 	}
 
-	if err = grant.AuthorizationResponse(r, rw); err != nil {
+	if err = srv.ConsumeAuthorizationRequest(r); err != nil {
 		return srv.HandleError(hr, rw, err)
 	}
 
+	if err = grant.AuthorizationResponse(r, rw); err != nil {
+		return srv.HandleError(hr, rw, withResponseMode(err, r))
+	}
+
 	return nil
 }
 
+// authorizationRequest parses hr and passes the result through every
+// registered AuthorizationRequestResolver.
+func (srv *Server) authorizationRequest(hr *http.Request) (*requests.AuthorizationRequest, error) {
+	r, err := requests.NewAuthorizationRequestFromHttp(hr)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, resolver := range srv.requestResolvers {
+		if r, err = resolver.ResolveAuthorizationRequest(r); err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+// ConsumeAuthorizationRequest passes r to every registered resolver that is an
+// AuthorizationRequestConsumer, so one-time references such as a pushed
+// request_uri cannot be used again. CreateAuthorizationResponse and
+// CreateConsentResponse call it before issuing the response; call it yourself
+// when issuing the response from the grant returned by
+// ValidateAuthorizationRequest or ValidateConsentRequest.
+func (srv *Server) ConsumeAuthorizationRequest(r *requests.AuthorizationRequest) error {
+	for _, resolver := range srv.requestResolvers {
+		if c, ok := resolver.(AuthorizationRequestConsumer); ok {
+			if err := c.ConsumeAuthorizationRequest(r); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// withResponseMode records the response_mode and client_id of r on an error
+// redirect, so HandleError can encode it the way the client asked for.
+func withResponseMode(err error, r *requests.AuthorizationRequest) error {
+	var authErr *autherrors.AuthLibError
+	if errors.As(err, &authErr) && authErr.RedirectURI != "" && authErr.ResponseMode.IsEmpty() {
+		authErr.WithResponseMode(r.ResponseMode, r.ClientID)
+	}
+
+	return err
+}
+
 // TokenGrant returns the first registered grant that supports the requested
 // grant_type, or UnsupportedGrantTypeError if none match.
 func (srv *Server) TokenGrant(r *requests.TokenRequest) (TokenGrant, error) {
@@ -242,11 +306,85 @@ func (srv *Server) RegisterTokenGrant(grant any) {
 }
 
 // RegisterEndpoint registers an endpoint (e.g. token introspection) that can
-// be dispatched to via EndpointResponse.
+// be dispatched to via EndpointResponse. An endpoint that also implements
+// AuthorizationRequestResolver (e.g. pushed authorization requests) is
+// registered as a resolver too.
 func (srv *Server) RegisterEndpoint(endpoint any) {
 	if g, ok := endpoint.(Endpoint); ok {
 		srv.endpoints = append(srv.endpoints, g)
 	}
+
+	srv.RegisterAuthorizationRequestResolver(endpoint)
+}
+
+// RegisterAuthorizationRequestResolver registers resolver as an
+// AuthorizationRequestResolver if it implements the interface. Resolvers run
+// in registration order.
+func (srv *Server) RegisterAuthorizationRequestResolver(resolver any) {
+	if h, ok := resolver.(AuthorizationRequestResolver); ok {
+		srv.requestResolvers = append(srv.requestResolvers, h)
+	}
+}
+
+// RegisterResponseModeHandler registers handler as a ResponseModeHandler if it
+// implements the interface. Handlers are matched in registration order.
+func (srv *Server) RegisterResponseModeHandler(handler any) {
+	if h, ok := handler.(ResponseModeHandler); ok {
+		srv.responseModeHandlers = append(srv.responseModeHandlers, h)
+	}
+}
+
+// responseModeHandler returns the first registered ResponseModeHandler that
+// supports mode, or nil if none match.
+func (srv *Server) responseModeHandler(mode types.ResponseMode) ResponseModeHandler {
+	if mode.IsEmpty() {
+		return nil
+	}
+
+	for _, h := range srv.responseModeHandlers {
+		if h.CheckResponseMode(mode) {
+			return h
+		}
+	}
+
+	return nil
+}
+
+// Metadata returns the authorization server metadata (RFC 8414 §2) provided by
+// every registered grant, endpoint, resolver and response mode handler that
+// implements MetadataProvider, in registration order. A component registered
+// in several roles provides its metadata once per role; list values are not
+// repeated. Endpoint URLs are not included; they are set by the metadata
+// endpoint.
+func (srv *Server) Metadata() types.Metadata {
+	md := types.Metadata{}
+	provide := func(component any) {
+		if p, ok := component.(MetadataProvider); ok {
+			p.ProvideMetadata(md)
+		}
+	}
+
+	for _, g := range srv.authorizationGrants {
+		provide(g)
+	}
+
+	for _, g := range srv.tokenGrants {
+		provide(g)
+	}
+
+	for _, e := range srv.endpoints {
+		provide(e)
+	}
+
+	for _, r := range srv.requestResolvers {
+		provide(r)
+	}
+
+	for _, h := range srv.responseModeHandlers {
+		provide(h)
+	}
+
+	return md
 }
 
 // RegisterErrorHandler sets a custom error handler. When set, all errors are
@@ -257,7 +395,12 @@ func (srv *Server) RegisterErrorHandler(h ErrorHandler) {
 
 // HandleError converts err to an OAuth2 error response. If a custom
 // ErrorHandler is registered it takes full control. Otherwise:
-//   - If err carries a RedirectURI, the client is redirected with the error params.
+//   - If err carries a RedirectURI and a response_mode supported by a
+//     registered ResponseModeHandler, the handler writes the response.
+//   - If err carries a RedirectURI, the error params are returned to it using
+//     the query, fragment or form_post response mode of the request, falling
+//     back to the fragment for errors of token-issuing flows and to the query
+//     otherwise.
 //   - Otherwise a JSON error body is written with the appropriate HTTP status.
 //
 // Non-AuthLibError values (e.g. unexpected DB errors) are wrapped in a 500
@@ -270,7 +413,19 @@ func (srv *Server) HandleError(hr *http.Request, rw http.ResponseWriter, err err
 	authErr := autherrors.ToAuthLibError(err)
 
 	if authErr.RedirectURI != "" {
-		return utils.Redirect(rw, authErr.RedirectURI, authErr.Data())
+		if h := srv.responseModeHandler(authErr.ResponseMode); h != nil {
+			return h.WriteErrorResponse(rw, authErr)
+		}
+
+		mode := authErr.ResponseMode
+		if !mode.IsValid() || mode.IsJWT() {
+			mode = types.ResponseModeQuery
+			if authErr.Fragment {
+				mode = types.ResponseModeFragment
+			}
+		}
+
+		return utils.RedirectWithResponseMode(rw, mode, authErr.RedirectURI, authErr.Data())
 	}
 
 	status, header, data := authErr.Response()
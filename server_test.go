@@ -82,6 +82,38 @@ func (s *stubEndpoint) EndpointResponse(_ *http.Request, _ http.ResponseWriter)
 	return s.responseErr
 }
 
+type stubResolver struct {
+	resolved *requests.AuthorizationRequest
+	err      error
+}
+
+func (s *stubResolver) ResolveAuthorizationRequest(r *requests.AuthorizationRequest) (*requests.AuthorizationRequest, error) {
+	if s.err != nil || s.resolved == nil {
+		return r, s.err
+	}
+
+	return s.resolved, nil
+}
+
+// consumingResolver is an AuthorizationRequestResolver whose references are
+// one-time, like a pushed request_uri.
+type consumingResolver struct {
+	stubResolver
+	consumed int
+	err      error
+}
+
+func (s *consumingResolver) ConsumeAuthorizationRequest(_ *requests.AuthorizationRequest) error {
+	s.consumed++
+	return s.err
+}
+
+// resolvingEndpoint implements both Endpoint and AuthorizationRequestResolver.
+type resolvingEndpoint struct {
+	stubEndpoint
+	stubResolver
+}
+
 // allGrant implements AuthorizationGrant, ConsentGrant, and TokenGrant simultaneously.
 type allGrant struct{}
 
@@ -95,6 +127,38 @@ func (g *allGrant) CheckGrantType(_ types.GrantType) bool
 func (g *allGrant) ValidateTokenRequest(_ *requests.TokenRequest) error                 { return nil }
 func (g *allGrant) TokenResponse(_ *requests.TokenRequest, _ http.ResponseWriter) error { return nil }
 
+type stubResponseModeHandler struct {
+	mode   types.ResponseMode
+	called *autherrors.AuthLibError
+}
+
+func (s *stubResponseModeHandler) CheckResponseMode(mode types.ResponseMode) bool {
+	return mode == s.mode
+}
+
+func (s *stubResponseModeHandler) WriteErrorResponse(rw http.ResponseWriter, err *autherrors.AuthLibError) error {
+	s.called = err
+	rw.WriteHeader(http.StatusOK)
+	return nil
+}
+
+type stubMetadataTokenGrant struct {
+	stubTokenGrant
+}
+
+func (s *stubMetadataTokenGrant) ProvideMetadata(md types.Metadata) {
+	md.Add(types.MetadataGrantTypesSupported, s.grantType.String())
+}
+
+type stubMetadataEndpoint struct {
+	stubEndpoint
+	field string
+}
+
+func (s *stubMetadataEndpoint) ProvideMetadata(md types.Metadata) {
+	md.Set(s.field, true)
+}
+
 func newAuthorizeRequest(responseType string) *http.Request {
 	return httptest.NewRequest(http.MethodGet, "/authorize?response_type="+responseType, nil)
 }
@@ -176,6 +240,96 @@ func TestServer_RegisterEndpoint(t *testing.T) {
 		srv.RegisterEndpoint(struct{}{})
 		assert.Empty(t, srv.endpoints)
 	})
+
+	t.Run("registers_resolver_endpoint", func(t *testing.T) {
+		srv := NewServer()
+		srv.RegisterEndpoint(&resolvingEndpoint{})
+		assert.Len(t, srv.endpoints, 1)
+		assert.Len(t, srv.requestResolvers, 1)
+	})
+}
+
+func TestServer_RegisterAuthorizationRequestResolver(t *testing.T) {
+	t.Run("registers_when_interface_satisfied", func(t *testing.T) {
+		srv := NewServer()
+		srv.RegisterAuthorizationRequestResolver(&stubResolver{})
+		assert.Len(t, srv.requestResolvers, 1)
+		assert.Empty(t, srv.endpoints)
+	})
+
+	t.Run("ignores_when_interface_not_satisfied", func(t *testing.T) {
+		srv := NewServer()
+		srv.RegisterAuthorizationRequestResolver(struct{}{})
+		assert.Empty(t, srv.requestResolvers)
+	})
+}
+
+func TestServer_ValidateAuthorizationRequest(t *testing.T) {
+	t.Run("dispatches_resolved_request", func(t *testing.T) {
+		resolved := &requests.AuthorizationRequest{ResponseType: types.ResponseTypeCode, ClientID: "client-1"}
+		srv := NewServer()
+		srv.RegisterAuthorizationGrant(&stubAuthorizationGrant{responseType: types.ResponseTypeCode})
+		srv.RegisterAuthorizationRequestResolver(&stubResolver{resolved: resolved})
+
+		hr := httptest.NewRequest(http.MethodGet, "/authorize?client_id=client-1&request_uri=urn:ietf:params:oauth:request_uri:abc", nil)
+		grant, r, err := srv.ValidateAuthorizationRequest(hr, nil)
+		require.NoError(t, err)
+		assert.NotNil(t, grant)
+		assert.Same(t, resolved, r)
+	})
+
+	t.Run("error_when_resolver_fails", func(t *testing.T) {
+		srv := NewServer()
+		srv.RegisterAuthorizationGrant(&stubAuthorizationGrant{responseType: types.ResponseTypeCode})
+		srv.RegisterAuthorizationRequestResolver(&stubResolver{err: autherrors.InvalidRequestURIError()})
+
+		grant, r, err := srv.ValidateAuthorizationRequest(newAuthorizeRequest("code"), nil)
+		assert.Equal(t, autherrors.ErrInvalidRequestURI, autherrors.ToAuthLibError(err).Code)
+		assert.Nil(t, grant)
+		assert.Nil(t, r)
+	})
+}
+
+func TestServer_RegisterResponseModeHandler(t *testing.T) {
+	t.Run("registers_when_interface_satisfied", func(t *testing.T) {
+		srv := NewServer()
+		srv.RegisterResponseModeHandler(&stubResponseModeHandler{})
+		assert.Len(t, srv.responseModeHandlers, 1)
+	})
+
+	t.Run("ignores_when_interface_not_satisfied", func(t *testing.T) {
+		srv := NewServer()
+		srv.RegisterResponseModeHandler(struct{}{})
+		assert.Empty(t, srv.responseModeHandlers)
+	})
+}
+
+func TestServer_ValidateAuthorizationRequest_ResponseMode(t *testing.T) {
+	t.Run("records_response_mode_on_error_redirect", func(t *testing.T) {
+		srv := NewServer()
+		srv.RegisterAuthorizationGrant(&stubAuthorizationGrant{
+			responseType: types.ResponseTypeCode,
+			validateErr:  autherrors.InvalidScopeError().WithRedirectURI("https://example.com/cb"),
+		})
+
+		hr := httptest.NewRequest(http.MethodGet, "/authorize?response_type=code&client_id=client-1&response_mode=jwt", nil)
+		_, _, err := srv.ValidateAuthorizationRequest(hr, nil)
+		authErr := autherrors.ToAuthLibError(err)
+		assert.Equal(t, types.ResponseModeJWT, authErr.ResponseMode)
+		assert.Equal(t, "client-1", authErr.ClientID)
+	})
+
+	t.Run("ignores_error_without_redirect", func(t *testing.T) {
+		srv := NewServer()
+		srv.RegisterAuthorizationGrant(&stubAuthorizationGrant{
+			responseType: types.ResponseTypeCode,
+			validateErr:  autherrors.InvalidRequestError(),
+		})
+
+		hr := httptest.NewRequest(http.MethodGet, "/authorize?response_type=code&client_id=client-1&response_mode=jwt", nil)
+		_, _, err := srv.ValidateAuthorizationRequest(hr, nil)
+		assert.True(t, autherrors.ToAuthLibError(err).ResponseMode.IsEmpty())
+	})
 }
 
 func TestServer_RegisterGrant(t *testing.T) {
@@ -196,6 +350,23 @@ func TestServer_RegisterGrant(t *testing.T) {
 	})
 }
 
+func TestServer_Metadata(t *testing.T) {
+	srv := NewServer()
+	srv.RegisterGrant(&stubMetadataTokenGrant{stubTokenGrant{grantType: types.GrantTypeClientCredentials}})
+	srv.RegisterGrant(&stubMetadataTokenGrant{stubTokenGrant{grantType: types.GrantTypeRefreshToken}})
+	srv.RegisterGrant(&stubTokenGrant{grantType: types.GrantTypeROPC})
+	srv.RegisterEndpoint(&stubMetadataEndpoint{stubEndpoint{name: "par"}, types.MetadataRequirePushedAuthorizationRequests})
+
+	md := srv.Metadata()
+	assert.Equal(t, types.Metadata{
+		types.MetadataGrantTypesSupported:                []string{"client_credentials", "refresh_token"},
+		types.MetadataRequirePushedAuthorizationRequests: true,
+	}, md)
+
+	md.Set(types.MetadataIssuer, "https://as.example.com")
+	assert.NotContains(t, srv.Metadata(), types.MetadataIssuer)
+}
+
 func TestServer_AuthorizationGrant(t *testing.T) {
 	t.Run("returns_matching_grant", func(t *testing.T) {
 		srv := NewServer()
@@ -340,6 +511,32 @@ func TestServer_CreateAuthorizationResponse(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, rw.Code)
 	})
 
+	t.Run("consumes_request", func(t *testing.T) {
+		resolver := &consumingResolver{}
+		srv := NewServer()
+		srv.RegisterAuthorizationGrant(&stubAuthorizationGrant{responseType: types.ResponseTypeCode})
+		srv.RegisterAuthorizationRequestResolver(resolver)
+
+		rw := httptest.NewRecorder()
+		assert.NoError(t, srv.CreateAuthorizationResponse(newAuthorizeRequest("code"), rw, nil))
+		assert.Equal(t, http.StatusOK, rw.Code)
+		assert.Equal(t, 1, resolver.consumed)
+	})
+
+	t.Run("error_when_consume_fails", func(t *testing.T) {
+		srv := NewServer()
+		srv.RegisterAuthorizationGrant(&stubAuthorizationGrant{
+			responseType: types.ResponseTypeCode,
+			responseErr:  errors.New("must not be called"),
+		})
+		srv.RegisterAuthorizationRequestResolver(&consumingResolver{err: autherrors.InvalidRequestURIError()})
+
+		rw := httptest.NewRecorder()
+		assert.NoError(t, srv.CreateAuthorizationResponse(newAuthorizeRequest("code"), rw, nil))
+		assert.Equal(t, http.StatusBadRequest, rw.Code)
+		assert.Contains(t, rw.Body.String(), "invalid_request_uri")
+	})
+
 	t.Run("error_when_response_fails", func(t *testing.T) {
 		srv := NewServer()
 		srv.RegisterAuthorizationGrant(&stubAuthorizationGrant{
@@ -387,6 +584,22 @@ func TestServer_CreateConsentResponse(t *testing.T) {
 		assert.Equal(t, http.StatusForbidden, rw.Code)
 	})
 
+	t.Run("consumes_request", func(t *testing.T) {
+		resolver := &consumingResolver{}
+		srv := NewServer()
+		srv.RegisterConsentGrant(&stubConsentGrant{responseType: types.ResponseTypeCode})
+		srv.RegisterAuthorizationRequestResolver(resolver)
+
+		rw := httptest.NewRecorder()
+		assert.NoError(t, srv.CreateConsentResponse(newAuthorizeRequest("code"), rw, nil))
+		assert.Equal(t, http.StatusOK, rw.Code)
+		assert.Equal(t, 1, resolver.consumed)
+
+		_, _, err := srv.ValidateConsentRequest(newAuthorizeRequest("code"), nil)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, resolver.consumed, "validation alone must not consume the request")
+	})
+
 	t.Run("error_when_response_fails", func(t *testing.T) {
 		srv := NewServer()
 		srv.RegisterConsentGrant(&stubConsentGrant{
@@ -558,6 +771,92 @@ func TestServer_HandleError(t *testing.T) {
 		assert.Contains(t, rw.Header().Get("Location"), "invalid_request")
 	})
 
+	t.Run("redirects_with_fragment_when_error_is_fragment", func(t *testing.T) {
+		srv := NewServer()
+		authErr := autherrors.AccessDeniedError().
+			WithRedirectURI("https://example.com/cb").
+			WithState("abc").
+			WithFragment()
+
+		hr := httptest.NewRequest(http.MethodGet, "/authorize", nil)
+		rw := httptest.NewRecorder()
+
+		err := srv.HandleError(hr, rw, authErr)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusFound, rw.Code)
+		assert.Contains(t, rw.Header().Get("Location"), "https://example.com/cb#")
+		assert.Contains(t, rw.Header().Get("Location"), "access_denied")
+	})
+
+	t.Run("posts_form_when_response_mode_is_form_post", func(t *testing.T) {
+		srv := NewServer()
+		authErr := autherrors.AccessDeniedError().
+			WithRedirectURI("https://example.com/cb").
+			WithState("abc").
+			WithFragment().
+			WithResponseMode(types.ResponseModeFormPost, "client-1")
+
+		hr := httptest.NewRequest(http.MethodGet, "/authorize", nil)
+		rw := httptest.NewRecorder()
+
+		err := srv.HandleError(hr, rw, authErr)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rw.Code)
+		assert.Empty(t, rw.Header().Get("Location"))
+		assert.Contains(t, rw.Header().Get("Content-Security-Policy"), "form-action https://example.com")
+		assert.Contains(t, rw.Body.String(), `action="https://example.com/cb"`)
+		assert.Contains(t, rw.Body.String(), `name="error" value="access_denied"`)
+	})
+
+	t.Run("redirects_with_fragment_when_response_mode_is_fragment", func(t *testing.T) {
+		srv := NewServer()
+		authErr := autherrors.InvalidScopeError().
+			WithRedirectURI("https://example.com/cb").
+			WithResponseMode(types.ResponseModeFragment, "client-1")
+
+		hr := httptest.NewRequest(http.MethodGet, "/authorize", nil)
+		rw := httptest.NewRecorder()
+
+		err := srv.HandleError(hr, rw, authErr)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusFound, rw.Code)
+		assert.Contains(t, rw.Header().Get("Location"), "https://example.com/cb#error=invalid_scope")
+	})
+
+	t.Run("delegates_to_response_mode_handler", func(t *testing.T) {
+		handler := &stubResponseModeHandler{mode: types.ResponseModeJWT}
+		srv := NewServer()
+		srv.RegisterResponseModeHandler(handler)
+		authErr := autherrors.AccessDeniedError().
+			WithRedirectURI("https://example.com/cb").
+			WithResponseMode(types.ResponseModeJWT, "client-1")
+
+		hr := httptest.NewRequest(http.MethodGet, "/authorize", nil)
+		rw := httptest.NewRecorder()
+
+		err := srv.HandleError(hr, rw, authErr)
+		assert.NoError(t, err)
+		assert.Same(t, authErr, handler.called)
+		assert.Empty(t, rw.Header().Get("Location"))
+	})
+
+	t.Run("redirects_when_no_handler_supports_response_mode", func(t *testing.T) {
+		handler := &stubResponseModeHandler{mode: types.ResponseModeJWT}
+		srv := NewServer()
+		srv.RegisterResponseModeHandler(handler)
+		authErr := autherrors.AccessDeniedError().
+			WithRedirectURI("https://example.com/cb").
+			WithResponseMode(types.ResponseModeQuery, "client-1")
+
+		hr := httptest.NewRequest(http.MethodGet, "/authorize", nil)
+		rw := httptest.NewRecorder()
+
+		err := srv.HandleError(hr, rw, authErr)
+		assert.NoError(t, err)
+		assert.Nil(t, handler.called)
+		assert.Equal(t, http.StatusFound, rw.Code)
+	})
+
 	t.Run("writes_json_for_authliberror_without_redirect", func(t *testing.T) {
 		srv := NewServer()
 		authErr := autherrors.InvalidClientError()
@@ -3,6 +3,7 @@ package authlib
 import (
 	"net/http"
 
+	autherrors "github.com/tniah/authlib/errors"
 	"github.com/tniah/authlib/requests"
 	"github.com/tniah/authlib/types"
 )
@@ -55,6 +56,49 @@ type Endpoint interface {
 	EndpointResponse(r *http.Request, rw http.ResponseWriter) error
 }
 
+// AuthorizationRequestResolver expands an authorization request that passes
+// its parameters by reference, such as the request_uri issued by the pushed
+// authorization request endpoint (RFC 9126 §4). Resolvers run before the
+// grant is selected, so the resolved response_type decides the dispatch.
+type AuthorizationRequestResolver interface {
+	// ResolveAuthorizationRequest returns the request to process in place of
+	// r. It returns r unchanged when r does not reference parameters handled
+	// by this resolver.
+	ResolveAuthorizationRequest(r *requests.AuthorizationRequest) (*requests.AuthorizationRequest, error)
+}
+
+// AuthorizationRequestConsumer is implemented by resolvers whose references
+// can be used only once, such as the request_uri issued by the pushed
+// authorization request endpoint (RFC 9126 §4). The server consumes the
+// request just before the grant issues the authorization response.
+type AuthorizationRequestConsumer interface {
+	// ConsumeAuthorizationRequest marks the reference r was resolved from as
+	// used. It returns an error when it was already used.
+	ConsumeAuthorizationRequest(r *requests.AuthorizationRequest) error
+}
+
+// ResponseModeHandler writes authorization error redirects for the
+// response_mode values it supports, such as the JWT-secured response modes
+// (JARM §2.4). Register with Server.RegisterResponseModeHandler; grant flows
+// take the same handler as an extension for successful responses.
+type ResponseModeHandler interface {
+	// CheckResponseMode reports whether this handler encodes the given
+	// response_mode.
+	CheckResponseMode(mode types.ResponseMode) bool
+	// WriteErrorResponse sends err to its RedirectURI.
+	WriteErrorResponse(rw http.ResponseWriter, err *autherrors.AuthLibError) error
+}
+
+// MetadataProvider describes the capabilities of a grant, endpoint, resolver
+// or response mode handler in the authorization server metadata (RFC 8414 §2).
+// Server.Metadata collects the metadata of every registered component that
+// implements it.
+type MetadataProvider interface {
+	// ProvideMetadata adds the supported values of this component to md, such
+	// as its grant_type or the client authentication methods it accepts.
+	ProvideMetadata(md types.Metadata)
+}
+
 // ErrorHandler is an optional custom function that takes over all error
 // responses when registered via Server.RegisterErrorHandler. It must write
 // its own HTTP response and return any secondary error.
@@ -25,6 +25,22 @@ func (m ClientAuthMethod) IsNone() bool {
 	return m.Equal(ClientNoneAuthentication)
 }
 
+func (m ClientAuthMethod) IsSecretJWT() bool {
+	return m.Equal(ClientSecretJWTAuthentication)
+}
+
+func (m ClientAuthMethod) IsPrivateKeyJWT() bool {
+	return m.Equal(ClientPrivateKeyJWTAuthentication)
+}
+
+func (m ClientAuthMethod) IsTLS() bool {
+	return m.Equal(ClientTLSAuthentication)
+}
+
+func (m ClientAuthMethod) IsSelfSignedTLS() bool {
+	return m.Equal(ClientSelfSignedTLSAuthentication)
+}
+
 func (m ClientAuthMethod) IsEmpty() bool {
 	return m.Equal("")
 }
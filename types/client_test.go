@@ -17,6 +17,12 @@ func TestClientAuthMethod(t *testing.T) {
 	assert.True(t, ClientBasicAuthentication.IsBasic())
 	assert.True(t, ClientPostAuthentication.IsPOST())
 	assert.True(t, ClientNoneAuthentication.IsNone())
+	assert.True(t, ClientSecretJWTAuthentication.IsSecretJWT())
+	assert.True(t, ClientPrivateKeyJWTAuthentication.IsPrivateKeyJWT())
+	assert.False(t, ClientSecretJWTAuthentication.IsPrivateKeyJWT())
+	assert.True(t, ClientTLSAuthentication.IsTLS())
+	assert.True(t, ClientSelfSignedTLSAuthentication.IsSelfSignedTLS())
+	assert.False(t, ClientTLSAuthentication.IsSelfSignedTLS())
 	assert.True(t, NewClientAuthMethod("").IsEmpty())
 	assert.False(t, m.IsEmpty())
 }
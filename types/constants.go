@@ -3,6 +3,12 @@ package types
 const (
 	// ScopeOpenID is the scope value required for OpenID Connect requests.
 	ScopeOpenID Scope = "openid"
+	// ScopeProfile, ScopeEmail, ScopeAddress and ScopePhone request access to
+	// the standard claims about the end-user (OIDC Core §5.4).
+	ScopeProfile Scope = "profile"
+	ScopeEmail   Scope = "email"
+	ScopeAddress Scope = "address"
+	ScopePhone   Scope = "phone"
 
 	// GrantTypeAuthorizationCode is the authorization code grant (RFC 6749 §4.1).
 	GrantTypeAuthorizationCode GrantType = "authorization_code"
@@ -12,11 +18,44 @@ const (
 	GrantTypeROPC GrantType = "password"
 	// GrantTypeRefreshToken is the refresh token grant (RFC 6749 §6).
 	GrantTypeRefreshToken GrantType = "refresh_token"
+	// GrantTypeImplicit is the implicit grant (RFC 6749 §4.2). It is never sent
+	// as a grant_type parameter; it tags token requests built by the implicit flow.
+	GrantTypeImplicit GrantType = "implicit"
+	// GrantTypeDeviceCode is the device authorization grant (RFC 8628 §3.4).
+	GrantTypeDeviceCode GrantType = "urn:ietf:params:oauth:grant-type:device_code"
+	// GrantTypeTokenExchange is the token exchange grant (RFC 8693 §2.1).
+	GrantTypeTokenExchange GrantType = "urn:ietf:params:oauth:grant-type:token-exchange"
+	// GrantTypeJWTBearer is the JWT bearer authorization grant (RFC 7523 §2.1).
+	GrantTypeJWTBearer GrantType = "urn:ietf:params:oauth:grant-type:jwt-bearer"
 
 	// ResponseTypeCode is the authorization code response type (RFC 6749 §3.1.1).
 	ResponseTypeCode ResponseType = "code"
 	// ResponseTypeToken is the implicit grant response type (RFC 6749 §3.1.1).
 	ResponseTypeToken ResponseType = "token"
+	// ResponseTypeIDToken is the OpenID Connect ID Token response type (OIDC Core §3.2.2.1).
+	ResponseTypeIDToken ResponseType = "id_token"
+
+	// ResponseModeQuery returns the authorization response parameters in the
+	// query string (OAuth 2.0 Multiple Response Type Encoding Practices §2.1).
+	ResponseModeQuery ResponseMode = "query"
+	// ResponseModeFragment returns the authorization response parameters in
+	// the fragment (OAuth 2.0 Multiple Response Type Encoding Practices §2.1).
+	ResponseModeFragment ResponseMode = "fragment"
+	// ResponseModeFormPost returns the authorization response parameters in an
+	// auto-submitted HTML form (OAuth 2.0 Form Post Response Mode §2).
+	ResponseModeFormPost ResponseMode = "form_post"
+	// ResponseModeJWT returns the authorization response as a JWT, in the
+	// default encoding of the response type (JARM §2.3.4).
+	ResponseModeJWT ResponseMode = "jwt"
+	// ResponseModeQueryJWT returns the authorization response as a JWT in the
+	// query string (JARM §2.3.1).
+	ResponseModeQueryJWT ResponseMode = "query.jwt"
+	// ResponseModeFragmentJWT returns the authorization response as a JWT in
+	// the fragment (JARM §2.3.2).
+	ResponseModeFragmentJWT ResponseMode = "fragment.jwt"
+	// ResponseModeFormPostJWT returns the authorization response as a JWT in an
+	// auto-submitted HTML form (JARM §2.3.3).
+	ResponseModeFormPostJWT ResponseMode = "form_post.jwt"
 
 	// DisplayPage requests a full-page authentication UI.
 	DisplayPage Display = "page"
@@ -46,15 +85,51 @@ const (
 	// TokenTypeHintRefreshToken hints that the submitted token is a refresh token.
 	TokenTypeHintRefreshToken TokenTypeHint = "refresh_token"
 
+	// TokenTypeIdentifierAccessToken identifies an OAuth 2.0 access token (RFC 8693 §3).
+	TokenTypeIdentifierAccessToken TokenTypeIdentifier = "urn:ietf:params:oauth:token-type:access_token"
+	// TokenTypeIdentifierRefreshToken identifies an OAuth 2.0 refresh token (RFC 8693 §3).
+	TokenTypeIdentifierRefreshToken TokenTypeIdentifier = "urn:ietf:params:oauth:token-type:refresh_token"
+	// TokenTypeIdentifierIDToken identifies an OpenID Connect ID Token (RFC 8693 §3).
+	TokenTypeIdentifierIDToken TokenTypeIdentifier = "urn:ietf:params:oauth:token-type:id_token"
+	// TokenTypeIdentifierSAML1 identifies a base64url-encoded SAML 1.1 assertion (RFC 8693 §3).
+	TokenTypeIdentifierSAML1 TokenTypeIdentifier = "urn:ietf:params:oauth:token-type:saml1"
+	// TokenTypeIdentifierSAML2 identifies a base64url-encoded SAML 2.0 assertion (RFC 8693 §3).
+	TokenTypeIdentifierSAML2 TokenTypeIdentifier = "urn:ietf:params:oauth:token-type:saml2"
+	// TokenTypeIdentifierJWT identifies a JWT (RFC 8693 §3, RFC 7519 §9).
+	TokenTypeIdentifierJWT TokenTypeIdentifier = "urn:ietf:params:oauth:token-type:jwt"
+
+	// DeviceCodeStatusPending marks a device authorization request the user has
+	// not acted on yet.
+	DeviceCodeStatusPending DeviceCodeStatus = "pending"
+	// DeviceCodeStatusApproved marks a device authorization request the user approved.
+	DeviceCodeStatusApproved DeviceCodeStatus = "approved"
+	// DeviceCodeStatusDenied marks a device authorization request the user denied.
+	DeviceCodeStatusDenied DeviceCodeStatus = "denied"
+
 	// ClientBasicAuthentication is the client_secret_basic authentication method (RFC 6749 §2.3.1).
 	ClientBasicAuthentication ClientAuthMethod = "client_secret_basic"
 	// ClientPostAuthentication is the client_secret_post authentication method.
 	ClientPostAuthentication ClientAuthMethod = "client_secret_post"
 	// ClientNoneAuthentication is the "none" authentication method used by public clients.
 	ClientNoneAuthentication ClientAuthMethod = "none"
+	// ClientSecretJWTAuthentication is the client_secret_jwt authentication
+	// method: a JWT signed with HMAC using the client secret (OIDC Core §9, RFC 7523 §2.2).
+	ClientSecretJWTAuthentication ClientAuthMethod = "client_secret_jwt"
+	// ClientPrivateKeyJWTAuthentication is the private_key_jwt authentication
+	// method: a JWT signed with a key from the client's JWK Set (OIDC Core §9, RFC 7523 §2.2).
+	ClientPrivateKeyJWTAuthentication ClientAuthMethod = "private_key_jwt"
+	// ClientTLSAuthentication is the PKI mutual-TLS authentication method
+	// (RFC 8705 §2.1).
+	ClientTLSAuthentication ClientAuthMethod = "tls_client_auth"
+	// ClientSelfSignedTLSAuthentication is the self-signed certificate
+	// mutual-TLS authentication method (RFC 8705 §2.2).
+	ClientSelfSignedTLSAuthentication ClientAuthMethod = "self_signed_tls_client_auth"
 
 	// ContentTypeJSON is the application/json content type with UTF-8 charset.
 	ContentTypeJSON ContentType = "application/json;charset=UTF-8"
 	// ContentTypeXWWWFormUrlencoded is the application/x-www-form-urlencoded content type.
 	ContentTypeXWWWFormUrlencoded ContentType = "application/x-www-form-urlencoded"
+	// ContentTypeJWT is the application/jwt content type of signed or
+	// encrypted JWT responses (RFC 7519 §10.3.1).
+	ContentTypeJWT ContentType = "application/jwt"
 )
@@ -0,0 +1,26 @@
+package types
+
+// DeviceCodeStatus is the state of a device authorization request
+// (RFC 8628 §3.3). A request starts pending and is moved to approved or
+// denied by the end-user on the verification page.
+type DeviceCodeStatus string
+
+func NewDeviceCodeStatus(s string) DeviceCodeStatus {
+	return DeviceCodeStatus(s)
+}
+
+func (s DeviceCodeStatus) IsPending() bool {
+	return s == DeviceCodeStatusPending
+}
+
+func (s DeviceCodeStatus) IsApproved() bool {
+	return s == DeviceCodeStatusApproved
+}
+
+func (s DeviceCodeStatus) IsDenied() bool {
+	return s == DeviceCodeStatusDenied
+}
+
+func (s DeviceCodeStatus) String() string {
+	return string(s)
+}
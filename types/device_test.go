@@ -0,0 +1,20 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeviceCodeStatus(t *testing.T) {
+	s := NewDeviceCodeStatus("pending")
+	assert.IsType(t, DeviceCodeStatus(""), s)
+	assert.Equal(t, "pending", s.String())
+	assert.True(t, s.IsPending())
+	assert.False(t, s.IsApproved())
+	assert.False(t, s.IsDenied())
+
+	assert.True(t, DeviceCodeStatusApproved.IsApproved())
+	assert.True(t, DeviceCodeStatusDenied.IsDenied())
+	assert.False(t, NewDeviceCodeStatus("").IsPending())
+}
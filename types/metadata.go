@@ -0,0 +1,113 @@
+package types
+
+import "sort"
+
+// Authorization server metadata field names (RFC 8414 §2), including the
+// fields registered by later specifications.
+const (
+	MetadataIssuer                                     = "issuer"
+	MetadataAuthorizationEndpoint                      = "authorization_endpoint"
+	MetadataTokenEndpoint                              = "token_endpoint"
+	MetadataJWKSURI                                    = "jwks_uri"
+	MetadataRegistrationEndpoint                       = "registration_endpoint"
+	MetadataScopesSupported                            = "scopes_supported"
+	MetadataResponseTypesSupported                     = "response_types_supported"
+	MetadataResponseModesSupported                     = "response_modes_supported"
+	MetadataGrantTypesSupported                        = "grant_types_supported"
+	MetadataTokenEndpointAuthMethodsSupported          = "token_endpoint_auth_methods_supported"
+	MetadataTokenEndpointAuthSigningAlgValuesSupported = "token_endpoint_auth_signing_alg_values_supported"
+	MetadataServiceDocumentation                       = "service_documentation"
+	MetadataUILocalesSupported                         = "ui_locales_supported"
+	MetadataOpPolicyURI                                = "op_policy_uri"
+	MetadataOpTosURI                                   = "op_tos_uri"
+	MetadataRevocationEndpoint                         = "revocation_endpoint"
+	MetadataRevocationEndpointAuthMethodsSupported     = "revocation_endpoint_auth_methods_supported"
+	MetadataIntrospectionEndpoint                      = "introspection_endpoint"
+	MetadataIntrospectionEndpointAuthMethodsSupported  = "introspection_endpoint_auth_methods_supported"
+	MetadataCodeChallengeMethodsSupported              = "code_challenge_methods_supported"
+	MetadataSignedMetadata                             = "signed_metadata"
+
+	// MetadataDeviceAuthorizationEndpoint is defined by RFC 8628 §4.
+	MetadataDeviceAuthorizationEndpoint = "device_authorization_endpoint"
+	// MetadataPushedAuthorizationRequestEndpoint and
+	// MetadataRequirePushedAuthorizationRequests are defined by RFC 9126 §5.
+	MetadataPushedAuthorizationRequestEndpoint = "pushed_authorization_request_endpoint"
+	MetadataRequirePushedAuthorizationRequests = "require_pushed_authorization_requests"
+	// MetadataRequireSignedRequestObject is defined by RFC 9101 §10.5.
+	MetadataRequireSignedRequestObject = "require_signed_request_object"
+	// MetadataRequestParameterSupported, MetadataRequestURIParameterSupported
+	// and MetadataRequestObjectSigningAlgValuesSupported are shared with
+	// OpenID Connect Discovery §3.
+	MetadataRequestParameterSupported              = "request_parameter_supported"
+	MetadataRequestURIParameterSupported           = "request_uri_parameter_supported"
+	MetadataRequestObjectSigningAlgValuesSupported = "request_object_signing_alg_values_supported"
+	// MetadataAuthorizationSigningAlgValuesSupported is defined by JARM §3.
+	MetadataAuthorizationSigningAlgValuesSupported = "authorization_signing_alg_values_supported"
+	// MetadataTLSClientCertificateBoundAccessTokens is defined by RFC 8705 §3.3.
+	MetadataTLSClientCertificateBoundAccessTokens = "tls_client_certificate_bound_access_tokens"
+	// MetadataDPoPSigningAlgValuesSupported is defined by RFC 9449 §5.1.
+	MetadataDPoPSigningAlgValuesSupported = "dpop_signing_alg_values_supported"
+
+	// OpenID Provider metadata fields (OpenID Connect Discovery 1.0 §3, OpenID
+	// Connect RP-Initiated Logout 1.0 §2.1).
+	MetadataUserInfoEndpoint                  = "userinfo_endpoint"
+	MetadataEndSessionEndpoint                = "end_session_endpoint"
+	MetadataClaimsSupported                   = "claims_supported"
+	MetadataSubjectTypesSupported             = "subject_types_supported"
+	MetadataIDTokenSigningAlgValuesSupported  = "id_token_signing_alg_values_supported"
+	MetadataUserInfoSigningAlgValuesSupported = "userinfo_signing_alg_values_supported"
+	MetadataClaimsParameterSupported          = "claims_parameter_supported"
+	MetadataACRValuesSupported                = "acr_values_supported"
+)
+
+// Metadata is a set of authorization server metadata fields (RFC 8414 §2).
+// Grants, endpoints and extensions describe their capabilities by adding
+// values to it.
+type Metadata map[string]interface{}
+
+// Set stores value under field, replacing any previous value.
+func (m Metadata) Set(field string, value interface{}) {
+	m[field] = value
+}
+
+// Add appends values to the string list stored under field, skipping values
+// already present. A field holding anything other than a string list is
+// replaced. Nothing is stored when values is empty.
+func (m Metadata) Add(field string, values ...string) {
+	if len(values) == 0 {
+		return
+	}
+
+	list, _ := m[field].([]string)
+	for _, v := range values {
+		if !contains(list, v) {
+			list = append(list, v)
+		}
+	}
+
+	m[field] = list
+}
+
+// AddClientAuthMethods adds the enabled methods to the string list stored
+// under field, in lexical order so the output is stable.
+func (m Metadata) AddClientAuthMethods(field string, methods map[ClientAuthMethod]bool) {
+	values := make([]string, 0, len(methods))
+	for method, enabled := range methods {
+		if enabled {
+			values = append(values, method.String())
+		}
+	}
+
+	sort.Strings(values)
+	m.Add(field, values...)
+}
+
+func contains(list []string, v string) bool {
+	for i := range list {
+		if list[i] == v {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,45 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetadata_Set(t *testing.T) {
+	md := Metadata{}
+	md.Set(MetadataIssuer, "https://as.example.com")
+	md.Set(MetadataIssuer, "https://other.example.com")
+	assert.Equal(t, "https://other.example.com", md[MetadataIssuer])
+}
+
+func TestMetadata_Add(t *testing.T) {
+	md := Metadata{}
+	md.Add(MetadataGrantTypesSupported, "authorization_code")
+	md.Add(MetadataGrantTypesSupported, "refresh_token", "authorization_code")
+	assert.Equal(t, []string{"authorization_code", "refresh_token"}, md[MetadataGrantTypesSupported])
+
+	// replaces a value that is not a string list
+	md.Set(MetadataScopesSupported, true)
+	md.Add(MetadataScopesSupported, "openid")
+	assert.Equal(t, []string{"openid"}, md[MetadataScopesSupported])
+
+	// nothing to add
+	md.Add(MetadataUILocalesSupported)
+	assert.NotContains(t, md, MetadataUILocalesSupported)
+}
+
+func TestMetadata_AddClientAuthMethods(t *testing.T) {
+	md := Metadata{}
+	md.AddClientAuthMethods(MetadataTokenEndpointAuthMethodsSupported, map[ClientAuthMethod]bool{
+		ClientPostAuthentication:  true,
+		ClientBasicAuthentication: true,
+		ClientNoneAuthentication:  false,
+	})
+	md.AddClientAuthMethods(MetadataTokenEndpointAuthMethodsSupported, map[ClientAuthMethod]bool{
+		ClientBasicAuthentication:         true,
+		ClientPrivateKeyJWTAuthentication: true,
+	})
+
+	assert.Equal(t, []string{"client_secret_basic", "client_secret_post", "private_key_jwt"}, md[MetadataTokenEndpointAuthMethodsSupported])
+}
@@ -1,5 +1,7 @@
 package types
 
+import "strings"
+
 // GrantType identifies the OAuth 2.0 grant being used at the token endpoint
 // (RFC 6749 §1.3).
 type GrantType string
@@ -32,6 +34,22 @@ func (g GrantType) IsRefreshToken() bool {
 	return g.Equal(GrantTypeRefreshToken)
 }
 
+func (g GrantType) IsImplicit() bool {
+	return g.Equal(GrantTypeImplicit)
+}
+
+func (g GrantType) IsDeviceCode() bool {
+	return g.Equal(GrantTypeDeviceCode)
+}
+
+func (g GrantType) IsTokenExchange() bool {
+	return g.Equal(GrantTypeTokenExchange)
+}
+
+func (g GrantType) IsJWTBearer() bool {
+	return g.Equal(GrantTypeJWTBearer)
+}
+
 func (g GrantType) String() string {
 	return string(g)
 }
@@ -80,8 +98,54 @@ func (t ResponseType) IsToken() bool {
 	return t == ResponseTypeToken
 }
 
+// Equal reports whether t and other hold the same set of space-delimited
+// values. Order is not significant (OAuth 2.0 Multiple Response Type Encoding
+// Practices §5), so "code id_token" equals "id_token code".
 func (t ResponseType) Equal(other ResponseType) bool {
-	return t == other
+	if t == other {
+		return true
+	}
+
+	a, b := t.Values(), other.Values()
+	if len(a) != len(b) {
+		return false
+	}
+
+	for _, v := range a {
+		if !b.Contains(v) {
+			return false
+		}
+	}
+
+	for _, v := range b {
+		if !a.Contains(v) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Has reports whether v is one of the space-delimited values of t.
+func (t ResponseType) Has(v ResponseType) bool {
+	return t.Values().Contains(v)
+}
+
+// Values splits a multi-value response_type into its individual values.
+func (t ResponseType) Values() ResponseTypes {
+	return NewResponseTypes(strings.Fields(t.String()))
+}
+
+// DefaultResponseMode returns the response mode used when response_mode is
+// omitted: fragment for response types that issue a token or an ID Token from
+// the authorization endpoint, query otherwise (OAuth 2.0 Multiple Response
+// Type Encoding Practices §5).
+func (t ResponseType) DefaultResponseMode() ResponseMode {
+	if t.Has(ResponseTypeToken) || t.Has(ResponseTypeIDToken) {
+		return ResponseModeFragment
+	}
+
+	return ResponseModeQuery
 }
 
 func (t ResponseType) IsEmpty() bool {
@@ -107,6 +171,16 @@ func NewResponseTypes(typ []string) ResponseTypes {
 	return ret
 }
 
+func (r ResponseTypes) Contains(expected ResponseType) bool {
+	for _, t := range r {
+		if t == expected {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (r ResponseTypes) String() []string {
 	ret := make([]string, len(r))
 	for i, t := range r {
@@ -20,9 +20,17 @@ func TestGrantType(t *testing.T) {
 	assert.True(t, GrantTypeClientCredentials.IsClientCredentials())
 	assert.True(t, GrantTypeROPC.IsROPC())
 	assert.True(t, GrantTypeRefreshToken.IsRefreshToken())
+	assert.True(t, GrantTypeImplicit.IsImplicit())
+	assert.True(t, GrantTypeDeviceCode.IsDeviceCode())
+	assert.True(t, GrantTypeTokenExchange.IsTokenExchange())
+	assert.True(t, GrantTypeJWTBearer.IsJWTBearer())
 
 	assert.False(t, GrantTypeAuthorizationCode.IsROPC())
 	assert.False(t, GrantTypeROPC.IsRefreshToken())
+	assert.False(t, GrantTypeAuthorizationCode.IsImplicit())
+	assert.False(t, GrantTypeAuthorizationCode.IsDeviceCode())
+	assert.False(t, GrantTypeDeviceCode.IsTokenExchange())
+	assert.False(t, GrantTypeTokenExchange.IsJWTBearer())
 }
 
 func TestGrantTypes(t *testing.T) {
@@ -56,4 +64,38 @@ func TestResponseType(t *testing.T) {
 func TestResponseTypes(t *testing.T) {
 	rts := NewResponseTypes([]string{"code", "token"})
 	assert.Equal(t, []string{"code", "token"}, rts.String())
+	assert.True(t, rts.Contains(ResponseTypeCode))
+	assert.False(t, rts.Contains(ResponseTypeIDToken))
+}
+
+func TestResponseTypeMultiValue(t *testing.T) {
+	r := NewResponseType("code id_token")
+	assert.Equal(t, ResponseTypes{ResponseTypeCode, ResponseTypeIDToken}, r.Values())
+	assert.True(t, r.Has(ResponseTypeCode))
+	assert.True(t, r.Has(ResponseTypeIDToken))
+	assert.False(t, r.Has(ResponseTypeToken))
+	assert.False(t, r.IsCode())
+
+	assert.True(t, r.Equal(NewResponseType("id_token code")))
+	assert.True(t, r.Equal(NewResponseType("code  id_token")))
+	assert.False(t, r.Equal(NewResponseType("code id_token token")))
+	assert.False(t, r.Equal(NewResponseType("code token")))
+	assert.False(t, r.Equal(NewResponseType("code code")))
+}
+
+func TestResponseType_DefaultResponseMode(t *testing.T) {
+	cases := []struct {
+		rt       ResponseType
+		expected ResponseMode
+	}{
+		{ResponseTypeCode, ResponseModeQuery},
+		{NewResponseType("none"), ResponseModeQuery},
+		{ResponseTypeToken, ResponseModeFragment},
+		{ResponseTypeIDToken, ResponseModeFragment},
+		{NewResponseType("code id_token"), ResponseModeFragment},
+		{NewResponseType("code token"), ResponseModeFragment},
+	}
+	for _, c := range cases {
+		assert.Equalf(t, c.expected, c.rt.DefaultResponseMode(), "case %s", c.rt)
+	}
 }
@@ -1,6 +1,12 @@
 package types
 
-import "golang.org/x/text/language"
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+
+	"golang.org/x/text/language"
+)
 
 // Scope is a single OAuth 2.0 / OpenID Connect scope value (RFC 6749 §3.3).
 type Scope string
@@ -197,13 +203,37 @@ func NewLocales(locales []string) Locales {
 }
 
 // ResponseMode specifies how the authorization server returns the authorization
-// response to the client (RFC 9207).
+// response to the client (OAuth 2.0 Multiple Response Type Encoding Practices
+// §2.1).
 type ResponseMode string
 
 func NewResponseMode(s string) ResponseMode {
 	return ResponseMode(s)
 }
 
+func (m ResponseMode) IsQuery() bool {
+	return m == ResponseModeQuery
+}
+
+func (m ResponseMode) IsFragment() bool {
+	return m == ResponseModeFragment
+}
+
+func (m ResponseMode) IsFormPost() bool {
+	return m == ResponseModeFormPost
+}
+
+// IsJWT reports whether m asks for a JWT-secured authorization response
+// (JARM §2.3).
+func (m ResponseMode) IsJWT() bool {
+	return m == ResponseModeJWT || m == ResponseModeQueryJWT || m == ResponseModeFragmentJWT || m == ResponseModeFormPostJWT
+}
+
+// IsValid reports whether m is a response mode known to the library.
+func (m ResponseMode) IsValid() bool {
+	return m.IsQuery() || m.IsFragment() || m.IsFormPost() || m.IsJWT()
+}
+
 func (m ResponseMode) IsEmpty() bool {
 	return m == ""
 }
@@ -211,3 +241,162 @@ func (m ResponseMode) IsEmpty() bool {
 func (m ResponseMode) String() string {
 	return string(m)
 }
+
+var (
+	ErrClaimsRequestNotObject = errors.New("\"claims\" must be a JSON object")
+	ErrInvalidClaimsTarget    = errors.New("\"id_token\" and \"userinfo\" in \"claims\" must be JSON objects")
+	ErrInvalidClaimRequest    = errors.New("each requested claim must be null or a JSON object")
+	ErrInvalidEssential       = errors.New("\"essential\" of a requested claim must be a boolean")
+	ErrInvalidValues          = errors.New("\"values\" of a requested claim must be a JSON array")
+)
+
+// ClaimRequest requests an individual claim (OpenID Connect Core §5.5.1). A
+// nil *ClaimRequest, the JSON null, requests the claim in the default manner.
+type ClaimRequest struct {
+	// Essential reports whether the claim is needed for the client to work.
+	Essential bool `json:"essential,omitempty"`
+	// Value requests the claim with a particular value.
+	Value interface{} `json:"value,omitempty"`
+	// Values requests the claim with one of a set of values, in order of
+	// preference.
+	Values []interface{} `json:"values,omitempty"`
+}
+
+// IsEssential reports whether the claim was requested as essential.
+func (c *ClaimRequest) IsEssential() bool {
+	return c != nil && c.Essential
+}
+
+// Allows reports whether v satisfies the value and values requested for the
+// claim. Any value satisfies a claim requested without them.
+func (c *ClaimRequest) Allows(v interface{}) bool {
+	if c == nil || (c.Value == nil && len(c.Values) == 0) {
+		return true
+	}
+
+	if c.Value != nil && equalJSON(c.Value, v) {
+		return true
+	}
+
+	for _, want := range c.Values {
+		if equalJSON(want, v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ClaimsRequest is the claims request parameter (OpenID Connect Core §5.5),
+// requesting individual claims in the ID Token and from the UserInfo endpoint.
+type ClaimsRequest struct {
+	UserInfo map[string]*ClaimRequest `json:"userinfo,omitempty"`
+	IDToken  map[string]*ClaimRequest `json:"id_token,omitempty"`
+}
+
+// ParseClaimsRequest parses and validates the JSON value of the claims
+// parameter. Members other than id_token and userinfo are ignored, as are
+// unknown members of the requested claims (OpenID Connect Core §5.5).
+func ParseClaimsRequest(s string) (*ClaimsRequest, error) {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(s), &members); err != nil || members == nil {
+		return nil, ErrClaimsRequestNotObject
+	}
+
+	var (
+		req = &ClaimsRequest{}
+		err error
+	)
+
+	if raw, ok := members["userinfo"]; ok {
+		if req.UserInfo, err = parseClaimRequests(raw); err != nil {
+			return nil, err
+		}
+	}
+
+	if raw, ok := members["id_token"]; ok {
+		if req.IDToken, err = parseClaimRequests(raw); err != nil {
+			return nil, err
+		}
+	}
+
+	return req, nil
+}
+
+// String returns the JSON form of r, as sent in the claims parameter.
+func (r *ClaimsRequest) String() string {
+	if r == nil {
+		return ""
+	}
+
+	data, _ := json.Marshal(r)
+	return string(data)
+}
+
+func parseClaimRequests(raw json.RawMessage) (map[string]*ClaimRequest, error) {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &members); err != nil || members == nil {
+		return nil, ErrInvalidClaimsTarget
+	}
+
+	claims := make(map[string]*ClaimRequest, len(members))
+	for name, raw := range members {
+		c, err := parseClaimRequest(raw)
+		if err != nil {
+			return nil, err
+		}
+
+		claims[name] = c
+	}
+
+	return claims, nil
+}
+
+func parseClaimRequest(raw json.RawMessage) (*ClaimRequest, error) {
+	if bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+		return nil, nil
+	}
+
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &members); err != nil {
+		return nil, ErrInvalidClaimRequest
+	}
+
+	c := &ClaimRequest{}
+	if v, ok := members["essential"]; ok {
+		if err := json.Unmarshal(v, &c.Essential); err != nil {
+			return nil, ErrInvalidEssential
+		}
+	}
+
+	if v, ok := members["value"]; ok {
+		if err := json.Unmarshal(v, &c.Value); err != nil {
+			return nil, ErrInvalidClaimRequest
+		}
+	}
+
+	if v, ok := members["values"]; ok {
+		if err := json.Unmarshal(v, &c.Values); err != nil {
+			return nil, ErrInvalidValues
+		}
+	}
+
+	return c, nil
+}
+
+// equalJSON reports whether a and b have the same JSON encoding, so that
+// values decoded from a request compare equal to Go values of other types,
+// such as 1.0 and 1.
+func equalJSON(a, b interface{}) bool {
+	da, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+
+	db, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+
+	return bytes.Equal(da, db)
+}
@@ -91,4 +91,85 @@ func TestResponseMode(t *testing.T) {
 	assert.Equal(t, "query", m.String())
 	assert.False(t, m.IsEmpty())
 	assert.True(t, NewResponseMode("").IsEmpty())
+	assert.True(t, m.IsQuery())
+	assert.True(t, NewResponseMode("fragment").IsFragment())
+	assert.True(t, NewResponseMode("form_post").IsFormPost())
+	assert.False(t, m.IsJWT())
+	assert.True(t, m.IsValid())
+	assert.False(t, NewResponseMode("web_message").IsValid())
+
+	for _, s := range []string{"jwt", "query.jwt", "fragment.jwt", "form_post.jwt"} {
+		assert.Truef(t, NewResponseMode(s).IsJWT(), "case %s", s)
+		assert.Truef(t, NewResponseMode(s).IsValid(), "case %s", s)
+	}
+}
+
+func TestParseClaimsRequest(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		r, err := ParseClaimsRequest(`{
+			"userinfo": {"given_name": {"essential": true}, "email": null},
+			"id_token": {
+				"auth_time": {"essential": true},
+				"acr": {"values": ["urn:mace:incommon:iap:silver", "urn:mace:incommon:iap:bronze"]},
+				"sub": {"value": "248289761001"}
+			},
+			"other": 1
+		}`)
+		assert.NoError(t, err)
+		assert.True(t, r.UserInfo["given_name"].IsEssential())
+		assert.Contains(t, r.UserInfo, "email")
+		assert.Nil(t, r.UserInfo["email"])
+		assert.True(t, r.IDToken["auth_time"].IsEssential())
+		assert.False(t, r.IDToken["acr"].IsEssential())
+		assert.Equal(t, []interface{}{"urn:mace:incommon:iap:silver", "urn:mace:incommon:iap:bronze"}, r.IDToken["acr"].Values)
+		assert.Equal(t, "248289761001", r.IDToken["sub"].Value)
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		r, err := ParseClaimsRequest(`{"id_token":{"acr":{"essential":true,"values":["1"]},"email":null}}`)
+		assert.NoError(t, err)
+
+		parsed, err := ParseClaimsRequest(r.String())
+		assert.NoError(t, err)
+		assert.Equal(t, r, parsed)
+		assert.Equal(t, "", (*ClaimsRequest)(nil).String())
+	})
+
+	cases := []struct {
+		name  string
+		value string
+		err   error
+	}{
+		{"not JSON", `not-json`, ErrClaimsRequestNotObject},
+		{"array", `[]`, ErrClaimsRequestNotObject},
+		{"null", `null`, ErrClaimsRequestNotObject},
+		{"target not an object", `{"id_token": ["acr"]}`, ErrInvalidClaimsTarget},
+		{"null target", `{"userinfo": null}`, ErrInvalidClaimsTarget},
+		{"claim not an object", `{"id_token": {"acr": true}}`, ErrInvalidClaimRequest},
+		{"essential not a boolean", `{"id_token": {"acr": {"essential": "true"}}}`, ErrInvalidEssential},
+		{"values not an array", `{"id_token": {"acr": {"values": "1"}}}`, ErrInvalidValues},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := ParseClaimsRequest(c.value)
+			assert.ErrorIs(t, err, c.err)
+		})
+	}
+}
+
+func TestClaimRequest_Allows(t *testing.T) {
+	var none *ClaimRequest
+	assert.True(t, none.Allows("anything"))
+	assert.False(t, none.IsEssential())
+	assert.True(t, (&ClaimRequest{Essential: true}).Allows(nil))
+
+	value := &ClaimRequest{Value: float64(1)}
+	assert.True(t, value.Allows(1))
+	assert.False(t, value.Allows("1"))
+	assert.False(t, value.Allows(nil))
+
+	values := &ClaimRequest{Values: []interface{}{"silver", "bronze"}}
+	assert.True(t, values.Allows("bronze"))
+	assert.False(t, values.Allows("gold"))
 }
@@ -0,0 +1,34 @@
+package types
+
+// TokenTypeIdentifier is a URI identifying the type of a token in a token
+// exchange request or response (RFC 8693 §3), e.g. subject_token_type or
+// issued_token_type.
+type TokenTypeIdentifier string
+
+func NewTokenTypeIdentifier(s string) TokenTypeIdentifier {
+	return TokenTypeIdentifier(s)
+}
+
+func (t TokenTypeIdentifier) IsEmpty() bool {
+	return t == ""
+}
+
+func (t TokenTypeIdentifier) IsAccessToken() bool {
+	return t == TokenTypeIdentifierAccessToken
+}
+
+func (t TokenTypeIdentifier) IsRefreshToken() bool {
+	return t == TokenTypeIdentifierRefreshToken
+}
+
+func (t TokenTypeIdentifier) IsIDToken() bool {
+	return t == TokenTypeIdentifierIDToken
+}
+
+func (t TokenTypeIdentifier) IsJWT() bool {
+	return t == TokenTypeIdentifierJWT
+}
+
+func (t TokenTypeIdentifier) String() string {
+	return string(t)
+}
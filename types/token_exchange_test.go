@@ -0,0 +1,25 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenTypeIdentifier(t *testing.T) {
+	tt := NewTokenTypeIdentifier("urn:example:custom")
+	assert.IsType(t, TokenTypeIdentifier(""), tt)
+	assert.Equal(t, "urn:example:custom", tt.String())
+	assert.False(t, tt.IsEmpty())
+	assert.True(t, NewTokenTypeIdentifier("").IsEmpty())
+	assert.False(t, tt.IsAccessToken())
+	assert.False(t, tt.IsRefreshToken())
+	assert.False(t, tt.IsIDToken())
+	assert.False(t, tt.IsJWT())
+
+	assert.True(t, TokenTypeIdentifierAccessToken.IsAccessToken())
+	assert.True(t, TokenTypeIdentifierRefreshToken.IsRefreshToken())
+	assert.True(t, TokenTypeIdentifierIDToken.IsIDToken())
+	assert.True(t, TokenTypeIdentifierJWT.IsJWT())
+	assert.False(t, TokenTypeIdentifierJWT.IsAccessToken())
+}
@@ -3,6 +3,7 @@ package utils
 import (
 	"encoding/json"
 	"fmt"
+	"html/template"
 	"mime"
 	"net/http"
 	"net/url"
@@ -10,6 +11,23 @@ import (
 	"github.com/tniah/authlib/types"
 )
 
+// formPostTemplate renders the auto-submitting page of the Form Post Response
+// Mode. html/template escapes the parameters and neutralises unsafe action URLs.
+var formPostTemplate = template.Must(template.New("form_post").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Submit This Form</title></head>
+<body>
+<form method="post" action="{{.Action}}">
+{{- range $name, $value := .Params}}
+<input type="hidden" name="{{$name}}" value="{{$value}}">
+{{- end}}
+<noscript><button type="submit">Continue</button></noscript>
+</form>
+<script nonce="{{.Nonce}}">document.forms[0].submit();</script>
+</body>
+</html>
+`))
+
 // ContentType parses the Content-Type header from r and returns it as a
 // types.ContentType. Returns an error if the header is missing or malformed.
 func ContentType(r *http.Request) (types.ContentType, error) {
@@ -65,6 +83,42 @@ func AddParamsToURI(uri string, params map[string]interface{}) (string, error) {
 	return u.String(), nil
 }
 
+// AddParamsToFragment appends params to the fragment component of uri and
+// returns the resulting URL string. Used for responses that must not reach the
+// server through the query string (RFC 6749 §4.2.2).
+func AddParamsToFragment(uri string, params map[string]interface{}) (string, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", err
+	}
+
+	f, err := url.ParseQuery(u.Fragment)
+	if err != nil {
+		return "", err
+	}
+
+	for k, v := range params {
+		f.Set(k, fmt.Sprint(v))
+	}
+	u.Fragment = ""
+	u.RawFragment = ""
+
+	return u.String() + "#" + f.Encode(), nil
+}
+
+// RedirectWithFragment writes a 302 redirect response to rw, appending params
+// to the fragment component of uri.
+func RedirectWithFragment(rw http.ResponseWriter, uri string, params map[string]interface{}) error {
+	location, err := AddParamsToFragment(uri, params)
+	if err != nil {
+		return err
+	}
+
+	rw.Header().Set("Location", location)
+	rw.WriteHeader(http.StatusFound)
+	return nil
+}
+
 // Redirect writes a 302 redirect response to rw, appending params to uri as
 // query string parameters.
 func Redirect(rw http.ResponseWriter, uri string, params map[string]interface{}) error {
@@ -77,3 +131,52 @@ func Redirect(rw http.ResponseWriter, uri string, params map[string]interface{})
 	rw.WriteHeader(http.StatusFound)
 	return nil
 }
+
+// RedirectWithResponseMode returns params to uri the way mode asks for: in an
+// auto-submitted form for form_post, in the fragment for fragment, and in the
+// query string otherwise.
+func RedirectWithResponseMode(rw http.ResponseWriter, mode types.ResponseMode, uri string, params map[string]interface{}) error {
+	switch {
+	case mode.IsFormPost():
+		return FormPost(rw, uri, params)
+	case mode.IsFragment():
+		return RedirectWithFragment(rw, uri, params)
+	default:
+		return Redirect(rw, uri, params)
+	}
+}
+
+// FormPost writes a 200 HTML page to rw that auto-submits params to uri with
+// an HTTP POST (OAuth 2.0 Form Post Response Mode §2). The page is served with
+// a Content-Security-Policy that only runs its own script and only submits
+// forms to the origin of uri.
+func FormPost(rw http.ResponseWriter, uri string, params map[string]interface{}) error {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return err
+	}
+
+	nonce, err := GenerateRandString(24, AlphaNum)
+	if err != nil {
+		return err
+	}
+
+	origin := u.Scheme + ":"
+	if u.Host != "" {
+		origin = u.Scheme + "://" + u.Host
+	}
+
+	rw.Header().Set("Content-Type", "text/html;charset=UTF-8")
+	rw.Header().Set("Cache-Control", "no-store")
+	rw.Header().Set("Pragma", "no-cache")
+	rw.Header().Set("Referrer-Policy", "no-referrer")
+	rw.Header().Set("Content-Security-Policy", fmt.Sprintf(
+		"default-src 'none'; script-src 'nonce-%s'; form-action %s; base-uri 'none'", nonce, origin))
+	rw.WriteHeader(http.StatusOK)
+
+	return formPostTemplate.Execute(rw, map[string]interface{}{
+		"Action": u.String(),
+		"Params": params,
+		"Nonce":  nonce,
+	})
+}
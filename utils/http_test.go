@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -106,6 +107,63 @@ func TestAddParamsToURI(t *testing.T) {
 	})
 }
 
+func TestAddParamsToFragment(t *testing.T) {
+	t.Run("appends_params_to_fragment", func(t *testing.T) {
+		uri, err := AddParamsToFragment("https://example.com/cb?existing=1", map[string]interface{}{
+			"access_token": "abc123",
+			"state":        "xyz",
+		})
+		assert.NoError(t, err)
+
+		parsed, err := url.Parse(uri)
+		require.NoError(t, err)
+		assert.Equal(t, "1", parsed.Query().Get("existing"))
+		assert.Empty(t, parsed.Query().Get("access_token"))
+
+		fragment, err := url.ParseQuery(parsed.Fragment)
+		require.NoError(t, err)
+		assert.Equal(t, "abc123", fragment.Get("access_token"))
+		assert.Equal(t, "xyz", fragment.Get("state"))
+	})
+
+	t.Run("preserves_existing_fragment_params", func(t *testing.T) {
+		uri, err := AddParamsToFragment("https://example.com/cb#existing=1", map[string]interface{}{
+			"new": "2",
+		})
+		assert.NoError(t, err)
+
+		parsed, err := url.Parse(uri)
+		require.NoError(t, err)
+		fragment, err := url.ParseQuery(parsed.Fragment)
+		require.NoError(t, err)
+		assert.Equal(t, "1", fragment.Get("existing"))
+		assert.Equal(t, "2", fragment.Get("new"))
+	})
+
+	t.Run("error_on_invalid_uri", func(t *testing.T) {
+		_, err := AddParamsToFragment("://bad uri", map[string]interface{}{})
+		assert.Error(t, err)
+	})
+}
+
+func TestRedirectWithFragment(t *testing.T) {
+	t.Run("sets_302_and_location_header", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+		err := RedirectWithFragment(rw, "https://example.com/cb", map[string]interface{}{
+			"access_token": "abc123",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusFound, rw.Code)
+		assert.Equal(t, "https://example.com/cb#access_token=abc123", rw.Header().Get("Location"))
+	})
+
+	t.Run("error_on_invalid_uri", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+		err := RedirectWithFragment(rw, "://bad uri", map[string]interface{}{})
+		assert.Error(t, err)
+	})
+}
+
 func TestRedirect(t *testing.T) {
 	t.Run("sets_302_and_location_header", func(t *testing.T) {
 		rw := httptest.NewRecorder()
@@ -126,3 +184,72 @@ func TestRedirect(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestRedirectWithResponseMode(t *testing.T) {
+	params := map[string]interface{}{"code": "abc123"}
+
+	t.Run("query", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+		require.NoError(t, RedirectWithResponseMode(rw, types.ResponseModeQuery, "https://example.com/cb", params))
+		assert.Equal(t, http.StatusFound, rw.Code)
+		assert.Equal(t, "https://example.com/cb?code=abc123", rw.Header().Get("Location"))
+	})
+
+	t.Run("fragment", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+		require.NoError(t, RedirectWithResponseMode(rw, types.ResponseModeFragment, "https://example.com/cb", params))
+		assert.Equal(t, "https://example.com/cb#code=abc123", rw.Header().Get("Location"))
+	})
+
+	t.Run("form_post", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+		require.NoError(t, RedirectWithResponseMode(rw, types.ResponseModeFormPost, "https://example.com/cb", params))
+		assert.Equal(t, http.StatusOK, rw.Code)
+		assert.Empty(t, rw.Header().Get("Location"))
+		assert.Contains(t, rw.Body.String(), `name="code" value="abc123"`)
+	})
+
+	t.Run("defaults_to_query", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+		require.NoError(t, RedirectWithResponseMode(rw, "", "https://example.com/cb", params))
+		assert.Equal(t, "https://example.com/cb?code=abc123", rw.Header().Get("Location"))
+	})
+}
+
+func TestFormPost(t *testing.T) {
+	t.Run("renders_auto_submitting_form", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+		err := FormPost(rw, "https://example.com/cb?x=1", map[string]interface{}{
+			"code":  "abc123",
+			"state": `"><script>alert(1)</script>`,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rw.Code)
+		assert.Equal(t, "text/html;charset=UTF-8", rw.Header().Get("Content-Type"))
+		assert.Equal(t, "no-store", rw.Header().Get("Cache-Control"))
+
+		csp := rw.Header().Get("Content-Security-Policy")
+		assert.Contains(t, csp, "default-src 'none'")
+		assert.Contains(t, csp, "form-action https://example.com")
+		assert.Regexp(t, `script-src 'nonce-[a-zA-Z0-9]{24}'`, csp)
+
+		body := rw.Body.String()
+		assert.Contains(t, body, `action="https://example.com/cb?x=1"`)
+		assert.Contains(t, body, `name="code" value="abc123"`)
+		assert.NotContains(t, body, "<script>alert(1)</script>")
+		assert.Contains(t, body, "document.forms[0].submit()")
+	})
+
+	t.Run("neutralises_unsafe_action", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+		err := FormPost(rw, "javascript:alert(1)", map[string]interface{}{})
+		assert.NoError(t, err)
+		assert.NotContains(t, rw.Body.String(), `action="javascript:`)
+	})
+
+	t.Run("error_on_invalid_uri", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+		err := FormPost(rw, "://bad uri", map[string]interface{}{})
+		assert.Error(t, err)
+	})
+}
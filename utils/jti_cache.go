@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryJWTIDCache is an in-process replay cache of JWT IDs, satisfying the
+// JWTIDCache interfaces of client authentication, the JWT bearer grant and
+// DPoP. Entries are dropped once their JWT has expired. It suits a single
+// server instance; use a shared store (e.g. Redis) when running several.
+type MemoryJWTIDCache struct {
+	lock sync.Mutex
+	used map[string]time.Time
+}
+
+// NewMemoryJWTIDCache returns an empty MemoryJWTIDCache.
+func NewMemoryJWTIDCache() *MemoryJWTIDCache {
+	return &MemoryJWTIDCache{used: make(map[string]time.Time)}
+}
+
+// Use records jti for issuer until expiresAt. It returns false when the pair
+// is already recorded.
+func (c *MemoryJWTIDCache) Use(_ context.Context, issuer, jti string, expiresAt time.Time) (bool, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := time.Now()
+	for k, exp := range c.used {
+		if now.After(exp) {
+			delete(c.used, k)
+		}
+	}
+
+	// Issuers are URIs, which cannot contain NUL, so distinct pairs never
+	// share a key.
+	key := issuer + "\x00" + jti
+	if _, ok := c.used[key]; ok {
+		return false, nil
+	}
+
+	c.used[key] = expiresAt
+	return true, nil
+}
@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryJWTIDCache(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("rejects_reuse", func(t *testing.T) {
+		c := NewMemoryJWTIDCache()
+		exp := time.Now().Add(time.Minute)
+
+		ok, err := c.Use(ctx, "issuer", "jti-1", exp)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+
+		ok, err = c.Use(ctx, "issuer", "jti-1", exp)
+		assert.NoError(t, err)
+		assert.False(t, ok)
+
+		ok, err = c.Use(ctx, "other-issuer", "jti-1", exp)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("forgets_expired_entries", func(t *testing.T) {
+		c := NewMemoryJWTIDCache()
+		ok, _ := c.Use(ctx, "issuer", "jti-1", time.Now().Add(time.Millisecond))
+		assert.True(t, ok)
+
+		time.Sleep(5 * time.Millisecond)
+		ok, _ = c.Use(ctx, "issuer", "jti-1", time.Now().Add(time.Minute))
+		assert.True(t, ok)
+		assert.Len(t, c.used, 1)
+	})
+}
@@ -0,0 +1,291 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+var (
+	// ErrUnsupportedKeyType is returned by JWK.PublicKey when the kty or crv
+	// parameter is not recognised, and by NewJWK for other key types.
+	ErrUnsupportedKeyType = errors.New("unsupported key type")
+	// ErrInvalidJWK is returned by JWK.PublicKey when a key parameter is
+	// missing or malformed.
+	ErrInvalidJWK = errors.New("invalid jwk")
+)
+
+type (
+	// JWK is a public JSON Web Key (RFC 7517 §4). Only the parameters needed
+	// to verify signatures with RSA, EC, and OKP (Ed25519) keys are kept.
+	JWK struct {
+		Kty string `json:"kty"`
+		Use string `json:"use,omitempty"`
+		Alg string `json:"alg,omitempty"`
+		Kid string `json:"kid,omitempty"`
+
+		// RSA parameters (RFC 7518 §6.3.1).
+		N string `json:"n,omitempty"`
+		E string `json:"e,omitempty"`
+
+		// EC (RFC 7518 §6.2.1) and OKP (RFC 8037 §2) parameters.
+		Crv string `json:"crv,omitempty"`
+		X   string `json:"x,omitempty"`
+		Y   string `json:"y,omitempty"`
+
+		// X5c is the X.509 certificate chain of the key (RFC 7517 §4.7),
+		// each entry a base64-encoded (not base64url) DER certificate.
+		X5c []string `json:"x5c,omitempty"`
+	}
+
+	// JWKSet is a JSON Web Key Set (RFC 7517 §5).
+	JWKSet struct {
+		Keys []JWK `json:"keys"`
+	}
+)
+
+// ParseJWKSet decodes a JWK Set document.
+func ParseJWKSet(data []byte) (*JWKSet, error) {
+	var set JWKSet
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, err
+	}
+
+	return &set, nil
+}
+
+// SigningKeys returns the keys usable to verify a signature made with alg
+// and, when kid is not empty, carrying that key ID. Keys reserved for
+// encryption (use "enc") or for another algorithm are skipped.
+func (s *JWKSet) SigningKeys(kid, alg string) []JWK {
+	keys := make([]JWK, 0, len(s.Keys))
+	for _, k := range s.Keys {
+		if kid != "" && k.Kid != kid {
+			continue
+		}
+
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+
+		if k.Alg != "" && k.Alg != alg {
+			continue
+		}
+
+		keys = append(keys, k)
+	}
+
+	return keys
+}
+
+// NewJWK returns the public JWK of an *rsa.PublicKey, *ecdsa.PublicKey or
+// ed25519.PublicKey, the inverse of JWK.PublicKey. Private keys of these types
+// are accepted too; only their public part is kept.
+func NewJWK(key crypto.PublicKey) (JWK, error) {
+	if k, ok := key.(interface{ Public() crypto.PublicKey }); ok {
+		key = k.Public()
+	}
+
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		return JWK{
+			Kty: "RSA",
+			N:   base64.RawURLEncoding.EncodeToString(k.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.E)).Bytes()),
+		}, nil
+	case *ecdsa.PublicKey:
+		var crv string
+		switch k.Curve {
+		case elliptic.P256():
+			crv = "P-256"
+		case elliptic.P384():
+			crv = "P-384"
+		case elliptic.P521():
+			crv = "P-521"
+		default:
+			return JWK{}, ErrUnsupportedKeyType
+		}
+
+		// RFC 7518 §6.2.1.2: coordinates are the full size of the field.
+		size := (k.Curve.Params().BitSize + 7) / 8
+		return JWK{
+			Kty: "EC",
+			Crv: crv,
+			X:   base64.RawURLEncoding.EncodeToString(k.X.FillBytes(make([]byte, size))),
+			Y:   base64.RawURLEncoding.EncodeToString(k.Y.FillBytes(make([]byte, size))),
+		}, nil
+	case ed25519.PublicKey:
+		return JWK{
+			Kty: "OKP",
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(k),
+		}, nil
+	default:
+		return JWK{}, ErrUnsupportedKeyType
+	}
+}
+
+// PublicKey returns the key as *rsa.PublicKey, *ecdsa.PublicKey, or
+// ed25519.PublicKey, ready to verify signatures with golang-jwt.
+func (k JWK) PublicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		return k.rsaPublicKey()
+	case "EC":
+		return k.ecPublicKey()
+	case "OKP":
+		return k.okpPublicKey()
+	default:
+		return nil, ErrUnsupportedKeyType
+	}
+}
+
+// Thumbprint returns the base64url-encoded SHA-256 JWK thumbprint of the key
+// (RFC 7638): the hash of the JSON object holding only its required public
+// members, in lexicographic order.
+func (k JWK) Thumbprint() (string, error) {
+	var members string
+	switch k.Kty {
+	case "RSA":
+		if k.E == "" || k.N == "" {
+			return "", ErrInvalidJWK
+		}
+		members = fmt.Sprintf(`{"e":%q,"kty":"RSA","n":%q}`, k.E, k.N)
+	case "EC":
+		if k.Crv == "" || k.X == "" || k.Y == "" {
+			return "", ErrInvalidJWK
+		}
+		members = fmt.Sprintf(`{"crv":%q,"kty":"EC","x":%q,"y":%q}`, k.Crv, k.X, k.Y)
+	case "OKP":
+		if k.Crv == "" || k.X == "" {
+			return "", ErrInvalidJWK
+		}
+		members = fmt.Sprintf(`{"crv":%q,"kty":"OKP","x":%q}`, k.Crv, k.X)
+	default:
+		return "", ErrUnsupportedKeyType
+	}
+
+	sum := sha256.Sum256([]byte(members))
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
+
+// Certificate returns the first certificate of the x5c chain, the one
+// holding the key itself (RFC 7517 §4.7).
+func (k JWK) Certificate() (*x509.Certificate, error) {
+	if len(k.X5c) == 0 {
+		return nil, ErrInvalidJWK
+	}
+
+	der, err := base64.StdEncoding.DecodeString(k.X5c[0])
+	if err != nil {
+		return nil, ErrInvalidJWK
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, ErrInvalidJWK
+	}
+
+	return cert, nil
+}
+
+func (k JWK) rsaPublicKey() (*rsa.PublicKey, error) {
+	n, err := decodeJWKParam(k.N)
+	if err != nil {
+		return nil, err
+	}
+
+	e, err := decodeJWKParam(k.E)
+	if err != nil {
+		return nil, err
+	}
+
+	exponent := new(big.Int).SetBytes(e)
+	if !exponent.IsInt64() || exponent.Int64() < 2 || exponent.Int64() > 1<<31-1 {
+		return nil, ErrInvalidJWK
+	}
+
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+}
+
+func (k JWK) ecPublicKey() (*ecdsa.PublicKey, error) {
+	var (
+		curve     elliptic.Curve
+		ecdhCurve ecdh.Curve
+	)
+	switch k.Crv {
+	case "P-256":
+		curve, ecdhCurve = elliptic.P256(), ecdh.P256()
+	case "P-384":
+		curve, ecdhCurve = elliptic.P384(), ecdh.P384()
+	case "P-521":
+		curve, ecdhCurve = elliptic.P521(), ecdh.P521()
+	default:
+		return nil, ErrUnsupportedKeyType
+	}
+
+	x, err := decodeJWKParam(k.X)
+	if err != nil {
+		return nil, err
+	}
+
+	y, err := decodeJWKParam(k.Y)
+	if err != nil {
+		return nil, err
+	}
+
+	// RFC 7518 §6.2.1.2: coordinates are the full size of the field.
+	size := (curve.Params().BitSize + 7) / 8
+	if len(x) != size || len(y) != size {
+		return nil, ErrInvalidJWK
+	}
+
+	// ecdh rejects points that are not on the curve.
+	point := append([]byte{4}, append(x, y...)...)
+	if _, err = ecdhCurve.NewPublicKey(point); err != nil {
+		return nil, ErrInvalidJWK
+	}
+
+	return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+}
+
+func (k JWK) okpPublicKey() (ed25519.PublicKey, error) {
+	if k.Crv != "Ed25519" {
+		return nil, ErrUnsupportedKeyType
+	}
+
+	x, err := decodeJWKParam(k.X)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(x) != ed25519.PublicKeySize {
+		return nil, ErrInvalidJWK
+	}
+
+	return ed25519.PublicKey(x), nil
+}
+
+// decodeJWKParam decodes a base64url-encoded key parameter (RFC 7518 §2).
+func decodeJWKParam(v string) ([]byte, error) {
+	if v == "" {
+		return nil, ErrInvalidJWK
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(v)
+	if err != nil {
+		return nil, ErrInvalidJWK
+	}
+
+	return b, nil
+}
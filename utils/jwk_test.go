@@ -0,0 +1,254 @@
+package utils
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func b64(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func TestParseJWKSet(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		set, err := ParseJWKSet([]byte(`{"keys":[{"kty":"RSA","kid":"k1","n":"AQAB","e":"AQAB"},{"kty":"OKP","crv":"Ed25519","x":"abc"}]}`))
+		require.NoError(t, err)
+		require.Len(t, set.Keys, 2)
+		assert.Equal(t, "k1", set.Keys[0].Kid)
+		assert.Equal(t, "Ed25519", set.Keys[1].Crv)
+	})
+
+	t.Run("error_on_invalid_json", func(t *testing.T) {
+		_, err := ParseJWKSet([]byte("not-json"))
+		assert.Error(t, err)
+	})
+}
+
+func TestJWKSet_SigningKeys(t *testing.T) {
+	set := &JWKSet{Keys: []JWK{
+		{Kty: "RSA", Kid: "k1"},
+		{Kty: "RSA", Kid: "k2", Use: "enc"},
+		{Kty: "RSA", Kid: "k3", Alg: "PS256"},
+		{Kty: "RSA", Kid: "k4", Use: "sig", Alg: "RS256"},
+	}}
+
+	kids := func(keys []JWK) []string {
+		ret := make([]string, 0, len(keys))
+		for _, k := range keys {
+			ret = append(ret, k.Kid)
+		}
+		return ret
+	}
+
+	assert.Equal(t, []string{"k1", "k4"}, kids(set.SigningKeys("", "RS256")))
+	assert.Equal(t, []string{"k3"}, kids(set.SigningKeys("k3", "PS256")))
+	assert.Empty(t, set.SigningKeys("k2", "RS256"))
+	assert.Empty(t, set.SigningKeys("unknown", "RS256"))
+}
+
+func TestNewJWK(t *testing.T) {
+	t.Run("rsa", func(t *testing.T) {
+		priv, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+
+		k, err := NewJWK(priv)
+		require.NoError(t, err)
+		assert.Equal(t, "RSA", k.Kty)
+		assert.Equal(t, "AQAB", k.E)
+
+		pub, err := k.PublicKey()
+		require.NoError(t, err)
+		assert.True(t, priv.PublicKey.Equal(pub))
+	})
+
+	t.Run("ec", func(t *testing.T) {
+		for _, curve := range []elliptic.Curve{elliptic.P256(), elliptic.P384(), elliptic.P521()} {
+			priv, err := ecdsa.GenerateKey(curve, rand.Reader)
+			require.NoError(t, err)
+
+			k, err := NewJWK(&priv.PublicKey)
+			require.NoError(t, err)
+			assert.Equal(t, curve.Params().Name, k.Crv)
+
+			pub, err := k.PublicKey()
+			require.NoError(t, err)
+			assert.True(t, priv.PublicKey.Equal(pub))
+		}
+	})
+
+	t.Run("ed25519", func(t *testing.T) {
+		pubKey, priv, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		k, err := NewJWK(priv)
+		require.NoError(t, err)
+		assert.Equal(t, JWK{Kty: "OKP", Crv: "Ed25519", X: b64(pubKey)}, k)
+	})
+
+	t.Run("error_on_unsupported_key", func(t *testing.T) {
+		_, err := NewJWK([]byte("secret"))
+		assert.ErrorIs(t, err, ErrUnsupportedKeyType)
+	})
+}
+
+func TestJWK_PublicKey(t *testing.T) {
+	t.Run("rsa", func(t *testing.T) {
+		privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+
+		jwk := JWK{
+			Kty: "RSA",
+			N:   b64(privateKey.N.Bytes()),
+			E:   b64(big.NewInt(int64(privateKey.E)).Bytes()),
+		}
+		key, err := jwk.PublicKey()
+		require.NoError(t, err)
+		assert.True(t, privateKey.PublicKey.Equal(key))
+	})
+
+	t.Run("ec", func(t *testing.T) {
+		privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		jwk := JWK{
+			Kty: "EC",
+			Crv: "P-256",
+			X:   b64(privateKey.X.FillBytes(make([]byte, 32))),
+			Y:   b64(privateKey.Y.FillBytes(make([]byte, 32))),
+		}
+		key, err := jwk.PublicKey()
+		require.NoError(t, err)
+		assert.True(t, privateKey.PublicKey.Equal(key))
+	})
+
+	t.Run("ed25519", func(t *testing.T) {
+		publicKey, _, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		key, err := JWK{Kty: "OKP", Crv: "Ed25519", X: b64(publicKey)}.PublicKey()
+		require.NoError(t, err)
+		assert.Equal(t, publicKey, key)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		offCurve := b64(make([]byte, 32))
+		cases := []struct {
+			name string
+			jwk  JWK
+			err  error
+		}{
+			{"unsupported_kty", JWK{Kty: "oct"}, ErrUnsupportedKeyType},
+			{"unsupported_crv", JWK{Kty: "EC", Crv: "P-192"}, ErrUnsupportedKeyType},
+			{"unsupported_okp_crv", JWK{Kty: "OKP", Crv: "X25519"}, ErrUnsupportedKeyType},
+			{"missing_rsa_modulus", JWK{Kty: "RSA", E: "AQAB"}, ErrInvalidJWK},
+			{"invalid_rsa_exponent", JWK{Kty: "RSA", N: "AQAB", E: "AQ"}, ErrInvalidJWK},
+			{"invalid_base64", JWK{Kty: "RSA", N: "!!", E: "AQAB"}, ErrInvalidJWK},
+			{"short_ec_coordinate", JWK{Kty: "EC", Crv: "P-256", X: "AQAB", Y: "AQAB"}, ErrInvalidJWK},
+			{"ec_point_off_curve", JWK{Kty: "EC", Crv: "P-256", X: offCurve, Y: offCurve}, ErrInvalidJWK},
+			{"short_ed25519_key", JWK{Kty: "OKP", Crv: "Ed25519", X: "AQAB"}, ErrInvalidJWK},
+		}
+
+		for _, c := range cases {
+			t.Run(c.name, func(t *testing.T) {
+				_, err := c.jwk.PublicKey()
+				assert.ErrorIs(t, err, c.err)
+			})
+		}
+	})
+}
+
+func TestJWK_Certificate(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		tmpl := &x509.Certificate{SerialNumber: big.NewInt(1), Subject: pkix.Name{CommonName: "client"}}
+		der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &priv.PublicKey, priv)
+		require.NoError(t, err)
+
+		cert, err := JWK{Kty: "EC", X5c: []string{base64.StdEncoding.EncodeToString(der)}}.Certificate()
+		require.NoError(t, err)
+		assert.Equal(t, der, cert.Raw)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		cases := []struct {
+			name string
+			jwk  JWK
+		}{
+			{"missing_x5c", JWK{Kty: "EC"}},
+			{"invalid_base64", JWK{Kty: "EC", X5c: []string{"!!"}}},
+			{"invalid_der", JWK{Kty: "EC", X5c: []string{"AQAB"}}},
+		}
+
+		for _, c := range cases {
+			t.Run(c.name, func(t *testing.T) {
+				_, err := c.jwk.Certificate()
+				assert.ErrorIs(t, err, ErrInvalidJWK)
+			})
+		}
+	})
+}
+
+func TestJWK_Thumbprint(t *testing.T) {
+	t.Run("rfc7638_example", func(t *testing.T) {
+		// RFC 7638 §3.1.
+		k := JWK{
+			Kty: "RSA",
+			N:   "0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw",
+			E:   "AQAB",
+			Alg: "RS256",
+			Kid: "2011-04-29",
+		}
+
+		thumbprint, err := k.Thumbprint()
+		require.NoError(t, err)
+		assert.Equal(t, "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs", thumbprint)
+	})
+
+	t.Run("ignores_optional_members", func(t *testing.T) {
+		k := JWK{Kty: "EC", Crv: "P-256", X: "eA", Y: "eQ"}
+		withOptional := k
+		withOptional.Kid, withOptional.Use, withOptional.Alg = "k1", "sig", "ES256"
+
+		a, err := k.Thumbprint()
+		require.NoError(t, err)
+		b, err := withOptional.Thumbprint()
+		require.NoError(t, err)
+		assert.Equal(t, a, b)
+	})
+
+	t.Run("okp", func(t *testing.T) {
+		// RFC 8037 Appendix A.3.
+		k := JWK{Kty: "OKP", Crv: "Ed25519", X: "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}
+
+		thumbprint, err := k.Thumbprint()
+		require.NoError(t, err)
+		assert.Equal(t, "kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k", thumbprint)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		_, err := JWK{Kty: "oct"}.Thumbprint()
+		assert.ErrorIs(t, err, ErrUnsupportedKeyType)
+
+		_, err = JWK{Kty: "EC", Crv: "P-256", X: "eA"}.Thumbprint()
+		assert.ErrorIs(t, err, ErrInvalidJWK)
+
+		_, err = JWK{Kty: "RSA", N: "AQAB"}.Thumbprint()
+		assert.ErrorIs(t, err, ErrInvalidJWK)
+
+		_, err = JWK{Kty: "OKP", Crv: "Ed25519"}.Thumbprint()
+		assert.ErrorIs(t, err, ErrInvalidJWK)
+	})
+}
@@ -1,7 +1,11 @@
 package utils
 
 import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
 	"errors"
+	"hash"
 	"strings"
 	"time"
 
@@ -48,6 +52,18 @@ func NewJWTToken(signingKey []byte, signingMethod jwt.SigningMethod, args ...str
 	}, nil
 }
 
+// NewJWTTokenWithKey returns a JWTToken signing with an already parsed key,
+// such as an *rsa.PrivateKey, skipping the PEM parsing of NewJWTToken. Any
+// other crypto.Signer is accepted for RS*, PS*, ES* and EdDSA, so that the
+// private key can stay inside a KMS or HSM.
+func NewJWTTokenWithKey(signingKey interface{}, signingMethod jwt.SigningMethod, keyID string) *JWTToken {
+	return &JWTToken{
+		signingKeyID:  keyID,
+		signingKey:    signingKey,
+		signingMethod: signingMethod,
+	}
+}
+
 // KeyID returns the key ID associated with this token, or an empty string if
 // none was provided.
 func (t *JWTToken) KeyID() string {
@@ -75,7 +91,7 @@ func (t *JWTToken) Generate(claims JWTClaim, headers JWTHeader) (string, error)
 		mapClaims[k] = claims[k]
 	}
 
-	token := jwt.NewWithClaims(t.signingMethod, mapClaims)
+	token := jwt.NewWithClaims(jwtSigningMethod(t.signingKey, t.signingMethod), mapClaims)
 	for k, v := range headers {
 		token.Header[k] = v
 	}
@@ -111,3 +127,52 @@ func ParseSigningKey(signingKey []byte, signingMethod jwt.SigningMethod) (interf
 
 	return nil, ErrUnsupportedSigningMethod
 }
+
+// ParseVerificationKey parses verificationKey into the concrete key type
+// expected by signingMethod to verify signatures: a PEM-encoded public key for
+// ES, RS/PS, and Ed, or the raw shared secret for HS. Returns
+// ErrUnsupportedSigningMethod for any other algorithm.
+func ParseVerificationKey(verificationKey []byte, signingMethod jwt.SigningMethod) (interface{}, error) {
+	alg := signingMethod.Alg()
+	if strings.HasPrefix(alg, "ES") {
+		return jwt.ParseECPublicKeyFromPEM(verificationKey)
+	}
+
+	if strings.HasPrefix(alg, "RS") || strings.HasPrefix(alg, "PS") {
+		return jwt.ParseRSAPublicKeyFromPEM(verificationKey)
+	}
+
+	if strings.HasPrefix(alg, "HS") {
+		return verificationKey, nil
+	}
+
+	if strings.HasPrefix(alg, "Ed") {
+		return jwt.ParseEdPublicKeyFromPEM(verificationKey)
+	}
+
+	return nil, ErrUnsupportedSigningMethod
+}
+
+// HalfHash computes the left-most half of the hash of value, base64url encoded
+// without padding, as used by the at_hash and c_hash ID Token claims
+// (OIDC Core §3.3.2.11). The hash function matches the signing algorithm:
+// SHA-256 for *256, SHA-384 for *384 and SHA-512 for *512 and EdDSA.
+// Returns ErrUnsupportedSigningMethod for any other algorithm.
+func HalfHash(value string, signingMethod jwt.SigningMethod) (string, error) {
+	var h hash.Hash
+	alg := signingMethod.Alg()
+	switch {
+	case strings.HasSuffix(alg, "256"):
+		h = sha256.New()
+	case strings.HasSuffix(alg, "384"):
+		h = sha512.New384()
+	case strings.HasSuffix(alg, "512"), alg == "EdDSA":
+		h = sha512.New()
+	default:
+		return "", ErrUnsupportedSigningMethod
+	}
+
+	h.Write([]byte(value))
+	sum := h.Sum(nil)
+	return base64.RawURLEncoding.EncodeToString(sum[:len(sum)/2]), nil
+}
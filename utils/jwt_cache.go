@@ -0,0 +1,97 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// DefaultSigningKeyCacheSize is the number of parsed signing keys a
+// SigningKeyCache holds by default.
+const DefaultSigningKeyCacheSize = 64
+
+// SigningKeyCache caches the JWTTokens built by NewJWTToken, so that a PEM
+// signing key is parsed once instead of for every token signed. Entries are
+// keyed by a SHA-256 fingerprint of the signing method, key ID and key bytes:
+// keys returned per request by a generator are cached too, and a changed key
+// is never served from the cache. The cache is cleared when full.
+//
+// A nil *SigningKeyCache is valid and parses the key on every call.
+type SigningKeyCache struct {
+	lock   sync.RWMutex
+	size   int
+	tokens map[[sha256.Size]byte]*JWTToken
+}
+
+// NewSigningKeyCache returns an empty SigningKeyCache holding up to size
+// keys, or DefaultSigningKeyCacheSize when size is not positive.
+func NewSigningKeyCache(size int) *SigningKeyCache {
+	if size <= 0 {
+		size = DefaultSigningKeyCacheSize
+	}
+
+	return &SigningKeyCache{
+		size:   size,
+		tokens: make(map[[sha256.Size]byte]*JWTToken),
+	}
+}
+
+// JWTToken returns the JWTToken for signingKey, signingMethod and keyID,
+// parsing the key with NewJWTToken only when it is not cached yet.
+func (c *SigningKeyCache) JWTToken(signingKey []byte, signingMethod jwt.SigningMethod, keyID string) (*JWTToken, error) {
+	if c == nil {
+		return NewJWTToken(signingKey, signingMethod, keyID)
+	}
+
+	fp := signingKeyFingerprint(signingKey, signingMethod, keyID)
+
+	c.lock.RLock()
+	t, ok := c.tokens[fp]
+	c.lock.RUnlock()
+	if ok {
+		return t, nil
+	}
+
+	t, err := NewJWTToken(signingKey, signingMethod, keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if len(c.tokens) >= c.size {
+		clear(c.tokens)
+	}
+
+	c.tokens[fp] = t
+	return t, nil
+}
+
+// Len returns the number of cached keys.
+func (c *SigningKeyCache) Len() int {
+	if c == nil {
+		return 0
+	}
+
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return len(c.tokens)
+}
+
+// signingKeyFingerprint hashes the length-prefixed algorithm, key ID and key,
+// so that no two distinct inputs share a fingerprint.
+func signingKeyFingerprint(signingKey []byte, signingMethod jwt.SigningMethod, keyID string) [sha256.Size]byte {
+	h := sha256.New()
+	for _, b := range [][]byte{[]byte(signingMethod.Alg()), []byte(keyID), signingKey} {
+		h.Write(binary.BigEndian.AppendUint64(nil, uint64(len(b))))
+		h.Write(b)
+	}
+
+	var fp [sha256.Size]byte
+	h.Sum(fp[:0])
+	return fp
+}
@@ -0,0 +1,163 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func rsaPrivateKeyPEM(tb testing.TB) ([]byte, *rsa.PrivateKey) {
+	tb.Helper()
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(tb, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(priv)}), priv
+}
+
+func TestSigningKeyCache_JWTToken(t *testing.T) {
+	keyPEM, priv := rsaPrivateKeyPEM(t)
+
+	t.Run("parses_once", func(t *testing.T) {
+		c := NewSigningKeyCache(0)
+		a, err := c.JWTToken(keyPEM, jwt.SigningMethodRS256, "kid-1")
+		require.NoError(t, err)
+		b, err := c.JWTToken(keyPEM, jwt.SigningMethodRS256, "kid-1")
+		require.NoError(t, err)
+
+		assert.Same(t, a, b)
+		assert.Equal(t, 1, c.Len())
+		assert.Equal(t, "kid-1", a.KeyID())
+		assert.True(t, priv.Equal(a.SigningKey()))
+	})
+
+	t.Run("distinct_entries_per_method_kid_and_key", func(t *testing.T) {
+		c := NewSigningKeyCache(0)
+		otherPEM, _ := rsaPrivateKeyPEM(t)
+
+		a, err := c.JWTToken(keyPEM, jwt.SigningMethodRS256, "kid-1")
+		require.NoError(t, err)
+		b, err := c.JWTToken(keyPEM, jwt.SigningMethodPS256, "kid-1")
+		require.NoError(t, err)
+		d, err := c.JWTToken(keyPEM, jwt.SigningMethodRS256, "kid-2")
+		require.NoError(t, err)
+		e, err := c.JWTToken(otherPEM, jwt.SigningMethodRS256, "kid-1")
+		require.NoError(t, err)
+
+		assert.Equal(t, jwt.SigningMethodPS256, b.SigningMethod())
+		assert.Equal(t, "kid-2", d.KeyID())
+		assert.NotEqual(t, a.SigningKey(), e.SigningKey())
+		assert.Equal(t, 4, c.Len())
+	})
+
+	t.Run("cleared_when_full", func(t *testing.T) {
+		c := NewSigningKeyCache(2)
+		for i := 0; i < 3; i++ {
+			_, err := c.JWTToken(hmacKey, jwt.SigningMethodHS256, fmt.Sprintf("kid-%d", i))
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 1, c.Len())
+	})
+
+	t.Run("errors_are_not_cached", func(t *testing.T) {
+		c := NewSigningKeyCache(0)
+		_, err := c.JWTToken([]byte("not a pem key"), jwt.SigningMethodRS256, "")
+		assert.Error(t, err)
+		assert.Equal(t, 0, c.Len())
+	})
+
+	t.Run("nil_cache_parses_every_time", func(t *testing.T) {
+		var c *SigningKeyCache
+		tok, err := c.JWTToken(keyPEM, jwt.SigningMethodRS256, "kid-1")
+		require.NoError(t, err)
+		assert.Equal(t, "kid-1", tok.KeyID())
+		assert.Equal(t, 0, c.Len())
+	})
+
+	t.Run("concurrent_use", func(t *testing.T) {
+		c := NewSigningKeyCache(0)
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := c.JWTToken(keyPEM, jwt.SigningMethodRS256, "kid-1")
+				assert.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, 1, c.Len())
+	})
+}
+
+// The benchmarks below compare building a JWTToken from a PEM key on every
+// call, as done before keys were cached, with the cached and pre-parsed paths.
+// Run with: go test ./utils -run '^$' -bench JWTToken -benchmem
+
+func BenchmarkNewJWTToken(b *testing.B) {
+	keyPEM, _ := rsaPrivateKeyPEM(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := NewJWTToken(keyPEM, jwt.SigningMethodRS256, "kid-1"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSigningKeyCache_JWTToken(b *testing.B) {
+	keyPEM, _ := rsaPrivateKeyPEM(b)
+	c := NewSigningKeyCache(0)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := c.JWTToken(keyPEM, jwt.SigningMethodRS256, "kid-1"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkJWTToken_Generate(b *testing.B) {
+	keyPEM, priv := rsaPrivateKeyPEM(b)
+	claims := JWTClaim{"sub": "user-1", "iss": "https://example.com"}
+
+	b.Run("parse_per_token", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			tok, err := NewJWTToken(keyPEM, jwt.SigningMethodRS256, "kid-1")
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			if _, err = tok.Generate(claims, JWTHeader{}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("cached", func(b *testing.B) {
+		c := NewSigningKeyCache(0)
+		for i := 0; i < b.N; i++ {
+			tok, err := c.JWTToken(keyPEM, jwt.SigningMethodRS256, "kid-1")
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			if _, err = tok.Generate(claims, JWTHeader{}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("pre_parsed", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			tok := NewJWTTokenWithKey(priv, jwt.SigningMethodRS256, "kid-1")
+			if _, err := tok.Generate(claims, JWTHeader{}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,128 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/asn1"
+	"errors"
+	"math/big"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ErrInvalidSignature is returned when a crypto.Signer returns an ECDSA
+// signature that is not valid ASN.1 or does not fit the curve.
+var ErrInvalidSignature = errors.New("invalid signature returned by signer")
+
+// signerMethod wraps an asymmetric jwt.SigningMethod so that it signs with any
+// crypto.Signer, not only with the concrete private key types of the jwt
+// package. The private key stays behind the signer, which may be a KMS, an HSM
+// or a remote signing service. Verification is left to the wrapped method.
+type signerMethod struct {
+	jwt.SigningMethod
+}
+
+// Sign hashes signingString as required by the algorithm and signs the digest
+// with key, which must be a crypto.Signer holding a key of the matching type.
+// ECDSA signatures are converted from ASN.1 DER, the crypto.Signer format, to
+// the fixed-size R || S encoding of JWS (RFC 7518 §3.4).
+func (m signerMethod) Sign(signingString string, key interface{}) ([]byte, error) {
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, jwt.ErrInvalidKeyType
+	}
+
+	alg := m.Alg()
+	if alg == jwt.SigningMethodEdDSA.Alg() {
+		if _, ok = signer.Public().(ed25519.PublicKey); !ok {
+			return nil, jwt.ErrInvalidKeyType
+		}
+
+		return signer.Sign(rand.Reader, []byte(signingString), crypto.Hash(0))
+	}
+
+	var hash crypto.Hash
+	switch {
+	case strings.HasSuffix(alg, "256"):
+		hash = crypto.SHA256
+	case strings.HasSuffix(alg, "384"):
+		hash = crypto.SHA384
+	case strings.HasSuffix(alg, "512"):
+		hash = crypto.SHA512
+	default:
+		return nil, ErrUnsupportedSigningMethod
+	}
+
+	h := hash.New()
+	h.Write([]byte(signingString))
+	digest := h.Sum(nil)
+
+	switch {
+	case strings.HasPrefix(alg, "RS"):
+		if _, ok = signer.Public().(*rsa.PublicKey); !ok {
+			return nil, jwt.ErrInvalidKeyType
+		}
+
+		return signer.Sign(rand.Reader, digest, hash)
+	case strings.HasPrefix(alg, "PS"):
+		if _, ok = signer.Public().(*rsa.PublicKey); !ok {
+			return nil, jwt.ErrInvalidKeyType
+		}
+
+		return signer.Sign(rand.Reader, digest, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash, Hash: hash})
+	case strings.HasPrefix(alg, "ES"):
+		pub, ok := signer.Public().(*ecdsa.PublicKey)
+		if !ok {
+			return nil, jwt.ErrInvalidKeyType
+		}
+
+		der, err := signer.Sign(rand.Reader, digest, hash)
+		if err != nil {
+			return nil, err
+		}
+
+		return rawECDSASignature(der, (pub.Curve.Params().BitSize+7)/8)
+	}
+
+	return nil, ErrUnsupportedSigningMethod
+}
+
+// rawECDSASignature converts an ASN.1 DER ECDSA signature into R || S, each
+// left-padded to size bytes.
+func rawECDSASignature(der []byte, size int) ([]byte, error) {
+	var sig struct {
+		R, S *big.Int
+	}
+
+	rest, err := asn1.Unmarshal(der, &sig)
+	if err != nil || len(rest) > 0 {
+		return nil, ErrInvalidSignature
+	}
+
+	if sig.R.Sign() <= 0 || sig.S.Sign() <= 0 || sig.R.BitLen() > size*8 || sig.S.BitLen() > size*8 {
+		return nil, ErrInvalidSignature
+	}
+
+	out := make([]byte, 2*size)
+	sig.R.FillBytes(out[:size])
+	sig.S.FillBytes(out[size:])
+	return out, nil
+}
+
+// jwtSigningMethod returns the method signing with key: method itself for the
+// key types the jwt package signs with natively, or method wrapped to sign
+// through the crypto.Signer interface for any other signer.
+func jwtSigningMethod(key interface{}, method jwt.SigningMethod) jwt.SigningMethod {
+	switch key.(type) {
+	case *rsa.PrivateKey, *ecdsa.PrivateKey, ed25519.PrivateKey, []byte:
+		return method
+	case crypto.Signer:
+		return signerMethod{method}
+	}
+
+	return method
+}
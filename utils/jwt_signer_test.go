@@ -0,0 +1,128 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// opaqueSigner hides the concrete key type, as a KMS-backed signer does.
+type opaqueSigner struct {
+	key crypto.Signer
+	der []byte
+	err error
+}
+
+func (s *opaqueSigner) Public() crypto.PublicKey {
+	return s.key.Public()
+}
+
+func (s *opaqueSigner) Sign(r io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	if s.err != nil || s.der != nil {
+		return s.der, s.err
+	}
+
+	return s.key.Sign(r, digest, opts)
+}
+
+func TestJWTToken_GenerateWithSigner(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	ecKey := func(curve elliptic.Curve) *ecdsa.PrivateKey {
+		key, err := ecdsa.GenerateKey(curve, rand.Reader)
+		require.NoError(t, err)
+		return key
+	}
+
+	for _, tc := range []struct {
+		method jwt.SigningMethod
+		key    crypto.Signer
+	}{
+		{jwt.SigningMethodRS256, rsaKey},
+		{jwt.SigningMethodRS512, rsaKey},
+		{jwt.SigningMethodPS256, rsaKey},
+		{jwt.SigningMethodPS384, rsaKey},
+		{jwt.SigningMethodES256, ecKey(elliptic.P256())},
+		{jwt.SigningMethodES384, ecKey(elliptic.P384())},
+		{jwt.SigningMethodES512, ecKey(elliptic.P521())},
+		{jwt.SigningMethodEdDSA, edKey},
+	} {
+		t.Run(tc.method.Alg(), func(t *testing.T) {
+			tok := NewJWTTokenWithKey(&opaqueSigner{key: tc.key}, tc.method, "kms-1")
+			assert.Equal(t, tc.method, tok.SigningMethod())
+
+			tokenStr, err := tok.Generate(JWTClaim{"sub": "user-1"}, JWTHeader{})
+			require.NoError(t, err)
+
+			parsed, err := jwt.Parse(tokenStr, func(*jwt.Token) (interface{}, error) {
+				return tc.key.Public(), nil
+			}, jwt.WithValidMethods([]string{tc.method.Alg()}))
+			require.NoError(t, err)
+			assert.Equal(t, "kms-1", parsed.Header["kid"])
+		})
+	}
+
+	t.Run("key_type_mismatch", func(t *testing.T) {
+		for _, method := range []jwt.SigningMethod{jwt.SigningMethodRS256, jwt.SigningMethodPS256, jwt.SigningMethodEdDSA} {
+			_, err := NewJWTTokenWithKey(&opaqueSigner{key: ecKey(elliptic.P256())}, method, "").Generate(JWTClaim{}, JWTHeader{})
+			assert.ErrorIs(t, err, jwt.ErrInvalidKeyType)
+		}
+
+		_, err := NewJWTTokenWithKey(&opaqueSigner{key: rsaKey}, jwt.SigningMethodES256, "").Generate(JWTClaim{}, JWTHeader{})
+		assert.ErrorIs(t, err, jwt.ErrInvalidKeyType)
+	})
+
+	t.Run("symmetric_method", func(t *testing.T) {
+		_, err := NewJWTTokenWithKey(&opaqueSigner{key: rsaKey}, jwt.SigningMethodHS256, "").Generate(JWTClaim{}, JWTHeader{})
+		assert.Error(t, err)
+	})
+
+	t.Run("signer_error", func(t *testing.T) {
+		signer := &opaqueSigner{key: ecKey(elliptic.P256()), err: errors.New("kms unavailable")}
+		_, err := NewJWTTokenWithKey(signer, jwt.SigningMethodES256, "").Generate(JWTClaim{}, JWTHeader{})
+		assert.ErrorContains(t, err, "kms unavailable")
+	})
+
+	t.Run("invalid_ecdsa_signature", func(t *testing.T) {
+		signer := &opaqueSigner{key: ecKey(elliptic.P256()), der: []byte("not asn.1")}
+		_, err := NewJWTTokenWithKey(signer, jwt.SigningMethodES256, "").Generate(JWTClaim{}, JWTHeader{})
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+}
+
+func TestSignerMethod_Sign(t *testing.T) {
+	_, err := signerMethod{jwt.SigningMethodES256}.Sign("payload", []byte("secret"))
+	assert.ErrorIs(t, err, jwt.ErrInvalidKeyType)
+}
+
+func TestRawECDSASignature(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	digest := make([]byte, 32)
+
+	der, err := ecdsa.SignASN1(rand.Reader, key, digest)
+	require.NoError(t, err)
+
+	raw, err := rawECDSASignature(der, 32)
+	require.NoError(t, err)
+	assert.Len(t, raw, 64)
+
+	_, err = rawECDSASignature(der, 16)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	_, err = rawECDSASignature(append(der, 0), 32)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+}
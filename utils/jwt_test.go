@@ -1,6 +1,10 @@
 package utils
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
 	"strings"
 	"testing"
 	"time"
@@ -33,6 +37,25 @@ func TestNewJWTToken(t *testing.T) {
 	})
 }
 
+func TestNewJWTTokenWithKey(t *testing.T) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	tok := NewJWTTokenWithKey(priv, jwt.SigningMethodRS256, "rsa-1")
+	assert.Equal(t, "rsa-1", tok.KeyID())
+	assert.Equal(t, priv, tok.SigningKey())
+	assert.Equal(t, jwt.SigningMethodRS256, tok.SigningMethod())
+
+	tokenStr, err := tok.Generate(JWTClaim{"sub": "user-1"}, JWTHeader{})
+	require.NoError(t, err)
+
+	parsed, err := jwt.Parse(tokenStr, func(t *jwt.Token) (interface{}, error) {
+		return &priv.PublicKey, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "rsa-1", parsed.Header["kid"])
+}
+
 func TestJWTToken_Generate(t *testing.T) {
 	t.Run("produces_valid_signed_jwt", func(t *testing.T) {
 		tok, err := NewJWTToken(hmacKey, jwt.SigningMethodHS256)
@@ -127,3 +150,65 @@ func TestParseSigningKey(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestParseVerificationKey(t *testing.T) {
+	t.Run("hs256_returns_raw_bytes", func(t *testing.T) {
+		key, err := ParseVerificationKey(hmacKey, jwt.SigningMethodHS256)
+		assert.NoError(t, err)
+		assert.Equal(t, hmacKey, key)
+	})
+
+	t.Run("rs256_parses_public_key", func(t *testing.T) {
+		privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		der, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+		require.NoError(t, err)
+		publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+		key, err := ParseVerificationKey(publicPEM, jwt.SigningMethodRS256)
+		assert.NoError(t, err)
+		assert.Equal(t, &privateKey.PublicKey, key)
+	})
+
+	t.Run("unsupported_method_returns_error", func(t *testing.T) {
+		_, err := ParseVerificationKey(hmacKey, jwt.SigningMethodNone)
+		assert.ErrorIs(t, err, ErrUnsupportedSigningMethod)
+	})
+
+	t.Run("es256_with_invalid_pem_returns_error", func(t *testing.T) {
+		_, err := ParseVerificationKey([]byte("not-a-pem"), jwt.SigningMethodES256)
+		assert.Error(t, err)
+	})
+}
+
+func TestHalfHash(t *testing.T) {
+	t.Run("rs256_matches_oidc_example", func(t *testing.T) {
+		// OIDC Core Appendix A.4 (c_hash) and A.5 (at_hash).
+		cHash, err := HalfHash("Qcb0Orv1zh30vL1MPRsbm-diHiMwcLyZvn1arpZv-Jxf_11jnpEX3Tgfvk", jwt.SigningMethodRS256)
+		assert.NoError(t, err)
+		assert.Equal(t, "LDktKdoQak3Pk0cnXxCltA", cHash)
+
+		atHash, err := HalfHash("jHkWEdUXMU1BwAsC4vtUsZwnNvTIxEl0z9K3vx5KF0Y", jwt.SigningMethodRS256)
+		assert.NoError(t, err)
+		assert.Equal(t, "77QmUPtjPfzWtF2AnpK9RQ", atHash)
+	})
+
+	t.Run("length_follows_hash_size", func(t *testing.T) {
+		h384, err := HalfHash("value", jwt.SigningMethodES384)
+		assert.NoError(t, err)
+		assert.Len(t, h384, 32)
+
+		h512, err := HalfHash("value", jwt.SigningMethodHS512)
+		assert.NoError(t, err)
+		assert.Len(t, h512, 43)
+
+		hEd, err := HalfHash("value", jwt.SigningMethodEdDSA)
+		assert.NoError(t, err)
+		assert.Equal(t, h512, hEd)
+	})
+
+	t.Run("unsupported_method_returns_error", func(t *testing.T) {
+		_, err := HalfHash("value", jwt.SigningMethodNone)
+		assert.ErrorIs(t, err, ErrUnsupportedSigningMethod)
+	})
+}